@@ -4,6 +4,8 @@ import type {
   CreateStoryFromSlackInput,
   RuntimeLogInput,
   RuntimeOption,
+  SavedView,
+  SavedViewStories,
   SlackActor,
   SlackIdentity,
   SlackInstallation,
@@ -194,6 +196,27 @@ export class FortyOneClient implements StoryRuntime {
     });
   }
 
+  async listSavedViews(
+    actor: SlackActor,
+    favouritesOnly = false,
+  ): Promise<SavedView[]> {
+    return this.request("/internal/bot/slack/views", {
+      body: { actor, favouritesOnly },
+      method: "POST",
+    });
+  }
+
+  async getSavedViewStories(
+    actor: SlackActor,
+    viewId: string,
+    limit?: number,
+  ): Promise<SavedViewStories> {
+    return this.request("/internal/bot/slack/views/stories", {
+      body: { actor, limit, viewId },
+      method: "POST",
+    });
+  }
+
   private async request<T>(
     path: string,
     options: RequestOptions = {},
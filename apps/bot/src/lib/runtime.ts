@@ -72,15 +72,46 @@ export interface StoryUnfurl {
   url: string;
 }
 
+export interface SavedView {
+  icon?: string | null;
+  id: string;
+  isFavourite: boolean;
+  name: string;
+  scope: "personal" | "team" | "workspace";
+}
+
+export interface SavedViewStory {
+  id: string;
+  priority: string;
+  ref: string;
+  title: string;
+  url?: string;
+}
+
+export interface SavedViewStories {
+  hasMore: boolean;
+  stories: SavedViewStory[];
+  viewId: string;
+}
+
 export interface StoryRuntime {
   createStoryFromSlackForm: (
     input: CreateStoryFromSlackInput,
   ) => CreatedStory | Promise<CreatedStory>;
   getSlackInstallation?: (teamId: string) => Promise<SlackInstallation | null>;
+  getSavedViewStories?: (
+    actor: SlackActor,
+    viewId: string,
+    limit?: number,
+  ) => Promise<SavedViewStories>;
   getStoryUnfurl?: (
     url: string,
     actor: SlackActor,
   ) => Promise<StoryUnfurl | null>;
+  listSavedViews?: (
+    actor: SlackActor,
+    favouritesOnly?: boolean,
+  ) => Promise<SavedView[]>;
   listStoryOptions: (
     actor: SlackActor,
   ) => StoryFormOptions | Promise<StoryFormOptions>;
@@ -12,4 +12,30 @@ export const createTools = (runtime: StoryRuntime, actor: SlackActor) => ({
       options: await runtime.listStoryOptions(actor),
     }),
   }),
+  listSavedViews: tool({
+    description:
+      "List the saved story views the Slack user can see, optionally only their favourites.",
+    inputSchema: z.object({
+      favouritesOnly: z.boolean().optional(),
+    }),
+    execute: async ({ favouritesOnly }) => ({
+      views: runtime.listSavedViews
+        ? await runtime.listSavedViews(actor, favouritesOnly)
+        : [],
+    }),
+  }),
+  getSavedViewStories: tool({
+    description:
+      "Run a saved story view for the Slack user and return its first stories.",
+    inputSchema: z.object({
+      limit: z.number().int().min(1).max(50).optional(),
+      viewId: z.string(),
+    }),
+    execute: async ({ limit, viewId }) => {
+      if (!runtime.getSavedViewStories) {
+        return { hasMore: false, stories: [], viewId };
+      }
+      return runtime.getSavedViewStories(actor, viewId, limit);
+    },
+  }),
 });
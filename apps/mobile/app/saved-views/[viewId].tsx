@@ -0,0 +1,6 @@
+import React from "react";
+import { SavedViewStories } from "@/modules/saved-views/stories";
+
+export default function SavedViewPage() {
+  return <SavedViewStories />;
+}
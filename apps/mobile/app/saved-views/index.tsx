@@ -0,0 +1,6 @@
+import React from "react";
+import { SavedViews } from "@/modules/saved-views";
+
+export default function SavedViewsScreen() {
+  return <SavedViews />;
+}
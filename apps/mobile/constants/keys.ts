@@ -39,6 +39,8 @@ export const storyKeys = {
     [...storyKeys.all, "sprint", sprintId, "grouped", params] as const,
   objectiveGrouped: (objectiveId: string, params: Record<string, any>) =>
     [...storyKeys.all, "objective", objectiveId, "grouped", params] as const,
+  savedViewGrouped: (viewId: string, params: Record<string, any>) =>
+    [...storyKeys.all, "saved-view", viewId, "grouped", params] as const,
   group: (groupKey: string, params: Record<string, any>) =>
     [...storyKeys.all, "group", groupKey, params] as const,
   attachments: (storyId: string) =>
@@ -79,6 +81,13 @@ export const objectiveKeys = {
   statuses: () => [...objectiveKeys.all, "statuses"] as const,
 };
 
+export const savedViewKeys = {
+  all: ["saved-views"] as const,
+  lists: () => [...savedViewKeys.all, "list"] as const,
+  details: () => [...savedViewKeys.all, "detail"] as const,
+  detail: (id: string) => [...savedViewKeys.details(), id] as const,
+};
+
 export const statusKeys = {
   all: ["statuses"] as const,
   lists: () => [...statusKeys.all, "list"] as const,
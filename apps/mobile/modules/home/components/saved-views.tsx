@@ -0,0 +1,79 @@
+import React from "react";
+import { View, Pressable } from "react-native";
+import { SymbolView } from "expo-symbols";
+import { useRouter } from "expo-router";
+import { Row, Text } from "@/components/ui";
+import { colors } from "@/constants";
+import { useTheme } from "@/hooks";
+import { useSavedViews } from "@/modules/saved-views/hooks";
+
+const ViewRow = ({
+  name,
+  icon,
+  onPress,
+}: {
+  name: string;
+  icon: "star.fill" | "square.stack";
+  onPress: () => void;
+}) => {
+  const { resolvedTheme } = useTheme();
+  const iconColor =
+    resolvedTheme === "light" ? colors.gray.DEFAULT : colors.gray[300];
+  return (
+    <Pressable
+      className="active:bg-gray-50 dark:active:bg-dark-300 rounded-xl"
+      onPress={onPress}
+    >
+      <Row
+        align="center"
+        justify="between"
+        className="py-3.5 px-3 min-h-[44px]"
+      >
+        <Row align="center" gap={2}>
+          <SymbolView name={icon} size={14} tintColor={iconColor} />
+          <Text>{name}</Text>
+        </Row>
+        <SymbolView
+          name="chevron.forward"
+          weight="semibold"
+          size={12}
+          tintColor={iconColor}
+        />
+      </Row>
+    </Pressable>
+  );
+};
+
+export const SavedViews = () => {
+  const router = useRouter();
+  const { data: views = [] } = useSavedViews();
+  const favourites = views.filter((view) => view.isFavourite);
+
+  if (views.length === 0) {
+    return null;
+  }
+  return (
+    <View className="mt-4">
+      <Row asContainer>
+        <Text color="muted" fontSize="sm" className="mb-1">
+          Views
+        </Text>
+      </Row>
+      <View className="px-3">
+        {favourites.map((view) => (
+          <ViewRow
+            key={view.id}
+            name={view.name}
+            icon="star.fill"
+            onPress={() => router.push(`/saved-views/${view.id}`)}
+          />
+        ))}
+        <ViewRow
+          name="All views"
+          icon="square.stack"
+          onPress={() => router.push("/saved-views")}
+        />
+      </View>
+    </View>
+  );
+};
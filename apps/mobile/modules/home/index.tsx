@@ -4,6 +4,7 @@ import { SafeContainer } from "@/components/ui";
 import { Header } from "./components/header";
 import { Overview } from "./components/overview";
 import { Teams } from "./components/teams";
+import { SavedViews } from "./components/saved-views";
 import { NewStoryButton } from "./components/new-story";
 
 export const Home = () => {
@@ -13,6 +14,7 @@ export const Home = () => {
       <ScrollView className="flex-1">
         <Overview />
         <Teams />
+        <SavedViews />
       </ScrollView>
       <NewStoryButton />
     </SafeContainer>
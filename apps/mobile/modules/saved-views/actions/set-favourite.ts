@@ -0,0 +1,10 @@
+import { put } from "@/lib/http/fetch";
+
+export const setFavouriteAction = async (
+  viewId: string,
+  isFavourite: boolean
+) => {
+  await put<{ isFavourite: boolean }, null>(`saved-views/${viewId}/favourite`, {
+    isFavourite,
+  });
+};
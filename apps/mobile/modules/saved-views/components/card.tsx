@@ -0,0 +1,69 @@
+import React from "react";
+import { Pressable } from "react-native";
+import { SymbolView } from "expo-symbols";
+import { router } from "expo-router";
+import { Col, Row, Text } from "@/components/ui";
+import { colors } from "@/constants/colors";
+import { useTheme } from "@/hooks";
+import type { SavedView, SavedViewScope } from "../types";
+import { useFavouriteSavedViewMutation } from "../hooks";
+
+const scopeLabels: Record<SavedViewScope, string> = {
+  personal: "Personal",
+  team: "Team",
+  workspace: "Workspace",
+};
+
+export const Card = ({ view }: { view: SavedView }) => {
+  const { resolvedTheme } = useTheme();
+  const favouriteMutation = useFavouriteSavedViewMutation();
+  const iconColor =
+    resolvedTheme === "light" ? colors.gray.DEFAULT : colors.gray[300];
+
+  return (
+    <Pressable
+      className="active:bg-gray-50 dark:active:bg-dark-300"
+      onPress={() => router.push(`/saved-views/${view.id}`)}
+    >
+      <Row
+        align="center"
+        justify="between"
+        className="p-4 border-b border-gray-50 dark:border-dark"
+      >
+        <Row align="center" gap={3} className="w-10/12">
+          <Row className="bg-gray-100 dark:bg-dark-200 rounded-lg p-1.5">
+            <SymbolView
+              name="line.3.horizontal.decrease.circle"
+              size={20}
+              weight="bold"
+              tintColor={iconColor}
+            />
+          </Row>
+          <Col gap={1}>
+            <Text numberOfLines={1} fontWeight="semibold">
+              {view.name}
+            </Text>
+            <Text fontSize="sm" color="muted">
+              {scopeLabels[view.scope]}
+            </Text>
+          </Col>
+        </Row>
+        <Pressable
+          hitSlop={8}
+          onPress={() =>
+            favouriteMutation.mutate({
+              viewId: view.id,
+              isFavourite: !view.isFavourite,
+            })
+          }
+        >
+          <SymbolView
+            name={view.isFavourite ? "star.fill" : "star"}
+            size={18}
+            tintColor={view.isFavourite ? colors.warning : iconColor}
+          />
+        </Pressable>
+      </Row>
+    </Pressable>
+  );
+};
@@ -0,0 +1,36 @@
+import React from "react";
+import { Col, Row, Text } from "@/components/ui";
+import { SymbolView } from "expo-symbols";
+import { colors } from "@/constants";
+import { useTerminology } from "@/hooks/use-terminology";
+import { useTheme } from "@/hooks";
+
+export const EmptyState = () => {
+  const { resolvedTheme } = useTheme();
+  const { getTermDisplay } = useTerminology();
+
+  return (
+    <Col justify="center" align="center" className="flex-1 pt-56" asContainer>
+      <Row
+        align="center"
+        justify="center"
+        className="size-18 rounded-full bg-gray-50 mb-6 dark:bg-dark-300"
+      >
+        <SymbolView
+          name="line.3.horizontal.decrease.circle"
+          size={36}
+          tintColor={
+            resolvedTheme === "light" ? colors.gray.DEFAULT : colors.gray[200]
+          }
+        />
+      </Row>
+      <Text fontSize="xl" fontWeight="semibold" className="mb-4 text-center">
+        No views found
+      </Text>
+      <Text color="muted" className="text-center">
+        Views saved on the web to filter your{" "}
+        {getTermDisplay("storyTerm", { variant: "plural" })} will show up here.
+      </Text>
+    </Col>
+  );
+};
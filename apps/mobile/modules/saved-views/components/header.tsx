@@ -0,0 +1,14 @@
+import React from "react";
+import { Row, Text, Back } from "@/components/ui";
+
+export const Header = () => {
+  return (
+    <Row asContainer align="center" gap={3} justify="between" className="mb-2">
+      <Back />
+      <Text fontSize="2xl" fontWeight="semibold">
+        Views
+      </Text>
+      <Row className="w-10" />
+    </Row>
+  );
+};
@@ -0,0 +1,3 @@
+export { Header } from "./header";
+export { Card } from "./card";
+export { EmptyState } from "./empty-state";
@@ -0,0 +1,2 @@
+export { useSavedViews, useSavedView } from "./use-saved-views";
+export { useFavouriteSavedViewMutation } from "./use-favourite-saved-view-mutation";
@@ -0,0 +1,72 @@
+import { useMutation, useQueryClient } from "@tanstack/react-query";
+import { toast } from "sonner-native";
+import { savedViewKeys } from "@/constants/keys";
+import type { SavedView } from "../types";
+import { setFavouriteAction } from "../actions/set-favourite";
+
+export const useFavouriteSavedViewMutation = () => {
+  const queryClient = useQueryClient();
+
+  const mutation = useMutation({
+    mutationFn: ({
+      viewId,
+      isFavourite,
+    }: {
+      viewId: string;
+      isFavourite: boolean;
+    }) => setFavouriteAction(viewId, isFavourite),
+
+    onMutate: async ({ viewId, isFavourite }) => {
+      await queryClient.cancelQueries({ queryKey: savedViewKeys.all });
+      const previousViews = queryClient.getQueryData<SavedView[]>(
+        savedViewKeys.lists()
+      );
+      const previousView = queryClient.getQueryData<SavedView>(
+        savedViewKeys.detail(viewId)
+      );
+
+      if (previousViews) {
+        queryClient.setQueryData<SavedView[]>(
+          savedViewKeys.lists(),
+          previousViews.map((view) =>
+            view.id === viewId ? { ...view, isFavourite } : view
+          )
+        );
+      }
+      if (previousView) {
+        queryClient.setQueryData<SavedView>(savedViewKeys.detail(viewId), {
+          ...previousView,
+          isFavourite,
+        });
+      }
+
+      return { previousViews, previousView };
+    },
+
+    onError: (error, variables, context) => {
+      if (context?.previousViews) {
+        queryClient.setQueryData(savedViewKeys.lists(), context.previousViews);
+      }
+      if (context?.previousView) {
+        queryClient.setQueryData(
+          savedViewKeys.detail(variables.viewId),
+          context.previousView
+        );
+      }
+
+      toast.error("Failed to update view", {
+        description: error.message || "Please try again",
+        action: {
+          label: "Retry",
+          onClick: () => mutation.mutate(variables),
+        },
+      });
+    },
+
+    onSettled: () => {
+      queryClient.invalidateQueries({ queryKey: savedViewKeys.all });
+    },
+  });
+
+  return mutation;
+};
@@ -0,0 +1,19 @@
+import { useQuery } from "@tanstack/react-query";
+import { savedViewKeys } from "@/constants/keys";
+import { getSavedView, getSavedViews } from "../queries/get-saved-views";
+
+export const useSavedViews = () => {
+  return useQuery({
+    queryKey: savedViewKeys.lists(),
+    queryFn: getSavedViews,
+  });
+};
+
+export const useSavedView = (viewId: string) => {
+  return useQuery({
+    queryKey: savedViewKeys.detail(viewId),
+    queryFn: () => getSavedView(viewId),
+    enabled: Boolean(viewId),
+    staleTime: 1000 * 60 * 2, // 2 minutes
+  });
+};
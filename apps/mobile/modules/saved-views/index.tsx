@@ -0,0 +1,31 @@
+import React from "react";
+import { RefreshControl, ScrollView } from "react-native";
+import { SafeContainer } from "@/components/ui";
+import { Card, EmptyState, Header } from "./components";
+import { useSavedViews } from "./hooks";
+
+export const SavedViews = () => {
+  const {
+    data: views = [],
+    isPending,
+    refetch,
+    isRefetching,
+  } = useSavedViews();
+
+  return (
+    <SafeContainer isFull>
+      <Header />
+      <ScrollView
+        showsVerticalScrollIndicator={false}
+        refreshControl={
+          <RefreshControl refreshing={isRefetching} onRefresh={refetch} />
+        }
+      >
+        {!isPending && views.length === 0 && <EmptyState />}
+        {views.map((view) => (
+          <Card key={view.id} view={view} />
+        ))}
+      </ScrollView>
+    </SafeContainer>
+  );
+};
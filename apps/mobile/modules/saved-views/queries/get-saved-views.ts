@@ -0,0 +1,13 @@
+import { get } from "@/lib/http";
+import type { ApiResponse } from "@/types";
+import type { SavedView } from "../types";
+
+export const getSavedViews = async () => {
+  const response = await get<ApiResponse<SavedView[]>>("saved-views");
+  return response.data ?? [];
+};
+
+export const getSavedView = async (viewId: string) => {
+  const response = await get<ApiResponse<SavedView>>(`saved-views/${viewId}`);
+  return response.data;
+};
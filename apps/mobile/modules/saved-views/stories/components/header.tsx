@@ -0,0 +1,40 @@
+import React from "react";
+import { Row, Text, Back, ContextMenuButton } from "@/components/ui";
+import { useGlobalSearchParams } from "expo-router";
+import { truncateText } from "@/lib/utils";
+import {
+  useFavouriteSavedViewMutation,
+  useSavedView,
+} from "@/modules/saved-views/hooks";
+
+export const Header = () => {
+  const { viewId } = useGlobalSearchParams<{ viewId: string }>();
+  const { data: view } = useSavedView(viewId);
+  const favouriteMutation = useFavouriteSavedViewMutation();
+
+  return (
+    <Row className="mb-3" asContainer gap={2} justify="between" align="center">
+      <Back />
+      <Text fontSize="2xl" fontWeight="semibold">
+        {truncateText(view?.name ?? "", 20)}
+      </Text>
+      <ContextMenuButton
+        actions={[
+          {
+            systemImage: view?.isFavourite ? "star.slash" : "star",
+            label: view?.isFavourite
+              ? "Remove from favourites"
+              : "Add to favourites",
+            onPress: () => {
+              if (!view) return;
+              favouriteMutation.mutate({
+                viewId: view.id,
+                isFavourite: !view.isFavourite,
+              });
+            },
+          },
+        ]}
+      />
+    </Row>
+  );
+};
@@ -0,0 +1 @@
+export { Header } from "./header";
@@ -0,0 +1,72 @@
+import React, { useMemo } from "react";
+
+import { SafeContainer, StoriesListSkeleton } from "@/components/ui";
+import { StoriesBoard } from "@/modules/stories/components";
+import { useSavedViewStoriesGrouped } from "@/modules/stories/hooks";
+import { useSavedView } from "@/modules/saved-views/hooks";
+import { useGlobalSearchParams } from "expo-router";
+import { useTerminology } from "@/hooks/use-terminology";
+import { useQueryClient } from "@tanstack/react-query";
+import { storyKeys } from "@/constants/keys";
+import type { DisplayColumn } from "@/types/stories-view-options";
+import { Header } from "./components";
+
+const visibleColumns: DisplayColumn[] = ["Status", "Assignee", "Priority"];
+
+// The view's filters, grouping and ordering are applied by the server from
+// viewId, so the board only needs them to label and page its groups.
+export const SavedViewStories = () => {
+  const queryClient = useQueryClient();
+  const { viewId } = useGlobalSearchParams<{ viewId: string }>();
+  const { data: view, isPending: isViewPending } = useSavedView(viewId);
+  const { getTermDisplay } = useTerminology();
+
+  const queryOptions = useMemo(() => {
+    return {
+      viewId: viewId!,
+      groupBy: view?.query.groupBy || "status",
+      orderBy: view?.query.orderBy,
+      orderDirection: view?.query.orderDirection,
+    };
+  }, [
+    viewId,
+    view?.query.groupBy,
+    view?.query.orderBy,
+    view?.query.orderDirection,
+  ]);
+
+  const {
+    data: groupedStories,
+    isPending,
+    refetch,
+    isRefetching,
+  } = useSavedViewStoriesGrouped(viewId!, queryOptions);
+
+  if (isViewPending) {
+    return (
+      <SafeContainer isFull>
+        <Header />
+        <StoriesListSkeleton />
+      </SafeContainer>
+    );
+  }
+
+  return (
+    <SafeContainer isFull>
+      <Header />
+      <StoriesBoard
+        groupedStories={groupedStories}
+        groupFilters={queryOptions}
+        isLoading={isPending}
+        visibleColumns={visibleColumns}
+        emptyTitle={`No ${getTermDisplay("storyTerm", { variant: "plural" })} found for this view`}
+        emptyMessage={`There are no ${getTermDisplay("storyTerm", { variant: "plural" })} matching this view at the moment.`}
+        onRefresh={() => {
+          refetch();
+          queryClient.invalidateQueries({ queryKey: storyKeys.all });
+        }}
+        isRefreshing={isRefetching}
+      />
+    </SafeContainer>
+  );
+};
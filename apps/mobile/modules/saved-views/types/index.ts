@@ -0,0 +1,29 @@
+import type {
+  GroupedStoryParams,
+  StoryFilters,
+} from "@/modules/stories/types";
+
+export type SavedViewScope = "personal" | "team" | "workspace";
+
+export type SavedViewQuery = {
+  filters: StoryFilters;
+  groupBy: GroupedStoryParams["groupBy"];
+  orderBy: NonNullable<GroupedStoryParams["orderBy"]>;
+  orderDirection: NonNullable<GroupedStoryParams["orderDirection"]>;
+  storiesPerGroup: number;
+};
+
+export type SavedView = {
+  id: string;
+  workspaceId: string;
+  ownerId: string;
+  scope: SavedViewScope;
+  teamId: string | null;
+  name: string;
+  icon: string | null;
+  query: SavedViewQuery;
+  isFavourite: boolean;
+  position: number | null;
+  createdAt: string;
+  updatedAt: string;
+};
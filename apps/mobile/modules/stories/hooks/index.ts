@@ -2,6 +2,7 @@ export { useGroupStoriesInfinite } from "./use-group-stories-infinite";
 export { useTeamStoriesGrouped } from "./use-team-stories-grouped";
 export { useSprintStoriesGrouped } from "./use-sprint-stories-grouped";
 export { useObjectiveStoriesGrouped } from "./use-objective-stories-grouped";
+export { useSavedViewStoriesGrouped } from "./use-saved-view-stories-grouped";
 export { useStory } from "./use-story";
 export {
   useArchiveStoryMutation,
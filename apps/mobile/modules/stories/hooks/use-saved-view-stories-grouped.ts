@@ -0,0 +1,23 @@
+import { useQuery } from "@tanstack/react-query";
+import { storyKeys } from "@/constants/keys";
+import { getGroupedStories } from "../queries/get-grouped-stories";
+import type { GroupedStoryParams } from "../types";
+
+export const useSavedViewStoriesGrouped = (
+  viewId: string,
+  options: GroupedStoryParams
+) => {
+  const params: GroupedStoryParams = {
+    ...options,
+    viewId,
+  };
+
+  const queryKey = storyKeys.savedViewGrouped(viewId, params);
+
+  return useQuery({
+    queryKey,
+    queryFn: () => getGroupedStories(params),
+    enabled: Boolean(viewId),
+    staleTime: 1000 * 60 * 2,
+  });
+};
@@ -142,6 +142,7 @@ export type GroupedStoryParams = {
   completedAfter?: string;
   completedBefore?: string;
   includeDeleted?: boolean;
+  viewId?: string;
 };
 
 export type GroupStoryParams = {
@@ -175,4 +176,5 @@ export type GroupStoryParams = {
   includeDeleted?: boolean;
   completedAfter?: string;
   completedBefore?: string;
+  viewId?: string;
 };
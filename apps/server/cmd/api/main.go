@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -149,6 +150,13 @@ type Config struct {
 	Bot struct {
 		Token string `env:"FORTYONE_BOT_TOKEN"`
 	}
+	InboundEmail struct {
+		Domain          string `env:"INBOUND_EMAIL_DOMAIN"`
+		Secret          string `env:"INBOUND_EMAIL_SECRET"`
+		AuthServID      string `env:"INBOUND_EMAIL_AUTHSERV_ID"`
+		MaxMessageBytes int64  `env:"INBOUND_EMAIL_MAX_MESSAGE_BYTES" default:"26214400"`
+		MaxPerHour      int    `env:"INBOUND_EMAIL_MAX_PER_HOUR" default:"30"`
+	}
 }
 
 func main() {
@@ -415,6 +423,11 @@ func run(ctx context.Context, log *logger.Logger) error {
 		SlackClientSecret:  cfg.Slack.ClientSecret,
 		SlackRedirectURL:   cfg.Slack.RedirectURL,
 		BotToken:           cfg.Bot.Token,
+		InboundDomain:      cfg.InboundEmail.Domain,
+		InboundSecret:      cfg.InboundEmail.Secret,
+		InboundAuthServID:  cfg.InboundEmail.AuthServID,
+		InboundMaxBytes:    cfg.InboundEmail.MaxMessageBytes,
+		InboundMaxPerHour:  cfg.InboundEmail.MaxPerHour,
 		AIAPIKey:           strings.TrimSpace(os.Getenv("OPENAI_API_KEY")),
 		SSEHub:             sseHub,
 		CorsOrigin:         "*",
@@ -432,6 +445,12 @@ func run(ctx context.Context, log *logger.Logger) error {
 		}
 	}()
 
+	go func() {
+		if err := runtime.Relay.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			log.Error(ctx, "event outbox relay stopped", "error", err)
+		}
+	}()
+
 	// Create the mux
 	routeAdder := runtime.RouteAdder
 	handler := mux.New(muxConfig, routeAdder)
@@ -0,0 +1,125 @@
+// Command inboundsmtp runs a local SMTP server that forwards every message it
+// receives to the API's inbound email endpoint, the same way the mail provider
+// does in production. Point a mail client or swaks at it to test team inbound
+// addresses without a real mail setup.
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/smtpd"
+)
+
+var (
+	service = "projects-inbound-smtp"
+)
+
+// secretHeader matches inboundemail.SecretHeader.
+const secretHeader = "X-FortyOne-Inbound-Secret"
+
+func main() {
+	ctx := context.Background()
+	log := logger.NewWithText(os.Stdout, slog.LevelDebug, service)
+
+	if err := run(ctx, log); err != nil {
+		log.Error(ctx, "Inbound SMTP server ended with error", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, log *logger.Logger) error {
+	addr := flag.String("addr", ":2525", "address to listen for SMTP on")
+	endpoint := flag.String("endpoint", "http://localhost:8000/inbound-email", "inbound email endpoint of the API")
+	secret := flag.String("secret", os.Getenv("INBOUND_EMAIL_SECRET"), "shared secret of the inbound email endpoint")
+	maxBytes := flag.Int64("max-bytes", 25<<20, "largest message accepted")
+	authServID := flag.String("authserv-id", "", "stamp a passing Authentication-Results header with this authserv-id, as a provider would for a verified sender")
+	flag.Parse()
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	server := &smtpd.Server{
+		Hostname:        "fortyone.local",
+		MaxMessageBytes: *maxBytes,
+		Handler: func(ctx context.Context, envelope smtpd.Envelope) error {
+			if *authServID != "" {
+				envelope.Data = stampAuthenticationResults(envelope, *authServID)
+			}
+			result, err := forward(ctx, client, *endpoint, *secret, envelope)
+			if err != nil {
+				log.Error(ctx, "failed to forward message", "from", envelope.From, "to", envelope.To, "error", err)
+				return err
+			}
+			log.Info(ctx, "forwarded message", "from", envelope.From, "to", envelope.To, "result", result)
+			return nil
+		},
+	}
+
+	shutdown := make(chan os.Signal, 1)
+	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-shutdown
+		server.Shutdown()
+	}()
+
+	log.Info(ctx, "Inbound SMTP server listening", "addr", *addr, "endpoint", *endpoint)
+	if err := server.ListenAndServe(*addr); err != nil && err != smtpd.ErrServerClosed {
+		return err
+	}
+	log.Info(ctx, "Inbound SMTP server shut down")
+	return nil
+}
+
+// forward posts the raw message to the endpoint with the envelope recipients.
+func forward(ctx context.Context, client *http.Client, endpoint, secret string, envelope smtpd.Envelope) (string, error) {
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("parsing endpoint: %w", err)
+	}
+	query := target.Query()
+	for _, recipient := range envelope.To {
+		query.Add("to", recipient)
+	}
+	target.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(envelope.Data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "message/rfc822")
+	req.Header.Set(secretHeader, secret)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return "", fmt.Errorf("endpoint returned %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return string(bytes.TrimSpace(body)), nil
+}
+
+// stampAuthenticationResults prepends the DMARC pass a provider would add for
+// mail from the envelope sender's domain. Only use it with trusted senders.
+func stampAuthenticationResults(envelope smtpd.Envelope, authServID string) []byte {
+	_, domain, ok := strings.Cut(envelope.From, "@")
+	if !ok {
+		return envelope.Data
+	}
+	header := fmt.Sprintf("Authentication-Results: %s; dmarc=pass header.from=%s\r\n", authServID, domain)
+	return append([]byte(header), envelope.Data...)
+}
@@ -5,14 +5,17 @@ import (
 
 	activitieshttp "github.com/complexus-tech/projects-api/internal/modules/activities/http"
 	adminhttp "github.com/complexus-tech/projects-api/internal/modules/admin/http"
+	apitokenshttp "github.com/complexus-tech/projects-api/internal/modules/apitokens/http"
 	calendarhttp "github.com/complexus-tech/projects-api/internal/modules/calendar/http"
 	chatsessionshttp "github.com/complexus-tech/projects-api/internal/modules/chatsessions/http"
 	commentshttp "github.com/complexus-tech/projects-api/internal/modules/comments/http"
+	customfieldshttp "github.com/complexus-tech/projects-api/internal/modules/customfields/http"
 	documentshttp "github.com/complexus-tech/projects-api/internal/modules/documents/http"
 	epicshttp "github.com/complexus-tech/projects-api/internal/modules/epics/http"
 	feedbackhttp "github.com/complexus-tech/projects-api/internal/modules/feedback/http"
 	githubhttp "github.com/complexus-tech/projects-api/internal/modules/github/http"
 	healthhttp "github.com/complexus-tech/projects-api/internal/modules/health/http"
+	inboundemailhttp "github.com/complexus-tech/projects-api/internal/modules/inboundemail/http"
 	integrationrequestshttp "github.com/complexus-tech/projects-api/internal/modules/integrationrequests/http"
 	invitationshttp "github.com/complexus-tech/projects-api/internal/modules/invitations/http"
 	keyresultshttp "github.com/complexus-tech/projects-api/internal/modules/keyresults/http"
@@ -23,6 +26,7 @@ import (
 	objectiveshttp "github.com/complexus-tech/projects-api/internal/modules/objectives/http"
 	objectivestatushttp "github.com/complexus-tech/projects-api/internal/modules/objectivestatus/http"
 	reportshttp "github.com/complexus-tech/projects-api/internal/modules/reports/http"
+	savedviewshttp "github.com/complexus-tech/projects-api/internal/modules/savedviews/http"
 	searchhttp "github.com/complexus-tech/projects-api/internal/modules/search/http"
 	slackhttp "github.com/complexus-tech/projects-api/internal/modules/slack/http"
 	sprintshttp "github.com/complexus-tech/projects-api/internal/modules/sprints/http"
@@ -31,9 +35,12 @@ import (
 	subscriptionshttp "github.com/complexus-tech/projects-api/internal/modules/subscriptions/http"
 	teamshttp "github.com/complexus-tech/projects-api/internal/modules/teams/http"
 	teamsettingshttp "github.com/complexus-tech/projects-api/internal/modules/teamsettings/http"
+	timeentrieshttp "github.com/complexus-tech/projects-api/internal/modules/timeentries/http"
 	usershttp "github.com/complexus-tech/projects-api/internal/modules/users/http"
 	users "github.com/complexus-tech/projects-api/internal/modules/users/service"
+	webhookshttp "github.com/complexus-tech/projects-api/internal/modules/webhooks/http"
 	workspaceshttp "github.com/complexus-tech/projects-api/internal/modules/workspaces/http"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
 	"github.com/complexus-tech/projects-api/internal/platform/http/mux"
 	ssehttp "github.com/complexus-tech/projects-api/internal/sse/http"
 	"github.com/complexus-tech/projects-api/pkg/web"
@@ -76,6 +83,7 @@ func (r routes) BuildAllRoutes(app *web.App, cfg mux.Config) {
 	if err := svcs.validate(); err != nil {
 		panic("bootstrap service validation failed: " + err.Error())
 	}
+	mid.SetAPITokenResolver(svcs.apiTokens)
 
 	healthhttp.Routes(healthhttp.Config{
 		DB:  cfg.DB,
@@ -88,6 +96,14 @@ func (r routes) BuildAllRoutes(app *web.App, cfg mux.Config) {
 		Service:   svcs.admin,
 	}, app)
 
+	apitokenshttp.Routes(apitokenshttp.Config{
+		DB:        cfg.DB,
+		Log:       cfg.Log,
+		SecretKey: cfg.SecretKey,
+		Cache:     cfg.Cache,
+		Service:   svcs.apiTokens,
+	}, app)
+
 	githubhttp.Routes(githubhttp.Config{
 		DB:        cfg.DB,
 		Log:       cfg.Log,
@@ -128,6 +144,14 @@ func (r routes) BuildAllRoutes(app *web.App, cfg mux.Config) {
 		AIAPIKey:   cfg.AIAPIKey,
 	}, app)
 
+	inboundemailhttp.Routes(inboundemailhttp.Config{
+		DB:        cfg.DB,
+		Log:       cfg.Log,
+		SecretKey: cfg.SecretKey,
+		Cache:     cfg.Cache,
+		Service:   svcs.inboundEmail,
+	}, app)
+
 	integrationrequestshttp.Routes(integrationrequestshttp.Config{
 		DB:        cfg.DB,
 		Log:       cfg.Log,
@@ -158,6 +182,7 @@ func (r routes) BuildAllRoutes(app *web.App, cfg mux.Config) {
 		Comments:       svcs.comments,
 		Links:          svcs.links,
 		Attachments:    svcs.attachments,
+		SavedViews:     svcs.savedViews,
 	}, app)
 
 	objectiveshttp.Routes(objectiveshttp.Config{
@@ -224,6 +249,22 @@ func (r routes) BuildAllRoutes(app *web.App, cfg mux.Config) {
 		Service:   svcs.documents,
 	}, app)
 
+	customfieldshttp.Routes(customfieldshttp.Config{
+		DB:        cfg.DB,
+		Log:       cfg.Log,
+		SecretKey: cfg.SecretKey,
+		Cache:     cfg.Cache,
+		Service:   svcs.customFields,
+	}, app)
+
+	savedviewshttp.Routes(savedviewshttp.Config{
+		DB:        cfg.DB,
+		Log:       cfg.Log,
+		SecretKey: cfg.SecretKey,
+		Cache:     cfg.Cache,
+		Service:   svcs.savedViews,
+	}, app)
+
 	stateshttp.Routes(stateshttp.Config{
 		DB:        cfg.DB,
 		Log:       cfg.Log,
@@ -255,6 +296,14 @@ func (r routes) BuildAllRoutes(app *web.App, cfg mux.Config) {
 		Attachments:    svcs.attachments,
 	}, app)
 
+	webhookshttp.Routes(webhookshttp.Config{
+		DB:        cfg.DB,
+		Log:       cfg.Log,
+		SecretKey: cfg.SecretKey,
+		Cache:     cfg.Cache,
+		Service:   svcs.webhooks,
+	}, app)
+
 	workspaceshttp.Routes(workspaceshttp.Config{
 		DB:              cfg.DB,
 		Log:             cfg.Log,
@@ -381,6 +430,14 @@ func (r routes) BuildAllRoutes(app *web.App, cfg mux.Config) {
 		Service:      svcs.teamSettings,
 	}, app)
 
+	timeentrieshttp.Routes(timeentrieshttp.Config{
+		DB:        cfg.DB,
+		Log:       cfg.Log,
+		SecretKey: cfg.SecretKey,
+		Cache:     cfg.Cache,
+		Service:   svcs.timeEntries,
+	}, app)
+
 	chatsessionshttp.Routes(chatsessionshttp.Config{
 		DB:        cfg.DB,
 		Log:       cfg.Log,
@@ -6,11 +6,13 @@ import (
 	"github.com/complexus-tech/projects-api/internal/platform/http/mux"
 	"github.com/complexus-tech/projects-api/pkg/consumer"
 	"github.com/complexus-tech/projects-api/pkg/mailer"
+	"github.com/complexus-tech/projects-api/pkg/outbox"
 )
 
 type Runtime struct {
 	RouteAdder mux.RouteAdder
 	Consumer   *consumer.Consumer
+	Relay      *outbox.Relay
 }
 
 func BuildRuntime(cfg mux.Config, websiteURL string, emailService mailer.Service) (Runtime, error) {
@@ -31,10 +33,12 @@ func BuildRuntime(cfg mux.Config, websiteURL string, emailService mailer.Service
 		svcs.users,
 		svcs.states,
 		svcs.github,
+		svcs.webhooks,
 	)
 
 	return Runtime{
 		RouteAdder: NewWithServices(svcs),
 		Consumer:   streamConsumer,
+		Relay:      outbox.NewRelay(cfg.DB, cfg.Publisher, cfg.Log),
 	}, nil
 }
@@ -9,6 +9,8 @@ import (
 	activities "github.com/complexus-tech/projects-api/internal/modules/activities/service"
 	adminrepository "github.com/complexus-tech/projects-api/internal/modules/admin/repository"
 	admin "github.com/complexus-tech/projects-api/internal/modules/admin/service"
+	apitokensrepository "github.com/complexus-tech/projects-api/internal/modules/apitokens/repository"
+	apitokens "github.com/complexus-tech/projects-api/internal/modules/apitokens/service"
 	attachmentsrepository "github.com/complexus-tech/projects-api/internal/modules/attachments/repository"
 	attachments "github.com/complexus-tech/projects-api/internal/modules/attachments/service"
 	calendarrepository "github.com/complexus-tech/projects-api/internal/modules/calendar/repository"
@@ -17,6 +19,8 @@ import (
 	chatsessions "github.com/complexus-tech/projects-api/internal/modules/chatsessions/service"
 	commentsrepository "github.com/complexus-tech/projects-api/internal/modules/comments/repository"
 	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
+	customfieldsrepository "github.com/complexus-tech/projects-api/internal/modules/customfields/repository"
+	customfields "github.com/complexus-tech/projects-api/internal/modules/customfields/service"
 	documentsrepository "github.com/complexus-tech/projects-api/internal/modules/documents/repository"
 	documents "github.com/complexus-tech/projects-api/internal/modules/documents/service"
 	epicsrepository "github.com/complexus-tech/projects-api/internal/modules/epics/repository"
@@ -25,6 +29,8 @@ import (
 	feedback "github.com/complexus-tech/projects-api/internal/modules/feedback/service"
 	githubrepository "github.com/complexus-tech/projects-api/internal/modules/github/repository"
 	github "github.com/complexus-tech/projects-api/internal/modules/github/service"
+	inboundemailrepository "github.com/complexus-tech/projects-api/internal/modules/inboundemail/repository"
+	inboundemail "github.com/complexus-tech/projects-api/internal/modules/inboundemail/service"
 	integrationrequestsrepository "github.com/complexus-tech/projects-api/internal/modules/integrationrequests/repository"
 	integrationrequests "github.com/complexus-tech/projects-api/internal/modules/integrationrequests/service"
 	invitationsrepository "github.com/complexus-tech/projects-api/internal/modules/invitations/repository"
@@ -48,6 +54,8 @@ import (
 	okractivities "github.com/complexus-tech/projects-api/internal/modules/okractivities/service"
 	reportsrepository "github.com/complexus-tech/projects-api/internal/modules/reports/repository"
 	reports "github.com/complexus-tech/projects-api/internal/modules/reports/service"
+	savedviewsrepository "github.com/complexus-tech/projects-api/internal/modules/savedviews/repository"
+	savedviews "github.com/complexus-tech/projects-api/internal/modules/savedviews/service"
 	searchrepository "github.com/complexus-tech/projects-api/internal/modules/search/repository"
 	search "github.com/complexus-tech/projects-api/internal/modules/search/service"
 	slackrepository "github.com/complexus-tech/projects-api/internal/modules/slack/repository"
@@ -64,13 +72,18 @@ import (
 	teams "github.com/complexus-tech/projects-api/internal/modules/teams/service"
 	teamsettingsrepository "github.com/complexus-tech/projects-api/internal/modules/teamsettings/repository"
 	teamsettings "github.com/complexus-tech/projects-api/internal/modules/teamsettings/service"
+	timeentriesrepository "github.com/complexus-tech/projects-api/internal/modules/timeentries/repository"
+	timeentries "github.com/complexus-tech/projects-api/internal/modules/timeentries/service"
 	usersrepository "github.com/complexus-tech/projects-api/internal/modules/users/repository"
 	users "github.com/complexus-tech/projects-api/internal/modules/users/service"
+	webhooksrepository "github.com/complexus-tech/projects-api/internal/modules/webhooks/repository"
+	webhooks "github.com/complexus-tech/projects-api/internal/modules/webhooks/service"
 	workspacesrepository "github.com/complexus-tech/projects-api/internal/modules/workspaces/repository"
 	workspaces "github.com/complexus-tech/projects-api/internal/modules/workspaces/service"
 	"github.com/complexus-tech/projects-api/internal/platform/actors"
 	"github.com/complexus-tech/projects-api/internal/platform/billing"
 	"github.com/complexus-tech/projects-api/internal/platform/http/mux"
+	"github.com/complexus-tech/projects-api/pkg/deadletter"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
@@ -78,15 +91,18 @@ import (
 type services struct {
 	activities          *activities.Service
 	admin               *admin.Service
+	apiTokens           *apitokens.Service
 	attachments         *attachments.Service
 	calendar            *calendar.Service
 	chatSessions        *chatsessions.Service
 	comments            *comments.Service
+	customFields        *customfields.Service
 	documents           *documents.Service
 	epics               *epics.Service
 	feedback            *feedback.Service
 	github              *github.Service
 	slack               *slack.Service
+	inboundEmail        *inboundemail.Service
 	integrationRequests *integrationrequests.Service
 	invitations         *invitations.Service
 	keyResults          *keyresults.Service
@@ -98,6 +114,7 @@ type services struct {
 	objectiveStats      *objectivestatus.Service
 	okrActivities       *okractivities.Service
 	reports             *reports.Service
+	savedViews          *savedviews.Service
 	search              *search.Service
 	sprints             *sprints.Service
 	states              *states.Service
@@ -105,7 +122,9 @@ type services struct {
 	subscriptions       *subscriptions.Service
 	teams               *teams.Service
 	teamSettings        *teamsettings.Service
+	timeEntries         *timeentries.Service
 	users               *users.Service
+	webhooks            *webhooks.Service
 	workspaces          *workspaces.Service
 }
 
@@ -130,7 +149,7 @@ func buildServices(cfg mux.Config) services {
 		cfg.WebhookSecret,
 		cfg.TasksService,
 	)
-	storiesService := stories.New(cfg.Log, storiesrepository.New(cfg.Log, cfg.DB), mentionsRepo, cfg.Publisher, cfg.TasksService)
+	storiesService := stories.New(cfg.Log, storiesrepository.New(cfg.Log, cfg.DB), mentionsRepo, cfg.TasksService)
 	integrationRequestsRepo := integrationrequestsrepository.New(cfg.Log, cfg.DB)
 	commentsService := comments.New(cfg.Log, commentsrepository.New(cfg.Log, cfg.DB), mentionsRepo)
 	linksService := links.New(cfg.Log, linksrepository.New(cfg.Log, cfg.DB))
@@ -179,11 +198,13 @@ func buildServices(cfg mux.Config) services {
 	if err != nil {
 		panic("failed to initialize github service: " + err.Error())
 	}
+	savedViewsService := savedviews.New(cfg.Log, savedviewsrepository.New(cfg.Log, cfg.DB))
 	slackService := slack.New(
 		cfg.Log,
 		slackrepository.New(cfg.Log, cfg.DB),
 		integrationRequestsRepo,
 		storiesService,
+		savedViewsService,
 		slack.Config{
 			SigningSecret: cfg.SlackSigningSecret,
 			ClientID:      cfg.SlackClientID,
@@ -209,6 +230,25 @@ func buildServices(cfg mux.Config) services {
 	if err != nil {
 		panic("failed to resolve maya actor: " + err.Error())
 	}
+	emailActorID, err := actorResolver.Resolve(context.Background(), actors.KeyEmail)
+	if err != nil {
+		panic("failed to resolve email actor: " + err.Error())
+	}
+	inboundEmailService := inboundemail.New(
+		cfg.Log,
+		inboundemailrepository.New(cfg.Log, cfg.DB),
+		integrationRequestsRepo,
+		storiesService,
+		attachmentsService,
+		inboundemail.Config{
+			Domain:             cfg.InboundDomain,
+			Secret:             cfg.InboundSecret,
+			AuthServID:         cfg.InboundAuthServID,
+			EmailActorID:       emailActorID,
+			MaxMessageBytes:    cfg.InboundMaxBytes,
+			MaxMessagesPerHour: cfg.InboundMaxPerHour,
+		},
+	)
 	reportsService := reports.New(cfg.Log, reportsrepository.New(cfg.Log, cfg.DB))
 	mayaPlanner := maya.NewPlanner()
 	if strings.TrimSpace(cfg.AIAPIKey) != "" {
@@ -239,22 +279,26 @@ func buildServices(cfg mux.Config) services {
 		map[string]integrationrequests.ProviderAccepter{
 			integrationrequests.ProviderGitHub: githubService,
 			integrationrequests.ProviderSlack:  slackService,
+			integrationrequests.ProviderEmail:  inboundEmailService,
 		},
 	)
 	feedbackService := feedback.New(feedbackrepository.New(cfg.Log, cfg.DB), storiesService)
 
 	return services{
 		activities:          activities.New(cfg.Log, activitiesrepository.New(cfg.Log, cfg.DB)),
-		admin:               admin.New(adminrepository.New(cfg.Log, cfg.DB)),
+		admin:               admin.New(adminrepository.New(cfg.Log, cfg.DB), admin.WithDeadLetters(deadletter.New(cfg.Redis))),
+		apiTokens:           apitokens.New(cfg.Log, apitokensrepository.New(cfg.Log, cfg.DB)),
 		attachments:         attachmentsService,
 		calendar:            calendarService,
 		chatSessions:        chatsessions.New(cfg.Log, chatsessionsrepository.New(cfg.Log, cfg.DB)),
 		comments:            commentsService,
+		customFields:        customfields.New(cfg.Log, customfieldsrepository.New(cfg.Log, cfg.DB)),
 		documents:           documents.New(cfg.Log, documentsrepository.New(cfg.Log, cfg.DB)),
 		epics:               epics.New(cfg.Log, epicsrepository.New(cfg.Log, cfg.DB)),
 		feedback:            feedbackService,
 		github:              githubService,
 		slack:               slackService,
+		inboundEmail:        inboundEmailService,
 		integrationRequests: integrationRequestsService,
 		invitations:         invitationsService,
 		keyResults:          keyResultsService,
@@ -266,14 +310,17 @@ func buildServices(cfg mux.Config) services {
 		objectiveStats:      objectiveStatusService,
 		okrActivities:       okrActivitiesService,
 		reports:             reportsService,
-		search:              search.New(cfg.Log, searchrepository.New(cfg.Log, cfg.DB)),
+		savedViews:          savedViewsService,
+		search:              search.New(cfg.Log, searchrepository.New(cfg.Log, cfg.DB), storiesService),
 		sprints:             sprints.New(cfg.Log, sprintsrepository.New(cfg.Log, cfg.DB)),
 		states:              statesService,
 		stories:             storiesService,
 		subscriptions:       subscriptionsService,
 		teams:               teamsService,
 		teamSettings:        teamsettings.New(cfg.Log, teamsettingsrepository.New(cfg.Log, cfg.DB), cfg.TasksService),
+		timeEntries:         timeentries.New(cfg.Log, timeentriesrepository.New(cfg.Log, cfg.DB)),
 		users:               usersService,
+		webhooks:            webhooks.New(cfg.Log, webhooksrepository.New(cfg.Log, cfg.DB), cfg.TasksService),
 		workspaces:          workspacesService,
 	}
 }
@@ -296,6 +343,9 @@ func (s services) validate() error {
 	if s.admin == nil {
 		return fmt.Errorf("missing service: admin")
 	}
+	if s.apiTokens == nil {
+		return fmt.Errorf("missing service: apiTokens")
+	}
 	if s.attachments == nil {
 		return fmt.Errorf("missing service: attachments")
 	}
@@ -308,6 +358,9 @@ func (s services) validate() error {
 	if s.comments == nil {
 		return fmt.Errorf("missing service: comments")
 	}
+	if s.customFields == nil {
+		return fmt.Errorf("missing service: customFields")
+	}
 	if s.documents == nil {
 		return fmt.Errorf("missing service: documents")
 	}
@@ -323,6 +376,9 @@ func (s services) validate() error {
 	if s.slack == nil {
 		return fmt.Errorf("missing service: slack")
 	}
+	if s.inboundEmail == nil {
+		return fmt.Errorf("missing service: inboundEmail")
+	}
 	if s.integrationRequests == nil {
 		return fmt.Errorf("missing service: integrationRequests")
 	}
@@ -356,6 +412,9 @@ func (s services) validate() error {
 	if s.reports == nil {
 		return fmt.Errorf("missing service: reports")
 	}
+	if s.savedViews == nil {
+		return fmt.Errorf("missing service: savedViews")
+	}
 	if s.search == nil {
 		return fmt.Errorf("missing service: search")
 	}
@@ -377,9 +436,15 @@ func (s services) validate() error {
 	if s.teamSettings == nil {
 		return fmt.Errorf("missing service: teamSettings")
 	}
+	if s.timeEntries == nil {
+		return fmt.Errorf("missing service: timeEntries")
+	}
 	if s.users == nil {
 		return fmt.Errorf("missing service: users")
 	}
+	if s.webhooks == nil {
+		return fmt.Errorf("missing service: webhooks")
+	}
 	if s.workspaces == nil {
 		return fmt.Errorf("missing service: workspaces")
 	}
@@ -28,6 +28,9 @@ func buildTaskMux(log *logger.Logger, db *sqlx.DB, brevoService *brevo.Service,
 	mux.HandleFunc(tasks.TypeNotificationEmailDigest, workerTaskService.HandleNotificationEmailDigest)
 	mux.HandleFunc(tasks.TypeGitHubStorySync, workerTaskService.HandleGitHubStorySync)
 	mux.HandleFunc(tasks.TypeMayaBatchAssignment, workerTaskService.HandleMayaBatchAssignment)
+	mux.HandleFunc(tasks.TypeWebhookDelivery, workerTaskService.HandleWebhookDelivery)
+	mux.HandleFunc(tasks.TypeStoryRankRebalance, workerTaskService.HandleStoryRankRebalance)
+	mux.HandleFunc(tasks.TypeStorySLAResync, workerTaskService.HandleStorySLAResync)
 
 	// Cleanup handlers
 	mux.HandleFunc(tasks.TypeTokenCleanup, cleanupHandlers.HandleTokenCleanup)
@@ -40,6 +43,7 @@ func buildTaskMux(log *logger.Logger, db *sqlx.DB, brevoService *brevo.Service,
 	mux.HandleFunc(tasks.TypeSprintAutoCreation, cleanupHandlers.HandleSprintAutoCreation)
 	mux.HandleFunc(tasks.TypeStoryAutoArchive, cleanupHandlers.HandleStoryAutoArchive)
 	mux.HandleFunc(tasks.TypeStoryAutoClose, cleanupHandlers.HandleStoryAutoClose)
+	mux.HandleFunc(tasks.TypeStorySLAAlerts, cleanupHandlers.HandleStorySLAAlerts)
 	mux.HandleFunc(tasks.TypeSprintStoryMigration, cleanupHandlers.HandleSprintStoryMigration)
 	mux.HandleFunc(tasks.TypeMayaWorkFocusInference, cleanupHandlers.HandleMayaWorkFocusInference)
 	mux.HandleFunc("overdue:stories:email", cleanupHandlers.HandleOverdueStoriesEmail)
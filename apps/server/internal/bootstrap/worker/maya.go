@@ -21,7 +21,7 @@ import (
 
 func buildMayaService(log *logger.Logger, db *sqlx.DB, cfg Config, mayaActorID uuid.UUID) *maya.Service {
 	mentionsRepo := mentionsrepository.New(log, db)
-	storiesService := stories.New(log, storiesrepository.New(log, db), mentionsRepo, nil, nil)
+	storiesService := stories.New(log, storiesrepository.New(log, db), mentionsRepo, nil)
 	reportsService := reports.New(log, reportsrepository.New(log, db))
 	calendarService := calendar.New(log, calendarrepository.New(log, db), calendar.Config{
 		SecretKey:  cfg.Auth.SecretKey,
@@ -71,6 +71,15 @@ func registerSchedules(scheduler *asynq.Scheduler) error {
 		return fmt.Errorf("failed to register story auto-close task: %w", err)
 	}
 
+	_, err = scheduler.Register(
+		"*/15 * * * *", // Every 15 minutes
+		asynq.NewTask(tasks.TypeStorySLAAlerts, nil),
+		asynq.Queue("automation"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register story SLA alerts task: %w", err)
+	}
+
 	_, err = scheduler.Register(
 		"0 1 * * *", // Daily at 1:00 AM
 		asynq.NewTask(tasks.TypeSprintStoryMigration, nil),
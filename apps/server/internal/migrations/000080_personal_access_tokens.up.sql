@@ -0,0 +1,26 @@
+CREATE TABLE public.personal_access_tokens (
+    token_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    name text NOT NULL,
+    token_prefix text NOT NULL,
+    token_hash text NOT NULL,
+    scopes text[] NOT NULL DEFAULT '{}',
+    expires_at timestamptz,
+    last_used_at timestamptz,
+    revoked_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT personal_access_tokens_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES public.users(user_id) ON DELETE CASCADE,
+    CONSTRAINT personal_access_tokens_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT personal_access_tokens_name_not_empty CHECK (length(trim(name)) > 0),
+    PRIMARY KEY (token_id)
+);
+
+CREATE UNIQUE INDEX personal_access_tokens_hash_unique
+    ON public.personal_access_tokens (token_hash);
+
+CREATE INDEX idx_personal_access_tokens_user_workspace
+    ON public.personal_access_tokens (user_id, workspace_id, created_at DESC);
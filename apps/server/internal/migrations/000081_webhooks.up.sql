@@ -0,0 +1,57 @@
+CREATE TABLE public.webhook_endpoints (
+    endpoint_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    workspace_id uuid NOT NULL,
+    url text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    secret text NOT NULL,
+    event_types text[] NOT NULL DEFAULT '{}',
+    is_active boolean NOT NULL DEFAULT true,
+    consecutive_failures integer NOT NULL DEFAULT 0,
+    disabled_at timestamptz,
+    created_by uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT webhook_endpoints_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT webhook_endpoints_created_by_fkey
+        FOREIGN KEY (created_by) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    CONSTRAINT webhook_endpoints_url_https CHECK (url LIKE 'https://%'),
+    PRIMARY KEY (endpoint_id)
+);
+
+CREATE INDEX idx_webhook_endpoints_workspace
+    ON public.webhook_endpoints (workspace_id);
+
+CREATE INDEX idx_webhook_endpoints_active_event_types
+    ON public.webhook_endpoints USING gin (event_types)
+    WHERE is_active = true;
+
+CREATE TABLE public.webhook_deliveries (
+    delivery_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    endpoint_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    event_type text NOT NULL,
+    payload jsonb NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    response_code integer,
+    response_body text,
+    error text,
+    redelivery_of uuid,
+    last_attempt_at timestamptz,
+    delivered_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT webhook_deliveries_endpoint_id_fkey
+        FOREIGN KEY (endpoint_id) REFERENCES public.webhook_endpoints(endpoint_id) ON DELETE CASCADE,
+    CONSTRAINT webhook_deliveries_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT webhook_deliveries_redelivery_of_fkey
+        FOREIGN KEY (redelivery_of) REFERENCES public.webhook_deliveries(delivery_id) ON DELETE SET NULL,
+    CONSTRAINT webhook_deliveries_status_check
+        CHECK (status IN ('pending', 'succeeded', 'failed')),
+    PRIMARY KEY (delivery_id)
+);
+
+CREATE INDEX idx_webhook_deliveries_endpoint_created
+    ON public.webhook_deliveries (endpoint_id, created_at DESC);
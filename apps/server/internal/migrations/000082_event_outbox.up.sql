@@ -0,0 +1,22 @@
+CREATE TABLE public.event_outbox (
+    outbox_id bigserial NOT NULL,
+    dedupe_key text NOT NULL,
+    event_type text NOT NULL,
+    payload jsonb NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    published_at timestamptz,
+    PRIMARY KEY (outbox_id)
+);
+
+CREATE UNIQUE INDEX event_outbox_dedupe_key_unique
+    ON public.event_outbox (dedupe_key);
+
+CREATE INDEX idx_event_outbox_unpublished
+    ON public.event_outbox (outbox_id)
+    WHERE published_at IS NULL;
+
+CREATE INDEX idx_event_outbox_published_at
+    ON public.event_outbox (published_at)
+    WHERE published_at IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS public.idx_stories_epic_id;
+ALTER TABLE public.stories DROP CONSTRAINT IF EXISTS stories_epic_id_fkey;
+DROP TABLE IF EXISTS public.epics;
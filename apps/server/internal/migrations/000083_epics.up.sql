@@ -0,0 +1,44 @@
+CREATE TABLE public.epics (
+    id uuid NOT NULL DEFAULT gen_random_uuid(),
+    workspace_id uuid NOT NULL,
+    team_id uuid NOT NULL,
+    objective_id uuid,
+    name varchar(255) NOT NULL,
+    description text NOT NULL DEFAULT '',
+    description_html text NOT NULL DEFAULT '',
+    status text NOT NULL DEFAULT 'planned',
+    lead_user_id uuid,
+    start_date date,
+    target_date date,
+    color varchar(16) NOT NULL DEFAULT '#6b665c',
+    created_by uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT epics_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT epics_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT epics_objective_id_fkey
+        FOREIGN KEY (objective_id) REFERENCES public.objectives(objective_id) ON DELETE SET NULL,
+    CONSTRAINT epics_lead_user_id_fkey
+        FOREIGN KEY (lead_user_id) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    CONSTRAINT epics_created_by_fkey
+        FOREIGN KEY (created_by) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    CONSTRAINT epics_status_check
+        CHECK (status IN ('planned', 'in_progress', 'paused', 'completed', 'cancelled')),
+    CONSTRAINT epics_dates_check
+        CHECK (start_date IS NULL OR target_date IS NULL OR start_date <= target_date),
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX epics_team_name_unique ON public.epics (team_id, lower(name));
+CREATE INDEX idx_epics_workspace_team ON public.epics (workspace_id, team_id);
+CREATE INDEX idx_epics_objective_id ON public.epics (objective_id);
+
+ALTER TABLE public.stories ADD COLUMN IF NOT EXISTS epic_id uuid;
+-- Any epic ids set before this table existed pointed at placeholder epics.
+UPDATE public.stories SET epic_id = NULL WHERE epic_id IS NOT NULL;
+ALTER TABLE public.stories
+    ADD CONSTRAINT stories_epic_id_fkey
+    FOREIGN KEY (epic_id) REFERENCES public.epics(id) ON DELETE SET NULL;
+CREATE INDEX idx_stories_epic_id ON public.stories (epic_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.document_links;
+DROP TABLE IF EXISTS public.document_revisions;
+DROP TABLE IF EXISTS public.documents;
@@ -0,0 +1,73 @@
+CREATE TABLE public.documents (
+    id uuid NOT NULL DEFAULT gen_random_uuid(),
+    workspace_id uuid NOT NULL,
+    team_id uuid,
+    parent_id uuid,
+    title varchar(255) NOT NULL,
+    content text NOT NULL DEFAULT '',
+    content_html text NOT NULL DEFAULT '',
+    icon varchar(64),
+    revision integer NOT NULL DEFAULT 1,
+    created_by uuid,
+    updated_by uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    deleted_at timestamptz,
+    CONSTRAINT documents_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT documents_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT documents_parent_id_fkey
+        FOREIGN KEY (parent_id) REFERENCES public.documents(id) ON DELETE CASCADE,
+    CONSTRAINT documents_created_by_fkey
+        FOREIGN KEY (created_by) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    CONSTRAINT documents_updated_by_fkey
+        FOREIGN KEY (updated_by) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    CONSTRAINT documents_parent_not_self_check CHECK (parent_id IS NULL OR parent_id <> id),
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_documents_workspace_team ON public.documents (workspace_id, team_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_documents_parent_id ON public.documents (parent_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE public.document_revisions (
+    id uuid NOT NULL DEFAULT gen_random_uuid(),
+    document_id uuid NOT NULL,
+    revision integer NOT NULL,
+    title varchar(255) NOT NULL,
+    content text NOT NULL DEFAULT '',
+    content_html text NOT NULL DEFAULT '',
+    author_id uuid,
+    restored_from integer,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT document_revisions_document_id_fkey
+        FOREIGN KEY (document_id) REFERENCES public.documents(id) ON DELETE CASCADE,
+    CONSTRAINT document_revisions_author_id_fkey
+        FOREIGN KEY (author_id) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    CONSTRAINT document_revisions_unique UNIQUE (document_id, revision),
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE public.document_links (
+    id uuid NOT NULL DEFAULT gen_random_uuid(),
+    document_id uuid NOT NULL,
+    story_id uuid,
+    objective_id uuid,
+    created_by uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT document_links_document_id_fkey
+        FOREIGN KEY (document_id) REFERENCES public.documents(id) ON DELETE CASCADE,
+    CONSTRAINT document_links_story_id_fkey
+        FOREIGN KEY (story_id) REFERENCES public.stories(id) ON DELETE CASCADE,
+    CONSTRAINT document_links_objective_id_fkey
+        FOREIGN KEY (objective_id) REFERENCES public.objectives(objective_id) ON DELETE CASCADE,
+    CONSTRAINT document_links_created_by_fkey
+        FOREIGN KEY (created_by) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    CONSTRAINT document_links_single_target_check CHECK (num_nonnulls(story_id, objective_id) = 1),
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX document_links_story_unique ON public.document_links (document_id, story_id) WHERE story_id IS NOT NULL;
+CREATE UNIQUE INDEX document_links_objective_unique ON public.document_links (document_id, objective_id) WHERE objective_id IS NOT NULL;
+CREATE INDEX idx_document_links_story_id ON public.document_links (story_id) WHERE story_id IS NOT NULL;
+CREATE INDEX idx_document_links_objective_id ON public.document_links (objective_id) WHERE objective_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.story_custom_field_values;
+DROP TABLE IF EXISTS public.custom_fields;
@@ -0,0 +1,49 @@
+CREATE TABLE public.custom_fields (
+    id uuid NOT NULL DEFAULT gen_random_uuid(),
+    workspace_id uuid NOT NULL,
+    team_id uuid,
+    name varchar(100) NOT NULL,
+    description text NOT NULL DEFAULT '',
+    type text NOT NULL,
+    options jsonb NOT NULL DEFAULT '[]',
+    required boolean NOT NULL DEFAULT false,
+    position integer NOT NULL DEFAULT 0,
+    created_by uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    archived_at timestamptz,
+    CONSTRAINT custom_fields_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT custom_fields_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT custom_fields_created_by_fkey
+        FOREIGN KEY (created_by) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    CONSTRAINT custom_fields_type_check
+        CHECK (type IN ('text', 'number', 'single_select', 'multi_select', 'date', 'user', 'url')),
+    CONSTRAINT custom_fields_options_check CHECK (jsonb_typeof(options) = 'array'),
+    PRIMARY KEY (id)
+);
+
+-- Field names are unique within their scope: the workspace, or a single team.
+CREATE UNIQUE INDEX custom_fields_name_unique
+    ON public.custom_fields (workspace_id, COALESCE(team_id, '00000000-0000-0000-0000-000000000000'::uuid), lower(name))
+    WHERE archived_at IS NULL;
+CREATE INDEX idx_custom_fields_workspace_team ON public.custom_fields (workspace_id, team_id) WHERE archived_at IS NULL;
+
+CREATE TABLE public.story_custom_field_values (
+    story_id uuid NOT NULL,
+    field_id uuid NOT NULL,
+    value jsonb NOT NULL,
+    updated_by uuid,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT story_custom_field_values_story_id_fkey
+        FOREIGN KEY (story_id) REFERENCES public.stories(id) ON DELETE CASCADE,
+    CONSTRAINT story_custom_field_values_field_id_fkey
+        FOREIGN KEY (field_id) REFERENCES public.custom_fields(id) ON DELETE CASCADE,
+    CONSTRAINT story_custom_field_values_updated_by_fkey
+        FOREIGN KEY (updated_by) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    PRIMARY KEY (story_id, field_id)
+);
+
+CREATE INDEX idx_story_custom_field_values_field ON public.story_custom_field_values (field_id);
+CREATE INDEX idx_story_custom_field_values_value ON public.story_custom_field_values USING gin (value);
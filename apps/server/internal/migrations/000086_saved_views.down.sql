@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.saved_view_preferences;
+DROP TABLE IF EXISTS public.saved_views;
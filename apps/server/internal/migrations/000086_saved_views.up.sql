@@ -0,0 +1,44 @@
+CREATE TABLE public.saved_views (
+    id uuid NOT NULL DEFAULT gen_random_uuid(),
+    workspace_id uuid NOT NULL,
+    owner_id uuid NOT NULL,
+    scope text NOT NULL DEFAULT 'personal',
+    team_id uuid,
+    name varchar(100) NOT NULL,
+    icon varchar(50),
+    query jsonb NOT NULL DEFAULT '{}',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT saved_views_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT saved_views_owner_id_fkey
+        FOREIGN KEY (owner_id) REFERENCES public.users(user_id) ON DELETE CASCADE,
+    CONSTRAINT saved_views_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT saved_views_scope_check CHECK (scope IN ('personal', 'team', 'workspace')),
+    -- Only team views point at a team.
+    CONSTRAINT saved_views_team_scope_check CHECK ((scope = 'team') = (team_id IS NOT NULL)),
+    CONSTRAINT saved_views_query_check CHECK (jsonb_typeof(query) = 'object'),
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_saved_views_workspace_scope ON public.saved_views (workspace_id, scope);
+CREATE INDEX idx_saved_views_owner ON public.saved_views (owner_id);
+CREATE INDEX idx_saved_views_team ON public.saved_views (team_id) WHERE team_id IS NOT NULL;
+
+-- Favourites and ordering are per user, so a shared view can sit in a
+-- different place in every member's sidebar.
+CREATE TABLE public.saved_view_preferences (
+    user_id uuid NOT NULL,
+    view_id uuid NOT NULL,
+    is_favourite boolean NOT NULL DEFAULT false,
+    position integer,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT saved_view_preferences_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES public.users(user_id) ON DELETE CASCADE,
+    CONSTRAINT saved_view_preferences_view_id_fkey
+        FOREIGN KEY (view_id) REFERENCES public.saved_views(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, view_id)
+);
+
+CREATE INDEX idx_saved_view_preferences_view ON public.saved_view_preferences (view_id);
@@ -0,0 +1,27 @@
+-- Time logged against stories. A timer is an entry without ended_at; every
+-- other entry has both ends, including those logged as a plain duration.
+CREATE TABLE public.time_entries (
+    id uuid NOT NULL DEFAULT gen_random_uuid(),
+    workspace_id uuid NOT NULL,
+    story_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    started_at timestamptz NOT NULL,
+    ended_at timestamptz,
+    note varchar(500) NOT NULL DEFAULT '',
+    billable boolean NOT NULL DEFAULT false,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT time_entries_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT time_entries_story_id_fkey
+        FOREIGN KEY (story_id) REFERENCES public.stories(id) ON DELETE CASCADE,
+    CONSTRAINT time_entries_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES public.users(user_id) ON DELETE CASCADE,
+    CONSTRAINT time_entries_range_check CHECK (ended_at IS NULL OR ended_at > started_at),
+    PRIMARY KEY (id)
+);
+
+-- A user has at most one running timer across all workspaces.
+CREATE UNIQUE INDEX idx_time_entries_running_timer ON public.time_entries (user_id) WHERE ended_at IS NULL;
+CREATE INDEX idx_time_entries_workspace_user_started ON public.time_entries (workspace_id, user_id, started_at);
+CREATE INDEX idx_time_entries_story ON public.time_entries (story_id);
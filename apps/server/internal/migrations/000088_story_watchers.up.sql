@@ -0,0 +1,19 @@
+-- Users subscribed to a story's updates and comments. source records how the
+-- subscription started: explicitly, by commenting or by being mentioned.
+CREATE TABLE public.story_watchers (
+    story_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    source varchar(20) NOT NULL DEFAULT 'manual',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT story_watchers_story_id_fkey
+        FOREIGN KEY (story_id) REFERENCES public.stories(id) ON DELETE CASCADE,
+    CONSTRAINT story_watchers_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES public.users(user_id) ON DELETE CASCADE,
+    CONSTRAINT story_watchers_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT story_watchers_source_check CHECK (source IN ('manual', 'comment', 'mention')),
+    PRIMARY KEY (story_id, user_id)
+);
+
+CREATE INDEX idx_story_watchers_user ON public.story_watchers (user_id, workspace_id);
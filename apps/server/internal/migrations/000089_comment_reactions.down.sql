@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS public.comment_reactions;
+
+DELETE FROM public.notifications WHERE type = 'comment_reaction';
+
+UPDATE public.notification_preferences
+SET preferences = preferences - 'comment_reaction'
+WHERE preferences ? 'comment_reaction';
+
+-- Postgres cannot drop an enum value, so 'comment_reaction' stays on
+-- notification_type.
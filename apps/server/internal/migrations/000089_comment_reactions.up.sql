@@ -0,0 +1,27 @@
+-- Emoji reactions on story comments. A user can react to a comment once per
+-- emoji.
+CREATE TABLE public.comment_reactions (
+    comment_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    emoji varchar(32) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT comment_reactions_comment_id_fkey
+        FOREIGN KEY (comment_id) REFERENCES public.story_comments(comment_id) ON DELETE CASCADE,
+    CONSTRAINT comment_reactions_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES public.users(user_id) ON DELETE CASCADE,
+    PRIMARY KEY (comment_id, user_id, emoji)
+);
+
+CREATE INDEX idx_comment_reactions_comment ON public.comment_reactions (comment_id, created_at);
+
+ALTER TYPE public.notification_type ADD VALUE IF NOT EXISTS 'comment_reaction';
+
+-- Reactions are low signal, so they stay out of email digests by default.
+UPDATE public.notification_preferences
+SET preferences = jsonb_set(
+    preferences,
+    '{comment_reaction}',
+    '{"email": false, "in_app": true}'::jsonb,
+    true
+)
+WHERE NOT preferences ? 'comment_reaction';
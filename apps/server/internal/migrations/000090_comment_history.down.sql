@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS public.comment_revisions;
+
+ALTER TABLE public.story_comments
+    DROP COLUMN IF EXISTS resolved_by,
+    DROP COLUMN IF EXISTS resolved_at,
+    DROP COLUMN IF EXISTS edited_by,
+    DROP COLUMN IF EXISTS edited_at;
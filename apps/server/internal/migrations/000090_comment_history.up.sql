@@ -0,0 +1,28 @@
+-- Edit markers and thread resolution on comments. Only top-level comments
+-- are resolved; their replies follow the thread.
+ALTER TABLE public.story_comments
+    ADD COLUMN edited_at timestamptz,
+    ADD COLUMN edited_by uuid,
+    ADD COLUMN resolved_at timestamptz,
+    ADD COLUMN resolved_by uuid,
+    ADD CONSTRAINT story_comments_edited_by_fkey
+        FOREIGN KEY (edited_by) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    ADD CONSTRAINT story_comments_resolved_by_fkey
+        FOREIGN KEY (resolved_by) REFERENCES public.users(user_id) ON DELETE SET NULL;
+
+-- Every saved version of a comment. The first edit also records the
+-- original, so the history is complete.
+CREATE TABLE public.comment_revisions (
+    revision_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    comment_id uuid NOT NULL,
+    content text NOT NULL,
+    edited_by uuid,
+    edited_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT comment_revisions_comment_id_fkey
+        FOREIGN KEY (comment_id) REFERENCES public.story_comments(comment_id) ON DELETE CASCADE,
+    CONSTRAINT comment_revisions_edited_by_fkey
+        FOREIGN KEY (edited_by) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    PRIMARY KEY (revision_id)
+);
+
+CREATE INDEX idx_comment_revisions_comment ON public.comment_revisions (comment_id, edited_at DESC);
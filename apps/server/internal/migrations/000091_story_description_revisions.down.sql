@@ -0,0 +1 @@
+DROP TABLE IF EXISTS public.story_description_revisions;
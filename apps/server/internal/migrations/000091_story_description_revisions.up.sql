@@ -0,0 +1,22 @@
+-- Every saved version of a story description. reason carries the activity
+-- reason of the update, so syncs and restores can be told apart from edits.
+CREATE TABLE public.story_description_revisions (
+    revision_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    story_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    description text,
+    description_html text,
+    edited_by uuid,
+    reason text,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT story_description_revisions_story_id_fkey
+        FOREIGN KEY (story_id) REFERENCES public.stories(id) ON DELETE CASCADE,
+    CONSTRAINT story_description_revisions_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT story_description_revisions_edited_by_fkey
+        FOREIGN KEY (edited_by) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    PRIMARY KEY (revision_id)
+);
+
+CREATE INDEX idx_story_description_revisions_story
+    ON public.story_description_revisions (story_id, created_at DESC);
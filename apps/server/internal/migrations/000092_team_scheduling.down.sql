@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.team_holidays;
+DROP TABLE IF EXISTS public.team_scheduling_settings;
@@ -0,0 +1,28 @@
+-- Per-team scheduling. With cascade_dependencies on, the team's stories are
+-- moved forward when a story blocking them slips, skipping weekends and the
+-- team's holidays.
+CREATE TABLE public.team_scheduling_settings (
+    team_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    cascade_dependencies bool NOT NULL DEFAULT false,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT team_scheduling_settings_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT team_scheduling_settings_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    PRIMARY KEY (team_id)
+);
+
+CREATE TABLE public.team_holidays (
+    team_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    holiday_date date NOT NULL,
+    name varchar(100) NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT team_holidays_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT team_holidays_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    PRIMARY KEY (team_id, holiday_date)
+);
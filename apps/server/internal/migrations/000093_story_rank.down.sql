@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS public.idx_stories_workspace_rank;
+
+ALTER TABLE public.stories DROP COLUMN IF EXISTS rank;
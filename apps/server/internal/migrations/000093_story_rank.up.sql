@@ -0,0 +1,15 @@
+-- Manual story ordering. rank holds a fractional key compared byte by byte,
+-- so a story moves between two others by taking a key between theirs.
+ALTER TABLE public.stories ADD COLUMN rank varchar(255) COLLATE "C";
+
+-- Rank existing stories newest first with fixed width keys. Trailing zeros
+-- are trimmed because a key never ends in the lowest digit.
+UPDATE public.stories s
+SET rank = rtrim(lpad(ranked.position::text, 10, '0'), '0')
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY workspace_id ORDER BY created_at DESC, id) AS position
+    FROM public.stories
+) ranked
+WHERE s.id = ranked.id;
+
+CREATE INDEX idx_stories_workspace_rank ON public.stories (workspace_id, rank);
@@ -0,0 +1,18 @@
+-- References a story had before it moved to another team, so links to the
+-- old reference keep resolving to the story.
+CREATE TABLE public.story_ref_aliases (
+    team_id uuid NOT NULL,
+    sequence_id int4 NOT NULL,
+    story_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT story_ref_aliases_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT story_ref_aliases_story_id_fkey
+        FOREIGN KEY (story_id) REFERENCES public.stories(id) ON DELETE CASCADE,
+    CONSTRAINT story_ref_aliases_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    PRIMARY KEY (team_id, sequence_id)
+);
+
+CREATE INDEX idx_story_ref_aliases_story_id ON public.story_ref_aliases (story_id);
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS public.story_sla_clocks;
+DROP TABLE IF EXISTS public.team_sla_policies;
+DROP TABLE IF EXISTS public.team_sla_settings;
+
+DELETE FROM public.notifications WHERE type IN ('sla_at_risk', 'sla_breached');
+
+UPDATE public.notification_preferences
+SET preferences = preferences - 'sla_at_risk' - 'sla_breached'
+WHERE preferences ?| ARRAY['sla_at_risk', 'sla_breached'];
+
+-- Postgres cannot drop an enum value, so 'sla_at_risk' and 'sla_breached'
+-- stay on notification_type.
@@ -0,0 +1,88 @@
+-- Service level targets per team and priority. Targets are business time
+-- such as 30m, 4h or 2d, where a day is the team's working hours. Business
+-- time skips weekends, the team's holidays and time outside working hours.
+CREATE TABLE public.team_sla_settings (
+    team_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    timezone varchar(64) NOT NULL DEFAULT 'UTC',
+    workday_start time NOT NULL DEFAULT '09:00',
+    workday_end time NOT NULL DEFAULT '17:00',
+    at_risk_percent int4 NOT NULL DEFAULT 80,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT team_sla_settings_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT team_sla_settings_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT team_sla_settings_workday_check CHECK (workday_start < workday_end),
+    CONSTRAINT team_sla_settings_at_risk_percent_check CHECK (at_risk_percent BETWEEN 1 AND 99),
+    PRIMARY KEY (team_id)
+);
+
+CREATE TABLE public.team_sla_policies (
+    id uuid NOT NULL DEFAULT gen_random_uuid(),
+    team_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    priority varchar(50) NOT NULL,
+    first_response_target varchar(16),
+    resolve_target varchar(16),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT team_sla_policies_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT team_sla_policies_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT team_sla_policies_first_response_target_check
+        CHECK (first_response_target ~ '^[1-9][0-9]*[mhd]$'),
+    CONSTRAINT team_sla_policies_resolve_target_check
+        CHECK (resolve_target ~ '^[1-9][0-9]*[mhd]$'),
+    CONSTRAINT team_sla_policies_target_check
+        CHECK (num_nonnulls(first_response_target, resolve_target) > 0),
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX team_sla_policies_team_priority_key
+    ON public.team_sla_policies (team_id, lower(priority));
+
+-- The SLA clock of a story. The clock runs in business minutes: elapsed
+-- minutes were counted before running_since, and running_since is null while
+-- the clock is stopped, such as when the story is paused. Due and at-risk
+-- times are worked out whenever the clock starts. The alert columns hold the
+-- last alert sent for each target.
+CREATE TABLE public.story_sla_clocks (
+    story_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    priority varchar(50) NOT NULL,
+    first_response_minutes int4,
+    resolve_minutes int4,
+    elapsed_minutes int4 NOT NULL DEFAULT 0,
+    running_since timestamptz,
+    first_response_at_risk_at timestamptz,
+    first_response_due_at timestamptz,
+    first_responded_at timestamptz,
+    first_response_breached_at timestamptz,
+    first_response_alert varchar(20),
+    resolve_at_risk_at timestamptz,
+    resolve_due_at timestamptz,
+    resolved_at timestamptz,
+    resolve_breached_at timestamptz,
+    resolve_alert varchar(20),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT story_sla_clocks_story_id_fkey
+        FOREIGN KEY (story_id) REFERENCES public.stories(id) ON DELETE CASCADE,
+    CONSTRAINT story_sla_clocks_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT story_sla_clocks_first_response_alert_check
+        CHECK (first_response_alert IN ('at_risk', 'breached')),
+    CONSTRAINT story_sla_clocks_resolve_alert_check
+        CHECK (resolve_alert IN ('at_risk', 'breached')),
+    PRIMARY KEY (story_id)
+);
+
+CREATE INDEX idx_story_sla_clocks_running
+    ON public.story_sla_clocks (running_since)
+    WHERE running_since IS NOT NULL;
+
+ALTER TYPE public.notification_type ADD VALUE IF NOT EXISTS 'sla_at_risk';
+ALTER TYPE public.notification_type ADD VALUE IF NOT EXISTS 'sla_breached';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.team_category_requirements;
+DROP TABLE IF EXISTS public.team_status_transitions;
+DROP TABLE IF EXISTS public.team_workflow_settings;
@@ -0,0 +1,60 @@
+-- Workflow rules per team. With restrict_transitions on, stories only move
+-- between statuses along the team's transitions. Transitions with allowed
+-- roles can only be made by users with one of those workspace roles, which
+-- applies whether or not transitions are restricted. Integrations such as
+-- GitHub and Maya follow the rules unless integrations_override is on.
+CREATE TABLE public.team_workflow_settings (
+    team_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    restrict_transitions boolean NOT NULL DEFAULT false,
+    integrations_override boolean NOT NULL DEFAULT false,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT team_workflow_settings_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT team_workflow_settings_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    PRIMARY KEY (team_id)
+);
+
+CREATE TABLE public.team_status_transitions (
+    team_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    from_status_id uuid NOT NULL,
+    to_status_id uuid NOT NULL,
+    allowed_roles text[] NOT NULL DEFAULT '{}',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT team_status_transitions_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT team_status_transitions_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT team_status_transitions_from_status_id_fkey
+        FOREIGN KEY (from_status_id) REFERENCES public.statuses(status_id) ON DELETE CASCADE,
+    CONSTRAINT team_status_transitions_to_status_id_fkey
+        FOREIGN KEY (to_status_id) REFERENCES public.statuses(status_id) ON DELETE CASCADE,
+    CONSTRAINT team_status_transitions_distinct_check CHECK (from_status_id <> to_status_id),
+    CONSTRAINT team_status_transitions_roles_check
+        CHECK (allowed_roles <@ ARRAY['admin', 'member', 'guest', 'system']::text[]),
+    PRIMARY KEY (team_id, from_status_id, to_status_id)
+);
+
+-- Fields a story needs before it enters a status of the category.
+CREATE TABLE public.team_category_requirements (
+    team_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    category varchar(50) NOT NULL,
+    required_fields text[] NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT team_category_requirements_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT team_category_requirements_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT team_category_requirements_category_check
+        CHECK (category IN ('backlog', 'unstarted', 'started', 'paused', 'completed', 'cancelled')),
+    CONSTRAINT team_category_requirements_fields_check
+        CHECK (
+            cardinality(required_fields) > 0
+            AND required_fields <@ ARRAY['assignee', 'estimate', 'sprint', 'start_date', 'deadline', 'epic', 'objective', 'description']::text[]
+        ),
+    PRIMARY KEY (team_id, category)
+);
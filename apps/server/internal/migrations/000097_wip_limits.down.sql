@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS public.team_wip_limits;
+
+ALTER TABLE public.team_workflow_settings
+    DROP CONSTRAINT IF EXISTS team_workflow_settings_wip_enforcement_check,
+    DROP COLUMN IF EXISTS wip_enforcement;
@@ -0,0 +1,25 @@
+-- Work-in-progress limits per status. A limit counts all of the status's
+-- stories, or each assignee's stories when per_assignee is set. Teams either
+-- warn about moves into a full status or block them.
+ALTER TABLE public.team_workflow_settings
+    ADD COLUMN wip_enforcement varchar(10) NOT NULL DEFAULT 'warn',
+    ADD CONSTRAINT team_workflow_settings_wip_enforcement_check CHECK (wip_enforcement IN ('warn', 'block'));
+
+CREATE TABLE public.team_wip_limits (
+    team_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    status_id uuid NOT NULL,
+    max_stories integer NOT NULL,
+    per_assignee boolean NOT NULL DEFAULT false,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT team_wip_limits_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT team_wip_limits_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT team_wip_limits_status_id_fkey
+        FOREIGN KEY (status_id) REFERENCES public.statuses(status_id) ON DELETE CASCADE,
+    CONSTRAINT team_wip_limits_max_stories_check CHECK (max_stories BETWEEN 1 AND 1000),
+    PRIMARY KEY (team_id, status_id)
+);
+
+CREATE INDEX idx_team_wip_limits_status_id ON public.team_wip_limits (status_id);
@@ -0,0 +1,29 @@
+-- Ordered checklist items on a story, for small steps that do not need a
+-- sub-story of their own.
+CREATE TABLE public.story_checklist_items (
+    item_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    story_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    text varchar(500) NOT NULL,
+    done boolean NOT NULL DEFAULT false,
+    assignee_id uuid,
+    due_date date,
+    order_index int4 NOT NULL DEFAULT 0,
+    created_by uuid,
+    completed_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT story_checklist_items_story_id_fkey
+        FOREIGN KEY (story_id) REFERENCES public.stories(id) ON DELETE CASCADE,
+    CONSTRAINT story_checklist_items_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT story_checklist_items_assignee_id_fkey
+        FOREIGN KEY (assignee_id) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    CONSTRAINT story_checklist_items_created_by_fkey
+        FOREIGN KEY (created_by) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    CONSTRAINT story_checklist_items_text_check CHECK (length(btrim(text)) > 0),
+    PRIMARY KEY (item_id)
+);
+
+CREATE INDEX idx_story_checklist_items_story
+    ON public.story_checklist_items (story_id, order_index);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS public.idx_story_associations_workspace_type;
+
+DELETE FROM public.story_associations
+WHERE association_type NOT IN ('blocking', 'related', 'duplicate');
+
+ALTER TABLE public.story_associations
+    ADD CONSTRAINT story_associations_association_type_check
+    CHECK (association_type IN ('blocking', 'related', 'duplicate'));
+
+DROP TABLE IF EXISTS public.story_association_types;
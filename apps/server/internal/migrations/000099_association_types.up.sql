@@ -0,0 +1,28 @@
+-- Workspace-defined association types. The built-in blocking, related and
+-- duplicate types are not stored here; their keys are reserved.
+CREATE TABLE public.story_association_types (
+    type_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    workspace_id uuid NOT NULL,
+    key varchar(50) NOT NULL,
+    forward_label varchar(100) NOT NULL,
+    backward_label varchar(100) NOT NULL,
+    created_by uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT story_association_types_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT story_association_types_created_by_fkey
+        FOREIGN KEY (created_by) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    CONSTRAINT story_association_types_key_check
+        CHECK (key !~ '^(blocking|related|duplicate)$'),
+    CONSTRAINT story_association_types_workspace_key_key UNIQUE (workspace_id, key),
+    PRIMARY KEY (type_id)
+);
+
+-- Association types are now checked against the workspace's types by the
+-- application instead of a fixed list.
+ALTER TABLE public.story_associations
+    DROP CONSTRAINT IF EXISTS story_associations_association_type_check;
+
+CREATE INDEX idx_story_associations_workspace_type
+    ON public.story_associations (workspace_id, association_type);
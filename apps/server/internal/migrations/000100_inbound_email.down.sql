@@ -0,0 +1,11 @@
+DELETE FROM public.users WHERE email = 'email@fortyone.app';
+
+DELETE FROM public.integration_requests WHERE provider = 'email';
+
+ALTER TABLE public.integration_requests
+    DROP CONSTRAINT integration_requests_provider_check,
+    ADD CONSTRAINT integration_requests_provider_check
+        CHECK (provider IN ('github', 'slack', 'intercom'));
+
+DROP TABLE IF EXISTS public.inbound_email_messages;
+DROP TABLE IF EXISTS public.team_inbound_addresses;
@@ -0,0 +1,94 @@
+-- Per-team inbound email addresses. Mail sent to <local_part>@<inbound domain>
+-- becomes an integration request or a story on the team.
+CREATE TABLE public.team_inbound_addresses (
+    team_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    local_part varchar(64) NOT NULL,
+    mode varchar(20) NOT NULL DEFAULT 'request',
+    is_active boolean NOT NULL DEFAULT true,
+    allowed_domains text[] NOT NULL DEFAULT '{}',
+    created_by uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT team_inbound_addresses_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT team_inbound_addresses_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT team_inbound_addresses_created_by_fkey
+        FOREIGN KEY (created_by) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    CONSTRAINT team_inbound_addresses_mode_check CHECK (mode IN ('request', 'story')),
+    CONSTRAINT team_inbound_addresses_local_part_key UNIQUE (local_part),
+    PRIMARY KEY (team_id)
+);
+
+-- Every message received on an inbound address, including rejected ones. The
+-- RFC 5322 Message-ID threads replies onto the story or request the first
+-- message created. A delivery claims its Message-ID as 'pending' before
+-- filing the message so redeliveries cannot file it twice.
+CREATE TABLE public.inbound_email_messages (
+    inbound_message_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    workspace_id uuid NOT NULL,
+    team_id uuid NOT NULL,
+    message_id text NOT NULL,
+    in_reply_to text,
+    sender_email text NOT NULL,
+    sender_user_id uuid,
+    subject text NOT NULL DEFAULT '',
+    size_bytes integer NOT NULL DEFAULT 0,
+    outcome varchar(20) NOT NULL,
+    reason text,
+    story_id uuid,
+    request_id uuid,
+    comment_id uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT inbound_email_messages_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES public.workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT inbound_email_messages_team_id_fkey
+        FOREIGN KEY (team_id) REFERENCES public.teams(team_id) ON DELETE CASCADE,
+    CONSTRAINT inbound_email_messages_sender_user_id_fkey
+        FOREIGN KEY (sender_user_id) REFERENCES public.users(user_id) ON DELETE SET NULL,
+    CONSTRAINT inbound_email_messages_story_id_fkey
+        FOREIGN KEY (story_id) REFERENCES public.stories(id) ON DELETE SET NULL,
+    CONSTRAINT inbound_email_messages_request_id_fkey
+        FOREIGN KEY (request_id) REFERENCES public.integration_requests(id) ON DELETE SET NULL,
+    CONSTRAINT inbound_email_messages_outcome_check
+        CHECK (outcome IN ('pending', 'story', 'request', 'comment', 'rejected')),
+    CONSTRAINT inbound_email_messages_workspace_message_key UNIQUE (workspace_id, message_id),
+    PRIMARY KEY (inbound_message_id)
+);
+
+CREATE INDEX idx_inbound_email_messages_sender
+    ON public.inbound_email_messages (workspace_id, lower(sender_email), created_at);
+CREATE INDEX idx_inbound_email_messages_request
+    ON public.inbound_email_messages (request_id) WHERE request_id IS NOT NULL;
+
+ALTER TABLE public.integration_requests
+    DROP CONSTRAINT integration_requests_provider_check,
+    ADD CONSTRAINT integration_requests_provider_check
+        CHECK (provider IN ('github', 'slack', 'intercom', 'email'));
+
+-- Comments from senders who are not workspace members are posted as this user.
+INSERT INTO public.users (
+    user_id,
+    username,
+    email,
+    full_name,
+    is_active,
+    is_system,
+    timezone
+) VALUES (
+    '00000000-0000-0000-0000-000000000003',
+    'email',
+    'email@fortyone.app',
+    'Email',
+    true,
+    true,
+    'UTC'
+)
+ON CONFLICT (email) DO UPDATE
+SET
+    username = EXCLUDED.username,
+    full_name = EXCLUDED.full_name,
+    is_active = EXCLUDED.is_active,
+    is_system = EXCLUDED.is_system,
+    timezone = EXCLUDED.timezone;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS public.idx_event_outbox_unpublished;
+CREATE INDEX idx_event_outbox_unpublished
+    ON public.event_outbox (outbox_id)
+    WHERE published_at IS NULL;
+
+ALTER TABLE public.event_outbox DROP COLUMN IF EXISTS dead_at;
@@ -0,0 +1,8 @@
+-- Rows whose payload can never be relayed are parked here instead of being
+-- retried on every poll.
+ALTER TABLE public.event_outbox ADD COLUMN dead_at timestamptz;
+
+DROP INDEX IF EXISTS public.idx_event_outbox_unpublished;
+CREATE INDEX idx_event_outbox_unpublished
+    ON public.event_outbox (outbox_id)
+    WHERE published_at IS NULL AND dead_at IS NULL;
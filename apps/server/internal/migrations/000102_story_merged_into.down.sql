@@ -0,0 +1 @@
+ALTER TABLE public.stories DROP COLUMN IF EXISTS merged_into_id;
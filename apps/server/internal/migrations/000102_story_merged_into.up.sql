@@ -0,0 +1,7 @@
+-- The canonical story a duplicate was merged into, so the duplicate's
+-- reference resolves to the story that carries on its work.
+ALTER TABLE public.stories ADD COLUMN merged_into_id uuid;
+ALTER TABLE public.stories ADD CONSTRAINT stories_merged_into_id_fkey
+    FOREIGN KEY (merged_into_id) REFERENCES public.stories(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_stories_merged_into_id ON public.stories (merged_into_id) WHERE merged_into_id IS NOT NULL;
@@ -22,6 +22,10 @@ type Handlers struct {
 	admin *admin.Service
 }
 
+type resolveDeadLetterRequest struct {
+	Reason string `json:"reason"`
+}
+
 type updateWorkspaceTrialRequest struct {
 	TrialEndsOn time.Time `json:"trialEndsOn"`
 	Reason      string    `json:"reason"`
@@ -177,6 +181,75 @@ func (h *Handlers) ListAuditLogs(ctx context.Context, w http.ResponseWriter, r *
 	return web.Respond(ctx, w, result, http.StatusOK)
 }
 
+func (h *Handlers) ListDeadLetters(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	_, limit := paginationParams(r)
+	result, err := h.admin.ListDeadLetters(ctx, userID, admin.ListDeadLettersInput{
+		Cursor: r.URL.Query().Get("cursor"),
+		Limit:  limit,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, adminErrorStatus(err))
+	}
+	return web.Respond(ctx, w, result, http.StatusOK)
+}
+
+func (h *Handlers) GetDeadLetter(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	entry, err := h.admin.GetDeadLetter(ctx, userID, web.Params(r, "deadLetterID"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, adminErrorStatus(err))
+	}
+	return web.Respond(ctx, w, entry, http.StatusOK)
+}
+
+func (h *Handlers) ReplayDeadLetter(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	var req resolveDeadLetterRequest
+	if r.ContentLength > 0 {
+		if err := web.Decode(r, &req); err != nil {
+			return web.RespondError(ctx, w, err, http.StatusBadRequest)
+		}
+	}
+
+	entry, err := h.admin.ReplayDeadLetter(ctx, userID, web.Params(r, "deadLetterID"), req.Reason)
+	if err != nil {
+		return web.RespondError(ctx, w, err, adminErrorStatus(err))
+	}
+	return web.Respond(ctx, w, entry, http.StatusOK)
+}
+
+func (h *Handlers) DiscardDeadLetter(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	var req resolveDeadLetterRequest
+	if r.ContentLength > 0 {
+		if err := web.Decode(r, &req); err != nil {
+			return web.RespondError(ctx, w, err, http.StatusBadRequest)
+		}
+	}
+
+	if _, err := h.admin.DiscardDeadLetter(ctx, userID, web.Params(r, "deadLetterID"), req.Reason); err != nil {
+		return web.RespondError(ctx, w, err, adminErrorStatus(err))
+	}
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
 func adminErrorStatus(err error) int {
 	switch {
 	case errors.Is(err, admin.ErrForbidden):
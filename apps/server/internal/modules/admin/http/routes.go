@@ -25,4 +25,8 @@ func Routes(cfg Config, app *web.App) {
 	app.Get("/admin/users", h.ListUsers, auth)
 	app.Get("/admin/users/{userID}", h.GetUser, auth)
 	app.Get("/admin/audit-logs", h.ListAuditLogs, auth)
+	app.Get("/admin/dead-letters", h.ListDeadLetters, auth)
+	app.Get("/admin/dead-letters/{deadLetterID}", h.GetDeadLetter, auth)
+	app.Post("/admin/dead-letters/{deadLetterID}/replay", h.ReplayDeadLetter, auth)
+	app.Delete("/admin/dead-letters/{deadLetterID}", h.DiscardDeadLetter, auth)
 }
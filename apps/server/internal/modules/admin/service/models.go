@@ -138,6 +138,11 @@ type ListAuditLogsInput struct {
 	TargetType  string
 }
 
+type ListDeadLettersInput struct {
+	Cursor string
+	Limit  int
+}
+
 type UpdateWorkspaceTrialInput struct {
 	WorkspaceID uuid.UUID `json:"-"`
 	TrialEndsOn time.Time `json:"trialEndsOn"`
@@ -2,9 +2,11 @@ package admin
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"time"
 
+	"github.com/complexus-tech/projects-api/pkg/deadletter"
 	"github.com/complexus-tech/projects-api/pkg/web"
 	"github.com/google/uuid"
 )
@@ -21,9 +23,18 @@ type Repository interface {
 	InsertAuditEntry(ctx context.Context, input AuditEntryInput) error
 }
 
+// DeadLetterStore manages events that exhausted their stream delivery attempts.
+type DeadLetterStore interface {
+	List(ctx context.Context, cursor string, limit int) (deadletter.Page, error)
+	Get(ctx context.Context, id string) (deadletter.Entry, error)
+	Replay(ctx context.Context, id string) (deadletter.Entry, error)
+	Discard(ctx context.Context, id string) (deadletter.Entry, error)
+}
+
 type Service struct {
-	repo Repository
-	now  func() time.Time
+	repo        Repository
+	deadLetters DeadLetterStore
+	now         func() time.Time
 }
 
 type Option func(*Service)
@@ -34,6 +45,12 @@ func WithNow(now func() time.Time) Option {
 	}
 }
 
+func WithDeadLetters(store DeadLetterStore) Option {
+	return func(s *Service) {
+		s.deadLetters = store
+	}
+}
+
 func New(repo Repository, opts ...Option) *Service {
 	s := &Service{
 		repo: repo,
@@ -173,6 +190,92 @@ func (s *Service) ListAuditLogs(ctx context.Context, actorID uuid.UUID, input Li
 	return s.repo.ListAuditLogs(ctx, normalizeListAuditLogsInput(input))
 }
 
+func (s *Service) ListDeadLetters(ctx context.Context, actorID uuid.UUID, input ListDeadLettersInput) (deadletter.Page, error) {
+	ctx, span := web.AddSpan(ctx, "business.admin.ListDeadLetters")
+	defer span.End()
+
+	if _, err := s.ensureAdmin(ctx, actorID); err != nil {
+		return deadletter.Page{}, err
+	}
+	if s.deadLetters == nil {
+		return deadletter.Page{Items: []deadletter.Entry{}}, nil
+	}
+	limit := normalizePagination(PaginationInput{Limit: input.Limit}).Limit
+	return s.deadLetters.List(ctx, strings.TrimSpace(input.Cursor), limit)
+}
+
+func (s *Service) GetDeadLetter(ctx context.Context, actorID uuid.UUID, id string) (deadletter.Entry, error) {
+	ctx, span := web.AddSpan(ctx, "business.admin.GetDeadLetter")
+	defer span.End()
+
+	if _, err := s.ensureAdmin(ctx, actorID); err != nil {
+		return deadletter.Entry{}, err
+	}
+	if s.deadLetters == nil {
+		return deadletter.Entry{}, ErrNotFound
+	}
+	entry, err := s.deadLetters.Get(ctx, id)
+	if errors.Is(err, deadletter.ErrNotFound) {
+		return deadletter.Entry{}, ErrNotFound
+	}
+	return entry, err
+}
+
+// ReplayDeadLetter republishes a dead-lettered event to the events stream.
+func (s *Service) ReplayDeadLetter(ctx context.Context, actorID uuid.UUID, id, reason string) (deadletter.Entry, error) {
+	ctx, span := web.AddSpan(ctx, "business.admin.ReplayDeadLetter")
+	defer span.End()
+
+	return s.resolveDeadLetter(ctx, actorID, id, reason, "event.dead_letter_replayed", func(ctx context.Context, id string) (deadletter.Entry, error) {
+		return s.deadLetters.Replay(ctx, id)
+	})
+}
+
+// DiscardDeadLetter drops a dead-lettered event for good.
+func (s *Service) DiscardDeadLetter(ctx context.Context, actorID uuid.UUID, id, reason string) (deadletter.Entry, error) {
+	ctx, span := web.AddSpan(ctx, "business.admin.DiscardDeadLetter")
+	defer span.End()
+
+	return s.resolveDeadLetter(ctx, actorID, id, reason, "event.dead_letter_discarded", func(ctx context.Context, id string) (deadletter.Entry, error) {
+		return s.deadLetters.Discard(ctx, id)
+	})
+}
+
+func (s *Service) resolveDeadLetter(ctx context.Context, actorID uuid.UUID, id, reason, action string, resolve func(context.Context, string) (deadletter.Entry, error)) (deadletter.Entry, error) {
+	if _, err := s.ensureAdmin(ctx, actorID); err != nil {
+		return deadletter.Entry{}, err
+	}
+	if s.deadLetters == nil {
+		return deadletter.Entry{}, ErrNotFound
+	}
+
+	entry, err := resolve(ctx, id)
+	if err != nil {
+		if errors.Is(err, deadletter.ErrNotFound) {
+			return deadletter.Entry{}, ErrNotFound
+		}
+		return deadletter.Entry{}, err
+	}
+
+	if err := s.repo.InsertAuditEntry(ctx, AuditEntryInput{
+		ActorUserID: actorID,
+		TargetType:  "dead_letter",
+		Action:      action,
+		Reason:      strings.TrimSpace(reason),
+		Metadata: map[string]any{
+			"dead_letter_id": entry.ID,
+			"message_id":     entry.MessageID,
+			"event_type":     entry.EventType,
+			"attempts":       entry.Attempts,
+			"error":          entry.Error,
+			"replayed_as":    entry.ReplayedAs,
+		},
+	}); err != nil {
+		return deadletter.Entry{}, err
+	}
+	return entry, nil
+}
+
 func (s *Service) ensureAdmin(ctx context.Context, actorID uuid.UUID) (UserSummary, error) {
 	user, err := s.repo.GetAdminUser(ctx, actorID)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/complexus-tech/projects-api/pkg/deadletter"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -65,6 +66,43 @@ func (r *adminTestRepo) InsertAuditEntry(ctx context.Context, input AuditEntryIn
 	return nil
 }
 
+type deadLetterStoreStub struct {
+	entries  map[string]deadletter.Entry
+	replayed []string
+}
+
+func (s *deadLetterStoreStub) List(ctx context.Context, cursor string, limit int) (deadletter.Page, error) {
+	return deadletter.Page{}, nil
+}
+
+func (s *deadLetterStoreStub) Get(ctx context.Context, id string) (deadletter.Entry, error) {
+	entry, ok := s.entries[id]
+	if !ok {
+		return deadletter.Entry{}, deadletter.ErrNotFound
+	}
+	return entry, nil
+}
+
+func (s *deadLetterStoreStub) Replay(ctx context.Context, id string) (deadletter.Entry, error) {
+	entry, err := s.Get(ctx, id)
+	if err != nil {
+		return deadletter.Entry{}, err
+	}
+	s.replayed = append(s.replayed, id)
+	entry.ReplayedAs = "1700000000000-1"
+	delete(s.entries, id)
+	return entry, nil
+}
+
+func (s *deadLetterStoreStub) Discard(ctx context.Context, id string) (deadletter.Entry, error) {
+	entry, err := s.Get(ctx, id)
+	if err != nil {
+		return deadletter.Entry{}, err
+	}
+	delete(s.entries, id)
+	return entry, nil
+}
+
 func TestListWorkspacesRejectsNonInternalUsers(t *testing.T) {
 	actorID := uuid.New()
 	repo := &adminTestRepo{
@@ -244,3 +282,43 @@ type adminTestRepoWithAuditError struct {
 func (r *adminTestRepoWithAuditError) InsertAuditEntry(ctx context.Context, input AuditEntryInput) error {
 	return r.auditErr
 }
+
+func TestReplayDeadLetterRepublishesAndWritesAudit(t *testing.T) {
+	actorID := uuid.New()
+	repo := &adminTestRepo{
+		users: map[uuid.UUID]UserSummary{
+			actorID: {ID: actorID, Email: "ops@fortyone.app", IsActive: true, IsInternal: true},
+		},
+	}
+	store := &deadLetterStoreStub{entries: map[string]deadletter.Entry{
+		"1699999999999-0": {ID: "1699999999999-0", MessageID: "1699999990000-0", EventType: "story.updated", Attempts: 5, Error: "boom"},
+	}}
+	service := New(repo, WithDeadLetters(store))
+
+	entry, err := service.ReplayDeadLetter(context.Background(), actorID, "1699999999999-0", "  handler fixed  ")
+
+	require.NoError(t, err)
+	require.Equal(t, "1700000000000-1", entry.ReplayedAs)
+	require.Equal(t, []string{"1699999999999-0"}, store.replayed)
+	require.Len(t, repo.auditEntries, 1)
+	audit := repo.auditEntries[0]
+	require.Equal(t, "dead_letter", audit.TargetType)
+	require.Equal(t, "event.dead_letter_replayed", audit.Action)
+	require.Equal(t, "handler fixed", audit.Reason)
+	require.Equal(t, "story.updated", audit.Metadata["event_type"])
+}
+
+func TestDiscardDeadLetterMapsMissingEntryToNotFound(t *testing.T) {
+	actorID := uuid.New()
+	repo := &adminTestRepo{
+		users: map[uuid.UUID]UserSummary{
+			actorID: {ID: actorID, Email: "ops@fortyone.app", IsActive: true, IsInternal: true},
+		},
+	}
+	service := New(repo, WithDeadLetters(&deadLetterStoreStub{}))
+
+	_, err := service.DiscardDeadLetter(context.Background(), actorID, "1699999999999-0", "")
+
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Empty(t, repo.auditEntries)
+}
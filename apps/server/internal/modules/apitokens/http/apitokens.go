@@ -0,0 +1,117 @@
+package apitokenshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	apitokens "github.com/complexus-tech/projects-api/internal/modules/apitokens/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+type Handlers struct {
+	tokens *apitokens.Service
+	log    *logger.Logger
+}
+
+func New(service *apitokens.Service, log *logger.Logger) *Handlers {
+	return &Handlers{tokens: service, log: log}
+}
+
+func (h *Handlers) List(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, userID, err := requestIdentity(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	tokens, err := h.tokens.List(ctx, workspace.ID, userID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusInternalServerError)
+	}
+	return web.Respond(ctx, w, toAppTokens(tokens), http.StatusOK)
+}
+
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, userID, err := requestIdentity(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	var input AppNewToken
+	if err := web.Decode(r, &input); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	created, err := h.tokens.Create(ctx, apitokens.CoreNewToken{
+		UserID:      userID,
+		WorkspaceID: workspace.ID,
+		Name:        input.Name,
+		Scopes:      input.Scopes,
+		ExpiresAt:   input.ExpiresAt,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, AppCreatedToken{AppToken: toAppToken(created.Token), Token: created.Plaintext}, http.StatusCreated)
+}
+
+func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, userID, err := requestIdentity(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	tokenID, err := uuid.Parse(web.Params(r, "tokenId"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	var input AppUpdateToken
+	if err := web.Decode(r, &input); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	token, err := h.tokens.Update(ctx, workspace.ID, userID, tokenID, apitokens.CoreUpdateToken{
+		Name:   input.Name,
+		Scopes: input.Scopes,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, toAppToken(token), http.StatusOK)
+}
+
+func (h *Handlers) Revoke(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, userID, err := requestIdentity(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	tokenID, err := uuid.Parse(web.Params(r, "tokenId"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	if err := h.tokens.Revoke(ctx, workspace.ID, userID, tokenID); err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+func requestIdentity(ctx context.Context) (mid.WorkspaceInfo, uuid.UUID, error) {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return mid.WorkspaceInfo{}, uuid.Nil, err
+	}
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return mid.WorkspaceInfo{}, uuid.Nil, err
+	}
+	return workspace, userID, nil
+}
+
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, apitokens.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apitokens.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
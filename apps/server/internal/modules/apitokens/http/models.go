@@ -0,0 +1,59 @@
+package apitokenshttp
+
+import (
+	"time"
+
+	apitokens "github.com/complexus-tech/projects-api/internal/modules/apitokens/service"
+	"github.com/google/uuid"
+)
+
+type AppToken struct {
+	ID          uuid.UUID  `json:"id"`
+	WorkspaceID uuid.UUID  `json:"workspaceId"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Scopes      []string   `json:"scopes"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+	LastUsedAt  *time.Time `json:"lastUsedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// AppCreatedToken includes the plaintext token, which is never returned again.
+type AppCreatedToken struct {
+	AppToken
+	Token string `json:"token"`
+}
+
+type AppNewToken struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type AppUpdateToken struct {
+	Name   *string  `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+func toAppToken(token apitokens.CoreToken) AppToken {
+	return AppToken{
+		ID:          token.ID,
+		WorkspaceID: token.WorkspaceID,
+		Name:        token.Name,
+		Prefix:      token.Prefix,
+		Scopes:      token.Scopes,
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		CreatedAt:   token.CreatedAt,
+		UpdatedAt:   token.UpdatedAt,
+	}
+}
+
+func toAppTokens(tokens []apitokens.CoreToken) []AppToken {
+	result := make([]AppToken, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, toAppToken(token))
+	}
+	return result
+}
@@ -0,0 +1,29 @@
+package apitokenshttp
+
+import (
+	apitokens "github.com/complexus-tech/projects-api/internal/modules/apitokens/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/cache"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	DB        *sqlx.DB
+	Log       *logger.Logger
+	SecretKey string
+	Cache     *cache.Service
+	Service   *apitokens.Service
+}
+
+func Routes(cfg Config, app *web.App) {
+	h := New(cfg.Service, cfg.Log)
+	auth := mid.Auth(cfg.Log, cfg.SecretKey)
+	workspace := mid.Workspace(cfg.Log, cfg.DB, cfg.Cache)
+
+	app.Get("/workspaces/{workspaceSlug}/api-tokens", h.List, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/api-tokens", h.Create, auth, workspace)
+	app.Patch("/workspaces/{workspaceSlug}/api-tokens/{tokenId}", h.Update, auth, workspace)
+	app.Delete("/workspaces/{workspaceSlug}/api-tokens/{tokenId}", h.Revoke, auth, workspace)
+}
@@ -0,0 +1,152 @@
+package apitokensrepository
+
+import (
+	"context"
+	"time"
+
+	apitokens "github.com/complexus-tech/projects-api/internal/modules/apitokens/service"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repo struct {
+	log *logger.Logger
+	db  *sqlx.DB
+}
+
+func New(log *logger.Logger, db *sqlx.DB) *Repo {
+	return &Repo{log: log, db: db}
+}
+
+const tokenColumns = `token_id, user_id, workspace_id, name, token_prefix, scopes, expires_at, last_used_at, revoked_at, created_at, updated_at`
+
+type tokenRow struct {
+	ID          uuid.UUID      `db:"token_id"`
+	UserID      uuid.UUID      `db:"user_id"`
+	WorkspaceID uuid.UUID      `db:"workspace_id"`
+	Name        string         `db:"name"`
+	Prefix      string         `db:"token_prefix"`
+	Scopes      pq.StringArray `db:"scopes"`
+	ExpiresAt   *time.Time     `db:"expires_at"`
+	LastUsedAt  *time.Time     `db:"last_used_at"`
+	RevokedAt   *time.Time     `db:"revoked_at"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+type resolvedTokenRow struct {
+	tokenRow
+	WorkspaceSlug string `db:"workspace_slug"`
+}
+
+func (r *Repo) List(ctx context.Context, workspaceID, userID uuid.UUID) ([]apitokens.CoreToken, error) {
+	var rows []tokenRow
+	if err := r.db.SelectContext(ctx, &rows, `
+		SELECT `+tokenColumns+`
+		FROM personal_access_tokens
+		WHERE workspace_id = $1 AND user_id = $2 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	result := make([]apitokens.CoreToken, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toCoreToken(row))
+	}
+	return result, nil
+}
+
+func (r *Repo) Get(ctx context.Context, workspaceID, userID, tokenID uuid.UUID) (apitokens.CoreToken, error) {
+	var row tokenRow
+	if err := r.db.GetContext(ctx, &row, `
+		SELECT `+tokenColumns+`
+		FROM personal_access_tokens
+		WHERE workspace_id = $1 AND user_id = $2 AND token_id = $3 AND revoked_at IS NULL
+	`, workspaceID, userID, tokenID); err != nil {
+		return apitokens.CoreToken{}, err
+	}
+	return toCoreToken(row), nil
+}
+
+func (r *Repo) Create(ctx context.Context, input apitokens.CoreNewToken, prefix, hash string) (apitokens.CoreToken, error) {
+	var row tokenRow
+	if err := r.db.GetContext(ctx, &row, `
+		INSERT INTO personal_access_tokens (user_id, workspace_id, name, token_prefix, token_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+tokenColumns,
+		input.UserID, input.WorkspaceID, input.Name, prefix, hash, pq.StringArray(input.Scopes), input.ExpiresAt); err != nil {
+		return apitokens.CoreToken{}, err
+	}
+	return toCoreToken(row), nil
+}
+
+func (r *Repo) Update(ctx context.Context, workspaceID, userID, tokenID uuid.UUID, input apitokens.CoreUpdateToken) (apitokens.CoreToken, error) {
+	var scopes any
+	if input.Scopes != nil {
+		scopes = pq.StringArray(input.Scopes)
+	}
+	var row tokenRow
+	if err := r.db.GetContext(ctx, &row, `
+		UPDATE personal_access_tokens
+		SET name = COALESCE($4, name), scopes = COALESCE($5, scopes), updated_at = NOW()
+		WHERE workspace_id = $1 AND user_id = $2 AND token_id = $3 AND revoked_at IS NULL
+		RETURNING `+tokenColumns,
+		workspaceID, userID, tokenID, input.Name, scopes); err != nil {
+		return apitokens.CoreToken{}, err
+	}
+	return toCoreToken(row), nil
+}
+
+func (r *Repo) Revoke(ctx context.Context, workspaceID, userID, tokenID uuid.UUID) error {
+	var id uuid.UUID
+	return r.db.GetContext(ctx, &id, `
+		UPDATE personal_access_tokens
+		SET revoked_at = NOW(), updated_at = NOW()
+		WHERE workspace_id = $1 AND user_id = $2 AND token_id = $3 AND revoked_at IS NULL
+		RETURNING token_id
+	`, workspaceID, userID, tokenID)
+}
+
+// GetActiveByHash only returns tokens whose owner is still a member of the token's workspace.
+func (r *Repo) GetActiveByHash(ctx context.Context, hash string) (apitokens.CoreResolvedToken, error) {
+	var row resolvedTokenRow
+	if err := r.db.GetContext(ctx, &row, `
+		SELECT t.token_id, t.user_id, t.workspace_id, t.name, t.token_prefix, t.scopes, t.expires_at,
+			t.last_used_at, t.revoked_at, t.created_at, t.updated_at, w.slug AS workspace_slug
+		FROM personal_access_tokens t
+		INNER JOIN workspaces w ON w.workspace_id = t.workspace_id
+		INNER JOIN workspace_members wm ON wm.workspace_id = t.workspace_id AND wm.user_id = t.user_id
+		WHERE t.token_hash = $1 AND t.revoked_at IS NULL
+	`, hash); err != nil {
+		return apitokens.CoreResolvedToken{}, err
+	}
+	return apitokens.CoreResolvedToken{CoreToken: toCoreToken(row.tokenRow), WorkspaceSlug: row.WorkspaceSlug}, nil
+}
+
+// TouchLastUsed records token usage at most once a minute to avoid a write per request.
+func (r *Repo) TouchLastUsed(ctx context.Context, tokenID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE personal_access_tokens
+		SET last_used_at = NOW()
+		WHERE token_id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+	`, tokenID)
+	return err
+}
+
+func toCoreToken(row tokenRow) apitokens.CoreToken {
+	return apitokens.CoreToken{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		WorkspaceID: row.WorkspaceID,
+		Name:        row.Name,
+		Prefix:      row.Prefix,
+		Scopes:      []string(row.Scopes),
+		ExpiresAt:   row.ExpiresAt,
+		LastUsedAt:  row.LastUsedAt,
+		RevokedAt:   row.RevokedAt,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
@@ -0,0 +1,161 @@
+package apitokens
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/complexus-tech/projects-api/internal/platform/auth"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidInput = errors.New("invalid api token input")
+	ErrNotFound     = sql.ErrNoRows
+	ErrInvalidToken = errors.New("api token is invalid or expired")
+)
+
+const (
+	tokenBytes    = 32
+	displayLength = len(auth.APITokenPrefix) + 6
+	maxNameLength = 100
+)
+
+type Service struct {
+	repo Repository
+	log  *logger.Logger
+	now  func() time.Time
+}
+
+func New(log *logger.Logger, repo Repository) *Service {
+	return &Service{repo: repo, log: log, now: time.Now}
+}
+
+func (s *Service) List(ctx context.Context, workspaceID, userID uuid.UUID) ([]CoreToken, error) {
+	return s.repo.List(ctx, workspaceID, userID)
+}
+
+func (s *Service) Create(ctx context.Context, input CoreNewToken) (CoreCreatedToken, error) {
+	name, err := normalizeName(input.Name)
+	if err != nil {
+		return CoreCreatedToken{}, err
+	}
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return CoreCreatedToken{}, err
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(s.now()) {
+		return CoreCreatedToken{}, fmt.Errorf("%w: expiry must be in the future", ErrInvalidInput)
+	}
+	input.Name = name
+	input.Scopes = scopes
+
+	plaintext, err := generateToken()
+	if err != nil {
+		return CoreCreatedToken{}, err
+	}
+	token, err := s.repo.Create(ctx, input, plaintext[:displayLength], hashToken(plaintext))
+	if err != nil {
+		return CoreCreatedToken{}, err
+	}
+	return CoreCreatedToken{Token: token, Plaintext: plaintext}, nil
+}
+
+func (s *Service) Update(ctx context.Context, workspaceID, userID, tokenID uuid.UUID, input CoreUpdateToken) (CoreToken, error) {
+	if input.Name != nil {
+		name, err := normalizeName(*input.Name)
+		if err != nil {
+			return CoreToken{}, err
+		}
+		input.Name = &name
+	}
+	if input.Scopes != nil {
+		scopes, err := normalizeScopes(input.Scopes)
+		if err != nil {
+			return CoreToken{}, err
+		}
+		input.Scopes = scopes
+	}
+	return s.repo.Update(ctx, workspaceID, userID, tokenID, input)
+}
+
+func (s *Service) Revoke(ctx context.Context, workspaceID, userID, tokenID uuid.UUID) error {
+	return s.repo.Revoke(ctx, workspaceID, userID, tokenID)
+}
+
+// ResolveAPIToken looks up an active token by its plaintext value and records its use.
+func (s *Service) ResolveAPIToken(ctx context.Context, plaintext string) (auth.APIToken, error) {
+	if !strings.HasPrefix(plaintext, auth.APITokenPrefix) {
+		return auth.APIToken{}, ErrInvalidToken
+	}
+	token, err := s.repo.GetActiveByHash(ctx, hashToken(plaintext))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return auth.APIToken{}, ErrInvalidToken
+		}
+		return auth.APIToken{}, err
+	}
+	if token.RevokedAt != nil || (token.ExpiresAt != nil && !token.ExpiresAt.After(s.now())) {
+		return auth.APIToken{}, ErrInvalidToken
+	}
+
+	if err := s.repo.TouchLastUsed(ctx, token.ID); err != nil && s.log != nil {
+		s.log.Error(ctx, "failed to record api token usage", "error", err, "token_id", token.ID)
+	}
+
+	return auth.APIToken{
+		ID:            token.ID,
+		UserID:        token.UserID,
+		WorkspaceID:   token.WorkspaceID,
+		WorkspaceSlug: token.WorkspaceSlug,
+		Scopes:        token.Scopes,
+	}, nil
+}
+
+func normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLength {
+		return "", fmt.Errorf("%w: name must be between 1 and %d characters", ErrInvalidInput, maxNameLength)
+	}
+	return name, nil
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidInput)
+	}
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !auth.IsValidScope(scope) {
+			return nil, fmt.Errorf("%w: unknown scope %q", ErrInvalidInput, scope)
+		}
+		if !slices.Contains(result, scope) {
+			result = append(result, scope)
+		}
+	}
+	slices.Sort(result)
+	return result, nil
+}
+
+func generateToken() (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating api token: %w", err)
+	}
+	return auth.APITokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
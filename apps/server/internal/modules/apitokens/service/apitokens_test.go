@@ -0,0 +1,161 @@
+package apitokens
+
+import (
+	"context"
+	"database/sql"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/complexus-tech/projects-api/internal/platform/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type repoStub struct {
+	tokens  map[string]CoreResolvedToken
+	touched []uuid.UUID
+}
+
+func newRepoStub() *repoStub {
+	return &repoStub{tokens: map[string]CoreResolvedToken{}}
+}
+
+func (r *repoStub) List(ctx context.Context, workspaceID, userID uuid.UUID) ([]CoreToken, error) {
+	return nil, nil
+}
+
+func (r *repoStub) Get(ctx context.Context, workspaceID, userID, tokenID uuid.UUID) (CoreToken, error) {
+	return CoreToken{}, sql.ErrNoRows
+}
+
+func (r *repoStub) Create(ctx context.Context, input CoreNewToken, prefix, hash string) (CoreToken, error) {
+	token := CoreToken{
+		ID:          uuid.New(),
+		UserID:      input.UserID,
+		WorkspaceID: input.WorkspaceID,
+		Name:        input.Name,
+		Prefix:      prefix,
+		Scopes:      input.Scopes,
+		ExpiresAt:   input.ExpiresAt,
+	}
+	r.tokens[hash] = CoreResolvedToken{CoreToken: token, WorkspaceSlug: "acme"}
+	return token, nil
+}
+
+func (r *repoStub) Update(ctx context.Context, workspaceID, userID, tokenID uuid.UUID, input CoreUpdateToken) (CoreToken, error) {
+	return CoreToken{}, sql.ErrNoRows
+}
+
+func (r *repoStub) Revoke(ctx context.Context, workspaceID, userID, tokenID uuid.UUID) error {
+	now := time.Now()
+	for hash, token := range r.tokens {
+		if token.ID == tokenID {
+			token.RevokedAt = &now
+			r.tokens[hash] = token
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *repoStub) GetActiveByHash(ctx context.Context, hash string) (CoreResolvedToken, error) {
+	token, ok := r.tokens[hash]
+	if !ok {
+		return CoreResolvedToken{}, sql.ErrNoRows
+	}
+	return token, nil
+}
+
+func (r *repoStub) TouchLastUsed(ctx context.Context, tokenID uuid.UUID) error {
+	r.touched = append(r.touched, tokenID)
+	return nil
+}
+
+func TestCreateStoresHashAndResolvesPlaintext(t *testing.T) {
+	repo := newRepoStub()
+	service := New(nil, repo)
+
+	created, err := service.Create(context.Background(), CoreNewToken{
+		UserID:      uuid.New(),
+		WorkspaceID: uuid.New(),
+		Name:        "  CI pipeline ",
+		Scopes:      []string{"stories:write", "Sprints:read", "stories:write"},
+	})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(created.Plaintext, auth.APITokenPrefix))
+	require.Equal(t, "CI pipeline", created.Token.Name)
+	require.Equal(t, []string{"sprints:read", "stories:write"}, created.Token.Scopes)
+	require.True(t, strings.HasPrefix(created.Plaintext, created.Token.Prefix))
+	require.NotContains(t, repo.tokens, created.Plaintext)
+
+	resolved, err := service.ResolveAPIToken(context.Background(), created.Plaintext)
+	require.NoError(t, err)
+	require.Equal(t, created.Token.UserID, resolved.UserID)
+	require.Equal(t, "acme", resolved.WorkspaceSlug)
+	require.Equal(t, []uuid.UUID{created.Token.ID}, repo.touched)
+}
+
+func TestCreateRejectsInvalidInput(t *testing.T) {
+	service := New(nil, newRepoStub())
+	past := time.Now().Add(-time.Hour)
+
+	cases := []CoreNewToken{
+		{Name: "", Scopes: []string{"stories:read"}},
+		{Name: "token", Scopes: nil},
+		{Name: "token", Scopes: []string{"documents:read"}},
+		{Name: "token", Scopes: []string{"stories:admin"}},
+		{Name: "token", Scopes: []string{"stories:read"}, ExpiresAt: &past},
+	}
+	for _, input := range cases {
+		_, err := service.Create(context.Background(), input)
+		require.ErrorIs(t, err, ErrInvalidInput)
+	}
+}
+
+func TestResolveRejectsRevokedExpiredAndUnknownTokens(t *testing.T) {
+	repo := newRepoStub()
+	service := New(nil, repo)
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, CoreNewToken{Name: "revoked", Scopes: []string{"stories:read"}})
+	require.NoError(t, err)
+	require.NoError(t, service.Revoke(ctx, uuid.Nil, uuid.Nil, created.Token.ID))
+	_, err = service.ResolveAPIToken(ctx, created.Plaintext)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	expiresAt := time.Now().Add(time.Hour)
+	expiring, err := service.Create(ctx, CoreNewToken{Name: "expiring", Scopes: []string{"stories:read"}, ExpiresAt: &expiresAt})
+	require.NoError(t, err)
+	service.now = func() time.Time { return expiresAt.Add(time.Second) }
+	_, err = service.ResolveAPIToken(ctx, expiring.Plaintext)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = service.ResolveAPIToken(ctx, auth.APITokenPrefix+"unknown")
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestTokenScopesAuthorizeRequests(t *testing.T) {
+	token := auth.APIToken{WorkspaceSlug: "acme", Scopes: []string{"stories:write", "sprints:read"}}
+
+	cases := []struct {
+		method string
+		path   string
+		want   bool
+	}{
+		{"GET", "/workspaces/acme/stories", true},
+		{"PUT", "/workspaces/acme/stories/123", true},
+		{"GET", "/workspaces/acme/story-by-ref/ENG-1", true},
+		{"GET", "/workspaces/acme/sprints", true},
+		{"POST", "/workspaces/acme/sprints", false},
+		{"GET", "/workspaces/acme/objectives", false},
+		{"GET", "/workspaces/other/stories", false},
+		{"GET", "/workspaces/acme/api-tokens", false},
+		{"GET", "/me", false},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(tc.method, tc.path, nil)
+		require.Equal(t, tc.want, token.AuthorizeRequest(r), "%s %s", tc.method, tc.path)
+	}
+}
@@ -0,0 +1,57 @@
+package apitokens
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CoreToken struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	WorkspaceID uuid.UUID
+	Name        string
+	Prefix      string
+	Scopes      []string
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CoreCreatedToken carries the plaintext token, which is only available at creation time.
+type CoreCreatedToken struct {
+	Token     CoreToken
+	Plaintext string
+}
+
+type CoreNewToken struct {
+	UserID      uuid.UUID
+	WorkspaceID uuid.UUID
+	Name        string
+	Scopes      []string
+	ExpiresAt   *time.Time
+}
+
+type CoreUpdateToken struct {
+	Name   *string
+	Scopes []string
+}
+
+// CoreResolvedToken is a token joined with its workspace slug, used to authenticate requests.
+type CoreResolvedToken struct {
+	CoreToken
+	WorkspaceSlug string
+}
+
+type Repository interface {
+	List(ctx context.Context, workspaceID, userID uuid.UUID) ([]CoreToken, error)
+	Get(ctx context.Context, workspaceID, userID, tokenID uuid.UUID) (CoreToken, error)
+	Create(ctx context.Context, input CoreNewToken, prefix, hash string) (CoreToken, error)
+	Update(ctx context.Context, workspaceID, userID, tokenID uuid.UUID, input CoreUpdateToken) (CoreToken, error)
+	Revoke(ctx context.Context, workspaceID, userID, tokenID uuid.UUID) error
+	GetActiveByHash(ctx context.Context, hash string) (CoreResolvedToken, error)
+	TouchLastUsed(ctx context.Context, tokenID uuid.UUID) error
+}
@@ -2,6 +2,7 @@ package commentshttp
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
@@ -29,6 +30,11 @@ func (h *Handlers) UpdateComment(ctx context.Context, w http.ResponseWriter, r *
 		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
 	}
 
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
 	commentIDParam := web.Params(r, "id")
 	commentID, err := uuid.Parse(commentIDParam)
 	if err != nil {
@@ -42,8 +48,8 @@ func (h *Handlers) UpdateComment(ctx context.Context, w http.ResponseWriter, r *
 		return nil
 	}
 
-	if err := h.comments.UpdateComment(ctx, commentID, uc.Content, uc.Mentions); err != nil {
-		web.RespondError(ctx, w, err, http.StatusInternalServerError)
+	if err := h.comments.UpdateComment(ctx, commentID, userID, uc.Content, uc.Mentions); err != nil {
+		web.RespondError(ctx, w, err, commentStatus(err))
 		return nil
 	}
 
@@ -70,3 +76,77 @@ func (h *Handlers) DeleteComment(ctx context.Context, w http.ResponseWriter, r *
 
 	return web.Respond(ctx, w, nil, http.StatusNoContent)
 }
+
+// ListRevisions returns a comment's edit history, newest first.
+func (h *Handlers) ListRevisions(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	commentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidCommentID, http.StatusBadRequest)
+		return nil
+	}
+
+	revisions, err := h.comments.Revisions(ctx, commentID, workspace.ID)
+	if err != nil {
+		web.RespondError(ctx, w, err, commentStatus(err))
+		return nil
+	}
+
+	return web.Respond(ctx, w, toAppCommentRevisions(revisions), http.StatusOK)
+}
+
+// Resolve marks a top-level comment's thread as resolved.
+func (h *Handlers) Resolve(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.setResolved(ctx, w, r, true)
+}
+
+// Unresolve reopens a resolved thread.
+func (h *Handlers) Unresolve(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.setResolved(ctx, w, r, false)
+}
+
+func (h *Handlers) setResolved(ctx context.Context, w http.ResponseWriter, r *http.Request, resolved bool) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	commentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidCommentID, http.StatusBadRequest)
+		return nil
+	}
+
+	var comment comments.CoreComment
+	if resolved {
+		comment, err = h.comments.Resolve(ctx, commentID, workspace.ID, userID)
+	} else {
+		comment, err = h.comments.Unresolve(ctx, commentID, workspace.ID)
+	}
+	if err != nil {
+		web.RespondError(ctx, w, err, commentStatus(err))
+		return nil
+	}
+
+	return web.Respond(ctx, w, toAppCommentResolution(comment), http.StatusOK)
+}
+
+func commentStatus(err error) int {
+	switch {
+	case errors.Is(err, comments.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, comments.ErrNotTopLevel):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
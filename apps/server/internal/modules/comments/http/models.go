@@ -2,7 +2,9 @@ package commentshttp
 
 import (
 	"errors"
+	"time"
 
+	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
 	"github.com/google/uuid"
 )
 
@@ -14,3 +16,46 @@ type UpdateComment struct {
 var (
 	ErrInvalidCommentID = errors.New("comment id is not in its proper form")
 )
+
+// AppCommentRevision is a saved version of a comment. ID is null for the
+// original content of a comment that was never edited.
+type AppCommentRevision struct {
+	ID         *uuid.UUID `json:"id"`
+	Content    string     `json:"content"`
+	EditedByID *uuid.UUID `json:"editedById"`
+	EditedAt   time.Time  `json:"editedAt"`
+}
+
+// AppCommentResolution is the resolution state of a thread.
+type AppCommentResolution struct {
+	ID           uuid.UUID  `json:"id"`
+	Resolved     bool       `json:"resolved"`
+	ResolvedAt   *time.Time `json:"resolvedAt"`
+	ResolvedByID *uuid.UUID `json:"resolvedById"`
+}
+
+func toAppCommentRevisions(revisions []comments.CoreCommentRevision) []AppCommentRevision {
+	appRevisions := make([]AppCommentRevision, len(revisions))
+	for i, revision := range revisions {
+		var id *uuid.UUID
+		if revision.ID != uuid.Nil {
+			id = &revision.ID
+		}
+		appRevisions[i] = AppCommentRevision{
+			ID:         id,
+			Content:    revision.Content,
+			EditedByID: revision.EditedBy,
+			EditedAt:   revision.EditedAt,
+		}
+	}
+	return appRevisions
+}
+
+func toAppCommentResolution(comment comments.CoreComment) AppCommentResolution {
+	return AppCommentResolution{
+		ID:           comment.ID,
+		Resolved:     comment.IsResolved(),
+		ResolvedAt:   comment.ResolvedAt,
+		ResolvedByID: comment.ResolvedBy,
+	}
+}
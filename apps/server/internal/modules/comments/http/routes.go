@@ -25,4 +25,7 @@ func Routes(cfg Config, app *web.App) {
 
 	app.Put("/workspaces/{workspaceSlug}/comments/{id}", h.UpdateComment, auth, workspace)
 	app.Delete("/workspaces/{workspaceSlug}/comments/{id}", h.DeleteComment, auth, workspace)
+	app.Get("/workspaces/{workspaceSlug}/comments/{id}/revisions", h.ListRevisions, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/comments/{id}/resolve", h.Resolve, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/comments/{id}/unresolve", h.Unresolve, auth, workspace)
 }
@@ -2,50 +2,133 @@ package commentsrepository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
+	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
 	"github.com/complexus-tech/projects-api/pkg/web"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
-func (r *repo) UpdateComment(ctx context.Context, commentID uuid.UUID, comment string) error {
+// UpdateComment saves new content as a revision and marks the comment as
+// edited. The first edit also saves the original content, and edits that do
+// not change the content are ignored.
+func (r *repo) UpdateComment(ctx context.Context, commentID, editorID uuid.UUID, comment string) error {
 	ctx, span := web.AddSpan(ctx, "business.repository.comments.UpdateComment")
 	defer span.End()
 
 	span.SetAttributes(attribute.String("commentId", commentID.String()))
 
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var current struct {
+		Content      string    `db:"content"`
+		CommenterID  uuid.UUID `db:"commenter_id"`
+		CreatedAt    time.Time `db:"created_at"`
+		HasRevisions bool      `db:"has_revisions"`
+	}
 	query := `
-		UPDATE story_comments
-		SET
-			content = :comment,
-			updated_at = NOW()
-		WHERE comment_id = :comment_id
+		SELECT
+			content, commenter_id, created_at,
+			EXISTS (SELECT 1 FROM comment_revisions WHERE comment_id = $1) AS has_revisions
+		FROM story_comments
+		WHERE comment_id = $1
+		FOR UPDATE
 	`
+	if err := tx.GetContext(ctx, &current, query, commentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return comments.ErrNotFound
+		}
+		r.log.Error(ctx, "error loading comment", err)
+		return err
+	}
+	if current.Content == comment {
+		return nil
+	}
 
-	params := map[string]interface{}{
-		"comment_id": commentID,
-		"comment":    comment,
+	if !current.HasRevisions {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO comment_revisions (comment_id, content, edited_by, edited_at)
+			VALUES ($1, $2, $3, $4)`,
+			commentID, current.Content, current.CommenterID, current.CreatedAt); err != nil {
+			r.log.Error(ctx, "error saving original comment revision", err)
+			return err
+		}
 	}
 
-	stmt, err := r.db.PrepareNamedContext(ctx, query)
-	if err != nil {
-		r.log.Error(ctx, "error preparing statement", err)
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE story_comments
+		SET
+			content = $2,
+			updated_at = NOW(),
+			edited_at = NOW(),
+			edited_by = $3
+		WHERE comment_id = $1`,
+		commentID, comment, editorID); err != nil {
+		r.log.Error(ctx, "error updating comment", err)
 		return err
 	}
-	defer stmt.Close()
 
-	if _, err := stmt.ExecContext(ctx, params); err != nil {
-		r.log.Error(ctx, "error updating comment", err)
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO comment_revisions (comment_id, content, edited_by)
+		VALUES ($1, $2, $3)`,
+		commentID, comment, editorID); err != nil {
+		r.log.Error(ctx, "error saving comment revision", err)
 		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	r.log.Info(ctx, "comment updated successfully", "commentId", commentID)
 
 	return nil
 }
 
+// SetResolved resolves a comment's thread when resolvedBy is set and reopens
+// it otherwise.
+func (r *repo) SetResolved(ctx context.Context, commentID uuid.UUID, resolvedBy *uuid.UUID) (comments.CoreComment, error) {
+	ctx, span := web.AddSpan(ctx, "business.repository.comments.SetResolved")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("commentId", commentID.String()))
+
+	query := `
+		UPDATE story_comments
+		SET
+			resolved_by = $2,
+			resolved_at = CASE WHEN $2::uuid IS NULL THEN NULL ELSE NOW() END
+		WHERE comment_id = $1
+		RETURNING comment_id, story_id, commenter_id, content, parent_id, created_at, updated_at,
+			edited_at, edited_by, resolved_at, resolved_by
+	`
+
+	var dbComment DbComment
+	if err := r.db.GetContext(ctx, &dbComment, query, commentID, resolvedBy); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return comments.CoreComment{}, comments.ErrNotFound
+		}
+		r.log.Error(ctx, "error resolving comment", err)
+		return comments.CoreComment{}, err
+	}
+
+	span.AddEvent("Comment resolution changed.", trace.WithAttributes(
+		attribute.String("comment.id", commentID.String()),
+		attribute.Bool("comment.resolved", resolvedBy != nil),
+	))
+
+	return toCoreComment(dbComment), nil
+}
+
 func (r *repo) DeleteComment(ctx context.Context, commentID uuid.UUID) error {
 	ctx, span := web.AddSpan(ctx, "business.repository.comments.DeleteComment")
 	defer span.End()
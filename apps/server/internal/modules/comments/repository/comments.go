@@ -28,6 +28,24 @@ func toCoreComment(dbComment DbComment) comments.CoreComment {
 		Comment:     dbComment.Comment,
 		CreatedAt:   dbComment.CreatedAt,
 		UpdatedAt:   dbComment.UpdatedAt,
+		EditedAt:    dbComment.EditedAt,
+		EditedBy:    dbComment.EditedBy,
+		ResolvedAt:  dbComment.ResolvedAt,
+		ResolvedBy:  dbComment.ResolvedBy,
 		SubComments: []comments.CoreComment{}, // Empty for single comment fetch
 	}
 }
+
+func toCoreCommentRevisions(rows []dbCommentRevision) []comments.CoreCommentRevision {
+	revisions := make([]comments.CoreCommentRevision, len(rows))
+	for i, row := range rows {
+		revisions[i] = comments.CoreCommentRevision{
+			ID:        row.ID,
+			CommentID: row.CommentID,
+			Content:   row.Content,
+			EditedBy:  row.EditedBy,
+			EditedAt:  row.EditedAt,
+		}
+	}
+	return revisions
+}
@@ -16,13 +16,27 @@ type DbComment struct {
 	Comment     string           `db:"content"`
 	CreatedAt   time.Time        `db:"created_at"`
 	UpdatedAt   time.Time        `db:"updated_at"`
+	EditedAt    *time.Time       `db:"edited_at"`
+	EditedBy    *uuid.UUID       `db:"edited_by"`
+	ResolvedAt  *time.Time       `db:"resolved_at"`
+	ResolvedBy  *uuid.UUID       `db:"resolved_by"`
 	SubComments *json.RawMessage `db:"sub_comments"`
+	Reactions   *json.RawMessage `db:"reactions"`
 }
 
 type DbNewComment struct {
+	ID       uuid.UUID   `db:"id"`
 	StoryID  uuid.UUID   `db:"story_id"`
 	Parent   *uuid.UUID  `db:"parent_id"`
 	UserID   uuid.UUID   `db:"commenter_id"`
 	Comment  string      `db:"content"`
 	Mentions []uuid.UUID // New field (not mapped to DB, handled separately)
 }
+
+type dbCommentRevision struct {
+	ID        uuid.UUID  `db:"revision_id"`
+	CommentID uuid.UUID  `db:"comment_id"`
+	Content   string     `db:"content"`
+	EditedBy  *uuid.UUID `db:"edited_by"`
+	EditedAt  time.Time  `db:"edited_at"`
+}
@@ -2,6 +2,8 @@ package commentsrepository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
 	"github.com/complexus-tech/projects-api/pkg/web"
@@ -17,7 +19,8 @@ func (r *repo) GetComment(ctx context.Context, commentID uuid.UUID) (comments.Co
 
 	query := `
 		SELECT comment_id, story_id, commenter_id,
-		content, parent_id, created_at, updated_at
+		content, parent_id, created_at, updated_at,
+		edited_at, edited_by, resolved_at, resolved_by
 		FROM story_comments 
 		WHERE comment_id = :comment_id
 	`
@@ -41,3 +44,54 @@ func (r *repo) GetComment(ctx context.Context, commentID uuid.UUID) (comments.Co
 
 	return toCoreComment(dbComment), nil
 }
+
+// GetWorkspaceComment returns a comment on a story in the workspace.
+func (r *repo) GetWorkspaceComment(ctx context.Context, commentID, workspaceID uuid.UUID) (comments.CoreComment, error) {
+	ctx, span := web.AddSpan(ctx, "business.repository.comments.GetWorkspaceComment")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("commentId", commentID.String()))
+
+	query := `
+		SELECT sc.comment_id, sc.story_id, sc.commenter_id,
+		sc.content, sc.parent_id, sc.created_at, sc.updated_at,
+		sc.edited_at, sc.edited_by, sc.resolved_at, sc.resolved_by
+		FROM story_comments sc
+		INNER JOIN stories s ON s.id = sc.story_id
+		WHERE sc.comment_id = $1 AND s.workspace_id = $2
+	`
+
+	var dbComment DbComment
+	if err := r.db.GetContext(ctx, &dbComment, query, commentID, workspaceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return comments.CoreComment{}, comments.ErrNotFound
+		}
+		r.log.Error(ctx, "error getting comment", err)
+		return comments.CoreComment{}, err
+	}
+
+	return toCoreComment(dbComment), nil
+}
+
+// ListRevisions returns a comment's saved versions, newest first.
+func (r *repo) ListRevisions(ctx context.Context, commentID uuid.UUID) ([]comments.CoreCommentRevision, error) {
+	ctx, span := web.AddSpan(ctx, "business.repository.comments.ListRevisions")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("commentId", commentID.String()))
+
+	query := `
+		SELECT revision_id, comment_id, content, edited_by, edited_at
+		FROM comment_revisions
+		WHERE comment_id = $1
+		ORDER BY edited_at DESC
+	`
+
+	var rows []dbCommentRevision
+	if err := r.db.SelectContext(ctx, &rows, query, commentID); err != nil {
+		r.log.Error(ctx, "error listing comment revisions", err)
+		return nil, err
+	}
+
+	return toCoreCommentRevisions(rows), nil
+}
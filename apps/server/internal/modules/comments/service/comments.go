@@ -2,16 +2,27 @@ package comments
 
 import (
 	"context"
+	"errors"
 
 	"github.com/complexus-tech/projects-api/pkg/logger"
 	"github.com/complexus-tech/projects-api/pkg/web"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrNotFound    = errors.New("comment not found")
+	ErrNotTopLevel = errors.New("only top-level comments can be resolved")
 )
 
 type Repository interface {
-	UpdateComment(ctx context.Context, commentID uuid.UUID, comment string) error
+	UpdateComment(ctx context.Context, commentID, editorID uuid.UUID, comment string) error
 	DeleteComment(ctx context.Context, commentID uuid.UUID) error
 	GetComment(ctx context.Context, commentID uuid.UUID) (CoreComment, error)
+	GetWorkspaceComment(ctx context.Context, commentID, workspaceID uuid.UUID) (CoreComment, error)
+	ListRevisions(ctx context.Context, commentID uuid.UUID) ([]CoreCommentRevision, error)
+	SetResolved(ctx context.Context, commentID uuid.UUID, resolvedBy *uuid.UUID) (CoreComment, error)
 }
 
 // MentionsRepository provides access to comment mentions storage.
@@ -35,12 +46,14 @@ func New(log *logger.Logger, repo Repository, mentionsRepo MentionsRepository) *
 	}
 }
 
-func (s *Service) UpdateComment(ctx context.Context, commentID uuid.UUID, comment string, mentions []uuid.UUID) error {
+// UpdateComment changes a comment's content and mentions. Edits that change
+// the content are kept as revisions and mark the comment as edited.
+func (s *Service) UpdateComment(ctx context.Context, commentID, editorID uuid.UUID, comment string, mentions []uuid.UUID) error {
 	ctx, span := web.AddSpan(ctx, "business.service.comments.UpdateComment")
 	defer span.End()
 
 	// Update the comment content
-	if err := s.repo.UpdateComment(ctx, commentID, comment); err != nil {
+	if err := s.repo.UpdateComment(ctx, commentID, editorID, comment); err != nil {
 		return err
 	}
 
@@ -70,3 +83,71 @@ func (s *Service) GetComment(ctx context.Context, commentID uuid.UUID) (CoreComm
 
 	return s.repo.GetComment(ctx, commentID)
 }
+
+// Revisions returns a comment's versions, newest first. A comment that was
+// never edited has its original content as the only version.
+func (s *Service) Revisions(ctx context.Context, commentID, workspaceID uuid.UUID) ([]CoreCommentRevision, error) {
+	s.log.Info(ctx, "business.core.comments.Revisions")
+	ctx, span := web.AddSpan(ctx, "business.service.comments.Revisions")
+	defer span.End()
+
+	comment, err := s.repo.GetWorkspaceComment(ctx, commentID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	revisions, err := s.repo.ListRevisions(ctx, commentID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		authorID := comment.UserID
+		revisions = []CoreCommentRevision{{
+			CommentID: comment.ID,
+			Content:   comment.Comment,
+			EditedBy:  &authorID,
+			EditedAt:  comment.CreatedAt,
+		}}
+	}
+
+	span.AddEvent("comment revisions retrieved", trace.WithAttributes(
+		attribute.Int("revisions.count", len(revisions)),
+	))
+	return revisions, nil
+}
+
+// Resolve marks a top-level comment's thread as resolved. Resolving a
+// resolved thread keeps the original resolver.
+func (s *Service) Resolve(ctx context.Context, commentID, workspaceID, userID uuid.UUID) (CoreComment, error) {
+	s.log.Info(ctx, "business.core.comments.Resolve")
+	ctx, span := web.AddSpan(ctx, "business.service.comments.Resolve")
+	defer span.End()
+
+	return s.setResolved(ctx, commentID, workspaceID, &userID)
+}
+
+// Unresolve reopens a resolved thread.
+func (s *Service) Unresolve(ctx context.Context, commentID, workspaceID uuid.UUID) (CoreComment, error) {
+	s.log.Info(ctx, "business.core.comments.Unresolve")
+	ctx, span := web.AddSpan(ctx, "business.service.comments.Unresolve")
+	defer span.End()
+
+	return s.setResolved(ctx, commentID, workspaceID, nil)
+}
+
+func (s *Service) setResolved(ctx context.Context, commentID, workspaceID uuid.UUID, resolvedBy *uuid.UUID) (CoreComment, error) {
+	comment, err := s.repo.GetWorkspaceComment(ctx, commentID, workspaceID)
+	if err != nil {
+		return CoreComment{}, err
+	}
+	if comment.Parent != nil {
+		return CoreComment{}, ErrNotTopLevel
+	}
+	if comment.IsResolved() == (resolvedBy != nil) {
+		return comment, nil
+	}
+
+	return s.repo.SetResolved(ctx, commentID, resolvedBy)
+}
@@ -0,0 +1,188 @@
+package comments
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/google/uuid"
+)
+
+type commentsRepo struct {
+	Repository
+
+	comment     CoreComment
+	revisions   []CoreCommentRevision
+	edits       []string
+	editorID    uuid.UUID
+	resolveSets int
+}
+
+func (r *commentsRepo) GetWorkspaceComment(ctx context.Context, commentID, workspaceID uuid.UUID) (CoreComment, error) {
+	if r.comment.ID != commentID {
+		return CoreComment{}, ErrNotFound
+	}
+	return r.comment, nil
+}
+
+func (r *commentsRepo) UpdateComment(ctx context.Context, commentID, editorID uuid.UUID, comment string) error {
+	r.edits = append(r.edits, comment)
+	r.editorID = editorID
+	return nil
+}
+
+func (r *commentsRepo) ListRevisions(ctx context.Context, commentID uuid.UUID) ([]CoreCommentRevision, error) {
+	return r.revisions, nil
+}
+
+func (r *commentsRepo) SetResolved(ctx context.Context, commentID uuid.UUID, resolvedBy *uuid.UUID) (CoreComment, error) {
+	r.resolveSets++
+	r.comment.ResolvedBy = resolvedBy
+	r.comment.ResolvedAt = nil
+	if resolvedBy != nil {
+		now := time.Now()
+		r.comment.ResolvedAt = &now
+	}
+	return r.comment, nil
+}
+
+type mentionsRepo struct {
+	MentionsRepository
+
+	saved []uuid.UUID
+}
+
+func (r *mentionsRepo) SaveMentions(ctx context.Context, commentID uuid.UUID, userIDs []uuid.UUID) error {
+	r.saved = userIDs
+	return nil
+}
+
+func newCommentsService(repo *commentsRepo, mentions *mentionsRepo) *Service {
+	return New(logger.NewWithText(io.Discard, slog.LevelError, "test"), repo, mentions)
+}
+
+func TestUpdateCommentPassesEditor(t *testing.T) {
+	repo := &commentsRepo{}
+	mentions := &mentionsRepo{}
+	service := newCommentsService(repo, mentions)
+	editorID := uuid.New()
+	mentioned := []uuid.UUID{uuid.New()}
+
+	if err := service.UpdateComment(context.Background(), uuid.New(), editorID, "Edited", mentioned); err != nil {
+		t.Fatalf("expected comment to update, got error: %v", err)
+	}
+	if len(repo.edits) != 1 || repo.edits[0] != "Edited" {
+		t.Fatalf("expected the new content to be written, got %v", repo.edits)
+	}
+	if repo.editorID != editorID {
+		t.Fatalf("expected editor %s to be recorded, got %s", editorID, repo.editorID)
+	}
+	if len(mentions.saved) != 1 || mentions.saved[0] != mentioned[0] {
+		t.Fatalf("expected mentions to be saved, got %v", mentions.saved)
+	}
+}
+
+func TestRevisionsReturnsSavedVersions(t *testing.T) {
+	commentID := uuid.New()
+	editorID := uuid.New()
+	revisions := []CoreCommentRevision{
+		{ID: uuid.New(), CommentID: commentID, Content: "Second", EditedBy: &editorID},
+		{ID: uuid.New(), CommentID: commentID, Content: "First", EditedBy: &editorID},
+	}
+	repo := &commentsRepo{comment: CoreComment{ID: commentID, Comment: "Second"}, revisions: revisions}
+
+	got, err := newCommentsService(repo, &mentionsRepo{}).Revisions(context.Background(), commentID, uuid.New())
+	if err != nil {
+		t.Fatalf("expected revisions, got error: %v", err)
+	}
+	if len(got) != 2 || got[0].Content != "Second" || got[1].Content != "First" {
+		t.Fatalf("expected the saved versions newest first, got %+v", got)
+	}
+}
+
+func TestRevisionsOfUneditedCommentIsTheOriginal(t *testing.T) {
+	authorID := uuid.New()
+	createdAt := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	comment := CoreComment{ID: uuid.New(), UserID: authorID, Comment: "Original", CreatedAt: createdAt}
+	repo := &commentsRepo{comment: comment}
+
+	got, err := newCommentsService(repo, &mentionsRepo{}).Revisions(context.Background(), comment.ID, uuid.New())
+	if err != nil {
+		t.Fatalf("expected revisions, got error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 revision, got %d", len(got))
+	}
+	if got[0].Content != "Original" || got[0].EditedBy == nil || *got[0].EditedBy != authorID || !got[0].EditedAt.Equal(createdAt) {
+		t.Fatalf("expected the original content by its author, got %+v", got[0])
+	}
+}
+
+func TestRevisionsOfCommentInAnotherWorkspace(t *testing.T) {
+	repo := &commentsRepo{comment: CoreComment{ID: uuid.New()}}
+
+	if _, err := newCommentsService(repo, &mentionsRepo{}).Revisions(context.Background(), uuid.New(), uuid.New()); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected %v, got %v", ErrNotFound, err)
+	}
+}
+
+func TestResolveAndUnresolveThread(t *testing.T) {
+	repo := &commentsRepo{comment: CoreComment{ID: uuid.New()}}
+	service := newCommentsService(repo, &mentionsRepo{})
+	resolverID := uuid.New()
+
+	resolved, err := service.Resolve(context.Background(), repo.comment.ID, uuid.New(), resolverID)
+	if err != nil {
+		t.Fatalf("expected thread to resolve, got error: %v", err)
+	}
+	if !resolved.IsResolved() || resolved.ResolvedBy == nil || *resolved.ResolvedBy != resolverID {
+		t.Fatalf("expected thread resolved by %s, got %+v", resolverID, resolved)
+	}
+
+	unresolved, err := service.Unresolve(context.Background(), repo.comment.ID, uuid.New())
+	if err != nil {
+		t.Fatalf("expected thread to reopen, got error: %v", err)
+	}
+	if unresolved.IsResolved() || unresolved.ResolvedBy != nil {
+		t.Fatalf("expected thread to be open, got %+v", unresolved)
+	}
+	if repo.resolveSets != 2 {
+		t.Fatalf("expected 2 writes, got %d", repo.resolveSets)
+	}
+}
+
+func TestResolveKeepsOriginalResolver(t *testing.T) {
+	resolverID := uuid.New()
+	resolvedAt := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	repo := &commentsRepo{comment: CoreComment{ID: uuid.New(), ResolvedAt: &resolvedAt, ResolvedBy: &resolverID}}
+	service := newCommentsService(repo, &mentionsRepo{})
+
+	comment, err := service.Resolve(context.Background(), repo.comment.ID, uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("expected resolving again to succeed, got error: %v", err)
+	}
+	if *comment.ResolvedBy != resolverID || repo.resolveSets != 0 {
+		t.Fatalf("expected the original resolver and no write, got %s after %d writes", *comment.ResolvedBy, repo.resolveSets)
+	}
+
+	repo.comment.ResolvedAt, repo.comment.ResolvedBy = nil, nil
+	if _, err := service.Unresolve(context.Background(), repo.comment.ID, uuid.New()); err != nil || repo.resolveSets != 0 {
+		t.Fatalf("expected reopening an open thread to do nothing, got %v after %d writes", err, repo.resolveSets)
+	}
+}
+
+func TestResolveRejectsReplies(t *testing.T) {
+	parentID := uuid.New()
+	repo := &commentsRepo{comment: CoreComment{ID: uuid.New(), Parent: &parentID}}
+
+	if _, err := newCommentsService(repo, &mentionsRepo{}).Resolve(context.Background(), repo.comment.ID, uuid.New(), uuid.New()); !errors.Is(err, ErrNotTopLevel) {
+		t.Fatalf("expected %v, got %v", ErrNotTopLevel, err)
+	}
+	if repo.resolveSets != 0 {
+		t.Fatalf("expected no write, got %d", repo.resolveSets)
+	}
+}
@@ -7,12 +7,45 @@ import (
 )
 
 type CoreComment struct {
-	ID          uuid.UUID     `json:"comment_id"`
-	StoryID     uuid.UUID     `json:"story_id"`
-	Parent      *uuid.UUID    `json:"parent_id"`
-	UserID      uuid.UUID     `json:"commenter_id"`
-	Comment     string        `json:"content"`
-	CreatedAt   time.Time     `json:"created_at"`
-	UpdatedAt   time.Time     `json:"updated_at"`
-	SubComments []CoreComment `json:"sub_comments"`
+	ID          uuid.UUID      `json:"comment_id"`
+	StoryID     uuid.UUID      `json:"story_id"`
+	Parent      *uuid.UUID     `json:"parent_id"`
+	UserID      uuid.UUID      `json:"commenter_id"`
+	Comment     string         `json:"content"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	EditedAt    *time.Time     `json:"edited_at"`
+	EditedBy    *uuid.UUID     `json:"edited_by"`
+	ResolvedAt  *time.Time     `json:"resolved_at"`
+	ResolvedBy  *uuid.UUID     `json:"resolved_by"`
+	SubComments []CoreComment  `json:"sub_comments"`
+	Reactions   []CoreReaction `json:"reactions"`
+}
+
+// IsEdited reports whether the comment changed after it was posted.
+func (c CoreComment) IsEdited() bool {
+	return c.EditedAt != nil
+}
+
+// IsResolved reports whether the comment's thread is resolved.
+func (c CoreComment) IsResolved() bool {
+	return c.ResolvedAt != nil
+}
+
+// CoreCommentRevision is a saved version of a comment. EditedBy is nil when
+// the editor's account was removed.
+type CoreCommentRevision struct {
+	ID        uuid.UUID
+	CommentID uuid.UUID
+	Content   string
+	EditedBy  *uuid.UUID
+	EditedAt  time.Time
+}
+
+// CoreReaction is an emoji on a comment with the users who reacted with it,
+// in the order they reacted.
+type CoreReaction struct {
+	Emoji   string      `json:"emoji"`
+	Count   int         `json:"count"`
+	UserIDs []uuid.UUID `json:"user_ids"`
 }
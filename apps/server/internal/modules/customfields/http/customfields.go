@@ -0,0 +1,183 @@
+package customfieldshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	customfields "github.com/complexus-tech/projects-api/internal/modules/customfields/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidFieldID = errors.New("custom field id is not in its proper form")
+	ErrInvalidTeamID  = errors.New("team id is not in its proper form")
+)
+
+type Handlers struct {
+	customFields *customfields.Service
+}
+
+func New(customFields *customfields.Service) *Handlers {
+	return &Handlers{
+		customFields: customFields,
+	}
+}
+
+// List returns the workspace's custom fields. With teamId only the fields that
+// apply to that team are returned.
+func (h *Handlers) List(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "customfieldshttp.handlers.List")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	var teamID *uuid.UUID
+	if value := r.URL.Query().Get("teamId"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return web.RespondError(ctx, w, ErrInvalidTeamID, http.StatusBadRequest)
+		}
+		teamID = &id
+	}
+
+	fields, err := h.customFields.List(ctx, workspace.ID, teamID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppCustomFields(fields), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) Get(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "customfieldshttp.handlers.Get")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	fieldID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidFieldID, http.StatusBadRequest)
+	}
+
+	field, err := h.customFields.Get(ctx, fieldID, workspace.ID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppCustomField(field), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "customfieldshttp.handlers.Create")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	var nf AppNewCustomField
+	if err := web.Decode(r, &nf); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	field, err := h.customFields.Create(ctx, workspace.ID, userID, customfields.CoreNewCustomField{
+		TeamID:      nf.Team,
+		Name:        nf.Name,
+		Description: nf.Description,
+		Type:        nf.Type,
+		Options:     nf.Options,
+		Required:    nf.Required,
+		Position:    nf.Position,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppCustomField(field), http.StatusCreated)
+	return nil
+}
+
+func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "customfieldshttp.handlers.Update")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	fieldID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidFieldID, http.StatusBadRequest)
+	}
+
+	var uf AppUpdateCustomField
+	if err := web.Decode(r, &uf); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	field, err := h.customFields.Update(ctx, fieldID, workspace.ID, customfields.CoreUpdateCustomField{
+		Name:        uf.Name,
+		Description: uf.Description,
+		Options:     uf.Options,
+		Required:    uf.Required,
+		Position:    uf.Position,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppCustomField(field), http.StatusOK)
+	return nil
+}
+
+// Archive hides a field from stories. Existing values are kept.
+func (h *Handlers) Archive(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "customfieldshttp.handlers.Archive")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	fieldID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidFieldID, http.StatusBadRequest)
+	}
+
+	if err := h.customFields.Archive(ctx, fieldID, workspace.ID); err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, nil, http.StatusNoContent)
+	return nil
+}
+
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, customfields.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, customfields.ErrNameExists):
+		return http.StatusConflict
+	case errors.Is(err, customfields.ErrNameRequired),
+		errors.Is(err, customfields.ErrInvalidType),
+		errors.Is(err, customfields.ErrInvalidTeam),
+		errors.Is(err, customfields.ErrInvalidOptions):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,66 @@
+package customfieldshttp
+
+import (
+	"time"
+
+	customfields "github.com/complexus-tech/projects-api/internal/modules/customfields/service"
+	"github.com/google/uuid"
+)
+
+type AppCustomField struct {
+	ID          uuid.UUID                 `json:"id"`
+	Workspace   uuid.UUID                 `json:"workspaceId"`
+	Team        *uuid.UUID                `json:"teamId"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Type        string                    `json:"type"`
+	Options     []customfields.CoreOption `json:"options"`
+	Required    bool                      `json:"required"`
+	Position    int                       `json:"position"`
+	CreatedBy   *uuid.UUID                `json:"createdBy"`
+	CreatedAt   time.Time                 `json:"createdAt"`
+	UpdatedAt   time.Time                 `json:"updatedAt"`
+}
+
+type AppNewCustomField struct {
+	Team        *uuid.UUID                `json:"teamId"`
+	Name        string                    `json:"name" validate:"required,max=100"`
+	Description string                    `json:"description"`
+	Type        string                    `json:"type" validate:"required"`
+	Options     []customfields.CoreOption `json:"options"`
+	Required    bool                      `json:"required"`
+	Position    int                       `json:"position"`
+}
+
+type AppUpdateCustomField struct {
+	Name        *string                    `json:"name" validate:"omitempty,max=100"`
+	Description *string                    `json:"description"`
+	Options     *[]customfields.CoreOption `json:"options"`
+	Required    *bool                      `json:"required"`
+	Position    *int                       `json:"position"`
+}
+
+func toAppCustomField(f customfields.CoreCustomField) AppCustomField {
+	return AppCustomField{
+		ID:          f.ID,
+		Workspace:   f.WorkspaceID,
+		Team:        f.TeamID,
+		Name:        f.Name,
+		Description: f.Description,
+		Type:        f.Type,
+		Options:     f.Options,
+		Required:    f.Required,
+		Position:    f.Position,
+		CreatedBy:   f.CreatedBy,
+		CreatedAt:   f.CreatedAt,
+		UpdatedAt:   f.UpdatedAt,
+	}
+}
+
+func toAppCustomFields(fields []customfields.CoreCustomField) []AppCustomField {
+	result := make([]AppCustomField, len(fields))
+	for i, f := range fields {
+		result[i] = toAppCustomField(f)
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package customfieldshttp
+
+import (
+	customfields "github.com/complexus-tech/projects-api/internal/modules/customfields/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/cache"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	DB        *sqlx.DB
+	Log       *logger.Logger
+	SecretKey string
+	Cache     *cache.Service
+	Service   *customfields.Service
+}
+
+func Routes(cfg Config, app *web.App) {
+	customFieldsService := cfg.Service
+	auth := mid.Auth(cfg.Log, cfg.SecretKey)
+	workspace := mid.Workspace(cfg.Log, cfg.DB, cfg.Cache)
+	adminOnly := mid.RequireMinimumRole(cfg.Log, mid.RoleAdmin)
+
+	h := New(customFieldsService)
+
+	app.Get("/workspaces/{workspaceSlug}/custom-fields", h.List, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/custom-fields", h.Create, auth, workspace, adminOnly)
+	app.Get("/workspaces/{workspaceSlug}/custom-fields/{id}", h.Get, auth, workspace)
+	app.Put("/workspaces/{workspaceSlug}/custom-fields/{id}", h.Update, auth, workspace, adminOnly)
+	app.Delete("/workspaces/{workspaceSlug}/custom-fields/{id}", h.Archive, auth, workspace, adminOnly)
+}
@@ -0,0 +1,156 @@
+package customfieldsrepository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	customfields "github.com/complexus-tech/projects-api/internal/modules/customfields/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Create inserts a field. A team field is only inserted when the team belongs
+// to the workspace.
+func (r *repo) Create(ctx context.Context, workspaceID, userID uuid.UUID, nf customfields.CoreNewCustomField) (customfields.CoreCustomField, error) {
+	r.log.Info(ctx, "business.repository.customfields.Create")
+	ctx, span := web.AddSpan(ctx, "business.repository.customfields.Create")
+	defer span.End()
+
+	options, err := json.Marshal(nf.Options)
+	if err != nil {
+		return customfields.CoreCustomField{}, fmt.Errorf("marshal custom field options: %w", err)
+	}
+
+	query := `
+		INSERT INTO custom_fields (
+			workspace_id, team_id, name, description, type, options, required, position, created_by
+		)
+		SELECT $1, CAST($2 AS uuid), $3, $4, $5, $6, $7, $8, $9
+		WHERE $2::uuid IS NULL
+			OR EXISTS (SELECT 1 FROM teams WHERE team_id = $2 AND workspace_id = $1)
+		RETURNING id`
+
+	var id uuid.UUID
+	err = r.db.GetContext(ctx, &id, query,
+		workspaceID, nf.TeamID, nf.Name, nf.Description, nf.Type, options, nf.Required, nf.Position, userID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customfields.CoreCustomField{}, customfields.ErrInvalidTeam
+		}
+		if strings.Contains(err.Error(), "custom_fields_name_unique") {
+			return customfields.CoreCustomField{}, customfields.ErrNameExists
+		}
+		errMsg := fmt.Sprintf("failed to create custom field: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to create custom field"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return customfields.CoreCustomField{}, err
+	}
+
+	span.AddEvent("custom field created", trace.WithAttributes(
+		attribute.String("custom_field.id", id.String()),
+	))
+	return r.Get(ctx, id, workspaceID)
+}
+
+// Update writes the field definition and drops story values that point at
+// options which no longer exist.
+func (r *repo) Update(ctx context.Context, field customfields.CoreCustomField, removedOptionIDs []string) error {
+	r.log.Info(ctx, "business.repository.customfields.Update")
+	ctx, span := web.AddSpan(ctx, "business.repository.customfields.Update")
+	defer span.End()
+
+	options, err := json.Marshal(field.Options)
+	if err != nil {
+		return fmt.Errorf("marshal custom field options: %w", err)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin update custom field: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE custom_fields
+		SET name = $3,
+			description = $4,
+			options = $5,
+			required = $6,
+			position = $7,
+			updated_at = NOW()
+		WHERE id = $1 AND workspace_id = $2 AND archived_at IS NULL
+	`, field.ID, field.WorkspaceID, field.Name, field.Description, options, field.Required, field.Position)
+	if err != nil {
+		if strings.Contains(err.Error(), "custom_fields_name_unique") {
+			return customfields.ErrNameExists
+		}
+		errMsg := fmt.Sprintf("failed to update custom field: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to update custom field"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return customfields.ErrNotFound
+	}
+
+	if len(removedOptionIDs) > 0 {
+		if field.Type == customfields.TypeMultiSelect {
+			_, err = tx.ExecContext(ctx, `
+				UPDATE story_custom_field_values
+				SET value = value - $2::text[], updated_at = NOW()
+				WHERE field_id = $1 AND jsonb_exists_any(value, $2::text[])
+			`, field.ID, removedOptionIDs)
+			if err == nil {
+				_, err = tx.ExecContext(ctx, `
+					DELETE FROM story_custom_field_values
+					WHERE field_id = $1 AND value = '[]'::jsonb
+				`, field.ID)
+			}
+		} else {
+			_, err = tx.ExecContext(ctx, `
+				DELETE FROM story_custom_field_values
+				WHERE field_id = $1 AND value #>> '{}' = ANY($2::text[])
+			`, field.ID, removedOptionIDs)
+		}
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to prune custom field values: %s", err)
+			r.log.Error(ctx, errMsg)
+			span.RecordError(errors.New("failed to prune custom field values"), trace.WithAttributes(attribute.String("error", errMsg)))
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit update custom field: %w", err)
+	}
+	return nil
+}
+
+func (r *repo) Archive(ctx context.Context, id, workspaceID uuid.UUID) error {
+	r.log.Info(ctx, "business.repository.customfields.Archive")
+	ctx, span := web.AddSpan(ctx, "business.repository.customfields.Archive")
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE custom_fields
+		SET archived_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND workspace_id = $2 AND archived_at IS NULL
+	`, id, workspaceID)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to archive custom field: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to archive custom field"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return customfields.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package customfieldsrepository
+
+import (
+	"encoding/json"
+	"time"
+
+	customfields "github.com/complexus-tech/projects-api/internal/modules/customfields/service"
+	"github.com/google/uuid"
+)
+
+type dbCustomField struct {
+	ID          uuid.UUID       `db:"id"`
+	WorkspaceID uuid.UUID       `db:"workspace_id"`
+	TeamID      *uuid.UUID      `db:"team_id"`
+	Name        string          `db:"name"`
+	Description string          `db:"description"`
+	Type        string          `db:"type"`
+	Options     json.RawMessage `db:"options"`
+	Required    bool            `db:"required"`
+	Position    int             `db:"position"`
+	CreatedBy   *uuid.UUID      `db:"created_by"`
+	CreatedAt   time.Time       `db:"created_at"`
+	UpdatedAt   time.Time       `db:"updated_at"`
+}
+
+func toCoreCustomField(f dbCustomField) customfields.CoreCustomField {
+	options := []customfields.CoreOption{}
+	if len(f.Options) > 0 {
+		// The column is constrained to a JSON array, so a failure here only
+		// means an option carries unexpected keys; those are ignored.
+		_ = json.Unmarshal(f.Options, &options)
+	}
+
+	return customfields.CoreCustomField{
+		ID:          f.ID,
+		WorkspaceID: f.WorkspaceID,
+		TeamID:      f.TeamID,
+		Name:        f.Name,
+		Description: f.Description,
+		Type:        f.Type,
+		Options:     options,
+		Required:    f.Required,
+		Position:    f.Position,
+		CreatedBy:   f.CreatedBy,
+		CreatedAt:   f.CreatedAt,
+		UpdatedAt:   f.UpdatedAt,
+	}
+}
+
+func toCoreCustomFields(fields []dbCustomField) []customfields.CoreCustomField {
+	result := make([]customfields.CoreCustomField, len(fields))
+	for i, f := range fields {
+		result[i] = toCoreCustomField(f)
+	}
+	return result
+}
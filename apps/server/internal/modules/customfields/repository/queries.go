@@ -0,0 +1,76 @@
+package customfieldsrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	customfields "github.com/complexus-tech/projects-api/internal/modules/customfields/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const fieldColumns = `
+		id,
+		workspace_id,
+		team_id,
+		name,
+		description,
+		type,
+		options,
+		required,
+		position,
+		created_by,
+		created_at,
+		updated_at`
+
+// List returns active fields ordered by position. When teamID is set only the
+// team's own fields and workspace-wide fields are returned.
+func (r *repo) List(ctx context.Context, workspaceID uuid.UUID, teamID *uuid.UUID) ([]customfields.CoreCustomField, error) {
+	r.log.Info(ctx, "business.repository.customfields.List")
+	ctx, span := web.AddSpan(ctx, "business.repository.customfields.List")
+	defer span.End()
+
+	query := "SELECT " + fieldColumns + `
+		FROM custom_fields
+		WHERE workspace_id = $1
+			AND archived_at IS NULL
+			AND ($2::uuid IS NULL OR team_id IS NULL OR team_id = $2)
+		ORDER BY position ASC, lower(name) ASC`
+
+	var rows []dbCustomField
+	if err := r.db.SelectContext(ctx, &rows, query, workspaceID, teamID); err != nil {
+		errMsg := fmt.Sprintf("failed to list custom fields: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to list custom fields"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	return toCoreCustomFields(rows), nil
+}
+
+func (r *repo) Get(ctx context.Context, id, workspaceID uuid.UUID) (customfields.CoreCustomField, error) {
+	r.log.Info(ctx, "business.repository.customfields.Get")
+	ctx, span := web.AddSpan(ctx, "business.repository.customfields.Get")
+	defer span.End()
+
+	query := "SELECT " + fieldColumns + `
+		FROM custom_fields
+		WHERE id = $1 AND workspace_id = $2 AND archived_at IS NULL`
+
+	var row dbCustomField
+	if err := r.db.GetContext(ctx, &row, query, id, workspaceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customfields.CoreCustomField{}, customfields.ErrNotFound
+		}
+		errMsg := fmt.Sprintf("failed to get custom field: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get custom field"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return customfields.CoreCustomField{}, err
+	}
+
+	return toCoreCustomField(row), nil
+}
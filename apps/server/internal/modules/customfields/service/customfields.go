@@ -0,0 +1,220 @@
+package customfields
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const maxOptions = 100
+
+// Service errors
+var (
+	ErrNotFound       = errors.New("custom field not found")
+	ErrNameRequired   = errors.New("custom field name is required")
+	ErrNameExists     = errors.New("a custom field with this name already exists")
+	ErrInvalidType    = fmt.Errorf("invalid custom field type, must be one of: %s", strings.Join(Types, ", "))
+	ErrInvalidTeam    = errors.New("team does not belong to this workspace")
+	ErrInvalidOptions = errors.New("select fields need between 1 and 100 options with unique, non-empty labels")
+	ErrInvalidValue   = errors.New("invalid custom field value")
+)
+
+// Repository provides access to the custom fields storage.
+type Repository interface {
+	List(ctx context.Context, workspaceID uuid.UUID, teamID *uuid.UUID) ([]CoreCustomField, error)
+	Get(ctx context.Context, id, workspaceID uuid.UUID) (CoreCustomField, error)
+	Create(ctx context.Context, workspaceID, userID uuid.UUID, nf CoreNewCustomField) (CoreCustomField, error)
+	Update(ctx context.Context, field CoreCustomField, removedOptionIDs []string) error
+	Archive(ctx context.Context, id, workspaceID uuid.UUID) error
+}
+
+// Service provides custom field operations.
+type Service struct {
+	repo Repository
+	log  *logger.Logger
+}
+
+// New constructs a new custom fields service instance with the provided repository.
+func New(log *logger.Logger, repo Repository) *Service {
+	return &Service{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// List returns the active fields of a workspace. With a team, only the fields
+// that apply to that team (its own and workspace-wide ones) are returned.
+func (s *Service) List(ctx context.Context, workspaceID uuid.UUID, teamID *uuid.UUID) ([]CoreCustomField, error) {
+	s.log.Info(ctx, "business.core.customfields.list")
+	ctx, span := web.AddSpan(ctx, "business.core.customfields.List")
+	defer span.End()
+
+	fields, err := s.repo.List(ctx, workspaceID, teamID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.AddEvent("custom fields retrieved.", trace.WithAttributes(
+		attribute.Int("custom_field.count", len(fields)),
+	))
+	return fields, nil
+}
+
+// Get returns a single active field.
+func (s *Service) Get(ctx context.Context, id, workspaceID uuid.UUID) (CoreCustomField, error) {
+	s.log.Info(ctx, "business.core.customfields.get")
+	ctx, span := web.AddSpan(ctx, "business.core.customfields.Get")
+	defer span.End()
+
+	field, err := s.repo.Get(ctx, id, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreCustomField{}, err
+	}
+	return field, nil
+}
+
+// Create defines a new field.
+func (s *Service) Create(ctx context.Context, workspaceID, userID uuid.UUID, nf CoreNewCustomField) (CoreCustomField, error) {
+	s.log.Info(ctx, "business.core.customfields.create")
+	ctx, span := web.AddSpan(ctx, "business.core.customfields.Create")
+	defer span.End()
+
+	nf.Name = strings.TrimSpace(nf.Name)
+	if nf.Name == "" {
+		return CoreCustomField{}, ErrNameRequired
+	}
+	if !slices.Contains(Types, nf.Type) {
+		return CoreCustomField{}, ErrInvalidType
+	}
+
+	options, err := normalizeOptions(nf.Type, nf.Options)
+	if err != nil {
+		return CoreCustomField{}, err
+	}
+	nf.Options = options
+
+	field, err := s.repo.Create(ctx, workspaceID, userID, nf)
+	if err != nil {
+		span.RecordError(err)
+		return CoreCustomField{}, err
+	}
+
+	span.AddEvent("custom field created.", trace.WithAttributes(
+		attribute.String("custom_field.id", field.ID.String()),
+		attribute.String("custom_field.type", field.Type),
+	))
+	return field, nil
+}
+
+// Update changes a field's definition. Values pointing at removed options are
+// dropped from stories.
+func (s *Service) Update(ctx context.Context, id, workspaceID uuid.UUID, uf CoreUpdateCustomField) (CoreCustomField, error) {
+	s.log.Info(ctx, "business.core.customfields.update")
+	ctx, span := web.AddSpan(ctx, "business.core.customfields.Update")
+	defer span.End()
+
+	field, err := s.repo.Get(ctx, id, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreCustomField{}, err
+	}
+
+	if uf.Name != nil {
+		field.Name = strings.TrimSpace(*uf.Name)
+		if field.Name == "" {
+			return CoreCustomField{}, ErrNameRequired
+		}
+	}
+	if uf.Description != nil {
+		field.Description = *uf.Description
+	}
+	if uf.Required != nil {
+		field.Required = *uf.Required
+	}
+	if uf.Position != nil {
+		field.Position = *uf.Position
+	}
+
+	var removedOptionIDs []string
+	if uf.Options != nil {
+		options, err := normalizeOptions(field.Type, *uf.Options)
+		if err != nil {
+			return CoreCustomField{}, err
+		}
+		for _, option := range field.Options {
+			if !hasOptionID(options, option.ID) {
+				removedOptionIDs = append(removedOptionIDs, option.ID)
+			}
+		}
+		field.Options = options
+	}
+
+	if err := s.repo.Update(ctx, field, removedOptionIDs); err != nil {
+		span.RecordError(err)
+		return CoreCustomField{}, err
+	}
+	return s.repo.Get(ctx, id, workspaceID)
+}
+
+// Archive hides a field. Stored values are kept so the field can be reviewed later
+// but no longer show up on stories.
+func (s *Service) Archive(ctx context.Context, id, workspaceID uuid.UUID) error {
+	s.log.Info(ctx, "business.core.customfields.archive")
+	ctx, span := web.AddSpan(ctx, "business.core.customfields.Archive")
+	defer span.End()
+
+	if err := s.repo.Archive(ctx, id, workspaceID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// normalizeOptions validates select options and assigns IDs to new ones.
+// Non-select fields never carry options.
+func normalizeOptions(fieldType string, options []CoreOption) ([]CoreOption, error) {
+	if fieldType != TypeSingleSelect && fieldType != TypeMultiSelect {
+		return []CoreOption{}, nil
+	}
+	if len(options) == 0 || len(options) > maxOptions {
+		return nil, ErrInvalidOptions
+	}
+
+	normalized := make([]CoreOption, 0, len(options))
+	seenLabels := make(map[string]struct{}, len(options))
+	for _, option := range options {
+		option.Label = strings.TrimSpace(option.Label)
+		key := strings.ToLower(option.Label)
+		if option.Label == "" {
+			return nil, ErrInvalidOptions
+		}
+		if _, ok := seenLabels[key]; ok {
+			return nil, ErrInvalidOptions
+		}
+		seenLabels[key] = struct{}{}
+
+		if option.ID == "" || hasOptionID(normalized, option.ID) {
+			option.ID = uuid.NewString()
+		}
+		normalized = append(normalized, option)
+	}
+	return normalized, nil
+}
+
+func hasOptionID(options []CoreOption, id string) bool {
+	for _, option := range options {
+		if option.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package customfields
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Field types.
+const (
+	TypeText         = "text"
+	TypeNumber       = "number"
+	TypeSingleSelect = "single_select"
+	TypeMultiSelect  = "multi_select"
+	TypeDate         = "date"
+	TypeUser         = "user"
+	TypeURL          = "url"
+)
+
+// Types lists every supported field type.
+var Types = []string{TypeText, TypeNumber, TypeSingleSelect, TypeMultiSelect, TypeDate, TypeUser, TypeURL}
+
+// CoreCustomField is a typed story field defined by a workspace admin. Fields
+// without a team apply to every team in the workspace.
+type CoreCustomField struct {
+	ID          uuid.UUID
+	WorkspaceID uuid.UUID
+	TeamID      *uuid.UUID
+	Name        string
+	Description string
+	Type        string
+	Options     []CoreOption
+	Required    bool
+	Position    int
+	CreatedBy   *uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CoreOption is a choice of a single or multi select field. Values store the option ID,
+// so labels can be renamed without touching stories.
+type CoreOption struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Color string `json:"color,omitempty"`
+}
+
+// IsSelect reports whether the field stores option IDs.
+func (f CoreCustomField) IsSelect() bool {
+	return f.Type == TypeSingleSelect || f.Type == TypeMultiSelect
+}
+
+// Groupable reports whether stories can be grouped by the field. Multi select
+// fields are excluded because a story would land in several groups.
+func (f CoreCustomField) Groupable() bool {
+	return f.Type != TypeMultiSelect
+}
+
+type CoreNewCustomField struct {
+	TeamID      *uuid.UUID
+	Name        string
+	Description string
+	Type        string
+	Options     []CoreOption
+	Required    bool
+	Position    int
+}
+
+// CoreUpdateCustomField holds the fields to change. Nil fields are left as they
+// are. A field's type cannot be changed.
+type CoreUpdateCustomField struct {
+	Name        *string
+	Description *string
+	Options     *[]CoreOption
+	Required    *bool
+	Position    *int
+}
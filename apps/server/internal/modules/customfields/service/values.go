@@ -0,0 +1,186 @@
+package customfields
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/complexus-tech/projects-api/pkg/date"
+	"github.com/google/uuid"
+)
+
+const (
+	maxTextLength = 5000
+	maxURLLength  = 2048
+)
+
+// ValidateValue checks raw against the field's type and returns it in its
+// canonical JSON form. A null or empty value clears the field and returns nil.
+func ValidateValue(field CoreCustomField, raw json.RawMessage) (json.RawMessage, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" {
+		if field.Required {
+			return nil, invalidValue(field, "is required")
+		}
+		return nil, nil
+	}
+
+	var canonical any
+	switch field.Type {
+	case TypeText:
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, invalidValue(field, "must be text")
+		}
+		if utf8.RuneCountInString(text) > maxTextLength {
+			return nil, invalidValue(field, fmt.Sprintf("must be at most %d characters", maxTextLength))
+		}
+		if strings.TrimSpace(text) == "" {
+			return ValidateValue(field, nil)
+		}
+		canonical = text
+
+	case TypeNumber:
+		var number float64
+		if err := json.Unmarshal(raw, &number); err != nil || math.IsInf(number, 0) || math.IsNaN(number) {
+			return nil, invalidValue(field, "must be a number")
+		}
+		canonical = number
+
+	case TypeSingleSelect:
+		var optionID string
+		if err := json.Unmarshal(raw, &optionID); err != nil {
+			return nil, invalidValue(field, "must be an option id")
+		}
+		if !field.hasOption(optionID) {
+			return nil, invalidValue(field, fmt.Sprintf("has no option %q", optionID))
+		}
+		canonical = optionID
+
+	case TypeMultiSelect:
+		var optionIDs []string
+		if err := json.Unmarshal(raw, &optionIDs); err != nil {
+			return nil, invalidValue(field, "must be a list of option ids")
+		}
+		selected := make([]string, 0, len(optionIDs))
+		for _, optionID := range optionIDs {
+			if !field.hasOption(optionID) {
+				return nil, invalidValue(field, fmt.Sprintf("has no option %q", optionID))
+			}
+			if !slices.Contains(selected, optionID) {
+				selected = append(selected, optionID)
+			}
+		}
+		if len(selected) == 0 {
+			return ValidateValue(field, nil)
+		}
+		canonical = selected
+
+	case TypeDate:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, invalidValue(field, "must be a date (YYYY-MM-DD)")
+		}
+		parsed, err := date.ParseDateOnly(value)
+		if err != nil {
+			return nil, invalidValue(field, "must be a date (YYYY-MM-DD)")
+		}
+		canonical = parsed.Format("2006-01-02")
+
+	case TypeUser:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, invalidValue(field, "must be a user id")
+		}
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			return nil, invalidValue(field, "must be a user id")
+		}
+		canonical = userID.String()
+
+	case TypeURL:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, invalidValue(field, "must be a URL")
+		}
+		value = strings.TrimSpace(value)
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, invalidValue(field, "must be an http or https URL")
+		}
+		if len(value) > maxURLLength {
+			return nil, invalidValue(field, fmt.Sprintf("must be at most %d characters", maxURLLength))
+		}
+		canonical = value
+
+	default:
+		return nil, invalidValue(field, "has an unknown type")
+	}
+
+	encoded, err := json.Marshal(canonical)
+	if err != nil {
+		return nil, invalidValue(field, err.Error())
+	}
+	return encoded, nil
+}
+
+// DisplayValue renders a stored value for activity logs, resolving option IDs to labels.
+func DisplayValue(field CoreCustomField, value json.RawMessage) string {
+	if len(value) == 0 || string(value) == "null" {
+		return "nil"
+	}
+
+	switch field.Type {
+	case TypeSingleSelect:
+		var optionID string
+		if err := json.Unmarshal(value, &optionID); err == nil {
+			return field.optionLabel(optionID)
+		}
+	case TypeMultiSelect:
+		var optionIDs []string
+		if err := json.Unmarshal(value, &optionIDs); err == nil {
+			labels := make([]string, len(optionIDs))
+			for i, optionID := range optionIDs {
+				labels[i] = field.optionLabel(optionID)
+			}
+			return strings.Join(labels, ", ")
+		}
+	case TypeNumber:
+		var number float64
+		if err := json.Unmarshal(value, &number); err == nil {
+			return fmt.Sprintf("%g", number)
+		}
+	default:
+		var text string
+		if err := json.Unmarshal(value, &text); err == nil {
+			return text
+		}
+	}
+	return string(value)
+}
+
+func (f CoreCustomField) hasOption(optionID string) bool {
+	for _, option := range f.Options {
+		if option.ID == optionID {
+			return true
+		}
+	}
+	return false
+}
+
+func (f CoreCustomField) optionLabel(optionID string) string {
+	for _, option := range f.Options {
+		if option.ID == optionID {
+			return option.Label
+		}
+	}
+	return optionID
+}
+
+func invalidValue(field CoreCustomField, reason string) error {
+	return fmt.Errorf("%w: %s %s", ErrInvalidValue, field.Name, reason)
+}
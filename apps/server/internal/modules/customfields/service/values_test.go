@@ -0,0 +1,93 @@
+package customfields
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestValidateValue(t *testing.T) {
+	selectField := CoreCustomField{
+		Name: "Severity",
+		Type: TypeSingleSelect,
+		Options: []CoreOption{
+			{ID: "sev1", Label: "Critical"},
+			{ID: "sev2", Label: "Major"},
+		},
+	}
+	multiField := selectField
+	multiField.Type = TypeMultiSelect
+
+	tests := []struct {
+		name    string
+		field   CoreCustomField
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{name: "text", field: CoreCustomField{Type: TypeText}, raw: `"Acme Corp"`, want: `"Acme Corp"`},
+		{name: "blank text clears", field: CoreCustomField{Type: TypeText}, raw: `"  "`, want: ``},
+		{name: "number", field: CoreCustomField{Type: TypeNumber}, raw: `4.50`, want: `4.5`},
+		{name: "number as string", field: CoreCustomField{Type: TypeNumber}, raw: `"4"`, wantErr: true},
+		{name: "single select", field: selectField, raw: `"sev2"`, want: `"sev2"`},
+		{name: "unknown option", field: selectField, raw: `"sev9"`, wantErr: true},
+		{name: "multi select dedupes", field: multiField, raw: `["sev1","sev2","sev1"]`, want: `["sev1","sev2"]`},
+		{name: "empty multi select clears", field: multiField, raw: `[]`, want: ``},
+		{name: "date", field: CoreCustomField{Type: TypeDate}, raw: `"2026-03-01T10:00:00Z"`, want: `"2026-03-01"`},
+		{name: "bad date", field: CoreCustomField{Type: TypeDate}, raw: `"March 1st"`, wantErr: true},
+		{name: "user", field: CoreCustomField{Type: TypeUser}, raw: `"6F9619FF-8B86-D011-B42D-00C04FC964FF"`, want: `"6f9619ff-8b86-d011-b42d-00c04fc964ff"`},
+		{name: "url", field: CoreCustomField{Type: TypeURL}, raw: `" https://example.com/a "`, want: `"https://example.com/a"`},
+		{name: "url without scheme", field: CoreCustomField{Type: TypeURL}, raw: `"example.com"`, wantErr: true},
+		{name: "null clears", field: CoreCustomField{Type: TypeText}, raw: `null`, want: ``},
+		{name: "required cannot clear", field: CoreCustomField{Type: TypeText, Required: true}, raw: `null`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateValue(tt.field, json.RawMessage(tt.raw))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidValue) {
+					t.Fatalf("expected ErrInvalidValue, got %v (value %s)", err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDisplayValueResolvesOptionLabels(t *testing.T) {
+	field := CoreCustomField{
+		Type:    TypeMultiSelect,
+		Options: []CoreOption{{ID: "a", Label: "Web"}, {ID: "b", Label: "iOS"}},
+	}
+
+	if got := DisplayValue(field, json.RawMessage(`["b","a"]`)); got != "iOS, Web" {
+		t.Fatalf("expected option labels, got %q", got)
+	}
+	if got := DisplayValue(field, nil); got != "nil" {
+		t.Fatalf("expected nil for an empty value, got %q", got)
+	}
+}
+
+func TestNormalizeOptions(t *testing.T) {
+	options, err := normalizeOptions(TypeSingleSelect, []CoreOption{{Label: " Low "}, {ID: "keep", Label: "High"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if options[0].ID == "" || options[0].Label != "Low" || options[1].ID != "keep" {
+		t.Fatalf("unexpected options: %+v", options)
+	}
+
+	if _, err := normalizeOptions(TypeMultiSelect, []CoreOption{{Label: "A"}, {Label: "a"}}); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected duplicate labels to be rejected, got %v", err)
+	}
+	if options, _ := normalizeOptions(TypeText, []CoreOption{{Label: "ignored"}}); len(options) != 0 {
+		t.Fatalf("expected non-select fields to drop options, got %+v", options)
+	}
+}
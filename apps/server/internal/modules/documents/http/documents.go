@@ -2,11 +2,21 @@ package documentshttp
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 
 	documents "github.com/complexus-tech/projects-api/internal/modules/documents/service"
 	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
 	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidDocumentID = errors.New("document id is not in its proper form")
+	ErrInvalidLinkID     = errors.New("link id is not in its proper form")
+	ErrInvalidRevision   = errors.New("revision must be a positive number")
+	ErrInvalidFilter     = errors.New("teamId, parentId, storyId and objectiveId must be valid ids")
 )
 
 type Handlers struct {
@@ -19,16 +29,457 @@ func New(documents *documents.Service) *Handlers {
 	}
 }
 
+// List returns documents. Supported filters: teamId (or scope=workspace),
+// parentId (or root=true), storyId and objectiveId.
 func (h *Handlers) List(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	_, err := mid.GetWorkspace(ctx)
+	ctx, span := web.AddSpan(ctx, "documentshttp.handlers.List")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	query := r.URL.Query()
+	filters := documents.CoreDocumentFilters{
+		WorkspaceOnly: query.Get("scope") == "workspace",
+		RootOnly:      query.Get("root") == "true",
+	}
+	for param, target := range map[string]**uuid.UUID{
+		"teamId":      &filters.TeamID,
+		"parentId":    &filters.ParentID,
+		"storyId":     &filters.StoryID,
+		"objectiveId": &filters.ObjectiveID,
+	} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return web.RespondError(ctx, w, ErrInvalidFilter, http.StatusBadRequest)
+		}
+		*target = &id
+	}
+
+	documentsList, err := h.documents.List(ctx, workspace.ID, userID, filters)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppDocuments(documentsList), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) Get(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "documentshttp.handlers.Get")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
 	if err != nil {
 		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
 	}
 
-	documents, err := h.documents.List(ctx)
+	userID, err := mid.GetUserID(ctx)
 	if err != nil {
-		return err
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
 	}
-	web.Respond(ctx, w, toAppDocuments(documents), http.StatusOK)
+
+	documentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidDocumentID, http.StatusBadRequest)
+	}
+
+	document, err := h.documents.Get(ctx, documentID, workspace.ID, userID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppDocument(document), http.StatusOK)
 	return nil
 }
+
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "documentshttp.handlers.Create")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	var nd AppNewDocument
+	if err := web.Decode(r, &nd); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	document, err := h.documents.Create(ctx, workspace.ID, userID, documents.CoreNewDocument{
+		TeamID:      nd.Team,
+		ParentID:    nd.Parent,
+		Title:       nd.Title,
+		Content:     nd.Content,
+		ContentHTML: nd.ContentHTML,
+		Icon:        nd.Icon,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppDocument(document), http.StatusCreated)
+	return nil
+}
+
+func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "documentshttp.handlers.Update")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	documentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidDocumentID, http.StatusBadRequest)
+	}
+
+	var ud AppUpdateDocument
+	if err := web.Decode(r, &ud); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	document, err := h.documents.Update(ctx, documentID, workspace.ID, userID, documents.CoreUpdateDocument{
+		Title:       ud.Title,
+		Content:     ud.Content,
+		ContentHTML: ud.ContentHTML,
+		Icon:        ud.Icon,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppDocument(document), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) Move(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "documentshttp.handlers.Move")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	documentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidDocumentID, http.StatusBadRequest)
+	}
+
+	var md AppMoveDocument
+	if err := web.Decode(r, &md); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	document, err := h.documents.Move(ctx, documentID, workspace.ID, userID, md.Parent)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppDocument(document), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "documentshttp.handlers.Delete")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	documentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidDocumentID, http.StatusBadRequest)
+	}
+
+	if err := h.documents.Delete(ctx, documentID, workspace.ID, userID); err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, nil, http.StatusNoContent)
+	return nil
+}
+
+func (h *Handlers) ListRevisions(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "documentshttp.handlers.ListRevisions")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	documentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidDocumentID, http.StatusBadRequest)
+	}
+
+	revisions, err := h.documents.ListRevisions(ctx, documentID, workspace.ID, userID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppRevisions(revisions), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) GetRevision(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "documentshttp.handlers.GetRevision")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	documentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidDocumentID, http.StatusBadRequest)
+	}
+	revision, err := parseRevision(web.Params(r, "revision"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	rev, err := h.documents.GetRevision(ctx, documentID, workspace.ID, userID, revision)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppRevision(rev), http.StatusOK)
+	return nil
+}
+
+// DiffRevisions compares ?from= and ?to= revisions. When to is omitted the
+// document's current revision is used.
+func (h *Handlers) DiffRevisions(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "documentshttp.handlers.DiffRevisions")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	documentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidDocumentID, http.StatusBadRequest)
+	}
+	from, err := parseRevision(r.URL.Query().Get("from"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	var to int
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = parseRevision(value); err != nil {
+			return web.RespondError(ctx, w, err, http.StatusBadRequest)
+		}
+	} else {
+		document, err := h.documents.Get(ctx, documentID, workspace.ID, userID)
+		if err != nil {
+			return web.RespondError(ctx, w, err, httpStatus(err))
+		}
+		to = document.Revision
+	}
+
+	diff, err := h.documents.DiffRevisions(ctx, documentID, workspace.ID, userID, from, to)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppRevisionDiff(diff), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) RestoreRevision(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "documentshttp.handlers.RestoreRevision")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	documentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidDocumentID, http.StatusBadRequest)
+	}
+	revision, err := parseRevision(web.Params(r, "revision"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	document, err := h.documents.RestoreRevision(ctx, documentID, workspace.ID, userID, revision)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppDocument(document), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) ListLinks(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "documentshttp.handlers.ListLinks")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	documentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidDocumentID, http.StatusBadRequest)
+	}
+
+	links, err := h.documents.ListLinks(ctx, documentID, workspace.ID, userID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppDocumentLinks(links), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) AddLink(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "documentshttp.handlers.AddLink")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	documentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidDocumentID, http.StatusBadRequest)
+	}
+
+	var nl AppNewDocumentLink
+	if err := web.Decode(r, &nl); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	link, err := h.documents.AddLink(ctx, documentID, workspace.ID, userID, nl.TargetType, nl.TargetID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppDocumentLink(link), http.StatusCreated)
+	return nil
+}
+
+func (h *Handlers) RemoveLink(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "documentshttp.handlers.RemoveLink")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	documentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidDocumentID, http.StatusBadRequest)
+	}
+	linkID, err := uuid.Parse(web.Params(r, "linkId"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidLinkID, http.StatusBadRequest)
+	}
+
+	if err := h.documents.RemoveLink(ctx, documentID, workspace.ID, userID, linkID); err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, nil, http.StatusNoContent)
+	return nil
+}
+
+func parseRevision(value string) (int, error) {
+	revision, err := strconv.Atoi(value)
+	if err != nil || revision < 1 {
+		return 0, ErrInvalidRevision
+	}
+	return revision, nil
+}
+
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, documents.ErrNotFound),
+		errors.Is(err, documents.ErrRevisionNotFound),
+		errors.Is(err, documents.ErrLinkNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, documents.ErrRevisionConflict),
+		errors.Is(err, documents.ErrLinkExists):
+		return http.StatusConflict
+	case errors.Is(err, documents.ErrTeamAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, documents.ErrTitleRequired),
+		errors.Is(err, documents.ErrInvalidTeam),
+		errors.Is(err, documents.ErrInvalidParent),
+		errors.Is(err, documents.ErrInvalidLinkTarget):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
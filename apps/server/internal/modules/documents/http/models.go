@@ -1,26 +1,176 @@
 package documentshttp
 
 import (
+	"time"
+
 	documents "github.com/complexus-tech/projects-api/internal/modules/documents/service"
 	"github.com/google/uuid"
 )
 
-// AppDocumentList represents a document in the application layer.
+// AppDocumentsList represents a document in list responses, without its content.
 type AppDocumentsList struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"title"`
-	Description string    `json:"description"`
+	ID         uuid.UUID  `json:"id"`
+	Workspace  uuid.UUID  `json:"workspaceId"`
+	Team       *uuid.UUID `json:"teamId"`
+	Parent     *uuid.UUID `json:"parentId"`
+	Title      string     `json:"title"`
+	Icon       *string    `json:"icon"`
+	Revision   int        `json:"revision"`
+	ChildCount int        `json:"childCount"`
+	CreatedBy  *uuid.UUID `json:"createdBy"`
+	UpdatedBy  *uuid.UUID `json:"updatedBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// AppDocument represents a single document with its content.
+type AppDocument struct {
+	AppDocumentsList
+	Content     string `json:"content"`
+	ContentHTML string `json:"contentHTML"`
+}
+
+type AppNewDocument struct {
+	Title       string     `json:"title" validate:"required"`
+	Team        *uuid.UUID `json:"teamId"`
+	Parent      *uuid.UUID `json:"parentId"`
+	Content     string     `json:"content"`
+	ContentHTML string     `json:"contentHTML"`
+	Icon        *string    `json:"icon"`
+}
+
+type AppUpdateDocument struct {
+	Title       *string `json:"title"`
+	Content     *string `json:"content"`
+	ContentHTML *string `json:"contentHTML"`
+	Icon        *string `json:"icon"`
+}
+
+type AppMoveDocument struct {
+	Parent *uuid.UUID `json:"parentId"`
+}
+
+type AppRevision struct {
+	ID           uuid.UUID  `json:"id"`
+	Document     uuid.UUID  `json:"documentId"`
+	Revision     int        `json:"revision"`
+	Title        string     `json:"title"`
+	Content      string     `json:"content"`
+	ContentHTML  string     `json:"contentHTML"`
+	Author       *uuid.UUID `json:"authorId"`
+	RestoredFrom *int       `json:"restoredFrom"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+type AppRevisionDiff struct {
+	Document  uuid.UUID `json:"documentId"`
+	From      int       `json:"from"`
+	To        int       `json:"to"`
+	FromTitle string    `json:"fromTitle"`
+	ToTitle   string    `json:"toTitle"`
+	Diff      string    `json:"diff"`
+	Additions int       `json:"additions"`
+	Deletions int       `json:"deletions"`
+}
+
+type AppDocumentLink struct {
+	ID         uuid.UUID  `json:"id"`
+	Document   uuid.UUID  `json:"documentId"`
+	TargetType string     `json:"type"`
+	TargetID   uuid.UUID  `json:"targetId"`
+	CreatedBy  *uuid.UUID `json:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type AppNewDocumentLink struct {
+	TargetType string    `json:"type" validate:"required,oneof=story objective"`
+	TargetID   uuid.UUID `json:"targetId" validate:"required"`
+}
+
+func toAppDocumentsListItem(document documents.CoreDocument) AppDocumentsList {
+	return AppDocumentsList{
+		ID:         document.ID,
+		Workspace:  document.WorkspaceID,
+		Team:       document.TeamID,
+		Parent:     document.ParentID,
+		Title:      document.Title,
+		Icon:       document.Icon,
+		Revision:   document.Revision,
+		ChildCount: document.ChildCount,
+		CreatedBy:  document.CreatedBy,
+		UpdatedBy:  document.UpdatedBy,
+		CreatedAt:  document.CreatedAt,
+		UpdatedAt:  document.UpdatedAt,
+	}
 }
 
 // toAppDocuments converts a list of core documents to a list of application documents.
 func toAppDocuments(documents []documents.CoreDocument) []AppDocumentsList {
 	appdocuments := make([]AppDocumentsList, len(documents))
 	for i, document := range documents {
-		appdocuments[i] = AppDocumentsList{
-			ID:          document.ID,
-			Name:        document.Name,
-			Description: document.Description,
-		}
+		appdocuments[i] = toAppDocumentsListItem(document)
 	}
 	return appdocuments
 }
+
+func toAppDocument(document documents.CoreDocument) AppDocument {
+	return AppDocument{
+		AppDocumentsList: toAppDocumentsListItem(document),
+		Content:          document.Content,
+		ContentHTML:      document.ContentHTML,
+	}
+}
+
+func toAppRevision(revision documents.CoreRevision) AppRevision {
+	return AppRevision{
+		ID:           revision.ID,
+		Document:     revision.DocumentID,
+		Revision:     revision.Revision,
+		Title:        revision.Title,
+		Content:      revision.Content,
+		ContentHTML:  revision.ContentHTML,
+		Author:       revision.AuthorID,
+		RestoredFrom: revision.RestoredFrom,
+		CreatedAt:    revision.CreatedAt,
+	}
+}
+
+func toAppRevisions(revisions []documents.CoreRevision) []AppRevision {
+	appRevisions := make([]AppRevision, len(revisions))
+	for i, revision := range revisions {
+		appRevisions[i] = toAppRevision(revision)
+	}
+	return appRevisions
+}
+
+func toAppRevisionDiff(diff documents.CoreRevisionDiff) AppRevisionDiff {
+	return AppRevisionDiff{
+		Document:  diff.DocumentID,
+		From:      diff.From,
+		To:        diff.To,
+		FromTitle: diff.FromTitle,
+		ToTitle:   diff.ToTitle,
+		Diff:      diff.Diff,
+		Additions: diff.Additions,
+		Deletions: diff.Deletions,
+	}
+}
+
+func toAppDocumentLink(link documents.CoreDocumentLink) AppDocumentLink {
+	return AppDocumentLink{
+		ID:         link.ID,
+		Document:   link.DocumentID,
+		TargetType: link.TargetType,
+		TargetID:   link.TargetID,
+		CreatedBy:  link.CreatedBy,
+		CreatedAt:  link.CreatedAt,
+	}
+}
+
+func toAppDocumentLinks(links []documents.CoreDocumentLink) []AppDocumentLink {
+	appLinks := make([]AppDocumentLink, len(links))
+	for i, link := range links {
+		appLinks[i] = toAppDocumentLink(link)
+	}
+	return appLinks
+}
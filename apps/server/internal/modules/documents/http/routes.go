@@ -21,8 +21,21 @@ func Routes(cfg Config, app *web.App) {
 	documentsService := cfg.Service
 	auth := mid.Auth(cfg.Log, cfg.SecretKey)
 	workspace := mid.Workspace(cfg.Log, cfg.DB, cfg.Cache)
+	memberAndAdmin := mid.RequireMinimumRole(cfg.Log, mid.RoleMember)
 
 	h := New(documentsService)
 
 	app.Get("/workspaces/{workspaceSlug}/documents", h.List, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/documents", h.Create, auth, workspace, memberAndAdmin)
+	app.Get("/workspaces/{workspaceSlug}/documents/{id}", h.Get, auth, workspace)
+	app.Put("/workspaces/{workspaceSlug}/documents/{id}", h.Update, auth, workspace, memberAndAdmin)
+	app.Delete("/workspaces/{workspaceSlug}/documents/{id}", h.Delete, auth, workspace, memberAndAdmin)
+	app.Put("/workspaces/{workspaceSlug}/documents/{id}/parent", h.Move, auth, workspace, memberAndAdmin)
+	app.Get("/workspaces/{workspaceSlug}/documents/{id}/revisions", h.ListRevisions, auth, workspace)
+	app.Get("/workspaces/{workspaceSlug}/documents/{id}/revisions/diff", h.DiffRevisions, auth, workspace)
+	app.Get("/workspaces/{workspaceSlug}/documents/{id}/revisions/{revision}", h.GetRevision, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/documents/{id}/revisions/{revision}/restore", h.RestoreRevision, auth, workspace, memberAndAdmin)
+	app.Get("/workspaces/{workspaceSlug}/documents/{id}/links", h.ListLinks, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/documents/{id}/links", h.AddLink, auth, workspace, memberAndAdmin)
+	app.Delete("/workspaces/{workspaceSlug}/documents/{id}/links/{linkId}", h.RemoveLink, auth, workspace, memberAndAdmin)
 }
@@ -0,0 +1,303 @@
+package documentsrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	documents "github.com/complexus-tech/projects-api/internal/modules/documents/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// subtreeCTE selects a live document ($1 in workspace $2) and all of its live sub-pages.
+const subtreeCTE = `
+	WITH RECURSIVE subtree AS (
+		SELECT id FROM documents
+		WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+		UNION ALL
+		SELECT d.id FROM documents d
+		INNER JOIN subtree s ON d.parent_id = s.id
+		WHERE d.deleted_at IS NULL
+	)
+`
+
+// Create inserts a document and its first revision. A sub-page inherits its
+// parent's team and must not be placed under a page from another team.
+func (r *repo) Create(ctx context.Context, workspaceID, userID uuid.UUID, nd documents.CoreNewDocument) (documents.CoreDocument, error) {
+	r.log.Info(ctx, "business.repository.documents.Create")
+	ctx, span := web.AddSpan(ctx, "business.repository.documents.Create")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return documents.CoreDocument{}, fmt.Errorf("begin create document: %w", err)
+	}
+	defer tx.Rollback()
+
+	if nd.TeamID != nil {
+		var exists bool
+		if err := tx.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM teams WHERE team_id = $1 AND workspace_id = $2)`, *nd.TeamID, workspaceID); err != nil {
+			return documents.CoreDocument{}, fmt.Errorf("check document team: %w", err)
+		}
+		if !exists {
+			return documents.CoreDocument{}, documents.ErrInvalidTeam
+		}
+	}
+
+	if nd.ParentID != nil {
+		var parentTeamID *uuid.UUID
+		err := tx.GetContext(ctx, &parentTeamID, `
+			SELECT team_id FROM documents
+			WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+		`, *nd.ParentID, workspaceID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return documents.CoreDocument{}, documents.ErrInvalidParent
+		}
+		if err != nil {
+			return documents.CoreDocument{}, fmt.Errorf("get parent document: %w", err)
+		}
+		if nd.TeamID == nil {
+			nd.TeamID = parentTeamID
+		} else if parentTeamID == nil || *parentTeamID != *nd.TeamID {
+			return documents.CoreDocument{}, documents.ErrInvalidParent
+		}
+	}
+
+	var id uuid.UUID
+	err = tx.GetContext(ctx, &id, `
+		INSERT INTO documents (
+			workspace_id, team_id, parent_id, title, content, content_html, icon, created_by, updated_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		RETURNING id
+	`, workspaceID, nd.TeamID, nd.ParentID, nd.Title, nd.Content, nd.ContentHTML, nd.Icon, userID)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to create document: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to create document"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return documents.CoreDocument{}, err
+	}
+
+	change := documents.CoreDocumentChange{Title: nd.Title, Content: nd.Content, ContentHTML: nd.ContentHTML}
+	if err := insertRevision(ctx, tx, id, 1, userID, change); err != nil {
+		return documents.CoreDocument{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return documents.CoreDocument{}, fmt.Errorf("commit create document: %w", err)
+	}
+
+	span.AddEvent("document created", trace.WithAttributes(
+		attribute.String("document.id", id.String()),
+	))
+	return r.Get(ctx, id, workspaceID)
+}
+
+// Update writes the full document state. The write only succeeds when the
+// stored revision still matches change.ExpectedRevision.
+func (r *repo) Update(ctx context.Context, id, workspaceID, userID uuid.UUID, change documents.CoreDocumentChange) error {
+	r.log.Info(ctx, "business.repository.documents.Update")
+	ctx, span := web.AddSpan(ctx, "business.repository.documents.Update")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin update document: %w", err)
+	}
+	defer tx.Rollback()
+
+	revisionStep := 0
+	if change.Revise {
+		revisionStep = 1
+	}
+
+	var revision int
+	err = tx.GetContext(ctx, &revision, `
+		UPDATE documents
+		SET title = $4,
+			content = $5,
+			content_html = $6,
+			icon = $7,
+			revision = revision + $8,
+			updated_by = $9,
+			updated_at = NOW()
+		WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL AND revision = $3
+		RETURNING revision
+	`, id, workspaceID, change.ExpectedRevision, change.Title, change.Content, change.ContentHTML, change.Icon, revisionStep, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, getErr := r.Get(ctx, id, workspaceID); getErr != nil {
+			return getErr
+		}
+		return documents.ErrRevisionConflict
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to update document: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to update document"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+
+	if change.Revise {
+		if err := insertRevision(ctx, tx, id, revision, userID, change); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit update document: %w", err)
+	}
+	return nil
+}
+
+func insertRevision(ctx context.Context, tx *sqlx.Tx, documentID uuid.UUID, revision int, userID uuid.UUID, change documents.CoreDocumentChange) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO document_revisions (
+			document_id, revision, title, content, content_html, author_id, restored_from
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, documentID, revision, change.Title, change.Content, change.ContentHTML, userID, change.RestoredFrom)
+	if err != nil {
+		return fmt.Errorf("insert document revision: %w", err)
+	}
+	return nil
+}
+
+// Move re-parents a document. The new parent must be a live page from the same
+// team (or also workspace-wide) and must not sit inside the document's own subtree.
+func (r *repo) Move(ctx context.Context, id, workspaceID uuid.UUID, parentID *uuid.UUID) error {
+	r.log.Info(ctx, "business.repository.documents.Move")
+	ctx, span := web.AddSpan(ctx, "business.repository.documents.Move")
+	defer span.End()
+
+	query := subtreeCTE + `
+		UPDATE documents d
+		SET parent_id = $3, updated_at = NOW()
+		WHERE d.id = $1 AND d.workspace_id = $2 AND d.deleted_at IS NULL
+			AND (
+				CAST($3 AS uuid) IS NULL OR EXISTS (
+					SELECT 1 FROM documents p
+					WHERE p.id = $3
+						AND p.workspace_id = $2
+						AND p.deleted_at IS NULL
+						AND p.team_id IS NOT DISTINCT FROM d.team_id
+						AND p.id NOT IN (SELECT id FROM subtree)
+				)
+			)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, workspaceID, parentID)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to move document: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to move document"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		if _, getErr := r.Get(ctx, id, workspaceID); getErr != nil {
+			return getErr
+		}
+		return documents.ErrInvalidParent
+	}
+	return nil
+}
+
+// Delete soft deletes a document and every live sub-page beneath it.
+func (r *repo) Delete(ctx context.Context, id, workspaceID uuid.UUID) error {
+	r.log.Info(ctx, "business.repository.documents.Delete")
+	ctx, span := web.AddSpan(ctx, "business.repository.documents.Delete")
+	defer span.End()
+
+	query := subtreeCTE + `
+		UPDATE documents SET deleted_at = NOW()
+		WHERE id IN (SELECT id FROM subtree)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, workspaceID)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to delete document: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to delete document"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err == nil && rows == 0 {
+		return documents.ErrNotFound
+	}
+
+	span.AddEvent("document deleted", trace.WithAttributes(
+		attribute.String("document.id", id.String()),
+		attribute.Int64("document.deleted_count", rows),
+	))
+	return nil
+}
+
+// AddLink links a document to a story or objective, checking the target is in the same workspace.
+func (r *repo) AddLink(ctx context.Context, id, workspaceID, userID uuid.UUID, targetType string, targetID uuid.UUID) (documents.CoreDocumentLink, error) {
+	r.log.Info(ctx, "business.repository.documents.AddLink")
+	ctx, span := web.AddSpan(ctx, "business.repository.documents.AddLink")
+	defer span.End()
+
+	var column, targetCheck string
+	switch targetType {
+	case documents.LinkTargetStory:
+		column = "story_id"
+		targetCheck = "SELECT 1 FROM stories WHERE id = $2 AND workspace_id = $3 AND deleted_at IS NULL"
+	case documents.LinkTargetObjective:
+		column = "objective_id"
+		targetCheck = "SELECT 1 FROM objectives WHERE objective_id = $2 AND workspace_id = $3"
+	default:
+		return documents.CoreDocumentLink{}, documents.ErrInvalidLinkTarget
+	}
+
+	query := fmt.Sprintf(`
+		WITH inserted AS (
+			INSERT INTO document_links (document_id, %s, created_by)
+			SELECT CAST($1 AS uuid), CAST($2 AS uuid), CAST($4 AS uuid)
+			WHERE EXISTS (%s)
+			RETURNING *
+		)
+		SELECT %s FROM inserted l
+	`, column, targetCheck, linkColumns)
+
+	var row dbDocumentLink
+	if err := r.db.GetContext(ctx, &row, query, id, targetID, workspaceID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return documents.CoreDocumentLink{}, documents.ErrInvalidLinkTarget
+		}
+		if strings.Contains(err.Error(), "document_links_story_unique") || strings.Contains(err.Error(), "document_links_objective_unique") {
+			return documents.CoreDocumentLink{}, documents.ErrLinkExists
+		}
+		errMsg := fmt.Sprintf("failed to add document link: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to add document link"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return documents.CoreDocumentLink{}, err
+	}
+
+	return documents.CoreDocumentLink(row), nil
+}
+
+func (r *repo) RemoveLink(ctx context.Context, id, workspaceID, linkID uuid.UUID) error {
+	r.log.Info(ctx, "business.repository.documents.RemoveLink")
+	ctx, span := web.AddSpan(ctx, "business.repository.documents.RemoveLink")
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM document_links l
+		USING documents d
+		WHERE l.id = $1 AND l.document_id = $2 AND d.id = l.document_id AND d.workspace_id = $3
+	`, linkID, id, workspaceID)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to remove document link: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to remove document link"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return documents.ErrLinkNotFound
+	}
+	return nil
+}
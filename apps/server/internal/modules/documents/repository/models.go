@@ -9,24 +9,56 @@ import (
 
 type dbDocument struct {
 	ID          uuid.UUID  `db:"id"`
-	Name        string     `db:"name"`
-	Description string     `db:"description"`
-	Owner       *uuid.UUID `db:"owner"`
-	StartDate   *time.Time `db:"start_date"`
-	EndDate     *time.Time `db:"end_date"`
+	WorkspaceID uuid.UUID  `db:"workspace_id"`
+	TeamID      *uuid.UUID `db:"team_id"`
+	ParentID    *uuid.UUID `db:"parent_id"`
+	Title       string     `db:"title"`
+	Content     string     `db:"content"`
+	ContentHTML string     `db:"content_html"`
+	Icon        *string    `db:"icon"`
+	Revision    int        `db:"revision"`
+	ChildCount  int        `db:"child_count"`
+	CreatedBy   *uuid.UUID `db:"created_by"`
+	UpdatedBy   *uuid.UUID `db:"updated_by"`
 	CreatedAt   time.Time  `db:"created_at"`
 	UpdatedAt   time.Time  `db:"updated_at"`
-	DeletedAt   *time.Time `db:"deleted_at"`
+}
+
+type dbRevision struct {
+	ID           uuid.UUID  `db:"id"`
+	DocumentID   uuid.UUID  `db:"document_id"`
+	Revision     int        `db:"revision"`
+	Title        string     `db:"title"`
+	Content      string     `db:"content"`
+	ContentHTML  string     `db:"content_html"`
+	AuthorID     *uuid.UUID `db:"author_id"`
+	RestoredFrom *int       `db:"restored_from"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+type dbDocumentLink struct {
+	ID         uuid.UUID  `db:"id"`
+	DocumentID uuid.UUID  `db:"document_id"`
+	TargetType string     `db:"target_type"`
+	TargetID   uuid.UUID  `db:"target_id"`
+	CreatedBy  *uuid.UUID `db:"created_by"`
+	CreatedAt  time.Time  `db:"created_at"`
 }
 
 func toCoreDocument(p dbDocument) documents.CoreDocument {
 	return documents.CoreDocument{
 		ID:          p.ID,
-		Name:        p.Name,
-		Description: p.Description,
-		Owner:       p.Owner,
-		StartDate:   p.StartDate,
-		EndDate:     p.EndDate,
+		WorkspaceID: p.WorkspaceID,
+		TeamID:      p.TeamID,
+		ParentID:    p.ParentID,
+		Title:       p.Title,
+		Content:     p.Content,
+		ContentHTML: p.ContentHTML,
+		Icon:        p.Icon,
+		Revision:    p.Revision,
+		ChildCount:  p.ChildCount,
+		CreatedBy:   p.CreatedBy,
+		UpdatedBy:   p.UpdatedBy,
 		CreatedAt:   p.CreatedAt,
 		UpdatedAt:   p.UpdatedAt,
 	}
@@ -39,3 +71,19 @@ func toCoreDocuments(do []dbDocument) []documents.CoreDocument {
 	}
 	return documents
 }
+
+func toCoreRevisions(rows []dbRevision) []documents.CoreRevision {
+	revisions := make([]documents.CoreRevision, len(rows))
+	for i, r := range rows {
+		revisions[i] = documents.CoreRevision(r)
+	}
+	return revisions
+}
+
+func toCoreDocumentLinks(rows []dbDocumentLink) []documents.CoreDocumentLink {
+	links := make([]documents.CoreDocumentLink, len(rows))
+	for i, l := range rows {
+		links[i] = documents.CoreDocumentLink(l)
+	}
+	return links
+}
@@ -2,19 +2,230 @@ package documentsrepository
 
 import (
 	"context"
-	"time"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 
 	documents "github.com/complexus-tech/projects-api/internal/modules/documents/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func (r *repo) List(ctx context.Context) ([]documents.CoreDocument, error) {
+const documentColumns = `
+		d.id,
+		d.workspace_id,
+		d.team_id,
+		d.parent_id,
+		d.title,
+		d.icon,
+		d.revision,
+		d.created_by,
+		d.updated_by,
+		d.created_at,
+		d.updated_at,
+		(
+			SELECT COUNT(*) FROM documents c
+			WHERE c.parent_id = d.id AND c.deleted_at IS NULL
+		) AS child_count`
 
-	p := []dbDocument{
-		{ID: uuid.New(), Name: "Document 1", Description: "This is document 1", CreatedAt: time.Now(), UpdatedAt: time.Now()},
-		{ID: uuid.New(), Name: "Document 2", Description: "This is document 2", CreatedAt: time.Now(), UpdatedAt: time.Now()},
-		{ID: uuid.New(), Name: "Document 3", Description: "This is document 3", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+const linkColumns = `
+		l.id,
+		l.document_id,
+		CASE WHEN l.story_id IS NOT NULL THEN 'story' ELSE 'objective' END AS target_type,
+		COALESCE(l.story_id, l.objective_id) AS target_id,
+		l.created_by,
+		l.created_at`
+
+// List returns documents without their content; use Get for the full page.
+func (r *repo) List(ctx context.Context, workspaceID, userID uuid.UUID, filters documents.CoreDocumentFilters) ([]documents.CoreDocument, error) {
+	r.log.Info(ctx, "business.repository.documents.List")
+	ctx, span := web.AddSpan(ctx, "business.repository.documents.List")
+	defer span.End()
+
+	// Documents of private teams are only listed for the team's members.
+	whereClauses := []string{
+		"d.workspace_id = :workspace_id",
+		"d.deleted_at IS NULL",
+		`(d.team_id IS NULL OR EXISTS (
+			SELECT 1 FROM teams t
+			WHERE t.team_id = d.team_id
+				AND (t.is_private = false OR EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = t.team_id AND tm.user_id = :user_id))
+		))`,
+	}
+	params := map[string]any{"workspace_id": workspaceID, "user_id": userID}
+
+	if filters.TeamID != nil {
+		whereClauses = append(whereClauses, "d.team_id = :team_id")
+		params["team_id"] = *filters.TeamID
+	}
+	if filters.WorkspaceOnly {
+		whereClauses = append(whereClauses, "d.team_id IS NULL")
+	}
+	if filters.ParentID != nil {
+		whereClauses = append(whereClauses, "d.parent_id = :parent_id")
+		params["parent_id"] = *filters.ParentID
+	}
+	if filters.RootOnly {
+		whereClauses = append(whereClauses, "d.parent_id IS NULL")
+	}
+	if filters.StoryID != nil {
+		whereClauses = append(whereClauses, "EXISTS (SELECT 1 FROM document_links l WHERE l.document_id = d.id AND l.story_id = :story_id)")
+		params["story_id"] = *filters.StoryID
+	}
+	if filters.ObjectiveID != nil {
+		whereClauses = append(whereClauses, "EXISTS (SELECT 1 FROM document_links l WHERE l.document_id = d.id AND l.objective_id = :objective_id)")
+		params["objective_id"] = *filters.ObjectiveID
+	}
+
+	query := "SELECT " + documentColumns + " FROM documents d WHERE " +
+		strings.Join(whereClauses, " AND ") + " ORDER BY d.title ASC, d.created_at ASC"
+
+	stmt, err := r.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to prepare named statement: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to prepare statement"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var rows []dbDocument
+	if err := stmt.SelectContext(ctx, &rows, params); err != nil {
+		errMsg := fmt.Sprintf("failed to list documents: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to list documents"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	return toCoreDocuments(rows), nil
+}
+
+func (r *repo) Get(ctx context.Context, id, workspaceID uuid.UUID) (documents.CoreDocument, error) {
+	r.log.Info(ctx, "business.repository.documents.Get")
+	ctx, span := web.AddSpan(ctx, "business.repository.documents.Get")
+	defer span.End()
+
+	query := "SELECT " + documentColumns + `, d.content, d.content_html
+		FROM documents d
+		WHERE d.id = $1 AND d.workspace_id = $2 AND d.deleted_at IS NULL`
+
+	var row dbDocument
+	if err := r.db.GetContext(ctx, &row, query, id, workspaceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return documents.CoreDocument{}, documents.ErrNotFound
+		}
+		errMsg := fmt.Sprintf("failed to get document: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get document"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return documents.CoreDocument{}, err
+	}
+
+	return toCoreDocument(row), nil
+}
+
+// ListRevisions returns revisions newest first.
+func (r *repo) ListRevisions(ctx context.Context, id, workspaceID uuid.UUID) ([]documents.CoreRevision, error) {
+	r.log.Info(ctx, "business.repository.documents.ListRevisions")
+	ctx, span := web.AddSpan(ctx, "business.repository.documents.ListRevisions")
+	defer span.End()
+
+	query := `
+		SELECT
+			dr.id, dr.document_id, dr.revision, dr.title, dr.content, dr.content_html,
+			dr.author_id, dr.restored_from, dr.created_at
+		FROM document_revisions dr
+		INNER JOIN documents d ON d.id = dr.document_id
+		WHERE dr.document_id = $1 AND d.workspace_id = $2 AND d.deleted_at IS NULL
+		ORDER BY dr.revision DESC
+	`
+
+	var rows []dbRevision
+	if err := r.db.SelectContext(ctx, &rows, query, id, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to list document revisions: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to list document revisions"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	return toCoreRevisions(rows), nil
+}
+
+func (r *repo) GetRevision(ctx context.Context, id, workspaceID uuid.UUID, revision int) (documents.CoreRevision, error) {
+	r.log.Info(ctx, "business.repository.documents.GetRevision")
+	ctx, span := web.AddSpan(ctx, "business.repository.documents.GetRevision")
+	defer span.End()
+
+	query := `
+		SELECT
+			dr.id, dr.document_id, dr.revision, dr.title, dr.content, dr.content_html,
+			dr.author_id, dr.restored_from, dr.created_at
+		FROM document_revisions dr
+		INNER JOIN documents d ON d.id = dr.document_id
+		WHERE dr.document_id = $1 AND d.workspace_id = $2 AND d.deleted_at IS NULL
+			AND dr.revision = $3
+	`
+
+	var row dbRevision
+	if err := r.db.GetContext(ctx, &row, query, id, workspaceID, revision); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return documents.CoreRevision{}, documents.ErrRevisionNotFound
+		}
+		errMsg := fmt.Sprintf("failed to get document revision: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get document revision"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return documents.CoreRevision{}, err
+	}
+
+	return documents.CoreRevision(row), nil
+}
+
+func (r *repo) ListLinks(ctx context.Context, id, workspaceID uuid.UUID) ([]documents.CoreDocumentLink, error) {
+	r.log.Info(ctx, "business.repository.documents.ListLinks")
+	ctx, span := web.AddSpan(ctx, "business.repository.documents.ListLinks")
+	defer span.End()
+
+	query := "SELECT " + linkColumns + `
+		FROM document_links l
+		INNER JOIN documents d ON d.id = l.document_id
+		WHERE l.document_id = $1 AND d.workspace_id = $2
+		ORDER BY l.created_at ASC`
+
+	var rows []dbDocumentLink
+	if err := r.db.SelectContext(ctx, &rows, query, id, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to list document links: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to list document links"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	return toCoreDocumentLinks(rows), nil
+}
+
+// CanAccessTeam reports whether the user may see the team's documents. Only
+// private teams are closed, and only to users who are not members; an unknown
+// team is left to the caller to reject.
+func (r *repo) CanAccessTeam(ctx context.Context, workspaceID, teamID, userID uuid.UUID) (bool, error) {
+	r.log.Info(ctx, "business.repository.documents.CanAccessTeam")
+	ctx, span := web.AddSpan(ctx, "business.repository.documents.CanAccessTeam")
+	defer span.End()
+
+	var allowed bool
+	query := `
+		SELECT NOT EXISTS (
+			SELECT 1
+			FROM teams t
+			WHERE t.team_id = $2 AND t.workspace_id = $1 AND t.is_private = true
+				AND NOT EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = t.team_id AND tm.user_id = $3)
+		)`
+	if err := r.db.GetContext(ctx, &allowed, query, workspaceID, teamID, userID); err != nil {
+		errMsg := fmt.Sprintf("failed to check team access: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to check team access"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return false, err
 	}
 
-	return toCoreDocuments(p), nil
+	return allowed, nil
 }
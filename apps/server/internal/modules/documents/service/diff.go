@@ -0,0 +1,41 @@
+package documents
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// diffRevisions builds a unified diff of the markdown content of two revisions.
+// go-difflib was already in the module graph through testify, and the story
+// description diff uses the same matcher, so the two histories agree.
+func diffRevisions(from, to CoreRevision) CoreRevisionDiff {
+	result := CoreRevisionDiff{
+		DocumentID: from.DocumentID,
+		From:       from.Revision,
+		To:         to.Revision,
+		FromTitle:  from.Title,
+		ToTitle:    to.Title,
+	}
+
+	// GetUnifiedDiffString only fails when writing to its buffer, which cannot happen.
+	result.Diff, _ = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from.Content),
+		B:        difflib.SplitLines(to.Content),
+		FromFile: fmt.Sprintf("revision %d", from.Revision),
+		ToFile:   fmt.Sprintf("revision %d", to.Revision),
+		Context:  3,
+	})
+
+	for _, line := range strings.Split(result.Diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			result.Additions++
+		case strings.HasPrefix(line, "-"):
+			result.Deletions++
+		}
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+package documents
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	documentID := uuid.New()
+	from := CoreRevision{DocumentID: documentID, Revision: 1, Title: "Spec", Content: "# Spec\n\nFirst draft.\nKeep me.\n"}
+	to := CoreRevision{DocumentID: documentID, Revision: 2, Title: "Spec v2", Content: "# Spec\n\nSecond draft.\nKeep me.\nNew line.\n"}
+
+	diff := diffRevisions(from, to)
+
+	if diff.Additions != 2 || diff.Deletions != 1 {
+		t.Fatalf("expected 2 additions and 1 deletion, got +%d -%d\n%s", diff.Additions, diff.Deletions, diff.Diff)
+	}
+	if !strings.Contains(diff.Diff, "-First draft.") || !strings.Contains(diff.Diff, "+Second draft.") {
+		t.Fatalf("unexpected diff:\n%s", diff.Diff)
+	}
+	if diff.FromTitle != "Spec" || diff.ToTitle != "Spec v2" {
+		t.Fatalf("unexpected titles %q -> %q", diff.FromTitle, diff.ToTitle)
+	}
+}
+
+func TestDiffRevisionsUnchanged(t *testing.T) {
+	rev := CoreRevision{Revision: 3, Content: "same\n"}
+
+	diff := diffRevisions(rev, rev)
+
+	if diff.Diff != "" || diff.Additions != 0 || diff.Deletions != 0 {
+		t.Fatalf("expected an empty diff, got %+v", diff)
+	}
+}
@@ -2,25 +2,53 @@ package documents
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"github.com/complexus-tech/projects-api/pkg/logger"
 	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// Service errors
+var (
+	ErrNotFound          = errors.New("document not found")
+	ErrRevisionNotFound  = errors.New("document revision not found")
+	ErrLinkNotFound      = errors.New("document link not found")
+	ErrTitleRequired     = errors.New("document title is required")
+	ErrInvalidTeam       = errors.New("team does not belong to this workspace")
+	ErrInvalidParent     = errors.New("parent document must be in the same team and cannot be the document itself or one of its sub-pages")
+	ErrRevisionConflict  = errors.New("document was changed by someone else, reload and try again")
+	ErrInvalidLinkTarget = errors.New("link target must be a story or objective in this workspace")
+	ErrLinkExists        = errors.New("document is already linked to this target")
+	ErrTeamAccessDenied  = errors.New("only members of this private team can use its documents")
+)
+
 // Repository provides access to the documents storage.
 type Repository interface {
-	List(ctx context.Context) ([]CoreDocument, error)
+	List(ctx context.Context, workspaceID, userID uuid.UUID, filters CoreDocumentFilters) ([]CoreDocument, error)
+	Get(ctx context.Context, id, workspaceID uuid.UUID) (CoreDocument, error)
+	Create(ctx context.Context, workspaceID, userID uuid.UUID, nd CoreNewDocument) (CoreDocument, error)
+	Update(ctx context.Context, id, workspaceID, userID uuid.UUID, change CoreDocumentChange) error
+	Move(ctx context.Context, id, workspaceID uuid.UUID, parentID *uuid.UUID) error
+	Delete(ctx context.Context, id, workspaceID uuid.UUID) error
+	ListRevisions(ctx context.Context, id, workspaceID uuid.UUID) ([]CoreRevision, error)
+	GetRevision(ctx context.Context, id, workspaceID uuid.UUID, revision int) (CoreRevision, error)
+	ListLinks(ctx context.Context, id, workspaceID uuid.UUID) ([]CoreDocumentLink, error)
+	AddLink(ctx context.Context, id, workspaceID, userID uuid.UUID, targetType string, targetID uuid.UUID) (CoreDocumentLink, error)
+	RemoveLink(ctx context.Context, id, workspaceID, linkID uuid.UUID) error
+	CanAccessTeam(ctx context.Context, workspaceID, teamID, userID uuid.UUID) (bool, error)
 }
 
-// Service provides story-related operations.
+// Service provides document-related operations.
 type Service struct {
 	repo Repository
 	log  *logger.Logger
 }
 
-// New constructs a new stories service instance with the provided repository.
+// New constructs a new documents service instance with the provided repository.
 func New(log *logger.Logger, repo Repository) *Service {
 	return &Service{
 		repo: repo,
@@ -28,19 +56,344 @@ func New(log *logger.Logger, repo Repository) *Service {
 	}
 }
 
-// List returns a list of documents.
-func (s *Service) List(ctx context.Context) ([]CoreDocument, error) {
+// List returns the documents in a workspace without their content, leaving
+// out those of private teams the user is not a member of.
+func (s *Service) List(ctx context.Context, workspaceID, userID uuid.UUID, filters CoreDocumentFilters) ([]CoreDocument, error) {
 	s.log.Info(ctx, "business.core.documents.list")
 	ctx, span := web.AddSpan(ctx, "business.core.documents.List")
 	defer span.End()
 
-	documents, err := s.repo.List(ctx)
+	documents, err := s.repo.List(ctx, workspaceID, userID, filters)
 	if err != nil {
 		span.RecordError(err)
 		return nil, err
 	}
 	span.AddEvent("documents retrieved.", trace.WithAttributes(
-		attribute.Int("story.count", len(documents)),
+		attribute.Int("document.count", len(documents)),
 	))
 	return documents, nil
 }
+
+// Get returns a single document with its content.
+func (s *Service) Get(ctx context.Context, id, workspaceID, userID uuid.UUID) (CoreDocument, error) {
+	s.log.Info(ctx, "business.core.documents.get")
+	ctx, span := web.AddSpan(ctx, "business.core.documents.Get")
+	defer span.End()
+
+	document, err := s.getDocument(ctx, id, workspaceID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreDocument{}, err
+	}
+	return document, nil
+}
+
+// Create adds a document and records its first revision.
+func (s *Service) Create(ctx context.Context, workspaceID, userID uuid.UUID, nd CoreNewDocument) (CoreDocument, error) {
+	s.log.Info(ctx, "business.core.documents.create")
+	ctx, span := web.AddSpan(ctx, "business.core.documents.Create")
+	defer span.End()
+
+	nd.Title = strings.TrimSpace(nd.Title)
+	if nd.Title == "" {
+		return CoreDocument{}, ErrTitleRequired
+	}
+	if err := s.checkTeamAccess(ctx, workspaceID, userID, nd.TeamID); err != nil {
+		span.RecordError(err)
+		return CoreDocument{}, err
+	}
+	if nd.ParentID != nil {
+		if _, err := s.getDocument(ctx, *nd.ParentID, workspaceID, userID); err != nil {
+			span.RecordError(err)
+			return CoreDocument{}, err
+		}
+	}
+
+	document, err := s.repo.Create(ctx, workspaceID, userID, nd)
+	if err != nil {
+		span.RecordError(err)
+		return CoreDocument{}, err
+	}
+
+	span.AddEvent("document created.", trace.WithAttributes(
+		attribute.String("document.id", document.ID.String()),
+	))
+	return document, nil
+}
+
+// Update changes a document. A new revision is recorded only when the title or
+// content actually changes.
+func (s *Service) Update(ctx context.Context, id, workspaceID, userID uuid.UUID, ud CoreUpdateDocument) (CoreDocument, error) {
+	s.log.Info(ctx, "business.core.documents.update")
+	ctx, span := web.AddSpan(ctx, "business.core.documents.Update")
+	defer span.End()
+
+	current, err := s.getDocument(ctx, id, workspaceID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreDocument{}, err
+	}
+
+	change := CoreDocumentChange{
+		Title:            current.Title,
+		Content:          current.Content,
+		ContentHTML:      current.ContentHTML,
+		Icon:             current.Icon,
+		ExpectedRevision: current.Revision,
+	}
+	if ud.Title != nil {
+		change.Title = strings.TrimSpace(*ud.Title)
+		if change.Title == "" {
+			return CoreDocument{}, ErrTitleRequired
+		}
+	}
+	if ud.Content != nil {
+		change.Content = *ud.Content
+	}
+	if ud.ContentHTML != nil {
+		change.ContentHTML = *ud.ContentHTML
+	}
+	if ud.Icon != nil {
+		change.Icon = ud.Icon
+	}
+	change.Revise = change.Title != current.Title ||
+		change.Content != current.Content ||
+		change.ContentHTML != current.ContentHTML
+
+	if err := s.repo.Update(ctx, id, workspaceID, userID, change); err != nil {
+		span.RecordError(err)
+		return CoreDocument{}, err
+	}
+	return s.repo.Get(ctx, id, workspaceID)
+}
+
+// Move re-parents a document. A nil parent moves it to the top level.
+func (s *Service) Move(ctx context.Context, id, workspaceID, userID uuid.UUID, parentID *uuid.UUID) (CoreDocument, error) {
+	s.log.Info(ctx, "business.core.documents.move")
+	ctx, span := web.AddSpan(ctx, "business.core.documents.Move")
+	defer span.End()
+
+	if parentID != nil && *parentID == id {
+		return CoreDocument{}, ErrInvalidParent
+	}
+	if _, err := s.getDocument(ctx, id, workspaceID, userID); err != nil {
+		span.RecordError(err)
+		return CoreDocument{}, err
+	}
+	if parentID != nil {
+		if _, err := s.getDocument(ctx, *parentID, workspaceID, userID); err != nil {
+			span.RecordError(err)
+			return CoreDocument{}, err
+		}
+	}
+	if err := s.repo.Move(ctx, id, workspaceID, parentID); err != nil {
+		span.RecordError(err)
+		return CoreDocument{}, err
+	}
+	return s.repo.Get(ctx, id, workspaceID)
+}
+
+// Delete soft deletes a document together with its sub-pages.
+func (s *Service) Delete(ctx context.Context, id, workspaceID, userID uuid.UUID) error {
+	s.log.Info(ctx, "business.core.documents.delete")
+	ctx, span := web.AddSpan(ctx, "business.core.documents.Delete")
+	defer span.End()
+
+	if _, err := s.getDocument(ctx, id, workspaceID, userID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if err := s.repo.Delete(ctx, id, workspaceID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// ListRevisions returns a document's revisions, newest first.
+func (s *Service) ListRevisions(ctx context.Context, id, workspaceID, userID uuid.UUID) ([]CoreRevision, error) {
+	s.log.Info(ctx, "business.core.documents.listRevisions")
+	ctx, span := web.AddSpan(ctx, "business.core.documents.ListRevisions")
+	defer span.End()
+
+	if _, err := s.getDocument(ctx, id, workspaceID, userID); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	revisions, err := s.repo.ListRevisions(ctx, id, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// GetRevision returns a single revision of a document.
+func (s *Service) GetRevision(ctx context.Context, id, workspaceID, userID uuid.UUID, revision int) (CoreRevision, error) {
+	s.log.Info(ctx, "business.core.documents.getRevision")
+	ctx, span := web.AddSpan(ctx, "business.core.documents.GetRevision")
+	defer span.End()
+
+	if _, err := s.getDocument(ctx, id, workspaceID, userID); err != nil {
+		span.RecordError(err)
+		return CoreRevision{}, err
+	}
+	rev, err := s.repo.GetRevision(ctx, id, workspaceID, revision)
+	if err != nil {
+		span.RecordError(err)
+		return CoreRevision{}, err
+	}
+	return rev, nil
+}
+
+// DiffRevisions compares the content of two revisions of a document.
+func (s *Service) DiffRevisions(ctx context.Context, id, workspaceID, userID uuid.UUID, from, to int) (CoreRevisionDiff, error) {
+	s.log.Info(ctx, "business.core.documents.diffRevisions")
+	ctx, span := web.AddSpan(ctx, "business.core.documents.DiffRevisions")
+	defer span.End()
+
+	if _, err := s.getDocument(ctx, id, workspaceID, userID); err != nil {
+		span.RecordError(err)
+		return CoreRevisionDiff{}, err
+	}
+	fromRev, err := s.repo.GetRevision(ctx, id, workspaceID, from)
+	if err != nil {
+		span.RecordError(err)
+		return CoreRevisionDiff{}, err
+	}
+	toRev, err := s.repo.GetRevision(ctx, id, workspaceID, to)
+	if err != nil {
+		span.RecordError(err)
+		return CoreRevisionDiff{}, err
+	}
+
+	return diffRevisions(fromRev, toRev), nil
+}
+
+// RestoreRevision writes an old revision's title and content back as a new
+// revision, so the restore itself shows up in the history.
+func (s *Service) RestoreRevision(ctx context.Context, id, workspaceID, userID uuid.UUID, revision int) (CoreDocument, error) {
+	s.log.Info(ctx, "business.core.documents.restoreRevision")
+	ctx, span := web.AddSpan(ctx, "business.core.documents.RestoreRevision")
+	defer span.End()
+
+	current, err := s.getDocument(ctx, id, workspaceID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreDocument{}, err
+	}
+	rev, err := s.repo.GetRevision(ctx, id, workspaceID, revision)
+	if err != nil {
+		span.RecordError(err)
+		return CoreDocument{}, err
+	}
+
+	change := CoreDocumentChange{
+		Title:            rev.Title,
+		Content:          rev.Content,
+		ContentHTML:      rev.ContentHTML,
+		Icon:             current.Icon,
+		Revise:           true,
+		RestoredFrom:     &rev.Revision,
+		ExpectedRevision: current.Revision,
+	}
+	if err := s.repo.Update(ctx, id, workspaceID, userID, change); err != nil {
+		span.RecordError(err)
+		return CoreDocument{}, err
+	}
+
+	span.AddEvent("document revision restored.", trace.WithAttributes(
+		attribute.String("document.id", id.String()),
+		attribute.Int("document.revision", revision),
+	))
+	return s.repo.Get(ctx, id, workspaceID)
+}
+
+// ListLinks returns the stories and objectives a document is linked to.
+func (s *Service) ListLinks(ctx context.Context, id, workspaceID, userID uuid.UUID) ([]CoreDocumentLink, error) {
+	s.log.Info(ctx, "business.core.documents.listLinks")
+	ctx, span := web.AddSpan(ctx, "business.core.documents.ListLinks")
+	defer span.End()
+
+	if _, err := s.getDocument(ctx, id, workspaceID, userID); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	links, err := s.repo.ListLinks(ctx, id, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return links, nil
+}
+
+// AddLink links a document to a story or objective in the same workspace.
+func (s *Service) AddLink(ctx context.Context, id, workspaceID, userID uuid.UUID, targetType string, targetID uuid.UUID) (CoreDocumentLink, error) {
+	s.log.Info(ctx, "business.core.documents.addLink")
+	ctx, span := web.AddSpan(ctx, "business.core.documents.AddLink")
+	defer span.End()
+
+	if targetType != LinkTargetStory && targetType != LinkTargetObjective {
+		return CoreDocumentLink{}, ErrInvalidLinkTarget
+	}
+	if _, err := s.getDocument(ctx, id, workspaceID, userID); err != nil {
+		span.RecordError(err)
+		return CoreDocumentLink{}, err
+	}
+
+	link, err := s.repo.AddLink(ctx, id, workspaceID, userID, targetType, targetID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreDocumentLink{}, err
+	}
+	return link, nil
+}
+
+// RemoveLink deletes a link from a document.
+func (s *Service) RemoveLink(ctx context.Context, id, workspaceID, userID, linkID uuid.UUID) error {
+	s.log.Info(ctx, "business.core.documents.removeLink")
+	ctx, span := web.AddSpan(ctx, "business.core.documents.RemoveLink")
+	defer span.End()
+
+	if _, err := s.getDocument(ctx, id, workspaceID, userID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if err := s.repo.RemoveLink(ctx, id, workspaceID, linkID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// getDocument returns a document the user may use. Documents of private teams
+// the user is not a member of are reported as not found.
+func (s *Service) getDocument(ctx context.Context, id, workspaceID, userID uuid.UUID) (CoreDocument, error) {
+	document, err := s.repo.Get(ctx, id, workspaceID)
+	if err != nil {
+		return CoreDocument{}, err
+	}
+	if err := s.checkTeamAccess(ctx, workspaceID, userID, document.TeamID); errors.Is(err, ErrTeamAccessDenied) {
+		return CoreDocument{}, ErrNotFound
+	} else if err != nil {
+		return CoreDocument{}, err
+	}
+	return document, nil
+}
+
+// checkTeamAccess returns ErrTeamAccessDenied when teamID is a private team
+// the user is not a member of. Workspace documents have no team and are open
+// to everyone in the workspace.
+func (s *Service) checkTeamAccess(ctx context.Context, workspaceID, userID uuid.UUID, teamID *uuid.UUID) error {
+	if teamID == nil {
+		return nil
+	}
+	ok, err := s.repo.CanAccessTeam(ctx, workspaceID, *teamID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTeamAccessDenied
+	}
+	return nil
+}
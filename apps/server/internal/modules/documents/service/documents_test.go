@@ -0,0 +1,279 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// documentsRepo keeps one workspace's documents and their revisions in
+// memory, recording a revision on create and on every revised update.
+// privateTeams holds the members of each private team.
+type documentsRepo struct {
+	Repository
+
+	documents    map[uuid.UUID]CoreDocument
+	revisions    map[uuid.UUID][]CoreRevision
+	privateTeams map[uuid.UUID][]uuid.UUID
+	updates      int
+}
+
+func newDocumentsRepo() *documentsRepo {
+	return &documentsRepo{
+		documents:    map[uuid.UUID]CoreDocument{},
+		revisions:    map[uuid.UUID][]CoreRevision{},
+		privateTeams: map[uuid.UUID][]uuid.UUID{},
+	}
+}
+
+func (r *documentsRepo) CanAccessTeam(ctx context.Context, workspaceID, teamID, userID uuid.UUID) (bool, error) {
+	members, private := r.privateTeams[teamID]
+	if !private {
+		return true, nil
+	}
+	for _, member := range members {
+		if member == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *documentsRepo) Get(ctx context.Context, id, workspaceID uuid.UUID) (CoreDocument, error) {
+	document, ok := r.documents[id]
+	if !ok {
+		return CoreDocument{}, ErrNotFound
+	}
+	return document, nil
+}
+
+func (r *documentsRepo) Create(ctx context.Context, workspaceID, userID uuid.UUID, nd CoreNewDocument) (CoreDocument, error) {
+	document := CoreDocument{
+		ID:          uuid.New(),
+		WorkspaceID: workspaceID,
+		TeamID:      nd.TeamID,
+		Title:       nd.Title,
+		Content:     nd.Content,
+		ContentHTML: nd.ContentHTML,
+		Icon:        nd.Icon,
+		Revision:    1,
+	}
+	r.documents[document.ID] = document
+	r.revise(document, userID, nil)
+	return document, nil
+}
+
+func (r *documentsRepo) Update(ctx context.Context, id, workspaceID, userID uuid.UUID, change CoreDocumentChange) error {
+	document, ok := r.documents[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if document.Revision != change.ExpectedRevision {
+		return ErrRevisionConflict
+	}
+	r.updates++
+	document.Title = change.Title
+	document.Content = change.Content
+	document.ContentHTML = change.ContentHTML
+	document.Icon = change.Icon
+	if change.Revise {
+		document.Revision++
+		r.revise(document, userID, change.RestoredFrom)
+	}
+	r.documents[id] = document
+	return nil
+}
+
+func (r *documentsRepo) ListRevisions(ctx context.Context, id, workspaceID uuid.UUID) ([]CoreRevision, error) {
+	revisions := r.revisions[id]
+	newestFirst := make([]CoreRevision, len(revisions))
+	for i, rev := range revisions {
+		newestFirst[len(revisions)-1-i] = rev
+	}
+	return newestFirst, nil
+}
+
+func (r *documentsRepo) GetRevision(ctx context.Context, id, workspaceID uuid.UUID, revision int) (CoreRevision, error) {
+	for _, rev := range r.revisions[id] {
+		if rev.Revision == revision {
+			return rev, nil
+		}
+	}
+	return CoreRevision{}, ErrRevisionNotFound
+}
+
+func (r *documentsRepo) revise(document CoreDocument, authorID uuid.UUID, restoredFrom *int) {
+	r.revisions[document.ID] = append(r.revisions[document.ID], CoreRevision{
+		ID:           uuid.New(),
+		DocumentID:   document.ID,
+		Revision:     document.Revision,
+		Title:        document.Title,
+		Content:      document.Content,
+		ContentHTML:  document.ContentHTML,
+		AuthorID:     &authorID,
+		RestoredFrom: restoredFrom,
+	})
+}
+
+func newDocumentsService(repo *documentsRepo) *Service {
+	return New(logger.NewWithText(io.Discard, slog.LevelError, "test"), repo)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestCreateRecordsFirstRevision(t *testing.T) {
+	repo := newDocumentsRepo()
+	service := newDocumentsService(repo)
+	workspaceID := uuid.New()
+
+	if _, err := service.Create(context.Background(), workspaceID, uuid.New(), CoreNewDocument{Title: "   "}); !errors.Is(err, ErrTitleRequired) {
+		t.Fatalf("expected %v for a blank title, got %v", ErrTitleRequired, err)
+	}
+
+	document, err := service.Create(context.Background(), workspaceID, uuid.New(), CoreNewDocument{Title: " Spec ", Content: "# Spec\n"})
+	if err != nil {
+		t.Fatalf("expected document to be created, got error: %v", err)
+	}
+	if document.Title != "Spec" {
+		t.Errorf("got title %q, want it trimmed", document.Title)
+	}
+
+	revisions, err := service.ListRevisions(context.Background(), document.ID, workspaceID, uuid.New())
+	if err != nil {
+		t.Fatalf("expected revisions, got error: %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Revision != 1 || revisions[0].Content != "# Spec\n" {
+		t.Fatalf("expected the first revision with the content, got %+v", revisions)
+	}
+}
+
+func TestUpdateRevisesOnlyWhenContentChanges(t *testing.T) {
+	repo := newDocumentsRepo()
+	service := newDocumentsService(repo)
+	workspaceID := uuid.New()
+	document, err := service.Create(context.Background(), workspaceID, uuid.New(), CoreNewDocument{Title: "Spec", Content: "First draft.\n"})
+	if err != nil {
+		t.Fatalf("expected document to be created, got error: %v", err)
+	}
+
+	updated, err := service.Update(context.Background(), document.ID, workspaceID, uuid.New(), CoreUpdateDocument{Icon: stringPtr("📄")})
+	if err != nil {
+		t.Fatalf("expected icon update, got error: %v", err)
+	}
+	if updated.Revision != 1 || len(repo.revisions[document.ID]) != 1 {
+		t.Fatalf("expected an icon change to keep revision 1, got revision %d with %d revisions", updated.Revision, len(repo.revisions[document.ID]))
+	}
+
+	updated, err = service.Update(context.Background(), document.ID, workspaceID, uuid.New(), CoreUpdateDocument{Content: stringPtr("Second draft.\n")})
+	if err != nil {
+		t.Fatalf("expected content update, got error: %v", err)
+	}
+	if updated.Revision != 2 || updated.Content != "Second draft.\n" || *updated.Icon != "📄" {
+		t.Fatalf("expected revision 2 with the new content and the icon kept, got %+v", updated)
+	}
+
+	revisions, err := service.ListRevisions(context.Background(), document.ID, workspaceID, uuid.New())
+	if err != nil {
+		t.Fatalf("expected revisions, got error: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Revision != 2 || revisions[1].Revision != 1 {
+		t.Fatalf("expected revisions 2 and 1, newest first, got %+v", revisions)
+	}
+
+	if _, err := service.Update(context.Background(), document.ID, workspaceID, uuid.New(), CoreUpdateDocument{Title: stringPtr(" ")}); !errors.Is(err, ErrTitleRequired) {
+		t.Fatalf("expected %v for a blank title, got %v", ErrTitleRequired, err)
+	}
+}
+
+func TestListRevisionsOfUnknownDocument(t *testing.T) {
+	service := newDocumentsService(newDocumentsRepo())
+
+	if _, err := service.ListRevisions(context.Background(), uuid.New(), uuid.New(), uuid.New()); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected %v, got %v", ErrNotFound, err)
+	}
+}
+
+func TestRestoreRevisionAddsNewRevision(t *testing.T) {
+	repo := newDocumentsRepo()
+	service := newDocumentsService(repo)
+	workspaceID, userID := uuid.New(), uuid.New()
+	document, err := service.Create(context.Background(), workspaceID, uuid.New(), CoreNewDocument{Title: "Spec", Content: "First draft.\n", Icon: stringPtr("📄")})
+	if err != nil {
+		t.Fatalf("expected document to be created, got error: %v", err)
+	}
+	if _, err := service.Update(context.Background(), document.ID, workspaceID, uuid.New(), CoreUpdateDocument{Title: stringPtr("Spec v2"), Content: stringPtr("Second draft.\n")}); err != nil {
+		t.Fatalf("expected content update, got error: %v", err)
+	}
+
+	restored, err := service.RestoreRevision(context.Background(), document.ID, workspaceID, userID, 1)
+	if err != nil {
+		t.Fatalf("expected revision to be restored, got error: %v", err)
+	}
+	if restored.Revision != 3 || restored.Title != "Spec" || restored.Content != "First draft.\n" || *restored.Icon != "📄" {
+		t.Fatalf("expected revision 3 with the first revision's title and content, got %+v", restored)
+	}
+
+	latest, err := service.GetRevision(context.Background(), document.ID, workspaceID, userID, 3)
+	if err != nil {
+		t.Fatalf("expected the restore revision, got error: %v", err)
+	}
+	if latest.RestoredFrom == nil || *latest.RestoredFrom != 1 || *latest.AuthorID != userID {
+		t.Fatalf("expected revision 3 restored from 1 by %s, got %+v", userID, latest)
+	}
+}
+
+func TestRestoreUnknownRevision(t *testing.T) {
+	repo := newDocumentsRepo()
+	service := newDocumentsService(repo)
+	workspaceID := uuid.New()
+	document, err := service.Create(context.Background(), workspaceID, uuid.New(), CoreNewDocument{Title: "Spec"})
+	if err != nil {
+		t.Fatalf("expected document to be created, got error: %v", err)
+	}
+
+	if _, err := service.RestoreRevision(context.Background(), document.ID, workspaceID, uuid.New(), 7); !errors.Is(err, ErrRevisionNotFound) {
+		t.Fatalf("expected %v, got %v", ErrRevisionNotFound, err)
+	}
+	if repo.updates != 0 {
+		t.Fatalf("expected no write, got %d", repo.updates)
+	}
+}
+
+func TestPrivateTeamDocumentsAreHiddenFromNonMembers(t *testing.T) {
+	repo := newDocumentsRepo()
+	service := newDocumentsService(repo)
+	workspaceID, teamID, memberID, outsiderID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	repo.privateTeams[teamID] = []uuid.UUID{memberID}
+
+	if _, err := service.Create(context.Background(), workspaceID, outsiderID, CoreNewDocument{Title: "Roadmap", TeamID: &teamID}); !errors.Is(err, ErrTeamAccessDenied) {
+		t.Fatalf("expected %v for a non-member, got %v", ErrTeamAccessDenied, err)
+	}
+
+	document, err := service.Create(context.Background(), workspaceID, memberID, CoreNewDocument{Title: "Roadmap", TeamID: &teamID})
+	if err != nil {
+		t.Fatalf("expected document to be created, got error: %v", err)
+	}
+	if _, err := service.Get(context.Background(), document.ID, workspaceID, memberID); err != nil {
+		t.Fatalf("expected the member to get the document, got error: %v", err)
+	}
+
+	if _, err := service.Get(context.Background(), document.ID, workspaceID, outsiderID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected %v for a non-member, got %v", ErrNotFound, err)
+	}
+	if _, err := service.Update(context.Background(), document.ID, workspaceID, outsiderID, CoreUpdateDocument{Content: stringPtr("Leaked.\n")}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected %v for a non-member, got %v", ErrNotFound, err)
+	}
+	if _, err := service.Create(context.Background(), workspaceID, outsiderID, CoreNewDocument{Title: "Notes", ParentID: &document.ID}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected %v for a child of a hidden document, got %v", ErrNotFound, err)
+	}
+	if repo.updates != 0 {
+		t.Fatalf("expected no write, got %d", repo.updates)
+	}
+}
@@ -6,13 +6,99 @@ import (
 	"github.com/google/uuid"
 )
 
+// Link target types.
+const (
+	LinkTargetStory     = "story"
+	LinkTargetObjective = "objective"
+)
+
+// CoreDocument is a wiki page. Pages without a team belong to the whole workspace.
 type CoreDocument struct {
 	ID          uuid.UUID
-	Name        string
-	Description string
-	Owner       *uuid.UUID
-	StartDate   *time.Time
-	EndDate     *time.Time
+	WorkspaceID uuid.UUID
+	TeamID      *uuid.UUID
+	ParentID    *uuid.UUID
+	Title       string
+	Content     string
+	ContentHTML string
+	Icon        *string
+	Revision    int
+	ChildCount  int
+	CreatedBy   *uuid.UUID
+	UpdatedBy   *uuid.UUID
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
+
+type CoreNewDocument struct {
+	TeamID      *uuid.UUID
+	ParentID    *uuid.UUID
+	Title       string
+	Content     string
+	ContentHTML string
+	Icon        *string
+}
+
+// CoreUpdateDocument holds the fields to change. Nil fields are left as they are.
+type CoreUpdateDocument struct {
+	Title       *string
+	Content     *string
+	ContentHTML *string
+	Icon        *string
+}
+
+// CoreDocumentChange is the full state written by the repository. When Revise
+// is set the revision number is bumped and a revision row is recorded.
+type CoreDocumentChange struct {
+	Title            string
+	Content          string
+	ContentHTML      string
+	Icon             *string
+	Revise           bool
+	RestoredFrom     *int
+	ExpectedRevision int
+}
+
+// CoreDocumentFilters narrows List results. Nil fields are ignored.
+type CoreDocumentFilters struct {
+	TeamID        *uuid.UUID
+	WorkspaceOnly bool
+	ParentID      *uuid.UUID
+	RootOnly      bool
+	StoryID       *uuid.UUID
+	ObjectiveID   *uuid.UUID
+}
+
+// CoreRevision is a snapshot of a document taken whenever its title or content changes.
+type CoreRevision struct {
+	ID           uuid.UUID
+	DocumentID   uuid.UUID
+	Revision     int
+	Title        string
+	Content      string
+	ContentHTML  string
+	AuthorID     *uuid.UUID
+	RestoredFrom *int
+	CreatedAt    time.Time
+}
+
+// CoreRevisionDiff compares the markdown content of two revisions.
+type CoreRevisionDiff struct {
+	DocumentID uuid.UUID
+	From       int
+	To         int
+	FromTitle  string
+	ToTitle    string
+	Diff       string
+	Additions  int
+	Deletions  int
+}
+
+type CoreDocumentLink struct {
+	ID         uuid.UUID
+	DocumentID uuid.UUID
+	TargetType string
+	TargetID   uuid.UUID
+	CreatedBy  *uuid.UUID
+	CreatedAt  time.Time
+}
@@ -2,11 +2,19 @@ package epicshttp
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 
 	epics "github.com/complexus-tech/projects-api/internal/modules/epics/service"
 	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
 	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidEpicID = errors.New("epic id is not in its proper form")
+	ErrInvalidFilter = errors.New("teamId, objectiveId and leadUserId must be valid ids")
 )
 
 type Handlers struct {
@@ -20,15 +28,159 @@ func New(epics *epics.Service) *Handlers {
 }
 
 func (h *Handlers) List(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	_, err := mid.GetWorkspace(ctx)
+	ctx, span := web.AddSpan(ctx, "epicshttp.handlers.List")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	query := r.URL.Query()
+	var filters epics.CoreEpicFilters
+	for param, target := range map[string]**uuid.UUID{
+		"teamId":      &filters.TeamID,
+		"objectiveId": &filters.ObjectiveID,
+		"leadUserId":  &filters.LeadUserID,
+	} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return web.RespondError(ctx, w, ErrInvalidFilter, http.StatusBadRequest)
+		}
+		*target = &id
+	}
+	if status := query.Get("status"); status != "" {
+		filters.Status = &status
+	}
+
+	epicsList, err := h.epics.List(ctx, workspace.ID, filters)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppEpics(epicsList), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) Get(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "epicshttp.handlers.Get")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	epicID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidEpicID, http.StatusBadRequest)
+	}
+
+	epic, err := h.epics.Get(ctx, epicID, workspace.ID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppEpic(epic), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "epicshttp.handlers.Create")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	var ne AppNewEpic
+	if err := web.Decode(r, &ne); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	epic, err := h.epics.Create(ctx, workspace.ID, userID, toCoreNewEpic(ne))
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppEpic(epic), http.StatusCreated)
+	return nil
+}
+
+func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "epicshttp.handlers.Update")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	epicID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidEpicID, http.StatusBadRequest)
+	}
+
+	var requestData map[string]json.RawMessage
+	if err := web.Decode(r, &requestData); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	updates, err := getUpdates(requestData)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	epic, err := h.epics.Update(ctx, epicID, workspace.ID, updates)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppEpic(epic), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "epicshttp.handlers.Delete")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
 	if err != nil {
 		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
 	}
 
-	epics, err := h.epics.List(ctx)
+	epicID, err := uuid.Parse(web.Params(r, "id"))
 	if err != nil {
-		return err
+		return web.RespondError(ctx, w, ErrInvalidEpicID, http.StatusBadRequest)
+	}
+
+	if err := h.epics.Delete(ctx, epicID, workspace.ID); err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
 	}
-	web.Respond(ctx, w, toAppEpics(epics), http.StatusOK)
+	web.Respond(ctx, w, nil, http.StatusNoContent)
 	return nil
 }
+
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, epics.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, epics.ErrNameExists):
+		return http.StatusConflict
+	case errors.Is(err, epics.ErrInvalidStatus),
+		errors.Is(err, epics.ErrInvalidDates),
+		errors.Is(err, epics.ErrNameRequired),
+		errors.Is(err, epics.ErrInvalidTeam),
+		errors.Is(err, epics.ErrInvalidLead),
+		errors.Is(err, epics.ErrFieldNotUpdatable):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,26 +1,122 @@
 package epicshttp
 
 import (
+	"time"
+
 	epics "github.com/complexus-tech/projects-api/internal/modules/epics/service"
+	"github.com/complexus-tech/projects-api/pkg/date"
 	"github.com/google/uuid"
 )
 
-// AppEpicList represents a epic in the application layer.
-type AppEpicsList struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"title"`
-	Description string    `json:"description"`
+// AppEpic represents an epic in the application layer.
+type AppEpic struct {
+	ID              uuid.UUID       `json:"id"`
+	Workspace       uuid.UUID       `json:"workspaceId"`
+	Team            uuid.UUID       `json:"teamId"`
+	Objective       *uuid.UUID      `json:"objectiveId"`
+	Name            string          `json:"name"`
+	Description     string          `json:"description"`
+	DescriptionHTML string          `json:"descriptionHTML"`
+	Status          string          `json:"status"`
+	LeadUser        *uuid.UUID      `json:"leadUser"`
+	StartDate       *time.Time      `json:"startDate"`
+	TargetDate      *time.Time      `json:"targetDate"`
+	Color           string          `json:"color"`
+	CreatedBy       *uuid.UUID      `json:"createdBy"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	UpdatedAt       time.Time       `json:"updatedAt"`
+	Progress        AppEpicProgress `json:"progress"`
+}
+
+// AppEpicProgress is the story rollup for an epic.
+type AppEpicProgress struct {
+	Total     int `json:"total"`
+	Backlog   int `json:"backlog"`
+	Unstarted int `json:"unstarted"`
+	Started   int `json:"started"`
+	Paused    int `json:"paused"`
+	Completed int `json:"completed"`
+	Cancelled int `json:"cancelled"`
+	Percent   int `json:"percent"`
+}
+
+// AppNewEpic represents the data needed to create an epic.
+type AppNewEpic struct {
+	Name            string     `json:"name" validate:"required"`
+	Team            uuid.UUID  `json:"teamId" validate:"required"`
+	Objective       *uuid.UUID `json:"objectiveId"`
+	Description     string     `json:"description"`
+	DescriptionHTML string     `json:"descriptionHTML"`
+	Status          string     `json:"status"`
+	LeadUser        *uuid.UUID `json:"leadUser"`
+	StartDate       *date.Date `json:"startDate"`
+	TargetDate      *date.Date `json:"targetDate"`
+	Color           string     `json:"color"`
+}
+
+// AppUpdateEpic represents the fields that can be updated on an epic.
+type AppUpdateEpic struct {
+	Name            *string    `json:"name" db:"name"`
+	Description     *string    `json:"description" db:"description"`
+	DescriptionHTML *string    `json:"descriptionHTML" db:"description_html"`
+	Status          *string    `json:"status" db:"status"`
+	Objective       *uuid.UUID `json:"objectiveId" db:"objective_id"`
+	LeadUser        *uuid.UUID `json:"leadUser" db:"lead_user_id"`
+	StartDate       *date.Date `json:"startDate" db:"start_date"`
+	TargetDate      *date.Date `json:"targetDate" db:"target_date"`
+	Color           *string    `json:"color" db:"color"`
+}
+
+func toAppEpic(epic epics.CoreEpic) AppEpic {
+	return AppEpic{
+		ID:              epic.ID,
+		Workspace:       epic.WorkspaceID,
+		Team:            epic.TeamID,
+		Objective:       epic.ObjectiveID,
+		Name:            epic.Name,
+		Description:     epic.Description,
+		DescriptionHTML: epic.DescriptionHTML,
+		Status:          epic.Status,
+		LeadUser:        epic.LeadUserID,
+		StartDate:       epic.StartDate,
+		TargetDate:      epic.TargetDate,
+		Color:           epic.Color,
+		CreatedBy:       epic.CreatedBy,
+		CreatedAt:       epic.CreatedAt,
+		UpdatedAt:       epic.UpdatedAt,
+		Progress: AppEpicProgress{
+			Total:     epic.Progress.Total,
+			Backlog:   epic.Progress.Backlog,
+			Unstarted: epic.Progress.Unstarted,
+			Started:   epic.Progress.Started,
+			Paused:    epic.Progress.Paused,
+			Completed: epic.Progress.Completed,
+			Cancelled: epic.Progress.Cancelled,
+			Percent:   epic.Progress.Percent(),
+		},
+	}
 }
 
 // toAppEpics converts a list of core epics to a list of application epics.
-func toAppEpics(epics []epics.CoreEpic) []AppEpicsList {
-	appEpics := make([]AppEpicsList, len(epics))
+func toAppEpics(epics []epics.CoreEpic) []AppEpic {
+	appEpics := make([]AppEpic, len(epics))
 	for i, epic := range epics {
-		appEpics[i] = AppEpicsList{
-			ID:          epic.ID,
-			Name:        epic.Name,
-			Description: epic.Description,
-		}
+		appEpics[i] = toAppEpic(epic)
 	}
 	return appEpics
 }
+
+func toCoreNewEpic(ane AppNewEpic) epics.CoreNewEpic {
+	return epics.CoreNewEpic{
+		TeamID:          ane.Team,
+		ObjectiveID:     ane.Objective,
+		Name:            ane.Name,
+		Description:     ane.Description,
+		DescriptionHTML: ane.DescriptionHTML,
+		Status:          ane.Status,
+		LeadUserID:      ane.LeadUser,
+		StartDate:       ane.StartDate.TimePtr(),
+		TargetDate:      ane.TargetDate.TimePtr(),
+		Color:           ane.Color,
+	}
+}
@@ -21,8 +21,13 @@ func Routes(cfg Config, app *web.App) {
 	epicsService := cfg.Service
 	auth := mid.Auth(cfg.Log, cfg.SecretKey)
 	workspace := mid.Workspace(cfg.Log, cfg.DB, cfg.Cache)
+	memberAndAdmin := mid.RequireMinimumRole(cfg.Log, mid.RoleMember)
 
 	h := New(epicsService)
 
 	app.Get("/workspaces/{workspaceSlug}/epics", h.List, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/epics", h.Create, auth, workspace, memberAndAdmin)
+	app.Get("/workspaces/{workspaceSlug}/epics/{id}", h.Get, auth, workspace)
+	app.Put("/workspaces/{workspaceSlug}/epics/{id}", h.Update, auth, workspace, memberAndAdmin)
+	app.Delete("/workspaces/{workspaceSlug}/epics/{id}", h.Delete, auth, workspace, memberAndAdmin)
 }
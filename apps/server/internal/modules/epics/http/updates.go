@@ -0,0 +1,43 @@
+package epicshttp
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/web"
+)
+
+// getUpdates returns a map of database field names and their values from the given request data.
+// Pointer values are dereferenced so an explicit null clears the column.
+func getUpdates(requestData map[string]json.RawMessage) (map[string]any, error) {
+	epic := AppUpdateEpic{}
+	updates := make(map[string]any)
+	v := reflect.ValueOf(&epic).Elem()
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		jsonTag := t.Field(i).Tag.Get("json")
+		dbTag := t.Field(i).Tag.Get("db")
+
+		rawValue, ok := requestData[jsonTag]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(rawValue, field.Addr().Interface()); err != nil {
+			return nil, web.HumanizeJSONFieldDecodeError(err, jsonTag, field.Type())
+		}
+
+		switch {
+		case field.IsNil():
+			updates[dbTag] = nil
+		case field.Type().Implements(reflect.TypeOf((*interface{ TimePtr() *time.Time })(nil)).Elem()):
+			updates[dbTag] = field.Interface().(interface{ TimePtr() *time.Time }).TimePtr()
+		default:
+			updates[dbTag] = field.Elem().Interface()
+		}
+	}
+
+	return updates, nil
+}
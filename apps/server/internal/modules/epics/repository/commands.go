@@ -0,0 +1,154 @@
+package epicsrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	epics "github.com/complexus-tech/projects-api/internal/modules/epics/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func (r *repo) Create(ctx context.Context, workspaceID, userID uuid.UUID, ne epics.CoreNewEpic) (epics.CoreEpic, error) {
+	r.log.Info(ctx, "business.repository.epics.Create")
+	ctx, span := web.AddSpan(ctx, "business.repository.epics.Create")
+	defer span.End()
+
+	// The team and objective must belong to the workspace; otherwise nothing is inserted.
+	query := `
+		INSERT INTO epics (
+			workspace_id, team_id, objective_id, name, description, description_html,
+			status, lead_user_id, start_date, target_date, color, created_by
+		)
+		SELECT
+			:workspace_id, :team_id, :objective_id, :name, :description, :description_html,
+			:status, :lead_user_id, :start_date, :target_date, COALESCE(NULLIF(:color, ''), '#6b665c'), :created_by
+		WHERE EXISTS (
+			SELECT 1 FROM teams WHERE team_id = :team_id AND workspace_id = :workspace_id
+		)
+		AND (
+			CAST(:objective_id AS uuid) IS NULL OR EXISTS (
+				SELECT 1 FROM objectives WHERE objective_id = :objective_id AND workspace_id = :workspace_id
+			)
+		)
+		RETURNING id
+	`
+	params := map[string]any{
+		"workspace_id":     workspaceID,
+		"team_id":          ne.TeamID,
+		"objective_id":     ne.ObjectiveID,
+		"name":             ne.Name,
+		"description":      ne.Description,
+		"description_html": ne.DescriptionHTML,
+		"status":           ne.Status,
+		"lead_user_id":     ne.LeadUserID,
+		"start_date":       ne.StartDate,
+		"target_date":      ne.TargetDate,
+		"color":            ne.Color,
+		"created_by":       userID,
+	}
+
+	stmt, err := r.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to prepare named statement: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to prepare statement"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return epics.CoreEpic{}, err
+	}
+	defer stmt.Close()
+
+	var id uuid.UUID
+	if err := stmt.GetContext(ctx, &id, params); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return epics.CoreEpic{}, epics.ErrInvalidTeam
+		}
+		if strings.Contains(err.Error(), "epics_team_name_unique") {
+			return epics.CoreEpic{}, epics.ErrNameExists
+		}
+		errMsg := fmt.Sprintf("failed to create epic: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to create epic"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return epics.CoreEpic{}, err
+	}
+
+	return r.Get(ctx, id, workspaceID)
+}
+
+// Update updates an epic's columns. Field names are validated by the service.
+func (r *repo) Update(ctx context.Context, id, workspaceID uuid.UUID, updates map[string]any) error {
+	r.log.Info(ctx, "business.repository.epics.Update")
+	ctx, span := web.AddSpan(ctx, "business.repository.epics.Update")
+	defer span.End()
+
+	var setClauses []string
+	params := map[string]any{"id": id, "workspace_id": workspaceID}
+	for field, value := range updates {
+		setClauses = append(setClauses, fmt.Sprintf("%s = :%s", field, field))
+		params[field] = value
+	}
+	setClauses = append(setClauses, "updated_at = NOW()")
+
+	query := "UPDATE epics SET " + strings.Join(setClauses, ", ") + " WHERE id = :id AND workspace_id = :workspace_id"
+	if _, ok := updates["objective_id"]; ok {
+		query += ` AND (
+			CAST(:objective_id AS uuid) IS NULL OR EXISTS (
+				SELECT 1 FROM objectives WHERE objective_id = :objective_id AND workspace_id = :workspace_id
+			)
+		)`
+	}
+
+	stmt, err := r.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to prepare named statement: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to prepare statement"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, params)
+	if err != nil {
+		if strings.Contains(err.Error(), "epics_team_name_unique") {
+			return epics.ErrNameExists
+		}
+		errMsg := fmt.Sprintf("failed to update epic: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to update epic"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		if _, ok := updates["objective_id"]; ok {
+			return epics.ErrInvalidTeam
+		}
+		return epics.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) Delete(ctx context.Context, id, workspaceID uuid.UUID) error {
+	r.log.Info(ctx, "business.repository.epics.Delete")
+	ctx, span := web.AddSpan(ctx, "business.repository.epics.Delete")
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM epics WHERE id = $1 AND workspace_id = $2", id, workspaceID)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to delete epic: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to delete epic"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return epics.ErrNotFound
+	}
+
+	span.AddEvent("epic deleted", trace.WithAttributes(
+		attribute.String("epic.id", id.String()),
+	))
+	return nil
+}
@@ -8,27 +8,42 @@ import (
 )
 
 type dbEpic struct {
-	ID          uuid.UUID  `db:"id"`
-	Name        string     `db:"name"`
-	Description string     `db:"description"`
-	Owner       *uuid.UUID `db:"owner"`
-	StartDate   *time.Time `db:"start_date"`
-	EndDate     *time.Time `db:"end_date"`
-	CreatedAt   time.Time  `db:"created_at"`
-	UpdatedAt   time.Time  `db:"updated_at"`
-	DeletedAt   *time.Time `db:"deleted_at"`
+	ID              uuid.UUID  `db:"id"`
+	WorkspaceID     uuid.UUID  `db:"workspace_id"`
+	TeamID          uuid.UUID  `db:"team_id"`
+	ObjectiveID     *uuid.UUID `db:"objective_id"`
+	Name            string     `db:"name"`
+	Description     string     `db:"description"`
+	DescriptionHTML string     `db:"description_html"`
+	Status          string     `db:"status"`
+	LeadUserID      *uuid.UUID `db:"lead_user_id"`
+	StartDate       *time.Time `db:"start_date"`
+	TargetDate      *time.Time `db:"target_date"`
+	Color           string     `db:"color"`
+	CreatedBy       *uuid.UUID `db:"created_by"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
+	epics.CoreEpicProgress
 }
 
 func toCoreEpic(p dbEpic) epics.CoreEpic {
 	return epics.CoreEpic{
-		ID:          p.ID,
-		Name:        p.Name,
-		Description: p.Description,
-		Owner:       p.Owner,
-		StartDate:   p.StartDate,
-		EndDate:     p.EndDate,
-		CreatedAt:   p.CreatedAt,
-		UpdatedAt:   p.UpdatedAt,
+		ID:              p.ID,
+		WorkspaceID:     p.WorkspaceID,
+		TeamID:          p.TeamID,
+		ObjectiveID:     p.ObjectiveID,
+		Name:            p.Name,
+		Description:     p.Description,
+		DescriptionHTML: p.DescriptionHTML,
+		Status:          p.Status,
+		LeadUserID:      p.LeadUserID,
+		StartDate:       p.StartDate,
+		TargetDate:      p.TargetDate,
+		Color:           p.Color,
+		CreatedBy:       p.CreatedBy,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+		Progress:        p.CoreEpicProgress,
 	}
 }
 
@@ -2,19 +2,153 @@ package epicsrepository
 
 import (
 	"context"
-	"time"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 
 	epics "github.com/complexus-tech/projects-api/internal/modules/epics/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func (r *repo) List(ctx context.Context) ([]epics.CoreEpic, error) {
+// epicSelect selects epics with a progress rollup over their live member stories.
+const epicSelect = `
+	SELECT
+		e.id,
+		e.workspace_id,
+		e.team_id,
+		e.objective_id,
+		e.name,
+		e.description,
+		e.description_html,
+		e.status,
+		e.lead_user_id,
+		e.start_date,
+		e.target_date,
+		e.color,
+		e.created_by,
+		e.created_at,
+		e.updated_at,
+		COALESCE(p.total, 0) AS total,
+		COALESCE(p.backlog, 0) AS backlog,
+		COALESCE(p.unstarted, 0) AS unstarted,
+		COALESCE(p.started, 0) AS started,
+		COALESCE(p.paused, 0) AS paused,
+		COALESCE(p.completed, 0) AS completed,
+		COALESCE(p.cancelled, 0) AS cancelled
+	FROM epics e
+	LEFT JOIN LATERAL (
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE st.category = 'backlog') AS backlog,
+			COUNT(*) FILTER (WHERE st.category = 'unstarted') AS unstarted,
+			COUNT(*) FILTER (WHERE st.category = 'started') AS started,
+			COUNT(*) FILTER (WHERE st.category = 'paused') AS paused,
+			COUNT(*) FILTER (WHERE st.category = 'completed') AS completed,
+			COUNT(*) FILTER (WHERE st.category = 'cancelled') AS cancelled
+		FROM stories s
+		LEFT JOIN statuses st ON st.status_id = s.status_id
+		WHERE s.epic_id = e.id
+			AND s.deleted_at IS NULL
+			AND s.archived_at IS NULL
+	) p ON true
+`
 
-	p := []dbEpic{
-		{ID: uuid.New(), Name: "Epic 1", Description: "This is epic 1", CreatedAt: time.Now(), UpdatedAt: time.Now()},
-		{ID: uuid.New(), Name: "Epic 2", Description: "This is epic 2", CreatedAt: time.Now(), UpdatedAt: time.Now()},
-		{ID: uuid.New(), Name: "Epic 3", Description: "This is epic 3", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+func (r *repo) List(ctx context.Context, workspaceID uuid.UUID, filters epics.CoreEpicFilters) ([]epics.CoreEpic, error) {
+	r.log.Info(ctx, "business.repository.epics.List")
+	ctx, span := web.AddSpan(ctx, "business.repository.epics.List")
+	defer span.End()
+
+	whereClauses := []string{"e.workspace_id = :workspace_id"}
+	params := map[string]any{"workspace_id": workspaceID}
+
+	if filters.TeamID != nil {
+		whereClauses = append(whereClauses, "e.team_id = :team_id")
+		params["team_id"] = *filters.TeamID
+	}
+	if filters.ObjectiveID != nil {
+		whereClauses = append(whereClauses, "e.objective_id = :objective_id")
+		params["objective_id"] = *filters.ObjectiveID
+	}
+	if filters.Status != nil {
+		whereClauses = append(whereClauses, "e.status = :status")
+		params["status"] = *filters.Status
+	}
+	if filters.LeadUserID != nil {
+		whereClauses = append(whereClauses, "e.lead_user_id = :lead_user_id")
+		params["lead_user_id"] = *filters.LeadUserID
+	}
+
+	query := epicSelect + " WHERE " + strings.Join(whereClauses, " AND ") +
+		" ORDER BY e.target_date ASC NULLS LAST, e.created_at DESC"
+
+	stmt, err := r.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to prepare named statement: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to prepare statement"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var rows []dbEpic
+	if err := stmt.SelectContext(ctx, &rows, params); err != nil {
+		errMsg := fmt.Sprintf("failed to list epics: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to list epics"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	return toCoreEpics(rows), nil
+}
+
+func (r *repo) Get(ctx context.Context, id, workspaceID uuid.UUID) (epics.CoreEpic, error) {
+	r.log.Info(ctx, "business.repository.epics.Get")
+	ctx, span := web.AddSpan(ctx, "business.repository.epics.Get")
+	defer span.End()
+
+	query := epicSelect + " WHERE e.id = :id AND e.workspace_id = :workspace_id"
+
+	stmt, err := r.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to prepare named statement: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to prepare statement"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return epics.CoreEpic{}, err
+	}
+	defer stmt.Close()
+
+	var row dbEpic
+	if err := stmt.GetContext(ctx, &row, map[string]any{"id": id, "workspace_id": workspaceID}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return epics.CoreEpic{}, epics.ErrNotFound
+		}
+		errMsg := fmt.Sprintf("failed to get epic: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get epic"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return epics.CoreEpic{}, err
+	}
+
+	return toCoreEpic(row), nil
+}
+
+// IsWorkspaceMember reports whether the user belongs to the workspace.
+func (r *repo) IsWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) (bool, error) {
+	r.log.Info(ctx, "business.repository.epics.IsWorkspaceMember")
+	ctx, span := web.AddSpan(ctx, "business.repository.epics.IsWorkspaceMember")
+	defer span.End()
+
+	var member bool
+	query := `SELECT EXISTS (SELECT 1 FROM workspace_members WHERE workspace_id = $1 AND user_id = $2)`
+	if err := r.db.GetContext(ctx, &member, query, workspaceID, userID); err != nil {
+		errMsg := fmt.Sprintf("failed to check workspace membership: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to check workspace membership"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return false, err
 	}
 
-	return toCoreEpics(p), nil
+	return member, nil
 }
@@ -2,25 +2,55 @@ package epics
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/complexus-tech/projects-api/pkg/logger"
 	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// Service errors
+var (
+	ErrNotFound      = errors.New("epic not found")
+	ErrNameExists    = errors.New("an epic with this name already exists in the team")
+	ErrInvalidStatus = fmt.Errorf("invalid epic status, must be one of: %s", strings.Join(Statuses, ", "))
+	ErrInvalidDates  = errors.New("epic start date must be on or before the target date")
+	ErrNameRequired  = errors.New("epic name is required")
+	ErrInvalidTeam   = errors.New("team or objective does not belong to this workspace")
+	ErrInvalidLead   = errors.New("epic lead must be a member of this workspace")
+
+	ErrFieldNotUpdatable = errors.New("field cannot be updated")
+)
+
+// updatableFields lists the columns Update accepts.
+var updatableFields = []string{
+	"name", "description", "description_html", "status", "objective_id",
+	"lead_user_id", "start_date", "target_date", "color",
+}
+
 // Repository provides access to the epics storage.
 type Repository interface {
-	List(ctx context.Context) ([]CoreEpic, error)
+	List(ctx context.Context, workspaceID uuid.UUID, filters CoreEpicFilters) ([]CoreEpic, error)
+	Get(ctx context.Context, id, workspaceID uuid.UUID) (CoreEpic, error)
+	Create(ctx context.Context, workspaceID, userID uuid.UUID, ne CoreNewEpic) (CoreEpic, error)
+	Update(ctx context.Context, id, workspaceID uuid.UUID, updates map[string]any) error
+	Delete(ctx context.Context, id, workspaceID uuid.UUID) error
+	IsWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) (bool, error)
 }
 
-// Service provides story-related operations.
+// Service provides epic-related operations.
 type Service struct {
 	repo Repository
 	log  *logger.Logger
 }
 
-// New constructs a new stories service instance with the provided repository.
+// New constructs a new epics service instance with the provided repository.
 func New(log *logger.Logger, repo Repository) *Service {
 	return &Service{
 		repo: repo,
@@ -28,19 +58,167 @@ func New(log *logger.Logger, repo Repository) *Service {
 	}
 }
 
-// List returns a list of epics.
-func (s *Service) List(ctx context.Context) ([]CoreEpic, error) {
+// List returns the epics in a workspace with their progress rollup.
+func (s *Service) List(ctx context.Context, workspaceID uuid.UUID, filters CoreEpicFilters) ([]CoreEpic, error) {
 	s.log.Info(ctx, "business.core.epics.list")
 	ctx, span := web.AddSpan(ctx, "business.core.epics.List")
 	defer span.End()
 
-	epics, err := s.repo.List(ctx)
+	if filters.Status != nil && !slices.Contains(Statuses, *filters.Status) {
+		return nil, ErrInvalidStatus
+	}
+
+	epics, err := s.repo.List(ctx, workspaceID, filters)
 	if err != nil {
 		span.RecordError(err)
 		return nil, err
 	}
 	span.AddEvent("epics retrieved.", trace.WithAttributes(
-		attribute.Int("story.count", len(epics)),
+		attribute.Int("epic.count", len(epics)),
 	))
 	return epics, nil
 }
+
+// Get returns a single epic with its progress rollup.
+func (s *Service) Get(ctx context.Context, id, workspaceID uuid.UUID) (CoreEpic, error) {
+	s.log.Info(ctx, "business.core.epics.get")
+	ctx, span := web.AddSpan(ctx, "business.core.epics.Get")
+	defer span.End()
+
+	epic, err := s.repo.Get(ctx, id, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreEpic{}, err
+	}
+	return epic, nil
+}
+
+// Create adds a new epic to a team.
+func (s *Service) Create(ctx context.Context, workspaceID, userID uuid.UUID, ne CoreNewEpic) (CoreEpic, error) {
+	s.log.Info(ctx, "business.core.epics.create")
+	ctx, span := web.AddSpan(ctx, "business.core.epics.Create")
+	defer span.End()
+
+	ne.Name = strings.TrimSpace(ne.Name)
+	if ne.Name == "" {
+		return CoreEpic{}, ErrNameRequired
+	}
+	if ne.Status == "" {
+		ne.Status = StatusPlanned
+	}
+	if !slices.Contains(Statuses, ne.Status) {
+		return CoreEpic{}, ErrInvalidStatus
+	}
+	if err := validateDates(ne.StartDate, ne.TargetDate); err != nil {
+		return CoreEpic{}, err
+	}
+	if ne.LeadUserID != nil {
+		if err := s.checkLead(ctx, workspaceID, *ne.LeadUserID); err != nil {
+			span.RecordError(err)
+			return CoreEpic{}, err
+		}
+	}
+
+	epic, err := s.repo.Create(ctx, workspaceID, userID, ne)
+	if err != nil {
+		span.RecordError(err)
+		return CoreEpic{}, err
+	}
+
+	span.AddEvent("epic created.", trace.WithAttributes(
+		attribute.String("epic.id", epic.ID.String()),
+	))
+	return epic, nil
+}
+
+// Update applies a partial update keyed by column name.
+func (s *Service) Update(ctx context.Context, id, workspaceID uuid.UUID, updates map[string]any) (CoreEpic, error) {
+	s.log.Info(ctx, "business.core.epics.update")
+	ctx, span := web.AddSpan(ctx, "business.core.epics.Update")
+	defer span.End()
+
+	for field := range updates {
+		if !slices.Contains(updatableFields, field) {
+			return CoreEpic{}, fmt.Errorf("%w: %s", ErrFieldNotUpdatable, field)
+		}
+	}
+
+	current, err := s.repo.Get(ctx, id, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreEpic{}, err
+	}
+
+	if value, ok := updates["name"]; ok {
+		name, _ := value.(string)
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return CoreEpic{}, ErrNameRequired
+		}
+		updates["name"] = name
+	}
+	if value, ok := updates["status"]; ok {
+		if status, _ := value.(string); !slices.Contains(Statuses, status) {
+			return CoreEpic{}, ErrInvalidStatus
+		}
+	}
+
+	startDate, targetDate := current.StartDate, current.TargetDate
+	if value, ok := updates["start_date"]; ok {
+		startDate, _ = value.(*time.Time)
+	}
+	if value, ok := updates["target_date"]; ok {
+		targetDate, _ = value.(*time.Time)
+	}
+	if err := validateDates(startDate, targetDate); err != nil {
+		return CoreEpic{}, err
+	}
+	if lead, ok := updates["lead_user_id"].(uuid.UUID); ok {
+		if err := s.checkLead(ctx, workspaceID, lead); err != nil {
+			span.RecordError(err)
+			return CoreEpic{}, err
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.Update(ctx, id, workspaceID, updates); err != nil {
+			span.RecordError(err)
+			return CoreEpic{}, err
+		}
+	}
+
+	return s.repo.Get(ctx, id, workspaceID)
+}
+
+// Delete removes an epic. Member stories keep existing with no epic.
+func (s *Service) Delete(ctx context.Context, id, workspaceID uuid.UUID) error {
+	s.log.Info(ctx, "business.core.epics.delete")
+	ctx, span := web.AddSpan(ctx, "business.core.epics.Delete")
+	defer span.End()
+
+	if err := s.repo.Delete(ctx, id, workspaceID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// checkLead returns ErrInvalidLead unless the user is a member of the
+// workspace.
+func (s *Service) checkLead(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	member, err := s.repo.IsWorkspaceMember(ctx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return ErrInvalidLead
+	}
+	return nil
+}
+
+func validateDates(startDate, targetDate *time.Time) error {
+	if startDate != nil && targetDate != nil && startDate.After(*targetDate) {
+		return ErrInvalidDates
+	}
+	return nil
+}
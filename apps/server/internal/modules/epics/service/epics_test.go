@@ -0,0 +1,156 @@
+package epics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/google/uuid"
+)
+
+type epicsRepo struct {
+	Repository
+
+	epic    CoreEpic
+	members []uuid.UUID
+	created []CoreNewEpic
+	updates map[string]any
+}
+
+func (r *epicsRepo) Get(ctx context.Context, id, workspaceID uuid.UUID) (CoreEpic, error) {
+	return r.epic, nil
+}
+
+func (r *epicsRepo) Create(ctx context.Context, workspaceID, userID uuid.UUID, ne CoreNewEpic) (CoreEpic, error) {
+	r.created = append(r.created, ne)
+	return CoreEpic{ID: uuid.New(), WorkspaceID: workspaceID, TeamID: ne.TeamID, Name: ne.Name, Status: ne.Status}, nil
+}
+
+func (r *epicsRepo) Update(ctx context.Context, id, workspaceID uuid.UUID, updates map[string]any) error {
+	r.updates = updates
+	return nil
+}
+
+func (r *epicsRepo) IsWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) (bool, error) {
+	for _, member := range r.members {
+		if member == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newEpicsService(repo *epicsRepo) *Service {
+	return New(logger.NewWithText(io.Discard, slog.LevelError, "test"), repo)
+}
+
+func TestEpicProgressPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress CoreEpicProgress
+		want     int
+	}{
+		{name: "no stories", progress: CoreEpicProgress{}, want: 0},
+		{name: "half done", progress: CoreEpicProgress{Total: 4, Started: 2, Completed: 2}, want: 50},
+		{name: "cancelled stories are out of scope", progress: CoreEpicProgress{Total: 4, Completed: 3, Cancelled: 1}, want: 100},
+		{name: "only cancelled stories", progress: CoreEpicProgress{Total: 2, Cancelled: 2}, want: 0},
+		{name: "rounds down", progress: CoreEpicProgress{Total: 3, Backlog: 2, Completed: 1}, want: 33},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.progress.Percent(); got != tt.want {
+				t.Errorf("Percent() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateEpicValidates(t *testing.T) {
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	before := start.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name string
+		epic CoreNewEpic
+		want error
+	}{
+		{name: "blank name", epic: CoreNewEpic{Name: "  "}, want: ErrNameRequired},
+		{name: "unknown status", epic: CoreNewEpic{Name: "Billing", Status: "done"}, want: ErrInvalidStatus},
+		{name: "target before start", epic: CoreNewEpic{Name: "Billing", StartDate: &start, TargetDate: &before}, want: ErrInvalidDates},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &epicsRepo{}
+			if _, err := newEpicsService(repo).Create(context.Background(), uuid.New(), uuid.New(), tt.epic); !errors.Is(err, tt.want) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.want)
+			}
+			if len(repo.created) != 0 {
+				t.Fatalf("expected nothing to be created, got %d epics", len(repo.created))
+			}
+		})
+	}
+}
+
+func TestCreateEpicDefaultsToPlanned(t *testing.T) {
+	repo := &epicsRepo{}
+	epic, err := newEpicsService(repo).Create(context.Background(), uuid.New(), uuid.New(), CoreNewEpic{Name: " Billing "})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if epic.Name != "Billing" || epic.Status != StatusPlanned {
+		t.Fatalf("expected a trimmed, planned epic, got %q %q", epic.Name, epic.Status)
+	}
+}
+
+func TestUpdateEpicChecksDatesAgainstCurrent(t *testing.T) {
+	target := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	after := target.AddDate(0, 0, 1)
+	repo := &epicsRepo{epic: CoreEpic{ID: uuid.New(), Name: "Billing", Status: StatusPlanned, TargetDate: &target}}
+	service := newEpicsService(repo)
+
+	if _, err := service.Update(context.Background(), repo.epic.ID, uuid.New(), map[string]any{"start_date": &after}); !errors.Is(err, ErrInvalidDates) {
+		t.Fatalf("expected %v for a start after the current target, got %v", ErrInvalidDates, err)
+	}
+	if repo.updates != nil {
+		t.Fatalf("expected no write, got %v", repo.updates)
+	}
+
+	if _, err := service.Update(context.Background(), repo.epic.ID, uuid.New(), map[string]any{"start_date": &after, "target_date": nil}); err != nil {
+		t.Fatalf("expected clearing the target to allow the start, got %v", err)
+	}
+	if _, err := service.Update(context.Background(), repo.epic.ID, uuid.New(), map[string]any{"team_id": uuid.New()}); !errors.Is(err, ErrFieldNotUpdatable) {
+		t.Fatalf("expected %v for team_id, got %v", ErrFieldNotUpdatable, err)
+	}
+}
+
+func TestEpicLeadMustBeWorkspaceMember(t *testing.T) {
+	memberID, outsiderID := uuid.New(), uuid.New()
+	repo := &epicsRepo{epic: CoreEpic{ID: uuid.New(), Name: "Billing", Status: StatusPlanned}, members: []uuid.UUID{memberID}}
+	service := newEpicsService(repo)
+
+	if _, err := service.Create(context.Background(), uuid.New(), uuid.New(), CoreNewEpic{Name: "Billing", LeadUserID: &outsiderID}); !errors.Is(err, ErrInvalidLead) {
+		t.Fatalf("expected %v for a lead outside the workspace, got %v", ErrInvalidLead, err)
+	}
+	if len(repo.created) != 0 {
+		t.Fatalf("expected nothing to be created, got %d epics", len(repo.created))
+	}
+	if _, err := service.Create(context.Background(), uuid.New(), uuid.New(), CoreNewEpic{Name: "Billing", LeadUserID: &memberID}); err != nil {
+		t.Fatalf("expected a member to lead the epic, got %v", err)
+	}
+
+	if _, err := service.Update(context.Background(), repo.epic.ID, uuid.New(), map[string]any{"lead_user_id": outsiderID}); !errors.Is(err, ErrInvalidLead) {
+		t.Fatalf("expected %v for a lead outside the workspace, got %v", ErrInvalidLead, err)
+	}
+	if repo.updates != nil {
+		t.Fatalf("expected no write, got %v", repo.updates)
+	}
+	if _, err := service.Update(context.Background(), repo.epic.ID, uuid.New(), map[string]any{"lead_user_id": nil}); err != nil {
+		t.Fatalf("expected clearing the lead to be allowed, got %v", err)
+	}
+}
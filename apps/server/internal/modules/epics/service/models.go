@@ -6,13 +6,74 @@ import (
 	"github.com/google/uuid"
 )
 
+// Epic statuses.
+const (
+	StatusPlanned    = "planned"
+	StatusInProgress = "in_progress"
+	StatusPaused     = "paused"
+	StatusCompleted  = "completed"
+	StatusCancelled  = "cancelled"
+)
+
+// Statuses lists every valid epic status.
+var Statuses = []string{StatusPlanned, StatusInProgress, StatusPaused, StatusCompleted, StatusCancelled}
+
 type CoreEpic struct {
-	ID          uuid.UUID
-	Name        string
-	Description string
-	Owner       *uuid.UUID
-	StartDate   *time.Time
-	EndDate     *time.Time
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID              uuid.UUID
+	WorkspaceID     uuid.UUID
+	TeamID          uuid.UUID
+	ObjectiveID     *uuid.UUID
+	Name            string
+	Description     string
+	DescriptionHTML string
+	Status          string
+	LeadUserID      *uuid.UUID
+	StartDate       *time.Time
+	TargetDate      *time.Time
+	Color           string
+	CreatedBy       *uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Progress        CoreEpicProgress
+}
+
+// CoreEpicProgress rolls up member stories by status category.
+type CoreEpicProgress struct {
+	Total     int `db:"total"`
+	Backlog   int `db:"backlog"`
+	Unstarted int `db:"unstarted"`
+	Started   int `db:"started"`
+	Paused    int `db:"paused"`
+	Completed int `db:"completed"`
+	Cancelled int `db:"cancelled"`
+}
+
+// Percent returns the share of non-cancelled stories that are completed.
+func (p CoreEpicProgress) Percent() int {
+	scope := p.Total - p.Cancelled
+	if scope <= 0 {
+		return 0
+	}
+	return p.Completed * 100 / scope
+}
+
+type CoreNewEpic struct {
+	TeamID          uuid.UUID
+	ObjectiveID     *uuid.UUID
+	Name            string
+	Description     string
+	DescriptionHTML string
+	Status          string
+	LeadUserID      *uuid.UUID
+	StartDate       *time.Time
+	TargetDate      *time.Time
+	Color           string
+}
+
+// CoreEpicFilters narrows List results. Nil fields are ignored.
+type CoreEpicFilters struct {
+	TeamID      *uuid.UUID
+	ObjectiveID *uuid.UUID
+	Status      *string
+	LeadUserID  *uuid.UUID
 }
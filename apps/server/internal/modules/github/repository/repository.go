@@ -9,6 +9,7 @@ import (
 	"time"
 
 	githubshared "github.com/complexus-tech/projects-api/internal/modules/github/shared"
+	storiesrepository "github.com/complexus-tech/projects-api/internal/modules/stories/repository"
 	"github.com/complexus-tech/projects-api/pkg/logger"
 	"github.com/complexus-tech/projects-api/pkg/web"
 	"github.com/google/uuid"
@@ -799,18 +800,23 @@ func (r *Repo) CreateOrUpdateExternalStory(ctx context.Context, workspaceID, tea
 		return uuid.Nil, err
 	}
 
+	rank, err := storiesrepository.NextStoryRank(ctx, tx, workspaceID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
 	var storyID uuid.UUID
 	insertStoryQuery := `
 		INSERT INTO stories (
 			sequence_id, title, description, description_html, status_id, priority, estimate_unit,
-			team_id, workspace_id, reporter_id, created_at, updated_at
+			team_id, workspace_id, reporter_id, rank, created_at, updated_at
 		) VALUES (
 			$1, $2, $3, $3, $4, 'No Priority', NULL,
-			$5, $6, $7, NOW(), NOW()
+			$5, $6, $7, $8, NOW(), NOW()
 		)
 		RETURNING id
 	`
-	if err := tx.GetContext(ctx, &storyID, insertStoryQuery, sequenceID+1, title, description, statusID, teamID, workspaceID, reporterID); err != nil {
+	if err := tx.GetContext(ctx, &storyID, insertStoryQuery, sequenceID+1, title, description, statusID, teamID, workspaceID, reporterID, rank); err != nil {
 		return uuid.Nil, err
 	}
 
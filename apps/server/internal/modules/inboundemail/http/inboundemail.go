@@ -0,0 +1,227 @@
+package inboundemailhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	inboundemail "github.com/complexus-tech/projects-api/internal/modules/inboundemail/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// formOverheadBytes allows for the form encoding around a raw message posted
+// as a form field.
+const formOverheadBytes = 1 << 20
+
+// rawMessageFields are the form fields providers post the raw MIME in.
+var rawMessageFields = []string{"email", "body-mime", "raw"}
+
+type Handlers struct {
+	inboundEmail *inboundemail.Service
+	log          *logger.Logger
+}
+
+func New(service *inboundemail.Service, log *logger.Logger) *Handlers {
+	return &Handlers{inboundEmail: service, log: log}
+}
+
+// Receive accepts a raw message from the mail provider, either as the request
+// body or as a form field. Envelope recipients may be passed in the "to"
+// query parameter or "recipient" form field.
+func (h *Handlers) Receive(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "inboundemailhttp.handlers.Receive")
+	defer span.End()
+
+	secret := r.Header.Get(inboundemail.SecretHeader)
+	if secret == "" {
+		if _, password, ok := r.BasicAuth(); ok {
+			secret = password
+		}
+	}
+	if err := h.inboundEmail.VerifySecret(secret); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.inboundEmail.MaxMessageBytes()+formOverheadBytes)
+	raw, recipients, err := readMessage(r)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return web.RespondError(ctx, w, inboundemail.ErrMessageTooLarge, http.StatusRequestEntityTooLarge)
+		}
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	result, err := h.inboundEmail.Receive(ctx, raw, recipients)
+	if err != nil {
+		status := httpStatus(err)
+		if status == http.StatusInternalServerError {
+			span.RecordError(err)
+			h.log.Error(ctx, "failed to receive inbound email", "error", err)
+		}
+		return web.RespondError(ctx, w, err, status)
+	}
+	return web.Respond(ctx, w, toAppResult(result), http.StatusOK)
+}
+
+func (h *Handlers) Get(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	teamID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	address, err := h.inboundEmail.GetAddress(ctx, workspace.ID, teamID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, toAppAddress(address, h.inboundEmail.EmailAddress(address)), http.StatusOK)
+}
+
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	teamID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	var input AppNewAddress
+	if err := web.Decode(r, &input); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	address, err := h.inboundEmail.CreateAddress(ctx, inboundemail.CoreNewAddress{
+		TeamID:         teamID,
+		WorkspaceID:    workspace.ID,
+		Mode:           input.Mode,
+		AllowedDomains: input.AllowedDomains,
+		CreatedBy:      userID,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, toAppAddress(address, h.inboundEmail.EmailAddress(address)), http.StatusCreated)
+}
+
+func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	teamID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	var input AppUpdateAddress
+	if err := web.Decode(r, &input); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	address, err := h.inboundEmail.UpdateAddress(ctx, workspace.ID, teamID, inboundemail.CoreUpdateAddress{
+		Mode:           input.Mode,
+		IsActive:       input.IsActive,
+		AllowedDomains: input.AllowedDomains,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, toAppAddress(address, h.inboundEmail.EmailAddress(address)), http.StatusOK)
+}
+
+func (h *Handlers) Rotate(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	teamID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	address, err := h.inboundEmail.RotateAddress(ctx, workspace.ID, teamID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, toAppAddress(address, h.inboundEmail.EmailAddress(address)), http.StatusOK)
+}
+
+func (h *Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	teamID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	if err := h.inboundEmail.DeleteAddress(ctx, workspace.ID, teamID); err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// readMessage returns the raw message and envelope recipients of a request.
+func readMessage(r *http.Request) ([]byte, []string, error) {
+	recipients := splitRecipients(r.URL.Query()["to"])
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" && mediaType != "application/x-www-form-urlencoded" {
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(raw) == 0 {
+			return nil, nil, errors.New("request body is empty")
+		}
+		return raw, recipients, nil
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return nil, nil, err
+	}
+	recipients = append(recipients, splitRecipients(r.PostForm["recipient"])...)
+	for _, field := range rawMessageFields {
+		if value := r.PostFormValue(field); value != "" {
+			return []byte(value), recipients, nil
+		}
+	}
+	return nil, nil, errors.New("request has no raw message field")
+}
+
+func splitRecipients(values []string) []string {
+	var recipients []string
+	for _, value := range values {
+		for _, recipient := range strings.Split(value, ",") {
+			if recipient = strings.ToLower(strings.TrimSpace(recipient)); recipient != "" {
+				recipients = append(recipients, recipient)
+			}
+		}
+	}
+	return recipients
+}
+
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, inboundemail.ErrNotFound), errors.Is(err, inboundemail.ErrUnknownRecipient):
+		return http.StatusNotFound
+	case errors.Is(err, inboundemail.ErrAddressExists), errors.Is(err, inboundemail.ErrMessageInProgress):
+		return http.StatusConflict
+	case errors.Is(err, inboundemail.ErrMessageTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, inboundemail.ErrInvalidInput), errors.Is(err, inboundemail.ErrInvalidMessage):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
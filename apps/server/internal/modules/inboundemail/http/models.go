@@ -0,0 +1,66 @@
+package inboundemailhttp
+
+import (
+	"time"
+
+	inboundemail "github.com/complexus-tech/projects-api/internal/modules/inboundemail/service"
+	"github.com/google/uuid"
+)
+
+type AppAddress struct {
+	TeamID         uuid.UUID  `json:"teamId"`
+	Address        string     `json:"address"`
+	Mode           string     `json:"mode"`
+	IsActive       bool       `json:"isActive"`
+	AllowedDomains []string   `json:"allowedDomains"`
+	CreatedBy      *uuid.UUID `json:"createdBy"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+type AppNewAddress struct {
+	Mode           string   `json:"mode"`
+	AllowedDomains []string `json:"allowedDomains"`
+}
+
+type AppUpdateAddress struct {
+	Mode           *string  `json:"mode"`
+	IsActive       *bool    `json:"isActive"`
+	AllowedDomains []string `json:"allowedDomains"`
+}
+
+// AppResult tells the mail provider what became of a message.
+type AppResult struct {
+	Outcome            string     `json:"outcome"`
+	Reason             string     `json:"reason,omitempty"`
+	StoryID            *uuid.UUID `json:"storyId,omitempty"`
+	RequestID          *uuid.UUID `json:"requestId,omitempty"`
+	CommentID          *uuid.UUID `json:"commentId,omitempty"`
+	SkippedAttachments []string   `json:"skippedAttachments,omitempty"`
+	AlreadyProcessed   bool       `json:"alreadyProcessed,omitempty"`
+}
+
+func toAppAddress(address inboundemail.CoreAddress, emailAddress string) AppAddress {
+	return AppAddress{
+		TeamID:         address.TeamID,
+		Address:        emailAddress,
+		Mode:           address.Mode,
+		IsActive:       address.IsActive,
+		AllowedDomains: address.AllowedDomains,
+		CreatedBy:      address.CreatedBy,
+		CreatedAt:      address.CreatedAt,
+		UpdatedAt:      address.UpdatedAt,
+	}
+}
+
+func toAppResult(result inboundemail.CoreResult) AppResult {
+	return AppResult{
+		Outcome:            result.Outcome,
+		Reason:             result.Reason,
+		StoryID:            result.StoryID,
+		RequestID:          result.RequestID,
+		CommentID:          result.CommentID,
+		SkippedAttachments: result.SkippedAttachments,
+		AlreadyProcessed:   result.AlreadyProcessed,
+	}
+}
@@ -0,0 +1,34 @@
+package inboundemailhttp
+
+import (
+	inboundemail "github.com/complexus-tech/projects-api/internal/modules/inboundemail/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/cache"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	DB        *sqlx.DB
+	Log       *logger.Logger
+	SecretKey string
+	Cache     *cache.Service
+	Service   *inboundemail.Service
+}
+
+func Routes(cfg Config, app *web.App) {
+	h := New(cfg.Service, cfg.Log)
+	auth := mid.Auth(cfg.Log, cfg.SecretKey)
+	workspace := mid.Workspace(cfg.Log, cfg.DB, cfg.Cache)
+	adminOnly := mid.RequireMinimumRole(cfg.Log, mid.RoleAdmin)
+
+	// Mail providers post raw messages here, authenticated by the shared inbound secret.
+	app.Post("/inbound-email", h.Receive)
+
+	app.Get("/workspaces/{workspaceSlug}/teams/{id}/inbound-email", h.Get, auth, workspace, adminOnly)
+	app.Post("/workspaces/{workspaceSlug}/teams/{id}/inbound-email", h.Create, auth, workspace, adminOnly)
+	app.Put("/workspaces/{workspaceSlug}/teams/{id}/inbound-email", h.Update, auth, workspace, adminOnly)
+	app.Delete("/workspaces/{workspaceSlug}/teams/{id}/inbound-email", h.Delete, auth, workspace, adminOnly)
+	app.Post("/workspaces/{workspaceSlug}/teams/{id}/inbound-email/rotate", h.Rotate, auth, workspace, adminOnly)
+}
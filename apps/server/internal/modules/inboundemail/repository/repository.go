@@ -0,0 +1,340 @@
+package inboundemailrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	inboundemail "github.com/complexus-tech/projects-api/internal/modules/inboundemail/service"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repo struct {
+	log *logger.Logger
+	db  *sqlx.DB
+}
+
+func New(log *logger.Logger, db *sqlx.DB) *Repo {
+	return &Repo{log: log, db: db}
+}
+
+const (
+	addressColumns = `team_id, workspace_id, local_part, mode, is_active, allowed_domains, created_by, created_at, updated_at`
+	messageColumns = `inbound_message_id, workspace_id, team_id, message_id, in_reply_to, sender_email, sender_user_id, subject, size_bytes, outcome, reason, story_id, request_id, comment_id, created_at`
+)
+
+type addressRow struct {
+	TeamID         uuid.UUID      `db:"team_id"`
+	WorkspaceID    uuid.UUID      `db:"workspace_id"`
+	LocalPart      string         `db:"local_part"`
+	Mode           string         `db:"mode"`
+	IsActive       bool           `db:"is_active"`
+	AllowedDomains pq.StringArray `db:"allowed_domains"`
+	CreatedBy      *uuid.UUID     `db:"created_by"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+}
+
+type messageRow struct {
+	ID           uuid.UUID  `db:"inbound_message_id"`
+	WorkspaceID  uuid.UUID  `db:"workspace_id"`
+	TeamID       uuid.UUID  `db:"team_id"`
+	MessageID    string     `db:"message_id"`
+	InReplyTo    *string    `db:"in_reply_to"`
+	SenderEmail  string     `db:"sender_email"`
+	SenderUserID *uuid.UUID `db:"sender_user_id"`
+	Subject      string     `db:"subject"`
+	SizeBytes    int        `db:"size_bytes"`
+	Outcome      string     `db:"outcome"`
+	Reason       *string    `db:"reason"`
+	StoryID      *uuid.UUID `db:"story_id"`
+	RequestID    *uuid.UUID `db:"request_id"`
+	CommentID    *uuid.UUID `db:"comment_id"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+func (r *Repo) GetAddress(ctx context.Context, workspaceID, teamID uuid.UUID) (inboundemail.CoreAddress, error) {
+	var row addressRow
+	if err := r.db.GetContext(ctx, &row, `
+		SELECT `+addressColumns+`
+		FROM team_inbound_addresses
+		WHERE workspace_id = $1 AND team_id = $2
+	`, workspaceID, teamID); err != nil {
+		return inboundemail.CoreAddress{}, err
+	}
+	return toCoreAddress(row), nil
+}
+
+func (r *Repo) GetAddressByLocalPart(ctx context.Context, localPart string) (inboundemail.CoreAddress, error) {
+	var row addressRow
+	if err := r.db.GetContext(ctx, &row, `
+		SELECT `+addressColumns+`
+		FROM team_inbound_addresses
+		WHERE local_part = $1
+	`, localPart); err != nil {
+		return inboundemail.CoreAddress{}, err
+	}
+	return toCoreAddress(row), nil
+}
+
+// CreateAddress stores a team's address. It returns sql.ErrNoRows when the
+// team already has one.
+func (r *Repo) CreateAddress(ctx context.Context, input inboundemail.CoreNewAddress, localPart string) (inboundemail.CoreAddress, error) {
+	var row addressRow
+	if err := r.db.GetContext(ctx, &row, `
+		INSERT INTO team_inbound_addresses (team_id, workspace_id, local_part, mode, allowed_domains, created_by)
+		SELECT t.team_id, t.workspace_id, $3, $4, $5, $6
+		FROM teams t
+		WHERE t.workspace_id = $1 AND t.team_id = $2
+		ON CONFLICT (team_id) DO NOTHING
+		RETURNING `+addressColumns,
+		input.WorkspaceID, input.TeamID, localPart, input.Mode, pq.StringArray(input.AllowedDomains), input.CreatedBy); err != nil {
+		return inboundemail.CoreAddress{}, err
+	}
+	return toCoreAddress(row), nil
+}
+
+func (r *Repo) UpdateAddress(ctx context.Context, workspaceID, teamID uuid.UUID, input inboundemail.CoreUpdateAddress) (inboundemail.CoreAddress, error) {
+	var allowedDomains any
+	if input.AllowedDomains != nil {
+		allowedDomains = pq.StringArray(input.AllowedDomains)
+	}
+	var row addressRow
+	if err := r.db.GetContext(ctx, &row, `
+		UPDATE team_inbound_addresses
+		SET mode = COALESCE($3, mode),
+			is_active = COALESCE($4, is_active),
+			allowed_domains = COALESCE($5, allowed_domains),
+			updated_at = NOW()
+		WHERE workspace_id = $1 AND team_id = $2
+		RETURNING `+addressColumns,
+		workspaceID, teamID, input.Mode, input.IsActive, allowedDomains); err != nil {
+		return inboundemail.CoreAddress{}, err
+	}
+	return toCoreAddress(row), nil
+}
+
+func (r *Repo) UpdateAddressLocalPart(ctx context.Context, workspaceID, teamID uuid.UUID, localPart string) (inboundemail.CoreAddress, error) {
+	var row addressRow
+	if err := r.db.GetContext(ctx, &row, `
+		UPDATE team_inbound_addresses
+		SET local_part = $3, updated_at = NOW()
+		WHERE workspace_id = $1 AND team_id = $2
+		RETURNING `+addressColumns,
+		workspaceID, teamID, localPart); err != nil {
+		return inboundemail.CoreAddress{}, err
+	}
+	return toCoreAddress(row), nil
+}
+
+func (r *Repo) DeleteAddress(ctx context.Context, workspaceID, teamID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM team_inbound_addresses
+		WHERE workspace_id = $1 AND team_id = $2
+	`, workspaceID, teamID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *Repo) GetTeamCode(ctx context.Context, workspaceID, teamID uuid.UUID) (string, error) {
+	var code string
+	if err := r.db.GetContext(ctx, &code, `
+		SELECT code FROM teams WHERE workspace_id = $1 AND team_id = $2
+	`, workspaceID, teamID); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// FindMemberByEmail returns the workspace member with the email, or nil when
+// the sender is not a member.
+func (r *Repo) FindMemberByEmail(ctx context.Context, workspaceID uuid.UUID, email string) (*uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.GetContext(ctx, &userID, `
+		SELECT u.user_id
+		FROM workspace_members wm
+		JOIN users u ON u.user_id = wm.user_id
+		WHERE wm.workspace_id = $1
+		  AND lower(u.email) = lower($2)
+		  AND u.is_active = true
+		  AND u.is_system = false
+		LIMIT 1
+	`, workspaceID, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &userID, nil
+}
+
+func (r *Repo) FindFirstStatusByCategory(ctx context.Context, teamID uuid.UUID, category string) (*uuid.UUID, error) {
+	var statusID uuid.UUID
+	err := r.db.GetContext(ctx, &statusID, `SELECT status_id FROM statuses WHERE team_id = $1 AND category = $2 ORDER BY order_index ASC LIMIT 1`, teamID, category)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &statusID, nil
+}
+
+// FindThread returns the most recent filed message among messageIDs that has
+// a story or request to reply to.
+func (r *Repo) FindThread(ctx context.Context, workspaceID uuid.UUID, messageIDs []string) (inboundemail.CoreMessage, error) {
+	var row messageRow
+	if err := r.db.GetContext(ctx, &row, `
+		SELECT `+messageColumns+`
+		FROM inbound_email_messages
+		WHERE workspace_id = $1
+		  AND message_id = ANY($2)
+		  AND outcome <> 'rejected'
+		  AND (story_id IS NOT NULL OR request_id IS NOT NULL)
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, workspaceID, pq.StringArray(messageIDs)); err != nil {
+		return inboundemail.CoreMessage{}, err
+	}
+	return toCoreMessage(row), nil
+}
+
+// CountRecentMessages counts the messages filed for a sender since a time.
+// Rejected messages are not counted so a throttled sender recovers.
+func (r *Repo) CountRecentMessages(ctx context.Context, workspaceID uuid.UUID, senderEmail string, since time.Time) (int, error) {
+	var count int
+	if err := r.db.GetContext(ctx, &count, `
+		SELECT COUNT(*)
+		FROM inbound_email_messages
+		WHERE workspace_id = $1
+		  AND lower(sender_email) = lower($2)
+		  AND created_at >= $3
+		  AND outcome <> 'rejected'
+	`, workspaceID, senderEmail, since); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ClaimMessage logs a message as pending and reports whether this call owns
+// it. When the Message-ID is already logged the stored message is returned
+// instead, unless it is a pending claim older than staleBefore, which is taken
+// over.
+func (r *Repo) ClaimMessage(ctx context.Context, message inboundemail.CoreMessage, staleBefore time.Time) (inboundemail.CoreMessage, bool, error) {
+	var row messageRow
+	err := r.db.GetContext(ctx, &row, `
+		INSERT INTO inbound_email_messages (
+			workspace_id, team_id, message_id, in_reply_to, sender_email, subject, size_bytes, outcome
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, 'pending')
+		ON CONFLICT (workspace_id, message_id) DO UPDATE
+		SET created_at = NOW()
+		WHERE inbound_email_messages.outcome = 'pending'
+		  AND inbound_email_messages.created_at < $8
+		RETURNING `+messageColumns,
+		message.WorkspaceID, message.TeamID, message.MessageID, message.InReplyTo, message.SenderEmail,
+		message.Subject, message.SizeBytes, staleBefore)
+	if errors.Is(err, sql.ErrNoRows) {
+		if err := r.db.GetContext(ctx, &row, `
+			SELECT `+messageColumns+`
+			FROM inbound_email_messages
+			WHERE workspace_id = $1 AND message_id = $2
+		`, message.WorkspaceID, message.MessageID); err != nil {
+			return inboundemail.CoreMessage{}, false, err
+		}
+		return toCoreMessage(row), false, nil
+	}
+	if err != nil {
+		return inboundemail.CoreMessage{}, false, err
+	}
+	return toCoreMessage(row), true, nil
+}
+
+// CompleteMessage stores what became of a claimed message.
+func (r *Repo) CompleteMessage(ctx context.Context, message inboundemail.CoreMessage) (inboundemail.CoreMessage, error) {
+	var row messageRow
+	if err := r.db.GetContext(ctx, &row, `
+		UPDATE inbound_email_messages
+		SET sender_user_id = $2,
+			outcome = $3,
+			reason = $4,
+			story_id = $5,
+			request_id = $6,
+			comment_id = $7
+		WHERE inbound_message_id = $1
+		RETURNING `+messageColumns,
+		message.ID, message.SenderUserID, message.Outcome, message.Reason, message.StoryID, message.RequestID, message.CommentID); err != nil {
+		return inboundemail.CoreMessage{}, err
+	}
+	return toCoreMessage(row), nil
+}
+
+// ReleaseMessage drops a pending claim so a redelivery can file the message.
+func (r *Repo) ReleaseMessage(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM inbound_email_messages
+		WHERE inbound_message_id = $1 AND outcome = 'pending'
+	`, id)
+	return err
+}
+
+// SetThreadStory points the messages of an accepted request at its story.
+func (r *Repo) SetThreadStory(ctx context.Context, workspaceID, requestID, storyID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE inbound_email_messages
+		SET story_id = $3
+		WHERE workspace_id = $1 AND request_id = $2
+	`, workspaceID, requestID, storyID)
+	return err
+}
+
+func toCoreAddress(row addressRow) inboundemail.CoreAddress {
+	allowedDomains := []string(row.AllowedDomains)
+	if allowedDomains == nil {
+		allowedDomains = []string{}
+	}
+	return inboundemail.CoreAddress{
+		TeamID:         row.TeamID,
+		WorkspaceID:    row.WorkspaceID,
+		LocalPart:      row.LocalPart,
+		Mode:           row.Mode,
+		IsActive:       row.IsActive,
+		AllowedDomains: allowedDomains,
+		CreatedBy:      row.CreatedBy,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}
+}
+
+func toCoreMessage(row messageRow) inboundemail.CoreMessage {
+	return inboundemail.CoreMessage{
+		ID:           row.ID,
+		WorkspaceID:  row.WorkspaceID,
+		TeamID:       row.TeamID,
+		MessageID:    row.MessageID,
+		InReplyTo:    row.InReplyTo,
+		SenderEmail:  row.SenderEmail,
+		SenderUserID: row.SenderUserID,
+		Subject:      row.Subject,
+		SizeBytes:    row.SizeBytes,
+		Outcome:      row.Outcome,
+		Reason:       row.Reason,
+		StoryID:      row.StoryID,
+		RequestID:    row.RequestID,
+		CommentID:    row.CommentID,
+		CreatedAt:    row.CreatedAt,
+	}
+}
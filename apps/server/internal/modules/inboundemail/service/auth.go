@@ -0,0 +1,89 @@
+package inboundemail
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+var authCommentPattern = regexp.MustCompile(`\([^()]*\)`)
+
+// senderAuthenticated reports whether the mail provider vouched for the From
+// domain of a message. Only Authentication-Results (RFC 8601) headers stamped
+// with authServID count, since any other header may have been written by the
+// sender. The From domain must pass DMARC, or pass DKIM or SPF with a domain
+// aligned to it. Nothing is trusted while authServID is not configured.
+func senderAuthenticated(header mail.Header, from, authServID string) bool {
+	authServID = strings.ToLower(strings.TrimSpace(authServID))
+	_, fromDomain, ok := strings.Cut(strings.ToLower(from), "@")
+	if authServID == "" || !ok || fromDomain == "" {
+		return false
+	}
+
+	for _, value := range header["Authentication-Results"] {
+		value = authCommentPattern.ReplaceAllString(value, " ")
+		parts := strings.Split(value, ";")
+		fields := strings.Fields(parts[0])
+		if len(fields) == 0 || strings.ToLower(fields[0]) != authServID {
+			continue
+		}
+		// Providers prepend their own result, so the first header stamped
+		// with our authserv-id is the one to trust.
+		for _, resinfo := range parts[1:] {
+			if resultPasses(resinfo, fromDomain) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// resultPasses reports whether one "method=result ptype.property=value" entry
+// is a pass aligned with fromDomain.
+func resultPasses(resinfo, fromDomain string) bool {
+	fields := strings.Fields(strings.ToLower(resinfo))
+	if len(fields) == 0 {
+		return false
+	}
+	method, result, _ := strings.Cut(fields[0], "=")
+	method, _, _ = strings.Cut(method, "/")
+	if result != "pass" {
+		return false
+	}
+
+	properties := make(map[string]string, len(fields)-1)
+	for _, field := range fields[1:] {
+		if key, value, ok := strings.Cut(field, "="); ok {
+			properties[key] = strings.Trim(value, `"`)
+		}
+	}
+
+	switch method {
+	case "dmarc":
+		return properties["header.from"] == fromDomain
+	case "dkim":
+		domain := properties["header.d"]
+		if domain == "" {
+			_, domain, _ = strings.Cut(properties["header.i"], "@")
+		}
+		return domainAligned(domain, fromDomain)
+	case "spf":
+		mailFrom := properties["smtp.mailfrom"]
+		if _, domain, ok := strings.Cut(mailFrom, "@"); ok {
+			mailFrom = domain
+		}
+		return domainAligned(mailFrom, fromDomain)
+	default:
+		return false
+	}
+}
+
+// domainAligned applies relaxed alignment: the authenticated domain is the
+// From domain or one of its parents.
+func domainAligned(domain, fromDomain string) bool {
+	if domain == "" || !strings.Contains(domain, ".") {
+		return false
+	}
+	return domain == fromDomain || strings.HasSuffix(fromDomain, "."+domain)
+}
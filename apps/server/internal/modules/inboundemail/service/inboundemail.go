@@ -0,0 +1,694 @@
+package inboundemail
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	integrationrequests "github.com/complexus-tech/projects-api/internal/modules/integrationrequests/service"
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidInput      = errors.New("invalid inbound email input")
+	ErrNotFound          = sql.ErrNoRows
+	ErrAddressExists     = errors.New("team already has an inbound email address")
+	ErrUnknownRecipient  = errors.New("no inbound email address matches the recipients")
+	ErrMessageTooLarge   = errors.New("email message is too large")
+	ErrInvalidSecret     = errors.New("invalid inbound email secret")
+	ErrMessageInProgress = errors.New("email message is already being processed")
+)
+
+const (
+	SecretHeader = "X-FortyOne-Inbound-Secret"
+
+	defaultMaxMessageBytes    = 25 << 20
+	defaultMaxAttachments     = 10
+	defaultMaxMessagesPerHour = 30
+	maxTitleLength            = 255
+	maxDescriptionLength      = 50000
+	metadataAttachmentIDs     = "email_attachment_ids"
+
+	// claimTimeout is how long a claim may stay pending before a redelivery
+	// takes it over, e.g. after the API crashed mid-way.
+	claimTimeout = 10 * time.Minute
+)
+
+var (
+	subjectPrefixPattern = regexp.MustCompile(`(?i)^\s*(re|fwd?|aw|sv)\s*(\[\d+\])?\s*:\s*`)
+	domainPattern        = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+$`)
+	nonAlphanumeric      = regexp.MustCompile(`[^a-z0-9]+`)
+	localPartEncoding    = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+)
+
+type Service struct {
+	repo        Repository
+	log         *logger.Logger
+	requests    RequestStore
+	stories     StoryService
+	attachments AttachmentService
+	cfg         Config
+	now         func() time.Time
+}
+
+func New(log *logger.Logger, repo Repository, requests RequestStore, storyService StoryService, attachmentService AttachmentService, cfg Config) *Service {
+	cfg.Domain = strings.ToLower(strings.TrimSpace(cfg.Domain))
+	if cfg.MaxMessageBytes <= 0 {
+		cfg.MaxMessageBytes = defaultMaxMessageBytes
+	}
+	if cfg.MaxAttachments <= 0 {
+		cfg.MaxAttachments = defaultMaxAttachments
+	}
+	if cfg.MaxMessagesPerHour <= 0 {
+		cfg.MaxMessagesPerHour = defaultMaxMessagesPerHour
+	}
+	return &Service{
+		repo:        repo,
+		log:         log,
+		requests:    requests,
+		stories:     storyService,
+		attachments: attachmentService,
+		cfg:         cfg,
+		now:         time.Now,
+	}
+}
+
+// MaxMessageBytes is the largest raw message the service accepts.
+func (s *Service) MaxMessageBytes() int64 {
+	return s.cfg.MaxMessageBytes
+}
+
+// EmailAddress returns the full address mail for a team is sent to.
+func (s *Service) EmailAddress(address CoreAddress) string {
+	if s.cfg.Domain == "" {
+		return address.LocalPart
+	}
+	return address.LocalPart + "@" + s.cfg.Domain
+}
+
+// VerifySecret checks the shared secret sent by the mail provider. Inbound
+// email is switched off while no secret is configured.
+func (s *Service) VerifySecret(secret string) error {
+	if s.cfg.Secret == "" || !hmac.Equal([]byte(secret), []byte(s.cfg.Secret)) {
+		return ErrInvalidSecret
+	}
+	return nil
+}
+
+func (s *Service) GetAddress(ctx context.Context, workspaceID, teamID uuid.UUID) (CoreAddress, error) {
+	return s.repo.GetAddress(ctx, workspaceID, teamID)
+}
+
+// CreateAddress gives a team its inbound address. The local part combines the
+// team code with a random token so addresses cannot be guessed.
+func (s *Service) CreateAddress(ctx context.Context, input CoreNewAddress) (CoreAddress, error) {
+	ctx, span := web.AddSpan(ctx, "business.core.inboundemail.CreateAddress")
+	defer span.End()
+
+	if input.Mode == "" {
+		input.Mode = ModeRequest
+	}
+	if err := validateMode(input.Mode); err != nil {
+		return CoreAddress{}, err
+	}
+	domains, err := normalizeDomains(input.AllowedDomains)
+	if err != nil {
+		return CoreAddress{}, err
+	}
+	input.AllowedDomains = domains
+
+	localPart, err := s.newLocalPart(ctx, input.WorkspaceID, input.TeamID)
+	if err != nil {
+		return CoreAddress{}, err
+	}
+	address, err := s.repo.CreateAddress(ctx, input, localPart)
+	if errors.Is(err, ErrNotFound) {
+		return CoreAddress{}, ErrAddressExists
+	}
+	return address, err
+}
+
+func (s *Service) UpdateAddress(ctx context.Context, workspaceID, teamID uuid.UUID, input CoreUpdateAddress) (CoreAddress, error) {
+	ctx, span := web.AddSpan(ctx, "business.core.inboundemail.UpdateAddress")
+	defer span.End()
+
+	if input.Mode != nil {
+		if err := validateMode(*input.Mode); err != nil {
+			return CoreAddress{}, err
+		}
+	}
+	if input.AllowedDomains != nil {
+		domains, err := normalizeDomains(input.AllowedDomains)
+		if err != nil {
+			return CoreAddress{}, err
+		}
+		input.AllowedDomains = domains
+	}
+	return s.repo.UpdateAddress(ctx, workspaceID, teamID, input)
+}
+
+// RotateAddress replaces a team's address, e.g. after it started receiving
+// spam. Mail to the old address is no longer accepted.
+func (s *Service) RotateAddress(ctx context.Context, workspaceID, teamID uuid.UUID) (CoreAddress, error) {
+	ctx, span := web.AddSpan(ctx, "business.core.inboundemail.RotateAddress")
+	defer span.End()
+
+	localPart, err := s.newLocalPart(ctx, workspaceID, teamID)
+	if err != nil {
+		return CoreAddress{}, err
+	}
+	return s.repo.UpdateAddressLocalPart(ctx, workspaceID, teamID, localPart)
+}
+
+func (s *Service) DeleteAddress(ctx context.Context, workspaceID, teamID uuid.UUID) error {
+	return s.repo.DeleteAddress(ctx, workspaceID, teamID)
+}
+
+// Receive turns a raw message posted by the mail provider into a story, an
+// integration request or a comment. recipients are the envelope recipients
+// when the provider knows them; the message's own headers are used otherwise.
+// Spam, automatic replies and senders over the rate limit are logged and
+// rejected without an error so the provider does not retry them.
+// Redeliveries return the first delivery's result, or ErrMessageInProgress
+// while it is still being filed.
+func (s *Service) Receive(ctx context.Context, raw []byte, recipients []string) (CoreResult, error) {
+	ctx, span := web.AddSpan(ctx, "business.core.inboundemail.Receive")
+	defer span.End()
+
+	if int64(len(raw)) > s.cfg.MaxMessageBytes {
+		return CoreResult{}, ErrMessageTooLarge
+	}
+	email, err := ParseMessage(raw)
+	if err != nil {
+		return CoreResult{}, err
+	}
+	if email.From.Address == "" {
+		return CoreResult{}, fmt.Errorf("%w: missing sender", ErrInvalidMessage)
+	}
+
+	address, err := s.resolveAddress(ctx, append(slices.Clone(recipients), email.Recipients...))
+	if err != nil {
+		return CoreResult{}, err
+	}
+
+	message := CoreMessage{
+		WorkspaceID: address.WorkspaceID,
+		TeamID:      address.TeamID,
+		MessageID:   email.MessageID,
+		SenderEmail: email.From.Address,
+		Subject:     truncate(email.Subject, maxTitleLength),
+		SizeBytes:   email.Size,
+		Outcome:     OutcomePending,
+	}
+	if email.InReplyTo != "" {
+		message.InReplyTo = &email.InReplyTo
+	}
+
+	// Claim the Message-ID before filing anything so concurrent redeliveries
+	// of one message cannot both create a story.
+	claimed, won, err := s.repo.ClaimMessage(ctx, message, s.now().Add(-claimTimeout))
+	if err != nil {
+		return CoreResult{}, err
+	}
+	if !won {
+		if claimed.Outcome == OutcomePending {
+			return CoreResult{}, ErrMessageInProgress
+		}
+		result := toResult(claimed)
+		result.AlreadyProcessed = true
+		return result, nil
+	}
+	message.ID = claimed.ID
+
+	result, err := s.file(ctx, email, address, message)
+	if err != nil {
+		// file only fails before anything is written, so the claim is
+		// released and the provider's retry can file the message.
+		if releaseErr := s.repo.ReleaseMessage(ctx, message.ID); releaseErr != nil {
+			s.log.Error(ctx, "failed to release inbound email claim", "message_id", message.MessageID, "error", releaseErr)
+		}
+		return CoreResult{}, err
+	}
+	return result, nil
+}
+
+// file turns a claimed message into a story, request or comment, or rejects it.
+func (s *Service) file(ctx context.Context, email CoreEmail, address CoreAddress, message CoreMessage) (CoreResult, error) {
+	if reason := rejectionReason(email, address); reason != "" {
+		return s.reject(ctx, message, reason)
+	}
+	// The count includes this message's own claim.
+	recent, err := s.repo.CountRecentMessages(ctx, address.WorkspaceID, email.From.Address, s.now().Add(-time.Hour))
+	if err != nil {
+		return CoreResult{}, err
+	}
+	if recent > s.cfg.MaxMessagesPerHour {
+		return s.reject(ctx, message, ReasonRateLimited)
+	}
+
+	// From is trivially forged, so only senders whose domain the provider
+	// authenticated act as workspace members.
+	if senderAuthenticated(email.Header, email.From.Address, s.cfg.AuthServID) {
+		message.SenderUserID, err = s.repo.FindMemberByEmail(ctx, address.WorkspaceID, email.From.Address)
+		if err != nil {
+			return CoreResult{}, err
+		}
+	}
+
+	if threadIDs := email.ThreadIDs(); len(threadIDs) > 0 {
+		thread, err := s.repo.FindThread(ctx, address.WorkspaceID, threadIDs)
+		if err == nil {
+			return s.reply(ctx, email, thread, message)
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return CoreResult{}, err
+		}
+	}
+
+	if address.Mode == ModeStory && message.SenderUserID != nil {
+		return s.createStory(ctx, email, message)
+	}
+	return s.createRequest(ctx, email, message)
+}
+
+// AcceptIntegrationRequest links the attachments of an email request to the
+// accepted story and moves the thread over to it, so later replies become
+// comments on the story.
+func (s *Service) AcceptIntegrationRequest(ctx context.Context, request integrationrequests.CoreIntegrationRequest, story stories.CoreSingleStory) error {
+	if request.Provider != integrationrequests.ProviderEmail {
+		return nil
+	}
+	for _, value := range metadataStrings(request.Metadata, metadataAttachmentIDs) {
+		attachmentID, err := uuid.Parse(value)
+		if err != nil {
+			continue
+		}
+		if err := s.attachments.LinkAttachmentToStory(ctx, story.ID, attachmentID); err != nil {
+			return err
+		}
+	}
+	return s.repo.SetThreadStory(ctx, request.WorkspaceID, request.ID, story.ID)
+}
+
+func (s *Service) resolveAddress(ctx context.Context, recipients []string) (CoreAddress, error) {
+	for _, recipient := range recipients {
+		at := strings.LastIndex(recipient, "@")
+		if at <= 0 {
+			continue
+		}
+		localPart, domain := strings.ToLower(recipient[:at]), strings.ToLower(recipient[at+1:])
+		if s.cfg.Domain != "" && domain != s.cfg.Domain {
+			continue
+		}
+		// Sub-addresses such as eng-x1y2+billing@ reach the same team.
+		if plus := strings.Index(localPart, "+"); plus > 0 {
+			localPart = localPart[:plus]
+		}
+		address, err := s.repo.GetAddressByLocalPart(ctx, localPart)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return CoreAddress{}, err
+		}
+		if address.IsActive {
+			return address, nil
+		}
+	}
+	return CoreAddress{}, ErrUnknownRecipient
+}
+
+func (s *Service) reply(ctx context.Context, email CoreEmail, thread CoreMessage, message CoreMessage) (CoreResult, error) {
+	storyID := thread.StoryID
+	if storyID == nil && thread.RequestID != nil {
+		request, err := s.requests.Get(ctx, message.WorkspaceID, *thread.RequestID)
+		if err != nil {
+			return CoreResult{}, err
+		}
+		switch request.Status {
+		case integrationrequests.StatusPending:
+			return s.appendToRequest(ctx, email, request, message)
+		case integrationrequests.StatusDeclined:
+			return s.reject(ctx, message, ReasonThreadDeclined)
+		}
+		storyID = request.AcceptedStoryID
+	}
+	if storyID == nil {
+		return s.createRequest(ctx, email, message)
+	}
+
+	actorID := s.actorFor(message)
+	body := stripQuotedReply(email.Text)
+	if body == "" {
+		body = "_No message_"
+	}
+	if message.SenderUserID == nil {
+		body = fmt.Sprintf("**%s** replied by email:\n\n%s", senderLabel(email), body)
+	}
+	comment, err := s.stories.CreateCommentExternal(ctx, actorID, message.WorkspaceID, stories.CoreNewComment{
+		StoryID: *storyID,
+		UserID:  actorID,
+		Comment: truncate(body, maxDescriptionLength),
+	})
+	if err != nil {
+		return CoreResult{}, err
+	}
+
+	_, skipped := s.uploadAttachments(ctx, email, actorID, message.WorkspaceID, storyID)
+	message.Outcome = OutcomeComment
+	message.StoryID = storyID
+	message.CommentID = &comment.ID
+	return s.record(ctx, message, skipped)
+}
+
+// appendToRequest adds a reply to a request that is still waiting for triage.
+func (s *Service) appendToRequest(ctx context.Context, email CoreEmail, request integrationrequests.CoreIntegrationRequest, message CoreMessage) (CoreResult, error) {
+	description := ""
+	if request.Description != nil {
+		description = *request.Description
+	}
+	if reply := stripQuotedReply(email.Text); reply != "" {
+		description = fmt.Sprintf("%s\n\n---\n\n**%s** replied:\n\n%s", description, senderLabel(email), reply)
+	}
+	description = truncate(strings.TrimSpace(description), maxDescriptionLength)
+
+	attachmentIDs, skipped := s.uploadAttachments(ctx, email, s.actorFor(message), message.WorkspaceID, nil)
+	metadata := make(map[string]any, len(request.Metadata)+1)
+	for key, value := range request.Metadata {
+		metadata[key] = value
+	}
+	metadata[metadataAttachmentIDs] = append(metadataStrings(request.Metadata, metadataAttachmentIDs), attachmentIDs...)
+
+	updated, err := s.requests.UpsertPending(ctx, integrationrequests.CoreUpsertRequestInput{
+		WorkspaceID:      request.WorkspaceID,
+		TeamID:           request.TeamID,
+		Provider:         request.Provider,
+		SourceType:       request.SourceType,
+		SourceExternalID: request.SourceExternalID,
+		SourceNumber:     request.SourceNumber,
+		SourceURL:        request.SourceURL,
+		Title:            request.Title,
+		Description:      &description,
+		Priority:         request.Priority,
+		Metadata:         metadata,
+		CreatedByUserID:  request.CreatedByUserID,
+	})
+	if err != nil {
+		return CoreResult{}, err
+	}
+
+	message.Outcome = OutcomeRequest
+	message.RequestID = &updated.ID
+	return s.record(ctx, message, skipped)
+}
+
+func (s *Service) createRequest(ctx context.Context, email CoreEmail, message CoreMessage) (CoreResult, error) {
+	attachmentIDs, skipped := s.uploadAttachments(ctx, email, s.actorFor(message), message.WorkspaceID, nil)
+	description := truncate(email.Text, maxDescriptionLength)
+
+	request, err := s.requests.UpsertPending(ctx, integrationrequests.CoreUpsertRequestInput{
+		WorkspaceID:      message.WorkspaceID,
+		TeamID:           message.TeamID,
+		Provider:         integrationrequests.ProviderEmail,
+		SourceType:       SourceTypeEmailMessage,
+		SourceExternalID: email.MessageID,
+		Title:            storyTitle(email),
+		Description:      &description,
+		Metadata: map[string]any{
+			"email_from":          email.From.Address,
+			"email_from_name":     email.From.Name,
+			"email_subject":       email.Subject,
+			"email_message_id":    email.MessageID,
+			metadataAttachmentIDs: attachmentIDs,
+		},
+		CreatedByUserID: message.SenderUserID,
+	})
+	if err != nil {
+		return CoreResult{}, err
+	}
+
+	message.Outcome = OutcomeRequest
+	message.RequestID = &request.ID
+	return s.record(ctx, message, skipped)
+}
+
+func (s *Service) createStory(ctx context.Context, email CoreEmail, message CoreMessage) (CoreResult, error) {
+	statusID, err := s.repo.FindFirstStatusByCategory(ctx, message.TeamID, "unstarted")
+	if err != nil {
+		return CoreResult{}, err
+	}
+	if statusID == nil {
+		return CoreResult{}, errors.New("team has no unstarted status configured")
+	}
+
+	reporterID := *message.SenderUserID
+	description := truncate(email.Text, maxDescriptionLength)
+	descriptionHTML := textToHTML(description)
+	story, err := s.stories.CreateExternal(ctx, reporterID, stories.CoreNewStory{
+		Title:           storyTitle(email),
+		Description:     &description,
+		DescriptionHTML: &descriptionHTML,
+		Status:          statusID,
+		Reporter:        &reporterID,
+		Team:            message.TeamID,
+		Priority:        "No Priority",
+	}, message.WorkspaceID)
+	if err != nil {
+		return CoreResult{}, err
+	}
+
+	_, skipped := s.uploadAttachments(ctx, email, reporterID, message.WorkspaceID, &story.ID)
+	message.Outcome = OutcomeStory
+	message.StoryID = &story.ID
+	return s.record(ctx, message, skipped)
+}
+
+func (s *Service) reject(ctx context.Context, message CoreMessage, reason string) (CoreResult, error) {
+	s.log.Info(ctx, "inbound email rejected", "workspace_id", message.WorkspaceID, "sender", message.SenderEmail, "reason", reason)
+	message.Outcome = OutcomeRejected
+	message.Reason = &reason
+	return s.record(ctx, message, nil)
+}
+
+// record completes a message's claim. Once a story, request or comment
+// exists the claim must not be released, since a retry would file the
+// message again: the claim is completed even if the delivery was cancelled,
+// and if that still fails the delivery succeeds with the claim left pending.
+func (s *Service) record(ctx context.Context, message CoreMessage, skipped []string) (CoreResult, error) {
+	recorded, err := s.repo.CompleteMessage(context.WithoutCancel(ctx), message)
+	if err != nil {
+		if message.Outcome == OutcomeRejected {
+			return CoreResult{}, err
+		}
+		s.log.Error(ctx, "failed to record filed inbound email", "message_id", message.MessageID, "outcome", message.Outcome, "error", err)
+		recorded = message
+	}
+	result := toResult(recorded)
+	result.SkippedAttachments = skipped
+	return result, nil
+}
+
+// uploadAttachments stores a message's attachments, linking them to storyID
+// when it is set. Attachments over the per-message limit or rejected by
+// attachment validation are skipped and returned by name.
+func (s *Service) uploadAttachments(ctx context.Context, email CoreEmail, uploaderID, workspaceID uuid.UUID, storyID *uuid.UUID) ([]string, []string) {
+	uploaded := make([]string, 0, len(email.Attachments))
+	var skipped []string
+	for i, attachment := range email.Attachments {
+		if i >= s.cfg.MaxAttachments {
+			skipped = append(skipped, attachment.Filename)
+			continue
+		}
+		header := &multipart.FileHeader{
+			Filename: attachment.Filename,
+			Size:     int64(len(attachment.Data)),
+			Header:   textproto.MIMEHeader{"Content-Type": {attachment.ContentType}},
+		}
+		info, err := s.attachments.UploadAttachment(ctx, attachmentFile{bytes.NewReader(attachment.Data)}, header, uploaderID, workspaceID)
+		if err != nil {
+			s.log.Warn(ctx, "inbound email attachment skipped", "filename", attachment.Filename, "error", err)
+			skipped = append(skipped, attachment.Filename)
+			continue
+		}
+		if storyID != nil {
+			if err := s.attachments.LinkAttachmentToStory(ctx, *storyID, info.ID); err != nil {
+				s.log.Warn(ctx, "inbound email attachment not linked", "attachment_id", info.ID, "error", err)
+				skipped = append(skipped, attachment.Filename)
+				continue
+			}
+		}
+		uploaded = append(uploaded, info.ID.String())
+	}
+	return uploaded, skipped
+}
+
+// actorFor returns the user a message acts as: the sender when they are a
+// workspace member, the email system user otherwise.
+func (s *Service) actorFor(message CoreMessage) uuid.UUID {
+	if message.SenderUserID != nil {
+		return *message.SenderUserID
+	}
+	return s.cfg.EmailActorID
+}
+
+func (s *Service) newLocalPart(ctx context.Context, workspaceID, teamID uuid.UUID) (string, error) {
+	code, err := s.repo.GetTeamCode(ctx, workspaceID, teamID)
+	if err != nil {
+		return "", err
+	}
+	prefix := strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(code), "-"), "-")
+	if prefix == "" {
+		prefix = "team"
+	}
+	if len(prefix) > 20 {
+		prefix = prefix[:20]
+	}
+	token := make([]byte, 5)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return prefix + "-" + localPartEncoding.EncodeToString(token), nil
+}
+
+// attachmentFile adapts an in-memory attachment to multipart.File.
+type attachmentFile struct {
+	*bytes.Reader
+}
+
+func (attachmentFile) Close() error {
+	return nil
+}
+
+// rejectionReason returns why a message must not be filed, or "" when it is
+// acceptable.
+func rejectionReason(email CoreEmail, address CoreAddress) string {
+	header := email.Header
+	if strings.EqualFold(strings.TrimSpace(header.Get("X-Spam-Flag")), "yes") ||
+		strings.HasPrefix(strings.ToLower(strings.TrimSpace(header.Get("X-Spam-Status"))), "yes") {
+		return ReasonSpam
+	}
+
+	// Out-of-office replies, bounces and mailing lists would otherwise loop
+	// with our own notification mail.
+	if autoSubmitted := strings.ToLower(strings.TrimSpace(header.Get("Auto-Submitted"))); autoSubmitted != "" && autoSubmitted != "no" {
+		return ReasonAutoSubmitted
+	}
+	switch strings.ToLower(strings.TrimSpace(header.Get("Precedence"))) {
+	case "bulk", "junk", "list", "auto_reply":
+		return ReasonAutoSubmitted
+	}
+	if header.Get("X-Autoreply") != "" || header.Get("X-Autorespond") != "" {
+		return ReasonAutoSubmitted
+	}
+	localPart, domain, _ := strings.Cut(email.From.Address, "@")
+	switch localPart {
+	case "mailer-daemon", "postmaster":
+		return ReasonAutoSubmitted
+	}
+
+	if len(address.AllowedDomains) > 0 && !slices.Contains(address.AllowedDomains, domain) {
+		return ReasonSenderDomain
+	}
+	return ""
+}
+
+// storyTitle is the subject without reply and forward prefixes.
+func storyTitle(email CoreEmail) string {
+	title := email.Subject
+	for {
+		stripped := subjectPrefixPattern.ReplaceAllString(title, "")
+		if stripped == title {
+			break
+		}
+		title = stripped
+	}
+	title = strings.Join(strings.Fields(title), " ")
+	if title == "" {
+		title = "Email from " + senderLabel(email)
+	}
+	return truncate(title, maxTitleLength)
+}
+
+func senderLabel(email CoreEmail) string {
+	if name := strings.TrimSpace(email.From.Name); name != "" {
+		return fmt.Sprintf("%s <%s>", name, email.From.Address)
+	}
+	return email.From.Address
+}
+
+func truncate(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+	runes := []rune(value)
+	return string(runes[:limit])
+}
+
+func validateMode(mode string) error {
+	if mode != ModeRequest && mode != ModeStory {
+		return fmt.Errorf("%w: mode must be %q or %q", ErrInvalidInput, ModeRequest, ModeStory)
+	}
+	return nil
+}
+
+func normalizeDomains(domains []string) ([]string, error) {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "@")
+		if domain == "" {
+			continue
+		}
+		if !domainPattern.MatchString(domain) {
+			return nil, fmt.Errorf("%w: invalid sender domain %q", ErrInvalidInput, domain)
+		}
+		if !slices.Contains(normalized, domain) {
+			normalized = append(normalized, domain)
+		}
+	}
+	return normalized, nil
+}
+
+func metadataStrings(metadata map[string]any, key string) []string {
+	switch values := metadata[key].(type) {
+	case []string:
+		return slices.Clone(values)
+	case []any:
+		result := make([]string, 0, len(values))
+		for _, value := range values {
+			if text, ok := value.(string); ok {
+				result = append(result, text)
+			}
+		}
+		return result
+	default:
+		return []string{}
+	}
+}
+
+func toResult(message CoreMessage) CoreResult {
+	result := CoreResult{
+		Outcome:   message.Outcome,
+		StoryID:   message.StoryID,
+		RequestID: message.RequestID,
+		CommentID: message.CommentID,
+	}
+	if message.Reason != nil {
+		result.Reason = *message.Reason
+	}
+	return result
+}
@@ -0,0 +1,646 @@
+package inboundemail
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/mail"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	attachments "github.com/complexus-tech/projects-api/internal/modules/attachments/service"
+	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
+	integrationrequests "github.com/complexus-tech/projects-api/internal/modules/integrationrequests/service"
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type repoStub struct {
+	addresses   map[string]CoreAddress
+	members     map[string]uuid.UUID
+	statusID    uuid.UUID
+	messages    []CoreMessage
+	completeErr error
+}
+
+func (r *repoStub) GetAddress(ctx context.Context, workspaceID, teamID uuid.UUID) (CoreAddress, error) {
+	for _, address := range r.addresses {
+		if address.WorkspaceID == workspaceID && address.TeamID == teamID {
+			return address, nil
+		}
+	}
+	return CoreAddress{}, sql.ErrNoRows
+}
+
+func (r *repoStub) GetAddressByLocalPart(ctx context.Context, localPart string) (CoreAddress, error) {
+	address, ok := r.addresses[localPart]
+	if !ok {
+		return CoreAddress{}, sql.ErrNoRows
+	}
+	return address, nil
+}
+
+func (r *repoStub) CreateAddress(ctx context.Context, input CoreNewAddress, localPart string) (CoreAddress, error) {
+	if _, err := r.GetAddress(ctx, input.WorkspaceID, input.TeamID); err == nil {
+		return CoreAddress{}, sql.ErrNoRows
+	}
+	address := CoreAddress{TeamID: input.TeamID, WorkspaceID: input.WorkspaceID, LocalPart: localPart, Mode: input.Mode, IsActive: true, AllowedDomains: input.AllowedDomains}
+	r.addresses[localPart] = address
+	return address, nil
+}
+
+func (r *repoStub) UpdateAddress(ctx context.Context, workspaceID, teamID uuid.UUID, input CoreUpdateAddress) (CoreAddress, error) {
+	return CoreAddress{}, sql.ErrNoRows
+}
+
+func (r *repoStub) UpdateAddressLocalPart(ctx context.Context, workspaceID, teamID uuid.UUID, localPart string) (CoreAddress, error) {
+	return CoreAddress{}, sql.ErrNoRows
+}
+
+func (r *repoStub) DeleteAddress(ctx context.Context, workspaceID, teamID uuid.UUID) error {
+	return nil
+}
+
+func (r *repoStub) GetTeamCode(ctx context.Context, workspaceID, teamID uuid.UUID) (string, error) {
+	return "OPS", nil
+}
+
+func (r *repoStub) FindMemberByEmail(ctx context.Context, workspaceID uuid.UUID, email string) (*uuid.UUID, error) {
+	if userID, ok := r.members[email]; ok {
+		return &userID, nil
+	}
+	return nil, nil
+}
+
+func (r *repoStub) FindFirstStatusByCategory(ctx context.Context, teamID uuid.UUID, category string) (*uuid.UUID, error) {
+	return &r.statusID, nil
+}
+
+func (r *repoStub) FindThread(ctx context.Context, workspaceID uuid.UUID, messageIDs []string) (CoreMessage, error) {
+	for i := len(r.messages) - 1; i >= 0; i-- {
+		message := r.messages[i]
+		if message.WorkspaceID == workspaceID && message.Outcome != OutcomeRejected && slices.Contains(messageIDs, message.MessageID) {
+			return message, nil
+		}
+	}
+	return CoreMessage{}, sql.ErrNoRows
+}
+
+func (r *repoStub) CountRecentMessages(ctx context.Context, workspaceID uuid.UUID, senderEmail string, since time.Time) (int, error) {
+	count := 0
+	for _, message := range r.messages {
+		if message.SenderEmail == senderEmail && message.Outcome != OutcomeRejected && !message.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *repoStub) ClaimMessage(ctx context.Context, message CoreMessage, staleBefore time.Time) (CoreMessage, bool, error) {
+	for i, existing := range r.messages {
+		if existing.WorkspaceID != message.WorkspaceID || existing.MessageID != message.MessageID {
+			continue
+		}
+		if existing.Outcome == OutcomePending && existing.CreatedAt.Before(staleBefore) {
+			r.messages[i].CreatedAt = time.Now()
+			return r.messages[i], true, nil
+		}
+		return existing, false, nil
+	}
+	message.ID = uuid.New()
+	message.Outcome = OutcomePending
+	message.CreatedAt = time.Now()
+	r.messages = append(r.messages, message)
+	return message, true, nil
+}
+
+func (r *repoStub) CompleteMessage(ctx context.Context, message CoreMessage) (CoreMessage, error) {
+	if r.completeErr != nil {
+		return CoreMessage{}, r.completeErr
+	}
+	for i := range r.messages {
+		if r.messages[i].ID == message.ID {
+			message.CreatedAt = r.messages[i].CreatedAt
+			r.messages[i] = message
+			return message, nil
+		}
+	}
+	return CoreMessage{}, sql.ErrNoRows
+}
+
+func (r *repoStub) ReleaseMessage(ctx context.Context, id uuid.UUID) error {
+	r.messages = slices.DeleteFunc(r.messages, func(message CoreMessage) bool {
+		return message.ID == id && message.Outcome == OutcomePending
+	})
+	return nil
+}
+
+func (r *repoStub) SetThreadStory(ctx context.Context, workspaceID, requestID, storyID uuid.UUID) error {
+	for i := range r.messages {
+		if r.messages[i].RequestID != nil && *r.messages[i].RequestID == requestID {
+			r.messages[i].StoryID = &storyID
+		}
+	}
+	return nil
+}
+
+type requestStoreStub struct {
+	requests map[uuid.UUID]integrationrequests.CoreIntegrationRequest
+}
+
+func (s *requestStoreStub) UpsertPending(ctx context.Context, input integrationrequests.CoreUpsertRequestInput) (integrationrequests.CoreIntegrationRequest, error) {
+	for id, request := range s.requests {
+		if request.Provider == input.Provider && request.SourceExternalID == input.SourceExternalID {
+			request.Description = input.Description
+			request.Metadata = input.Metadata
+			s.requests[id] = request
+			return request, nil
+		}
+	}
+	request := integrationrequests.CoreIntegrationRequest{
+		ID:               uuid.New(),
+		WorkspaceID:      input.WorkspaceID,
+		TeamID:           input.TeamID,
+		Provider:         input.Provider,
+		SourceType:       input.SourceType,
+		SourceExternalID: input.SourceExternalID,
+		Title:            input.Title,
+		Description:      input.Description,
+		Status:           integrationrequests.StatusPending,
+		Metadata:         input.Metadata,
+	}
+	s.requests[request.ID] = request
+	return request, nil
+}
+
+func (s *requestStoreStub) Get(ctx context.Context, workspaceID, requestID uuid.UUID) (integrationrequests.CoreIntegrationRequest, error) {
+	request, ok := s.requests[requestID]
+	if !ok {
+		return integrationrequests.CoreIntegrationRequest{}, sql.ErrNoRows
+	}
+	return request, nil
+}
+
+type storyServiceStub struct {
+	stories  []stories.CoreNewStory
+	comments []stories.CoreNewComment
+	err      error
+}
+
+func (s *storyServiceStub) CreateExternal(ctx context.Context, actorID uuid.UUID, ns stories.CoreNewStory, workspaceID uuid.UUID) (stories.CoreSingleStory, error) {
+	if s.err != nil {
+		return stories.CoreSingleStory{}, s.err
+	}
+	s.stories = append(s.stories, ns)
+	return stories.CoreSingleStory{ID: uuid.New(), Title: ns.Title}, nil
+}
+
+func (s *storyServiceStub) CreateCommentExternal(ctx context.Context, actorID uuid.UUID, workspaceID uuid.UUID, cnc stories.CoreNewComment) (comments.CoreComment, error) {
+	s.comments = append(s.comments, cnc)
+	return comments.CoreComment{ID: uuid.New()}, nil
+}
+
+type attachmentServiceStub struct {
+	uploaded []string
+	linked   map[uuid.UUID][]uuid.UUID
+}
+
+func (s *attachmentServiceStub) UploadAttachment(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, userID uuid.UUID, workspaceID uuid.UUID) (attachments.FileInfo, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return attachments.FileInfo{}, err
+	}
+	s.uploaded = append(s.uploaded, fileHeader.Filename+":"+string(data))
+	return attachments.FileInfo{ID: uuid.New(), Filename: fileHeader.Filename}, nil
+}
+
+func (s *attachmentServiceStub) LinkAttachmentToStory(ctx context.Context, storyID, attachmentID uuid.UUID) error {
+	s.linked[storyID] = append(s.linked[storyID], attachmentID)
+	return nil
+}
+
+type fixture struct {
+	service     *Service
+	repo        *repoStub
+	requests    *requestStoreStub
+	stories     *storyServiceStub
+	attachments *attachmentServiceStub
+	address     CoreAddress
+	memberID    uuid.UUID
+}
+
+func newFixture(t *testing.T, mode string, allowedDomains ...string) *fixture {
+	t.Helper()
+
+	address := CoreAddress{
+		TeamID:         uuid.New(),
+		WorkspaceID:    uuid.New(),
+		LocalPart:      "ops-x1y2z3",
+		Mode:           mode,
+		IsActive:       true,
+		AllowedDomains: allowedDomains,
+	}
+	memberID := uuid.New()
+	f := &fixture{
+		repo: &repoStub{
+			addresses: map[string]CoreAddress{address.LocalPart: address},
+			members:   map[string]uuid.UUID{"jane@example.com": memberID},
+			statusID:  uuid.New(),
+		},
+		requests:    &requestStoreStub{requests: map[uuid.UUID]integrationrequests.CoreIntegrationRequest{}},
+		stories:     &storyServiceStub{},
+		attachments: &attachmentServiceStub{linked: map[uuid.UUID][]uuid.UUID{}},
+		address:     address,
+		memberID:    memberID,
+	}
+	f.service = New(logger.NewWithText(io.Discard, slog.LevelError, "inboundemail-test"), f.repo, f.requests, f.stories, f.attachments, Config{
+		Domain:             "in.fortyone.test",
+		Secret:             "secret",
+		AuthServID:         "mx.fortyone.test",
+		EmailActorID:       uuid.New(),
+		MaxMessagesPerHour: 2,
+	})
+	return f
+}
+
+func rawEmail(from, messageID, subject, body string, headers ...string) []byte {
+	lines := []string{
+		"From: " + from,
+		"To: ops-x1y2z3@in.fortyone.test",
+		"Subject: " + subject,
+		"Message-ID: <" + messageID + ">",
+	}
+	lines = append(lines, headers...)
+	return []byte(strings.Join(lines, "\r\n") + "\r\n\r\n" + body + "\r\n")
+}
+
+const janeAuthenticated = "Authentication-Results: mx.fortyone.test; dmarc=pass (p=reject) header.from=example.com"
+
+const multipartEmail = "From: \"Jane Doe\" <Jane@Example.com>\r\n" +
+	"To: ops-x1y2z3+billing@in.fortyone.test\r\n" +
+	"Subject: =?UTF-8?Q?Invoice_=E2=82=AC_missing?=\r\n" +
+	"Message-ID: <abc@mail.example.com>\r\n" +
+	"Authentication-Results: mx.fortyone.test; dkim=pass header.d=example.com; dmarc=pass header.from=example.com\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"The invoice for =E2=82=AC120 never arrived.\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>The invoice never arrived.</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/csv; name=\"invoice.csv\"\r\n" +
+	"Content-Disposition: attachment; filename=\"invoice.csv\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"YSxiLGMK\r\n" +
+	"--outer--\r\n"
+
+func TestParseMessageReadsMultipartBodyAndAttachments(t *testing.T) {
+	email, err := ParseMessage([]byte(multipartEmail))
+	require.NoError(t, err)
+
+	require.Equal(t, "jane@example.com", email.From.Address)
+	require.Equal(t, "Jane Doe", email.From.Name)
+	require.Equal(t, "Invoice € missing", email.Subject)
+	require.Equal(t, "abc@mail.example.com", email.MessageID)
+	require.Equal(t, "The invoice for €120 never arrived.", email.Text)
+	require.Equal(t, "<p>The invoice never arrived.</p>", strings.TrimSpace(email.HTML))
+	require.Len(t, email.Attachments, 1)
+	require.Equal(t, "invoice.csv", email.Attachments[0].Filename)
+	require.Equal(t, "a,b,c\n", string(email.Attachments[0].Data))
+}
+
+func TestParseMessageFallsBackToHTMLText(t *testing.T) {
+	raw := "From: jane@example.com\r\n" +
+		"Subject: Hi\r\n" +
+		"Content-Type: text/html; charset=utf-8\r\n" +
+		"\r\n" +
+		"<html><head><style>p{}</style></head><body><p>First &amp; foremost</p><p>Second<br>line</p></body></html>\r\n"
+
+	email, err := ParseMessage([]byte(raw))
+	require.NoError(t, err)
+	require.Equal(t, "First & foremost\nSecond\nline", email.Text)
+	require.NotEmpty(t, email.MessageID, "expected a message id derived from the content")
+}
+
+func TestStripQuotedReply(t *testing.T) {
+	text := "Still broken on my side.\n\nOn Mon, 3 Mar 2025 at 10:00, Ops <ops@in.fortyone.test> wrote:\n> The printer is fixed."
+	require.Equal(t, "Still broken on my side.", stripQuotedReply(text))
+}
+
+func TestReceiveCreatesStoryForKnownSenderInStoryMode(t *testing.T) {
+	f := newFixture(t, ModeStory)
+
+	result, err := f.service.Receive(context.Background(), []byte(multipartEmail), nil)
+	require.NoError(t, err)
+
+	require.Equal(t, OutcomeStory, result.Outcome)
+	require.NotNil(t, result.StoryID)
+	require.Len(t, f.stories.stories, 1)
+	story := f.stories.stories[0]
+	require.Equal(t, "Invoice € missing", story.Title)
+	require.Equal(t, f.address.TeamID, story.Team)
+	require.Equal(t, f.memberID, *story.Reporter)
+	require.Equal(t, f.repo.statusID, *story.Status)
+	require.Equal(t, []string{"invoice.csv:a,b,c\n"}, f.attachments.uploaded)
+	require.Len(t, f.attachments.linked[*result.StoryID], 1)
+}
+
+func TestReceiveTreatsUnauthenticatedMemberAsUnknown(t *testing.T) {
+	tests := map[string][]string{
+		"no authentication results":   nil,
+		"results from another server": {"Authentication-Results: mx.attacker.test; dmarc=pass header.from=example.com"},
+		"dmarc failed":                {"Authentication-Results: mx.fortyone.test; dmarc=fail header.from=example.com"},
+		"dkim for another domain":     {"Authentication-Results: mx.fortyone.test; dkim=pass header.d=attacker.test; spf=pass smtp.mailfrom=bounce@attacker.test"},
+	}
+	for name, headers := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := newFixture(t, ModeStory)
+
+			raw := rawEmail("Jane <jane@example.com>", "forged@attacker.test", "Delete production", "Please", headers...)
+			result, err := f.service.Receive(context.Background(), raw, nil)
+			require.NoError(t, err)
+
+			require.Equal(t, OutcomeRequest, result.Outcome)
+			require.Empty(t, f.stories.stories)
+			require.Nil(t, f.requests.requests[*result.RequestID].CreatedByUserID)
+			require.Nil(t, f.repo.messages[0].SenderUserID)
+		})
+	}
+}
+
+func TestReceiveCommentsAsEmailActorForForgedReply(t *testing.T) {
+	f := newFixture(t, ModeRequest)
+	ctx := context.Background()
+
+	first, err := f.service.Receive(ctx, rawEmail("bob@customer.test", "m1@customer.test", "Printer", "It is down."), nil)
+	require.NoError(t, err)
+	storyID := uuid.New()
+	request := f.requests.requests[*first.RequestID]
+	request.Status = integrationrequests.StatusAccepted
+	request.AcceptedStoryID = &storyID
+	f.requests.requests[request.ID] = request
+	require.NoError(t, f.service.AcceptIntegrationRequest(ctx, request, stories.CoreSingleStory{ID: storyID}))
+
+	_, err = f.service.Receive(ctx, rawEmail("jane@example.com", "m2@attacker.test", "Re: Printer", "Close it.", "In-Reply-To: <m1@customer.test>"), nil)
+	require.NoError(t, err)
+	require.Len(t, f.stories.comments, 1)
+	require.Equal(t, f.service.cfg.EmailActorID, f.stories.comments[0].UserID)
+	require.Contains(t, f.stories.comments[0].Comment, "**jane@example.com** replied by email:")
+}
+
+func TestSenderAuthenticated(t *testing.T) {
+	header := func(values ...string) mail.Header {
+		return mail.Header{"Authentication-Results": values}
+	}
+	tests := []struct {
+		name   string
+		header mail.Header
+		want   bool
+	}{
+		{"dmarc pass", header("mx.fortyone.test; dmarc=pass header.from=sub.example.com"), true},
+		{"aligned dkim on parent domain", header("MX.fortyone.test 1; dkim=pass header.d=example.com header.s=s1"), true},
+		{"spf pass with aligned mail from", header("mx.fortyone.test; spf=pass smtp.mailfrom=bounces@example.com"), true},
+		{"pass inside comment", header("mx.fortyone.test; dkim=fail (dkim=pass header.d=example.com) header.d=example.com"), false},
+		{"dmarc for other domain", header("mx.fortyone.test; dmarc=pass header.from=attacker.test"), false},
+		{"unaligned dkim", header("mx.fortyone.test; dkim=pass header.d=mail.example.com"), false},
+		{"forged header below provider header", header("mx.fortyone.test; dmarc=fail header.from=sub.example.com", "mx.fortyone.test; dmarc=pass header.from=sub.example.com"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, senderAuthenticated(tt.header, "jane@sub.example.com", "mx.fortyone.test"))
+		})
+	}
+
+	require.False(t, senderAuthenticated(header("; dmarc=pass header.from=example.com"), "jane@example.com", ""))
+}
+
+func TestReceiveFilesRequestForUnknownSender(t *testing.T) {
+	f := newFixture(t, ModeStory)
+
+	raw := rawEmail("Bob <bob@customer.test>", "m1@customer.test", "Re: Printer is down", "The printer on floor 2 is down.")
+	result, err := f.service.Receive(context.Background(), raw, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, OutcomeRequest, result.Outcome)
+	require.Empty(t, f.stories.stories)
+	request := f.requests.requests[*result.RequestID]
+	require.Equal(t, integrationrequests.ProviderEmail, request.Provider)
+	require.Equal(t, "m1@customer.test", request.SourceExternalID)
+	require.Equal(t, "Printer is down", request.Title)
+	require.Equal(t, "The printer on floor 2 is down.", *request.Description)
+	require.Equal(t, "bob@customer.test", request.Metadata["email_from"])
+}
+
+func TestReceiveRejectsSpamAndAutomaticReplies(t *testing.T) {
+	f := newFixture(t, ModeRequest)
+
+	spam := rawEmail("bob@customer.test", "spam@customer.test", "Win", "Click here", "X-Spam-Flag: YES")
+	result, err := f.service.Receive(context.Background(), spam, nil)
+	require.NoError(t, err)
+	require.Equal(t, OutcomeRejected, result.Outcome)
+	require.Equal(t, ReasonSpam, result.Reason)
+
+	autoReply := rawEmail("bob@customer.test", "ooo@customer.test", "Out of office", "Back Monday", "Auto-Submitted: auto-replied")
+	result, err = f.service.Receive(context.Background(), autoReply, nil)
+	require.NoError(t, err)
+	require.Equal(t, OutcomeRejected, result.Outcome)
+	require.Equal(t, ReasonAutoSubmitted, result.Reason)
+	require.Empty(t, f.requests.requests)
+}
+
+func TestReceiveRejectsSendersOutsideAllowedDomains(t *testing.T) {
+	f := newFixture(t, ModeRequest, "example.com")
+
+	result, err := f.service.Receive(context.Background(), rawEmail("bob@customer.test", "m1@customer.test", "Help", "Please"), nil)
+	require.NoError(t, err)
+	require.Equal(t, ReasonSenderDomain, result.Reason)
+
+	result, err = f.service.Receive(context.Background(), rawEmail("jane@example.com", "m2@example.com", "Help", "Please"), nil)
+	require.NoError(t, err)
+	require.Equal(t, OutcomeRequest, result.Outcome)
+}
+
+func TestReceiveRateLimitsSender(t *testing.T) {
+	f := newFixture(t, ModeRequest)
+
+	for i, id := range []string{"m1@customer.test", "m2@customer.test", "m3@customer.test"} {
+		result, err := f.service.Receive(context.Background(), rawEmail("bob@customer.test", id, "Help", "Please"), nil)
+		require.NoError(t, err)
+		if i < 2 {
+			require.Equal(t, OutcomeRequest, result.Outcome)
+		} else {
+			require.Equal(t, ReasonRateLimited, result.Reason)
+		}
+	}
+}
+
+func TestReceiveIgnoresUnknownAndForeignRecipients(t *testing.T) {
+	f := newFixture(t, ModeRequest)
+
+	raw := []byte("From: bob@customer.test\r\nTo: ops-x1y2z3@elsewhere.test\r\nSubject: Hi\r\n\r\nHello\r\n")
+	_, err := f.service.Receive(context.Background(), raw, []string{"nobody@in.fortyone.test"})
+	require.ErrorIs(t, err, ErrUnknownRecipient)
+}
+
+func TestReceiveIsIdempotentForRedeliveries(t *testing.T) {
+	f := newFixture(t, ModeRequest)
+	raw := rawEmail("bob@customer.test", "m1@customer.test", "Help", "Please")
+
+	first, err := f.service.Receive(context.Background(), raw, nil)
+	require.NoError(t, err)
+	second, err := f.service.Receive(context.Background(), raw, nil)
+	require.NoError(t, err)
+
+	require.True(t, second.AlreadyProcessed)
+	require.Equal(t, first.RequestID, second.RequestID)
+	require.Len(t, f.requests.requests, 1)
+	require.Len(t, f.repo.messages, 1)
+}
+
+func TestReceiveDoesNotFileMessageClaimedByAnotherDelivery(t *testing.T) {
+	f := newFixture(t, ModeStory)
+	email, err := ParseMessage([]byte(multipartEmail))
+	require.NoError(t, err)
+
+	_, won, err := f.repo.ClaimMessage(context.Background(), CoreMessage{WorkspaceID: f.address.WorkspaceID, MessageID: email.MessageID}, time.Now().Add(-claimTimeout))
+	require.NoError(t, err)
+	require.True(t, won)
+
+	_, err = f.service.Receive(context.Background(), []byte(multipartEmail), nil)
+	require.ErrorIs(t, err, ErrMessageInProgress)
+	require.Empty(t, f.stories.stories)
+	require.Empty(t, f.attachments.uploaded)
+}
+
+func TestReceiveTakesOverStaleClaim(t *testing.T) {
+	f := newFixture(t, ModeStory)
+	f.repo.messages = append(f.repo.messages, CoreMessage{
+		ID:          uuid.New(),
+		WorkspaceID: f.address.WorkspaceID,
+		MessageID:   "abc@mail.example.com",
+		Outcome:     OutcomePending,
+		CreatedAt:   time.Now().Add(-2 * claimTimeout),
+	})
+
+	result, err := f.service.Receive(context.Background(), []byte(multipartEmail), nil)
+	require.NoError(t, err)
+	require.Equal(t, OutcomeStory, result.Outcome)
+	require.Len(t, f.stories.stories, 1)
+	require.Len(t, f.repo.messages, 1)
+}
+
+func TestReceiveReleasesClaimWhenFilingFails(t *testing.T) {
+	f := newFixture(t, ModeStory)
+	f.stories.err = errors.New("database unavailable")
+
+	_, err := f.service.Receive(context.Background(), []byte(multipartEmail), nil)
+	require.Error(t, err)
+	require.Empty(t, f.repo.messages)
+
+	// The provider's retry files the message once the failure is gone.
+	f.stories.err = nil
+	result, err := f.service.Receive(context.Background(), []byte(multipartEmail), nil)
+	require.NoError(t, err)
+	require.Equal(t, OutcomeStory, result.Outcome)
+	require.False(t, result.AlreadyProcessed)
+}
+
+func TestReceiveKeepsClaimWhenRecordingFails(t *testing.T) {
+	f := newFixture(t, ModeStory)
+	f.repo.completeErr = errors.New("database unavailable")
+
+	result, err := f.service.Receive(context.Background(), []byte(multipartEmail), nil)
+	require.NoError(t, err)
+	require.Equal(t, OutcomeStory, result.Outcome)
+	require.NotNil(t, result.StoryID)
+	require.Len(t, f.repo.messages, 1)
+
+	// The story exists, so a redelivery must not file the message again.
+	f.repo.completeErr = nil
+	_, err = f.service.Receive(context.Background(), []byte(multipartEmail), nil)
+	require.ErrorIs(t, err, ErrMessageInProgress)
+	require.Len(t, f.stories.stories, 1)
+}
+
+func TestReceiveThreadsRepliesOntoRequestAndStory(t *testing.T) {
+	f := newFixture(t, ModeRequest)
+	ctx := context.Background()
+
+	first, err := f.service.Receive(ctx, rawEmail("bob@customer.test", "m1@customer.test", "Printer", "It is down."), nil)
+	require.NoError(t, err)
+
+	// While the request is pending, replies are appended to it.
+	reply, err := f.service.Receive(ctx, rawEmail("bob@customer.test", "m2@customer.test", "Re: Printer", "Still down.\n\nOn Mon, Ops wrote:\n> hi", "In-Reply-To: <m1@customer.test>"), nil)
+	require.NoError(t, err)
+	require.Equal(t, OutcomeRequest, reply.Outcome)
+	require.Equal(t, first.RequestID, reply.RequestID)
+	request := f.requests.requests[*first.RequestID]
+	require.Contains(t, *request.Description, "Still down.")
+	require.NotContains(t, *request.Description, "> hi")
+
+	// Once accepted, replies become comments on the story.
+	storyID := uuid.New()
+	request.Status = integrationrequests.StatusAccepted
+	request.AcceptedStoryID = &storyID
+	f.requests.requests[request.ID] = request
+	require.NoError(t, f.service.AcceptIntegrationRequest(ctx, request, stories.CoreSingleStory{ID: storyID}))
+
+	comment, err := f.service.Receive(ctx, rawEmail("jane@example.com", "m3@example.com", "Re: Printer", "Fixed now.", "References: <m1@customer.test> <m2@customer.test>", janeAuthenticated), nil)
+	require.NoError(t, err)
+	require.Equal(t, OutcomeComment, comment.Outcome)
+	require.Equal(t, storyID, *comment.StoryID)
+	require.Len(t, f.stories.comments, 1)
+	require.Equal(t, "Fixed now.", f.stories.comments[0].Comment)
+	require.Equal(t, f.memberID, f.stories.comments[0].UserID)
+}
+
+func TestAcceptIntegrationRequestLinksEmailAttachments(t *testing.T) {
+	f := newFixture(t, ModeRequest)
+	attachmentID := uuid.New()
+	storyID := uuid.New()
+
+	err := f.service.AcceptIntegrationRequest(context.Background(), integrationrequests.CoreIntegrationRequest{
+		ID:          uuid.New(),
+		WorkspaceID: f.address.WorkspaceID,
+		Provider:    integrationrequests.ProviderEmail,
+		Metadata:    map[string]any{metadataAttachmentIDs: []any{attachmentID.String()}},
+	}, stories.CoreSingleStory{ID: storyID})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{attachmentID}, f.attachments.linked[storyID])
+}
+
+func TestCreateAddressUsesTeamCodePrefix(t *testing.T) {
+	f := newFixture(t, ModeRequest)
+
+	address, err := f.service.CreateAddress(context.Background(), CoreNewAddress{
+		TeamID:         uuid.New(),
+		WorkspaceID:    f.address.WorkspaceID,
+		AllowedDomains: []string{"@Example.com", "example.com"},
+	})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(address.LocalPart, "ops-"))
+	require.Len(t, address.LocalPart, len("ops-")+8)
+	require.Equal(t, ModeRequest, address.Mode)
+	require.Equal(t, []string{"example.com"}, address.AllowedDomains)
+
+	_, err = f.service.CreateAddress(context.Background(), CoreNewAddress{TeamID: address.TeamID, WorkspaceID: address.WorkspaceID})
+	require.ErrorIs(t, err, ErrAddressExists)
+}
@@ -0,0 +1,299 @@
+package inboundemail
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+var ErrInvalidMessage = errors.New("invalid email message")
+
+// maxPartDepth bounds how deeply multipart bodies may nest.
+const maxPartDepth = 10
+
+var recipientHeaders = []string{"X-Original-To", "Delivered-To", "To", "Cc"}
+
+var wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// ParseMessage parses a raw RFC 5322 message. The text body is taken from the
+// first text/plain part, falling back to the text of the first text/html
+// part. Parts with a file name or an attachment disposition are returned as
+// attachments.
+func ParseMessage(raw []byte) (CoreEmail, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return CoreEmail{}, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	parser := mail.AddressParser{WordDecoder: wordDecoder}
+	from, err := parser.Parse(msg.Header.Get("From"))
+	if err != nil {
+		return CoreEmail{}, fmt.Errorf("%w: invalid sender: %v", ErrInvalidMessage, err)
+	}
+	from.Address = strings.ToLower(strings.TrimSpace(from.Address))
+
+	subject, err := wordDecoder.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+
+	email := CoreEmail{
+		MessageID:  normalizeMessageID(msg.Header.Get("Message-ID")),
+		InReplyTo:  normalizeMessageID(msg.Header.Get("In-Reply-To")),
+		References: parseMessageIDs(msg.Header.Get("References")),
+		From:       *from,
+		Subject:    strings.TrimSpace(subject),
+		Header:     msg.Header,
+		Size:       len(raw),
+	}
+	if email.MessageID == "" {
+		// Without a Message-ID redeliveries of the same message must still
+		// map to one story, so derive one from the content.
+		sum := sha256.Sum256(raw)
+		email.MessageID = hex.EncodeToString(sum[:16]) + "@inbound.fortyone.app"
+	}
+	for _, key := range recipientHeaders {
+		for _, value := range msg.Header[key] {
+			addresses, err := parser.ParseList(value)
+			if err != nil {
+				continue
+			}
+			for _, address := range addresses {
+				email.Recipients = append(email.Recipients, strings.ToLower(address.Address))
+			}
+		}
+	}
+
+	if err := email.readPart(textproto.MIMEHeader(msg.Header), msg.Body, 0); err != nil {
+		return CoreEmail{}, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	if strings.TrimSpace(email.Text) == "" && email.HTML != "" {
+		email.Text = htmlToText(email.HTML)
+	}
+	email.Text = strings.TrimSpace(normalizeNewlines(email.Text))
+	return email, nil
+}
+
+// ThreadIDs returns the Message-IDs the message replies to, most recent first.
+func (e CoreEmail) ThreadIDs() []string {
+	ids := make([]string, 0, len(e.References)+1)
+	if e.InReplyTo != "" {
+		ids = append(ids, e.InReplyTo)
+	}
+	for i := len(e.References) - 1; i >= 0; i-- {
+		if e.References[i] != e.InReplyTo {
+			ids = append(ids, e.References[i])
+		}
+	}
+	return ids
+}
+
+func (e *CoreEmail) readPart(header textproto.MIMEHeader, body io.Reader, depth int) error {
+	if depth > maxPartDepth {
+		return errors.New("message parts are nested too deeply")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	body = decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body)
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := e.readPart(part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if decoded, err := wordDecoder.DecodeHeader(filename); err == nil {
+		filename = decoded
+	}
+	isText := mediaType == "text/plain" || mediaType == "text/html"
+	if disposition == "attachment" || filename != "" || !isText {
+		if len(data) == 0 {
+			return nil
+		}
+		e.Attachments = append(e.Attachments, CoreEmailAttachment{
+			Filename:    attachmentFilename(filename, mediaType, len(e.Attachments)),
+			ContentType: mediaType,
+			Data:        data,
+		})
+		return nil
+	}
+
+	text := decodeCharset(data, params["charset"])
+	switch {
+	case mediaType == "text/plain" && e.Text == "":
+		e.Text = text
+	case mediaType == "text/html" && e.HTML == "":
+		e.HTML = text
+	}
+	return nil
+}
+
+func decodeTransferEncoding(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, err
+	}
+	return encoding.NewDecoder().Reader(input), nil
+}
+
+// decodeCharset converts text in charset to UTF-8. Unknown charsets are
+// treated as UTF-8 with invalid bytes dropped.
+func decodeCharset(data []byte, charset string) string {
+	charset = strings.ToLower(strings.TrimSpace(charset))
+	if charset != "" && charset != "utf-8" && charset != "us-ascii" {
+		if encoding, err := htmlindex.Get(charset); err == nil {
+			if decoded, err := encoding.NewDecoder().Bytes(data); err == nil {
+				data = decoded
+			}
+		}
+	}
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	return strings.ToValidUTF8(string(data), "")
+}
+
+func attachmentFilename(filename, mediaType string, index int) string {
+	filename = strings.TrimSpace(filepath.Base(strings.ReplaceAll(filename, `\`, "/")))
+	if filename != "" && filename != "." && filename != "/" {
+		return filename
+	}
+	name := fmt.Sprintf("attachment-%d", index+1)
+	if extensions, err := mime.ExtensionsByType(mediaType); err == nil && len(extensions) > 0 {
+		name += extensions[0]
+	}
+	return name
+}
+
+func normalizeMessageID(value string) string {
+	ids := parseMessageIDs(value)
+	if len(ids) == 0 {
+		return ""
+	}
+	return ids[0]
+}
+
+// parseMessageIDs returns the message ids in a References style header
+// without their angle brackets.
+func parseMessageIDs(value string) []string {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == ','
+	})
+	ids := make([]string, 0, len(fields))
+	for _, field := range fields {
+		id := strings.TrimSpace(strings.Trim(field, "<>"))
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+var (
+	htmlHiddenPattern = regexp.MustCompile(`(?is)<(script|style|head)\b.*?</(script|style|head)\s*>`)
+	htmlBreakPattern  = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|tr|h[1-6]|blockquote)\s*>`)
+	htmlTagPattern    = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLinesPattern = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText reduces an HTML body to its text, keeping paragraph breaks.
+func htmlToText(body string) string {
+	body = htmlHiddenPattern.ReplaceAllString(body, "")
+	body = htmlBreakPattern.ReplaceAllString(body, "\n")
+	body = htmlTagPattern.ReplaceAllString(body, "")
+	body = html.UnescapeString(body)
+
+	lines := strings.Split(normalizeNewlines(body), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(strings.ReplaceAll(line, "\u00a0", " "))
+	}
+	return strings.TrimSpace(blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+func normalizeNewlines(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+}
+
+var (
+	replyHeaderPattern = regexp.MustCompile(`^On .+wrote:$`)
+	separatorPattern   = regexp.MustCompile(`^-{2,}\s*(Original Message|Forwarded message)\s*-{2,}$`)
+)
+
+// stripQuotedReply drops the quoted conversation mail clients append below a
+// reply, keeping only what the sender wrote.
+func stripQuotedReply(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if replyHeaderPattern.MatchString(trimmed) || separatorPattern.MatchString(trimmed) || strings.HasPrefix(trimmed, ">") {
+			lines = lines[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// textToHTML renders plain text as escaped paragraphs.
+func textToHTML(text string) string {
+	var b strings.Builder
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		b.WriteString("</p>")
+	}
+	return b.String()
+}
@@ -0,0 +1,172 @@
+package inboundemail
+
+import (
+	"context"
+	"mime/multipart"
+	"net/mail"
+	"time"
+
+	attachments "github.com/complexus-tech/projects-api/internal/modules/attachments/service"
+	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
+	integrationrequests "github.com/complexus-tech/projects-api/internal/modules/integrationrequests/service"
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/google/uuid"
+)
+
+const (
+	// ModeRequest files every message as an integration request for triage.
+	ModeRequest = "request"
+	// ModeStory creates stories directly for mail from workspace members.
+	// Mail from anyone else still becomes a request.
+	ModeStory = "story"
+
+	// OutcomePending marks a message claimed by a delivery still filing it.
+	OutcomePending  = "pending"
+	OutcomeStory    = "story"
+	OutcomeRequest  = "request"
+	OutcomeComment  = "comment"
+	OutcomeRejected = "rejected"
+
+	ReasonSpam           = "spam"
+	ReasonAutoSubmitted  = "auto_submitted"
+	ReasonSenderDomain   = "sender_domain"
+	ReasonRateLimited    = "rate_limited"
+	ReasonThreadDeclined = "thread_declined"
+
+	SourceTypeEmailMessage = "email_message"
+)
+
+type CoreAddress struct {
+	TeamID         uuid.UUID
+	WorkspaceID    uuid.UUID
+	LocalPart      string
+	Mode           string
+	IsActive       bool
+	AllowedDomains []string
+	CreatedBy      *uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type CoreNewAddress struct {
+	TeamID         uuid.UUID
+	WorkspaceID    uuid.UUID
+	Mode           string
+	AllowedDomains []string
+	CreatedBy      uuid.UUID
+}
+
+type CoreUpdateAddress struct {
+	Mode           *string
+	IsActive       *bool
+	AllowedDomains []string
+}
+
+// CoreEmail is a parsed inbound message.
+type CoreEmail struct {
+	MessageID   string
+	InReplyTo   string
+	References  []string
+	From        mail.Address
+	Recipients  []string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []CoreEmailAttachment
+	Header      mail.Header
+	Size        int
+}
+
+type CoreEmailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// CoreMessage is the log entry of a received message. Replies are threaded
+// onto the story or request of the message they answer.
+type CoreMessage struct {
+	ID           uuid.UUID
+	WorkspaceID  uuid.UUID
+	TeamID       uuid.UUID
+	MessageID    string
+	InReplyTo    *string
+	SenderEmail  string
+	SenderUserID *uuid.UUID
+	Subject      string
+	SizeBytes    int
+	Outcome      string
+	Reason       *string
+	StoryID      *uuid.UUID
+	RequestID    *uuid.UUID
+	CommentID    *uuid.UUID
+	CreatedAt    time.Time
+}
+
+// CoreResult is what became of a received message.
+type CoreResult struct {
+	Outcome            string
+	Reason             string
+	StoryID            *uuid.UUID
+	RequestID          *uuid.UUID
+	CommentID          *uuid.UUID
+	SkippedAttachments []string
+	// AlreadyProcessed is set when the provider redelivers a message.
+	AlreadyProcessed bool
+}
+
+type Repository interface {
+	GetAddress(ctx context.Context, workspaceID, teamID uuid.UUID) (CoreAddress, error)
+	GetAddressByLocalPart(ctx context.Context, localPart string) (CoreAddress, error)
+	CreateAddress(ctx context.Context, input CoreNewAddress, localPart string) (CoreAddress, error)
+	UpdateAddress(ctx context.Context, workspaceID, teamID uuid.UUID, input CoreUpdateAddress) (CoreAddress, error)
+	UpdateAddressLocalPart(ctx context.Context, workspaceID, teamID uuid.UUID, localPart string) (CoreAddress, error)
+	DeleteAddress(ctx context.Context, workspaceID, teamID uuid.UUID) error
+	GetTeamCode(ctx context.Context, workspaceID, teamID uuid.UUID) (string, error)
+	FindMemberByEmail(ctx context.Context, workspaceID uuid.UUID, email string) (*uuid.UUID, error)
+	FindFirstStatusByCategory(ctx context.Context, teamID uuid.UUID, category string) (*uuid.UUID, error)
+	FindThread(ctx context.Context, workspaceID uuid.UUID, messageIDs []string) (CoreMessage, error)
+	CountRecentMessages(ctx context.Context, workspaceID uuid.UUID, senderEmail string, since time.Time) (int, error)
+	ClaimMessage(ctx context.Context, message CoreMessage, staleBefore time.Time) (CoreMessage, bool, error)
+	CompleteMessage(ctx context.Context, message CoreMessage) (CoreMessage, error)
+	ReleaseMessage(ctx context.Context, id uuid.UUID) error
+	SetThreadStory(ctx context.Context, workspaceID, requestID, storyID uuid.UUID) error
+}
+
+type RequestStore interface {
+	UpsertPending(ctx context.Context, input integrationrequests.CoreUpsertRequestInput) (integrationrequests.CoreIntegrationRequest, error)
+	Get(ctx context.Context, workspaceID, requestID uuid.UUID) (integrationrequests.CoreIntegrationRequest, error)
+}
+
+type StoryService interface {
+	CreateExternal(ctx context.Context, actorID uuid.UUID, ns stories.CoreNewStory, workspaceID uuid.UUID) (stories.CoreSingleStory, error)
+	CreateCommentExternal(ctx context.Context, actorID uuid.UUID, workspaceID uuid.UUID, cnc stories.CoreNewComment) (comments.CoreComment, error)
+}
+
+type AttachmentService interface {
+	UploadAttachment(ctx context.Context, file multipart.File, fileHeader *multipart.FileHeader, userID uuid.UUID, workspaceID uuid.UUID) (attachments.FileInfo, error)
+	LinkAttachmentToStory(ctx context.Context, storyID, attachmentID uuid.UUID) error
+}
+
+type Config struct {
+	// Domain is the mail domain inbound addresses live on, e.g.
+	// "in.fortyone.app". Recipients on other domains are ignored.
+	Domain string
+	// Secret authenticates the mail provider posting to the inbound endpoint.
+	Secret string
+	// AuthServID is the authserv-id the mail provider stamps on the
+	// Authentication-Results header. Senders are only mapped to workspace
+	// members when that header shows their domain passed DMARC, DKIM or SPF;
+	// without it every sender is treated as unknown.
+	AuthServID string
+	// EmailActorID posts comments and uploads attachments for senders who
+	// are not workspace members.
+	EmailActorID uuid.UUID
+	// MaxMessageBytes caps the raw message size, attachments included.
+	MaxMessageBytes int64
+	// MaxAttachments is the number of attachments kept from one message.
+	MaxAttachments int
+	// MaxMessagesPerHour is the number of messages accepted from one sender
+	// per workspace per hour.
+	MaxMessagesPerHour int
+}
@@ -12,6 +12,7 @@ const (
 	ProviderGitHub   = "github"
 	ProviderSlack    = "slack"
 	ProviderIntercom = "intercom"
+	ProviderEmail    = "email"
 
 	SourceTypeIssue = "issue"
 
@@ -25,6 +25,9 @@ const (
 	NotificationTypeObjectiveUpdate NotificationType = "objective_update"
 	NotificationTypeKeyResultUpdate NotificationType = "key_result_update"
 	NotificationTypeMention         NotificationType = "mention"
+	NotificationTypeCommentReaction NotificationType = "comment_reaction"
+	NotificationTypeSLAAtRisk       NotificationType = "sla_at_risk"
+	NotificationTypeSLABreached     NotificationType = "sla_breached"
 )
 
 type dbNotification struct {
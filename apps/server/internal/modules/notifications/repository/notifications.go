@@ -44,6 +44,18 @@ func getDefaultPreferences() map[string]map[string]bool {
 			"email":  true,
 			"in_app": true,
 		},
+		"comment_reaction": {
+			"email":  false,
+			"in_app": true,
+		},
+		"sla_at_risk": {
+			"email":  true,
+			"in_app": true,
+		},
+		"sla_breached": {
+			"email":  true,
+			"in_app": true,
+		},
 		"reminders": {
 			"email":  true,
 			"in_app": true,
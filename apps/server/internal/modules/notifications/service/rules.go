@@ -13,18 +13,20 @@ import (
 )
 
 type Rules struct {
-	log      *logger.Logger
-	stories  *stories.Service
-	users    *users.Service
-	statuses *states.Service
+	log         *logger.Logger
+	stories     *stories.Service
+	users       *users.Service
+	statuses    *states.Service
+	preferences *Service
 }
 
-func NewRules(log *logger.Logger, stories *stories.Service, users *users.Service, statuses *states.Service) *Rules {
+func NewRules(log *logger.Logger, stories *stories.Service, users *users.Service, statuses *states.Service, preferences *Service) *Rules {
 	return &Rules{
-		log:      log,
-		stories:  stories,
-		users:    users,
-		statuses: statuses,
+		log:         log,
+		stories:     stories,
+		users:       users,
+		statuses:    statuses,
+		preferences: preferences,
 	}
 }
 
@@ -85,6 +87,9 @@ func (r *Rules) ProcessStoryUpdate(ctx context.Context, payload events.StoryUpda
 		notifications = append(notifications, r.handleStoryUpdates(ctx, payload, actorID)...)
 	}
 
+	// Tell watchers about the same changes
+	notifications = r.handleWatcherUpdates(ctx, notifications, payload, actorID)
+
 	return notifications, nil
 }
 
@@ -100,15 +105,10 @@ func (r *Rules) ProcessCommentCreated(ctx context.Context, payload events.Commen
 		}
 	}
 
+	message := commentMessage(actorUsername, payload.Content)
+
 	// Rule 1: Notify story assignee when someone comments on their assigned story
 	if payload.AssigneeID != nil && shouldNotify(*payload.AssigneeID, actorID) {
-		message := NotificationMessage{
-			Template: fmt.Sprintf("{actor} left a comment: %s", payload.Content),
-			Variables: map[string]Variable{
-				"actor": {Value: actorUsername, Type: "actor"},
-			},
-		}
-
 		notification := CoreNewNotification{
 			RecipientID: *payload.AssigneeID,
 			WorkspaceID: payload.WorkspaceID,
@@ -122,6 +122,9 @@ func (r *Rules) ProcessCommentCreated(ctx context.Context, payload events.Commen
 		notifications = append(notifications, notification)
 	}
 
+	// Rule 2: Notify watchers, leaving mentioned users to their mention notification
+	notifications = r.notifyWatchers(ctx, notifications, payload.StoryID, payload.WorkspaceID, actorID, "story_comment", payload.StoryTitle, message, payload.Mentions...)
+
 	return notifications, nil
 }
 
@@ -159,6 +162,10 @@ func (r *Rules) ProcessCommentReplied(ctx context.Context, payload events.Commen
 		notifications = append(notifications, notification)
 	}
 
+	// Rule 2: Notify watchers, leaving mentioned users to their mention notification
+	watcherMessage := commentMessage(actorUsername, payload.Content)
+	notifications = r.notifyWatchers(ctx, notifications, payload.StoryID, payload.WorkspaceID, actorID, "story_comment", payload.StoryTitle, watcherMessage, append([]uuid.UUID{payload.ParentAuthorID}, payload.Mentions...)...)
+
 	return notifications, nil
 }
 
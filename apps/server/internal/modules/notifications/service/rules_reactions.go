@@ -0,0 +1,91 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/google/uuid"
+)
+
+// ProcessCommentReacted applies notification rules for comment reactions.
+// The comment author gets one notification per comment that is updated as
+// people react, and only when someone reacts to the comment for the first
+// time, so further emoji from the same user and removals stay silent.
+func (r *Rules) ProcessCommentReacted(ctx context.Context, payload events.CommentReactedPayload, actorID uuid.UUID, reactions []comments.CoreReaction) ([]CoreNewNotification, error) {
+	if !shouldNotify(payload.CommentAuthorID, actorID) || !isFirstReaction(reactions, actorID, payload.Emoji) {
+		return nil, nil
+	}
+	if !r.wantsInApp(ctx, payload.CommentAuthorID, payload.WorkspaceID, "comment_reaction") {
+		return nil, nil
+	}
+
+	actorName := "Someone"
+	if r.users != nil {
+		actorName = r.getUserName(ctx, actorID)
+	}
+
+	others := len(reactorIDs(reactions, payload.CommentAuthorID)) - 1
+	return []CoreNewNotification{
+		{
+			RecipientID: payload.CommentAuthorID,
+			WorkspaceID: payload.WorkspaceID,
+			Type:        "comment_reaction",
+			EntityType:  "comment",
+			EntityID:    payload.CommentID,
+			ActorID:     actorID,
+			Title:       payload.StoryTitle,
+			Message:     reactionMessage(actorName, others),
+		},
+	}, nil
+}
+
+// isFirstReaction reports whether emoji is the user's only reaction on the
+// comment, which means they just added it.
+func isFirstReaction(reactions []comments.CoreReaction, userID uuid.UUID, emoji string) bool {
+	var userReactions []string
+	for _, reaction := range reactions {
+		for _, id := range reaction.UserIDs {
+			if id == userID {
+				userReactions = append(userReactions, reaction.Emoji)
+			}
+		}
+	}
+	return len(userReactions) == 1 && userReactions[0] == emoji
+}
+
+// reactorIDs returns the distinct users who reacted to a comment, leaving
+// out its author.
+func reactorIDs(reactions []comments.CoreReaction, authorID uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool)
+	var ids []uuid.UUID
+	for _, reaction := range reactions {
+		for _, id := range reaction.UserIDs {
+			if id == authorID || seen[id] {
+				continue
+			}
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// reactionMessage summarises the reactions on a comment from the latest
+// reactor's point of view.
+func reactionMessage(actorName string, others int) NotificationMessage {
+	template := "{actor} reacted to your comment"
+	switch {
+	case others == 1:
+		template = "{actor} and 1 other reacted to your comment"
+	case others > 1:
+		template = fmt.Sprintf("{actor} and %d others reacted to your comment", others)
+	}
+	return NotificationMessage{
+		Template: template,
+		Variables: map[string]Variable{
+			"actor": {Value: actorName, Type: "actor"},
+		},
+	}
+}
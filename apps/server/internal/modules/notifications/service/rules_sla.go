@@ -0,0 +1,58 @@
+package notifications
+
+import (
+	"context"
+
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/google/uuid"
+)
+
+// ProcessStorySLAAlert applies notification rules for SLA alerts. The
+// assignee and the story's watchers hear when a target becomes at risk and
+// again when it is breached.
+func (r *Rules) ProcessStorySLAAlert(ctx context.Context, payload events.StorySLAAlertPayload, actorID uuid.UUID) ([]CoreNewNotification, error) {
+	r.log.Info(ctx, "ProcessStorySLAAlert", "payload", payload, "actor_id", actorID)
+
+	notifType := "sla_at_risk"
+	if payload.Level == "breached" {
+		notifType = "sla_breached"
+	}
+	message := slaAlertMessage(payload)
+
+	var notifications []CoreNewNotification
+	if payload.AssigneeID != nil && shouldNotify(*payload.AssigneeID, actorID) &&
+		r.wantsInApp(ctx, *payload.AssigneeID, payload.WorkspaceID, notifType) {
+		notifications = append(notifications, CoreNewNotification{
+			RecipientID: *payload.AssigneeID,
+			WorkspaceID: payload.WorkspaceID,
+			Type:        notifType,
+			EntityType:  "story",
+			EntityID:    payload.StoryID,
+			ActorID:     actorID,
+			Title:       payload.StoryTitle,
+			Message:     message,
+		})
+	}
+
+	return r.notifyWatchers(ctx, notifications, payload.StoryID, payload.WorkspaceID, actorID, notifType, payload.StoryTitle, message), nil
+}
+
+// slaAlertMessage describes an SLA alert, such as "The first response SLA
+// is at risk, due 4 Jun 14:00".
+func slaAlertMessage(payload events.StorySLAAlertPayload) NotificationMessage {
+	field := "resolution"
+	if payload.Target == "first_response" {
+		field = "first response"
+	}
+	template := "The {field} SLA is at risk, due {value}"
+	if payload.Level == "breached" {
+		template = "The {field} SLA was breached at {value}"
+	}
+	return NotificationMessage{
+		Template: template,
+		Variables: map[string]Variable{
+			"field": {Value: field, Type: "field"},
+			"value": {Value: payload.DueAt.UTC().Format("2 Jan 15:04 MST"), Type: "date"},
+		},
+	}
+}
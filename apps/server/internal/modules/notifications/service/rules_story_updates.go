@@ -142,6 +142,21 @@ func (r *Rules) handleStoryUpdates(ctx context.Context, payload events.StoryUpda
 	if payload.AssigneeID == nil || !shouldNotify(*payload.AssigneeID, actorID) {
 		return nil
 	}
+	r.addStatusName(ctx, payload)
+	actorName := r.getUserName(ctx, actorID)
+	storyTitle := r.getStoryTitle(ctx, payload.StoryID, payload.WorkspaceID)
+	message := r.generateNonAssignmentUpdateMessage(actorName, payload.Updates)
+
+	return []CoreNewNotification{
+		r.createNotification(*payload.AssigneeID, payload, actorID, "story_update", storyTitle, message),
+	}
+}
+
+// addStatusName adds the name of a new status to the updates for messages
+func (r *Rules) addStatusName(ctx context.Context, payload events.StoryUpdatedPayload) {
+	if _, exists := payload.Updates["status_name"]; exists {
+		return
+	}
 	if statusID, exists := payload.Updates["status_id"]; exists {
 		if statusStr, ok := statusID.(string); ok {
 			if statusID, err := uuid.Parse(statusStr); err == nil {
@@ -150,13 +165,6 @@ func (r *Rules) handleStoryUpdates(ctx context.Context, payload events.StoryUpda
 			}
 		}
 	}
-	actorName := r.getUserName(ctx, actorID)
-	storyTitle := r.getStoryTitle(ctx, payload.StoryID, payload.WorkspaceID)
-	message := r.generateNonAssignmentUpdateMessage(actorName, payload.Updates)
-
-	return []CoreNewNotification{
-		r.createNotification(*payload.AssigneeID, payload, actorID, "story_update", storyTitle, message),
-	}
 }
 
 // generateNonAssignmentUpdateMessage creates messages for status, priority, due date updates
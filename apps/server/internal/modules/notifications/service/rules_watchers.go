@@ -0,0 +1,141 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/google/uuid"
+)
+
+// watchedStoryFields are the story updates watchers are told about.
+var watchedStoryFields = []string{"status_id", "priority", "end_date", "assignee_id"}
+
+// notifyWatchers appends a notification for each watcher of a story, skipping
+// the actor, users already in notifications, the users in skip and watchers
+// who turned off in-app notifications of the type.
+func (r *Rules) notifyWatchers(ctx context.Context, notifications []CoreNewNotification, storyID, workspaceID, actorID uuid.UUID, notifType, title string, message NotificationMessage, skip ...uuid.UUID) []CoreNewNotification {
+	if r.stories == nil {
+		return notifications
+	}
+
+	watcherIDs, err := r.stories.WatcherIDs(ctx, storyID, workspaceID)
+	if err != nil {
+		r.log.Error(ctx, "failed to get story watchers", "error", err, "story_id", storyID)
+		return notifications
+	}
+
+	excluded := make(map[uuid.UUID]bool, len(notifications)+len(skip)+1)
+	excluded[actorID] = true
+	for _, n := range notifications {
+		excluded[n.RecipientID] = true
+	}
+	for _, id := range skip {
+		excluded[id] = true
+	}
+
+	for _, watcherID := range watcherIDs {
+		if excluded[watcherID] || !r.wantsInApp(ctx, watcherID, workspaceID, notifType) {
+			continue
+		}
+		excluded[watcherID] = true
+		notifications = append(notifications, CoreNewNotification{
+			RecipientID: watcherID,
+			WorkspaceID: workspaceID,
+			Type:        notifType,
+			EntityType:  "story",
+			EntityID:    storyID,
+			ActorID:     actorID,
+			Title:       title,
+			Message:     message,
+		})
+	}
+	return notifications
+}
+
+// wantsInApp reports whether a user gets in-app notifications of a type.
+// Users are notified when their preferences cannot be read.
+func (r *Rules) wantsInApp(ctx context.Context, userID, workspaceID uuid.UUID, notifType string) bool {
+	if r.preferences == nil {
+		return true
+	}
+	preferences, err := r.preferences.GetPreferences(ctx, userID, workspaceID)
+	if err != nil {
+		return true
+	}
+	return inAppEnabled(preferences.Preferences, notifType)
+}
+
+// inAppEnabled reads the in_app flag of a notification type, which defaults
+// to enabled.
+func inAppEnabled(preferences map[string]interface{}, notifType string) bool {
+	preference, ok := preferences[notifType].(map[string]interface{})
+	if !ok {
+		return true
+	}
+	enabled, ok := preference["in_app"].(bool)
+	if !ok {
+		return true
+	}
+	return enabled
+}
+
+// hasWatchedUpdates detects updates watchers are told about.
+func hasWatchedUpdates(updates map[string]any) bool {
+	for _, field := range watchedStoryFields {
+		if _, exists := updates[field]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// handleWatcherUpdates notifies the watchers of a story about an update.
+func (r *Rules) handleWatcherUpdates(ctx context.Context, notifications []CoreNewNotification, payload events.StoryUpdatedPayload, actorID uuid.UUID) []CoreNewNotification {
+	if r.stories == nil || !hasWatchedUpdates(payload.Updates) {
+		return notifications
+	}
+
+	actorName := r.getUserName(ctx, actorID)
+	var message NotificationMessage
+	if _, exists := payload.Updates["assignee_id"]; exists {
+		message = r.watcherAssignmentMessage(ctx, actorName, payload.Updates)
+	} else {
+		r.addStatusName(ctx, payload)
+		message = r.generateNonAssignmentUpdateMessage(actorName, payload.Updates)
+	}
+
+	storyTitle := r.getStoryTitle(ctx, payload.StoryID, payload.WorkspaceID)
+	return r.notifyWatchers(ctx, notifications, payload.StoryID, payload.WorkspaceID, actorID, "story_update", storyTitle, message)
+}
+
+// watcherAssignmentMessage describes an assignment change to watchers.
+func (r *Rules) watcherAssignmentMessage(ctx context.Context, actorName string, updates map[string]any) NotificationMessage {
+	newAssigneeID := getNewAssignee(updates)
+	if newAssigneeID == nil {
+		return NotificationMessage{
+			Template: "{actor} removed the assignee",
+			Variables: map[string]Variable{
+				"actor": {Value: actorName, Type: "actor"},
+			},
+		}
+	}
+
+	return NotificationMessage{
+		Template: "{actor} assigned the task to {assignee}",
+		Variables: map[string]Variable{
+			"actor":    {Value: actorName, Type: "actor"},
+			"assignee": {Value: r.getUserName(ctx, *newAssigneeID), Type: "assignee"},
+		},
+	}
+}
+
+// commentMessage is the message watchers get for a new comment.
+func commentMessage(actorUsername, content string) NotificationMessage {
+	return NotificationMessage{
+		Template: fmt.Sprintf("{actor} left a comment: %s", content),
+		Variables: map[string]Variable{
+			"actor": {Value: actorUsername, Type: "actor"},
+		},
+	}
+}
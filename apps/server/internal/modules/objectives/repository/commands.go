@@ -8,6 +8,8 @@ import (
 
 	keyresults "github.com/complexus-tech/projects-api/internal/modules/keyresults/service"
 	objectives "github.com/complexus-tech/projects-api/internal/modules/objectives/service"
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/outbox"
 	"github.com/complexus-tech/projects-api/pkg/web"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
@@ -119,8 +121,8 @@ func (r *repo) Create(ctx context.Context, objective objectives.CoreNewObjective
 	return toCoreObjective(createdObj), createdKRs, nil
 }
 
-// Update updates an objective
-func (r *repo) Update(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID, updates map[string]any) error {
+// Update updates an objective and writes any outbox events in the same transaction.
+func (r *repo) Update(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID, updates map[string]any, outboxEvents ...events.Event) error {
 	ctx, span := web.AddSpan(ctx, "business.repository.objectives.Update")
 	defer span.End()
 
@@ -137,7 +139,13 @@ func (r *repo) Update(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID,
 	query += strings.Join(setClauses, ", ")
 	query += " WHERE objective_id = :id"
 
-	stmt, err := r.db.PrepareNamedContext(ctx, query)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareNamedContext(ctx, query)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to prepare named statement: %s", err)
 		r.log.Error(ctx, errMsg)
@@ -166,6 +174,17 @@ func (r *repo) Update(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID,
 		return err
 	}
 
+	if err := outbox.Write(ctx, tx, outboxEvents...); err != nil {
+		errMsg := fmt.Sprintf("failed to write objective events: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to write objective events"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	r.log.Info(ctx, fmt.Sprintf("Objective #%s updated successfully", id), "id", id)
 	span.AddEvent("objective updated", trace.WithAttributes(
 		attribute.String("objective.id", id.String()),
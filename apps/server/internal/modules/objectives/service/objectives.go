@@ -8,6 +8,7 @@ import (
 
 	keyresults "github.com/complexus-tech/projects-api/internal/modules/keyresults/service"
 	okractivities "github.com/complexus-tech/projects-api/internal/modules/okractivities/service"
+	"github.com/complexus-tech/projects-api/pkg/events"
 	"github.com/complexus-tech/projects-api/pkg/logger"
 	"github.com/complexus-tech/projects-api/pkg/web"
 	"github.com/google/uuid"
@@ -25,7 +26,7 @@ var (
 type Repository interface {
 	List(ctx context.Context, workspaceId uuid.UUID, userID uuid.UUID, filters map[string]any) ([]CoreObjective, error)
 	Get(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID) (CoreObjective, error)
-	Update(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID, updates map[string]any) error
+	Update(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID, updates map[string]any, outboxEvents ...events.Event) error
 	Delete(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID) error
 	Create(ctx context.Context, objective CoreNewObjective, workspaceID uuid.UUID, keyResults []keyresults.CoreNewKeyResult) (CoreObjective, []keyresults.CoreKeyResult, error)
 	GetAnalytics(ctx context.Context, objectiveID uuid.UUID, workspaceID uuid.UUID) (CoreObjectiveAnalytics, error)
@@ -89,7 +90,22 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, workspaceId uuid.UUI
 	ctx, span := web.AddSpan(ctx, "business.core.objectives.Update")
 	defer span.End()
 
-	if err := s.repo.Update(ctx, id, workspaceId, updates); err != nil {
+	payload := events.ObjectiveUpdatedPayload{
+		ObjectiveID: id,
+		WorkspaceID: workspaceId,
+		Updates:     updates,
+	}
+	if leadID, ok := updates["lead_user_id"].(uuid.UUID); ok {
+		payload.LeadID = &leadID
+	}
+	event := events.Event{
+		Type:      events.ObjectiveUpdated,
+		Payload:   payload,
+		Timestamp: time.Now(),
+		ActorID:   userId,
+	}
+
+	if err := s.repo.Update(ctx, id, workspaceId, updates, event); err != nil {
 		if errors.Is(err, ErrNotFound) {
 			return ErrNotFound
 		}
@@ -168,6 +168,7 @@ type AppPulseStoryHealth struct {
 	HighPriorityStories int `json:"highPriorityStories"`
 	UnassignedStories   int `json:"unassignedStories"`
 	UnestimatedStories  int `json:"unestimatedStories"`
+	WIPLimitBreaches    int `json:"wipLimitBreaches"`
 }
 
 type AppPulseSprintHealth struct {
@@ -355,6 +356,27 @@ type AppSprintProgressItem struct {
 	Status     string    `json:"status"`
 }
 
+type AppSprintTimeReport struct {
+	Sprints          []AppSprintTime `json:"sprints"`
+	EstimatedMinutes int             `json:"estimatedMinutes"`
+	ActualMinutes    int             `json:"actualMinutes"`
+	BillableMinutes  int             `json:"billableMinutes"`
+}
+
+type AppSprintTime struct {
+	SprintID            uuid.UUID `json:"sprintId"`
+	SprintName          string    `json:"sprintName"`
+	TeamID              uuid.UUID `json:"teamId"`
+	StartDate           time.Time `json:"startDate"`
+	EndDate             time.Time `json:"endDate"`
+	StoryCount          int       `json:"storyCount"`
+	EstimatedStoryCount int       `json:"estimatedStoryCount"`
+	EstimatedMinutes    int       `json:"estimatedMinutes"`
+	ActualMinutes       int       `json:"actualMinutes"`
+	BillableMinutes     int       `json:"billableMinutes"`
+	VarianceMinutes     int       `json:"varianceMinutes"`
+}
+
 type AppCombinedBurndownPoint struct {
 	Date    time.Time `json:"date"`
 	Planned int       `json:"planned"`
@@ -601,6 +623,7 @@ func toAppPulseStoryHealth(health reports.CorePulseStoryHealth) AppPulseStoryHea
 		HighPriorityStories: health.HighPriorityStories,
 		UnassignedStories:   health.UnassignedStories,
 		UnestimatedStories:  health.UnestimatedStories,
+		WIPLimitBreaches:    health.WIPLimitBreaches,
 	}
 }
 
@@ -898,6 +921,31 @@ func toAppSprintProgressItems(items []reports.CoreSprintProgressItem) []AppSprin
 	return result
 }
 
+func toAppSprintTimeReport(report reports.CoreSprintTimeReport) AppSprintTimeReport {
+	sprints := make([]AppSprintTime, len(report.Sprints))
+	for i, sprint := range report.Sprints {
+		sprints[i] = AppSprintTime{
+			SprintID:            sprint.SprintID,
+			SprintName:          sprint.SprintName,
+			TeamID:              sprint.TeamID,
+			StartDate:           sprint.StartDate,
+			EndDate:             sprint.EndDate,
+			StoryCount:          sprint.StoryCount,
+			EstimatedStoryCount: sprint.EstimatedStoryCount,
+			EstimatedMinutes:    sprint.EstimatedMinutes,
+			ActualMinutes:       sprint.ActualMinutes,
+			BillableMinutes:     sprint.BillableMinutes,
+			VarianceMinutes:     sprint.ActualMinutes - sprint.EstimatedMinutes,
+		}
+	}
+	return AppSprintTimeReport{
+		Sprints:          sprints,
+		EstimatedMinutes: report.EstimatedMinutes,
+		ActualMinutes:    report.ActualMinutes,
+		BillableMinutes:  report.BillableMinutes,
+	}
+}
+
 func toAppCombinedBurndownPoints(points []reports.CoreCombinedBurndownPoint) []AppCombinedBurndownPoint {
 	result := make([]AppCombinedBurndownPoint, len(points))
 	for i, point := range points {
@@ -541,6 +541,35 @@ func (h *Handlers) GetSprintAnalytics(ctx context.Context, w http.ResponseWriter
 	return web.Respond(ctx, w, toAppSprintAnalyticsWorkspace(analytics), http.StatusOK)
 }
 
+// GetSprintTimeTracking compares estimated and logged time per sprint.
+func (h *Handlers) GetSprintTimeTracking(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "handlers.reports.GetSprintTimeTracking")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	var af AppReportFilters
+	query, err := web.GetFilters(r.URL.Query(), &af)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	filters, err := parseReportFilters(query)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	report, err := h.reports.GetSprintTimeTracking(ctx, workspace.ID, filters)
+	if err != nil {
+		return fmt.Errorf("getting sprint time tracking: %w", err)
+	}
+
+	return web.Respond(ctx, w, toAppSprintTimeReport(report), http.StatusOK)
+}
+
 func (h *Handlers) GetTimelineTrends(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	ctx, span := web.AddSpan(ctx, "handlers.reports.GetTimelineTrends")
 	defer span.End()
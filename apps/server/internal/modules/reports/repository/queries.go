@@ -1342,6 +1342,9 @@ func (r *repo) GetPulseStoryHealth(ctx context.Context, workspaceID uuid.UUID, f
 		"workspace_id": workspaceID,
 	}
 	storyFilter := buildWorkloadStoryFilter(filters, namedParams)
+	// WIP limits apply to the current stories of a status, so breaches only
+	// follow the team filter.
+	wipFilter := buildUUIDArrayFilter("l.team_id", "team_ids", filters.TeamIDs, namedParams)
 	openStoryCondition := "stat.category IS NULL OR stat.category NOT IN ('completed', 'cancelled')"
 	query := fmt.Sprintf(`
 		SELECT
@@ -1355,7 +1358,23 @@ func (r *repo) GetPulseStoryHealth(ctx context.Context, workspaceID uuid.UUID, f
 			CAST(COUNT(*) FILTER (WHERE (%s) AND s.priority = 'Urgent') AS int) AS urgent_stories,
 			CAST(COUNT(*) FILTER (WHERE (%s) AND s.priority = 'High') AS int) AS high_priority_stories,
 			CAST(COUNT(*) FILTER (WHERE (%s) AND s.assignee_id IS NULL) AS int) AS unassigned_stories,
-			CAST(COUNT(*) FILTER (WHERE (%s) AND s.estimate_unit IS NULL) AS int) AS unestimated_stories
+			CAST(COUNT(*) FILTER (WHERE (%s) AND s.estimate_unit IS NULL) AS int) AS unestimated_stories,
+			(
+				SELECT CAST(COUNT(*) AS int)
+				FROM (
+					SELECT 1
+					FROM team_wip_limits l
+					INNER JOIN stories ws ON ws.status_id = l.status_id
+						AND ws.deleted_at IS NULL
+						AND ws.archived_at IS NULL
+						AND ws.is_draft = false
+					WHERE l.workspace_id = :workspace_id
+						AND (NOT l.per_assignee OR ws.assignee_id IS NOT NULL)
+						%s
+					GROUP BY l.status_id, l.max_stories, l.per_assignee, CASE WHEN l.per_assignee THEN ws.assignee_id END
+					HAVING COUNT(*) > l.max_stories
+				) breaches
+			) AS wip_limit_breaches
 		FROM stories s
 		LEFT JOIN statuses stat ON stat.status_id = s.status_id
 		WHERE s.workspace_id = :workspace_id
@@ -1363,7 +1382,7 @@ func (r *repo) GetPulseStoryHealth(ctx context.Context, workspaceID uuid.UUID, f
 			AND s.archived_at IS NULL
 			AND s.is_draft = false
 			%s
-	`, openStoryCondition, openStoryCondition, openStoryCondition, openStoryCondition, openStoryCondition, openStoryCondition, openStoryCondition, storyFilter)
+	`, openStoryCondition, openStoryCondition, openStoryCondition, openStoryCondition, openStoryCondition, openStoryCondition, openStoryCondition, wipFilter, storyFilter)
 
 	stmt, err := r.db.PrepareNamedContext(ctx, query)
 	if err != nil {
@@ -0,0 +1,120 @@
+package reportsrepository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	reports "github.com/complexus-tech/projects-api/internal/modules/reports/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GetSprintTimeTracking returns the sprints running in the filtered period with
+// the time logged on their stories, and their stories' estimates grouped by
+// estimate and scheme. Running timers count up to now.
+func (r *repo) GetSprintTimeTracking(ctx context.Context, workspaceID uuid.UUID, filters reports.ReportFilters) ([]reports.CoreSprintTime, []reports.CoreSprintEstimate, error) {
+	r.log.Info(ctx, "reportsrepository.GetSprintTimeTracking")
+	ctx, span := web.AddSpan(ctx, "reportsrepository.GetSprintTimeTracking")
+	defer span.End()
+
+	namedParams := map[string]any{
+		"workspace_id": workspaceID,
+		"start_date":   *filters.StartDate,
+		"end_date":     *filters.EndDate,
+	}
+	sprintFilters := buildUUIDArrayFilter("sp.team_id", "team_ids", filters.TeamIDs, namedParams) +
+		buildUUIDArrayFilter("sp.sprint_id", "sprint_ids", filters.SprintIDs, namedParams)
+
+	sprintsQuery := fmt.Sprintf(`
+		WITH filtered_sprints AS (
+			SELECT sp.sprint_id, sp.name, sp.team_id, sp.start_date, sp.end_date
+			FROM sprints sp
+			WHERE sp.workspace_id = :workspace_id
+				AND sp.start_date <= CAST(:end_date AS date)
+				AND sp.end_date >= CAST(:start_date AS date)
+				%s
+		),
+		sprint_stories AS (
+			SELECT st.sprint_id, COUNT(*) AS story_count
+			FROM stories st
+			INNER JOIN filtered_sprints fs ON fs.sprint_id = st.sprint_id
+			WHERE st.deleted_at IS NULL AND st.is_draft = false
+			GROUP BY st.sprint_id
+		),
+		sprint_time AS (
+			SELECT
+				st.sprint_id,
+				SUM(EXTRACT(EPOCH FROM COALESCE(te.ended_at, NOW()) - te.started_at)) AS seconds,
+				SUM(EXTRACT(EPOCH FROM COALESCE(te.ended_at, NOW()) - te.started_at))
+					FILTER (WHERE te.billable) AS billable_seconds
+			FROM time_entries te
+			INNER JOIN stories st ON st.id = te.story_id
+			INNER JOIN filtered_sprints fs ON fs.sprint_id = st.sprint_id
+			WHERE st.deleted_at IS NULL
+			GROUP BY st.sprint_id
+		)
+		SELECT
+			fs.sprint_id,
+			fs.name AS sprint_name,
+			fs.team_id,
+			fs.start_date,
+			fs.end_date,
+			COALESCE(ss.story_count, 0) AS story_count,
+			CAST(ROUND(COALESCE(t.seconds, 0) / 60) AS integer) AS actual_minutes,
+			CAST(ROUND(COALESCE(t.billable_seconds, 0) / 60) AS integer) AS billable_minutes
+		FROM filtered_sprints fs
+		LEFT JOIN sprint_stories ss ON ss.sprint_id = fs.sprint_id
+		LEFT JOIN sprint_time t ON t.sprint_id = fs.sprint_id
+		ORDER BY fs.start_date DESC, fs.name`, sprintFilters)
+
+	sprintsStmt, err := r.db.PrepareNamedContext(ctx, sprintsQuery)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to prepare sprint time query: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to prepare sprint time query"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, nil, fmt.Errorf("preparing sprint time query: %w", err)
+	}
+	defer sprintsStmt.Close()
+
+	var sprints []reports.CoreSprintTime
+	if err := sprintsStmt.SelectContext(ctx, &sprints, namedParams); err != nil {
+		errMsg := fmt.Sprintf("failed to get sprint time: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get sprint time"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, nil, fmt.Errorf("executing sprint time query: %w", err)
+	}
+	if len(sprints) == 0 {
+		return []reports.CoreSprintTime{}, nil, nil
+	}
+
+	sprintIDs := make([]uuid.UUID, len(sprints))
+	for i, sprint := range sprints {
+		sprintIDs[i] = sprint.SprintID
+	}
+
+	var estimates []reports.CoreSprintEstimate
+	estimatesQuery := `
+		SELECT
+			st.sprint_id,
+			COALESCE(tes.scheme, 'hours') AS scheme,
+			st.estimate_unit AS estimate_value,
+			COUNT(*) AS story_count
+		FROM stories st
+		LEFT JOIN team_estimation_settings tes ON tes.team_id = st.team_id
+		WHERE st.sprint_id = ANY($1)
+			AND st.deleted_at IS NULL
+			AND st.is_draft = false
+			AND st.estimate_unit IS NOT NULL
+		GROUP BY st.sprint_id, COALESCE(tes.scheme, 'hours'), st.estimate_unit`
+	if err := r.db.SelectContext(ctx, &estimates, estimatesQuery, sprintIDs); err != nil {
+		errMsg := fmt.Sprintf("failed to get sprint estimates: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get sprint estimates"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, nil, fmt.Errorf("executing sprint estimates query: %w", err)
+	}
+
+	return sprints, estimates, nil
+}
@@ -223,6 +223,7 @@ const (
 	PulseRiskKindAtRiskObjectives  PulseRiskKind = "at_risk_objectives"
 	PulseRiskKindPendingRequests   PulseRiskKind = "pending_requests"
 	PulseRiskKindUnassignedStories PulseRiskKind = "unassigned_stories"
+	PulseRiskKindWIPLimitBreaches  PulseRiskKind = "wip_limit_breaches"
 )
 
 type CorePulseReport struct {
@@ -260,6 +261,7 @@ type CorePulseStoryHealth struct {
 	HighPriorityStories int `json:"highPriorityStories" db:"high_priority_stories"`
 	UnassignedStories   int `json:"unassignedStories" db:"unassigned_stories"`
 	UnestimatedStories  int `json:"unestimatedStories" db:"unestimated_stories"`
+	WIPLimitBreaches    int `json:"wipLimitBreaches" db:"wip_limit_breaches"`
 }
 
 type CorePulseSprintHealth struct {
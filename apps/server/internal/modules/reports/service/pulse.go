@@ -72,7 +72,7 @@ func (s *Service) GetPulseReport(ctx context.Context, workspaceID uuid.UUID, fil
 }
 
 func derivePulseRisks(stories CorePulseStoryHealth, sprints CorePulseSprintHealth, objectives CorePulseObjectiveHealth, requests CorePulseRequestHealth, workload CoreWorkloadAnalysis) []CorePulseRisk {
-	risks := make([]CorePulseRisk, 0, 7)
+	risks := make([]CorePulseRisk, 0, 8)
 
 	addRisk := func(kind PulseRiskKind, severity PulseRiskSeverity, title string, description string, count int) {
 		if count <= 0 {
@@ -90,6 +90,7 @@ func derivePulseRisks(stories CorePulseStoryHealth, sprints CorePulseSprintHealt
 	addRisk(PulseRiskKindOverdueStories, PulseRiskSeverityHigh, "Overdue stories", "Stories have passed their end date and are still open.", stories.OverdueStories)
 	addRisk(PulseRiskKindBlockedStories, PulseRiskSeverityHigh, "Blocked stories", "Stories are linked to blockers and may need intervention.", stories.BlockedStories)
 	addRisk(PulseRiskKindOverloadedMembers, PulseRiskSeverityHigh, "Overloaded members", "Members exceed the current open-story or estimate workload threshold.", len(workload.Risks.OverloadedMembers))
+	addRisk(PulseRiskKindWIPLimitBreaches, PulseRiskSeverityMedium, "WIP limit breaches", "Statuses or their assignees hold more stories than the work-in-progress limits allow.", stories.WIPLimitBreaches)
 	addRisk(PulseRiskKindAtRiskSprints, PulseRiskSeverityMedium, "At-risk sprints", "Active sprints have overdue or incomplete work close to the end date.", sprints.AtRiskSprints)
 	addRisk(PulseRiskKindAtRiskObjectives, PulseRiskSeverityMedium, "At-risk objectives", "Objectives are marked at risk or off track.", objectives.AtRiskObjectives+objectives.OffTrackObjectives)
 	addRisk(PulseRiskKindPendingRequests, PulseRiskSeverityMedium, "Pending requests", "Integration requests are waiting to be accepted or declined.", requests.PendingRequests)
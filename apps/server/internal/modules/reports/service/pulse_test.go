@@ -152,6 +152,30 @@ func TestGetPulseReportBuildsOrderedRiskCards(t *testing.T) {
 	}
 }
 
+func TestGetPulseReportIncludesWIPLimitBreaches(t *testing.T) {
+	t.Parallel()
+
+	repo := &pulseRepoStub{
+		storyResult: CorePulseStoryHealth{
+			WIPLimitBreaches: 2,
+		},
+	}
+	service := New(logger.NewWithText(io.Discard, slog.LevelError, "reports-test"), repo)
+
+	got, err := service.GetPulseReport(context.Background(), uuid.New(), ReportFilters{})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(got.Risks) != 1 {
+		t.Fatalf("expected one risk card, got %#v", got.Risks)
+	}
+	risk := got.Risks[0]
+	if risk.Kind != PulseRiskKindWIPLimitBreaches || risk.Severity != PulseRiskSeverityMedium || risk.Count != 2 {
+		t.Fatalf("expected a medium WIP limit breach risk, got %#v", risk)
+	}
+}
+
 func TestTrackWorkspaceAnalyticsEventNormalizesSafeInput(t *testing.T) {
 	t.Parallel()
 
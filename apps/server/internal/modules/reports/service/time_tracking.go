@@ -0,0 +1,95 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CoreSprintTime compares the estimated time of a sprint's stories with the
+// time logged on them. Minutes are summed over stories that are still in the
+// sprint.
+type CoreSprintTime struct {
+	SprintID            uuid.UUID `json:"sprintId" db:"sprint_id"`
+	SprintName          string    `json:"sprintName" db:"sprint_name"`
+	TeamID              uuid.UUID `json:"teamId" db:"team_id"`
+	StartDate           time.Time `json:"startDate" db:"start_date"`
+	EndDate             time.Time `json:"endDate" db:"end_date"`
+	StoryCount          int       `json:"storyCount" db:"story_count"`
+	EstimatedStoryCount int       `json:"estimatedStoryCount"`
+	EstimatedMinutes    int       `json:"estimatedMinutes"`
+	ActualMinutes       int       `json:"actualMinutes" db:"actual_minutes"`
+	BillableMinutes     int       `json:"billableMinutes" db:"billable_minutes"`
+}
+
+// CoreSprintEstimate counts a sprint's stories with the same estimate under
+// their team's estimation scheme.
+type CoreSprintEstimate struct {
+	SprintID      uuid.UUID `db:"sprint_id"`
+	Scheme        string    `db:"scheme"`
+	EstimateValue *int16    `db:"estimate_value"`
+	StoryCount    int       `db:"story_count"`
+}
+
+type CoreSprintTimeReport struct {
+	Sprints          []CoreSprintTime `json:"sprints"`
+	EstimatedMinutes int              `json:"estimatedMinutes"`
+	ActualMinutes    int              `json:"actualMinutes"`
+	BillableMinutes  int              `json:"billableMinutes"`
+}
+
+// GetSprintTimeTracking compares estimated and logged time for the sprints
+// running in the filtered period.
+func (s *Service) GetSprintTimeTracking(ctx context.Context, workspaceID uuid.UUID, filters ReportFilters) (CoreSprintTimeReport, error) {
+	s.log.Info(ctx, "business.core.reports.GetSprintTimeTracking")
+	ctx, span := web.AddSpan(ctx, "business.core.reports.GetSprintTimeTracking")
+	defer span.End()
+
+	sprints, estimates, err := s.repo.GetSprintTimeTracking(ctx, workspaceID, filters)
+	if err != nil {
+		span.RecordError(err)
+		return CoreSprintTimeReport{}, fmt.Errorf("getting sprint time tracking: %w", err)
+	}
+
+	report := applySprintEstimates(sprints, estimates)
+	span.AddEvent("sprint time tracking retrieved.", trace.WithAttributes(
+		attribute.Int("sprint.count", len(report.Sprints)),
+	))
+	return report, nil
+}
+
+// applySprintEstimates converts estimates to minutes with their team's scheme
+// and adds them to the sprints.
+func applySprintEstimates(sprints []CoreSprintTime, estimates []CoreSprintEstimate) CoreSprintTimeReport {
+	index := make(map[uuid.UUID]int, len(sprints))
+	for i, sprint := range sprints {
+		index[sprint.SprintID] = i
+	}
+
+	for _, estimate := range estimates {
+		i, ok := index[estimate.SprintID]
+		if !ok {
+			continue
+		}
+		minutes := stories.EstimateDurationMinutes(estimate.Scheme, estimate.EstimateValue)
+		if minutes == 0 {
+			continue
+		}
+		sprints[i].EstimatedMinutes += minutes * estimate.StoryCount
+		sprints[i].EstimatedStoryCount += estimate.StoryCount
+	}
+
+	report := CoreSprintTimeReport{Sprints: sprints}
+	for _, sprint := range sprints {
+		report.EstimatedMinutes += sprint.EstimatedMinutes
+		report.ActualMinutes += sprint.ActualMinutes
+		report.BillableMinutes += sprint.BillableMinutes
+	}
+	return report
+}
@@ -0,0 +1,36 @@
+package reports
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestApplySprintEstimates(t *testing.T) {
+	sprintA, sprintB := uuid.New(), uuid.New()
+	value := func(v int16) *int16 { return &v }
+
+	report := applySprintEstimates([]CoreSprintTime{
+		{SprintID: sprintA, StoryCount: 4, ActualMinutes: 300, BillableMinutes: 120},
+		{SprintID: sprintB, StoryCount: 1, ActualMinutes: 45},
+	}, []CoreSprintEstimate{
+		{SprintID: sprintA, Scheme: "hours", EstimateValue: value(3), StoryCount: 2},
+		{SprintID: sprintA, Scheme: "points", EstimateValue: value(1), StoryCount: 1},
+		// Unknown values have no duration and are not counted as estimated.
+		{SprintID: sprintA, Scheme: "hours", EstimateValue: value(4), StoryCount: 1},
+		{SprintID: sprintB, Scheme: "", EstimateValue: value(2), StoryCount: 1},
+		// Sprints outside the report are ignored.
+		{SprintID: uuid.New(), Scheme: "hours", EstimateValue: value(8), StoryCount: 3},
+	})
+
+	a, b := report.Sprints[0], report.Sprints[1]
+	if a.EstimatedMinutes != 2*120+120 || a.EstimatedStoryCount != 3 {
+		t.Errorf("sprint A estimate = %d minutes over %d stories, want 360 over 3", a.EstimatedMinutes, a.EstimatedStoryCount)
+	}
+	if b.EstimatedMinutes != 60 || b.EstimatedStoryCount != 1 {
+		t.Errorf("sprint B estimate = %d minutes over %d stories, want 60 over 1", b.EstimatedMinutes, b.EstimatedStoryCount)
+	}
+	if report.EstimatedMinutes != 420 || report.ActualMinutes != 345 || report.BillableMinutes != 120 {
+		t.Errorf("totals = %+v", report)
+	}
+}
@@ -0,0 +1,73 @@
+package savedviewshttp
+
+import (
+	"time"
+
+	savedviews "github.com/complexus-tech/projects-api/internal/modules/savedviews/service"
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/google/uuid"
+)
+
+type AppSavedView struct {
+	ID          uuid.UUID              `json:"id"`
+	Workspace   uuid.UUID              `json:"workspaceId"`
+	Owner       uuid.UUID              `json:"ownerId"`
+	Scope       string                 `json:"scope"`
+	Team        *uuid.UUID             `json:"teamId"`
+	Name        string                 `json:"name"`
+	Icon        *string                `json:"icon"`
+	Query       stories.CoreStoryQuery `json:"query"`
+	IsFavourite bool                   `json:"isFavourite"`
+	Position    *int                   `json:"position"`
+	CreatedAt   time.Time              `json:"createdAt"`
+	UpdatedAt   time.Time              `json:"updatedAt"`
+}
+
+type AppNewSavedView struct {
+	Scope string                 `json:"scope"`
+	Team  *uuid.UUID             `json:"teamId"`
+	Name  string                 `json:"name" validate:"required,max=100"`
+	Icon  *string                `json:"icon" validate:"omitempty,max=50"`
+	Query stories.CoreStoryQuery `json:"query"`
+}
+
+type AppUpdateSavedView struct {
+	Scope *string                 `json:"scope"`
+	Team  *uuid.UUID              `json:"teamId"`
+	Name  *string                 `json:"name" validate:"omitempty,max=100"`
+	Icon  *string                 `json:"icon" validate:"omitempty,max=50"`
+	Query *stories.CoreStoryQuery `json:"query"`
+}
+
+type AppFavouriteRequest struct {
+	IsFavourite bool `json:"isFavourite"`
+}
+
+type AppReorderRequest struct {
+	ViewIDs []uuid.UUID `json:"viewIds" validate:"required"`
+}
+
+func toAppSavedView(v savedviews.CoreSavedView) AppSavedView {
+	return AppSavedView{
+		ID:          v.ID,
+		Workspace:   v.WorkspaceID,
+		Owner:       v.OwnerID,
+		Scope:       v.Scope,
+		Team:        v.TeamID,
+		Name:        v.Name,
+		Icon:        v.Icon,
+		Query:       v.Query,
+		IsFavourite: v.IsFavourite,
+		Position:    v.Position,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   v.UpdatedAt,
+	}
+}
+
+func toAppSavedViews(views []savedviews.CoreSavedView) []AppSavedView {
+	result := make([]AppSavedView, len(views))
+	for i, v := range views {
+		result[i] = toAppSavedView(v)
+	}
+	return result
+}
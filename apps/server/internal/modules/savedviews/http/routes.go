@@ -0,0 +1,35 @@
+package savedviewshttp
+
+import (
+	savedviews "github.com/complexus-tech/projects-api/internal/modules/savedviews/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/cache"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	DB        *sqlx.DB
+	Log       *logger.Logger
+	SecretKey string
+	Cache     *cache.Service
+	Service   *savedviews.Service
+}
+
+func Routes(cfg Config, app *web.App) {
+	savedViewsService := cfg.Service
+	auth := mid.Auth(cfg.Log, cfg.SecretKey)
+	workspace := mid.Workspace(cfg.Log, cfg.DB, cfg.Cache)
+	memberAndAdmin := mid.RequireMinimumRole(cfg.Log, mid.RoleMember)
+
+	h := New(savedViewsService)
+
+	app.Get("/workspaces/{workspaceSlug}/saved-views", h.List, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/saved-views", h.Create, auth, workspace, memberAndAdmin)
+	app.Put("/workspaces/{workspaceSlug}/saved-views/order", h.Reorder, auth, workspace)
+	app.Get("/workspaces/{workspaceSlug}/saved-views/{id}", h.Get, auth, workspace)
+	app.Put("/workspaces/{workspaceSlug}/saved-views/{id}", h.Update, auth, workspace, memberAndAdmin)
+	app.Delete("/workspaces/{workspaceSlug}/saved-views/{id}", h.Delete, auth, workspace, memberAndAdmin)
+	app.Put("/workspaces/{workspaceSlug}/saved-views/{id}/favourite", h.SetFavourite, auth, workspace)
+}
@@ -0,0 +1,259 @@
+package savedviewshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	savedviews "github.com/complexus-tech/projects-api/internal/modules/savedviews/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidViewID = errors.New("saved view id is not in its proper form")
+)
+
+type Handlers struct {
+	savedViews *savedviews.Service
+}
+
+func New(savedViews *savedviews.Service) *Handlers {
+	return &Handlers{
+		savedViews: savedViews,
+	}
+}
+
+// List returns the views the user can see. With favourites=true only the
+// user's favourites are returned.
+func (h *Handlers) List(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "savedviewshttp.handlers.List")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	favouritesOnly := r.URL.Query().Get("favourites") == "true"
+	views, err := h.savedViews.List(ctx, workspace.ID, userID, favouritesOnly)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppSavedViews(views), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) Get(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "savedviewshttp.handlers.Get")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	viewID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidViewID, http.StatusBadRequest)
+	}
+
+	view, err := h.savedViews.Get(ctx, viewID, workspace.ID, userID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppSavedView(view), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "savedviewshttp.handlers.Create")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	var nv AppNewSavedView
+	if err := web.Decode(r, &nv); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	view, err := h.savedViews.Create(ctx, workspace.ID, userID, savedviews.CoreNewSavedView{
+		Scope:  nv.Scope,
+		TeamID: nv.Team,
+		Name:   nv.Name,
+		Icon:   nv.Icon,
+		Query:  nv.Query,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppSavedView(view), http.StatusCreated)
+	return nil
+}
+
+func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "savedviewshttp.handlers.Update")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	viewID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidViewID, http.StatusBadRequest)
+	}
+
+	var uv AppUpdateSavedView
+	if err := web.Decode(r, &uv); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	view, err := h.savedViews.Update(ctx, viewID, workspace.ID, userID, isAdmin(workspace), savedviews.CoreUpdateSavedView{
+		Scope:  uv.Scope,
+		TeamID: uv.Team,
+		Name:   uv.Name,
+		Icon:   uv.Icon,
+		Query:  uv.Query,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppSavedView(view), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "savedviewshttp.handlers.Delete")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	viewID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidViewID, http.StatusBadRequest)
+	}
+
+	if err := h.savedViews.Delete(ctx, viewID, workspace.ID, userID, isAdmin(workspace)); err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, nil, http.StatusNoContent)
+	return nil
+}
+
+func (h *Handlers) SetFavourite(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "savedviewshttp.handlers.SetFavourite")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	viewID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidViewID, http.StatusBadRequest)
+	}
+
+	var req AppFavouriteRequest
+	if err := web.Decode(r, &req); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	if err := h.savedViews.SetFavourite(ctx, viewID, workspace.ID, userID, req.IsFavourite); err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, nil, http.StatusNoContent)
+	return nil
+}
+
+// Reorder stores the user's order of views. Views left out of viewIds keep
+// their default order after the listed ones.
+func (h *Handlers) Reorder(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "savedviewshttp.handlers.Reorder")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	var req AppReorderRequest
+	if err := web.Decode(r, &req); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	if err := h.savedViews.Reorder(ctx, workspace.ID, userID, req.ViewIDs); err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, nil, http.StatusNoContent)
+	return nil
+}
+
+func isAdmin(workspace mid.WorkspaceInfo) bool {
+	return mid.Role(workspace.UserRole) == mid.RoleAdmin
+}
+
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, savedviews.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, savedviews.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, savedviews.ErrNameRequired),
+		errors.Is(err, savedviews.ErrNameTooLong),
+		errors.Is(err, savedviews.ErrIconTooLong),
+		errors.Is(err, savedviews.ErrInvalidScope),
+		errors.Is(err, savedviews.ErrTeamRequired),
+		errors.Is(err, savedviews.ErrInvalidTeam),
+		errors.Is(err, savedviews.ErrInvalidQuery),
+		errors.Is(err, savedviews.ErrInvalidOrder),
+		errors.Is(err, savedviews.ErrViewNotListed):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
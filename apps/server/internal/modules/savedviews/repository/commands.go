@@ -0,0 +1,192 @@
+package savedviewsrepository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	savedviews "github.com/complexus-tech/projects-api/internal/modules/savedviews/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Create inserts a view. A team view is only inserted when the team belongs to
+// the workspace.
+func (r *repo) Create(ctx context.Context, workspaceID, userID uuid.UUID, nv savedviews.CoreNewSavedView) (savedviews.CoreSavedView, error) {
+	r.log.Info(ctx, "business.repository.savedviews.Create")
+	ctx, span := web.AddSpan(ctx, "business.repository.savedviews.Create")
+	defer span.End()
+
+	query, err := json.Marshal(nv.Query)
+	if err != nil {
+		return savedviews.CoreSavedView{}, fmt.Errorf("marshal saved view query: %w", err)
+	}
+
+	var id uuid.UUID
+	err = r.db.GetContext(ctx, &id, `
+		INSERT INTO saved_views (workspace_id, owner_id, scope, team_id, name, icon, query)
+		SELECT $1, $2, $3, CAST($4 AS uuid), $5, $6, $7
+		WHERE $4::uuid IS NULL
+			OR EXISTS (SELECT 1 FROM teams WHERE team_id = $4 AND workspace_id = $1)
+		RETURNING id`,
+		workspaceID, userID, nv.Scope, nv.TeamID, nv.Name, nv.Icon, query,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return savedviews.CoreSavedView{}, savedviews.ErrInvalidTeam
+		}
+		errMsg := fmt.Sprintf("failed to create saved view: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to create saved view"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return savedviews.CoreSavedView{}, err
+	}
+
+	span.AddEvent("saved view created", trace.WithAttributes(
+		attribute.String("saved_view.id", id.String()),
+	))
+	return r.Get(ctx, id, workspaceID, userID)
+}
+
+// Update writes a view's definition. Preferences are untouched.
+func (r *repo) Update(ctx context.Context, view savedviews.CoreSavedView) error {
+	r.log.Info(ctx, "business.repository.savedviews.Update")
+	ctx, span := web.AddSpan(ctx, "business.repository.savedviews.Update")
+	defer span.End()
+
+	query, err := json.Marshal(view.Query)
+	if err != nil {
+		return fmt.Errorf("marshal saved view query: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE saved_views
+		SET scope = $3,
+			team_id = CAST($4 AS uuid),
+			name = $5,
+			icon = $6,
+			query = $7,
+			updated_at = NOW()
+		WHERE id = $1 AND workspace_id = $2
+			AND ($4::uuid IS NULL
+				OR EXISTS (SELECT 1 FROM teams WHERE team_id = $4 AND workspace_id = $2))`,
+		view.ID, view.WorkspaceID, view.Scope, view.TeamID, view.Name, view.Icon, query,
+	)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to update saved view: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to update saved view"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update saved view rows affected: %w", err)
+	}
+	if rows == 0 {
+		// The view was read just before, so a miss means the team check failed.
+		return savedviews.ErrInvalidTeam
+	}
+	return nil
+}
+
+func (r *repo) Delete(ctx context.Context, id, workspaceID uuid.UUID) error {
+	r.log.Info(ctx, "business.repository.savedviews.Delete")
+	ctx, span := web.AddSpan(ctx, "business.repository.savedviews.Delete")
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM saved_views WHERE id = $1 AND workspace_id = $2`, id, workspaceID)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to delete saved view: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to delete saved view"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete saved view rows affected: %w", err)
+	}
+	if rows == 0 {
+		return savedviews.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) SetFavourite(ctx context.Context, id, userID uuid.UUID, favourite bool) error {
+	r.log.Info(ctx, "business.repository.savedviews.SetFavourite")
+	ctx, span := web.AddSpan(ctx, "business.repository.savedviews.SetFavourite")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO saved_view_preferences (user_id, view_id, is_favourite)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, view_id)
+		DO UPDATE SET is_favourite = EXCLUDED.is_favourite, updated_at = NOW()`,
+		userID, id, favourite,
+	)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to set saved view favourite: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to set saved view favourite"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	return nil
+}
+
+// Reorder gives the listed views positions in the order given and clears the
+// position of the user's other views in the workspace.
+func (r *repo) Reorder(ctx context.Context, workspaceID, userID uuid.UUID, ids []uuid.UUID) error {
+	r.log.Info(ctx, "business.repository.savedviews.Reorder")
+	ctx, span := web.AddSpan(ctx, "business.repository.savedviews.Reorder")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin reorder saved views: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE saved_view_preferences p
+		SET position = NULL, updated_at = NOW()
+		FROM saved_views v
+		WHERE p.view_id = v.id
+			AND p.user_id = $1
+			AND v.workspace_id = $2
+			AND p.position IS NOT NULL
+			AND NOT (p.view_id = ANY($3))`,
+		userID, workspaceID, ids,
+	); err != nil {
+		errMsg := fmt.Sprintf("failed to clear saved view positions: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to reorder saved views"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO saved_view_preferences (user_id, view_id, position)
+		SELECT $1, ordered.view_id, ordered.position - 1
+		FROM unnest(CAST($2 AS uuid[])) WITH ORDINALITY AS ordered(view_id, position)
+		ON CONFLICT (user_id, view_id)
+		DO UPDATE SET position = EXCLUDED.position, updated_at = NOW()`,
+		userID, ids,
+	); err != nil {
+		errMsg := fmt.Sprintf("failed to write saved view positions: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to reorder saved views"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit reorder saved views: %w", err)
+	}
+
+	span.AddEvent("saved views reordered", trace.WithAttributes(
+		attribute.Int("saved_view.count", len(ids)),
+	))
+	return nil
+}
@@ -0,0 +1,57 @@
+package savedviewsrepository
+
+import (
+	"encoding/json"
+	"time"
+
+	savedviews "github.com/complexus-tech/projects-api/internal/modules/savedviews/service"
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/google/uuid"
+)
+
+type dbSavedView struct {
+	ID          uuid.UUID       `db:"id"`
+	WorkspaceID uuid.UUID       `db:"workspace_id"`
+	OwnerID     uuid.UUID       `db:"owner_id"`
+	Scope       string          `db:"scope"`
+	TeamID      *uuid.UUID      `db:"team_id"`
+	Name        string          `db:"name"`
+	Icon        *string         `db:"icon"`
+	Query       json.RawMessage `db:"query"`
+	IsFavourite bool            `db:"is_favourite"`
+	Position    *int            `db:"position"`
+	CreatedAt   time.Time       `db:"created_at"`
+	UpdatedAt   time.Time       `db:"updated_at"`
+}
+
+func toCoreSavedView(v dbSavedView) savedviews.CoreSavedView {
+	var query stories.CoreStoryQuery
+	if len(v.Query) > 0 {
+		// Queries are validated before they are written, so a failure here
+		// only means fields were added or removed since; those are ignored.
+		_ = json.Unmarshal(v.Query, &query)
+	}
+
+	return savedviews.CoreSavedView{
+		ID:          v.ID,
+		WorkspaceID: v.WorkspaceID,
+		OwnerID:     v.OwnerID,
+		Scope:       v.Scope,
+		TeamID:      v.TeamID,
+		Name:        v.Name,
+		Icon:        v.Icon,
+		Query:       query,
+		IsFavourite: v.IsFavourite,
+		Position:    v.Position,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   v.UpdatedAt,
+	}
+}
+
+func toCoreSavedViews(views []dbSavedView) []savedviews.CoreSavedView {
+	result := make([]savedviews.CoreSavedView, len(views))
+	for i, v := range views {
+		result[i] = toCoreSavedView(v)
+	}
+	return result
+}
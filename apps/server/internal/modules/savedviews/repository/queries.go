@@ -0,0 +1,91 @@
+package savedviewsrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	savedviews "github.com/complexus-tech/projects-api/internal/modules/savedviews/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// viewSelect reads views joined with the preferences of the user in $2.
+const viewSelect = `
+	SELECT
+		v.id,
+		v.workspace_id,
+		v.owner_id,
+		v.scope,
+		v.team_id,
+		v.name,
+		v.icon,
+		v.query,
+		COALESCE(p.is_favourite, false) AS is_favourite,
+		p.position,
+		v.created_at,
+		v.updated_at
+	FROM saved_views v
+	LEFT JOIN saved_view_preferences p ON p.view_id = v.id AND p.user_id = $2`
+
+// visibleTo limits views in workspace $1 to those the user in $2 can see:
+// their own, workspace views and views of teams they belong to.
+const visibleTo = `
+	v.workspace_id = $1
+	AND (
+		v.owner_id = $2
+		OR v.scope = 'workspace'
+		OR (
+			v.scope = 'team'
+			AND EXISTS (
+				SELECT 1 FROM team_members tm
+				WHERE tm.team_id = v.team_id AND tm.user_id = $2
+			)
+		)
+	)`
+
+func (r *repo) List(ctx context.Context, workspaceID, userID uuid.UUID, favouritesOnly bool) ([]savedviews.CoreSavedView, error) {
+	r.log.Info(ctx, "business.repository.savedviews.List")
+	ctx, span := web.AddSpan(ctx, "business.repository.savedviews.List")
+	defer span.End()
+
+	query := viewSelect + `
+		WHERE ` + visibleTo + `
+			AND ($3 = false OR p.is_favourite)
+		ORDER BY p.position ASC NULLS LAST, lower(v.name) ASC, v.created_at ASC`
+
+	var rows []dbSavedView
+	if err := r.db.SelectContext(ctx, &rows, query, workspaceID, userID, favouritesOnly); err != nil {
+		errMsg := fmt.Sprintf("failed to list saved views: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to list saved views"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	return toCoreSavedViews(rows), nil
+}
+
+func (r *repo) Get(ctx context.Context, id, workspaceID, userID uuid.UUID) (savedviews.CoreSavedView, error) {
+	r.log.Info(ctx, "business.repository.savedviews.Get")
+	ctx, span := web.AddSpan(ctx, "business.repository.savedviews.Get")
+	defer span.End()
+
+	query := viewSelect + `
+		WHERE v.id = $3 AND ` + visibleTo
+
+	var row dbSavedView
+	if err := r.db.GetContext(ctx, &row, query, workspaceID, userID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return savedviews.CoreSavedView{}, savedviews.ErrNotFound
+		}
+		errMsg := fmt.Sprintf("failed to get saved view: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get saved view"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return savedviews.CoreSavedView{}, err
+	}
+
+	return toCoreSavedView(row), nil
+}
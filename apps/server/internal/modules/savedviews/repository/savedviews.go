@@ -0,0 +1,18 @@
+package savedviewsrepository
+
+import (
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+type repo struct {
+	db  *sqlx.DB
+	log *logger.Logger
+}
+
+func New(log *logger.Logger, db *sqlx.DB) *repo {
+	return &repo{
+		db:  db,
+		log: log,
+	}
+}
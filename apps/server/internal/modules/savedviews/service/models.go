@@ -0,0 +1,57 @@
+package savedviews
+
+import (
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/google/uuid"
+)
+
+// View scopes
+const (
+	ScopePersonal  = "personal"
+	ScopeTeam      = "team"
+	ScopeWorkspace = "workspace"
+)
+
+// Scopes lists every supported view scope.
+var Scopes = []string{ScopePersonal, ScopeTeam, ScopeWorkspace}
+
+// CoreSavedView is a named story query. Personal views are only visible to
+// their owner, team views to the team's members and workspace views to
+// everyone in the workspace. IsFavourite and Position are the viewer's own
+// preferences.
+type CoreSavedView struct {
+	ID          uuid.UUID
+	WorkspaceID uuid.UUID
+	OwnerID     uuid.UUID
+	Scope       string
+	TeamID      *uuid.UUID
+	Name        string
+	Icon        *string
+	Query       stories.CoreStoryQuery
+	IsFavourite bool
+	Position    *int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CoreNewSavedView holds the fields for creating a view.
+type CoreNewSavedView struct {
+	Scope  string
+	TeamID *uuid.UUID
+	Name   string
+	Icon   *string
+	Query  stories.CoreStoryQuery
+}
+
+// CoreUpdateSavedView holds the fields that can be changed on a view. Nil
+// fields are left as they are; an empty icon clears it. Changing the scope to
+// team requires TeamID.
+type CoreUpdateSavedView struct {
+	Scope  *string
+	TeamID *uuid.UUID
+	Name   *string
+	Icon   *string
+	Query  *stories.CoreStoryQuery
+}
@@ -0,0 +1,369 @@
+package savedviews
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	maxNameLength      = 100
+	maxIconLength      = 50
+	maxStoriesPerGroup = 100
+)
+
+// Service errors
+var (
+	ErrNotFound      = errors.New("saved view not found")
+	ErrForbidden     = errors.New("only the owner or a workspace admin can change this view")
+	ErrNameRequired  = errors.New("saved view name is required")
+	ErrNameTooLong   = fmt.Errorf("saved view name cannot be longer than %d characters", maxNameLength)
+	ErrIconTooLong   = fmt.Errorf("saved view icon cannot be longer than %d characters", maxIconLength)
+	ErrInvalidScope  = fmt.Errorf("invalid saved view scope, must be one of: %s", strings.Join(Scopes, ", "))
+	ErrTeamRequired  = errors.New("team views need a team")
+	ErrInvalidTeam   = errors.New("team does not belong to this workspace")
+	ErrInvalidQuery  = errors.New("invalid saved view query")
+	ErrInvalidOrder  = errors.New("saved view order cannot contain duplicates")
+	ErrViewNotListed = errors.New("saved view order contains views you cannot see")
+)
+
+// Repository provides access to the saved views storage. Reads only return
+// views the user can see and carry that user's preferences.
+type Repository interface {
+	List(ctx context.Context, workspaceID, userID uuid.UUID, favouritesOnly bool) ([]CoreSavedView, error)
+	Get(ctx context.Context, id, workspaceID, userID uuid.UUID) (CoreSavedView, error)
+	Create(ctx context.Context, workspaceID, userID uuid.UUID, nv CoreNewSavedView) (CoreSavedView, error)
+	Update(ctx context.Context, view CoreSavedView) error
+	Delete(ctx context.Context, id, workspaceID uuid.UUID) error
+	SetFavourite(ctx context.Context, id, userID uuid.UUID, favourite bool) error
+	Reorder(ctx context.Context, workspaceID, userID uuid.UUID, ids []uuid.UUID) error
+}
+
+// Service provides saved view operations.
+type Service struct {
+	repo Repository
+	log  *logger.Logger
+}
+
+// New constructs a new saved views service instance with the provided repository.
+func New(log *logger.Logger, repo Repository) *Service {
+	return &Service{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// List returns the views the user can see, in the user's order. Views the
+// user has not placed come last, by name.
+func (s *Service) List(ctx context.Context, workspaceID, userID uuid.UUID, favouritesOnly bool) ([]CoreSavedView, error) {
+	s.log.Info(ctx, "business.core.savedviews.list")
+	ctx, span := web.AddSpan(ctx, "business.core.savedviews.List")
+	defer span.End()
+
+	views, err := s.repo.List(ctx, workspaceID, userID, favouritesOnly)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.AddEvent("saved views retrieved.", trace.WithAttributes(
+		attribute.Int("saved_view.count", len(views)),
+	))
+	return views, nil
+}
+
+// Get returns a single view the user can see.
+func (s *Service) Get(ctx context.Context, id, workspaceID, userID uuid.UUID) (CoreSavedView, error) {
+	s.log.Info(ctx, "business.core.savedviews.get")
+	ctx, span := web.AddSpan(ctx, "business.core.savedviews.Get")
+	defer span.End()
+
+	view, err := s.repo.Get(ctx, id, workspaceID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreSavedView{}, err
+	}
+	return view, nil
+}
+
+// Query returns the story query of a view, ready to run for the user. Filters
+// such as "assigned to me" resolve against the viewer, not the view's owner.
+func (s *Service) Query(ctx context.Context, id, workspaceID, userID uuid.UUID) (stories.CoreStoryQuery, error) {
+	s.log.Info(ctx, "business.core.savedviews.query")
+	ctx, span := web.AddSpan(ctx, "business.core.savedviews.Query")
+	defer span.End()
+
+	view, err := s.repo.Get(ctx, id, workspaceID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return stories.CoreStoryQuery{}, err
+	}
+
+	query := view.Query
+	query.Filters.CurrentUserID = userID
+	query.Filters.WorkspaceID = workspaceID
+	return query, nil
+}
+
+// Create saves a new view owned by the user.
+func (s *Service) Create(ctx context.Context, workspaceID, userID uuid.UUID, nv CoreNewSavedView) (CoreSavedView, error) {
+	s.log.Info(ctx, "business.core.savedviews.create")
+	ctx, span := web.AddSpan(ctx, "business.core.savedviews.Create")
+	defer span.End()
+
+	name, err := normalizeName(nv.Name)
+	if err != nil {
+		return CoreSavedView{}, err
+	}
+	nv.Name = name
+
+	if nv.Icon, err = normalizeIcon(nv.Icon); err != nil {
+		return CoreSavedView{}, err
+	}
+	if nv.Scope == "" {
+		nv.Scope = ScopePersonal
+	}
+	if nv.TeamID, err = scopeTeam(nv.Scope, nv.TeamID); err != nil {
+		return CoreSavedView{}, err
+	}
+	if nv.Query, err = normalizeQuery(nv.Query); err != nil {
+		return CoreSavedView{}, err
+	}
+
+	view, err := s.repo.Create(ctx, workspaceID, userID, nv)
+	if err != nil {
+		span.RecordError(err)
+		return CoreSavedView{}, err
+	}
+
+	span.AddEvent("saved view created.", trace.WithAttributes(
+		attribute.String("saved_view.id", view.ID.String()),
+		attribute.String("saved_view.scope", view.Scope),
+	))
+	return view, nil
+}
+
+// Update changes a view. Only the owner can change a personal view; shared
+// views can also be changed by workspace admins.
+func (s *Service) Update(ctx context.Context, id, workspaceID, userID uuid.UUID, isAdmin bool, uv CoreUpdateSavedView) (CoreSavedView, error) {
+	s.log.Info(ctx, "business.core.savedviews.update")
+	ctx, span := web.AddSpan(ctx, "business.core.savedviews.Update")
+	defer span.End()
+
+	view, err := s.repo.Get(ctx, id, workspaceID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreSavedView{}, err
+	}
+	if !canManage(view, userID, isAdmin) {
+		return CoreSavedView{}, ErrForbidden
+	}
+
+	if uv.Name != nil {
+		if view.Name, err = normalizeName(*uv.Name); err != nil {
+			return CoreSavedView{}, err
+		}
+	}
+	if uv.Icon != nil {
+		if view.Icon, err = normalizeIcon(uv.Icon); err != nil {
+			return CoreSavedView{}, err
+		}
+	}
+	if uv.Scope != nil {
+		view.Scope = *uv.Scope
+	}
+	if uv.TeamID != nil {
+		view.TeamID = uv.TeamID
+	}
+	if view.TeamID, err = scopeTeam(view.Scope, view.TeamID); err != nil {
+		return CoreSavedView{}, err
+	}
+	if uv.Query != nil {
+		if view.Query, err = normalizeQuery(*uv.Query); err != nil {
+			return CoreSavedView{}, err
+		}
+	}
+
+	if err := s.repo.Update(ctx, view); err != nil {
+		span.RecordError(err)
+		return CoreSavedView{}, err
+	}
+
+	span.AddEvent("saved view updated.", trace.WithAttributes(
+		attribute.String("saved_view.id", view.ID.String()),
+	))
+	return s.repo.Get(ctx, id, workspaceID, userID)
+}
+
+// Delete removes a view, with the same permissions as Update.
+func (s *Service) Delete(ctx context.Context, id, workspaceID, userID uuid.UUID, isAdmin bool) error {
+	s.log.Info(ctx, "business.core.savedviews.delete")
+	ctx, span := web.AddSpan(ctx, "business.core.savedviews.Delete")
+	defer span.End()
+
+	view, err := s.repo.Get(ctx, id, workspaceID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if !canManage(view, userID, isAdmin) {
+		return ErrForbidden
+	}
+
+	if err := s.repo.Delete(ctx, id, workspaceID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	span.AddEvent("saved view deleted.", trace.WithAttributes(
+		attribute.String("saved_view.id", id.String()),
+	))
+	return nil
+}
+
+// SetFavourite marks or unmarks a view as one of the user's favourites.
+func (s *Service) SetFavourite(ctx context.Context, id, workspaceID, userID uuid.UUID, favourite bool) error {
+	s.log.Info(ctx, "business.core.savedviews.setFavourite")
+	ctx, span := web.AddSpan(ctx, "business.core.savedviews.SetFavourite")
+	defer span.End()
+
+	if _, err := s.repo.Get(ctx, id, workspaceID, userID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.repo.SetFavourite(ctx, id, userID, favourite); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// Reorder stores the user's order of views. ids is the complete new order;
+// views left out lose their position and sort after the ordered ones.
+func (s *Service) Reorder(ctx context.Context, workspaceID, userID uuid.UUID, ids []uuid.UUID) error {
+	s.log.Info(ctx, "business.core.savedviews.reorder")
+	ctx, span := web.AddSpan(ctx, "business.core.savedviews.Reorder")
+	defer span.End()
+
+	views, err := s.repo.List(ctx, workspaceID, userID, false)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if err := validateOrder(ids, views); err != nil {
+		return err
+	}
+
+	if err := s.repo.Reorder(ctx, workspaceID, userID, ids); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+func canManage(view CoreSavedView, userID uuid.UUID, isAdmin bool) bool {
+	return view.OwnerID == userID || (isAdmin && view.Scope != ScopePersonal)
+}
+
+func normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrNameRequired
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return "", ErrNameTooLong
+	}
+	return name, nil
+}
+
+func normalizeIcon(icon *string) (*string, error) {
+	if icon == nil {
+		return nil, nil
+	}
+	value := strings.TrimSpace(*icon)
+	if value == "" {
+		return nil, nil
+	}
+	if utf8.RuneCountInString(value) > maxIconLength {
+		return nil, ErrIconTooLong
+	}
+	return &value, nil
+}
+
+// scopeTeam returns the team a view with the given scope belongs to. Only
+// team views keep a team.
+func scopeTeam(scope string, teamID *uuid.UUID) (*uuid.UUID, error) {
+	if !slices.Contains(Scopes, scope) {
+		return nil, ErrInvalidScope
+	}
+	if scope != ScopeTeam {
+		return nil, nil
+	}
+	if teamID == nil || *teamID == uuid.Nil {
+		return nil, ErrTeamRequired
+	}
+	return teamID, nil
+}
+
+// normalizeQuery validates a query before it is stored. The viewer, workspace
+// and pagination are filled in per request, so they are never persisted.
+func normalizeQuery(query stories.CoreStoryQuery) (stories.CoreStoryQuery, error) {
+	query.Filters.CurrentUserID = uuid.Nil
+	query.Filters.WorkspaceID = uuid.Nil
+	query.GroupKey = ""
+	query.Page = 0
+	query.PageSize = 0
+
+	if query.GroupBy == "" {
+		query.GroupBy = "status"
+	}
+	if query.OrderBy == "" {
+		query.OrderBy = "created"
+	}
+	if query.OrderDirection == "" {
+		query.OrderDirection = "desc"
+	}
+
+	if !stories.IsValidGroupBy(query.GroupBy) {
+		return query, fmt.Errorf("%w: unsupported groupBy %q", ErrInvalidQuery, query.GroupBy)
+	}
+	if !stories.IsValidOrderBy(query.OrderBy) {
+		return query, fmt.Errorf("%w: unsupported orderBy %q", ErrInvalidQuery, query.OrderBy)
+	}
+	if !stories.IsValidOrderDirection(query.OrderDirection) {
+		return query, fmt.Errorf("%w: orderDirection must be asc or desc", ErrInvalidQuery)
+	}
+	if query.StoriesPerGroup < 0 || query.StoriesPerGroup > maxStoriesPerGroup {
+		return query, fmt.Errorf("%w: storiesPerGroup must be between 0 and %d", ErrInvalidQuery, maxStoriesPerGroup)
+	}
+	return query, nil
+}
+
+func validateOrder(ids []uuid.UUID, visible []CoreSavedView) error {
+	known := make(map[uuid.UUID]bool, len(visible))
+	for _, view := range visible {
+		known[view.ID] = true
+	}
+
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return ErrInvalidOrder
+		}
+		if !known[id] {
+			return ErrViewNotListed
+		}
+		seen[id] = true
+	}
+	return nil
+}
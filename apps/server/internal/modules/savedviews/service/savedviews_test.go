@@ -0,0 +1,121 @@
+package savedviews
+
+import (
+	"errors"
+	"testing"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/google/uuid"
+)
+
+func TestNormalizeQueryDropsRequestState(t *testing.T) {
+	epicID := uuid.New()
+	query, err := normalizeQuery(stories.CoreStoryQuery{
+		Filters: stories.CoreStoryFilters{
+			Epic:          &epicID,
+			CurrentUserID: uuid.New(),
+			WorkspaceID:   uuid.New(),
+		},
+		GroupKey: "started",
+		Page:     3,
+		PageSize: 20,
+	})
+	if err != nil {
+		t.Fatalf("normalizeQuery() error = %v", err)
+	}
+
+	if query.Filters.CurrentUserID != uuid.Nil || query.Filters.WorkspaceID != uuid.Nil {
+		t.Errorf("viewer and workspace should not be stored, got %s and %s", query.Filters.CurrentUserID, query.Filters.WorkspaceID)
+	}
+	if query.GroupKey != "" || query.Page != 0 || query.PageSize != 0 {
+		t.Errorf("pagination should not be stored, got %q page %d size %d", query.GroupKey, query.Page, query.PageSize)
+	}
+	if query.Filters.Epic == nil || *query.Filters.Epic != epicID {
+		t.Errorf("filters should be kept, got epic %v", query.Filters.Epic)
+	}
+	if query.GroupBy != "status" || query.OrderBy != "created" || query.OrderDirection != "desc" {
+		t.Errorf("defaults not applied: %q %q %q", query.GroupBy, query.OrderBy, query.OrderDirection)
+	}
+}
+
+func TestNormalizeQueryRejectsUnsupportedValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		query stories.CoreStoryQuery
+	}{
+		{name: "group by", query: stories.CoreStoryQuery{GroupBy: "labels"}},
+		{name: "custom field group without id", query: stories.CoreStoryQuery{GroupBy: "custom_field:abc"}},
+		{name: "order by", query: stories.CoreStoryQuery{OrderBy: "title"}},
+		{name: "direction", query: stories.CoreStoryQuery{OrderDirection: "up"}},
+		{name: "stories per group", query: stories.CoreStoryQuery{StoriesPerGroup: 500}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := normalizeQuery(tt.query); !errors.Is(err, ErrInvalidQuery) {
+				t.Errorf("normalizeQuery() error = %v, want %v", err, ErrInvalidQuery)
+			}
+		})
+	}
+}
+
+func TestScopeTeam(t *testing.T) {
+	teamID := uuid.New()
+
+	if got, err := scopeTeam(ScopeTeam, &teamID); err != nil || got == nil || *got != teamID {
+		t.Errorf("team scope = %v, %v; want %s", got, err, teamID)
+	}
+	if got, err := scopeTeam(ScopeWorkspace, &teamID); err != nil || got != nil {
+		t.Errorf("workspace scope should drop the team, got %v, %v", got, err)
+	}
+	if _, err := scopeTeam(ScopeTeam, nil); !errors.Is(err, ErrTeamRequired) {
+		t.Errorf("team scope without team error = %v, want %v", err, ErrTeamRequired)
+	}
+	if _, err := scopeTeam("company", nil); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("unknown scope error = %v, want %v", err, ErrInvalidScope)
+	}
+}
+
+func TestCanManage(t *testing.T) {
+	owner := uuid.New()
+	other := uuid.New()
+
+	personal := CoreSavedView{OwnerID: owner, Scope: ScopePersonal}
+	shared := CoreSavedView{OwnerID: owner, Scope: ScopeWorkspace}
+
+	tests := []struct {
+		name    string
+		view    CoreSavedView
+		userID  uuid.UUID
+		isAdmin bool
+		want    bool
+	}{
+		{name: "owner", view: personal, userID: owner, want: true},
+		{name: "admin on personal view", view: personal, userID: other, isAdmin: true, want: false},
+		{name: "admin on shared view", view: shared, userID: other, isAdmin: true, want: true},
+		{name: "member on shared view", view: shared, userID: other, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canManage(tt.view, tt.userID, tt.isAdmin); got != tt.want {
+				t.Errorf("canManage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	visible := []CoreSavedView{{ID: a}, {ID: b}}
+
+	if err := validateOrder([]uuid.UUID{b, a}, visible); err != nil {
+		t.Errorf("validateOrder() error = %v", err)
+	}
+	if err := validateOrder([]uuid.UUID{a, a}, visible); !errors.Is(err, ErrInvalidOrder) {
+		t.Errorf("duplicate ids error = %v, want %v", err, ErrInvalidOrder)
+	}
+	if err := validateOrder([]uuid.UUID{a, uuid.New()}, visible); !errors.Is(err, ErrViewNotListed) {
+		t.Errorf("unknown id error = %v, want %v", err, ErrViewNotListed)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	search "github.com/complexus-tech/projects-api/internal/modules/search/service"
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
 	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
 	"github.com/complexus-tech/projects-api/pkg/web"
 	"github.com/google/uuid"
@@ -42,6 +43,7 @@ func (h *Handlers) Search(ctx context.Context, w http.ResponseWriter, r *http.Re
 	}
 
 	params.Query = r.URL.Query().Get("query")
+	params.Expression = r.URL.Query().Get("q")
 
 	if teamIDStr := r.URL.Query().Get("teamId"); teamIDStr != "" {
 		teamID, err := uuid.Parse(teamIDStr)
@@ -131,10 +133,14 @@ func (h *Handlers) Search(ctx context.Context, w http.ResponseWriter, r *http.Re
 		SortBy:     sortOption,
 		Page:       params.Page,
 		PageSize:   params.PageSize,
+		Expression: params.Expression,
 	}
 
 	result, err := h.searchService.Search(ctx, workspace.ID, userID, searchParams)
 	if err != nil {
+		if errors.Is(err, stories.ErrInvalidQueryExpression) {
+			return web.RespondError(ctx, w, err, http.StatusBadRequest)
+		}
 		return web.RespondError(ctx, w, err, http.StatusInternalServerError)
 	}
 
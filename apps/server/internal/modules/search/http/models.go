@@ -69,6 +69,7 @@ type AppSearchParams struct {
 	SortBy     string     `query:"sortBy"`
 	Page       int        `query:"page"`
 	PageSize   int        `query:"pageSize"`
+	Expression string     `query:"q"`
 }
 
 // toAppSearchStories converts core stories to app stories.
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	search "github.com/complexus-tech/projects-api/internal/modules/search/service"
+	storiesrepository "github.com/complexus-tech/projects-api/internal/modules/stories/repository"
 	"github.com/complexus-tech/projects-api/pkg/web"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
@@ -22,6 +23,25 @@ func (r *repo) SearchStories(ctx context.Context, workspaceID uuid.UUID, userID
 		attribute.String("search.query", params.Query),
 	)
 
+	// Conditions from a query expression, built as in story lists; their
+	// parameters are merged into the named parameters below.
+	var filterConditions string
+	filterParams := map[string]any{}
+	deletedCondition := "s.deleted_at IS NULL"
+	if filters := params.StoryFilters; filters != nil {
+		conditions, conditionParams := storiesrepository.StoryFilterConditions(*filters)
+		if filters.IncludeArchived != nil && *filters.IncludeArchived {
+			conditions = append(conditions, "s.archived_at IS NOT NULL")
+		}
+		for _, condition := range conditions {
+			filterConditions += "\n\t\t\tAND " + condition
+		}
+		filterParams = conditionParams
+		if filters.IncludeDeleted != nil && *filters.IncludeDeleted {
+			deletedCondition = "s.deleted_at IS NOT NULL"
+		}
+	}
+
 	// Build the main search query
 	queryBuilder := strings.Builder{}
 	queryBuilder.WriteString(`
@@ -58,7 +78,7 @@ func (r *repo) SearchStories(ctx context.Context, workspaceID uuid.UUID, userID
 			INNER JOIN team_members tm ON tm.team_id = s.team_id AND tm.user_id = :user_id
 		WHERE
 			s.workspace_id = :workspace_id
-			AND s.deleted_at IS NULL
+			AND ` + deletedCondition + `
 	`)
 
 	// Add search condition if query is provided
@@ -105,6 +125,8 @@ func (r *repo) SearchStories(ctx context.Context, workspaceID uuid.UUID, userID
 		`)
 	}
 
+	queryBuilder.WriteString(filterConditions)
+
 	// Add order by based on sort option
 	switch params.SortBy {
 	case search.SortByUpdated:
@@ -134,7 +156,7 @@ func (r *repo) SearchStories(ctx context.Context, workspaceID uuid.UUID, userID
 		FROM stories s
 		INNER JOIN team_members tm ON tm.team_id = s.team_id AND tm.user_id = :user_id
 		WHERE s.workspace_id = :workspace_id
-		AND s.deleted_at IS NULL
+		AND ` + deletedCondition + `
 	`
 
 	if params.Query != "" {
@@ -179,6 +201,8 @@ func (r *repo) SearchStories(ctx context.Context, workspaceID uuid.UUID, userID
 		`
 	}
 
+	countQuery += filterConditions
+
 	// Prepare named parameters
 	namedParams := map[string]any{
 		"workspace_id": workspaceID,
@@ -192,6 +216,9 @@ func (r *repo) SearchStories(ctx context.Context, workspaceID uuid.UUID, userID
 		"page_size":    params.PageSize,
 		"offset":       (params.Page - 1) * params.PageSize,
 	}
+	for name, value := range filterParams {
+		namedParams[name] = value
+	}
 
 	// Execute count query
 	var totalStories int
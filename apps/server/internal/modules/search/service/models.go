@@ -3,6 +3,7 @@ package search
 import (
 	"time"
 
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
 	"github.com/google/uuid"
 )
 
@@ -89,4 +90,9 @@ type SearchParams struct {
 	SortBy     SortOption
 	Page       int
 	PageSize   int
+	// Expression is a story query expression such as "assignee:me
+	// status:started login". Its free text is added to Query and its other
+	// terms are compiled into StoryFilters.
+	Expression   string
+	StoryFilters *stories.CoreStoryFilters
 }
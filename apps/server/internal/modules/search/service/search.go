@@ -2,7 +2,9 @@ package search
 
 import (
 	"context"
+	"strings"
 
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
 	"github.com/complexus-tech/projects-api/pkg/logger"
 	"github.com/complexus-tech/projects-api/pkg/web"
 	"github.com/google/uuid"
@@ -16,17 +18,24 @@ type Repository interface {
 	SearchObjectives(ctx context.Context, workspaceID uuid.UUID, userId uuid.UUID, params SearchParams) ([]CoreSearchObjective, int, error)
 }
 
+// QueryCompiler compiles story query expressions into filters.
+type QueryCompiler interface {
+	CompileQuery(ctx context.Context, workspaceID, userID uuid.UUID, expression string) (stories.CoreStoryFilters, error)
+}
+
 // Service provides search-related operations.
 type Service struct {
-	repo Repository
-	log  *logger.Logger
+	repo    Repository
+	queries QueryCompiler
+	log     *logger.Logger
 }
 
 // New constructs a new search service instance with the provided repository.
-func New(log *logger.Logger, repo Repository) *Service {
+func New(log *logger.Logger, repo Repository, queries QueryCompiler) *Service {
 	return &Service{
-		repo: repo,
-		log:  log,
+		repo:    repo,
+		queries: queries,
+		log:     log,
 	}
 }
 
@@ -46,6 +55,12 @@ func (s *Service) Search(ctx context.Context, workspaceID uuid.UUID, userId uuid
 	if params.Type == "" {
 		params.Type = SearchTypeAll
 	}
+	if params.Expression != "" {
+		if err := s.compileExpression(ctx, workspaceID, userId, &params); err != nil {
+			span.RecordError(err)
+			return CoreSearchResult{}, err
+		}
+	}
 
 	span.AddEvent("search initialized", trace.WithAttributes(
 		attribute.String("search.query", params.Query),
@@ -87,3 +102,19 @@ func (s *Service) Search(ctx context.Context, workspaceID uuid.UUID, userId uuid
 		TotalObjectives: totalObjectives,
 	}, nil
 }
+
+// compileExpression compiles params.Expression. Its free text joins
+// params.Query so it is ranked like any other search text; the rest narrows
+// stories only, so objectives are matched on the text alone.
+func (s *Service) compileExpression(ctx context.Context, workspaceID, userID uuid.UUID, params *SearchParams) error {
+	filters, err := s.queries.CompileQuery(ctx, workspaceID, userID, params.Expression)
+	if err != nil {
+		return err
+	}
+	if filters.TitleContains != nil {
+		params.Query = strings.TrimSpace(params.Query + " " + *filters.TitleContains)
+		filters.TitleContains = nil
+	}
+	params.StoryFilters = &filters
+	return nil
+}
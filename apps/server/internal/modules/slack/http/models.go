@@ -77,6 +77,17 @@ type AppRuntimeOptionsRequest struct {
 	TeamID string          `json:"teamId"`
 }
 
+type AppRuntimeViewsRequest struct {
+	Actor          AppRuntimeActor `json:"actor"`
+	FavouritesOnly bool            `json:"favouritesOnly"`
+}
+
+type AppRuntimeViewStoriesRequest struct {
+	Actor  AppRuntimeActor `json:"actor"`
+	ViewID string          `json:"viewId"`
+	Limit  int             `json:"limit"`
+}
+
 type AppRuntimeSlackInstallation struct {
 	BotToken  string `json:"botToken"`
 	BotUserID string `json:"botUserId,omitempty"`
@@ -43,6 +43,8 @@ func Routes(cfg Config, app *web.App) {
 	app.Post("/internal/bot/slack/options/objectives", h.RuntimeSearchObjectives, h.BotAuth)
 	app.Post("/internal/bot/slack/options/labels", h.RuntimeSearchLabels, h.BotAuth)
 	app.Post("/internal/bot/slack/stories", h.RuntimeCreateStory, h.BotAuth)
+	app.Post("/internal/bot/slack/views", h.RuntimeListViews, h.BotAuth)
+	app.Post("/internal/bot/slack/views/stories", h.RuntimeViewStories, h.BotAuth)
 	app.Post("/internal/bot/slack/thread-comments", h.RuntimeRecordThreadComment, h.BotAuth)
 	app.Post("/internal/bot/slack/unfurls/story", h.RuntimeStoryUnfurl, h.BotAuth)
 	app.Post("/internal/bot/slack/notifications/mentions", h.RuntimeMentionNotifications, h.BotAuth)
@@ -132,6 +132,30 @@ func (h *Handlers) RuntimeSearchLabels(ctx context.Context, w http.ResponseWrite
 	return web.Respond(ctx, w, options, http.StatusOK)
 }
 
+func (h *Handlers) RuntimeListViews(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var input AppRuntimeViewsRequest
+	if err := web.Decode(r, &input); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	views, err := h.service.RuntimeListViews(ctx, toCoreRuntimeActor(input.Actor), input.FavouritesOnly)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	return web.Respond(ctx, w, views, http.StatusOK)
+}
+
+func (h *Handlers) RuntimeViewStories(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var input AppRuntimeViewStoriesRequest
+	if err := web.Decode(r, &input); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	result, err := h.service.RuntimeViewStories(ctx, toCoreRuntimeActor(input.Actor), input.ViewID, input.Limit)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	return web.Respond(ctx, w, result, http.StatusOK)
+}
+
 func (h *Handlers) RuntimeGetInstallation(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	teamID := strings.TrimSpace(web.Params(r, "teamId"))
 	installation, err := h.service.RuntimeGetInstallation(ctx, teamID)
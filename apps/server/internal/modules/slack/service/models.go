@@ -5,6 +5,7 @@ import (
 	"time"
 
 	integrationrequests "github.com/complexus-tech/projects-api/internal/modules/integrationrequests/service"
+	savedviews "github.com/complexus-tech/projects-api/internal/modules/savedviews/service"
 	slackrepository "github.com/complexus-tech/projects-api/internal/modules/slack/repository"
 	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
 	"github.com/google/uuid"
@@ -53,6 +54,13 @@ type RequestStore interface {
 type StoryService interface {
 	CreateExternal(ctx context.Context, actorID uuid.UUID, ns stories.CoreNewStory, workspaceID uuid.UUID) (stories.CoreSingleStory, error)
 	UpdateLabels(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID, labels []uuid.UUID) error
+	ListGroupedStories(ctx context.Context, query stories.CoreStoryQuery) ([]stories.CoreStoryGroup, error)
+}
+
+// SavedViewService gives the bot access to the saved views of a linked user.
+type SavedViewService interface {
+	List(ctx context.Context, workspaceID, userID uuid.UUID, favouritesOnly bool) ([]savedviews.CoreSavedView, error)
+	Query(ctx context.Context, id, workspaceID, userID uuid.UUID) (stories.CoreStoryQuery, error)
 }
 
 type Config struct {
@@ -210,6 +218,28 @@ type CoreRuntimeCreatedStory struct {
 	URL   string `json:"url"`
 }
 
+type CoreRuntimeSavedView struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Icon        *string `json:"icon"`
+	Scope       string  `json:"scope"`
+	IsFavourite bool    `json:"isFavourite"`
+}
+
+type CoreRuntimeViewStory struct {
+	ID       string `json:"id"`
+	Ref      string `json:"ref"`
+	Title    string `json:"title"`
+	Priority string `json:"priority"`
+	URL      string `json:"url"`
+}
+
+type CoreRuntimeViewStories struct {
+	ViewID  string                 `json:"viewId"`
+	Stories []CoreRuntimeViewStory `json:"stories"`
+	HasMore bool                   `json:"hasMore"`
+}
+
 type CoreRuntimeSlackInstallation struct {
 	BotToken  string
 	BotUserID string
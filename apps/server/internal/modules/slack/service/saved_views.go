@@ -0,0 +1,88 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultRuntimeViewLimit = 10
+	maxRuntimeViewLimit     = 50
+)
+
+// RuntimeListViews returns the saved views the linked user can see.
+func (s *Service) RuntimeListViews(ctx context.Context, actor CoreRuntimeActor, favouritesOnly bool) ([]CoreRuntimeSavedView, error) {
+	workspace, actorID, err := s.runtimeWorkspaceAndLinkedActor(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	views, err := s.views.List(ctx, workspace.ID, actorID, favouritesOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]CoreRuntimeSavedView, 0, len(views))
+	for _, view := range views {
+		result = append(result, CoreRuntimeSavedView{
+			ID:          view.ID.String(),
+			Name:        view.Name,
+			Icon:        view.Icon,
+			Scope:       view.Scope,
+			IsFavourite: view.IsFavourite,
+		})
+	}
+	return result, nil
+}
+
+// RuntimeViewStories runs a saved view for the linked user and returns its
+// first stories in the view's order, without grouping.
+func (s *Service) RuntimeViewStories(ctx context.Context, actor CoreRuntimeActor, viewIDRaw string, limit int) (CoreRuntimeViewStories, error) {
+	workspace, actorID, err := s.runtimeWorkspaceAndLinkedActor(ctx, actor)
+	if err != nil {
+		return CoreRuntimeViewStories{}, err
+	}
+	viewID, err := uuid.Parse(strings.TrimSpace(viewIDRaw))
+	if err != nil {
+		return CoreRuntimeViewStories{}, fmt.Errorf("invalid view: %w", err)
+	}
+	if limit <= 0 || limit > maxRuntimeViewLimit {
+		limit = defaultRuntimeViewLimit
+	}
+
+	query, err := s.views.Query(ctx, viewID, workspace.ID, actorID)
+	if err != nil {
+		return CoreRuntimeViewStories{}, err
+	}
+	query.GroupBy = "none"
+	query.StoriesPerGroup = limit
+
+	groups, err := s.stories.ListGroupedStories(ctx, query)
+	if err != nil {
+		return CoreRuntimeViewStories{}, err
+	}
+
+	result := CoreRuntimeViewStories{
+		ViewID:  viewID.String(),
+		Stories: make([]CoreRuntimeViewStory, 0, limit),
+	}
+	for _, group := range groups {
+		result.HasMore = result.HasMore || group.HasMore
+		for _, story := range group.Stories {
+			ref := ""
+			if story.TeamSummary != nil {
+				ref = fmt.Sprintf("%s-%d", story.TeamSummary.Code, story.SequenceID)
+			}
+			result.Stories = append(result.Stories, CoreRuntimeViewStory{
+				ID:       story.ID.String(),
+				Ref:      ref,
+				Title:    story.Title,
+				Priority: story.Priority,
+				URL:      buildTaskURL(s.cfg.WebsiteURL, workspace.Slug, story.ID.String()),
+			})
+		}
+	}
+	return result, nil
+}
@@ -72,17 +72,19 @@ type Service struct {
 	repo     Repository
 	requests RequestStore
 	stories  StoryService
+	views    SavedViewService
 	cfg      Config
 	client   *http.Client
 	clock    Clock
 }
 
-func New(log *logger.Logger, repo Repository, requests RequestStore, stories StoryService, cfg Config) *Service {
+func New(log *logger.Logger, repo Repository, requests RequestStore, stories StoryService, views SavedViewService, cfg Config) *Service {
 	return &Service{
 		log:      log,
 		repo:     repo,
 		requests: requests,
 		stories:  stories,
+		views:    views,
 		cfg:      cfg,
 		client: &http.Client{
 			Timeout: 12 * time.Second,
@@ -420,9 +420,13 @@ func (m *mockStoryService) UpdateLabels(ctx context.Context, id uuid.UUID, works
 	return nil
 }
 
+func (m *mockStoryService) ListGroupedStories(ctx context.Context, query stories.CoreStoryQuery) ([]stories.CoreStoryGroup, error) {
+	return nil, nil
+}
+
 func newTestService(repo Repository, requests RequestStore, storyService StoryService, cfg Config) *Service {
 	testLogger := logger.NewWithJSON(io.Discard, slog.LevelError, "test")
-	service := New(testLogger, repo, requests, storyService, cfg)
+	service := New(testLogger, repo, requests, storyService, nil, cfg)
 	service.clock = fixedClock{now: time.Unix(1_700_000_000, 0)}
 	return service
 }
@@ -0,0 +1,176 @@
+package storieshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// AppAssociationType is a kind of link between two stories. Built-in types
+// have no id or timestamps.
+type AppAssociationType struct {
+	ID            *uuid.UUID `json:"id"`
+	Key           string     `json:"key"`
+	ForwardLabel  string     `json:"forwardLabel"`
+	BackwardLabel string     `json:"backwardLabel"`
+	System        bool       `json:"system"`
+	CreatedBy     *uuid.UUID `json:"createdBy"`
+	CreatedAt     *time.Time `json:"createdAt"`
+	UpdatedAt     *time.Time `json:"updatedAt"`
+}
+
+// AppNewAssociationType is an association type to define in a workspace.
+type AppNewAssociationType struct {
+	Key           string `json:"key" validate:"required"`
+	ForwardLabel  string `json:"forwardLabel" validate:"required"`
+	BackwardLabel string `json:"backwardLabel" validate:"required"`
+}
+
+// AppUpdateAssociationType holds the new labels of an association type.
+type AppUpdateAssociationType struct {
+	ForwardLabel  string `json:"forwardLabel" validate:"required"`
+	BackwardLabel string `json:"backwardLabel" validate:"required"`
+}
+
+func toAppAssociationType(t stories.CoreAssociationType) AppAssociationType {
+	appType := AppAssociationType{
+		Key:           t.Key,
+		ForwardLabel:  t.ForwardLabel,
+		BackwardLabel: t.BackwardLabel,
+		System:        t.System,
+	}
+	if !t.System {
+		appType.ID = &t.ID
+		appType.CreatedBy = t.CreatedBy
+		appType.CreatedAt = &t.CreatedAt
+		appType.UpdatedAt = &t.UpdatedAt
+	}
+	return appType
+}
+
+// ListAssociationTypes returns the association types stories in the
+// workspace can be linked with.
+func (h *Handlers) ListAssociationTypes(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.ListAssociationTypes")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	types, err := h.stories.ListAssociationTypes(ctx, workspace.ID)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusInternalServerError)
+		return nil
+	}
+
+	appTypes := make([]AppAssociationType, len(types))
+	for i, t := range types {
+		appTypes[i] = toAppAssociationType(t)
+	}
+	return web.Respond(ctx, w, appTypes, http.StatusOK)
+}
+
+// CreateAssociationType defines a new association type in the workspace.
+func (h *Handlers) CreateAssociationType(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.CreateAssociationType")
+	defer span.End()
+
+	var req AppNewAssociationType
+	if err := web.Decode(r, &req); err != nil {
+		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	created, err := h.stories.CreateAssociationType(ctx, workspace.ID, userID, stories.CoreNewAssociationType{
+		Key:           req.Key,
+		ForwardLabel:  req.ForwardLabel,
+		BackwardLabel: req.BackwardLabel,
+	})
+	if err != nil {
+		web.RespondError(ctx, w, err, associationTypeErrorStatus(err))
+		return nil
+	}
+	return web.Respond(ctx, w, toAppAssociationType(created), http.StatusCreated)
+}
+
+// UpdateAssociationType relabels one of the workspace's association types.
+func (h *Handlers) UpdateAssociationType(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.UpdateAssociationType")
+	defer span.End()
+
+	var req AppUpdateAssociationType
+	if err := web.Decode(r, &req); err != nil {
+		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	updated, err := h.stories.UpdateAssociationType(ctx, workspace.ID, web.Params(r, "key"), stories.CoreUpdateAssociationType{
+		ForwardLabel:  req.ForwardLabel,
+		BackwardLabel: req.BackwardLabel,
+	})
+	if err != nil {
+		web.RespondError(ctx, w, err, associationTypeErrorStatus(err))
+		return nil
+	}
+	return web.Respond(ctx, w, toAppAssociationType(updated), http.StatusOK)
+}
+
+// DeleteAssociationType removes an association type no story uses.
+func (h *Handlers) DeleteAssociationType(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.DeleteAssociationType")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	if err := h.stories.DeleteAssociationType(ctx, workspace.ID, web.Params(r, "key")); err != nil {
+		web.RespondError(ctx, w, err, associationTypeErrorStatus(err))
+		return nil
+	}
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+func associationTypeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, stories.ErrAssociationTypeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, stories.ErrAssociationTypeExists), errors.Is(err, stories.ErrAssociationTypeInUse):
+		return http.StatusConflict
+	case errors.Is(err, stories.ErrInvalidAssociationTypeKey),
+		errors.Is(err, stories.ErrInvalidAssociationTypeLabel),
+		errors.Is(err, stories.ErrSystemAssociationType):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,301 @@
+package storieshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidChecklistItemID is returned when the item id parameter is not a UUID.
+var ErrInvalidChecklistItemID = errors.New("checklist item id is not in its proper form")
+
+// AppChecklistItem is a step on a story's checklist.
+type AppChecklistItem struct {
+	ID          uuid.UUID  `json:"id"`
+	StoryID     uuid.UUID  `json:"storyId"`
+	Text        string     `json:"text"`
+	Done        bool       `json:"done"`
+	AssigneeID  *uuid.UUID `json:"assigneeId"`
+	DueDate     *time.Time `json:"dueDate"`
+	OrderIndex  int        `json:"orderIndex"`
+	CreatedBy   *uuid.UUID `json:"createdBy"`
+	CompletedAt *time.Time `json:"completedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// AppNewChecklistItem is a checklist item to add to a story.
+type AppNewChecklistItem struct {
+	Text       string     `json:"text"`
+	AssigneeID *uuid.UUID `json:"assigneeId"`
+	DueDate    *time.Time `json:"dueDate"`
+}
+
+// AppReorderChecklist lists every item of a story's checklist in its new
+// order.
+type AppReorderChecklist struct {
+	ItemIDs []uuid.UUID `json:"itemIds"`
+}
+
+func toAppChecklistItem(item stories.CoreChecklistItem) AppChecklistItem {
+	return AppChecklistItem{
+		ID:          item.ID,
+		StoryID:     item.StoryID,
+		Text:        item.Text,
+		Done:        item.Done,
+		AssigneeID:  item.AssigneeID,
+		DueDate:     item.DueDate,
+		OrderIndex:  item.OrderIndex,
+		CreatedBy:   item.CreatedBy,
+		CompletedAt: item.CompletedAt,
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}
+}
+
+func toAppChecklistItems(items []stories.CoreChecklistItem) []AppChecklistItem {
+	appItems := make([]AppChecklistItem, len(items))
+	for i, item := range items {
+		appItems[i] = toAppChecklistItem(item)
+	}
+	return appItems
+}
+
+// ListChecklistItems returns a story's checklist in order.
+func (h *Handlers) ListChecklistItems(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.ListChecklistItems")
+	defer span.End()
+
+	storyID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	items, err := h.stories.ListChecklistItems(ctx, storyID, workspace.ID)
+	if err != nil {
+		web.RespondError(ctx, w, err, checklistErrorStatus(err))
+		return nil
+	}
+	return web.Respond(ctx, w, toAppChecklistItems(items), http.StatusOK)
+}
+
+// AddChecklistItem adds an item to the end of a story's checklist.
+func (h *Handlers) AddChecklistItem(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.AddChecklistItem")
+	defer span.End()
+
+	storyID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return nil
+	}
+
+	var req AppNewChecklistItem
+	if err := web.Decode(r, &req); err != nil {
+		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	item, err := h.stories.AddChecklistItem(ctx, storyID, workspace.ID, userID, stories.CoreNewChecklistItem{
+		Text:       req.Text,
+		AssigneeID: req.AssigneeID,
+		DueDate:    req.DueDate,
+	})
+	if err != nil {
+		web.RespondError(ctx, w, err, checklistErrorStatus(err))
+		return nil
+	}
+
+	h.invalidateCacheForStory(ctx, workspace.ID, storyID)
+	return web.Respond(ctx, w, toAppChecklistItem(item), http.StatusCreated)
+}
+
+// ReorderChecklistItems puts a story's checklist in a new order and returns
+// the reordered checklist.
+func (h *Handlers) ReorderChecklistItems(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.ReorderChecklistItems")
+	defer span.End()
+
+	storyID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return nil
+	}
+
+	var req AppReorderChecklist
+	if err := web.Decode(r, &req); err != nil {
+		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	items, err := h.stories.ReorderChecklistItems(ctx, storyID, workspace.ID, userID, req.ItemIDs)
+	if err != nil {
+		web.RespondError(ctx, w, err, checklistErrorStatus(err))
+		return nil
+	}
+
+	h.invalidateCacheForStory(ctx, workspace.ID, storyID)
+	return web.Respond(ctx, w, toAppChecklistItems(items), http.StatusOK)
+}
+
+// ToggleChecklistItem marks a checklist item done or not done.
+func (h *Handlers) ToggleChecklistItem(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.ToggleChecklistItem")
+	defer span.End()
+
+	storyID, itemID, ok := checklistItemParams(ctx, w, r)
+	if !ok {
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	item, err := h.stories.ToggleChecklistItem(ctx, storyID, workspace.ID, userID, itemID)
+	if err != nil {
+		web.RespondError(ctx, w, err, checklistErrorStatus(err))
+		return nil
+	}
+
+	h.invalidateCacheForStory(ctx, workspace.ID, storyID)
+	return web.Respond(ctx, w, toAppChecklistItem(item), http.StatusOK)
+}
+
+// DeleteChecklistItem removes an item from a story's checklist.
+func (h *Handlers) DeleteChecklistItem(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.DeleteChecklistItem")
+	defer span.End()
+
+	storyID, itemID, ok := checklistItemParams(ctx, w, r)
+	if !ok {
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	if err := h.stories.DeleteChecklistItem(ctx, storyID, workspace.ID, userID, itemID); err != nil {
+		web.RespondError(ctx, w, err, checklistErrorStatus(err))
+		return nil
+	}
+
+	h.invalidateCacheForStory(ctx, workspace.ID, storyID)
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// ConvertChecklistItem turns a checklist item into a sub-story and returns
+// the sub-story.
+func (h *Handlers) ConvertChecklistItem(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.ConvertChecklistItem")
+	defer span.End()
+
+	storyID, itemID, ok := checklistItemParams(ctx, w, r)
+	if !ok {
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	subStory, err := h.stories.ConvertChecklistItem(ctx, storyID, workspace.ID, userID, itemID)
+	if err != nil {
+		web.RespondError(ctx, w, err, checklistErrorStatus(err))
+		return nil
+	}
+
+	h.invalidateStories(ctx, workspace.ID, storyID, subStory.ID)
+	return h.respondStory(ctx, w, subStory, http.StatusCreated)
+}
+
+// checklistItemParams parses the story and item ids of a checklist item
+// route, responding with an error when either is malformed.
+func checklistItemParams(ctx context.Context, w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	storyID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	itemID, err := uuid.Parse(web.Params(r, "itemId"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidChecklistItemID, http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return storyID, itemID, true
+}
+
+func checklistErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, stories.ErrNotFound), errors.Is(err, stories.ErrChecklistItemNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, stories.ErrInvalidChecklistItem),
+		errors.Is(err, stories.ErrInvalidChecklistAssignee),
+		errors.Is(err, stories.ErrInvalidChecklistOrder):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,167 @@
+package storieshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidScopeID is returned when the dependency graph id is not a UUID.
+var ErrInvalidScopeID = errors.New("scope id is not in its proper form")
+
+// AppDependencyNode is a story in a dependency graph.
+type AppDependencyNode struct {
+	ID              uuid.UUID  `json:"id"`
+	SequenceID      int        `json:"sequenceId"`
+	Title           string     `json:"title"`
+	TeamID          uuid.UUID  `json:"teamId"`
+	TeamCode        string     `json:"teamCode"`
+	StatusID        *uuid.UUID `json:"statusId"`
+	StatusCategory  string     `json:"statusCategory"`
+	AssigneeID      *uuid.UUID `json:"assigneeId"`
+	EstimateValue   *int16     `json:"estimateValue"`
+	EstimateLabel   *string    `json:"estimateLabel"`
+	StartDate       *time.Time `json:"startDate"`
+	EndDate         *time.Time `json:"endDate"`
+	CompletedAt     *time.Time `json:"completedAt"`
+	InScope         bool       `json:"inScope"`
+	DurationMinutes int        `json:"durationMinutes"`
+	OnCriticalPath  bool       `json:"onCriticalPath"`
+}
+
+// AppDependencyEdge is a blocking link between two stories.
+type AppDependencyEdge struct {
+	ID        uuid.UUID `json:"id"`
+	BlockerID uuid.UUID `json:"blockerId"`
+	BlockedID uuid.UUID `json:"blockedId"`
+}
+
+// AppScheduleConflict is a story scheduled to start before its blocker ends.
+type AppScheduleConflict struct {
+	BlockerID        uuid.UUID `json:"blockerId"`
+	BlockedID        uuid.UUID `json:"blockedId"`
+	BlockerEndDate   time.Time `json:"blockerEndDate"`
+	BlockedStartDate time.Time `json:"blockedStartDate"`
+}
+
+// AppDependencyGraph is the blocking graph around a story, epic, objective
+// or sprint.
+type AppDependencyGraph struct {
+	Nodes               []AppDependencyNode   `json:"nodes"`
+	Edges               []AppDependencyEdge   `json:"edges"`
+	Blockers            []uuid.UUID           `json:"blockers"`
+	Dependents          []uuid.UUID           `json:"dependents"`
+	CriticalPath        []uuid.UUID           `json:"criticalPath"`
+	CriticalPathMinutes int                   `json:"criticalPathMinutes"`
+	Conflicts           []AppScheduleConflict `json:"conflicts"`
+	HasCycle            bool                  `json:"hasCycle"`
+}
+
+// GetDependencyGraph returns the blocking graph for the story, epic,
+// objective or sprint in the scope and id query parameters. The scope
+// defaults to story.
+func (h *Handlers) GetDependencyGraph(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.GetDependencyGraph")
+	defer span.End()
+
+	scope := r.URL.Query().Get("scope")
+	if scope == "" {
+		scope = stories.DependencyScopeStory
+	}
+	if !stories.ValidDependencyScope(scope) {
+		web.RespondError(ctx, w, stories.ErrInvalidDependencyScope, http.StatusBadRequest)
+		return nil
+	}
+
+	scopeID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidScopeID, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	graph, err := h.stories.DependencyGraph(ctx, workspace.ID, scope, scopeID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, stories.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		web.RespondError(ctx, w, err, status)
+		return nil
+	}
+
+	return web.Respond(ctx, w, toAppDependencyGraph(graph), http.StatusOK)
+}
+
+func toAppDependencyGraph(graph stories.CoreDependencyGraph) AppDependencyGraph {
+	critical := make(map[uuid.UUID]bool, len(graph.CriticalPath))
+	for _, id := range graph.CriticalPath {
+		critical[id] = true
+	}
+
+	appGraph := AppDependencyGraph{
+		Nodes:               make([]AppDependencyNode, len(graph.Nodes)),
+		Edges:               make([]AppDependencyEdge, len(graph.Edges)),
+		Blockers:            graph.Blockers,
+		Dependents:          graph.Dependents,
+		CriticalPath:        graph.CriticalPath,
+		CriticalPathMinutes: graph.CriticalPathMinutes,
+		Conflicts:           make([]AppScheduleConflict, len(graph.Conflicts)),
+		HasCycle:            graph.HasCycle,
+	}
+	for i, node := range graph.Nodes {
+		appGraph.Nodes[i] = AppDependencyNode{
+			ID:              node.ID,
+			SequenceID:      node.SequenceID,
+			Title:           node.Title,
+			TeamID:          node.TeamID,
+			TeamCode:        node.TeamCode,
+			StatusID:        node.StatusID,
+			StatusCategory:  node.StatusCategory,
+			AssigneeID:      node.AssigneeID,
+			EstimateValue:   node.EstimateValue,
+			EstimateLabel:   node.EstimateLabel,
+			StartDate:       node.StartDate,
+			EndDate:         node.EndDate,
+			CompletedAt:     node.CompletedAt,
+			InScope:         node.InScope,
+			DurationMinutes: node.DurationMinutes,
+			OnCriticalPath:  critical[node.ID],
+		}
+	}
+	for i, edge := range graph.Edges {
+		appGraph.Edges[i] = AppDependencyEdge{
+			ID:        edge.ID,
+			BlockerID: edge.BlockerID,
+			BlockedID: edge.BlockedID,
+		}
+	}
+	for i, conflict := range graph.Conflicts {
+		appGraph.Conflicts[i] = AppScheduleConflict{
+			BlockerID:        conflict.BlockerID,
+			BlockedID:        conflict.BlockedID,
+			BlockerEndDate:   conflict.BlockerEndDate,
+			BlockedStartDate: conflict.BlockedStartDate,
+		}
+	}
+	return appGraph
+}
+
+// associationStatus maps association errors to response statuses.
+func associationStatus(err error) int {
+	if errors.Is(err, stories.ErrDependencyCycle) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,205 @@
+package storieshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidRevisionID is returned when a revision id is not a UUID.
+var ErrInvalidRevisionID = errors.New("revision id is not in its proper form")
+
+// AppDescriptionRevision is a saved version of a story description.
+type AppDescriptionRevision struct {
+	ID              uuid.UUID       `json:"id"`
+	Description     *string         `json:"description"`
+	DescriptionHTML *string         `json:"descriptionHTML"`
+	EditedBy        *AppUserSummary `json:"editedBy"`
+	Reason          *string         `json:"reason"`
+	CreatedAt       time.Time       `json:"createdAt"`
+}
+
+// AppDiffLine is one line of a description diff.
+type AppDiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// AppDescriptionDiff compares two description revisions.
+type AppDescriptionDiff struct {
+	From    AppDescriptionRevision `json:"from"`
+	To      AppDescriptionRevision `json:"to"`
+	Lines   []AppDiffLine          `json:"lines"`
+	Added   int                    `json:"added"`
+	Removed int                    `json:"removed"`
+}
+
+// ListDescriptionRevisions returns a story's description revisions, newest
+// first.
+func (h *Handlers) ListDescriptionRevisions(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.ListDescriptionRevisions")
+	defer span.End()
+
+	storyID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	revisions, err := h.stories.ListDescriptionRevisions(ctx, storyID, workspace.ID)
+	if err != nil {
+		web.RespondError(ctx, w, err, descriptionRevisionStatus(err))
+		return nil
+	}
+
+	usersByID, err := h.getStoryUsers(ctx, revisionEditorIDs(revisions...))
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusInternalServerError)
+		return nil
+	}
+
+	appRevisions := make([]AppDescriptionRevision, len(revisions))
+	for i, revision := range revisions {
+		appRevisions[i] = toAppDescriptionRevision(revision, usersByID)
+	}
+	return web.Respond(ctx, w, appRevisions, http.StatusOK)
+}
+
+// DiffDescriptionRevisions returns the line diff between the revisions in
+// the from and to query parameters.
+func (h *Handlers) DiffDescriptionRevisions(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.DiffDescriptionRevisions")
+	defer span.End()
+
+	storyID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return nil
+	}
+
+	fromID, err := uuid.Parse(r.URL.Query().Get("from"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidRevisionID, http.StatusBadRequest)
+		return nil
+	}
+	toID, err := uuid.Parse(r.URL.Query().Get("to"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidRevisionID, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	diff, err := h.stories.DiffDescriptionRevisions(ctx, storyID, workspace.ID, fromID, toID)
+	if err != nil {
+		web.RespondError(ctx, w, err, descriptionRevisionStatus(err))
+		return nil
+	}
+
+	usersByID, err := h.getStoryUsers(ctx, revisionEditorIDs(diff.From, diff.To))
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusInternalServerError)
+		return nil
+	}
+
+	lines := make([]AppDiffLine, len(diff.Lines))
+	for i, line := range diff.Lines {
+		lines[i] = AppDiffLine{Op: line.Op, Text: line.Text}
+	}
+	return web.Respond(ctx, w, AppDescriptionDiff{
+		From:    toAppDescriptionRevision(diff.From, usersByID),
+		To:      toAppDescriptionRevision(diff.To, usersByID),
+		Lines:   lines,
+		Added:   diff.Added,
+		Removed: diff.Removed,
+	}, http.StatusOK)
+}
+
+// RestoreDescriptionRevision sets a story's description back to a revision.
+func (h *Handlers) RestoreDescriptionRevision(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.RestoreDescriptionRevision")
+	defer span.End()
+
+	storyID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return nil
+	}
+
+	revisionID, err := uuid.Parse(web.Params(r, "revisionId"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidRevisionID, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	if err := h.stories.RestoreDescriptionRevision(ctx, storyID, workspace.ID, userID, revisionID); err != nil {
+		web.RespondError(ctx, w, err, descriptionRevisionStatus(err))
+		return nil
+	}
+
+	web.Respond(ctx, w, nil, http.StatusNoContent)
+	return nil
+}
+
+func revisionEditorIDs(revisions ...stories.CoreDescriptionRevision) []uuid.UUID {
+	userIDs := make(map[uuid.UUID]struct{})
+	for _, revision := range revisions {
+		if revision.EditedBy != nil {
+			userIDs[*revision.EditedBy] = struct{}{}
+		}
+	}
+	return mapUserIDs(userIDs)
+}
+
+func toAppDescriptionRevision(revision stories.CoreDescriptionRevision, usersByID map[uuid.UUID]AppUserSummary) AppDescriptionRevision {
+	appRevision := AppDescriptionRevision{
+		ID:              revision.ID,
+		Description:     revision.Description,
+		DescriptionHTML: revision.DescriptionHTML,
+		Reason:          revision.Reason,
+		CreatedAt:       revision.CreatedAt,
+	}
+	if revision.EditedBy != nil {
+		if user, ok := usersByID[*revision.EditedBy]; ok {
+			appRevision.EditedBy = &user
+		}
+	}
+	return appRevision
+}
+
+func descriptionRevisionStatus(err error) int {
+	switch {
+	case errors.Is(err, stories.ErrDescriptionRevisionNotFound), errors.Is(err, stories.ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
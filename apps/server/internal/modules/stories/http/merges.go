@@ -0,0 +1,98 @@
+package storieshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// AppMergeStoryRequest names the story a duplicate is merged into.
+type AppMergeStoryRequest struct {
+	CanonicalStoryID uuid.UUID `json:"canonicalStoryId"`
+}
+
+// AppStoryMerge is a duplicate story merged into its canonical story, with
+// how much moved across.
+type AppStoryMerge struct {
+	DuplicateStoryID uuid.UUID `json:"duplicateStoryId"`
+	CanonicalStoryID uuid.UUID `json:"canonicalStoryId"`
+	DuplicateRef     string    `json:"duplicateRef"`
+	CanonicalRef     string    `json:"canonicalRef"`
+	Comments         int       `json:"comments"`
+	Attachments      int       `json:"attachments"`
+	Links            int       `json:"links"`
+	Labels           int       `json:"labels"`
+	GitHubLinks      int       `json:"githubLinks"`
+	FeedbackLinks    int       `json:"feedbackLinks"`
+	Watchers         int       `json:"watchers"`
+	TimeEntries      int       `json:"timeEntries"`
+}
+
+// MergeStory closes a duplicate story into its canonical story.
+func (h *Handlers) MergeStory(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.MergeStory")
+	defer span.End()
+
+	duplicateID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return nil
+	}
+
+	var req AppMergeStoryRequest
+	if err := web.Decode(r, &req); err != nil {
+		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	merge, err := h.stories.MergeStories(ctx, workspace.ID, userID, duplicateID, req.CanonicalStoryID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, stories.ErrNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, stories.ErrMergeIntoSelf), errors.Is(err, stories.ErrTransitionNotAllowed), errors.Is(err, stories.ErrTransitionMissingFields):
+			status = http.StatusBadRequest
+		case errors.Is(err, stories.ErrTransitionForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, stories.ErrDependencyCycle), errors.Is(err, stories.ErrNoCancelledStatus):
+			status = http.StatusConflict
+		}
+		web.RespondError(ctx, w, err, status)
+		return nil
+	}
+
+	h.invalidateStories(ctx, workspace.ID, merge.DuplicateID, merge.CanonicalID)
+
+	return web.Respond(ctx, w, AppStoryMerge{
+		DuplicateStoryID: merge.DuplicateID,
+		CanonicalStoryID: merge.CanonicalID,
+		DuplicateRef:     merge.DuplicateRef,
+		CanonicalRef:     merge.CanonicalRef,
+		Comments:         merge.Comments,
+		Attachments:      merge.Attachments,
+		Links:            merge.Links,
+		Labels:           merge.Labels,
+		GitHubLinks:      merge.GitHubLinks,
+		FeedbackLinks:    merge.FeedbackLinks,
+		Watchers:         merge.Watchers,
+		TimeEntries:      merge.TimeEntries,
+	}, http.StatusOK)
+}
@@ -1,6 +1,7 @@
 package storieshttp
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
@@ -177,41 +178,43 @@ type AppLabel struct {
 
 // AppSingleStory represents a single story in the application.
 type AppSingleStory struct {
-	ID              uuid.UUID             `json:"id"`
-	SequenceID      int                   `json:"sequenceId"`
-	Title           string                `json:"title"`
-	EstimateLabel   *string               `json:"estimateLabel"`
-	EstimateValue   *int16                `json:"estimateValue"`
-	EstimateScheme  string                `json:"estimateScheme"`
-	TeamCode        string                `json:"teamCode"`
-	Description     *string               `json:"description"`
-	DescriptionHTML *string               `json:"descriptionHTML"`
-	Parent          *uuid.UUID            `json:"parentId"`
-	Status          *uuid.UUID            `json:"statusId"`
-	AssigneeID      *uuid.UUID            `json:"assigneeId"`
-	Assignee        *AppUserSummary       `json:"assignee"`
-	BlockedBy       *uuid.UUID            `json:"blockedById"`
-	Blocking        *uuid.UUID            `json:"blockingId"`
-	Related         *uuid.UUID            `json:"relatedId"`
-	ReporterID      *uuid.UUID            `json:"reporterId"`
-	Reporter        *AppUserSummary       `json:"reporter"`
-	Priority        string                `json:"priority"`
-	Sprint          *uuid.UUID            `json:"sprintId"`
-	Epic            *uuid.UUID            `json:"epicId"`
-	Objective       *uuid.UUID            `json:"objectiveId"`
-	KeyResult       *uuid.UUID            `json:"keyResultId"`
-	Team            uuid.UUID             `json:"teamId"`
-	Workspace       uuid.UUID             `json:"workspaceId"`
-	StartDate       *time.Time            `json:"startDate"`
-	EndDate         *time.Time            `json:"endDate"`
-	CreatedAt       time.Time             `json:"createdAt"`
-	UpdatedAt       time.Time             `json:"updatedAt"`
-	DeletedAt       *time.Time            `json:"deletedAt"`
-	ArchivedAt      *time.Time            `json:"archivedAt"`
-	CompletedAt     *time.Time            `json:"completedAt"`
-	SubStories      []AppStoryList        `json:"subStories"`
-	Labels          []uuid.UUID           `json:"labels"`
-	Associations    []AppStoryAssociation `json:"associations"`
+	ID              uuid.UUID                     `json:"id"`
+	SequenceID      int                           `json:"sequenceId"`
+	Title           string                        `json:"title"`
+	EstimateLabel   *string                       `json:"estimateLabel"`
+	EstimateValue   *int16                        `json:"estimateValue"`
+	EstimateScheme  string                        `json:"estimateScheme"`
+	TeamCode        string                        `json:"teamCode"`
+	Description     *string                       `json:"description"`
+	DescriptionHTML *string                       `json:"descriptionHTML"`
+	Parent          *uuid.UUID                    `json:"parentId"`
+	Status          *uuid.UUID                    `json:"statusId"`
+	AssigneeID      *uuid.UUID                    `json:"assigneeId"`
+	Assignee        *AppUserSummary               `json:"assignee"`
+	BlockedBy       *uuid.UUID                    `json:"blockedById"`
+	Blocking        *uuid.UUID                    `json:"blockingId"`
+	Related         *uuid.UUID                    `json:"relatedId"`
+	ReporterID      *uuid.UUID                    `json:"reporterId"`
+	Reporter        *AppUserSummary               `json:"reporter"`
+	Priority        string                        `json:"priority"`
+	Sprint          *uuid.UUID                    `json:"sprintId"`
+	Epic            *uuid.UUID                    `json:"epicId"`
+	Objective       *uuid.UUID                    `json:"objectiveId"`
+	KeyResult       *uuid.UUID                    `json:"keyResultId"`
+	Team            uuid.UUID                     `json:"teamId"`
+	Workspace       uuid.UUID                     `json:"workspaceId"`
+	StartDate       *time.Time                    `json:"startDate"`
+	EndDate         *time.Time                    `json:"endDate"`
+	CreatedAt       time.Time                     `json:"createdAt"`
+	UpdatedAt       time.Time                     `json:"updatedAt"`
+	DeletedAt       *time.Time                    `json:"deletedAt"`
+	ArchivedAt      *time.Time                    `json:"archivedAt"`
+	CompletedAt     *time.Time                    `json:"completedAt"`
+	SubStories      []AppStoryList                `json:"subStories"`
+	Labels          []uuid.UUID                   `json:"labels"`
+	Associations    []AppStoryAssociation         `json:"associations"`
+	CustomFields    map[uuid.UUID]json.RawMessage `json:"customFields"`
+	Watchers        []AppUserSummary              `json:"watchers"`
 }
 
 type AppStoryAssociation struct {
@@ -232,6 +235,7 @@ type AppStoryList struct {
 	EstimateScheme   string               `json:"estimateScheme"`
 	Objective        *uuid.UUID           `json:"objectiveId"`
 	ObjectiveSummary *AppObjectiveSummary `json:"objective"`
+	Epic             *uuid.UUID           `json:"epicId"`
 	Status           *uuid.UUID           `json:"statusId"`
 	AssigneeID       *uuid.UUID           `json:"assigneeId"`
 	Assignee         *AppUserSummary      `json:"assignee"`
@@ -253,6 +257,14 @@ type AppStoryList struct {
 	ArchivedAt       *time.Time           `json:"archivedAt"`
 	Labels           []uuid.UUID          `json:"labels"`
 	SubStories       []AppStoryList       `json:"subStories"`
+	// Checklist is set on stories with checklist items.
+	Checklist *AppChecklistProgress `json:"checklist,omitempty"`
+}
+
+// AppChecklistProgress counts a story's checklist items
+type AppChecklistProgress struct {
+	Total int `json:"total"`
+	Done  int `json:"done"`
 }
 
 func toAppUserSummary(user users.CoreUser) AppUserSummary {
@@ -303,9 +315,21 @@ func toAppStory(i stories.CoreSingleStory, usersByID map[uuid.UUID]AppUserSummar
 		SubStories:      toAppStories(i.SubStories, usersByID),
 		Labels:          i.Labels,
 		Associations:    toAppStoryAssociations(i.Associations, usersByID),
+		CustomFields:    i.CustomFields,
+		Watchers:        toAppWatcherSummaries(i.Watchers, usersByID),
 	}
 }
 
+func toAppWatcherSummaries(watcherIDs []uuid.UUID, usersByID map[uuid.UUID]AppUserSummary) []AppUserSummary {
+	watchers := make([]AppUserSummary, 0, len(watcherIDs))
+	for _, id := range watcherIDs {
+		if user, ok := usersByID[id]; ok {
+			watchers = append(watchers, user)
+		}
+	}
+	return watchers
+}
+
 func toAppStoryAssociations(associations []stories.CoreStoryAssociation, usersByID map[uuid.UUID]AppUserSummary) []AppStoryAssociation {
 	appAssociations := make([]AppStoryAssociation, len(associations))
 	for i, association := range associations {
@@ -338,6 +362,7 @@ func toAppStoryListItem(story stories.CoreStoryList, usersByID map[uuid.UUID]App
 		EstimateValue:    story.EstimateValue,
 		EstimateScheme:   story.EstimateScheme,
 		Objective:        story.Objective,
+		Epic:             story.Epic,
 		ObjectiveSummary: toAppObjectiveSummary(story.ObjectiveSummary),
 		Team:             story.Team,
 		TeamSummary:      toAppTeamSummary(story.TeamSummary),
@@ -360,7 +385,15 @@ func toAppStoryListItem(story stories.CoreStoryList, usersByID map[uuid.UUID]App
 		ArchivedAt:       story.ArchivedAt,
 		Labels:           story.Labels,
 		SubStories:       toAppStories(story.SubStories, usersByID),
+		Checklist:        toAppChecklistProgress(story.Checklist),
+	}
+}
+
+func toAppChecklistProgress(progress *stories.CoreChecklistProgress) *AppChecklistProgress {
+	if progress == nil {
+		return nil
 	}
+	return &AppChecklistProgress{Total: progress.Total, Done: progress.Done}
 }
 
 func toAppTeamSummary(team *stories.CoreTeamSummary) *AppTeamSummary {
@@ -423,6 +456,7 @@ type AppUpdateStory struct {
 	DescriptionHTML string     `json:"descriptionHTML" db:"description_html"`
 	Parent          uuid.UUID  `json:"parentId" db:"parent_id"`
 	Objective       uuid.UUID  `json:"objectiveId" db:"objective_id"`
+	Epic            *uuid.UUID `json:"epicId" db:"epic_id"`
 	Status          uuid.UUID  `json:"statusId" db:"status_id"`
 	Assignee        *uuid.UUID `json:"assigneeId" db:"assignee_id"`
 	Priority        string     `json:"priority" db:"priority" validate:"omitempty,oneof='No Priority' Low Medium High Urgent"`
@@ -430,24 +464,28 @@ type AppUpdateStory struct {
 	KeyResult       uuid.UUID  `json:"keyResultId" db:"key_result_id"`
 	StartDate       *date.Date `json:"startDate" db:"start_date"`
 	EndDate         *date.Date `json:"endDate" db:"end_date"`
+	// CustomFields maps a custom field ID to its new value; null clears a value.
+	CustomFields map[uuid.UUID]json.RawMessage `json:"customFields" db:"custom_fields"`
 }
 
 type AppNewStory struct {
-	Title           string      `json:"title" validate:"required"`
-	EstimateValue   *int16      `json:"estimateValue"`
-	Description     *string     `json:"description"`
-	DescriptionHTML *string     `json:"descriptionHTML"`
-	Parent          *uuid.UUID  `json:"parentId"`
-	Objective       *uuid.UUID  `json:"objectiveId"`
-	Status          *uuid.UUID  `json:"statusId"`
-	Assignee        *uuid.UUID  `json:"assigneeId"`
-	Priority        string      `json:"priority" validate:"oneof='No Priority' Low Medium High Urgent"`
-	Sprint          *uuid.UUID  `json:"sprintId"`
-	KeyResult       *uuid.UUID  `json:"keyResultId"`
-	LabelIDs        []uuid.UUID `json:"labelIds"`
-	Team            uuid.UUID   `json:"teamId" validate:"required"`
-	StartDate       *date.Date  `json:"startDate"`
-	EndDate         *date.Date  `json:"endDate"`
+	Title           string                        `json:"title" validate:"required"`
+	EstimateValue   *int16                        `json:"estimateValue"`
+	Description     *string                       `json:"description"`
+	DescriptionHTML *string                       `json:"descriptionHTML"`
+	Parent          *uuid.UUID                    `json:"parentId"`
+	Objective       *uuid.UUID                    `json:"objectiveId"`
+	Epic            *uuid.UUID                    `json:"epicId"`
+	Status          *uuid.UUID                    `json:"statusId"`
+	Assignee        *uuid.UUID                    `json:"assigneeId"`
+	Priority        string                        `json:"priority" validate:"oneof='No Priority' Low Medium High Urgent"`
+	Sprint          *uuid.UUID                    `json:"sprintId"`
+	KeyResult       *uuid.UUID                    `json:"keyResultId"`
+	LabelIDs        []uuid.UUID                   `json:"labelIds"`
+	Team            uuid.UUID                     `json:"teamId" validate:"required"`
+	StartDate       *date.Date                    `json:"startDate"`
+	EndDate         *date.Date                    `json:"endDate"`
+	CustomFields    map[uuid.UUID]json.RawMessage `json:"customFields"`
 }
 
 type AppNewComment struct {
@@ -465,7 +503,28 @@ type AppComment struct {
 	Comment     string         `json:"comment"`
 	CreatedAt   time.Time      `json:"createdAt"`
 	UpdatedAt   time.Time      `json:"updatedAt"`
+	Edited      bool           `json:"edited"`
+	EditedAt    *time.Time     `json:"editedAt"`
+	EditedByID  *uuid.UUID     `json:"editedById"`
+	Resolved    bool           `json:"resolved"`
+	ResolvedAt  *time.Time     `json:"resolvedAt"`
+	ResolvedBy  *uuid.UUID     `json:"resolvedById"`
+	ReplyCount  int            `json:"replyCount"`
+	Collapsed   bool           `json:"collapsed"`
 	SubComments []AppComment   `json:"subComments"`
+	Reactions   []AppReaction  `json:"reactions"`
+}
+
+// AppReaction is an emoji on a comment with the users who reacted with it.
+type AppReaction struct {
+	Emoji   string           `json:"emoji"`
+	Count   int              `json:"count"`
+	UserIDs []uuid.UUID      `json:"userIds"`
+	Users   []AppUserSummary `json:"users"`
+}
+
+type AppToggleReaction struct {
+	Emoji string `json:"emoji" validate:"required"`
 }
 
 type AppNewAssociation struct {
@@ -481,25 +540,29 @@ type AppUpdateAssociation struct {
 
 // StoryFilters represents filtering options for stories at the handler level
 type StoryFilters struct {
-	StatusIDs      []uuid.UUID `json:"statusIds"`
-	AssigneeIDs    []uuid.UUID `json:"assigneeIds"`
-	ReporterIDs    []uuid.UUID `json:"reporterIds"`
-	TitleContains  *string     `json:"titleContains"`
-	Priorities     []string    `json:"priorities"`
-	Categories     []string    `json:"categories"`
-	TeamIDs        []uuid.UUID `json:"teamIds"`
-	SprintIDs      []uuid.UUID `json:"sprintIds"`
-	LabelIDs       []uuid.UUID `json:"labelIds"`
-	EstimateValues []int16     `json:"estimateValues"`
-	Parent         *uuid.UUID  `json:"parentId"`
-	Objective      *uuid.UUID  `json:"objectiveId"`
-	Epic           *uuid.UUID  `json:"epicId"`
-	KeyResult      *uuid.UUID  `json:"keyResultId"`
-	HasNoAssignee  *bool       `json:"hasNoAssignee"`
-	HasBlockedBy   *bool       `json:"hasBlockedBy"`
-	AssignedToMe   *bool       `json:"assignedToMe"`
-	CreatedByMe    *bool       `json:"createdByMe"`
-	ShowSubStories *bool       `json:"showSubStories"`
+	StatusIDs        []uuid.UUID `json:"statusIds"`
+	AssigneeIDs      []uuid.UUID `json:"assigneeIds"`
+	ReporterIDs      []uuid.UUID `json:"reporterIds"`
+	TitleContains    *string     `json:"titleContains"`
+	Priorities       []string    `json:"priorities"`
+	Categories       []string    `json:"categories"`
+	TeamIDs          []uuid.UUID `json:"teamIds"`
+	SprintIDs        []uuid.UUID `json:"sprintIds"`
+	LabelIDs         []uuid.UUID `json:"labelIds"`
+	EstimateValues   []int16     `json:"estimateValues"`
+	Parent           *uuid.UUID  `json:"parentId"`
+	Objective        *uuid.UUID  `json:"objectiveId"`
+	Epic             *uuid.UUID  `json:"epicId"`
+	KeyResult        *uuid.UUID  `json:"keyResultId"`
+	HasNoAssignee    *bool       `json:"hasNoAssignee"`
+	HasNoSprint      *bool       `json:"hasNoSprint"`
+	HasNoEpic        *bool       `json:"hasNoEpic"`
+	HasBlockedBy     *bool       `json:"hasBlockedBy"`
+	SLABreached      *bool       `json:"slaBreached"`
+	AssociationTypes []string    `json:"associationTypes"`
+	AssignedToMe     *bool       `json:"assignedToMe"`
+	CreatedByMe      *bool       `json:"createdByMe"`
+	ShowSubStories   *bool       `json:"showSubStories"`
 	// Date range filters
 	CreatedAfter    *time.Time `json:"createdAfter"`
 	CreatedBefore   *time.Time `json:"createdBefore"`
@@ -513,6 +576,10 @@ type StoryFilters struct {
 	CompletedBefore *time.Time `json:"completedBefore"`
 	IncludeArchived *bool      `json:"includeArchived"`
 	IncludeDeleted  *bool      `json:"includeDeleted"`
+	// Custom field filters
+	CustomFields []stories.CoreCustomFieldFilter `json:"customFields"`
+	// Exclusions, set by query expressions
+	Exclude *stories.CoreStoryExclusions `json:"exclude,omitempty"`
 }
 
 // StoryQuery represents query parameters for grouped stories at the handler level
@@ -535,6 +602,17 @@ type StoryGroup struct {
 	HasMore     bool           `json:"hasMore"`
 	Stories     []AppStoryList `json:"stories"`
 	NextPage    int            `json:"nextPage"`
+	// WIP is set on status groups whose status has a WIP limit.
+	WIP *AppStoryGroupWIP `json:"wip,omitempty"`
+}
+
+// AppStoryGroupWIP represents the WIP limit of a status group
+type AppStoryGroupWIP struct {
+	Limit              int         `json:"limit"`
+	PerAssignee        bool        `json:"perAssignee"`
+	Count              int         `json:"count"`
+	Breached           bool        `json:"breached"`
+	OverLimitAssignees []uuid.UUID `json:"overLimitAssignees"`
 }
 
 // GroupsMeta represents metadata for grouped stories response
@@ -553,6 +631,56 @@ type StoriesResponse struct {
 	Meta    GroupsMeta     `json:"meta"`
 }
 
+// AppWIPBreach represents a WIP limit that an update went over
+type AppWIPBreach struct {
+	StatusID    uuid.UUID  `json:"statusId"`
+	StatusName  string     `json:"statusName"`
+	AssigneeID  *uuid.UUID `json:"assigneeId,omitempty"`
+	Limit       int        `json:"limit"`
+	Count       int        `json:"count"`
+	Enforcement string     `json:"enforcement"`
+	Message     string     `json:"message"`
+}
+
+// AppStoryUpdateResult represents the response to an update that went over
+// WIP limits which only warn
+type AppStoryUpdateResult struct {
+	WIPWarnings []AppWIPBreach `json:"wipWarnings"`
+}
+
+func toAppStoryGroupWIP(wip *stories.CoreStoryGroupWIP) *AppStoryGroupWIP {
+	if wip == nil {
+		return nil
+	}
+	over := wip.OverLimitAssignees
+	if over == nil {
+		over = []uuid.UUID{}
+	}
+	return &AppStoryGroupWIP{
+		Limit:              wip.Limit,
+		PerAssignee:        wip.PerAssignee,
+		Count:              wip.Count,
+		Breached:           wip.Breached,
+		OverLimitAssignees: over,
+	}
+}
+
+func toAppWIPBreaches(breaches []stories.CoreWIPBreach) []AppWIPBreach {
+	appBreaches := make([]AppWIPBreach, len(breaches))
+	for i, breach := range breaches {
+		appBreaches[i] = AppWIPBreach{
+			StatusID:    breach.StatusID,
+			StatusName:  breach.StatusName,
+			AssigneeID:  breach.AssigneeID,
+			Limit:       breach.Limit,
+			Count:       breach.Count,
+			Enforcement: breach.Enforcement,
+			Message:     breach.Message(),
+		}
+	}
+	return appBreaches
+}
+
 // GroupPagination represents pagination info for a specific group
 type GroupPagination struct {
 	Page     int  `json:"page"`
@@ -617,8 +745,35 @@ func toAppComment(i comments.CoreComment, usersByID map[uuid.UUID]AppUserSummary
 		Comment:     i.Comment,
 		CreatedAt:   i.CreatedAt,
 		UpdatedAt:   i.UpdatedAt,
+		Edited:      i.IsEdited(),
+		EditedAt:    i.EditedAt,
+		EditedByID:  i.EditedBy,
+		Resolved:    i.IsResolved(),
+		ResolvedAt:  i.ResolvedAt,
+		ResolvedBy:  i.ResolvedBy,
+		ReplyCount:  len(i.SubComments),
 		SubComments: toAppComments(i.SubComments, usersByID),
+		Reactions:   toAppReactions(i.Reactions, usersByID),
+	}
+}
+
+func toAppReactions(reactions []comments.CoreReaction, usersByID map[uuid.UUID]AppUserSummary) []AppReaction {
+	appReactions := make([]AppReaction, len(reactions))
+	for i, reaction := range reactions {
+		users := make([]AppUserSummary, 0, len(reaction.UserIDs))
+		for _, userID := range reaction.UserIDs {
+			if user, ok := usersByID[userID]; ok {
+				users = append(users, user)
+			}
+		}
+		appReactions[i] = AppReaction{
+			Emoji:   reaction.Emoji,
+			Count:   reaction.Count,
+			UserIDs: reaction.UserIDs,
+			Users:   users,
+		}
 	}
+	return appReactions
 }
 
 func toAppComments(i []comments.CoreComment, usersByID map[uuid.UUID]AppUserSummary) []AppComment {
@@ -637,6 +792,7 @@ func toCoreNewStory(a AppNewStory, userID uuid.UUID) stories.CoreNewStory {
 		DescriptionHTML: a.DescriptionHTML,
 		Parent:          a.Parent,
 		Objective:       a.Objective,
+		Epic:            a.Epic,
 		Status:          a.Status,
 		Assignee:        a.Assignee,
 		Reporter:        &userID,
@@ -647,6 +803,7 @@ func toCoreNewStory(a AppNewStory, userID uuid.UUID) stories.CoreNewStory {
 		StartDate:       a.StartDate.TimePtr(),
 		EndDate:         a.EndDate.TimePtr(),
 		Team:            a.Team,
+		CustomFields:    a.CustomFields,
 	}
 }
 
@@ -118,3 +118,38 @@ func TestToAppStoryListItemIncludesEmbeddedSummaries(t *testing.T) {
 		t.Fatalf("expected sprint goal %q, got %#v", goal, appStory.SprintSummary.Goal)
 	}
 }
+
+func TestParseStoryQueryMapsCustomFieldFilters(t *testing.T) {
+	severityID := uuid.New()
+	customerID := uuid.New()
+	request := httptest.NewRequest(
+		"GET",
+		"/stories?groupBy=custom_field:"+severityID.String()+
+			"&customField="+severityID.String()+":sev1,sev2"+
+			"&customField="+customerID.String()+":none",
+		nil,
+	)
+
+	query, err := parseStoryQuery(request, uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("expected query to parse, got error: %v", err)
+	}
+
+	filters := query.Filters.CustomFields
+	if len(filters) != 2 {
+		t.Fatalf("expected 2 custom field filters, got %d", len(filters))
+	}
+	if filters[0].FieldID != severityID || len(filters[0].Values) != 2 || filters[0].IsEmpty {
+		t.Fatalf("unexpected severity filter: %+v", filters[0])
+	}
+	if filters[1].FieldID != customerID || !filters[1].IsEmpty {
+		t.Fatalf("expected customer filter to match empty values, got %+v", filters[1])
+	}
+
+	if _, err := parseStoryQuery(httptest.NewRequest("GET", "/stories?customField=severity:sev1", nil), uuid.New(), uuid.New()); err == nil {
+		t.Fatal("expected a custom field filter without a field id to be rejected")
+	}
+	if _, err := parseStoryQuery(httptest.NewRequest("GET", "/stories?groupBy=custom_field:severity", nil), uuid.New(), uuid.New()); err == nil {
+		t.Fatal("expected grouping by an invalid custom field id to be rejected")
+	}
+}
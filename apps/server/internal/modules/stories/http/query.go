@@ -0,0 +1,67 @@
+package storieshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// ErrViewWithQuery is returned when a request passes both viewId and q.
+var ErrViewWithQuery = errors.New("viewId and q cannot be used together")
+
+// resolveExpressionQuery reads a grouped request whose filters come from the
+// q expression. Grouping, ordering, paging and showSubStories are still read
+// from the request.
+func (h *Handlers) resolveExpressionQuery(ctx context.Context, r *http.Request, userID, workspaceID uuid.UUID, expression string) (StoryQuery, *stories.CoreStoryFilters, error) {
+	query, err := parseStoryQuery(r, userID, workspaceID)
+	if err != nil {
+		return StoryQuery{}, nil, err
+	}
+
+	filters, err := h.stories.CompileQuery(ctx, workspaceID, userID, expression)
+	if err != nil {
+		return StoryQuery{}, nil, err
+	}
+	filters.ShowSubStories = query.Filters.ShowSubStories
+
+	query.Filters = toStoryFilters(filters)
+	return query, &filters, nil
+}
+
+// listQuery answers GET /stories?q= with the flat list of stories matching
+// the query expression.
+func (h *Handlers) listQuery(ctx context.Context, w http.ResponseWriter, r *http.Request, workspaceID uuid.UUID, expression string) error {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	filters, err := h.stories.CompileQuery(ctx, workspaceID, userID, expression)
+	if err != nil {
+		web.RespondError(ctx, w, err, queryExpressionStatus(err))
+		return nil
+	}
+	filters.ShowSubStories = parseBoolParam(r, "showSubStories")
+	filters.CurrentUserID = userID
+	filters.WorkspaceID = workspaceID
+
+	storyList, err := h.stories.List(ctx, workspaceID, coreFiltersToMap(filters))
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		return nil
+	}
+	return h.respondStories(ctx, w, storyList, http.StatusOK)
+}
+
+func queryExpressionStatus(err error) int {
+	if errors.Is(err, stories.ErrInvalidQueryExpression) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
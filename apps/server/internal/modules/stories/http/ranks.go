@@ -0,0 +1,79 @@
+package storieshttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/cache"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// AppMoveStoryRequest places a story between two others in rank order.
+// BeforeID is the story that should come directly before it and AfterID
+// the one directly after it. Either may be left out at the ends of a list.
+type AppMoveStoryRequest struct {
+	BeforeID *uuid.UUID `json:"beforeId"`
+	AfterID  *uuid.UUID `json:"afterId"`
+}
+
+// AppStoryRank is a story's rank after a move.
+type AppStoryRank struct {
+	ID   uuid.UUID `json:"id"`
+	Rank string    `json:"rank"`
+}
+
+// MoveStory changes a story's place in the manual order.
+func (h *Handlers) MoveStory(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.MoveStory")
+	defer span.End()
+
+	storyID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	var req AppMoveStoryRequest
+	if err := web.Decode(r, &req); err != nil {
+		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		return nil
+	}
+
+	rank, err := h.stories.MoveStory(ctx, storyID, workspace.ID, req.BeforeID, req.AfterID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, stories.ErrNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, stories.ErrRankNeighboursRequired):
+			status = http.StatusBadRequest
+		case errors.Is(err, stories.ErrInvalidRankNeighbours), errors.Is(err, stories.ErrRanksRebalancing):
+			status = http.StatusConflict
+		}
+		web.RespondError(ctx, w, err, status)
+		return nil
+	}
+
+	for _, key := range cache.InvalidateStoryKeys(workspace.ID, storyID) {
+		if strings.Contains(key, "*") {
+			h.cache.DeleteByPattern(ctx, key)
+		} else {
+			h.cache.Delete(ctx, key)
+		}
+	}
+	h.cache.DeleteByPattern(ctx, fmt.Sprintf(cache.MyStoriesKey+"*", workspace.ID.String()))
+
+	return web.Respond(ctx, w, AppStoryRank{ID: storyID, Rank: rank}, http.StatusOK)
+}
@@ -0,0 +1,73 @@
+package storieshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCommentID is returned when the comment id parameter is not a UUID.
+var ErrInvalidCommentID = errors.New("comment id is not in its proper form")
+
+// ToggleReaction adds or removes the current user's emoji reaction on a
+// comment and returns the comment's reactions.
+func (h *Handlers) ToggleReaction(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.ToggleReaction")
+	defer span.End()
+
+	commentID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidCommentID, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	var req AppToggleReaction
+	if err := web.Decode(r, &req); err != nil {
+		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		return nil
+	}
+
+	reactions, err := h.stories.ToggleReaction(ctx, workspace.ID, commentID, userID, req.Emoji)
+	if err != nil {
+		web.RespondError(ctx, w, err, reactionStatus(err))
+		return nil
+	}
+
+	userIDs := make(map[uuid.UUID]struct{})
+	collectReactionUserIDs(reactions, userIDs)
+	usersByID, err := h.getStoryUsers(ctx, mapUserIDs(userIDs))
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusInternalServerError)
+		return nil
+	}
+
+	return web.Respond(ctx, w, toAppReactions(reactions, usersByID), http.StatusOK)
+}
+
+func reactionStatus(err error) int {
+	switch {
+	case errors.Is(err, stories.ErrInvalidReaction):
+		return http.StatusBadRequest
+	case errors.Is(err, stories.ErrCommentNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -4,6 +4,7 @@ import (
 	attachments "github.com/complexus-tech/projects-api/internal/modules/attachments/service"
 	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
 	links "github.com/complexus-tech/projects-api/internal/modules/links/service"
+	savedviews "github.com/complexus-tech/projects-api/internal/modules/savedviews/service"
 	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
 	users "github.com/complexus-tech/projects-api/internal/modules/users/service"
 	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
@@ -30,6 +31,7 @@ type Config struct {
 	Comments       *comments.Service
 	Links          *links.Service
 	Attachments    *attachments.Service
+	SavedViews     *savedviews.Service
 }
 
 func Routes(cfg Config, app *web.App) {
@@ -41,8 +43,9 @@ func Routes(cfg Config, app *web.App) {
 	auth := mid.Auth(cfg.Log, cfg.SecretKey)
 	gzip := mid.Gzip(cfg.Log)
 	workspace := mid.Workspace(cfg.Log, cfg.DB, cfg.Cache)
+	adminOnly := mid.RequireMinimumRole(cfg.Log, mid.RoleAdmin)
 
-	h := New(storiesService, cfg.Users, commentsService, linksService, attachmentsService, cfg.Cache, cfg.Log)
+	h := New(storiesService, cfg.Users, commentsService, linksService, attachmentsService, cfg.SavedViews, cfg.Cache, cfg.Log)
 
 	// Stories
 	app.Get("/workspaces/{workspaceSlug}/stories", h.List, auth, workspace, gzip)
@@ -53,7 +56,11 @@ func Routes(cfg Config, app *web.App) {
 	app.Get("/workspaces/{workspaceSlug}/story-by-ref/{ref}", h.QueryByRef, auth, workspace, gzip)
 	app.Post("/workspaces/{workspaceSlug}/stories", h.Create, auth, workspace)
 	app.Put("/workspaces/{workspaceSlug}/stories/{id}", h.Update, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/stories/{id}/move", h.MoveStory, auth, workspace)
 	app.Put("/workspaces/{workspaceSlug}/stories", h.BulkUpdate, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/stories/move-team", h.BulkMoveToTeam, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/stories/{id}/move-team", h.MoveToTeam, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/stories/{id}/merge", h.MergeStory, auth, workspace)
 	app.Delete("/workspaces/{workspaceSlug}/stories/{id}", h.Delete, auth, workspace)
 	app.Post("/workspaces/{workspaceSlug}/stories/{id}/restore", h.Restore, auth, workspace)
 	app.Post("/workspaces/{workspaceSlug}/stories/restore", h.BulkRestore, auth, workspace)
@@ -61,16 +68,36 @@ func Routes(cfg Config, app *web.App) {
 	app.Post("/workspaces/{workspaceSlug}/stories/unarchive", h.BulkUnarchive, auth, workspace)
 	app.Delete("/workspaces/{workspaceSlug}/stories", h.BulkDelete, auth, workspace)
 	app.Get("/workspaces/{workspaceSlug}/stories/{id}/activities", h.GetActivities, auth, workspace, gzip)
+	app.Get("/workspaces/{workspaceSlug}/stories/{id}/sla", h.GetStorySLA, auth, workspace)
 	app.Post("/workspaces/{workspaceSlug}/stories/{id}/duplicate", h.DuplicateStory, auth, workspace)
 	app.Get("/workspaces/{workspaceSlug}/stories/count", h.CountInWorkspace, auth, workspace)
 
 	// Comments
 	app.Post("/workspaces/{workspaceSlug}/stories/{id}/comments", h.CreateComment, auth, workspace)
 	app.Get("/workspaces/{workspaceSlug}/stories/{id}/comments", h.GetComments, auth, workspace, gzip)
+	app.Post("/workspaces/{workspaceSlug}/comments/{id}/reactions", h.ToggleReaction, auth, workspace)
 	app.Put("/workspaces/{workspaceSlug}/stories/{id}/labels", h.UpdateLabels, auth, workspace)
 	app.Get("/workspaces/{workspaceSlug}/stories/{id}/links", h.GetStoryLinks, auth, workspace, gzip)
 	app.Get("/workspaces/{workspaceSlug}/my-stories", h.MyStories, auth, workspace, gzip)
 
+	// Description revisions
+	app.Get("/workspaces/{workspaceSlug}/stories/{id}/description-revisions", h.ListDescriptionRevisions, auth, workspace)
+	app.Get("/workspaces/{workspaceSlug}/stories/{id}/description-revisions/diff", h.DiffDescriptionRevisions, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/stories/{id}/description-revisions/{revisionId}/restore", h.RestoreDescriptionRevision, auth, workspace)
+
+	// Watchers
+	app.Get("/workspaces/{workspaceSlug}/stories/{id}/watchers", h.ListWatchers, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/stories/{id}/watchers", h.Watch, auth, workspace)
+	app.Delete("/workspaces/{workspaceSlug}/stories/{id}/watchers", h.Unwatch, auth, workspace)
+
+	// Checklists
+	app.Get("/workspaces/{workspaceSlug}/stories/{id}/checklist", h.ListChecklistItems, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/stories/{id}/checklist", h.AddChecklistItem, auth, workspace)
+	app.Put("/workspaces/{workspaceSlug}/stories/{id}/checklist/order", h.ReorderChecklistItems, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/stories/{id}/checklist/{itemId}/toggle", h.ToggleChecklistItem, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/stories/{id}/checklist/{itemId}/convert", h.ConvertChecklistItem, auth, workspace)
+	app.Delete("/workspaces/{workspaceSlug}/stories/{id}/checklist/{itemId}", h.DeleteChecklistItem, auth, workspace)
+
 	// Attachments
 	app.Post("/workspaces/{workspaceSlug}/stories/{id}/attachments", h.UploadStoryAttachment, auth, workspace)
 	app.Get("/workspaces/{workspaceSlug}/stories/{id}/attachments", h.GetAttachmentsForStory, auth, workspace)
@@ -80,4 +107,10 @@ func Routes(cfg Config, app *web.App) {
 	app.Post("/workspaces/{workspaceSlug}/stories/{id}/associations", h.AddAssociation, auth, workspace)
 	app.Put("/workspaces/{workspaceSlug}/stories/{id}/associations/{associationId}", h.UpdateAssociation, auth, workspace)
 	app.Delete("/workspaces/{workspaceSlug}/stories/associations/{associationId}", h.RemoveAssociation, auth, workspace)
+	app.Get("/workspaces/{workspaceSlug}/association-types", h.ListAssociationTypes, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/association-types", h.CreateAssociationType, auth, workspace, adminOnly)
+	app.Put("/workspaces/{workspaceSlug}/association-types/{key}", h.UpdateAssociationType, auth, workspace, adminOnly)
+	app.Delete("/workspaces/{workspaceSlug}/association-types/{key}", h.DeleteAssociationType, auth, workspace, adminOnly)
+	app.Get("/workspaces/{workspaceSlug}/stories/dependency-graph", h.GetDependencyGraph, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/stories/{id}/schedule-preview", h.PreviewSchedule, auth, workspace)
 }
@@ -0,0 +1,144 @@
+package storieshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	savedviews "github.com/complexus-tech/projects-api/internal/modules/savedviews/service"
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidViewID is returned when the viewId parameter is not a UUID.
+var ErrInvalidViewID = errors.New("view id is not in its proper form")
+
+// resolveStoryQuery reads the query of a grouped request. With viewId the
+// saved view's filters, grouping and ordering are used, and with q the
+// filters come from the query expression; either way the filter parameters
+// are ignored and paging parameters come from the request. The returned
+// filters, when not nil, replace those of the query because they can hold
+// filters that StoryQuery does not carry.
+func (h *Handlers) resolveStoryQuery(ctx context.Context, r *http.Request, userID, workspaceID uuid.UUID) (StoryQuery, *stories.CoreStoryFilters, error) {
+	viewIDParam := r.URL.Query().Get("viewId")
+	expression := r.URL.Query().Get("q")
+	switch {
+	case viewIDParam != "" && expression != "":
+		return StoryQuery{}, nil, ErrViewWithQuery
+	case expression != "":
+		return h.resolveExpressionQuery(ctx, r, userID, workspaceID, expression)
+	case viewIDParam == "":
+		query, err := parseStoryQuery(r, userID, workspaceID)
+		return query, nil, err
+	}
+
+	viewID, err := uuid.Parse(viewIDParam)
+	if err != nil {
+		return StoryQuery{}, nil, ErrInvalidViewID
+	}
+	viewQuery, err := h.savedViews.Query(ctx, viewID, workspaceID, userID)
+	if err != nil {
+		return StoryQuery{}, nil, err
+	}
+
+	query := toStoryQuery(viewQuery)
+	query.StoriesPerGroup = getIntParam(r, "storiesPerGroup", viewQuery.StoriesPerGroup)
+	query.GroupKey = r.URL.Query().Get("groupKey")
+	query.Page = getIntParam(r, "page", 1)
+	query.PageSize = getIntParam(r, "pageSize", 0)
+	return query, &viewQuery.Filters, nil
+}
+
+// listSavedView answers GET /stories?viewId= with the flat list of stories
+// matching the view's filters.
+func (h *Handlers) listSavedView(ctx context.Context, w http.ResponseWriter, workspaceID uuid.UUID, viewIDParam string) error {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	viewID, err := uuid.Parse(viewIDParam)
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidViewID, http.StatusBadRequest)
+		return nil
+	}
+
+	viewQuery, err := h.savedViews.Query(ctx, viewID, workspaceID, userID)
+	if err != nil {
+		web.RespondError(ctx, w, err, storyQueryStatus(err))
+		return nil
+	}
+
+	storyList, err := h.stories.List(ctx, workspaceID, coreFiltersToMap(viewQuery.Filters))
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		return nil
+	}
+	return h.respondStories(ctx, w, storyList, http.StatusOK)
+}
+
+// storyQueryStatus maps errors from resolving a request's story query.
+func storyQueryStatus(err error) int {
+	if errors.Is(err, savedviews.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+func toStoryQuery(query stories.CoreStoryQuery) StoryQuery {
+	return StoryQuery{
+		Filters:         toStoryFilters(query.Filters),
+		GroupBy:         query.GroupBy,
+		OrderBy:         query.OrderBy,
+		OrderDirection:  query.OrderDirection,
+		StoriesPerGroup: query.StoriesPerGroup,
+		GroupKey:        query.GroupKey,
+		Page:            query.Page,
+		PageSize:        query.PageSize,
+	}
+}
+
+func toStoryFilters(filters stories.CoreStoryFilters) StoryFilters {
+	return StoryFilters{
+		StatusIDs:        filters.StatusIDs,
+		AssigneeIDs:      filters.AssigneeIDs,
+		ReporterIDs:      filters.ReporterIDs,
+		TitleContains:    filters.TitleContains,
+		Priorities:       filters.Priorities,
+		Categories:       filters.Categories,
+		TeamIDs:          filters.TeamIDs,
+		SprintIDs:        filters.SprintIDs,
+		LabelIDs:         filters.LabelIDs,
+		EstimateValues:   filters.EstimateValues,
+		Parent:           filters.Parent,
+		Objective:        filters.Objective,
+		Epic:             filters.Epic,
+		KeyResult:        filters.KeyResult,
+		HasNoAssignee:    filters.HasNoAssignee,
+		HasNoSprint:      filters.HasNoSprint,
+		HasNoEpic:        filters.HasNoEpic,
+		HasBlockedBy:     filters.HasBlockedBy,
+		SLABreached:      filters.SLABreached,
+		AssociationTypes: filters.AssociationTypes,
+		AssignedToMe:     filters.AssignedToMe,
+		CreatedByMe:      filters.CreatedByMe,
+		ShowSubStories:   filters.ShowSubStories,
+		IncludeArchived:  filters.IncludeArchived,
+		IncludeDeleted:   filters.IncludeDeleted,
+		CreatedAfter:     filters.CreatedAfter,
+		CreatedBefore:    filters.CreatedBefore,
+		UpdatedAfter:     filters.UpdatedAfter,
+		UpdatedBefore:    filters.UpdatedBefore,
+		StartDateAfter:   filters.StartDateAfter,
+		StartDateBefore:  filters.StartDateBefore,
+		DeadlineAfter:    filters.DeadlineAfter,
+		DeadlineBefore:   filters.DeadlineBefore,
+		CompletedAfter:   filters.CompletedAfter,
+		CompletedBefore:  filters.CompletedBefore,
+		CustomFields:     filters.CustomFields,
+		Exclude:          filters.Exclude,
+	}
+}
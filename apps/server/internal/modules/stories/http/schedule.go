@@ -0,0 +1,92 @@
+package storieshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/date"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// AppSchedulePreviewRequest is the end date to preview. Without one the
+// story's current end date is used.
+type AppSchedulePreviewRequest struct {
+	EndDate *date.Date `json:"endDate"`
+}
+
+// AppScheduleChange is a story that would move to start after its blocker.
+type AppScheduleChange struct {
+	StoryID        uuid.UUID  `json:"storyId"`
+	SequenceID     int        `json:"sequenceId"`
+	Title          string     `json:"title"`
+	TeamCode       string     `json:"teamCode"`
+	BlockerID      uuid.UUID  `json:"blockerId"`
+	BlockerEndDate time.Time  `json:"blockerEndDate"`
+	OldStartDate   *time.Time `json:"oldStartDate"`
+	OldEndDate     *time.Time `json:"oldEndDate"`
+	NewStartDate   *time.Time `json:"newStartDate"`
+	NewEndDate     *time.Time `json:"newEndDate"`
+}
+
+// PreviewSchedule returns the stories that would move if the story ended on
+// the requested date.
+func (h *Handlers) PreviewSchedule(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.PreviewSchedule")
+	defer span.End()
+
+	storyID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	var req AppSchedulePreviewRequest
+	if err := web.Decode(r, &req); err != nil {
+		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		return nil
+	}
+
+	var endDate *time.Time
+	if req.EndDate != nil {
+		endDate = req.EndDate.TimePtr()
+	}
+
+	changes, err := h.stories.PreviewSchedule(ctx, storyID, workspace.ID, endDate)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, stories.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		web.RespondError(ctx, w, err, status)
+		return nil
+	}
+
+	appChanges := make([]AppScheduleChange, len(changes))
+	for i, change := range changes {
+		appChanges[i] = AppScheduleChange{
+			StoryID:        change.StoryID,
+			SequenceID:     change.SequenceID,
+			Title:          change.Title,
+			TeamCode:       change.TeamCode,
+			BlockerID:      change.BlockerID,
+			BlockerEndDate: change.BlockerEndDate,
+			OldStartDate:   change.OldStartDate,
+			OldEndDate:     change.OldEndDate,
+			NewStartDate:   change.NewStartDate,
+			NewEndDate:     change.NewEndDate,
+		}
+	}
+
+	return web.Respond(ctx, w, appChanges, http.StatusOK)
+}
@@ -0,0 +1,79 @@
+package storieshttp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// AppSLATarget is where a story stands against one SLA target.
+type AppSLATarget struct {
+	TargetMinutes int        `json:"targetMinutes"`
+	State         string     `json:"state"`
+	AtRiskAt      *time.Time `json:"atRiskAt"`
+	DueAt         *time.Time `json:"dueAt"`
+	MetAt         *time.Time `json:"metAt"`
+	BreachedAt    *time.Time `json:"breachedAt"`
+}
+
+// AppStorySLA is where a story stands against its team's SLA.
+type AppStorySLA struct {
+	Priority      string        `json:"priority"`
+	Paused        bool          `json:"paused"`
+	FirstResponse *AppSLATarget `json:"firstResponse"`
+	Resolve       *AppSLATarget `json:"resolve"`
+}
+
+// GetStorySLA returns where a story stands against its SLA, or null when
+// no policy covers it.
+func (h *Handlers) GetStorySLA(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.GetStorySLA")
+	defer span.End()
+
+	storyID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	sla, err := h.stories.GetStorySLA(ctx, storyID, workspace.ID)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusInternalServerError)
+		return nil
+	}
+	if sla == nil {
+		return web.Respond(ctx, w, nil, http.StatusOK)
+	}
+
+	return web.Respond(ctx, w, AppStorySLA{
+		Priority:      sla.Priority,
+		Paused:        sla.Paused,
+		FirstResponse: toAppSLATarget(sla.FirstResponse),
+		Resolve:       toAppSLATarget(sla.Resolve),
+	}, http.StatusOK)
+}
+
+func toAppSLATarget(target *stories.CoreSLATarget) *AppSLATarget {
+	if target == nil {
+		return nil
+	}
+	return &AppSLATarget{
+		TargetMinutes: target.TargetMinutes,
+		State:         target.State,
+		AtRiskAt:      target.AtRiskAt,
+		DueAt:         target.DueAt,
+		MetAt:         target.MetAt,
+		BreachedAt:    target.BreachedAt,
+	}
+}
@@ -31,6 +31,7 @@ import (
 	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
 	linkshttp "github.com/complexus-tech/projects-api/internal/modules/links/http"
 	links "github.com/complexus-tech/projects-api/internal/modules/links/service"
+	savedviews "github.com/complexus-tech/projects-api/internal/modules/savedviews/service"
 	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
 	users "github.com/complexus-tech/projects-api/internal/modules/users/service"
 	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
@@ -54,18 +55,20 @@ type Handlers struct {
 	comments    *comments.Service
 	links       *links.Service
 	attachments *attachments.Service
+	savedViews  *savedviews.Service
 	cache       *cache.Service
 	log         *logger.Logger
 }
 
 // New creates a new Handlers instance with the required dependencies.
-func New(stories *stories.Service, users *users.Service, comments *comments.Service, links *links.Service, attachments *attachments.Service, cacheService *cache.Service, log *logger.Logger) *Handlers {
+func New(stories *stories.Service, users *users.Service, comments *comments.Service, links *links.Service, attachments *attachments.Service, savedViews *savedviews.Service, cacheService *cache.Service, log *logger.Logger) *Handlers {
 	return &Handlers{
 		stories:     stories,
 		users:       users,
 		comments:    comments,
 		links:       links,
 		attachments: attachments,
+		savedViews:  savedViews,
 		cache:       cacheService,
 		log:         log,
 	}
@@ -136,6 +139,10 @@ func collectStoryUserIDs(story stories.CoreSingleStory, userIDs map[uuid.UUID]st
 	for _, association := range story.Associations {
 		collectStoryListUserIDs(association.Story, userIDs)
 	}
+
+	for _, watcherID := range story.Watchers {
+		userIDs[watcherID] = struct{}{}
+	}
 }
 
 func mapUserIDs(values map[uuid.UUID]struct{}) []uuid.UUID {
@@ -190,12 +197,21 @@ func (h *Handlers) respondStories(ctx context.Context, w http.ResponseWriter, st
 
 func collectCommentUserIDs(comment comments.CoreComment, userIDs map[uuid.UUID]struct{}) {
 	userIDs[comment.UserID] = struct{}{}
+	collectReactionUserIDs(comment.Reactions, userIDs)
 
 	for _, subComment := range comment.SubComments {
 		collectCommentUserIDs(subComment, userIDs)
 	}
 }
 
+func collectReactionUserIDs(reactions []comments.CoreReaction, userIDs map[uuid.UUID]struct{}) {
+	for _, reaction := range reactions {
+		for _, userID := range reaction.UserIDs {
+			userIDs[userID] = struct{}{}
+		}
+	}
+}
+
 func (h *Handlers) buildCommentsUsersByID(ctx context.Context, commentList []comments.CoreComment) (map[uuid.UUID]AppUserSummary, error) {
 	userIDs := make(map[uuid.UUID]struct{})
 	for _, comment := range commentList {
@@ -213,13 +229,21 @@ func (h *Handlers) respondComment(ctx context.Context, w http.ResponseWriter, co
 	return web.Respond(ctx, w, toAppComment(comment, usersByID), statusCode)
 }
 
-func (h *Handlers) respondComments(ctx context.Context, w http.ResponseWriter, commentList []comments.CoreComment, response CommentsResponse, statusCode int) error {
+func (h *Handlers) respondComments(ctx context.Context, w http.ResponseWriter, commentList []comments.CoreComment, response CommentsResponse, collapseResolved bool, statusCode int) error {
 	usersByID, err := h.buildCommentsUsersByID(ctx, commentList)
 	if err != nil {
 		return err
 	}
 
 	response.Comments = toAppComments(commentList, usersByID)
+	if collapseResolved {
+		for i := range response.Comments {
+			if response.Comments[i].Resolved {
+				response.Comments[i].SubComments = []AppComment{}
+				response.Comments[i].Collapsed = true
+			}
+		}
+	}
 	return web.Respond(ctx, w, response, statusCode)
 }
 
@@ -278,6 +302,13 @@ func (h *Handlers) List(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		return nil
 	}
 
+	if viewID := r.URL.Query().Get("viewId"); viewID != "" {
+		return h.listSavedView(ctx, w, workspace.ID, viewID)
+	}
+	if expression := r.URL.Query().Get("q"); expression != "" {
+		return h.listQuery(ctx, w, r, workspace.ID, expression)
+	}
+
 	var af AppFilters
 	filters, err := web.GetFilters(r.URL.Query(), &af)
 	if err != nil {
@@ -630,12 +661,13 @@ func (h *Handlers) BulkUpdate(ctx context.Context, w http.ResponseWriter, r *htt
 	myStoriesCachePattern := fmt.Sprintf(cache.MyStoriesKey+"*", workspace.ID.String())
 	h.cache.DeleteByPattern(ctx, myStoriesCachePattern)
 
-	if err := h.stories.BulkUpdate(ctx, storyIDs, workspace.ID, updates); err != nil {
-		web.RespondError(ctx, w, err, http.StatusBadRequest)
+	warnings, err := h.stories.BulkUpdate(ctx, storyIDs, workspace.ID, updates)
+	if err != nil {
+		web.RespondError(ctx, w, err, storyUpdateErrorStatus(err))
 		return nil
 	}
 
-	web.Respond(ctx, w, nil, http.StatusNoContent)
+	respondWIPWarnings(ctx, w, warnings)
 	return nil
 }
 
@@ -817,8 +849,9 @@ func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Re
 		web.RespondError(ctx, w, err, http.StatusBadRequest)
 		return nil
 	}
-	if err := h.stories.Update(ctx, storyId, workspace.ID, updates); err != nil {
-		web.RespondError(ctx, w, err, http.StatusBadRequest)
+	warnings, err := h.stories.Update(ctx, storyId, workspace.ID, updates)
+	if err != nil {
+		web.RespondError(ctx, w, err, storyUpdateErrorStatus(err))
 		return nil
 	}
 
@@ -834,10 +867,31 @@ func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Re
 	myStoriesCachePattern := fmt.Sprintf(cache.MyStoriesKey+"*", workspace.ID.String())
 	h.cache.DeleteByPattern(ctx, myStoriesCachePattern)
 
-	web.Respond(ctx, w, nil, http.StatusNoContent)
+	respondWIPWarnings(ctx, w, warnings)
 	return nil
 }
 
+// storyUpdateErrorStatus returns the status code for a failed update.
+func storyUpdateErrorStatus(err error) int {
+	if errors.Is(err, stories.ErrTransitionForbidden) {
+		return http.StatusForbidden
+	}
+	if errors.Is(err, stories.ErrWIPLimitReached) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+// respondWIPWarnings responds to a successful update with the WIP limits it
+// went over, or with no content when it went over none.
+func respondWIPWarnings(ctx context.Context, w http.ResponseWriter, warnings []stories.CoreWIPBreach) {
+	if len(warnings) == 0 {
+		web.Respond(ctx, w, nil, http.StatusNoContent)
+		return
+	}
+	web.Respond(ctx, w, AppStoryUpdateResult{WIPWarnings: toAppWIPBreaches(warnings)}, http.StatusOK)
+}
+
 func (h *Handlers) GetActivities(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.GetActivities")
 	defer span.End()
@@ -966,7 +1020,12 @@ func (h *Handlers) GetComments(ctx context.Context, w http.ResponseWriter, r *ht
 		pageSize = 20
 	}
 
-	commentsList, hasMore, err := h.stories.GetComments(ctx, storyId, page, pageSize)
+	// resolved=true|false filters threads; collapseResolved drops the replies
+	// of resolved threads while keeping their reply counts
+	resolved := parseBoolParam(r, "resolved")
+	collapseResolved := parseBoolParam(r, "collapseResolved")
+
+	commentsList, hasMore, err := h.stories.GetComments(ctx, storyId, resolved, page, pageSize)
 	if err != nil {
 		web.RespondError(ctx, w, err, http.StatusBadRequest)
 		return nil
@@ -986,7 +1045,7 @@ func (h *Handlers) GetComments(ctx context.Context, w http.ResponseWriter, r *ht
 		},
 	}
 
-	return h.respondComments(ctx, w, commentsList, response, http.StatusOK)
+	return h.respondComments(ctx, w, commentsList, response, collapseResolved != nil && *collapseResolved, http.StatusOK)
 }
 
 func (h *Handlers) DuplicateStory(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
@@ -1145,9 +1204,9 @@ func (h *Handlers) ListGrouped(ctx context.Context, w http.ResponseWriter, r *ht
 		return nil
 	}
 
-	query, err := parseStoryQuery(r, userID, workspace.ID)
+	query, filters, err := h.resolveStoryQuery(ctx, r, userID, workspace.ID)
 	if err != nil {
-		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		web.RespondError(ctx, w, err, storyQueryStatus(err))
 		return nil
 	}
 
@@ -1155,6 +1214,9 @@ func (h *Handlers) ListGrouped(ctx context.Context, w http.ResponseWriter, r *ht
 		query.StoriesPerGroup = 15
 	}
 	coreQuery := toCoreStoryQuery(query)
+	if filters != nil {
+		coreQuery.Filters = *filters
+	}
 	coreQuery.Filters.CurrentUserID = userID
 	coreQuery.Filters.WorkspaceID = workspace.ID
 
@@ -1177,7 +1239,7 @@ func (h *Handlers) ListGrouped(ctx context.Context, w http.ResponseWriter, r *ht
 
 	groups, err := h.stories.ListGroupedStories(ctx, coreQuery)
 	if err != nil {
-		web.RespondError(ctx, w, err, http.StatusInternalServerError)
+		web.RespondError(ctx, w, err, groupedStoriesStatus(err))
 		return nil
 	}
 
@@ -1206,9 +1268,9 @@ func (h *Handlers) LoadMoreGroup(ctx context.Context, w http.ResponseWriter, r *
 		return nil
 	}
 
-	query, err := parseStoryQuery(r, userID, workspace.ID)
+	query, filters, err := h.resolveStoryQuery(ctx, r, userID, workspace.ID)
 	if err != nil {
-		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		web.RespondError(ctx, w, err, storyQueryStatus(err))
 		return nil
 	}
 
@@ -1222,6 +1284,9 @@ func (h *Handlers) LoadMoreGroup(ctx context.Context, w http.ResponseWriter, r *
 	}
 
 	coreQuery := toCoreStoryQuery(query)
+	if filters != nil {
+		coreQuery.Filters = *filters
+	}
 	coreQuery.Filters.CurrentUserID = userID
 	coreQuery.Filters.WorkspaceID = workspace.ID
 
@@ -1355,11 +1420,11 @@ func parseStoryQuery(r *http.Request, userID, workspaceID uuid.UUID) (StoryQuery
 	query.GroupKey = r.URL.Query().Get("groupKey")
 
 	if !isValidGroupBy(query.GroupBy) {
-		return query, fmt.Errorf("invalid groupBy value: %s. Must be one of: status, assignee, priority, team, sprint, none", query.GroupBy)
+		return query, fmt.Errorf("invalid groupBy value: %s. Must be one of: status, assignee, priority, team, sprint, epic, custom_field:<fieldId>, none", query.GroupBy)
 	}
 
 	if !isValidOrderBy(query.OrderBy) {
-		return query, fmt.Errorf("invalid orderBy value: %s. Must be one of: created, updated, priority, deadline", query.OrderBy)
+		return query, fmt.Errorf("invalid orderBy value: %s. Must be one of: created, updated, priority, deadline, rank", query.OrderBy)
 	}
 
 	if !isValidOrderDirection(query.OrderDirection) {
@@ -1381,13 +1446,17 @@ func parseStoryQuery(r *http.Request, userID, workspaceID uuid.UUID) (StoryQuery
 
 	query.Filters.Priorities = parseStringArray(r, "priorities")
 	query.Filters.Categories = parseStringArray(r, "categories")
+	query.Filters.AssociationTypes = parseStringArray(r, "associationTypes")
 
 	query.Filters.Parent = parseUUIDParam(r, "parentId")
 	query.Filters.Objective = parseUUIDParam(r, "objectiveId")
 	query.Filters.Epic = parseUUIDParam(r, "epicId")
 
 	query.Filters.HasNoAssignee = parseBoolParam(r, "hasNoAssignee")
+	query.Filters.HasNoSprint = parseBoolParam(r, "hasNoSprint")
+	query.Filters.HasNoEpic = parseBoolParam(r, "hasNoEpic")
 	query.Filters.HasBlockedBy = parseBoolParam(r, "hasBlockedBy")
+	query.Filters.SLABreached = parseBoolParam(r, "slaBreached")
 	query.Filters.AssignedToMe = parseBoolParam(r, "assignedToMe")
 	query.Filters.CreatedByMe = parseBoolParam(r, "createdByMe")
 	query.Filters.ShowSubStories = parseBoolParam(r, "showSubStories")
@@ -1405,9 +1474,44 @@ func parseStoryQuery(r *http.Request, userID, workspaceID uuid.UUID) (StoryQuery
 	query.Filters.CompletedAfter = parseDateParam(r, "completedAfter")
 	query.Filters.CompletedBefore = parseDateParam(r, "completedBefore")
 
+	customFields, err := parseCustomFieldFilters(r)
+	if err != nil {
+		return query, err
+	}
+	query.Filters.CustomFields = customFields
+
 	return query, nil
 }
 
+// parseCustomFieldFilters reads repeatable customField=<fieldId>:<value>,<value>
+// parameters. The value "none" matches stories without a value for the field.
+func parseCustomFieldFilters(r *http.Request) ([]stories.CoreCustomFieldFilter, error) {
+	var filters []stories.CoreCustomFieldFilter
+	for _, raw := range r.URL.Query()["customField"] {
+		rawFieldID, rawValues, ok := strings.Cut(raw, ":")
+		fieldID, err := uuid.Parse(strings.TrimSpace(rawFieldID))
+		if !ok || err != nil {
+			return nil, fmt.Errorf("invalid customField value: %s. Must be <fieldId>:<value>[,<value>]", raw)
+		}
+
+		filter := stories.CoreCustomFieldFilter{FieldID: fieldID}
+		for _, part := range strings.Split(rawValues, ",") {
+			if trimmed := strings.TrimSpace(part); trimmed != "" {
+				filter.Values = append(filter.Values, trimmed)
+			}
+		}
+		if len(filter.Values) == 1 && filter.Values[0] == "none" {
+			filter.Values = nil
+			filter.IsEmpty = true
+		}
+		if len(filter.Values) == 0 && !filter.IsEmpty {
+			return nil, fmt.Errorf("invalid customField value: %s. At least one value is required", raw)
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
 func getStringParam(r *http.Request, key, defaultValue string) string {
 	if value := r.URL.Query().Get(key); value != "" {
 		return value
@@ -1534,64 +1638,66 @@ func parseDateParam(r *http.Request, key string) *time.Time {
 }
 
 func isValidOrderBy(orderBy string) bool {
-	validValues := []string{"created", "updated", "priority", "deadline"}
-	for _, v := range validValues {
-		if v == orderBy {
-			return true
-		}
-	}
-	return false
+	return stories.IsValidOrderBy(orderBy)
 }
 
 func isValidOrderDirection(direction string) bool {
-	return direction == "asc" || direction == "desc"
+	return stories.IsValidOrderDirection(direction)
 }
 
-func isValidGroupBy(groupBy string) bool {
-	validValues := []string{"status", "assignee", "priority", "team", "sprint", "none"}
-	for _, v := range validValues {
-		if v == groupBy {
-			return true
-		}
+// groupedStoriesStatus maps grouping errors caused by the request to a client error.
+func groupedStoriesStatus(err error) int {
+	if errors.Is(err, stories.ErrCustomFieldNotGroupable) || errors.Is(err, stories.ErrUnknownCustomField) {
+		return http.StatusBadRequest
 	}
-	return false
+	return http.StatusInternalServerError
+}
+
+func isValidGroupBy(groupBy string) bool {
+	return stories.IsValidGroupBy(groupBy)
 }
 
 func toCoreStoryQuery(query StoryQuery) stories.CoreStoryQuery {
 	return stories.CoreStoryQuery{
 		Filters: stories.CoreStoryFilters{
-			StatusIDs:       query.Filters.StatusIDs,
-			AssigneeIDs:     query.Filters.AssigneeIDs,
-			ReporterIDs:     query.Filters.ReporterIDs,
-			TitleContains:   query.Filters.TitleContains,
-			Priorities:      query.Filters.Priorities,
-			Categories:      query.Filters.Categories,
-			TeamIDs:         query.Filters.TeamIDs,
-			SprintIDs:       query.Filters.SprintIDs,
-			LabelIDs:        query.Filters.LabelIDs,
-			EstimateValues:  query.Filters.EstimateValues,
-			Parent:          query.Filters.Parent,
-			Objective:       query.Filters.Objective,
-			Epic:            query.Filters.Epic,
-			HasNoAssignee:   query.Filters.HasNoAssignee,
-			HasBlockedBy:    query.Filters.HasBlockedBy,
-			AssignedToMe:    query.Filters.AssignedToMe,
-			CreatedByMe:     query.Filters.CreatedByMe,
-			ShowSubStories:  query.Filters.ShowSubStories,
-			IncludeArchived: query.Filters.IncludeArchived,
-			IncludeDeleted:  query.Filters.IncludeDeleted,
-			CreatedAfter:    query.Filters.CreatedAfter,
-			CreatedBefore:   query.Filters.CreatedBefore,
-			UpdatedAfter:    query.Filters.UpdatedAfter,
-			UpdatedBefore:   query.Filters.UpdatedBefore,
-			StartDateAfter:  query.Filters.StartDateAfter,
-			StartDateBefore: query.Filters.StartDateBefore,
-			DeadlineAfter:   query.Filters.DeadlineAfter,
-			DeadlineBefore:  query.Filters.DeadlineBefore,
-			CompletedAfter:  query.Filters.CompletedAfter,
-			CompletedBefore: query.Filters.CompletedBefore,
-			CurrentUserID:   uuid.Nil,
-			WorkspaceID:     uuid.Nil,
+			StatusIDs:        query.Filters.StatusIDs,
+			AssigneeIDs:      query.Filters.AssigneeIDs,
+			ReporterIDs:      query.Filters.ReporterIDs,
+			TitleContains:    query.Filters.TitleContains,
+			Priorities:       query.Filters.Priorities,
+			Categories:       query.Filters.Categories,
+			TeamIDs:          query.Filters.TeamIDs,
+			SprintIDs:        query.Filters.SprintIDs,
+			LabelIDs:         query.Filters.LabelIDs,
+			EstimateValues:   query.Filters.EstimateValues,
+			Parent:           query.Filters.Parent,
+			Objective:        query.Filters.Objective,
+			Epic:             query.Filters.Epic,
+			HasNoAssignee:    query.Filters.HasNoAssignee,
+			HasNoSprint:      query.Filters.HasNoSprint,
+			HasNoEpic:        query.Filters.HasNoEpic,
+			HasBlockedBy:     query.Filters.HasBlockedBy,
+			SLABreached:      query.Filters.SLABreached,
+			AssociationTypes: query.Filters.AssociationTypes,
+			AssignedToMe:     query.Filters.AssignedToMe,
+			CreatedByMe:      query.Filters.CreatedByMe,
+			ShowSubStories:   query.Filters.ShowSubStories,
+			IncludeArchived:  query.Filters.IncludeArchived,
+			IncludeDeleted:   query.Filters.IncludeDeleted,
+			CreatedAfter:     query.Filters.CreatedAfter,
+			CreatedBefore:    query.Filters.CreatedBefore,
+			UpdatedAfter:     query.Filters.UpdatedAfter,
+			UpdatedBefore:    query.Filters.UpdatedBefore,
+			StartDateAfter:   query.Filters.StartDateAfter,
+			StartDateBefore:  query.Filters.StartDateBefore,
+			DeadlineAfter:    query.Filters.DeadlineAfter,
+			DeadlineBefore:   query.Filters.DeadlineBefore,
+			CompletedAfter:   query.Filters.CompletedAfter,
+			CompletedBefore:  query.Filters.CompletedBefore,
+			CustomFields:     query.Filters.CustomFields,
+			Exclude:          query.Filters.Exclude,
+			CurrentUserID:    uuid.Nil,
+			WorkspaceID:      uuid.Nil,
 		},
 		GroupBy:         query.GroupBy,
 		OrderBy:         query.OrderBy,
@@ -1648,9 +1754,21 @@ func coreFiltersToMap(filters stories.CoreStoryFilters) map[string]any {
 	if filters.HasNoAssignee != nil {
 		result["has_no_assignee"] = *filters.HasNoAssignee
 	}
+	if filters.HasNoSprint != nil {
+		result["has_no_sprint"] = *filters.HasNoSprint
+	}
+	if filters.HasNoEpic != nil {
+		result["has_no_epic"] = *filters.HasNoEpic
+	}
 	if filters.HasBlockedBy != nil {
 		result["has_blocked_by"] = *filters.HasBlockedBy
 	}
+	if filters.SLABreached != nil {
+		result["sla_breached"] = *filters.SLABreached
+	}
+	if len(filters.AssociationTypes) > 0 {
+		result["association_types"] = filters.AssociationTypes
+	}
 	if filters.AssignedToMe != nil {
 		result["assigned_to_me"] = *filters.AssignedToMe
 	}
@@ -1702,6 +1820,12 @@ func coreFiltersToMap(filters stories.CoreStoryFilters) map[string]any {
 	if filters.IsNotCompleted != nil {
 		result["is_not_completed"] = *filters.IsNotCompleted
 	}
+	if len(filters.CustomFields) > 0 {
+		result["custom_fields"] = filters.CustomFields
+	}
+	if filters.Exclude != nil {
+		result["exclude"] = filters.Exclude
+	}
 
 	return result
 }
@@ -1723,6 +1847,7 @@ func (h *Handlers) convertGroupsToResponse(ctx context.Context, groups []stories
 			HasMore:     group.HasMore,
 			Stories:     appStories,
 			NextPage:    group.NextPage,
+			WIP:         toAppStoryGroupWIP(group.WIP),
 		}
 	}
 
@@ -1763,7 +1888,7 @@ func (h *Handlers) AddAssociation(ctx context.Context, w http.ResponseWriter, r
 
 	assoc, err := h.stories.AddAssociation(ctx, fromStoryId, req.ToStoryID, req.AssociationType, workspace.ID)
 	if err != nil {
-		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		web.RespondError(ctx, w, err, associationStatus(err))
 		return nil
 	}
 
@@ -1808,7 +1933,7 @@ func (h *Handlers) UpdateAssociation(ctx context.Context, w http.ResponseWriter,
 
 	assoc, err := h.stories.UpdateAssociation(ctx, associationId, req.FromStoryID, req.ToStoryID, req.AssociationType, workspace.ID)
 	if err != nil {
-		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		web.RespondError(ctx, w, err, associationStatus(err))
 		return nil
 	}
 
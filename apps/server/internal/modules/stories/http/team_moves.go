@@ -0,0 +1,146 @@
+package storieshttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/cache"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// AppMoveToTeamRequest moves stories to another team. StatusMapping maps
+// statuses of the current teams to statuses of the target team. Unmapped
+// statuses move to a target status of the same category.
+type AppMoveToTeamRequest struct {
+	StoryIDs      []uuid.UUID             `json:"storyIds"`
+	TeamID        uuid.UUID               `json:"teamId"`
+	StatusMapping map[uuid.UUID]uuid.UUID `json:"statusMapping"`
+}
+
+// AppTeamMove is a story that moved to another team.
+type AppTeamMove struct {
+	StoryID     uuid.UUID  `json:"storyId"`
+	OldRef      string     `json:"oldRef"`
+	NewRef      string     `json:"newRef"`
+	TeamID      uuid.UUID  `json:"teamId"`
+	StatusID    *uuid.UUID `json:"statusId"`
+	SprintID    *uuid.UUID `json:"sprintId"`
+	OldSprintID *uuid.UUID `json:"oldSprintId"`
+}
+
+// MoveToTeam moves one story, with its sub-stories, to another team.
+func (h *Handlers) MoveToTeam(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.MoveToTeam")
+	defer span.End()
+
+	storyID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return nil
+	}
+
+	var req AppMoveToTeamRequest
+	if err := web.Decode(r, &req); err != nil {
+		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		return nil
+	}
+	req.StoryIDs = []uuid.UUID{storyID}
+
+	return h.moveToTeam(ctx, w, req)
+}
+
+// BulkMoveToTeam moves several stories, with their sub-stories, to another
+// team.
+func (h *Handlers) BulkMoveToTeam(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.BulkMoveToTeam")
+	defer span.End()
+
+	var req AppMoveToTeamRequest
+	if err := web.Decode(r, &req); err != nil {
+		web.RespondError(ctx, w, err, http.StatusBadRequest)
+		return nil
+	}
+
+	return h.moveToTeam(ctx, w, req)
+}
+
+func (h *Handlers) moveToTeam(ctx context.Context, w http.ResponseWriter, req AppMoveToTeamRequest) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	moves, err := h.stories.MoveToTeam(ctx, workspace.ID, userID, req.StoryIDs, req.TeamID, req.StatusMapping)
+	h.invalidateMovedStories(ctx, workspace.ID, moves)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, stories.ErrNotFound), errors.Is(err, stories.ErrTargetTeamNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, stories.ErrNoStoriesToMove), errors.Is(err, stories.ErrInvalidStatusMapping),
+			errors.Is(err, stories.ErrTransitionNotAllowed), errors.Is(err, stories.ErrTransitionMissingFields):
+			status = http.StatusBadRequest
+		case errors.Is(err, stories.ErrTransitionForbidden):
+			status = http.StatusForbidden
+		}
+		web.RespondError(ctx, w, err, status)
+		return nil
+	}
+
+	appMoves := make([]AppTeamMove, len(moves))
+	for i, move := range moves {
+		appMoves[i] = AppTeamMove{
+			StoryID:     move.StoryID,
+			OldRef:      move.OldRef(),
+			NewRef:      move.NewRef(),
+			TeamID:      move.ToTeamID,
+			StatusID:    move.NewStatusID,
+			SprintID:    move.NewSprintID,
+			OldSprintID: move.OldSprintID,
+		}
+	}
+
+	return web.Respond(ctx, w, appMoves, http.StatusOK)
+}
+
+// invalidateMovedStories clears the cached stories and lists touched by
+// moves, including those of a move that stopped partway.
+func (h *Handlers) invalidateMovedStories(ctx context.Context, workspaceID uuid.UUID, moves []stories.CoreTeamMove) {
+	storyIDs := make([]uuid.UUID, len(moves))
+	for i, move := range moves {
+		storyIDs[i] = move.StoryID
+	}
+	h.invalidateStories(ctx, workspaceID, storyIDs...)
+}
+
+// invalidateStories clears the cached stories and the workspace's story
+// lists.
+func (h *Handlers) invalidateStories(ctx context.Context, workspaceID uuid.UUID, storyIDs ...uuid.UUID) {
+	if len(storyIDs) == 0 {
+		return
+	}
+	for _, storyID := range storyIDs {
+		for _, key := range cache.InvalidateStoryKeys(workspaceID, storyID) {
+			if strings.Contains(key, "*") {
+				h.cache.DeleteByPattern(ctx, key)
+			} else {
+				h.cache.Delete(ctx, key)
+			}
+		}
+	}
+	h.cache.DeleteByPattern(ctx, fmt.Sprintf(cache.StoryListKey+"*", workspaceID.String()))
+	h.cache.DeleteByPattern(ctx, fmt.Sprintf(cache.MyStoriesKey+"*", workspaceID.String()))
+}
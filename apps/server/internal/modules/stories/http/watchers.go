@@ -0,0 +1,118 @@
+package storieshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+// AppStoryWatcher is a user subscribed to a story.
+type AppStoryWatcher struct {
+	User      AppUserSummary `json:"user"`
+	Source    string         `json:"source"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// ListWatchers returns the users watching a story.
+func (h *Handlers) ListWatchers(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.ListWatchers")
+	defer span.End()
+
+	storyID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	watchers, err := h.stories.ListWatchers(ctx, storyID, workspace.ID)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusInternalServerError)
+		return nil
+	}
+
+	userIDs := make([]uuid.UUID, len(watchers))
+	for i, watcher := range watchers {
+		userIDs[i] = watcher.UserID
+	}
+	usersByID, err := h.getStoryUsers(ctx, userIDs)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusInternalServerError)
+		return nil
+	}
+
+	appWatchers := make([]AppStoryWatcher, 0, len(watchers))
+	for _, watcher := range watchers {
+		user, ok := usersByID[watcher.UserID]
+		if !ok {
+			continue
+		}
+		appWatchers = append(appWatchers, AppStoryWatcher{
+			User:      user,
+			Source:    watcher.Source,
+			CreatedAt: watcher.CreatedAt,
+		})
+	}
+	return web.Respond(ctx, w, appWatchers, http.StatusOK)
+}
+
+// Watch subscribes the current user to a story.
+func (h *Handlers) Watch(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.setWatching(ctx, w, r, true)
+}
+
+// Unwatch unsubscribes the current user from a story.
+func (h *Handlers) Unwatch(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.setWatching(ctx, w, r, false)
+}
+
+func (h *Handlers) setWatching(ctx context.Context, w http.ResponseWriter, r *http.Request, watch bool) error {
+	ctx, span := web.AddSpan(ctx, "storieshttp.handlers.setWatching")
+	defer span.End()
+
+	storyID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		web.RespondError(ctx, w, ErrInvalidStoryID, http.StatusBadRequest)
+		return nil
+	}
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		web.RespondError(ctx, w, err, http.StatusUnauthorized)
+		return nil
+	}
+
+	if watch {
+		err = h.stories.Watch(ctx, storyID, workspace.ID, userID)
+	} else {
+		err = h.stories.Unwatch(ctx, storyID, workspace.ID, userID)
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, stories.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		web.RespondError(ctx, w, err, status)
+		return nil
+	}
+
+	web.Respond(ctx, w, nil, http.StatusNoContent)
+	return nil
+}
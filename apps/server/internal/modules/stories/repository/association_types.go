@@ -0,0 +1,196 @@
+package storiesrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const associationTypeColumns = `
+	type_id, workspace_id, key, forward_label, backward_label, created_by,
+	created_at, updated_at`
+
+type dbAssociationType struct {
+	ID            uuid.UUID  `db:"type_id"`
+	WorkspaceID   uuid.UUID  `db:"workspace_id"`
+	Key           string     `db:"key"`
+	ForwardLabel  string     `db:"forward_label"`
+	BackwardLabel string     `db:"backward_label"`
+	CreatedBy     *uuid.UUID `db:"created_by"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
+
+func toCoreAssociationType(t dbAssociationType) stories.CoreAssociationType {
+	return stories.CoreAssociationType{
+		ID:            t.ID,
+		WorkspaceID:   t.WorkspaceID,
+		Key:           t.Key,
+		ForwardLabel:  t.ForwardLabel,
+		BackwardLabel: t.BackwardLabel,
+		CreatedBy:     t.CreatedBy,
+		CreatedAt:     t.CreatedAt,
+		UpdatedAt:     t.UpdatedAt,
+	}
+}
+
+// ListAssociationTypes returns the workspace's own association types in the
+// order they were defined.
+func (r *repo) ListAssociationTypes(ctx context.Context, workspaceID uuid.UUID) ([]stories.CoreAssociationType, error) {
+	r.log.Info(ctx, "business.repository.stories.ListAssociationTypes")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.ListAssociationTypes")
+	defer span.End()
+
+	var rows []dbAssociationType
+	query := `
+		SELECT ` + associationTypeColumns + `
+		FROM story_association_types
+		WHERE workspace_id = $1
+		ORDER BY created_at, key`
+	if err := r.db.SelectContext(ctx, &rows, query, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to list association types: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to list association types"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	types := make([]stories.CoreAssociationType, len(rows))
+	for i, row := range rows {
+		types[i] = toCoreAssociationType(row)
+	}
+	return types, nil
+}
+
+func (r *repo) GetAssociationType(ctx context.Context, workspaceID uuid.UUID, key string) (stories.CoreAssociationType, error) {
+	r.log.Info(ctx, "business.repository.stories.GetAssociationType")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetAssociationType")
+	defer span.End()
+
+	var row dbAssociationType
+	query := `
+		SELECT ` + associationTypeColumns + `
+		FROM story_association_types
+		WHERE workspace_id = $1 AND key = $2`
+	if err := r.db.GetContext(ctx, &row, query, workspaceID, key); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return stories.CoreAssociationType{}, stories.ErrAssociationTypeNotFound
+		}
+		errMsg := fmt.Sprintf("failed to get association type: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get association type"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return stories.CoreAssociationType{}, err
+	}
+	return toCoreAssociationType(row), nil
+}
+
+// CreateAssociationType stores a new association type. A key the workspace
+// already uses fails with ErrAssociationTypeExists.
+func (r *repo) CreateAssociationType(ctx context.Context, assocType stories.CoreAssociationType) (stories.CoreAssociationType, error) {
+	r.log.Info(ctx, "business.repository.stories.CreateAssociationType")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.CreateAssociationType")
+	defer span.End()
+
+	var row dbAssociationType
+	query := `
+		INSERT INTO story_association_types (
+			workspace_id, key, forward_label, backward_label, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (workspace_id, key) DO NOTHING
+		RETURNING ` + associationTypeColumns
+	err := r.db.GetContext(ctx, &row, query,
+		assocType.WorkspaceID, assocType.Key, assocType.ForwardLabel, assocType.BackwardLabel, assocType.CreatedBy)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return stories.CoreAssociationType{}, stories.ErrAssociationTypeExists
+		}
+		errMsg := fmt.Sprintf("failed to create association type: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to create association type"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return stories.CoreAssociationType{}, err
+	}
+
+	span.AddEvent("association type created.", trace.WithAttributes(
+		attribute.String("association_type.key", row.Key),
+	))
+	return toCoreAssociationType(row), nil
+}
+
+// UpdateAssociationType sets the labels of an association type.
+func (r *repo) UpdateAssociationType(ctx context.Context, workspaceID uuid.UUID, key, forwardLabel, backwardLabel string) (stories.CoreAssociationType, error) {
+	r.log.Info(ctx, "business.repository.stories.UpdateAssociationType")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.UpdateAssociationType")
+	defer span.End()
+
+	var row dbAssociationType
+	query := `
+		UPDATE story_association_types
+		SET forward_label = $3, backward_label = $4, updated_at = NOW()
+		WHERE workspace_id = $1 AND key = $2
+		RETURNING ` + associationTypeColumns
+	if err := r.db.GetContext(ctx, &row, query, workspaceID, key, forwardLabel, backwardLabel); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return stories.CoreAssociationType{}, stories.ErrAssociationTypeNotFound
+		}
+		errMsg := fmt.Sprintf("failed to update association type: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to update association type"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return stories.CoreAssociationType{}, err
+	}
+	return toCoreAssociationType(row), nil
+}
+
+// DeleteAssociationType removes an association type that no association
+// uses. A type still in use fails with ErrAssociationTypeInUse.
+func (r *repo) DeleteAssociationType(ctx context.Context, workspaceID uuid.UUID, key string) error {
+	r.log.Info(ctx, "business.repository.stories.DeleteAssociationType")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.DeleteAssociationType")
+	defer span.End()
+
+	var result struct {
+		Exists  bool `db:"type_exists"`
+		InUse   bool `db:"in_use"`
+		Deleted bool `db:"deleted"`
+	}
+	query := `
+		WITH in_use AS (
+			SELECT EXISTS (
+				SELECT 1 FROM story_associations
+				WHERE workspace_id = $1 AND association_type = $2
+			) AS in_use
+		),
+		deleted AS (
+			DELETE FROM story_association_types
+			WHERE workspace_id = $1 AND key = $2 AND NOT (SELECT in_use FROM in_use)
+			RETURNING type_id
+		)
+		SELECT
+			EXISTS (
+				SELECT 1 FROM story_association_types WHERE workspace_id = $1 AND key = $2
+			) AS type_exists,
+			(SELECT in_use FROM in_use) AS in_use,
+			EXISTS (SELECT 1 FROM deleted) AS deleted`
+	if err := r.db.GetContext(ctx, &result, query, workspaceID, key); err != nil {
+		errMsg := fmt.Sprintf("failed to delete association type: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to delete association type"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+
+	switch {
+	case result.Deleted:
+		return nil
+	case result.Exists && result.InUse:
+		return stories.ErrAssociationTypeInUse
+	default:
+		return stories.ErrAssociationTypeNotFound
+	}
+}
@@ -0,0 +1,263 @@
+package storiesrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const checklistItemColumns = `
+	item_id, story_id, workspace_id, text, done, assignee_id, due_date,
+	order_index, created_by, completed_at, created_at, updated_at`
+
+type dbChecklistItem struct {
+	ID          uuid.UUID  `db:"item_id"`
+	StoryID     uuid.UUID  `db:"story_id"`
+	WorkspaceID uuid.UUID  `db:"workspace_id"`
+	Text        string     `db:"text"`
+	Done        bool       `db:"done"`
+	AssigneeID  *uuid.UUID `db:"assignee_id"`
+	DueDate     *time.Time `db:"due_date"`
+	OrderIndex  int        `db:"order_index"`
+	CreatedBy   *uuid.UUID `db:"created_by"`
+	CompletedAt *time.Time `db:"completed_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+type dbChecklistProgress struct {
+	StoryID uuid.UUID `db:"story_id"`
+	Total   int       `db:"total"`
+	Done    int       `db:"done"`
+}
+
+func toCoreChecklistItem(i dbChecklistItem) stories.CoreChecklistItem {
+	return stories.CoreChecklistItem{
+		ID:          i.ID,
+		StoryID:     i.StoryID,
+		WorkspaceID: i.WorkspaceID,
+		Text:        i.Text,
+		Done:        i.Done,
+		AssigneeID:  i.AssigneeID,
+		DueDate:     i.DueDate,
+		OrderIndex:  i.OrderIndex,
+		CreatedBy:   i.CreatedBy,
+		CompletedAt: i.CompletedAt,
+		CreatedAt:   i.CreatedAt,
+		UpdatedAt:   i.UpdatedAt,
+	}
+}
+
+// ListChecklistItems returns a story's checklist items in order.
+func (r *repo) ListChecklistItems(ctx context.Context, storyID, workspaceID uuid.UUID) ([]stories.CoreChecklistItem, error) {
+	r.log.Info(ctx, "business.repository.stories.ListChecklistItems")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.ListChecklistItems")
+	defer span.End()
+
+	var rows []dbChecklistItem
+	query := `
+		SELECT ` + checklistItemColumns + `
+		FROM story_checklist_items
+		WHERE story_id = $1 AND workspace_id = $2
+		ORDER BY order_index, created_at`
+	if err := r.db.SelectContext(ctx, &rows, query, storyID, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to list checklist items: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to list checklist items"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	items := make([]stories.CoreChecklistItem, len(rows))
+	for i, row := range rows {
+		items[i] = toCoreChecklistItem(row)
+	}
+	return items, nil
+}
+
+func (r *repo) GetChecklistItem(ctx context.Context, itemID, workspaceID uuid.UUID) (stories.CoreChecklistItem, error) {
+	r.log.Info(ctx, "business.repository.stories.GetChecklistItem")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetChecklistItem")
+	defer span.End()
+
+	var row dbChecklistItem
+	query := `
+		SELECT ` + checklistItemColumns + `
+		FROM story_checklist_items
+		WHERE item_id = $1 AND workspace_id = $2`
+	if err := r.db.GetContext(ctx, &row, query, itemID, workspaceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return stories.CoreChecklistItem{}, stories.ErrChecklistItemNotFound
+		}
+		errMsg := fmt.Sprintf("failed to get checklist item: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get checklist item"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return stories.CoreChecklistItem{}, err
+	}
+	return toCoreChecklistItem(row), nil
+}
+
+// CreateChecklistItem adds an item after the story's last item. An assignee
+// who is not a member of the workspace fails with
+// ErrInvalidChecklistAssignee.
+func (r *repo) CreateChecklistItem(ctx context.Context, item stories.CoreChecklistItem) (stories.CoreChecklistItem, error) {
+	r.log.Info(ctx, "business.repository.stories.CreateChecklistItem")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.CreateChecklistItem")
+	defer span.End()
+
+	var row dbChecklistItem
+	query := `
+		INSERT INTO story_checklist_items (
+			story_id, workspace_id, text, assignee_id, due_date, order_index, created_by
+		)
+		SELECT
+			$1, $2, $3, $4, $5,
+			COALESCE((SELECT MAX(order_index) + 1 FROM story_checklist_items WHERE story_id = $1), 0),
+			$6
+		WHERE $4::uuid IS NULL OR EXISTS (
+			SELECT 1 FROM workspace_members WHERE workspace_id = $2 AND user_id = $4
+		)
+		RETURNING ` + checklistItemColumns
+	err := r.db.GetContext(ctx, &row, query,
+		item.StoryID, item.WorkspaceID, item.Text, item.AssigneeID, item.DueDate, item.CreatedBy)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return stories.CoreChecklistItem{}, stories.ErrInvalidChecklistAssignee
+		}
+		errMsg := fmt.Sprintf("failed to create checklist item: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to create checklist item"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return stories.CoreChecklistItem{}, err
+	}
+
+	span.AddEvent("checklist item created.", trace.WithAttributes(
+		attribute.String("checklist_item.id", row.ID.String()),
+	))
+	return toCoreChecklistItem(row), nil
+}
+
+// ToggleChecklistItem flips an item's done flag and returns the item.
+func (r *repo) ToggleChecklistItem(ctx context.Context, itemID, workspaceID uuid.UUID) (stories.CoreChecklistItem, error) {
+	r.log.Info(ctx, "business.repository.stories.ToggleChecklistItem")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.ToggleChecklistItem")
+	defer span.End()
+
+	var row dbChecklistItem
+	query := `
+		UPDATE story_checklist_items
+		SET
+			done = NOT done,
+			completed_at = CASE WHEN done THEN NULL ELSE NOW() END,
+			updated_at = NOW()
+		WHERE item_id = $1 AND workspace_id = $2
+		RETURNING ` + checklistItemColumns
+	if err := r.db.GetContext(ctx, &row, query, itemID, workspaceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return stories.CoreChecklistItem{}, stories.ErrChecklistItemNotFound
+		}
+		errMsg := fmt.Sprintf("failed to toggle checklist item: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to toggle checklist item"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return stories.CoreChecklistItem{}, err
+	}
+	return toCoreChecklistItem(row), nil
+}
+
+// ReorderChecklistItems sets the order of a story's items to that of
+// itemIDs.
+func (r *repo) ReorderChecklistItems(ctx context.Context, storyID, workspaceID uuid.UUID, itemIDs []uuid.UUID) error {
+	r.log.Info(ctx, "business.repository.stories.ReorderChecklistItems")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.ReorderChecklistItems")
+	defer span.End()
+
+	query := `
+		UPDATE story_checklist_items i
+		SET order_index = o.position - 1, updated_at = NOW()
+		FROM unnest($3::uuid[]) WITH ORDINALITY AS o(item_id, position)
+		WHERE i.item_id = o.item_id AND i.story_id = $1 AND i.workspace_id = $2`
+	if _, err := r.db.ExecContext(ctx, query, storyID, workspaceID, itemIDs); err != nil {
+		errMsg := fmt.Sprintf("failed to reorder checklist items: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to reorder checklist items"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+
+	span.AddEvent("checklist items reordered.", trace.WithAttributes(
+		attribute.Int("checklist.count", len(itemIDs)),
+	))
+	return nil
+}
+
+func (r *repo) DeleteChecklistItem(ctx context.Context, itemID, workspaceID uuid.UUID) error {
+	r.log.Info(ctx, "business.repository.stories.DeleteChecklistItem")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.DeleteChecklistItem")
+	defer span.End()
+
+	if err := deleteChecklistItem(ctx, r.db, itemID, workspaceID); err != nil {
+		if errors.Is(err, stories.ErrChecklistItemNotFound) {
+			return err
+		}
+		errMsg := fmt.Sprintf("failed to delete checklist item: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to delete checklist item"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	return nil
+}
+
+// deleteChecklistItem deletes an item on q. It returns
+// ErrChecklistItemNotFound when the item is already gone, which inside a
+// transaction also means another one deleted it first.
+func deleteChecklistItem(ctx context.Context, q sqlx.ExecerContext, itemID, workspaceID uuid.UUID) error {
+	query := `DELETE FROM story_checklist_items WHERE item_id = $1 AND workspace_id = $2`
+	result, err := q.ExecContext(ctx, query, itemID, workspaceID)
+	if err != nil {
+		return err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return stories.ErrChecklistItemNotFound
+	}
+	return nil
+}
+
+// GetChecklistProgress counts the checklist items of stories. Stories
+// without items are left out.
+func (r *repo) GetChecklistProgress(ctx context.Context, workspaceID uuid.UUID, storyIDs []uuid.UUID) (map[uuid.UUID]stories.CoreChecklistProgress, error) {
+	r.log.Info(ctx, "business.repository.stories.GetChecklistProgress")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetChecklistProgress")
+	defer span.End()
+
+	var rows []dbChecklistProgress
+	query := `
+		SELECT
+			story_id,
+			CAST(COUNT(*) AS int) AS total,
+			CAST(COUNT(*) FILTER (WHERE done) AS int) AS done
+		FROM story_checklist_items
+		WHERE workspace_id = $1 AND story_id = ANY($2)
+		GROUP BY story_id`
+	if err := r.db.SelectContext(ctx, &rows, query, workspaceID, storyIDs); err != nil {
+		errMsg := fmt.Sprintf("failed to get checklist progress: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get checklist progress"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	progress := make(map[uuid.UUID]stories.CoreChecklistProgress, len(rows))
+	for _, row := range rows {
+		progress[row.StoryID] = stories.CoreChecklistProgress{Total: row.Total, Done: row.Done}
+	}
+	return progress, nil
+}
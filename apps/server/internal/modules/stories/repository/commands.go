@@ -10,8 +10,11 @@ import (
 	commentsrepository "github.com/complexus-tech/projects-api/internal/modules/comments/repository"
 	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
 	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/outbox"
 	"github.com/complexus-tech/projects-api/pkg/web"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -25,6 +28,25 @@ func (r *repo) GetNextSequenceID(ctx context.Context, teamID uuid.UUID, workspac
 		return 0, nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
+	currentSequence, err := r.nextSequenceID(ctx, tx, teamID, workspaceId)
+	if err != nil {
+		tx.Rollback()
+		return 0, nil, nil, err
+	}
+
+	commit := func() error {
+		return tx.Commit()
+	}
+
+	rollback := func() error {
+		return tx.Rollback()
+	}
+
+	return currentSequence, commit, rollback, nil
+}
+
+// nextSequenceID bumps the team's story sequence inside tx and returns the previous value.
+func (r *repo) nextSequenceID(ctx context.Context, tx *sqlx.Tx, teamID uuid.UUID, workspaceId uuid.UUID) (int, error) {
 	query := `
 		INSERT INTO team_story_sequences (workspace_id, team_id, current_sequence) 
 		VALUES (:workspace_id, :team_id, 0) 
@@ -40,31 +62,21 @@ func (r *repo) GetNextSequenceID(ctx context.Context, teamID uuid.UUID, workspac
 
 	stmt, err := tx.PrepareNamedContext(ctx, query)
 	if err != nil {
-		tx.Rollback()
-		return 0, nil, nil, fmt.Errorf("failed to prepare named statement: %w", err)
+		return 0, fmt.Errorf("failed to prepare named statement: %w", err)
 	}
 	defer stmt.Close()
 
 	var currentSequence int
-	err = stmt.GetContext(ctx, &currentSequence, params)
-	if err != nil {
-		tx.Rollback()
-		return 0, nil, nil, fmt.Errorf("failed to get/update sequence: %w", err)
+	if err := stmt.GetContext(ctx, &currentSequence, params); err != nil {
+		return 0, fmt.Errorf("failed to get/update sequence: %w", err)
 	}
 
-	commit := func() error {
-		return tx.Commit()
-	}
-
-	rollback := func() error {
-		return tx.Rollback()
-	}
-
-	return currentSequence, commit, rollback, nil
+	return currentSequence, nil
 }
 
 // Create creates a new story with automatic sequence recovery on conflicts.
-func (r *repo) Create(ctx context.Context, story *stories.CoreSingleStory) (stories.CoreSingleStory, error) {
+// The sequence bump, the insert and the rest of write are committed together.
+func (r *repo) Create(ctx context.Context, story *stories.CoreSingleStory, write stories.CoreStoryWrite) (stories.CoreSingleStory, error) {
 	ctx, span := web.AddSpan(ctx, "business.repository.stories.Create")
 	defer span.End()
 
@@ -74,20 +86,37 @@ func (r *repo) Create(ctx context.Context, story *stories.CoreSingleStory) (stor
 			return stories.CoreSingleStory{}, err
 		}
 	}
+	if story.ID == uuid.Nil {
+		story.ID = uuid.New()
+	}
 
 	const maxRetries = 3
 	var lastErr error
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		lastSequence, commit, rollback, err := r.GetNextSequenceID(ctx, story.Team, story.Workspace)
+		tx, err := r.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return stories.CoreSingleStory{}, fmt.Errorf("failed to begin transaction: %w", err)
+		}
+
+		if write.ChecklistItemID != nil {
+			if err := deleteChecklistItem(ctx, tx, *write.ChecklistItemID, story.Workspace); err != nil {
+				tx.Rollback()
+				span.RecordError(err)
+				return stories.CoreSingleStory{}, err
+			}
+		}
+
+		lastSequence, err := r.nextSequenceID(ctx, tx, story.Team, story.Workspace)
 		if err != nil {
+			tx.Rollback()
 			return stories.CoreSingleStory{}, fmt.Errorf("failed to get next sequence ID: %w", err)
 		}
 		story.SequenceID = lastSequence + 1
 
-		cs, err := r.insertStory(ctx, story)
+		cs, err := r.insertStory(ctx, tx, story)
 		if err != nil {
-			rollback()
+			tx.Rollback()
 
 			// Check if this is a duplicate sequence ID error
 			if strings.Contains(err.Error(), "duplicate key value violates unique constraint") &&
@@ -111,7 +140,19 @@ func (r *repo) Create(ctx context.Context, story *stories.CoreSingleStory) (stor
 			return stories.CoreSingleStory{}, fmt.Errorf("failed to insert story: %w", err)
 		}
 
-		if err := commit(); err != nil {
+		if err := r.setCustomFieldValues(ctx, tx, cs.ID, write.ActorID, write.CustomFieldValues); err != nil {
+			tx.Rollback()
+			span.RecordError(err)
+			return stories.CoreSingleStory{}, err
+		}
+
+		if err := outbox.Write(ctx, tx, write.Events...); err != nil {
+			tx.Rollback()
+			span.RecordError(err)
+			return stories.CoreSingleStory{}, err
+		}
+
+		if err := tx.Commit(); err != nil {
 			return stories.CoreSingleStory{}, fmt.Errorf("failed to commit transaction: %w", err)
 		}
 
@@ -127,11 +168,13 @@ func (r *repo) syncSequence(ctx context.Context, teamID, workspaceID uuid.UUID)
 	ctx, span := web.AddSpan(ctx, "business.repository.stories.syncSequence")
 	defer span.End()
 
-	// Get the actual max sequence_id from the stories table
+	// Get the actual max sequence_id from the stories table, counting the
+	// references of stories that moved away so they are never reused.
 	maxSeqQuery := `
-		SELECT COALESCE(MAX(sequence_id), 0) 
-		FROM stories 
-		WHERE team_id = :team_id AND workspace_id = :workspace_id
+		SELECT GREATEST(
+			(SELECT COALESCE(MAX(sequence_id), 0) FROM stories WHERE team_id = :team_id AND workspace_id = :workspace_id),
+			(SELECT COALESCE(MAX(sequence_id), 0) FROM story_ref_aliases WHERE team_id = :team_id AND workspace_id = :workspace_id)
+		)
 	`
 	params := map[string]any{
 		"team_id":      teamID,
@@ -179,27 +222,36 @@ func (r *repo) syncSequence(ctx context.Context, teamID, workspaceID uuid.UUID)
 	return nil
 }
 
-func (r *repo) insertStory(ctx context.Context, story *stories.CoreSingleStory) (dbStory, error) {
+func (r *repo) insertStory(ctx context.Context, tx *sqlx.Tx, story *stories.CoreSingleStory) (dbStory, error) {
 	ctx, span := web.AddSpan(ctx, "business.repository.stories.insertStory")
 	defer span.End()
 
 	q := `
 			INSERT INTO stories (
-					sequence_id, title, description, description_html,
-					parent_id, objective_id, status_id, assignee_id, 
+					id, sequence_id, title, description, description_html,
+					parent_id, objective_id, epic_id, status_id, assignee_id,
 					blocked_by_id, blocking_id, related_id, reporter_id,
 					priority, estimate_unit, sprint_id, key_result_id, team_id, workspace_id, start_date, 
-					end_date, created_at, updated_at
+					end_date, rank, created_at, updated_at
 			) VALUES (
-					:sequence_id, :title, :description, :description_html,
-					:parent_id, :objective_id, :status_id, :assignee_id, :blocked_by_id,
+					:id, :sequence_id, :title, :description, :description_html,
+					:parent_id, :objective_id, :epic_id, :status_id, :assignee_id, :blocked_by_id,
 					:blocking_id, :related_id, :reporter_id, :priority, :estimate_unit, :sprint_id,
-					:key_result_id, :team_id, :workspace_id, :start_date, :end_date, :created_at, :updated_at
-			) RETURNING stories.id, stories.sequence_id, stories.title, stories.description, stories.description_html, stories.parent_id, stories.objective_id, stories.status_id, stories.assignee_id, stories.blocked_by_id, stories.blocking_id, stories.related_id, stories.reporter_id, stories.priority, stories.estimate_unit, stories.sprint_id, stories.key_result_id, stories.team_id, stories.workspace_id, stories.start_date, stories.end_date, stories.created_at, stories.updated_at;
+					:key_result_id, :team_id, :workspace_id, :start_date, :end_date, :rank, :created_at, :updated_at
+			) RETURNING stories.id, stories.sequence_id, stories.title, stories.description, stories.description_html, stories.parent_id, stories.objective_id, stories.epic_id, stories.status_id, stories.assignee_id, stories.blocked_by_id, stories.blocking_id, stories.related_id, stories.reporter_id, stories.priority, stories.estimate_unit, stories.sprint_id, stories.key_result_id, stories.team_id, stories.workspace_id, stories.start_date, stories.end_date, stories.created_at, stories.updated_at;
 		`
 
+	params := toDBStory(*story)
+	rank, err := NextStoryRank(ctx, tx, story.Workspace)
+	if err != nil {
+		r.log.Error(ctx, err.Error())
+		span.RecordError(err)
+		return dbStory{}, err
+	}
+	params.Rank = &rank
+
 	var cs dbStory
-	stmt, err := r.db.PrepareNamedContext(ctx, q)
+	stmt, err := tx.PrepareNamedContext(ctx, q)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to prepare named statement: %s", err)
 		r.log.Error(ctx, errMsg)
@@ -209,7 +261,7 @@ func (r *repo) insertStory(ctx context.Context, story *stories.CoreSingleStory)
 	defer stmt.Close()
 
 	r.log.Info(ctx, "creating story.")
-	if err := stmt.GetContext(ctx, &cs, toDBStory(*story)); err != nil {
+	if err := stmt.GetContext(ctx, &cs, params); err != nil {
 		errMsg := fmt.Sprintf("failed to create story: %s", err)
 		r.log.Error(ctx, errMsg)
 		span.RecordError(errors.New("failed to create story"), trace.WithAttributes(attribute.String("error", errMsg)))
@@ -537,7 +589,8 @@ func (r *repo) BulkArchive(ctx context.Context, ids []uuid.UUID, workspaceId uui
 }
 
 // Update updates the story with the specified ID.
-func (r *repo) Update(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID, updates map[string]any) error {
+// The rest of write is committed in the same transaction as the update.
+func (r *repo) Update(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID, updates map[string]any, write stories.CoreStoryWrite) error {
 	r.log.Info(ctx, "business.repository.stories.Update")
 	ctx, span := web.AddSpan(ctx, "business.repository.stories.Update")
 	defer span.End()
@@ -586,7 +639,26 @@ func (r *repo) Update(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID,
 	query += strings.Join(setClauses, ", ")
 	query += " WHERE id = :id AND workspace_id = :workspace_id;"
 
-	stmt, err := r.db.PrepareNamedContext(ctx, query)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if write.CheckWIP != nil {
+		limits, err := getWIPLimits(ctx, tx, workspaceId, write.WIPStatusIDs, true)
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to lock WIP limits: %s", err)
+			r.log.Error(ctx, errMsg, "id", id)
+			span.RecordError(errors.New("failed to lock WIP limits"), trace.WithAttributes(attribute.String("error", errMsg)))
+			return err
+		}
+		if err := write.CheckWIP(limits); err != nil {
+			return err
+		}
+	}
+
+	stmt, err := tx.PrepareNamedContext(ctx, query)
 	if err != nil {
 		r.log.Error(ctx, fmt.Sprintf("Failed to prepare named update statement: %s", err), "id", id)
 		return err
@@ -600,6 +672,28 @@ func (r *repo) Update(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID,
 		return err
 	}
 
+	if err := r.setCustomFieldValues(ctx, tx, id, write.ActorID, write.CustomFieldValues); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := saveDescriptionRevision(ctx, tx, write); err != nil {
+		errMsg := err.Error()
+		r.log.Error(ctx, errMsg, "id", id)
+		span.RecordError(errors.New("failed to save description revision"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+
+	if err := outbox.Write(ctx, tx, write.Events...); err != nil {
+		r.log.Error(ctx, fmt.Sprintf("Failed to write story events: %s", err), "id", id)
+		span.RecordError(err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	r.log.Info(ctx, fmt.Sprintf("Story #%s updated successfully", id), "id", id)
 	span.AddEvent("Story updated.", trace.WithAttributes(attribute.String("story.id", id.String())))
 
@@ -783,20 +877,31 @@ func shouldCompactActivity(activity stories.CoreActivity) bool {
 
 // GetActivitiesWithUser returns activities for a given story ID with user details and pagination.
 
-func (r *repo) CreateComment(ctx context.Context, cnc stories.CoreNewComment) (comments.CoreComment, error) {
+// CreateComment inserts a comment and writes any outbox events in the same transaction.
+func (r *repo) CreateComment(ctx context.Context, cnc stories.CoreNewComment, outboxEvents ...events.Event) (comments.CoreComment, error) {
 	r.log.Info(ctx, "business.repository.stories.CreateComment")
 	ctx, span := web.AddSpan(ctx, "business.repository.stories.CreateComment")
 	defer span.End()
 
+	if cnc.ID == uuid.Nil {
+		cnc.ID = uuid.New()
+	}
+
 	q := `
 	INSERT INTO story_comments (
-		content, story_id, commenter_id, parent_id
+		id, content, story_id, commenter_id, parent_id
 	) VALUES (
-		:content, :story_id, :commenter_id, :parent_id
+		:id, :content, :story_id, :commenter_id, :parent_id
 	) RETURNING story_comments.*;
 `
 
-	stmt, err := r.db.PrepareNamedContext(ctx, q)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return comments.CoreComment{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareNamedContext(ctx, q)
 	if err != nil {
 		r.log.Error(ctx, fmt.Sprintf("failed to prepare named statement: %s", err))
 		return comments.CoreComment{}, err
@@ -809,6 +914,16 @@ func (r *repo) CreateComment(ctx context.Context, cnc stories.CoreNewComment) (c
 		return comments.CoreComment{}, err
 	}
 
+	if err := outbox.Write(ctx, tx, outboxEvents...); err != nil {
+		r.log.Error(ctx, fmt.Sprintf("failed to write comment events: %s", err))
+		span.RecordError(err)
+		return comments.CoreComment{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return comments.CoreComment{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return toCoreComment(comment), nil
 }
 
@@ -890,6 +1005,7 @@ func (r *repo) DuplicateStory(ctx context.Context, originalStoryID uuid.UUID, wo
 			description_html,
 			team_id,
 			objective_id,
+			epic_id,
 			status_id,
 			assignee_id,
 			priority,
@@ -897,6 +1013,7 @@ func (r *repo) DuplicateStory(ctx context.Context, originalStoryID uuid.UUID, wo
 			sprint_id,
 			workspace_id,
 			reporter_id,
+			rank,
 			created_at,
 			updated_at
 		) VALUES (
@@ -906,6 +1023,7 @@ func (r *repo) DuplicateStory(ctx context.Context, originalStoryID uuid.UUID, wo
 			:description_html,
 			:team_id,
 			:objective_id,
+			:epic_id,
 			:status_id,
 			:assignee_id,
 			:priority,
@@ -913,11 +1031,17 @@ func (r *repo) DuplicateStory(ctx context.Context, originalStoryID uuid.UUID, wo
 			:sprint_id,
 			:workspace_id,
 			:reporter_id,
+			:rank,
 			NOW(),
 			NOW()
-		) RETURNING stories.id, stories.sequence_id, stories.title, stories.description, stories.description_html, stories.parent_id, stories.objective_id, stories.status_id, stories.assignee_id, stories.blocked_by_id, stories.blocking_id, stories.related_id, stories.reporter_id, stories.priority, stories.estimate_unit, stories.sprint_id, stories.team_id, stories.workspace_id, stories.start_date, stories.end_date, stories.created_at, stories.updated_at;
+		) RETURNING stories.id, stories.sequence_id, stories.title, stories.description, stories.description_html, stories.parent_id, stories.objective_id, stories.epic_id, stories.status_id, stories.assignee_id, stories.blocked_by_id, stories.blocking_id, stories.related_id, stories.reporter_id, stories.priority, stories.estimate_unit, stories.sprint_id, stories.team_id, stories.workspace_id, stories.start_date, stories.end_date, stories.created_at, stories.updated_at;
 	`
 
+	rank, err := NextStoryRank(ctx, tx, workspaceId)
+	if err != nil {
+		return stories.CoreSingleStory{}, err
+	}
+
 	// Prepare parameters for the new story
 	params := map[string]any{
 		"sequence_id":      lastSequence + 1,
@@ -926,6 +1050,7 @@ func (r *repo) DuplicateStory(ctx context.Context, originalStoryID uuid.UUID, wo
 		"description_html": originalStory.DescriptionHTML,
 		"team_id":          originalStory.Team,
 		"objective_id":     originalStory.Objective,
+		"epic_id":          originalStory.Epic,
 		"status_id":        originalStory.Status,
 		"assignee_id":      originalStory.Assignee,
 		"priority":         originalStory.Priority,
@@ -933,6 +1058,7 @@ func (r *repo) DuplicateStory(ctx context.Context, originalStoryID uuid.UUID, wo
 		"sprint_id":        originalStory.Sprint,
 		"workspace_id":     workspaceId,
 		"reporter_id":      userID,
+		"rank":             rank,
 	}
 
 	// Execute the insert
@@ -985,8 +1111,15 @@ func (r *repo) CreateStoryFromIssue(ctx context.Context, workspaceID, teamID uui
 	}
 	stmtStatus.Close()
 
-	// 2) Get next sequence id
-	sequenceID, commit, rollback, err := r.GetNextSequenceID(ctx, teamID, workspaceID)
+	// 2) Get next sequence id and rank in the transaction that inserts the
+	// story, so the rank lock is held until the story is written
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	sequenceID, err := r.nextSequenceID(ctx, tx, teamID, workspaceID)
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -994,11 +1127,16 @@ func (r *repo) CreateStoryFromIssue(ctx context.Context, workspaceID, teamID uui
 	// 3) Insert story
 	insertQuery := `
 		INSERT INTO stories (
-			sequence_id, title, description, description_html, status_id, priority, estimate_unit, team_id, workspace_id, reporter_id, created_at, updated_at
+			sequence_id, title, description, description_html, status_id, priority, estimate_unit, team_id, workspace_id, reporter_id, rank, created_at, updated_at
 		) VALUES (
-			:sequence_id, :title, :description, :description_html, :status_id, :priority, :estimate_unit, :team_id, :workspace_id, :reporter_id, NOW(), NOW()
+			:sequence_id, :title, :description, :description_html, :status_id, :priority, :estimate_unit, :team_id, :workspace_id, :reporter_id, :rank, NOW(), NOW()
 		) RETURNING id`
 
+	rank, err := NextStoryRank(ctx, tx, workspaceID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
 	params := map[string]any{
 		"sequence_id":      sequenceID + 1,
 		"title":            title,
@@ -1010,22 +1148,21 @@ func (r *repo) CreateStoryFromIssue(ctx context.Context, workspaceID, teamID uui
 		"workspace_id":     workspaceID,
 		"reporter_id":      reporterID,
 		"priority":         "No Priority",
+		"rank":             rank,
 	}
 
-	stmt, err := r.db.PrepareNamedContext(ctx, insertQuery)
+	stmt, err := tx.PrepareNamedContext(ctx, insertQuery)
 	if err != nil {
-		rollback()
 		return uuid.Nil, fmt.Errorf("prepare insert story: %w", err)
 	}
 	var storyID uuid.UUID
 	if err := stmt.GetContext(ctx, &storyID, params); err != nil {
 		stmt.Close()
-		rollback()
 		return uuid.Nil, fmt.Errorf("insert story: %w", err)
 	}
 	stmt.Close()
 
-	if err := commit(); err != nil {
+	if err := tx.Commit(); err != nil {
 		return uuid.Nil, err
 	}
 	return storyID, nil
@@ -1040,7 +1177,7 @@ func (r *repo) UpdateStoryStatus(ctx context.Context, storyID uuid.UUID, workspa
 		"status_id": statusID,
 	}
 
-	return r.Update(ctx, storyID, workspaceID, updates)
+	return r.Update(ctx, storyID, workspaceID, updates, stories.CoreStoryWrite{})
 }
 
 // GetStatusCategory returns the category for a given status ID
@@ -0,0 +1,227 @@
+package storiesrepository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	customfields "github.com/complexus-tech/projects-api/internal/modules/customfields/service"
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// customFieldGroupPrefix marks a groupBy value that groups by a custom field.
+const customFieldGroupPrefix = stories.CustomFieldGroupByPrefix
+
+// customFieldValuesSelect aggregates a story's active custom field values into a
+// JSON object keyed by field ID.
+const customFieldValuesSelect = `
+	COALESCE(
+		(
+			SELECT jsonb_object_agg(cfv.field_id, cfv.value)
+			FROM story_custom_field_values cfv
+			INNER JOIN custom_fields cf ON cf.id = cfv.field_id AND cf.archived_at IS NULL
+			WHERE cfv.story_id = s.id
+		), '{}'
+	) AS custom_fields`
+
+type dbCustomField struct {
+	ID          uuid.UUID       `db:"id"`
+	WorkspaceID uuid.UUID       `db:"workspace_id"`
+	TeamID      *uuid.UUID      `db:"team_id"`
+	Name        string          `db:"name"`
+	Type        string          `db:"type"`
+	Options     json.RawMessage `db:"options"`
+	Required    bool            `db:"required"`
+	Position    int             `db:"position"`
+}
+
+// GetCustomFields returns the active custom fields that apply to a team.
+func (r *repo) GetCustomFields(ctx context.Context, workspaceID, teamID uuid.UUID) ([]customfields.CoreCustomField, error) {
+	r.log.Info(ctx, "business.repository.stories.GetCustomFields")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetCustomFields")
+	defer span.End()
+
+	query := `
+		SELECT id, workspace_id, team_id, name, type, options, required, position
+		FROM custom_fields
+		WHERE workspace_id = $1
+			AND archived_at IS NULL
+			AND (team_id IS NULL OR team_id = $2)
+		ORDER BY position ASC, lower(name) ASC
+	`
+
+	var rows []dbCustomField
+	if err := r.db.SelectContext(ctx, &rows, query, workspaceID, teamID); err != nil {
+		errMsg := fmt.Sprintf("failed to get custom fields: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get custom fields"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	fields := make([]customfields.CoreCustomField, len(rows))
+	for i, row := range rows {
+		options := []customfields.CoreOption{}
+		if err := json.Unmarshal(row.Options, &options); err != nil {
+			r.log.Error(ctx, fmt.Sprintf("failed to unmarshal custom field options: %s", err), "field_id", row.ID)
+		}
+		fields[i] = customfields.CoreCustomField{
+			ID:          row.ID,
+			WorkspaceID: row.WorkspaceID,
+			TeamID:      row.TeamID,
+			Name:        row.Name,
+			Type:        row.Type,
+			Options:     options,
+			Required:    row.Required,
+			Position:    row.Position,
+		}
+	}
+	return fields, nil
+}
+
+// setCustomFieldValues upserts custom field values for a story within tx. A
+// nil value removes the stored value.
+func (r *repo) setCustomFieldValues(ctx context.Context, tx sqlx.ExtContext, storyID, userID uuid.UUID, values map[uuid.UUID]json.RawMessage) error {
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.setCustomFieldValues")
+	defer span.End()
+
+	var actorID *uuid.UUID
+	if userID != uuid.Nil {
+		actorID = &userID
+	}
+
+	for fieldID, value := range values {
+		var err error
+		if value == nil {
+			_, err = tx.ExecContext(ctx, `
+				DELETE FROM story_custom_field_values
+				WHERE story_id = $1 AND field_id = $2
+			`, storyID, fieldID)
+		} else {
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO story_custom_field_values (story_id, field_id, value, updated_by, updated_at)
+				VALUES ($1, $2, $3, $4, NOW())
+				ON CONFLICT (story_id, field_id)
+				DO UPDATE SET value = EXCLUDED.value, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+			`, storyID, fieldID, []byte(value), actorID)
+		}
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to set custom field value: %s", err)
+			r.log.Error(ctx, errMsg, "story_id", storyID, "field_id", fieldID)
+			span.RecordError(errors.New("failed to set custom field value"), trace.WithAttributes(attribute.String("error", errMsg)))
+			return err
+		}
+	}
+	return nil
+}
+
+// customFieldWhereClauses returns one condition per custom field filter. The
+// parameters are added by addCustomFieldQueryParams.
+func customFieldWhereClauses(filters []stories.CoreCustomFieldFilter) []string {
+	clauses := make([]string, 0, len(filters))
+	for i, filter := range filters {
+		if filter.IsEmpty {
+			clauses = append(clauses, fmt.Sprintf(`NOT EXISTS (
+				SELECT 1 FROM story_custom_field_values cfv
+				WHERE cfv.story_id = s.id AND cfv.field_id = :cf_%d_field
+			)`, i))
+			continue
+		}
+		// String values and multi select arrays match on containment; numbers
+		// match on their text form.
+		clauses = append(clauses, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM story_custom_field_values cfv
+			WHERE cfv.story_id = s.id AND cfv.field_id = :cf_%[1]d_field
+			AND (
+				jsonb_exists_any(cfv.value, CAST(:cf_%[1]d_values AS text[]))
+				OR cfv.value #>> '{}' = ANY(CAST(:cf_%[1]d_values AS text[]))
+			)
+		)`, i))
+	}
+	return clauses
+}
+
+func addCustomFieldQueryParams(params map[string]any, filters []stories.CoreCustomFieldFilter) {
+	for i, filter := range filters {
+		params[fmt.Sprintf("cf_%d_field", i)] = filter.FieldID
+		if !filter.IsEmpty {
+			params[fmt.Sprintf("cf_%d_values", i)] = filter.Values
+		}
+	}
+}
+
+// parseCustomFieldGroupBy returns the field ID of a custom field groupBy value.
+func parseCustomFieldGroupBy(groupBy string) (uuid.UUID, bool) {
+	value, ok := strings.CutPrefix(groupBy, customFieldGroupPrefix)
+	if !ok {
+		return uuid.Nil, false
+	}
+	fieldID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return fieldID, true
+}
+
+// checkCustomFieldGroupable makes sure stories can be grouped by the field.
+func (r *repo) checkCustomFieldGroupable(ctx context.Context, fieldID, workspaceID uuid.UUID) error {
+	var fieldType string
+	err := r.db.GetContext(ctx, &fieldType, `
+		SELECT type FROM custom_fields
+		WHERE id = $1 AND workspace_id = $2 AND archived_at IS NULL
+	`, fieldID, workspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", stories.ErrUnknownCustomField, fieldID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get custom field: %w", err)
+	}
+	if fieldType == customfields.TypeMultiSelect {
+		return stories.ErrCustomFieldNotGroupable
+	}
+	return nil
+}
+
+// customFieldGroupColumn selects a story's value for the field as text.
+func customFieldGroupColumn(fieldID uuid.UUID) string {
+	return fmt.Sprintf(`(
+		SELECT cfv.value #>> '{}' FROM story_custom_field_values cfv
+		WHERE cfv.story_id = s.id AND cfv.field_id = '%s'
+	)`, fieldID)
+}
+
+// customFieldGroupsCTE lists the groups for a custom field: select options in
+// their defined order, or the distinct values in use for other types, followed
+// by the stories without a value.
+func customFieldGroupsCTE(fieldID uuid.UUID) string {
+	return fmt.Sprintf(`
+		SELECT opt.value->>'id' as group_key, opt.ordinality as sort_order
+		FROM custom_fields cf
+		CROSS JOIN LATERAL jsonb_array_elements(cf.options) WITH ORDINALITY AS opt(value, ordinality)
+		WHERE cf.id = '%[1]s'
+		AND cf.workspace_id = :workspace_id
+		AND cf.type = 'single_select'
+		UNION ALL
+		SELECT group_key, DENSE_RANK() OVER (ORDER BY value) as sort_order
+		FROM (
+			SELECT DISTINCT cfv.value #>> '{}' as group_key, cfv.value
+			FROM story_custom_field_values cfv
+			INNER JOIN custom_fields cf ON cf.id = cfv.field_id
+			INNER JOIN stories vs ON vs.id = cfv.story_id
+			WHERE cfv.field_id = '%[1]s'
+			AND cf.workspace_id = :workspace_id
+			AND cf.type <> 'single_select'
+			AND vs.workspace_id = :workspace_id
+			AND vs.deleted_at IS NULL
+		) used_values
+		UNION ALL
+		SELECT 'null' as group_key, CAST(9223372036854775807 AS bigint) as sort_order
+	`, fieldID)
+}
@@ -0,0 +1,212 @@
+package storiesrepository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// dependencyScopeColumns maps a dependency graph scope to the story column
+// that selects its stories.
+var dependencyScopeColumns = map[string]string{
+	stories.DependencyScopeStory:     "s.id",
+	stories.DependencyScopeEpic:      "s.epic_id",
+	stories.DependencyScopeObjective: "s.objective_id",
+	stories.DependencyScopeSprint:    "s.sprint_id",
+}
+
+type dbDependencyNode struct {
+	ID             uuid.UUID  `db:"id"`
+	SequenceID     int        `db:"sequence_id"`
+	Title          string     `db:"title"`
+	TeamID         uuid.UUID  `db:"team_id"`
+	TeamCode       string     `db:"team_code"`
+	StatusID       *uuid.UUID `db:"status_id"`
+	StatusCategory *string    `db:"status_category"`
+	AssigneeID     *uuid.UUID `db:"assignee_id"`
+	EstimateValue  *int16     `db:"estimate_unit"`
+	StartDate      *time.Time `db:"start_date"`
+	EndDate        *time.Time `db:"end_date"`
+	CompletedAt    *time.Time `db:"completed_at"`
+	InScope        bool       `db:"in_scope"`
+}
+
+type dbDependencyEdge struct {
+	ID        uuid.UUID `db:"id"`
+	BlockerID uuid.UUID `db:"from_story_id"`
+	BlockedID uuid.UUID `db:"to_story_id"`
+}
+
+// BlockingPathExists reports whether fromID blocks toID through a chain of
+// blocking links. The link ignoreAssociationID is not followed.
+func (r *repo) BlockingPathExists(ctx context.Context, fromID, toID, workspaceID, ignoreAssociationID uuid.UUID) (bool, error) {
+	r.log.Info(ctx, "business.repository.stories.BlockingPathExists")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.BlockingPathExists")
+	defer span.End()
+
+	exists, err := blockingPathExists(ctx, r.db, fromID, toID, workspaceID, ignoreAssociationID)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to check blocking path: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to check blocking path"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return false, err
+	}
+	return exists, nil
+}
+
+// blockingPathExists runs the BlockingPathExists query on q, so a caller
+// holding a transaction sees its own uncommitted links.
+func blockingPathExists(ctx context.Context, q sqlx.QueryerContext, fromID, toID, workspaceID, ignoreAssociationID uuid.UUID) (bool, error) {
+	query := `
+		WITH RECURSIVE blocked(story_id) AS (
+			SELECT $1::uuid
+			UNION
+			SELECT sa.to_story_id
+			FROM story_associations sa
+			INNER JOIN blocked b ON sa.from_story_id = b.story_id
+			WHERE sa.association_type = 'blocking'
+				AND sa.workspace_id = $3
+				AND sa.id <> $4
+		)
+		SELECT EXISTS (SELECT 1 FROM blocked WHERE story_id = $2)`
+
+	var exists bool
+	err := sqlx.GetContext(ctx, q, &exists, query, fromID, toID, workspaceID, ignoreAssociationID)
+	return exists, err
+}
+
+// GetDependencyGraph returns the stories in a scope together with every
+// story that transitively blocks or depends on them, and the blocking links
+// between those stories. Deleted stories are left out.
+func (r *repo) GetDependencyGraph(ctx context.Context, workspaceID uuid.UUID, scope string, scopeID uuid.UUID) ([]stories.CoreDependencyNode, []stories.CoreDependencyEdge, error) {
+	r.log.Info(ctx, "business.repository.stories.GetDependencyGraph")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetDependencyGraph")
+	defer span.End()
+
+	column, ok := dependencyScopeColumns[scope]
+	if !ok {
+		return nil, nil, stories.ErrInvalidDependencyScope
+	}
+
+	query := fmt.Sprintf(`
+		WITH RECURSIVE scoped AS (
+			SELECT s.id
+			FROM stories s
+			WHERE s.workspace_id = $1 AND %s = $2 AND s.deleted_at IS NULL
+		),
+		blockers(story_id) AS (
+			SELECT id FROM scoped
+			UNION
+			SELECT sa.from_story_id
+			FROM story_associations sa
+			INNER JOIN blockers b ON sa.to_story_id = b.story_id
+			INNER JOIN stories bs ON bs.id = sa.from_story_id AND bs.deleted_at IS NULL
+			WHERE sa.association_type = 'blocking' AND sa.workspace_id = $1
+		),
+		dependents(story_id) AS (
+			SELECT id FROM scoped
+			UNION
+			SELECT sa.to_story_id
+			FROM story_associations sa
+			INNER JOIN dependents d ON sa.from_story_id = d.story_id
+			INNER JOIN stories ds ON ds.id = sa.to_story_id AND ds.deleted_at IS NULL
+			WHERE sa.association_type = 'blocking' AND sa.workspace_id = $1
+		),
+		graph AS (
+			SELECT story_id FROM blockers
+			UNION
+			SELECT story_id FROM dependents
+		)
+		SELECT
+			s.id,
+			s.sequence_id,
+			s.title,
+			s.team_id,
+			t.code AS team_code,
+			s.status_id,
+			stat.category AS status_category,
+			s.assignee_id,
+			s.estimate_unit,
+			s.start_date,
+			s.end_date,
+			s.completed_at,
+			EXISTS (SELECT 1 FROM scoped WHERE scoped.id = s.id) AS in_scope
+		FROM graph g
+		INNER JOIN stories s ON s.id = g.story_id
+		INNER JOIN teams t ON s.team_id = t.team_id
+		LEFT JOIN statuses stat ON s.status_id = stat.status_id
+		ORDER BY t.code, s.sequence_id`, column)
+
+	var rows []dbDependencyNode
+	if err := r.db.SelectContext(ctx, &rows, query, workspaceID, scopeID); err != nil {
+		errMsg := fmt.Sprintf("failed to get dependency graph: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get dependency graph"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, nil, err
+	}
+
+	nodes := make([]stories.CoreDependencyNode, len(rows))
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		nodes[i] = stories.CoreDependencyNode{
+			ID:            row.ID,
+			SequenceID:    row.SequenceID,
+			Title:         row.Title,
+			TeamID:        row.TeamID,
+			TeamCode:      row.TeamCode,
+			StatusID:      row.StatusID,
+			AssigneeID:    row.AssigneeID,
+			EstimateValue: row.EstimateValue,
+			StartDate:     row.StartDate,
+			EndDate:       row.EndDate,
+			CompletedAt:   row.CompletedAt,
+			InScope:       row.InScope,
+		}
+		if row.StatusCategory != nil {
+			nodes[i].StatusCategory = *row.StatusCategory
+		}
+		ids[i] = row.ID
+	}
+	if len(ids) == 0 {
+		return nodes, []stories.CoreDependencyEdge{}, nil
+	}
+
+	var edgeRows []dbDependencyEdge
+	edgeQuery := `
+		SELECT id, from_story_id, to_story_id
+		FROM story_associations
+		WHERE association_type = 'blocking'
+			AND workspace_id = $1
+			AND from_story_id = ANY($2)
+			AND to_story_id = ANY($2)
+		ORDER BY created_at, id`
+	if err := r.db.SelectContext(ctx, &edgeRows, edgeQuery, workspaceID, ids); err != nil {
+		errMsg := fmt.Sprintf("failed to get dependency links: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get dependency links"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, nil, err
+	}
+
+	edges := make([]stories.CoreDependencyEdge, len(edgeRows))
+	for i, row := range edgeRows {
+		edges[i] = stories.CoreDependencyEdge{
+			ID:        row.ID,
+			BlockerID: row.BlockerID,
+			BlockedID: row.BlockedID,
+		}
+	}
+
+	span.AddEvent("dependency graph retrieved", trace.WithAttributes(
+		attribute.Int("graph.nodes", len(nodes)),
+		attribute.Int("graph.edges", len(edges)),
+	))
+	return nodes, edges, nil
+}
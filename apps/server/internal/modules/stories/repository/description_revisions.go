@@ -0,0 +1,125 @@
+package storiesrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type dbDescriptionRevision struct {
+	ID              uuid.UUID  `db:"revision_id"`
+	StoryID         uuid.UUID  `db:"story_id"`
+	WorkspaceID     uuid.UUID  `db:"workspace_id"`
+	Description     *string    `db:"description"`
+	DescriptionHTML *string    `db:"description_html"`
+	EditedBy        *uuid.UUID `db:"edited_by"`
+	Reason          *string    `db:"reason"`
+	CreatedAt       time.Time  `db:"created_at"`
+}
+
+const descriptionRevisionColumns = `
+	revision_id, story_id, workspace_id, description, description_html,
+	edited_by, reason, created_at`
+
+// saveDescriptionRevision saves the description revision of write in tx.
+// When write has an original description and the story has no revisions
+// yet, the original is saved first so the history includes the description
+// from before the first tracked change.
+func saveDescriptionRevision(ctx context.Context, tx sqlx.ExecerContext, write stories.CoreStoryWrite) error {
+	revision, original := write.DescriptionRevision, write.OriginalDescription
+	if revision == nil {
+		return nil
+	}
+
+	if original != nil {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO story_description_revisions (story_id, workspace_id, description, description_html, created_at)
+			SELECT $1, $2, $3, $4, $5
+			WHERE NOT EXISTS (
+				SELECT 1 FROM story_description_revisions WHERE story_id = $1
+			)`,
+			original.StoryID, original.WorkspaceID, original.Description, original.DescriptionHTML, original.CreatedAt); err != nil {
+			return fmt.Errorf("failed to save original description: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO story_description_revisions (story_id, workspace_id, description, description_html, edited_by, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		revision.StoryID, revision.WorkspaceID, revision.Description, revision.DescriptionHTML, revision.EditedBy, revision.Reason); err != nil {
+		return fmt.Errorf("failed to save description revision: %w", err)
+	}
+	return nil
+}
+
+// ListDescriptionRevisions returns a story's description revisions, newest
+// first.
+func (r *repo) ListDescriptionRevisions(ctx context.Context, storyID, workspaceID uuid.UUID) ([]stories.CoreDescriptionRevision, error) {
+	r.log.Info(ctx, "business.repository.stories.ListDescriptionRevisions")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.ListDescriptionRevisions")
+	defer span.End()
+
+	var rows []dbDescriptionRevision
+	query := `
+		SELECT ` + descriptionRevisionColumns + `
+		FROM story_description_revisions
+		WHERE story_id = $1 AND workspace_id = $2
+		ORDER BY created_at DESC, revision_id`
+	if err := r.db.SelectContext(ctx, &rows, query, storyID, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to list description revisions: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to list description revisions"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	revisions := make([]stories.CoreDescriptionRevision, len(rows))
+	for i, row := range rows {
+		revisions[i] = toCoreDescriptionRevision(row)
+	}
+	return revisions, nil
+}
+
+// GetDescriptionRevision returns a description revision in the workspace.
+func (r *repo) GetDescriptionRevision(ctx context.Context, revisionID, workspaceID uuid.UUID) (stories.CoreDescriptionRevision, error) {
+	r.log.Info(ctx, "business.repository.stories.GetDescriptionRevision")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetDescriptionRevision")
+	defer span.End()
+
+	var row dbDescriptionRevision
+	query := `
+		SELECT ` + descriptionRevisionColumns + `
+		FROM story_description_revisions
+		WHERE revision_id = $1 AND workspace_id = $2`
+	if err := r.db.GetContext(ctx, &row, query, revisionID, workspaceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return stories.CoreDescriptionRevision{}, stories.ErrDescriptionRevisionNotFound
+		}
+		errMsg := fmt.Sprintf("failed to get description revision: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get description revision"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return stories.CoreDescriptionRevision{}, err
+	}
+	return toCoreDescriptionRevision(row), nil
+}
+
+func toCoreDescriptionRevision(row dbDescriptionRevision) stories.CoreDescriptionRevision {
+	return stories.CoreDescriptionRevision{
+		ID:              row.ID,
+		StoryID:         row.StoryID,
+		WorkspaceID:     row.WorkspaceID,
+		Description:     row.Description,
+		DescriptionHTML: row.DescriptionHTML,
+		EditedBy:        row.EditedBy,
+		Reason:          row.Reason,
+		CreatedAt:       row.CreatedAt,
+	}
+}
@@ -0,0 +1,34 @@
+package storiesrepository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EpicInTeam reports whether epicID is an epic of the team in the workspace.
+func (r *repo) EpicInTeam(ctx context.Context, epicID, workspaceID, teamID uuid.UUID) (bool, error) {
+	r.log.Info(ctx, "business.repository.stories.EpicInTeam")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.EpicInTeam")
+	defer span.End()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM epics
+			WHERE id = $1 AND workspace_id = $2 AND team_id = $3
+		)`
+
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, query, epicID, workspaceID, teamID); err != nil {
+		errMsg := fmt.Sprintf("failed to check story epic: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to check story epic"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return false, err
+	}
+	return exists, nil
+}
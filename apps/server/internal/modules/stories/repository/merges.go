@@ -0,0 +1,263 @@
+package storiesrepository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/outbox"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type dbMergeStory struct {
+	ID         uuid.UUID  `db:"id"`
+	TeamID     uuid.UUID  `db:"team_id"`
+	TeamCode   string     `db:"team_code"`
+	SequenceID int        `db:"sequence_id"`
+	ParentID   *uuid.UUID `db:"parent_id"`
+}
+
+// MergeStory moves everything attached to the duplicate story onto the
+// canonical one in a single transaction, links the two as duplicates,
+// moves the duplicate to merge.CancelledStatusID and points it, and every
+// story merged into it before, at the canonical story, writing outboxEvents
+// with it. Rows the canonical story already has, such as a label both carry, stay
+// with the duplicate. The merge fails with ErrDependencyCycle when the moved
+// blocking links would make the canonical story block itself.
+func (r *repo) MergeStory(ctx context.Context, merge stories.CoreStoryMerge, outboxEvents ...events.Event) (stories.CoreStoryMerge, error) {
+	r.log.Info(ctx, "business.repository.stories.MergeStory")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.MergeStory")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return stories.CoreStoryMerge{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var rows []dbMergeStory
+	lockQuery := `
+		SELECT s.id, s.team_id, t.code AS team_code, s.sequence_id, s.parent_id
+		FROM stories s
+		INNER JOIN teams t ON t.team_id = s.team_id
+		WHERE s.id = ANY($1) AND s.workspace_id = $2 AND s.deleted_at IS NULL
+		ORDER BY s.id
+		FOR UPDATE OF s`
+	if err := tx.SelectContext(ctx, &rows, lockQuery, []uuid.UUID{merge.DuplicateID, merge.CanonicalID}, merge.WorkspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to load stories to merge: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to load stories to merge"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return stories.CoreStoryMerge{}, err
+	}
+	if len(rows) != 2 {
+		return stories.CoreStoryMerge{}, stories.ErrNotFound
+	}
+	duplicate, canonical := rows[0], rows[1]
+	if duplicate.ID != merge.DuplicateID {
+		duplicate, canonical = canonical, duplicate
+	}
+
+	// $1 is the duplicate story and $2 the canonical one throughout.
+	moves := []struct {
+		name  string
+		query string
+		count *int
+	}{
+		{"comments", `
+			UPDATE story_comments SET story_id = $2
+			WHERE story_id = $1`, &merge.Comments},
+		{"attachments", `
+			UPDATE story_attachments a SET story_id = $2
+			WHERE a.story_id = $1
+				AND NOT EXISTS (
+					SELECT 1 FROM story_attachments c
+					WHERE c.story_id = $2 AND c.attachment_id = a.attachment_id
+				)`, &merge.Attachments},
+		{"links", `
+			UPDATE story_links l SET story_id = $2, updated_at = NOW()
+			WHERE l.story_id = $1
+				AND NOT EXISTS (
+					SELECT 1 FROM story_links c
+					WHERE c.story_id = $2 AND c.url = l.url
+				)`, &merge.Links},
+		{"document links", `
+			UPDATE document_links d SET story_id = $2
+			WHERE d.story_id = $1
+				AND NOT EXISTS (
+					SELECT 1 FROM document_links c
+					WHERE c.story_id = $2 AND c.document_id = d.document_id
+				)`, &merge.Links},
+		{"labels", `
+			UPDATE story_labels l SET story_id = $2
+			WHERE l.story_id = $1
+				AND NOT EXISTS (
+					SELECT 1 FROM story_labels c
+					WHERE c.story_id = $2 AND c.label_id = l.label_id
+				)`, &merge.Labels},
+		{"github links", `
+			UPDATE github_story_links g SET story_id = $2, updated_at = NOW()
+			WHERE g.story_id = $1
+				AND NOT EXISTS (
+					SELECT 1 FROM github_story_links c
+					WHERE c.story_id = $2
+						AND c.repository_id = g.repository_id
+						AND c.external_type = g.external_type
+						AND COALESCE(c.github_id, 0) = COALESCE(g.github_id, 0)
+						AND COALESCE(c.ref_name, '') = COALESCE(g.ref_name, '')
+				)`, &merge.GitHubLinks},
+		{"github comment links", `
+			UPDATE github_comment_links SET story_id = $2
+			WHERE story_id = $1`, nil},
+		{"feedback links", `
+			UPDATE feedback_story_links f SET story_id = $2
+			WHERE f.story_id = $1
+				AND NOT EXISTS (
+					SELECT 1 FROM feedback_story_links c
+					WHERE c.story_id = $2 AND c.item_id = f.item_id
+				)`, &merge.FeedbackLinks},
+		{"watchers", `
+			INSERT INTO story_watchers (story_id, user_id, workspace_id, source)
+			SELECT $2, user_id, workspace_id, source
+			FROM story_watchers
+			WHERE story_id = $1
+			ON CONFLICT (story_id, user_id) DO NOTHING`, &merge.Watchers},
+		{"outgoing associations", `
+			UPDATE story_associations a SET from_story_id = $2
+			WHERE a.from_story_id = $1 AND a.to_story_id <> $2
+				AND NOT EXISTS (
+					SELECT 1 FROM story_associations c
+					WHERE c.from_story_id = $2 AND c.to_story_id = a.to_story_id
+						AND c.association_type = a.association_type
+				)`, nil},
+		{"incoming associations", `
+			UPDATE story_associations a SET to_story_id = $2
+			WHERE a.to_story_id = $1 AND a.from_story_id <> $2
+				AND NOT EXISTS (
+					SELECT 1 FROM story_associations c
+					WHERE c.to_story_id = $2 AND c.from_story_id = a.from_story_id
+						AND c.association_type = a.association_type
+				)`, nil},
+		{"remaining associations", `
+			DELETE FROM story_associations
+			WHERE from_story_id = $1 OR to_story_id = $1`, nil},
+		{"sub-stories", `
+			UPDATE stories SET parent_id = $2, updated_at = NOW()
+			WHERE parent_id = $1 AND id <> $2`, nil},
+		{"merged stories", `
+			UPDATE stories SET merged_into_id = $2
+			WHERE merged_into_id = $1`, nil},
+		{"time entries", `
+			UPDATE time_entries SET story_id = $2, updated_at = NOW()
+			WHERE story_id = $1`, &merge.TimeEntries},
+		{"description revisions", `
+			UPDATE story_description_revisions SET story_id = $2
+			WHERE story_id = $1`, nil},
+		{"custom field values", `
+			UPDATE story_custom_field_values v SET story_id = $2, updated_at = NOW()
+			WHERE v.story_id = $1
+				AND NOT EXISTS (
+					SELECT 1 FROM story_custom_field_values c
+					WHERE c.story_id = $2 AND c.field_id = v.field_id
+				)`, nil},
+	}
+	for _, move := range moves {
+		count, err := execCount(ctx, tx, move.query, merge.DuplicateID, merge.CanonicalID)
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to merge story %s: %s", move.name, err)
+			r.log.Error(ctx, errMsg, "story_id", merge.DuplicateID)
+			span.RecordError(errors.New("failed to merge story"), trace.WithAttributes(attribute.String("error", errMsg)))
+			return stories.CoreStoryMerge{}, err
+		}
+		if move.count != nil {
+			*move.count += count
+		}
+	}
+
+	// The stories were acyclic before, so a cycle now runs through one of the
+	// canonical story's blocking links, moved or not.
+	var blockedIDs []uuid.UUID
+	blockedQuery := `
+		SELECT to_story_id FROM story_associations
+		WHERE from_story_id = $1 AND association_type = 'blocking' AND workspace_id = $2`
+	if err := tx.SelectContext(ctx, &blockedIDs, blockedQuery, canonical.ID, merge.WorkspaceID); err != nil {
+		r.log.Error(ctx, fmt.Sprintf("failed to load blocked stories: %s", err), "story_id", canonical.ID)
+		return stories.CoreStoryMerge{}, err
+	}
+	for _, blockedID := range blockedIDs {
+		cycle, err := blockingPathExists(ctx, tx, blockedID, canonical.ID, merge.WorkspaceID, uuid.Nil)
+		if err != nil {
+			r.log.Error(ctx, fmt.Sprintf("failed to check blocking path: %s", err), "story_id", canonical.ID)
+			return stories.CoreStoryMerge{}, err
+		}
+		if cycle {
+			return stories.CoreStoryMerge{}, stories.ErrDependencyCycle
+		}
+	}
+
+	// A canonical sub-story of the duplicate takes the duplicate's place.
+	if canonical.ParentID != nil && *canonical.ParentID == duplicate.ID {
+		if _, err := tx.ExecContext(ctx, `UPDATE stories SET parent_id = $2 WHERE id = $1`, canonical.ID, duplicate.ParentID); err != nil {
+			r.log.Error(ctx, fmt.Sprintf("failed to reparent canonical story: %s", err), "story_id", canonical.ID)
+			return stories.CoreStoryMerge{}, err
+		}
+	}
+
+	associationQuery := `
+		INSERT INTO story_associations (from_story_id, to_story_id, association_type, workspace_id)
+		VALUES ($1, $2, 'duplicate', $3)`
+	if _, err := tx.ExecContext(ctx, associationQuery, duplicate.ID, canonical.ID, merge.WorkspaceID); err != nil {
+		r.log.Error(ctx, fmt.Sprintf("failed to link merged stories: %s", err), "story_id", duplicate.ID)
+		return stories.CoreStoryMerge{}, err
+	}
+
+	// The duplicate redirects to the canonical story, which stops redirecting
+	// if it was merged away before, so a reference never follows a cycle.
+	closeQuery := `
+		UPDATE stories
+		SET status_id = CASE WHEN id = $1 THEN $3 ELSE status_id END,
+			merged_into_id = CASE WHEN id = $1 THEN $2::uuid END,
+			updated_at = NOW()
+		WHERE id IN ($1, $2)`
+	if _, err := tx.ExecContext(ctx, closeQuery, duplicate.ID, canonical.ID, merge.CancelledStatusID); err != nil {
+		r.log.Error(ctx, fmt.Sprintf("failed to close duplicate story: %s", err), "story_id", duplicate.ID)
+		return stories.CoreStoryMerge{}, err
+	}
+
+	if err := outbox.Write(ctx, tx, outboxEvents...); err != nil {
+		r.log.Error(ctx, fmt.Sprintf("failed to write merge events: %s", err), "story_id", duplicate.ID)
+		span.RecordError(err)
+		return stories.CoreStoryMerge{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return stories.CoreStoryMerge{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	merge.DuplicateRef = fmt.Sprintf("%s-%d", duplicate.TeamCode, duplicate.SequenceID)
+	merge.CanonicalRef = fmt.Sprintf("%s-%d", canonical.TeamCode, canonical.SequenceID)
+	span.AddEvent("stories merged", trace.WithAttributes(
+		attribute.String("story.duplicate_ref", merge.DuplicateRef),
+		attribute.String("story.canonical_ref", merge.CanonicalRef),
+	))
+	return merge, nil
+}
+
+// execCount runs a statement in tx and returns the number of rows it
+// affected.
+func execCount(ctx context.Context, tx *sqlx.Tx, query string, args ...any) (int, error) {
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
@@ -46,6 +46,8 @@ type dbStory struct {
 	KeyResult            *uuid.UUID       `db:"key_result_id"`
 	StartDate            *time.Time       `db:"start_date"`
 	EndDate              *time.Time       `db:"end_date"`
+	Rank                 *string          `db:"rank"`
+	MergedInto           *uuid.UUID       `db:"merged_into_id"`
 	CreatedAt            time.Time        `db:"created_at"`
 	UpdatedAt            time.Time        `db:"updated_at"`
 	DeletedAt            *time.Time       `db:"deleted_at"`
@@ -54,6 +56,8 @@ type dbStory struct {
 	SubStories           *json.RawMessage `db:"sub_stories"`
 	Labels               *json.RawMessage `db:"labels"`
 	Associations         *json.RawMessage `db:"associations"`
+	CustomFields         *json.RawMessage `db:"custom_fields"`
+	Watchers             *json.RawMessage `db:"watchers"`
 }
 
 func toCoreTeamSummary(story dbStory) *stories.CoreTeamSummary {
@@ -121,6 +125,22 @@ func toCoreStory(i dbStory) stories.CoreSingleStory {
 		}
 	}
 
+	var watchers []uuid.UUID
+	if i.Watchers != nil {
+		err := json.Unmarshal(*i.Watchers, &watchers)
+		if err != nil {
+			log.Printf("Failed to unmarshal watchers: %s", err)
+		}
+	}
+
+	customFields := map[uuid.UUID]json.RawMessage{}
+	if i.CustomFields != nil {
+		err := json.Unmarshal(*i.CustomFields, &customFields)
+		if err != nil {
+			log.Printf("Failed to unmarshal custom_fields: %s", err)
+		}
+	}
+
 	return stories.CoreSingleStory{
 		ID:              i.ID,
 		SequenceID:      i.SequenceID,
@@ -132,6 +152,7 @@ func toCoreStory(i dbStory) stories.CoreSingleStory {
 		EstimateLabel:   stories.EstimateLabelFromValue(i.EstimateScheme, i.EstimateValue),
 		Parent:          i.Parent,
 		Objective:       i.Objective,
+		Epic:            i.Epic,
 		Team:            i.Team,
 		Workspace:       i.Workspace,
 		Status:          i.Status,
@@ -152,9 +173,12 @@ func toCoreStory(i dbStory) stories.CoreSingleStory {
 		DeletedAt:       i.DeletedAt,
 		ArchivedAt:      i.ArchivedAt,
 		CompletedAt:     i.CompletedAt,
+		MergedInto:      i.MergedInto,
 		SubStories:      subStories,
 		Labels:          labels,
 		Associations:    associations,
+		Watchers:        watchers,
+		CustomFields:    customFields,
 	}
 }
 
@@ -225,6 +249,7 @@ func toDBStory(i stories.CoreSingleStory) dbStory {
 		EstimateValue:   i.EstimateValue,
 		Parent:          i.Parent,
 		Objective:       i.Objective,
+		Epic:            i.Epic,
 		Workspace:       i.Workspace,
 		Team:            i.Team,
 		Status:          i.Status,
@@ -368,6 +393,14 @@ func toCoreComment(i commentsrepository.DbComment) comments.CoreComment {
 		}
 	}
 
+	reactions := []comments.CoreReaction{}
+	if i.Reactions != nil {
+		err := json.Unmarshal(*i.Reactions, &reactions)
+		if err != nil {
+			log.Printf("Failed to unmarshal reactions: %s", err)
+		}
+	}
+
 	return comments.CoreComment{
 		ID:          i.ID,
 		StoryID:     i.StoryID,
@@ -376,7 +409,12 @@ func toCoreComment(i commentsrepository.DbComment) comments.CoreComment {
 		Comment:     i.Comment,
 		CreatedAt:   i.CreatedAt,
 		UpdatedAt:   i.UpdatedAt,
+		EditedAt:    i.EditedAt,
+		EditedBy:    i.EditedBy,
+		ResolvedAt:  i.ResolvedAt,
+		ResolvedBy:  i.ResolvedBy,
 		SubComments: subComments,
+		Reactions:   reactions,
 	}
 }
 
@@ -391,6 +429,7 @@ func toCoreComments(is []commentsrepository.DbComment) []comments.CoreComment {
 
 func toDBNewComment(i stories.CoreNewComment) commentsrepository.DbNewComment {
 	return commentsrepository.DbNewComment{
+		ID:       i.ID,
 		StoryID:  i.StoryID,
 		Parent:   i.Parent,
 		UserID:   i.UserID,
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"sort"
 	"strings"
 	"time"
@@ -130,6 +131,7 @@ func (r *repo) MyStories(ctx context.Context, workspaceId uuid.UUID) ([]stories.
 			t.code AS team_code,
 			t.name AS team_name,
 			s.objective_id,
+			s.epic_id,
 			o.name AS objective_name,
 			o.description AS objective_description,
 			s.workspace_id,
@@ -155,6 +157,7 @@ func (r *repo) MyStories(ctx context.Context, workspaceId uuid.UUID) ([]stories.
 								'sprint_id', sub.sprint_id,
 								'team_id', sub.team_id,
 								'objective_id', sub.objective_id,
+								'epic_id', sub.epic_id,
 								'workspace_id', sub.workspace_id,
 								'assignee_id', sub.assignee_id,
 								'reporter_id', sub.reporter_id,
@@ -253,6 +256,7 @@ func (r *repo) getStoryById(ctx context.Context, id uuid.UUID, workspaceId uuid.
 					s.parent_id,
 					s.team_id,
 					s.objective_id,
+					s.epic_id,
 					s.sprint_id,
 					sp.name AS sprint_name,
 					sp.goal AS sprint_goal,
@@ -269,6 +273,7 @@ func (r *repo) getStoryById(ctx context.Context, id uuid.UUID, workspaceId uuid.
 					s.deleted_at,
 					s.archived_at,
 					s.completed_at,
+					s.merged_into_id,
 					COALESCE(
 							(
 									SELECT
@@ -310,6 +315,7 @@ func (r *repo) getStoryById(ctx context.Context, id uuid.UUID, workspaceId uuid.
 												'sprint_id', other.sprint_id,
 												'team_id', other.team_id,
 												'objective_id', other.objective_id,
+												'epic_id', other.epic_id,
 												'workspace_id', other.workspace_id,
 												'assignee_id', other.assignee_id,
 												'reporter_id', other.reporter_id,
@@ -335,7 +341,9 @@ func (r *repo) getStoryById(ctx context.Context, id uuid.UUID, workspaceId uuid.
 							WHERE
 								sa.from_story_id = s.id OR sa.to_story_id = s.id
 						), '[]'
-					) AS associations
+					) AS associations,
+					` + customFieldValuesSelect + `,
+					` + watchersSelect + `
 				FROM
 					stories s
 					INNER JOIN teams t ON s.team_id = t.team_id
@@ -388,7 +396,8 @@ func (r *repo) List(ctx context.Context, workspaceId uuid.UUID, filters map[stri
 			key == "updated_before" || key == "start_date_after" || key == "start_date_before" ||
 			key == "deadline_after" || key == "deadline_before" ||
 			key == "assigned_to_me" || key == "created_by_me" || key == "has_no_assignee" ||
-			key == "has_blocked_by" || key == "current_user_id" || key == "show_sub_stories" {
+			key == "has_blocked_by" || key == "sla_breached" || key == "association_types" || key == "current_user_id" || key == "show_sub_stories" ||
+			key == "custom_fields" || key == "has_no_sprint" || key == "has_no_epic" || key == "exclude" {
 			hasComplexFilters = true
 			break
 		}
@@ -447,6 +456,7 @@ func (r *repo) List(ctx context.Context, workspaceId uuid.UUID, filters map[stri
 			t.code AS team_code,
 			t.name AS team_name,
 			s.objective_id,
+			s.epic_id,
 			o.name AS objective_name,
 			o.description AS objective_description,
 			s.workspace_id,
@@ -569,9 +579,21 @@ func mapToCoreFilters(filters map[string]any, workspaceId uuid.UUID) stories.Cor
 	if hasNoAssignee, ok := filters["has_no_assignee"].(bool); ok {
 		coreFilters.HasNoAssignee = &hasNoAssignee
 	}
+	if hasNoSprint, ok := filters["has_no_sprint"].(bool); ok {
+		coreFilters.HasNoSprint = &hasNoSprint
+	}
+	if hasNoEpic, ok := filters["has_no_epic"].(bool); ok {
+		coreFilters.HasNoEpic = &hasNoEpic
+	}
 	if hasBlockedBy, ok := filters["has_blocked_by"].(bool); ok {
 		coreFilters.HasBlockedBy = &hasBlockedBy
 	}
+	if slaBreached, ok := filters["sla_breached"].(bool); ok {
+		coreFilters.SLABreached = &slaBreached
+	}
+	if associationTypes, ok := filters["association_types"].([]string); ok {
+		coreFilters.AssociationTypes = associationTypes
+	}
 	if assignedToMe, ok := filters["assigned_to_me"].(bool); ok {
 		coreFilters.AssignedToMe = &assignedToMe
 	}
@@ -617,6 +639,12 @@ func mapToCoreFilters(filters map[string]any, workspaceId uuid.UUID) stories.Cor
 	if includeArchived, ok := filters["include_archived"].(bool); ok {
 		coreFilters.IncludeArchived = &includeArchived
 	}
+	if customFields, ok := filters["custom_fields"].([]stories.CoreCustomFieldFilter); ok {
+		coreFilters.CustomFields = customFields
+	}
+	if exclude, ok := filters["exclude"].(*stories.CoreStoryExclusions); ok {
+		coreFilters.Exclude = exclude
+	}
 
 	return coreFilters
 }
@@ -649,6 +677,7 @@ func (r *repo) getSubStories(ctx context.Context, parentId uuid.UUID, workspaceI
 					sprint_id,
 					team_id,
 					objective_id,
+					epic_id,
 					workspace_id,
 					assignee_id,
 					reporter_id,
@@ -759,7 +788,7 @@ SELECT
 	return toCoreActivitiesWithUser(activities), hasMore, nil
 }
 
-func (r *repo) GetComments(ctx context.Context, storyID uuid.UUID, page, pageSize int) ([]comments.CoreComment, bool, error) {
+func (r *repo) GetComments(ctx context.Context, storyID uuid.UUID, resolved *bool, page, pageSize int) ([]comments.CoreComment, bool, error) {
 	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetComments")
 	defer span.End()
 
@@ -769,6 +798,7 @@ func (r *repo) GetComments(ctx context.Context, storyID uuid.UUID, page, pageSiz
 
 	params := map[string]any{
 		"story_id": storyID,
+		"resolved": resolved,
 		"limit":    limit,
 		"offset":   offset,
 	}
@@ -782,18 +812,24 @@ func (r *repo) GetComments(ctx context.Context, storyID uuid.UUID, page, pageSiz
 			sc.parent_id,
 			sc.created_at,
 			sc.updated_at,
+			sc.edited_at,
+			sc.edited_by,
+			sc.resolved_at,
+			sc.resolved_by,
 			COALESCE(
 				(
 					SELECT
-						json_agg(sub.*)
+						json_agg(to_jsonb(sub.*) || jsonb_build_object('reactions', ` + commentReactionsSelect("sub") + `))
 					FROM
 						story_comments sub
 					WHERE
 						sub.parent_id = sc.comment_id			
 				), '[]'
-			) AS sub_comments
+			) AS sub_comments,
+			` + commentReactionsSelect("sc") + ` AS reactions
 		FROM story_comments sc 
 		WHERE sc.story_id = :story_id AND sc.parent_id IS NULL 
+			AND (CAST(:resolved AS boolean) IS NULL OR (sc.resolved_at IS NOT NULL) = CAST(:resolved AS boolean))
 		ORDER BY sc.created_at DESC
 		LIMIT :limit OFFSET :offset
 	`
@@ -833,7 +869,8 @@ func (r *repo) GetComment(ctx context.Context, commentID uuid.UUID) (comments.Co
 	defer span.End()
 
 	q := `
-		SELECT comment_id, story_id, commenter_id, content, parent_id, created_at, updated_at
+		SELECT comment_id, story_id, commenter_id, content, parent_id, created_at, updated_at,
+			edited_at, edited_by, resolved_at, resolved_by
 		FROM story_comments 
 		WHERE comment_id = :comment_id
 	`
@@ -849,6 +886,9 @@ func (r *repo) GetComment(ctx context.Context, commentID uuid.UUID) (comments.Co
 
 	var comment commentsrepository.DbComment
 	if err := stmt.GetContext(ctx, &comment, params); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return comments.CoreComment{}, stories.ErrCommentNotFound
+		}
 		r.log.Error(ctx, fmt.Sprintf("failed to get comment: %s", err))
 		return comments.CoreComment{}, fmt.Errorf("failed to get comment: %w", err)
 	}
@@ -905,6 +945,12 @@ func (r *repo) ListGroupedStories(ctx context.Context, query stories.CoreStoryQu
 		return r.listGroupedStoriesNone(ctx, query)
 	}
 
+	if fieldID, ok := parseCustomFieldGroupBy(query.GroupBy); ok {
+		if err := r.checkCustomFieldGroupable(ctx, fieldID, query.Filters.WorkspaceID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Use SQL-based grouping for better performance with large datasets
 	if r.shouldUseSQLGrouping(query) {
 		return r.listGroupedStoriesSQL(ctx, query)
@@ -918,10 +964,11 @@ func (r *repo) ListGroupedStories(ctx context.Context, query stories.CoreStoryQu
 func (r *repo) shouldUseSQLGrouping(query stories.CoreStoryQuery) bool {
 	// Always use optimized SQL grouping for better performance
 	switch query.GroupBy {
-	case "status", "assignee", "priority", "team", "sprint":
+	case "status", "assignee", "priority", "team", "sprint", "epic":
 		return true
 	default:
-		return false
+		_, isCustomField := parseCustomFieldGroupBy(query.GroupBy)
+		return isCustomField
 	}
 }
 
@@ -967,11 +1014,6 @@ func (r *repo) listGroupedStoriesSQL(ctx context.Context, query stories.CoreStor
 		jsonAggOrder = r.buildOrderByClauseWithAlias(query.OrderBy, query.OrderDirection, "ls")
 	}
 
-	// Build the join clauses for categories filter
-	var joinClauses string
-	if len(query.Filters.Categories) > 0 {
-		joinClauses = "INNER JOIN statuses stat ON s.status_id = stat.status_id"
-	}
 	subStoriesJSONExpr := r.buildSubStoriesJSONExpr("ls", true)
 
 	// Simplified query that includes all possible groups
@@ -998,6 +1040,7 @@ func (r *repo) listGroupedStoriesSQL(ctx context.Context, query stories.CoreStor
 				t.code AS team_code,
 				t.name AS team_name,
 				s.objective_id,
+				s.epic_id,
 				o.name AS objective_name,
 				o.description AS objective_description,
 				s.workspace_id,
@@ -1008,6 +1051,7 @@ func (r *repo) listGroupedStoriesSQL(ctx context.Context, query stories.CoreStor
 				s.completed_at,
 				s.deleted_at,
 				s.archived_at,
+				s.rank,
 				COALESCE(CAST(%s AS text), 'null') as group_key,
 				COUNT(*) OVER (PARTITION BY COALESCE(CAST(%s AS text), 'null')) as total_count,
 				ROW_NUMBER() OVER (PARTITION BY COALESCE(CAST(%s AS text), 'null') ORDER BY %s) as row_num
@@ -1016,7 +1060,6 @@ func (r *repo) listGroupedStoriesSQL(ctx context.Context, query stories.CoreStor
 			LEFT JOIN objectives o ON s.objective_id = o.objective_id
 			LEFT JOIN sprints sp ON s.sprint_id = sp.sprint_id
 			%s
-			%s
 		),
 		limited_stories AS (
 			SELECT *
@@ -1046,6 +1089,7 @@ func (r *repo) listGroupedStoriesSQL(ctx context.Context, query stories.CoreStor
 						'team_code', ls.team_code,
 						'team_name', ls.team_name,
 						'objective_id', ls.objective_id,
+						'epic_id', ls.epic_id,
 						'objective_name', ls.objective_name,
 						'objective_description', ls.objective_description,
 						'workspace_id', ls.workspace_id,
@@ -1078,7 +1122,6 @@ func (r *repo) listGroupedStoriesSQL(ctx context.Context, query stories.CoreStor
 		ORDER BY ag.sort_order, ag.group_key`,
 		allGroupsCTE,
 		groupColumn, groupColumn, groupColumn, rowNumberOrder,
-		joinClauses,
 		r.buildSimpleWhereClause(query.Filters),
 		limit, subStoriesJSONExpr, jsonAggOrder)
 
@@ -1252,6 +1295,15 @@ func (r *repo) listGroupedStoriesNone(ctx context.Context, query stories.CoreSto
 
 // buildSimpleWhereClause builds a simplified WHERE clause without subqueries
 func (r *repo) buildSimpleWhereClause(filters stories.CoreStoryFilters) string {
+	whereClauses := append(storyScopeWhereClauses(filters), storyFilterWhereClauses(filters)...)
+	return "WHERE " + strings.Join(whereClauses, " AND ")
+}
+
+// storyScopeWhereClauses limits stories to the workspace, to archived or
+// active and deleted or live stories, to top-level stories unless a parent
+// or sub-stories are asked for, to the user's teams unless teams are given,
+// and to the user's own stories when asked.
+func storyScopeWhereClauses(filters stories.CoreStoryFilters) []string {
 	whereClauses := []string{
 		"s.workspace_id = :workspace_id",
 	}
@@ -1271,61 +1323,16 @@ func (r *repo) buildSimpleWhereClause(filters stories.CoreStoryFilters) string {
 		whereClauses = append(whereClauses, "s.deleted_at IS NULL")
 	}
 
-	// Handle parent filtering
-	if filters.Parent != nil {
-		whereClauses = append(whereClauses, "s.parent_id = :parent_id")
-	} else if filters.ShowSubStories == nil || !*filters.ShowSubStories {
-		// Default: only show top-level stories (no parent)
+	// Default: only show top-level stories (no parent)
+	if filters.Parent == nil && (filters.ShowSubStories == nil || !*filters.ShowSubStories) {
 		whereClauses = append(whereClauses, "s.parent_id IS NULL")
 	}
 
-	// Add filter conditions
-	if len(filters.StatusIDs) > 0 {
-		whereClauses = append(whereClauses, "s.status_id = ANY(:status_ids)")
-	}
-
-	if len(filters.AssigneeIDs) > 0 {
-		whereClauses = append(whereClauses, "s.assignee_id = ANY(:assignee_ids)")
-	}
-
-	if len(filters.ReporterIDs) > 0 {
-		whereClauses = append(whereClauses, "s.reporter_id = ANY(:reporter_ids)")
-	}
-
-	if filters.TitleContains != nil {
-		whereClauses = append(whereClauses, "(s.title ILIKE '%' || :title_contains || '%' OR s.description ILIKE '%' || :title_contains || '%' OR s.description_html ILIKE '%' || :title_contains || '%')")
-	}
-
-	if len(filters.Priorities) > 0 {
-		whereClauses = append(whereClauses, "s.priority = ANY(:priorities)")
-	}
-
-	if len(filters.Categories) > 0 {
-		whereClauses = append(whereClauses, "stat.category = ANY(:categories)")
-	}
-
-	if len(filters.TeamIDs) > 0 {
-		whereClauses = append(whereClauses, "s.team_id = ANY(:team_ids)")
-	} else {
-		// If no specific teams are provided, only show stories from teams user is a member of
+	// If no specific teams are provided, only show stories from teams user is a member of
+	if len(filters.TeamIDs) == 0 {
 		whereClauses = append(whereClauses, "EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = s.team_id AND tm.user_id = :current_user_id)")
 	}
 
-	if len(filters.SprintIDs) > 0 {
-		whereClauses = append(whereClauses, "s.sprint_id = ANY(:sprint_ids)")
-	}
-
-	if filters.Objective != nil {
-		whereClauses = append(whereClauses, "s.objective_id = :objective_id")
-	}
-
-	if filters.HasNoAssignee != nil && *filters.HasNoAssignee {
-		whereClauses = append(whereClauses, "s.assignee_id IS NULL")
-	}
-	if filters.HasBlockedBy != nil && *filters.HasBlockedBy {
-		whereClauses = append(whereClauses, "s.blocked_by_id IS NOT NULL")
-	}
-
 	// Handle createdByMe and assignedToMe with OR logic when both are true
 	if filters.AssignedToMe != nil && *filters.AssignedToMe && filters.CreatedByMe != nil && *filters.CreatedByMe {
 		whereClauses = append(whereClauses, "(s.assignee_id = :current_user_id OR s.reporter_id = :current_user_id)")
@@ -1339,49 +1346,7 @@ func (r *repo) buildSimpleWhereClause(filters stories.CoreStoryFilters) string {
 		}
 	}
 
-	// Date range filters
-	if filters.CreatedAfter != nil {
-		whereClauses = append(whereClauses, "s.created_at >= :created_after")
-	}
-
-	if filters.CreatedBefore != nil {
-		whereClauses = append(whereClauses, "s.created_at <= :created_before")
-	}
-
-	if filters.UpdatedAfter != nil {
-		whereClauses = append(whereClauses, "s.updated_at >= :updated_after")
-	}
-
-	if filters.UpdatedBefore != nil {
-		whereClauses = append(whereClauses, "s.updated_at <= :updated_before")
-	}
-
-	if filters.StartDateAfter != nil {
-		whereClauses = append(whereClauses, "(s.start_date >= :start_date_after)")
-	}
-
-	if filters.StartDateBefore != nil {
-		whereClauses = append(whereClauses, "(s.start_date <= :start_date_before)")
-	}
-
-	if filters.DeadlineAfter != nil {
-		whereClauses = append(whereClauses, "(s.end_date >= :deadline_after)")
-	}
-
-	if filters.DeadlineBefore != nil {
-		whereClauses = append(whereClauses, "(s.end_date <= :deadline_before)")
-	}
-
-	// Completion filters
-	if filters.CompletedAfter != nil {
-		whereClauses = append(whereClauses, "s.completed_at >= :completed_after")
-	}
-
-	if filters.CompletedBefore != nil {
-		whereClauses = append(whereClauses, "s.completed_at <= :completed_before")
-	}
-
-	return "WHERE " + strings.Join(whereClauses, " AND ")
+	return whereClauses
 }
 
 // mapToStoryList converts a map to CoreStoryList
@@ -1439,6 +1404,12 @@ func (r *repo) mapToStoryList(storyMap map[string]any) stories.CoreStoryList {
 		}
 	}
 
+	if epicID, ok := storyMap["epic_id"].(string); ok && epicID != "" {
+		if parsed, err := uuid.Parse(epicID); err == nil {
+			story.Epic = &parsed
+		}
+	}
+
 	if sprintID, ok := storyMap["sprint_id"].(string); ok && sprintID != "" {
 		if parsed, err := uuid.Parse(sprintID); err == nil {
 			story.Sprint = &parsed
@@ -1691,7 +1662,32 @@ func (r *repo) buildAllGroupsCTE(groupBy string, filters stories.CoreStoryFilter
 				SELECT 'null' as group_key, 'No Sprint' as sort_order
 			`
 		}
+	case "epic":
+		if len(filters.TeamIDs) > 0 {
+			// If specific teams are filtered, get all epics from those teams
+			return `
+				SELECT CAST(id AS text) as group_key, name as sort_order
+				FROM epics
+				WHERE workspace_id = :workspace_id
+				AND team_id = ANY(:team_ids)
+				UNION ALL
+				SELECT 'null' as group_key, 'No Epic' as sort_order
+			`
+		} else {
+			return `
+				SELECT CAST(e.id AS text) as group_key, e.name as sort_order
+				FROM epics e
+				INNER JOIN team_members tm ON tm.team_id = e.team_id
+				WHERE e.workspace_id = :workspace_id
+				AND tm.user_id = :current_user_id
+				UNION ALL
+				SELECT 'null' as group_key, 'No Epic' as sort_order
+			`
+		}
 	default:
+		if fieldID, ok := parseCustomFieldGroupBy(groupBy); ok {
+			return customFieldGroupsCTE(fieldID)
+		}
 		return `SELECT 'all' as group_key, 0 as sort_order`
 	}
 }
@@ -1709,7 +1705,12 @@ func (r *repo) getGroupColumn(groupBy string) string {
 		return "s.team_id"
 	case "sprint":
 		return "s.sprint_id"
+	case "epic":
+		return "s.epic_id"
 	default:
+		if fieldID, ok := parseCustomFieldGroupBy(groupBy); ok {
+			return customFieldGroupColumn(fieldID)
+		}
 		return "s.id"
 	}
 }
@@ -1749,6 +1750,10 @@ func (r *repo) buildOrderByClauseWithAlias(orderBy, orderDirection, tableAlias s
 			direction = "DESC"
 		}
 		return fmt.Sprintf("%s.end_date %s NULLS LAST, %s.created_at DESC", tableAlias, direction, tableAlias)
+	case "rank":
+		// Manual order ignores the direction. Every story gets a rank when it
+		// is created, so created_at only breaks ties.
+		return fmt.Sprintf("%s.rank ASC NULLS LAST, %s.created_at DESC", tableAlias, tableAlias)
 	default:
 		column = fmt.Sprintf("%s.created_at", tableAlias)
 	}
@@ -1768,80 +1773,7 @@ func (r *repo) buildQueryParams(filters stories.CoreStoryFilters) map[string]any
 		"current_user_id": filters.CurrentUserID,
 	}
 
-	// Direct assignment - let database driver handle array conversion
-	if len(filters.StatusIDs) > 0 {
-		params["status_ids"] = filters.StatusIDs
-	}
-	if len(filters.AssigneeIDs) > 0 {
-		params["assignee_ids"] = filters.AssigneeIDs
-	}
-	if len(filters.ReporterIDs) > 0 {
-		params["reporter_ids"] = filters.ReporterIDs
-	}
-	if len(filters.Priorities) > 0 {
-		params["priorities"] = filters.Priorities
-	}
-	if len(filters.Categories) > 0 {
-		params["categories"] = filters.Categories
-	}
-	if len(filters.TeamIDs) > 0 {
-		params["team_ids"] = filters.TeamIDs
-	}
-	if len(filters.SprintIDs) > 0 {
-		params["sprint_ids"] = filters.SprintIDs
-	}
-	if len(filters.LabelIDs) > 0 {
-		params["label_ids"] = filters.LabelIDs
-	}
-	if len(filters.EstimateValues) > 0 {
-		params["estimate_values"] = filters.EstimateValues
-	}
-	if filters.TitleContains != nil {
-		params["title_contains"] = *filters.TitleContains
-	}
-
-	// Single value parameters
-	if filters.Parent != nil {
-		params["parent_id"] = *filters.Parent
-	}
-	if filters.Objective != nil {
-		params["objective_id"] = *filters.Objective
-	}
-	if filters.Epic != nil {
-		params["epic_id"] = *filters.Epic
-	}
-
-	// Date range parameters
-	if filters.CreatedAfter != nil {
-		params["created_after"] = *filters.CreatedAfter
-	}
-	if filters.CreatedBefore != nil {
-		params["created_before"] = *filters.CreatedBefore
-	}
-	if filters.UpdatedAfter != nil {
-		params["updated_after"] = *filters.UpdatedAfter
-	}
-	if filters.UpdatedBefore != nil {
-		params["updated_before"] = *filters.UpdatedBefore
-	}
-	if filters.StartDateAfter != nil {
-		params["start_date_after"] = *filters.StartDateAfter
-	}
-	if filters.StartDateBefore != nil {
-		params["start_date_before"] = *filters.StartDateBefore
-	}
-	if filters.DeadlineAfter != nil {
-		params["deadline_after"] = *filters.DeadlineAfter
-	}
-	if filters.DeadlineBefore != nil {
-		params["deadline_before"] = *filters.DeadlineBefore
-	}
-	if filters.CompletedAfter != nil {
-		params["completed_after"] = *filters.CompletedAfter
-	}
-	if filters.CompletedBefore != nil {
-		params["completed_before"] = *filters.CompletedBefore
-	}
+	maps.Copy(params, storyFilterParams(filters))
 
 	return params
 }
@@ -1874,6 +1806,7 @@ func (r *repo) buildSubStoriesJSONExpr(parentAlias string, includeSubStories boo
 							'team_code', sub_team.code,
 							'team_name', sub_team.name,
 							'objective_id', sub.objective_id,
+							'epic_id', sub.epic_id,
 							'objective_name', sub_objective.name,
 							'objective_description', sub_objective.description,
 							'workspace_id', sub.workspace_id,
@@ -1926,6 +1859,7 @@ func (r *repo) buildStoriesQuery(filters stories.CoreStoryFilters) string {
 			t.code AS team_code,
 			t.name AS team_name,
 			s.objective_id,
+			s.epic_id,
 			o.name AS objective_name,
 			o.description AS objective_description,
 			s.workspace_id,
@@ -1965,157 +1899,7 @@ func (r *repo) buildStoriesQuery(filters stories.CoreStoryFilters) string {
 		`
 	}
 
-	// Add status join if categories filter is present
-	if len(filters.Categories) > 0 {
-		query += `
-			INNER JOIN statuses stat ON s.status_id = stat.status_id
-		`
-	}
-
-	// Build WHERE clauses
-	whereClauses := []string{
-		"s.workspace_id = :workspace_id",
-	}
-
-	// Handle parent filtering
-	if filters.Parent != nil {
-		whereClauses = append(whereClauses, "s.parent_id = :parent_id")
-	} else if filters.ShowSubStories == nil || !*filters.ShowSubStories {
-		// Default: only show top-level stories (no parent)
-		whereClauses = append(whereClauses, "s.parent_id IS NULL")
-	}
-
-	// Exclude archived unless explicitly included
-	if filters.IncludeArchived != nil && *filters.IncludeArchived {
-		whereClauses = append(whereClauses, "s.archived_at IS NOT NULL")
-	} else {
-		whereClauses = append(whereClauses, "s.archived_at IS NULL")
-	}
-
-	// Exclude deleted unless explicitly included
-	if filters.IncludeDeleted != nil && *filters.IncludeDeleted {
-		whereClauses = append(whereClauses, "s.deleted_at IS NOT NULL")
-	} else {
-		whereClauses = append(whereClauses, "s.deleted_at IS NULL")
-	}
-
-	// Add filter conditions
-	if len(filters.StatusIDs) > 0 {
-		whereClauses = append(whereClauses, "s.status_id = ANY(:status_ids)")
-	}
-
-	if len(filters.AssigneeIDs) > 0 {
-		whereClauses = append(whereClauses, "s.assignee_id = ANY(:assignee_ids)")
-	}
-
-	if len(filters.ReporterIDs) > 0 {
-		whereClauses = append(whereClauses, "s.reporter_id = ANY(:reporter_ids)")
-	}
-
-	if filters.TitleContains != nil {
-		whereClauses = append(whereClauses, "(s.title ILIKE '%' || :title_contains || '%' OR s.description ILIKE '%' || :title_contains || '%' OR s.description_html ILIKE '%' || :title_contains || '%')")
-	}
-
-	if len(filters.Priorities) > 0 {
-		whereClauses = append(whereClauses, "s.priority = ANY(:priorities)")
-	}
-
-	if len(filters.Categories) > 0 {
-		whereClauses = append(whereClauses, "stat.category = ANY(:categories)")
-	}
-
-	if len(filters.TeamIDs) > 0 {
-		whereClauses = append(whereClauses, "s.team_id = ANY(:team_ids)")
-	} else {
-		// If no specific teams are provided, only show stories from teams user is a member of
-		whereClauses = append(whereClauses, "EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = s.team_id AND tm.user_id = :current_user_id)")
-	}
-
-	if len(filters.SprintIDs) > 0 {
-		whereClauses = append(whereClauses, "s.sprint_id = ANY(:sprint_ids)")
-	}
-
-	if len(filters.LabelIDs) > 0 {
-		query += `
-			INNER JOIN story_labels sl_filter ON sl_filter.story_id = s.id
-		`
-		whereClauses = append(whereClauses, "sl_filter.label_id = ANY(:label_ids)")
-	}
-
-	if len(filters.EstimateValues) > 0 {
-		whereClauses = append(whereClauses, "s.estimate_unit = ANY(:estimate_values)")
-	}
-
-	if filters.Objective != nil {
-		whereClauses = append(whereClauses, "s.objective_id = :objective_id")
-	}
-
-	if filters.Epic != nil {
-		whereClauses = append(whereClauses, "s.epic_id = :epic_id")
-	}
-
-	if filters.HasNoAssignee != nil && *filters.HasNoAssignee {
-		whereClauses = append(whereClauses, "s.assignee_id IS NULL")
-	}
-	if filters.HasBlockedBy != nil && *filters.HasBlockedBy {
-		whereClauses = append(whereClauses, "s.blocked_by_id IS NOT NULL")
-	}
-
-	// Handle createdByMe and assignedToMe with OR logic when both are true
-	if filters.AssignedToMe != nil && *filters.AssignedToMe && filters.CreatedByMe != nil && *filters.CreatedByMe {
-		whereClauses = append(whereClauses, "(s.assignee_id = :current_user_id OR s.reporter_id = :current_user_id)")
-	} else {
-		if filters.AssignedToMe != nil && *filters.AssignedToMe {
-			whereClauses = append(whereClauses, "s.assignee_id = :current_user_id")
-		}
-
-		if filters.CreatedByMe != nil && *filters.CreatedByMe {
-			whereClauses = append(whereClauses, "s.reporter_id = :current_user_id")
-		}
-	}
-
-	// Date range filters
-	if filters.CreatedAfter != nil {
-		whereClauses = append(whereClauses, "s.created_at >= :created_after")
-	}
-
-	if filters.CreatedBefore != nil {
-		whereClauses = append(whereClauses, "s.created_at <= :created_before")
-	}
-
-	if filters.UpdatedAfter != nil {
-		whereClauses = append(whereClauses, "s.updated_at >= :updated_after")
-	}
-
-	if filters.UpdatedBefore != nil {
-		whereClauses = append(whereClauses, "s.updated_at <= :updated_before")
-	}
-
-	if filters.StartDateAfter != nil {
-		whereClauses = append(whereClauses, "(s.start_date >= :start_date_after)")
-	}
-
-	if filters.StartDateBefore != nil {
-		whereClauses = append(whereClauses, "(s.start_date <= :start_date_before)")
-	}
-
-	if filters.DeadlineAfter != nil {
-		whereClauses = append(whereClauses, "(s.end_date >= :deadline_after)")
-	}
-
-	if filters.DeadlineBefore != nil {
-		whereClauses = append(whereClauses, "(s.end_date <= :deadline_before)")
-	}
-
-	// Completion filters
-	if filters.CompletedAfter != nil {
-		whereClauses = append(whereClauses, "s.completed_at >= :completed_after")
-	}
-
-	if filters.CompletedBefore != nil {
-		whereClauses = append(whereClauses, "s.completed_at <= :completed_before")
-	}
-
+	whereClauses := append(storyScopeWhereClauses(filters), storyFilterWhereClauses(filters)...)
 	query += " WHERE " + strings.Join(whereClauses, " AND ")
 
 	return query
@@ -2214,8 +1998,9 @@ func (r *repo) groupStories(allStories []dbStory, groupBy string, storiesPerGrou
 	// Sort groups according to the same logic as SQL-based grouping
 	r.sortGroups(groups, groupBy)
 
-	// Sort stories within each group (only if not using default ordering)
-	if orderBy != "created" || orderDirection != "desc" {
+	// Sort stories within each group (only if not using default ordering).
+	// Rank order comes from the query and is kept as is.
+	if orderBy != "rank" && (orderBy != "created" || orderDirection != "desc") {
 		for i := range groups {
 			r.sortStoriesInGroup(&groups[i], orderBy, orderDirection)
 		}
@@ -2362,6 +2147,11 @@ func (r *repo) getGroupKey(story dbStory, groupBy string) string {
 			return story.Sprint.String()
 		}
 		return "null"
+	case "epic":
+		if story.Epic != nil {
+			return story.Epic.String()
+		}
+		return "null"
 	default:
 		return "all"
 	}
@@ -2448,6 +2238,7 @@ func (r *repo) buildSimpleStoriesQuery(filters stories.CoreStoryFilters) string
 			t.code AS team_code,
 			t.name AS team_name,
 			s.objective_id,
+			s.epic_id,
 			o.name AS objective_name,
 			o.description AS objective_description,
 			s.workspace_id,
@@ -2487,160 +2278,7 @@ func (r *repo) buildSimpleStoriesQuery(filters stories.CoreStoryFilters) string
 		`
 	}
 
-	// Add status join if categories filter is present
-	if len(filters.Categories) > 0 {
-		query += `
-			INNER JOIN statuses stat ON s.status_id = stat.status_id
-		`
-	}
-
-	// Add label join if needed for filtering (but don't fetch label data)
-	if len(filters.LabelIDs) > 0 {
-		query += `
-			INNER JOIN story_labels sl_filter ON sl_filter.story_id = s.id
-		`
-	}
-
-	// Build WHERE clauses (same as buildStoriesQuery)
-	whereClauses := []string{
-		"s.workspace_id = :workspace_id",
-	}
-
-	// Exclude archived unless explicitly included
-	if filters.IncludeArchived != nil && *filters.IncludeArchived {
-		whereClauses = append(whereClauses, "s.archived_at IS NOT NULL")
-	} else {
-		whereClauses = append(whereClauses, "s.archived_at IS NULL")
-	}
-
-	// Exclude deleted unless explicitly included
-	if filters.IncludeDeleted != nil && *filters.IncludeDeleted {
-		whereClauses = append(whereClauses, "s.deleted_at IS NOT NULL")
-	} else {
-		whereClauses = append(whereClauses, "s.deleted_at IS NULL")
-	}
-
-	// Add filter conditions
-	if len(filters.StatusIDs) > 0 {
-		whereClauses = append(whereClauses, "s.status_id = ANY(:status_ids)")
-	}
-
-	if len(filters.AssigneeIDs) > 0 {
-		whereClauses = append(whereClauses, "s.assignee_id = ANY(:assignee_ids)")
-	}
-
-	if len(filters.ReporterIDs) > 0 {
-		whereClauses = append(whereClauses, "s.reporter_id = ANY(:reporter_ids)")
-	}
-
-	if filters.TitleContains != nil {
-		whereClauses = append(whereClauses, "(s.title ILIKE '%' || :title_contains || '%' OR s.description ILIKE '%' || :title_contains || '%' OR s.description_html ILIKE '%' || :title_contains || '%')")
-	}
-
-	if len(filters.Priorities) > 0 {
-		whereClauses = append(whereClauses, "s.priority = ANY(:priorities)")
-	}
-
-	if len(filters.Categories) > 0 {
-		whereClauses = append(whereClauses, "stat.category = ANY(:categories)")
-	}
-
-	if len(filters.TeamIDs) > 0 {
-		whereClauses = append(whereClauses, "s.team_id = ANY(:team_ids)")
-	} else {
-		// If no specific teams are provided, only show stories from teams user is a member of
-		whereClauses = append(whereClauses, "EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = s.team_id AND tm.user_id = :current_user_id)")
-	}
-
-	if len(filters.SprintIDs) > 0 {
-		whereClauses = append(whereClauses, "s.sprint_id = ANY(:sprint_ids)")
-	}
-
-	if len(filters.LabelIDs) > 0 {
-		whereClauses = append(whereClauses, "sl_filter.label_id = ANY(:label_ids)")
-	}
-
-	if len(filters.EstimateValues) > 0 {
-		whereClauses = append(whereClauses, "s.estimate_unit = ANY(:estimate_values)")
-	}
-
-	if filters.Parent != nil {
-		whereClauses = append(whereClauses, "s.parent_id = :parent_id")
-	} else if filters.ShowSubStories == nil || !*filters.ShowSubStories {
-		// Default: only show top-level stories (no parent)
-		whereClauses = append(whereClauses, "s.parent_id IS NULL")
-	}
-
-	if filters.Objective != nil {
-		whereClauses = append(whereClauses, "s.objective_id = :objective_id")
-	}
-
-	if filters.Epic != nil {
-		whereClauses = append(whereClauses, "s.epic_id = :epic_id")
-	}
-
-	if filters.HasNoAssignee != nil && *filters.HasNoAssignee {
-		whereClauses = append(whereClauses, "s.assignee_id IS NULL")
-	}
-	if filters.HasBlockedBy != nil && *filters.HasBlockedBy {
-		whereClauses = append(whereClauses, "s.blocked_by_id IS NOT NULL")
-	}
-
-	// Handle createdByMe and assignedToMe with OR logic when both are true
-	if filters.AssignedToMe != nil && *filters.AssignedToMe && filters.CreatedByMe != nil && *filters.CreatedByMe {
-		whereClauses = append(whereClauses, "(s.assignee_id = :current_user_id OR s.reporter_id = :current_user_id)")
-	} else {
-		if filters.AssignedToMe != nil && *filters.AssignedToMe {
-			whereClauses = append(whereClauses, "s.assignee_id = :current_user_id")
-		}
-
-		if filters.CreatedByMe != nil && *filters.CreatedByMe {
-			whereClauses = append(whereClauses, "s.reporter_id = :current_user_id")
-		}
-	}
-
-	// Date range filters
-	if filters.CreatedAfter != nil {
-		whereClauses = append(whereClauses, "s.created_at >= :created_after")
-	}
-
-	if filters.CreatedBefore != nil {
-		whereClauses = append(whereClauses, "s.created_at <= :created_before")
-	}
-
-	if filters.UpdatedAfter != nil {
-		whereClauses = append(whereClauses, "s.updated_at >= :updated_after")
-	}
-
-	if filters.UpdatedBefore != nil {
-		whereClauses = append(whereClauses, "s.updated_at <= :updated_before")
-	}
-
-	if filters.StartDateAfter != nil {
-		whereClauses = append(whereClauses, "(s.start_date >= :start_date_after)")
-	}
-
-	if filters.StartDateBefore != nil {
-		whereClauses = append(whereClauses, "(s.start_date <= :start_date_before)")
-	}
-
-	if filters.DeadlineAfter != nil {
-		whereClauses = append(whereClauses, "(s.end_date >= :deadline_after)")
-	}
-
-	if filters.DeadlineBefore != nil {
-		whereClauses = append(whereClauses, "(s.end_date <= :deadline_before)")
-	}
-
-	// Completion filters
-	if filters.CompletedAfter != nil {
-		whereClauses = append(whereClauses, "s.completed_at >= :completed_after")
-	}
-
-	if filters.CompletedBefore != nil {
-		whereClauses = append(whereClauses, "s.completed_at <= :completed_before")
-	}
-
+	whereClauses := append(storyScopeWhereClauses(filters), storyFilterWhereClauses(filters)...)
 	query += " WHERE " + strings.Join(whereClauses, " AND ")
 
 	return query
@@ -2670,6 +2308,7 @@ func (r *repo) ListByCategory(ctx context.Context, workspaceId, userID, teamId u
 			s.sprint_id,
 			s.team_id,
 			s.objective_id,
+			s.epic_id,
 			s.workspace_id,
 			s.assignee_id,
 			s.reporter_id,
@@ -2776,6 +2415,7 @@ func (r *repo) getStoryByRef(ctx context.Context, workspaceId uuid.UUID, teamCod
 					s.parent_id,
 					s.team_id,
 					s.objective_id,
+					s.epic_id,
 					s.sprint_id,
 					s.key_result_id,
 					s.workspace_id,
@@ -2788,6 +2428,7 @@ func (r *repo) getStoryByRef(ctx context.Context, workspaceId uuid.UUID, teamCod
 					s.deleted_at,
 					s.archived_at,
 					s.completed_at,
+					s.merged_into_id,
 					COALESCE(
 							(
 									SELECT
@@ -2829,6 +2470,7 @@ func (r *repo) getStoryByRef(ctx context.Context, workspaceId uuid.UUID, teamCod
 												'sprint_id', other.sprint_id,
 												'team_id', other.team_id,
 												'objective_id', other.objective_id,
+												'epic_id', other.epic_id,
 												'workspace_id', other.workspace_id,
 												'assignee_id', other.assignee_id,
 												'reporter_id', other.reporter_id,
@@ -2854,7 +2496,9 @@ func (r *repo) getStoryByRef(ctx context.Context, workspaceId uuid.UUID, teamCod
 							WHERE
 								sa.from_story_id = s.id OR sa.to_story_id = s.id
 						), '[]'
-					) AS associations
+					) AS associations,
+					` + customFieldValuesSelect + `,
+					` + watchersSelect + `
 				FROM
 					stories s
 					INNER JOIN teams t ON s.team_id = t.team_id
@@ -2883,6 +2527,11 @@ func (r *repo) getStoryByRef(ctx context.Context, workspaceId uuid.UUID, teamCod
 	err = stmt.GetContext(ctx, &story, params)
 	if err != nil {
 		if err == sql.ErrNoRows {
+			// The reference may belong to a story that moved to another team.
+			currentCode, currentSequence, aliasErr := r.storyRefAlias(ctx, workspaceId, teamCode, sequenceID)
+			if aliasErr == nil {
+				return r.getStoryByRef(ctx, workspaceId, currentCode, currentSequence)
+			}
 			return dbStory{}, errors.New("story not found")
 		}
 		r.log.Error(ctx, fmt.Sprintf("failed to execute query: %s", err), "teamCode", teamCode, "sequenceID", sequenceID)
@@ -2893,11 +2542,12 @@ func (r *repo) getStoryByRef(ctx context.Context, workspaceId uuid.UUID, teamCod
 }
 
 // GetStoryIDByRef returns story UUID by workspace, team code and sequence.
+// A merged duplicate's reference returns the canonical story.
 func (r *repo) GetStoryIDByRef(ctx context.Context, workspaceId uuid.UUID, teamCode string, sequenceID int) (uuid.UUID, error) {
 	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetStoryIDByRef")
 	defer span.End()
 
-	q := `SELECT s.id
+	q := `SELECT COALESCE(s.merged_into_id, s.id)
 		  FROM stories s
 		  INNER JOIN teams t ON s.team_id = t.team_id
 		  WHERE t.code = :team_code
@@ -2922,6 +2572,9 @@ func (r *repo) GetStoryIDByRef(ctx context.Context, workspaceId uuid.UUID, teamC
 
 	if err := stmt.GetContext(ctx, &id, params); err != nil {
 		if err == sql.ErrNoRows {
+			if currentCode, currentSequence, aliasErr := r.storyRefAlias(ctx, workspaceId, teamCode, sequenceID); aliasErr == nil {
+				return r.GetStoryIDByRef(ctx, workspaceId, currentCode, currentSequence)
+			}
 			return uuid.Nil, errors.New("story not found")
 		}
 		return uuid.Nil, err
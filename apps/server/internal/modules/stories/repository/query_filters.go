@@ -0,0 +1,427 @@
+package storiesrepository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queryNameLookups select lower-cased names and the IDs they stand for in
+// workspace $1, limited to the names in $2. Statuses also match on their
+// category so "started" can be mixed with status names.
+var queryNameLookups = map[string]string{
+	stories.QueryNameUsers: `
+		SELECT lower(u.username) AS name, u.user_id AS id
+		FROM users u
+		INNER JOIN workspace_members wm ON wm.user_id = u.user_id AND wm.workspace_id = $1
+		WHERE lower(u.username) = ANY($2)
+		UNION
+		SELECT lower(u.email), u.user_id
+		FROM users u
+		INNER JOIN workspace_members wm ON wm.user_id = u.user_id AND wm.workspace_id = $1
+		WHERE lower(u.email) = ANY($2)`,
+	stories.QueryNameLabels: `
+		SELECT lower(name) AS name, label_id AS id
+		FROM labels
+		WHERE workspace_id = $1 AND lower(name) = ANY($2)`,
+	stories.QueryNameStatuses: `
+		SELECT lower(name) AS name, status_id AS id
+		FROM statuses
+		WHERE workspace_id = $1 AND lower(name) = ANY($2)
+		UNION
+		SELECT category, status_id
+		FROM statuses
+		WHERE workspace_id = $1 AND category = ANY($2)`,
+	stories.QueryNameTeams: `
+		SELECT lower(code) AS name, team_id AS id
+		FROM teams
+		WHERE workspace_id = $1 AND lower(code) = ANY($2)
+		UNION
+		SELECT lower(name), team_id
+		FROM teams
+		WHERE workspace_id = $1 AND lower(name) = ANY($2)`,
+	stories.QueryNameSprints: `
+		SELECT lower(name) AS name, sprint_id AS id
+		FROM sprints
+		WHERE workspace_id = $1 AND lower(name) = ANY($2)`,
+	stories.QueryNameEpics: `
+		SELECT lower(name) AS name, id
+		FROM epics
+		WHERE workspace_id = $1 AND lower(name) = ANY($2)`,
+}
+
+// ResolveQueryNames returns the IDs matching each of names, keyed by the
+// lower-cased name. Names without a match are left out.
+func (r *repo) ResolveQueryNames(ctx context.Context, workspaceID uuid.UUID, kind string, names []string) (map[string][]uuid.UUID, error) {
+	r.log.Info(ctx, "business.repository.stories.ResolveQueryNames")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.ResolveQueryNames")
+	defer span.End()
+
+	query, ok := queryNameLookups[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown query name kind %q", kind)
+	}
+
+	var rows []struct {
+		Name string    `db:"name"`
+		ID   uuid.UUID `db:"id"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, workspaceID, names); err != nil {
+		errMsg := fmt.Sprintf("failed to resolve query names: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to resolve query names"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	ids := make(map[string][]uuid.UUID, len(rows))
+	for _, row := range rows {
+		ids[row.Name] = append(ids[row.Name], row.ID)
+	}
+	return ids, nil
+}
+
+// StoryFilterConditions returns the conditions on stories s for the filters
+// that narrow a story list, with the named parameters they use. Other modules
+// searching stories use it so filters behave as they do in story lists;
+// scoping to the workspace, teams and live stories is left to them.
+func StoryFilterConditions(filters stories.CoreStoryFilters) ([]string, map[string]any) {
+	return storyFilterWhereClauses(filters), storyFilterParams(filters)
+}
+
+// storyFilterWhereClauses returns the conditions for the attribute, date,
+// custom field and query filters of filters. Statuses and labels are matched
+// in subqueries so callers need no extra joins.
+func storyFilterWhereClauses(filters stories.CoreStoryFilters) []string {
+	var whereClauses []string
+
+	if len(filters.StatusIDs) > 0 {
+		whereClauses = append(whereClauses, "s.status_id = ANY(:status_ids)")
+	}
+
+	if len(filters.AssigneeIDs) > 0 {
+		whereClauses = append(whereClauses, "s.assignee_id = ANY(:assignee_ids)")
+	}
+
+	if len(filters.ReporterIDs) > 0 {
+		whereClauses = append(whereClauses, "s.reporter_id = ANY(:reporter_ids)")
+	}
+
+	if filters.TitleContains != nil {
+		whereClauses = append(whereClauses, "(s.title ILIKE '%' || :title_contains || '%' OR s.description ILIKE '%' || :title_contains || '%' OR s.description_html ILIKE '%' || :title_contains || '%')")
+	}
+
+	if len(filters.Priorities) > 0 {
+		whereClauses = append(whereClauses, "s.priority = ANY(:priorities)")
+	}
+
+	if len(filters.Categories) > 0 {
+		whereClauses = append(whereClauses, `EXISTS (
+			SELECT 1 FROM statuses fs
+			WHERE fs.status_id = s.status_id AND fs.category = ANY(:categories)
+		)`)
+	}
+
+	if len(filters.TeamIDs) > 0 {
+		whereClauses = append(whereClauses, "s.team_id = ANY(:team_ids)")
+	}
+
+	if len(filters.SprintIDs) > 0 {
+		whereClauses = append(whereClauses, "s.sprint_id = ANY(:sprint_ids)")
+	}
+
+	if len(filters.LabelIDs) > 0 {
+		whereClauses = append(whereClauses, `EXISTS (
+			SELECT 1 FROM story_labels fl
+			WHERE fl.story_id = s.id AND fl.label_id = ANY(:label_ids)
+		)`)
+	}
+
+	if len(filters.EstimateValues) > 0 {
+		whereClauses = append(whereClauses, "s.estimate_unit = ANY(:estimate_values)")
+	}
+
+	if filters.Parent != nil {
+		whereClauses = append(whereClauses, "s.parent_id = :parent_id")
+	}
+
+	if filters.Objective != nil {
+		whereClauses = append(whereClauses, "s.objective_id = :objective_id")
+	}
+
+	if filters.KeyResult != nil {
+		whereClauses = append(whereClauses, "s.key_result_id = :key_result_id")
+	}
+
+	if filters.Epic != nil {
+		whereClauses = append(whereClauses, "s.epic_id = :epic_id")
+	}
+
+	if filters.HasNoAssignee != nil && *filters.HasNoAssignee {
+		whereClauses = append(whereClauses, "s.assignee_id IS NULL")
+	}
+	if filters.HasBlockedBy != nil && *filters.HasBlockedBy {
+		whereClauses = append(whereClauses, "s.blocked_by_id IS NOT NULL")
+	}
+	if filters.SLABreached != nil {
+		if *filters.SLABreached {
+			whereClauses = append(whereClauses, "EXISTS "+slaBreachedQuery)
+		} else {
+			whereClauses = append(whereClauses, "NOT EXISTS "+slaBreachedQuery)
+		}
+	}
+
+	// Date range filters
+	if filters.CreatedAfter != nil {
+		whereClauses = append(whereClauses, "s.created_at >= :created_after")
+	}
+
+	if filters.CreatedBefore != nil {
+		whereClauses = append(whereClauses, "s.created_at <= :created_before")
+	}
+
+	if filters.UpdatedAfter != nil {
+		whereClauses = append(whereClauses, "s.updated_at >= :updated_after")
+	}
+
+	if filters.UpdatedBefore != nil {
+		whereClauses = append(whereClauses, "s.updated_at <= :updated_before")
+	}
+
+	if filters.StartDateAfter != nil {
+		whereClauses = append(whereClauses, "(s.start_date >= :start_date_after)")
+	}
+
+	if filters.StartDateBefore != nil {
+		whereClauses = append(whereClauses, "(s.start_date <= :start_date_before)")
+	}
+
+	if filters.DeadlineAfter != nil {
+		whereClauses = append(whereClauses, "(s.end_date >= :deadline_after)")
+	}
+
+	if filters.DeadlineBefore != nil {
+		whereClauses = append(whereClauses, "(s.end_date <= :deadline_before)")
+	}
+
+	// Completion filters
+	if filters.CompletedAfter != nil {
+		whereClauses = append(whereClauses, "s.completed_at >= :completed_after")
+	}
+
+	if filters.CompletedBefore != nil {
+		whereClauses = append(whereClauses, "s.completed_at <= :completed_before")
+	}
+
+	whereClauses = append(whereClauses, customFieldWhereClauses(filters.CustomFields)...)
+	whereClauses = append(whereClauses, queryFilterWhereClauses(filters)...)
+
+	return whereClauses
+}
+
+// storyFilterParams returns the named parameters of storyFilterWhereClauses.
+func storyFilterParams(filters stories.CoreStoryFilters) map[string]any {
+	params := map[string]any{}
+
+	// Direct assignment - let database driver handle array conversion
+	if len(filters.StatusIDs) > 0 {
+		params["status_ids"] = filters.StatusIDs
+	}
+	if len(filters.AssigneeIDs) > 0 {
+		params["assignee_ids"] = filters.AssigneeIDs
+	}
+	if len(filters.ReporterIDs) > 0 {
+		params["reporter_ids"] = filters.ReporterIDs
+	}
+	if len(filters.Priorities) > 0 {
+		params["priorities"] = filters.Priorities
+	}
+	if len(filters.Categories) > 0 {
+		params["categories"] = filters.Categories
+	}
+	if len(filters.TeamIDs) > 0 {
+		params["team_ids"] = filters.TeamIDs
+	}
+	if len(filters.SprintIDs) > 0 {
+		params["sprint_ids"] = filters.SprintIDs
+	}
+	if len(filters.LabelIDs) > 0 {
+		params["label_ids"] = filters.LabelIDs
+	}
+	if len(filters.EstimateValues) > 0 {
+		params["estimate_values"] = filters.EstimateValues
+	}
+	if filters.TitleContains != nil {
+		params["title_contains"] = *filters.TitleContains
+	}
+
+	// Single value parameters
+	if filters.Parent != nil {
+		params["parent_id"] = *filters.Parent
+	}
+	if filters.Objective != nil {
+		params["objective_id"] = *filters.Objective
+	}
+	if filters.KeyResult != nil {
+		params["key_result_id"] = *filters.KeyResult
+	}
+	if filters.Epic != nil {
+		params["epic_id"] = *filters.Epic
+	}
+
+	// Date range parameters
+	if filters.CreatedAfter != nil {
+		params["created_after"] = *filters.CreatedAfter
+	}
+	if filters.CreatedBefore != nil {
+		params["created_before"] = *filters.CreatedBefore
+	}
+	if filters.UpdatedAfter != nil {
+		params["updated_after"] = *filters.UpdatedAfter
+	}
+	if filters.UpdatedBefore != nil {
+		params["updated_before"] = *filters.UpdatedBefore
+	}
+	if filters.StartDateAfter != nil {
+		params["start_date_after"] = *filters.StartDateAfter
+	}
+	if filters.StartDateBefore != nil {
+		params["start_date_before"] = *filters.StartDateBefore
+	}
+	if filters.DeadlineAfter != nil {
+		params["deadline_after"] = *filters.DeadlineAfter
+	}
+	if filters.DeadlineBefore != nil {
+		params["deadline_before"] = *filters.DeadlineBefore
+	}
+	if filters.CompletedAfter != nil {
+		params["completed_after"] = *filters.CompletedAfter
+	}
+	if filters.CompletedBefore != nil {
+		params["completed_before"] = *filters.CompletedBefore
+	}
+
+	addCustomFieldQueryParams(params, filters.CustomFields)
+	addQueryFilterParams(params, filters)
+
+	return params
+}
+
+// queryFilterWhereClauses adds the sprint and epic presence filters, the
+// association type filter and the exclusions of filters.
+func queryFilterWhereClauses(filters stories.CoreStoryFilters) []string {
+	var clauses []string
+	if filters.HasNoSprint != nil && *filters.HasNoSprint {
+		clauses = append(clauses, "s.sprint_id IS NULL")
+	}
+	if filters.HasNoEpic != nil && *filters.HasNoEpic {
+		clauses = append(clauses, "s.epic_id IS NULL")
+	}
+	if len(filters.AssociationTypes) > 0 {
+		clauses = append(clauses, `EXISTS (
+			SELECT 1 FROM story_associations fa
+			WHERE (fa.from_story_id = s.id OR fa.to_story_id = s.id)
+				AND fa.association_type = ANY(:association_types)
+		)`)
+	}
+
+	ex := filters.Exclude
+	if ex == nil {
+		return clauses
+	}
+	if len(ex.StatusIDs) > 0 {
+		clauses = append(clauses, "(s.status_id IS NULL OR NOT (s.status_id = ANY(:exclude_status_ids)))")
+	}
+	if len(ex.AssigneeIDs) > 0 {
+		clauses = append(clauses, "(s.assignee_id IS NULL OR NOT (s.assignee_id = ANY(:exclude_assignee_ids)))")
+	}
+	if len(ex.ReporterIDs) > 0 {
+		clauses = append(clauses, "(s.reporter_id IS NULL OR NOT (s.reporter_id = ANY(:exclude_reporter_ids)))")
+	}
+	if len(ex.Priorities) > 0 {
+		clauses = append(clauses, "NOT (s.priority = ANY(:exclude_priorities))")
+	}
+	if len(ex.Categories) > 0 {
+		clauses = append(clauses, `NOT EXISTS (
+			SELECT 1 FROM statuses xs
+			WHERE xs.status_id = s.status_id AND xs.category = ANY(:exclude_categories)
+		)`)
+	}
+	if len(ex.TeamIDs) > 0 {
+		clauses = append(clauses, "NOT (s.team_id = ANY(:exclude_team_ids))")
+	}
+	if len(ex.SprintIDs) > 0 {
+		clauses = append(clauses, "(s.sprint_id IS NULL OR NOT (s.sprint_id = ANY(:exclude_sprint_ids)))")
+	}
+	if len(ex.LabelIDs) > 0 {
+		clauses = append(clauses, `NOT EXISTS (
+			SELECT 1 FROM story_labels xl
+			WHERE xl.story_id = s.id AND xl.label_id = ANY(:exclude_label_ids)
+		)`)
+	}
+	if len(ex.EpicIDs) > 0 {
+		clauses = append(clauses, "(s.epic_id IS NULL OR NOT (s.epic_id = ANY(:exclude_epic_ids)))")
+	}
+	if len(ex.EstimateValues) > 0 {
+		clauses = append(clauses, "(s.estimate_unit IS NULL OR NOT (s.estimate_unit = ANY(:exclude_estimate_values)))")
+	}
+	if ex.Unassigned {
+		clauses = append(clauses, "s.assignee_id IS NOT NULL")
+	}
+	if ex.NoSprint {
+		clauses = append(clauses, "s.sprint_id IS NOT NULL")
+	}
+	if ex.NoEpic {
+		clauses = append(clauses, "s.epic_id IS NOT NULL")
+	}
+	if ex.Blocked {
+		clauses = append(clauses, "s.blocked_by_id IS NULL")
+	}
+	return clauses
+}
+
+func addQueryFilterParams(params map[string]any, filters stories.CoreStoryFilters) {
+	if len(filters.AssociationTypes) > 0 {
+		params["association_types"] = filters.AssociationTypes
+	}
+
+	ex := filters.Exclude
+	if ex == nil {
+		return
+	}
+	if len(ex.StatusIDs) > 0 {
+		params["exclude_status_ids"] = ex.StatusIDs
+	}
+	if len(ex.AssigneeIDs) > 0 {
+		params["exclude_assignee_ids"] = ex.AssigneeIDs
+	}
+	if len(ex.ReporterIDs) > 0 {
+		params["exclude_reporter_ids"] = ex.ReporterIDs
+	}
+	if len(ex.Priorities) > 0 {
+		params["exclude_priorities"] = ex.Priorities
+	}
+	if len(ex.Categories) > 0 {
+		params["exclude_categories"] = ex.Categories
+	}
+	if len(ex.TeamIDs) > 0 {
+		params["exclude_team_ids"] = ex.TeamIDs
+	}
+	if len(ex.SprintIDs) > 0 {
+		params["exclude_sprint_ids"] = ex.SprintIDs
+	}
+	if len(ex.LabelIDs) > 0 {
+		params["exclude_label_ids"] = ex.LabelIDs
+	}
+	if len(ex.EpicIDs) > 0 {
+		params["exclude_epic_ids"] = ex.EpicIDs
+	}
+	if len(ex.EstimateValues) > 0 {
+		params["exclude_estimate_values"] = ex.EstimateValues
+	}
+}
@@ -0,0 +1,186 @@
+package storiesrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type dbStoryRank struct {
+	ID   uuid.UUID `db:"id"`
+	Rank *string   `db:"rank"`
+}
+
+// GetStoryRanks returns the ranks of stories keyed by story. Stories that
+// have never been ranked map to nil and missing stories are left out.
+func (r *repo) GetStoryRanks(ctx context.Context, workspaceID uuid.UUID, storyIDs []uuid.UUID) (map[uuid.UUID]*string, error) {
+	r.log.Info(ctx, "business.repository.stories.GetStoryRanks")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetStoryRanks")
+	defer span.End()
+
+	query := `
+		SELECT id, rank
+		FROM stories
+		WHERE workspace_id = $1 AND id = ANY($2)`
+
+	var rows []dbStoryRank
+	if err := r.db.SelectContext(ctx, &rows, query, workspaceID, storyIDs); err != nil {
+		errMsg := fmt.Sprintf("failed to get story ranks: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get story ranks"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	ranks := make(map[uuid.UUID]*string, len(rows))
+	for _, row := range rows {
+		ranks[row.ID] = row.Rank
+	}
+	return ranks, nil
+}
+
+// GetNeighbourRank returns the rank that comes directly after rank in the
+// workspace when next is true, or directly before it otherwise. It returns
+// nil at either end of the list.
+func (r *repo) GetNeighbourRank(ctx context.Context, workspaceID uuid.UUID, rank string, next bool) (*string, error) {
+	r.log.Info(ctx, "business.repository.stories.GetNeighbourRank")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetNeighbourRank")
+	defer span.End()
+
+	query := `
+		SELECT rank
+		FROM stories
+		WHERE workspace_id = $1 AND rank < $2
+		ORDER BY rank DESC
+		LIMIT 1`
+	if next {
+		query = `
+			SELECT rank
+			FROM stories
+			WHERE workspace_id = $1 AND rank > $2
+			ORDER BY rank ASC
+			LIMIT 1`
+	}
+
+	var neighbour string
+	if err := r.db.GetContext(ctx, &neighbour, query, workspaceID, rank); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		errMsg := fmt.Sprintf("failed to get neighbour rank: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get neighbour rank"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+	return &neighbour, nil
+}
+
+// NextStoryRank returns the rank for a story added to the start of a
+// workspace's list, so the default order is newest first. It takes a
+// workspace lock held until tx ends, so stories created at the same time
+// each read the rank the other wrote and never share one. Modules that
+// insert stories directly rank them with it too.
+func NextStoryRank(ctx context.Context, tx *sqlx.Tx, workspaceID uuid.UUID) (string, error) {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('story_rank:' || $1::text))`, workspaceID); err != nil {
+		return "", fmt.Errorf("failed to lock story ranks: %w", err)
+	}
+	var first sql.NullString
+	if err := tx.GetContext(ctx, &first, `SELECT MIN(rank) FROM stories WHERE workspace_id = $1`, workspaceID); err != nil {
+		return "", fmt.Errorf("failed to get first story rank: %w", err)
+	}
+	return stories.RankKeyBefore(first.String), nil
+}
+
+// UpdateStoryRank sets the rank of a story. The story's updated_at is left
+// alone since reordering does not change the story.
+func (r *repo) UpdateStoryRank(ctx context.Context, storyID, workspaceID uuid.UUID, rank string) error {
+	r.log.Info(ctx, "business.repository.stories.UpdateStoryRank")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.UpdateStoryRank")
+	defer span.End()
+
+	query := `
+		UPDATE stories
+		SET rank = $3
+		WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, storyID, workspaceID, rank)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to update story rank: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to update story rank"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return stories.ErrNotFound
+	}
+
+	span.AddEvent("story rank updated", trace.WithAttributes(
+		attribute.String("story.id", storyID.String()),
+	))
+	return nil
+}
+
+// RebalanceStoryRanks respaces every story rank in a workspace, keeping the
+// current order. Stories sharing a rank keep newest first.
+func (r *repo) RebalanceStoryRanks(ctx context.Context, workspaceID uuid.UUID) error {
+	r.log.Info(ctx, "business.repository.stories.RebalanceStoryRanks")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.RebalanceStoryRanks")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the workspace's stories so moves made while respacing wait for it.
+	var ids []uuid.UUID
+	query := `
+		SELECT id
+		FROM stories
+		WHERE workspace_id = $1
+		ORDER BY rank ASC NULLS LAST, created_at DESC, id
+		FOR UPDATE`
+	if err := tx.SelectContext(ctx, &ids, query, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to get stories to rebalance: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get stories to rebalance"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	updateQuery := `
+		UPDATE stories s
+		SET rank = v.rank
+		FROM unnest($2::uuid[], $3::text[]) AS v(id, rank)
+		WHERE s.id = v.id AND s.workspace_id = $1`
+	if _, err := tx.ExecContext(ctx, updateQuery, workspaceID, ids, stories.SpacedRankKeys(len(ids))); err != nil {
+		errMsg := fmt.Sprintf("failed to rebalance story ranks: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to rebalance story ranks"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	span.AddEvent("story ranks rebalanced", trace.WithAttributes(
+		attribute.String("workspace.id", workspaceID.String()),
+		attribute.Int("stories.count", len(ids)),
+	))
+	return nil
+}
@@ -0,0 +1,130 @@
+package storiesrepository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/outbox"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// commentReactionsSelect aggregates the reactions on the comment with the
+// given alias into a JSON array, ordered by each emoji's first use.
+func commentReactionsSelect(alias string) string {
+	return fmt.Sprintf(`
+		COALESCE(
+			(
+				SELECT json_agg(
+					json_build_object('emoji', r.emoji, 'count', r.count, 'user_ids', r.user_ids)
+					ORDER BY r.first_reacted_at
+				)
+				FROM (
+					SELECT
+						cr.emoji,
+						COUNT(*) AS count,
+						json_agg(cr.user_id ORDER BY cr.created_at) AS user_ids,
+						MIN(cr.created_at) AS first_reacted_at
+					FROM comment_reactions cr
+					WHERE cr.comment_id = %s.comment_id
+					GROUP BY cr.emoji
+				) r
+			), '[]'
+		)`, alias)
+}
+
+// ToggleReaction adds the user's reaction to a comment, or removes it when
+// they already reacted with the emoji, and writes any outbox events in the
+// same transaction. It reports whether the reaction was added.
+func (r *repo) ToggleReaction(ctx context.Context, commentID, userID uuid.UUID, emoji string, outboxEvents ...events.Event) (bool, error) {
+	r.log.Info(ctx, "business.repository.stories.ToggleReaction")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.ToggleReaction")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM comment_reactions
+		WHERE comment_id = $1 AND user_id = $2 AND emoji = $3`,
+		commentID, userID, emoji)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to remove reaction: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to remove reaction"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return false, err
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if removed == 0 {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO comment_reactions (comment_id, user_id, emoji)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING`,
+			commentID, userID, emoji); err != nil {
+			errMsg := fmt.Sprintf("failed to add reaction: %s", err)
+			r.log.Error(ctx, errMsg)
+			span.RecordError(errors.New("failed to add reaction"), trace.WithAttributes(attribute.String("error", errMsg)))
+			return false, err
+		}
+	}
+
+	if err := outbox.Write(ctx, tx, outboxEvents...); err != nil {
+		r.log.Error(ctx, fmt.Sprintf("failed to write reaction events: %s", err))
+		span.RecordError(err)
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	span.AddEvent("reaction toggled", trace.WithAttributes(
+		attribute.String("comment.id", commentID.String()),
+		attribute.Bool("reaction.added", removed == 0),
+	))
+	return removed == 0, nil
+}
+
+// GetCommentReactions returns the reactions on a comment.
+func (r *repo) GetCommentReactions(ctx context.Context, commentID uuid.UUID) ([]comments.CoreReaction, error) {
+	r.log.Info(ctx, "business.repository.stories.GetCommentReactions")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetCommentReactions")
+	defer span.End()
+
+	query := `
+		SELECT ` + commentReactionsSelect("sc") + ` AS reactions
+		FROM story_comments sc
+		WHERE sc.comment_id = $1`
+
+	var raw []byte
+	if err := r.db.GetContext(ctx, &raw, query, commentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, stories.ErrCommentNotFound
+		}
+		errMsg := fmt.Sprintf("failed to get comment reactions: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get comment reactions"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	reactions := []comments.CoreReaction{}
+	if err := json.Unmarshal(raw, &reactions); err != nil {
+		return nil, fmt.Errorf("failed to decode comment reactions: %w", err)
+	}
+	return reactions, nil
+}
@@ -0,0 +1,239 @@
+package storiesrepository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/outbox"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type dbTeamSchedule struct {
+	TeamID              uuid.UUID `db:"team_id"`
+	CascadeDependencies bool      `db:"cascade_dependencies"`
+}
+
+type dbTeamHoliday struct {
+	TeamID      uuid.UUID `db:"team_id"`
+	HolidayDate time.Time `db:"holiday_date"`
+}
+
+// GetScheduleDependents returns a story, every story that transitively
+// depends on it and the blockers of those stories, with the blocking links
+// into the dependents. Deleted stories are left out.
+func (r *repo) GetScheduleDependents(ctx context.Context, storyID, workspaceID uuid.UUID) ([]stories.CoreDependencyNode, []stories.CoreDependencyEdge, error) {
+	r.log.Info(ctx, "business.repository.stories.GetScheduleDependents")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetScheduleDependents")
+	defer span.End()
+
+	query := `
+		WITH RECURSIVE dependents(story_id) AS (
+			SELECT $1::uuid
+			UNION
+			SELECT sa.to_story_id
+			FROM story_associations sa
+			INNER JOIN dependents d ON sa.from_story_id = d.story_id
+			INNER JOIN stories ds ON ds.id = sa.to_story_id AND ds.deleted_at IS NULL
+			WHERE sa.association_type = 'blocking' AND sa.workspace_id = $2
+		),
+		graph AS (
+			SELECT story_id FROM dependents
+			UNION
+			SELECT sa.from_story_id
+			FROM story_associations sa
+			INNER JOIN dependents d ON sa.to_story_id = d.story_id
+			WHERE sa.association_type = 'blocking' AND sa.workspace_id = $2
+		)
+		SELECT
+			s.id,
+			s.sequence_id,
+			s.title,
+			s.team_id,
+			t.code AS team_code,
+			s.status_id,
+			stat.category AS status_category,
+			s.assignee_id,
+			s.estimate_unit,
+			s.start_date,
+			s.end_date,
+			s.completed_at,
+			s.id = $1 AS in_scope
+		FROM graph g
+		INNER JOIN stories s ON s.id = g.story_id AND s.deleted_at IS NULL
+		INNER JOIN teams t ON s.team_id = t.team_id
+		LEFT JOIN statuses stat ON s.status_id = stat.status_id
+		WHERE s.workspace_id = $2
+		ORDER BY t.code, s.sequence_id`
+
+	var rows []dbDependencyNode
+	if err := r.db.SelectContext(ctx, &rows, query, storyID, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to get schedule dependents: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get schedule dependents"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, nil, err
+	}
+
+	nodes := make([]stories.CoreDependencyNode, len(rows))
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		nodes[i] = stories.CoreDependencyNode{
+			ID:            row.ID,
+			SequenceID:    row.SequenceID,
+			Title:         row.Title,
+			TeamID:        row.TeamID,
+			TeamCode:      row.TeamCode,
+			StatusID:      row.StatusID,
+			AssigneeID:    row.AssigneeID,
+			EstimateValue: row.EstimateValue,
+			StartDate:     row.StartDate,
+			EndDate:       row.EndDate,
+			CompletedAt:   row.CompletedAt,
+			InScope:       row.InScope,
+		}
+		if row.StatusCategory != nil {
+			nodes[i].StatusCategory = *row.StatusCategory
+		}
+		ids[i] = row.ID
+	}
+	if len(ids) == 0 {
+		return nodes, []stories.CoreDependencyEdge{}, nil
+	}
+
+	var edgeRows []dbDependencyEdge
+	edgeQuery := `
+		SELECT id, from_story_id, to_story_id
+		FROM story_associations
+		WHERE association_type = 'blocking'
+			AND workspace_id = $1
+			AND from_story_id = ANY($2)
+			AND to_story_id = ANY($2)
+		ORDER BY created_at, id`
+	if err := r.db.SelectContext(ctx, &edgeRows, edgeQuery, workspaceID, ids); err != nil {
+		errMsg := fmt.Sprintf("failed to get schedule links: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get schedule links"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, nil, err
+	}
+
+	edges := make([]stories.CoreDependencyEdge, len(edgeRows))
+	for i, row := range edgeRows {
+		edges[i] = stories.CoreDependencyEdge{
+			ID:        row.ID,
+			BlockerID: row.BlockerID,
+			BlockedID: row.BlockedID,
+		}
+	}
+
+	span.AddEvent("schedule dependents retrieved", trace.WithAttributes(
+		attribute.Int("graph.nodes", len(nodes)),
+		attribute.Int("graph.edges", len(edges)),
+	))
+	return nodes, edges, nil
+}
+
+// GetTeamSchedules returns the scheduling settings and holidays of teams.
+// Teams without settings do not cascade dependencies.
+func (r *repo) GetTeamSchedules(ctx context.Context, workspaceID uuid.UUID, teamIDs []uuid.UUID) (map[uuid.UUID]stories.CoreTeamSchedule, error) {
+	r.log.Info(ctx, "business.repository.stories.GetTeamSchedules")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetTeamSchedules")
+	defer span.End()
+
+	schedules := make(map[uuid.UUID]stories.CoreTeamSchedule, len(teamIDs))
+	if len(teamIDs) == 0 {
+		return schedules, nil
+	}
+
+	query := `
+		SELECT t.team_id, COALESCE(tss.cascade_dependencies, false) AS cascade_dependencies
+		FROM teams t
+		LEFT JOIN team_scheduling_settings tss ON tss.team_id = t.team_id
+		WHERE t.workspace_id = $1 AND t.team_id = ANY($2)`
+
+	var rows []dbTeamSchedule
+	if err := r.db.SelectContext(ctx, &rows, query, workspaceID, teamIDs); err != nil {
+		errMsg := fmt.Sprintf("failed to get team schedules: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get team schedules"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+	for _, row := range rows {
+		schedules[row.TeamID] = stories.CoreTeamSchedule{
+			CascadeDependencies: row.CascadeDependencies,
+			Holidays:            map[string]bool{},
+		}
+	}
+
+	holidayQuery := `
+		SELECT team_id, holiday_date
+		FROM team_holidays
+		WHERE workspace_id = $1 AND team_id = ANY($2)`
+
+	var holidays []dbTeamHoliday
+	if err := r.db.SelectContext(ctx, &holidays, holidayQuery, workspaceID, teamIDs); err != nil {
+		errMsg := fmt.Sprintf("failed to get team holidays: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get team holidays"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+	for _, holiday := range holidays {
+		if schedule, ok := schedules[holiday.TeamID]; ok {
+			schedule.Holidays[holiday.HolidayDate.Format("2006-01-02")] = true
+		}
+	}
+
+	return schedules, nil
+}
+
+// RescheduleStories moves the stories of a schedule cascade to their new
+// dates and writes outboxEvents in one transaction. A story that was deleted
+// in the meantime fails the whole cascade with ErrNotFound.
+func (r *repo) RescheduleStories(ctx context.Context, workspaceID uuid.UUID, changes []stories.CoreScheduleChange, outboxEvents ...events.Event) error {
+	r.log.Info(ctx, "business.repository.stories.RescheduleStories")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.RescheduleStories")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE stories
+		SET start_date = $3, end_date = COALESCE($4, end_date), updated_at = NOW()
+		WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL`
+	for _, change := range changes {
+		count, err := execCount(ctx, tx, query, change.StoryID, workspaceID, change.NewStartDate, change.NewEndDate)
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to reschedule story: %s", err)
+			r.log.Error(ctx, errMsg, "story_id", change.StoryID)
+			span.RecordError(errors.New("failed to reschedule story"), trace.WithAttributes(attribute.String("error", errMsg)))
+			return err
+		}
+		if count == 0 {
+			return stories.ErrNotFound
+		}
+	}
+
+	if err := outbox.Write(ctx, tx, outboxEvents...); err != nil {
+		r.log.Error(ctx, fmt.Sprintf("failed to write schedule events: %s", err))
+		span.RecordError(err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	span.AddEvent("stories rescheduled", trace.WithAttributes(
+		attribute.Int("stories.count", len(changes)),
+	))
+	return nil
+}
@@ -0,0 +1,254 @@
+package storiesrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// slaBreachedQuery finds the SLA clock of story s when a target is breached
+// or past due and not yet met.
+const slaBreachedQuery = `(
+	SELECT 1 FROM story_sla_clocks sc
+	WHERE sc.story_id = s.id
+		AND (
+			sc.first_response_breached_at IS NOT NULL
+			OR sc.resolve_breached_at IS NOT NULL
+			OR (sc.first_responded_at IS NULL AND sc.first_response_due_at <= NOW())
+			OR (sc.resolved_at IS NULL AND sc.resolve_due_at <= NOW())
+		)
+)`
+
+type dbSLAPolicy struct {
+	Priority            string  `db:"priority"`
+	FirstResponseTarget *string `db:"first_response_target"`
+	ResolveTarget       *string `db:"resolve_target"`
+	Timezone            string  `db:"timezone"`
+	WorkdayStart        int     `db:"workday_start"`
+	WorkdayEnd          int     `db:"workday_end"`
+	AtRiskPercent       int     `db:"at_risk_percent"`
+}
+
+type dbStorySLAClock struct {
+	StoryID                 uuid.UUID  `db:"story_id"`
+	WorkspaceID             uuid.UUID  `db:"workspace_id"`
+	Priority                string     `db:"priority"`
+	FirstResponseMinutes    *int       `db:"first_response_minutes"`
+	ResolveMinutes          *int       `db:"resolve_minutes"`
+	ElapsedMinutes          int        `db:"elapsed_minutes"`
+	RunningSince            *time.Time `db:"running_since"`
+	FirstResponseAtRiskAt   *time.Time `db:"first_response_at_risk_at"`
+	FirstResponseDueAt      *time.Time `db:"first_response_due_at"`
+	FirstRespondedAt        *time.Time `db:"first_responded_at"`
+	FirstResponseBreachedAt *time.Time `db:"first_response_breached_at"`
+	FirstResponseAlert      *string    `db:"first_response_alert"`
+	ResolveAtRiskAt         *time.Time `db:"resolve_at_risk_at"`
+	ResolveDueAt            *time.Time `db:"resolve_due_at"`
+	ResolvedAt              *time.Time `db:"resolved_at"`
+	ResolveBreachedAt       *time.Time `db:"resolve_breached_at"`
+	ResolveAlert            *string    `db:"resolve_alert"`
+}
+
+// GetSLAPolicy returns a team's SLA policy for a priority, matched without
+// regard to case, with the team's business calendar. It returns nil when the
+// team has no policy for the priority. Teams without SLA settings work 9:00
+// to 17:00 UTC.
+func (r *repo) GetSLAPolicy(ctx context.Context, workspaceID, teamID uuid.UUID, priority string) (*stories.CoreSLAPolicy, error) {
+	r.log.Info(ctx, "business.repository.stories.GetSLAPolicy")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetSLAPolicy")
+	defer span.End()
+
+	query := `
+		SELECT
+			p.priority,
+			p.first_response_target,
+			p.resolve_target,
+			COALESCE(ss.timezone, 'UTC') AS timezone,
+			COALESCE(EXTRACT(EPOCH FROM ss.workday_start)::int / 60, 540) AS workday_start,
+			COALESCE(EXTRACT(EPOCH FROM ss.workday_end)::int / 60, 1020) AS workday_end,
+			COALESCE(ss.at_risk_percent, 80) AS at_risk_percent
+		FROM team_sla_policies p
+		LEFT JOIN team_sla_settings ss ON ss.team_id = p.team_id
+		WHERE p.workspace_id = $1 AND p.team_id = $2 AND lower(p.priority) = lower($3)`
+
+	var row dbSLAPolicy
+	if err := r.db.GetContext(ctx, &row, query, workspaceID, teamID, priority); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		errMsg := fmt.Sprintf("failed to get SLA policy: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get SLA policy"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	var holidays []time.Time
+	holidayQuery := `
+		SELECT holiday_date
+		FROM team_holidays
+		WHERE workspace_id = $1 AND team_id = $2`
+	if err := r.db.SelectContext(ctx, &holidays, holidayQuery, workspaceID, teamID); err != nil {
+		errMsg := fmt.Sprintf("failed to get team holidays: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get team holidays"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(row.Timezone)
+	if err != nil {
+		r.log.Error(ctx, fmt.Sprintf("unknown SLA timezone %q, using UTC", row.Timezone), "team_id", teamID)
+		location = time.UTC
+	}
+	policy := &stories.CoreSLAPolicy{
+		Priority:            row.Priority,
+		FirstResponseTarget: row.FirstResponseTarget,
+		ResolveTarget:       row.ResolveTarget,
+		Calendar: stories.CoreSLACalendar{
+			Location:      location,
+			WorkdayStart:  row.WorkdayStart,
+			WorkdayEnd:    row.WorkdayEnd,
+			AtRiskPercent: row.AtRiskPercent,
+			Holidays:      make(map[string]bool, len(holidays)),
+		},
+	}
+	for _, holiday := range holidays {
+		policy.Calendar.Holidays[holiday.Format("2006-01-02")] = true
+	}
+	return policy, nil
+}
+
+// GetStorySLAClock returns the SLA clock of a story, or nil when the story
+// has none.
+func (r *repo) GetStorySLAClock(ctx context.Context, storyID, workspaceID uuid.UUID) (*stories.CoreStorySLAClock, error) {
+	r.log.Info(ctx, "business.repository.stories.GetStorySLAClock")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetStorySLAClock")
+	defer span.End()
+
+	query := `
+		SELECT
+			story_id, workspace_id, priority, first_response_minutes, resolve_minutes,
+			elapsed_minutes, running_since,
+			first_response_at_risk_at, first_response_due_at, first_responded_at,
+			first_response_breached_at, first_response_alert,
+			resolve_at_risk_at, resolve_due_at, resolved_at,
+			resolve_breached_at, resolve_alert
+		FROM story_sla_clocks
+		WHERE story_id = $1 AND workspace_id = $2`
+
+	var row dbStorySLAClock
+	if err := r.db.GetContext(ctx, &row, query, storyID, workspaceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		errMsg := fmt.Sprintf("failed to get story SLA clock: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get story SLA clock"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	clock := stories.CoreStorySLAClock(row)
+	return &clock, nil
+}
+
+// SaveStorySLAClock creates or replaces the SLA clock of a story.
+func (r *repo) SaveStorySLAClock(ctx context.Context, clock stories.CoreStorySLAClock) error {
+	r.log.Info(ctx, "business.repository.stories.SaveStorySLAClock")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.SaveStorySLAClock")
+	defer span.End()
+
+	query := `
+		INSERT INTO story_sla_clocks (
+			story_id, workspace_id, priority, first_response_minutes, resolve_minutes,
+			elapsed_minutes, running_since,
+			first_response_at_risk_at, first_response_due_at, first_responded_at,
+			first_response_breached_at, first_response_alert,
+			resolve_at_risk_at, resolve_due_at, resolved_at,
+			resolve_breached_at, resolve_alert
+		) VALUES (
+			:story_id, :workspace_id, :priority, :first_response_minutes, :resolve_minutes,
+			:elapsed_minutes, :running_since,
+			:first_response_at_risk_at, :first_response_due_at, :first_responded_at,
+			:first_response_breached_at, :first_response_alert,
+			:resolve_at_risk_at, :resolve_due_at, :resolved_at,
+			:resolve_breached_at, :resolve_alert
+		)
+		ON CONFLICT (story_id) DO UPDATE SET
+			priority = EXCLUDED.priority,
+			first_response_minutes = EXCLUDED.first_response_minutes,
+			resolve_minutes = EXCLUDED.resolve_minutes,
+			elapsed_minutes = EXCLUDED.elapsed_minutes,
+			running_since = EXCLUDED.running_since,
+			first_response_at_risk_at = EXCLUDED.first_response_at_risk_at,
+			first_response_due_at = EXCLUDED.first_response_due_at,
+			first_responded_at = EXCLUDED.first_responded_at,
+			first_response_breached_at = EXCLUDED.first_response_breached_at,
+			first_response_alert = EXCLUDED.first_response_alert,
+			resolve_at_risk_at = EXCLUDED.resolve_at_risk_at,
+			resolve_due_at = EXCLUDED.resolve_due_at,
+			resolved_at = EXCLUDED.resolved_at,
+			resolve_breached_at = EXCLUDED.resolve_breached_at,
+			resolve_alert = EXCLUDED.resolve_alert,
+			updated_at = NOW()`
+
+	if _, err := r.db.NamedExecContext(ctx, query, dbStorySLAClock(clock)); err != nil {
+		errMsg := fmt.Sprintf("failed to save story SLA clock: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to save story SLA clock"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+
+	span.AddEvent("story SLA clock saved", trace.WithAttributes(
+		attribute.String("story.id", clock.StoryID.String()),
+	))
+	return nil
+}
+
+// DeleteStorySLAClock drops the SLA clock of a story no policy covers.
+func (r *repo) DeleteStorySLAClock(ctx context.Context, storyID, workspaceID uuid.UUID) error {
+	r.log.Info(ctx, "business.repository.stories.DeleteStorySLAClock")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.DeleteStorySLAClock")
+	defer span.End()
+
+	query := `DELETE FROM story_sla_clocks WHERE story_id = $1 AND workspace_id = $2`
+	if _, err := r.db.ExecContext(ctx, query, storyID, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to delete story SLA clock: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to delete story SLA clock"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	return nil
+}
+
+// ListOpenTeamStoryIDs returns the stories of a team that are not completed,
+// cancelled, archived or deleted.
+func (r *repo) ListOpenTeamStoryIDs(ctx context.Context, workspaceID, teamID uuid.UUID) ([]uuid.UUID, error) {
+	r.log.Info(ctx, "business.repository.stories.ListOpenTeamStoryIDs")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.ListOpenTeamStoryIDs")
+	defer span.End()
+
+	query := `
+		SELECT s.id
+		FROM stories s
+		LEFT JOIN statuses st ON st.status_id = s.status_id
+		WHERE s.workspace_id = $1 AND s.team_id = $2
+			AND s.deleted_at IS NULL AND s.archived_at IS NULL
+			AND (st.category IS NULL OR st.category NOT IN ('completed', 'cancelled'))
+		ORDER BY s.created_at`
+
+	var ids []uuid.UUID
+	if err := r.db.SelectContext(ctx, &ids, query, workspaceID, teamID); err != nil {
+		errMsg := fmt.Sprintf("failed to list open team stories: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to list open team stories"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+	return ids, nil
+}
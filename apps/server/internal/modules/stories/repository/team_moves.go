@@ -0,0 +1,261 @@
+package storiesrepository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/outbox"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type dbTeamStatus struct {
+	ID         uuid.UUID `db:"status_id"`
+	Category   *string   `db:"category"`
+	IsDefault  bool      `db:"is_default"`
+	OrderIndex *int      `db:"order_index"`
+}
+
+type dbTeamMoveSource struct {
+	TeamID     uuid.UUID  `db:"team_id"`
+	TeamCode   string     `db:"team_code"`
+	SequenceID int        `db:"sequence_id"`
+	StatusID   *uuid.UUID `db:"status_id"`
+	SprintID   *uuid.UUID `db:"sprint_id"`
+	EpicID     *uuid.UUID `db:"epic_id"`
+}
+
+type dbDroppedFieldValue struct {
+	FieldID uuid.UUID       `db:"field_id"`
+	Value   json.RawMessage `db:"value"`
+}
+
+type dbTeamMoveTarget struct {
+	SequenceID int        `db:"sequence_id"`
+	SprintID   *uuid.UUID `db:"sprint_id"`
+}
+
+// GetTeamStatuses returns a team's statuses in workflow order.
+func (r *repo) GetTeamStatuses(ctx context.Context, workspaceID, teamID uuid.UUID) ([]stories.CoreTeamStatus, error) {
+	r.log.Info(ctx, "business.repository.stories.GetTeamStatuses")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetTeamStatuses")
+	defer span.End()
+
+	query := `
+		SELECT status_id, category, is_default, order_index
+		FROM statuses
+		WHERE workspace_id = $1 AND team_id = $2
+		ORDER BY order_index NULLS LAST, created_at`
+
+	var rows []dbTeamStatus
+	if err := r.db.SelectContext(ctx, &rows, query, workspaceID, teamID); err != nil {
+		errMsg := fmt.Sprintf("failed to get team statuses: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get team statuses"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	statuses := make([]stories.CoreTeamStatus, len(rows))
+	for i, row := range rows {
+		statuses[i] = stories.CoreTeamStatus{ID: row.ID, IsDefault: row.IsDefault}
+		if row.Category != nil {
+			statuses[i].Category = *row.Category
+		}
+		if row.OrderIndex != nil {
+			statuses[i].OrderIndex = *row.OrderIndex
+		}
+	}
+	return statuses, nil
+}
+
+// MoveStoriesToTeam gives each story the next reference of its target team
+// and the status in NewStatusID, and records its old reference as an alias.
+// A story's sprint is dropped unless it belongs to the target team. All the
+// moves and the events moveEvents builds from them are committed together.
+// The batch is retried after resyncing when a sequence is out of step.
+func (r *repo) MoveStoriesToTeam(ctx context.Context, moves []stories.CoreTeamMove, moveEvents func([]stories.CoreTeamMove) []events.Event) ([]stories.CoreTeamMove, error) {
+	r.log.Info(ctx, "business.repository.stories.MoveStoriesToTeam")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.MoveStoriesToTeam")
+	defer span.End()
+
+	const maxRetries = 3
+	var lastErr error
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		moved, err := r.moveStoriesToTeam(ctx, moves, moveEvents)
+		if err == nil {
+			span.AddEvent("stories moved to team", trace.WithAttributes(
+				attribute.Int("stories.count", len(moved)),
+			))
+			return moved, nil
+		}
+		if !strings.Contains(err.Error(), "unique_team_sequence") {
+			span.RecordError(err)
+			return nil, err
+		}
+
+		r.log.Info(ctx, "sequence out of sync, retrying team move", "attempt", attempt)
+		synced := map[uuid.UUID]bool{}
+		for _, move := range moves {
+			if synced[move.ToTeamID] {
+				continue
+			}
+			synced[move.ToTeamID] = true
+			if syncErr := r.syncSequence(ctx, move.ToTeamID, move.WorkspaceID); syncErr != nil {
+				return nil, fmt.Errorf("failed to sync sequence: %w", syncErr)
+			}
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("failed to move stories after %d retries: %w", maxRetries, lastErr)
+}
+
+func (r *repo) moveStoriesToTeam(ctx context.Context, moves []stories.CoreTeamMove, moveEvents func([]stories.CoreTeamMove) []events.Event) ([]stories.CoreTeamMove, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	moved := make([]stories.CoreTeamMove, 0, len(moves))
+	for _, move := range moves {
+		m, err := r.moveStoryToTeam(ctx, tx, move)
+		if err != nil {
+			return nil, err
+		}
+		moved = append(moved, m)
+	}
+
+	if moveEvents != nil {
+		if err := outbox.Write(ctx, tx, moveEvents(moved)...); err != nil {
+			r.log.Error(ctx, fmt.Sprintf("failed to write team move events: %s", err))
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return moved, nil
+}
+
+func (r *repo) moveStoryToTeam(ctx context.Context, tx *sqlx.Tx, move stories.CoreTeamMove) (stories.CoreTeamMove, error) {
+	var source dbTeamMoveSource
+	sourceQuery := `
+		SELECT s.team_id, t.code AS team_code, s.sequence_id, s.status_id, s.sprint_id, s.epic_id
+		FROM stories s
+		INNER JOIN teams t ON t.team_id = s.team_id
+		WHERE s.id = $1 AND s.workspace_id = $2 AND s.deleted_at IS NULL
+		FOR UPDATE OF s`
+	if err := tx.GetContext(ctx, &source, sourceQuery, move.StoryID, move.WorkspaceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return stories.CoreTeamMove{}, stories.ErrNotFound
+		}
+		r.log.Error(ctx, fmt.Sprintf("failed to load story to move: %s", err), "story_id", move.StoryID)
+		return stories.CoreTeamMove{}, err
+	}
+
+	var teamCode string
+	if err := tx.GetContext(ctx, &teamCode, `SELECT code FROM teams WHERE team_id = $1 AND workspace_id = $2`, move.ToTeamID, move.WorkspaceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return stories.CoreTeamMove{}, stories.ErrTargetTeamNotFound
+		}
+		return stories.CoreTeamMove{}, err
+	}
+
+	lastSequence, err := r.nextSequenceID(ctx, tx, move.ToTeamID, move.WorkspaceID)
+	if err != nil {
+		return stories.CoreTeamMove{}, err
+	}
+
+	aliasQuery := `
+		INSERT INTO story_ref_aliases (team_id, sequence_id, story_id, workspace_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (team_id, sequence_id) DO UPDATE SET story_id = EXCLUDED.story_id`
+	if _, err := tx.ExecContext(ctx, aliasQuery, source.TeamID, source.SequenceID, move.StoryID, move.WorkspaceID); err != nil {
+		r.log.Error(ctx, fmt.Sprintf("failed to save story ref alias: %s", err), "story_id", move.StoryID)
+		return stories.CoreTeamMove{}, err
+	}
+
+	// Epics belong to a single team, so the story leaves its epic behind.
+	var target dbTeamMoveTarget
+	updateQuery := `
+		UPDATE stories s
+		SET team_id = $3,
+			sequence_id = $4,
+			status_id = $5,
+			sprint_id = CASE
+				WHEN EXISTS (SELECT 1 FROM sprints sp WHERE sp.sprint_id = s.sprint_id AND sp.team_id = $3)
+				THEN s.sprint_id
+			END,
+			epic_id = NULL,
+			updated_at = NOW()
+		WHERE s.id = $1 AND s.workspace_id = $2
+		RETURNING s.sequence_id, s.sprint_id`
+	if err := tx.GetContext(ctx, &target, updateQuery, move.StoryID, move.WorkspaceID, move.ToTeamID, lastSequence+1, move.NewStatusID); err != nil {
+		r.log.Error(ctx, fmt.Sprintf("failed to move story to team: %s", err), "story_id", move.StoryID)
+		return stories.CoreTeamMove{}, err
+	}
+
+	// Values of another team's custom fields are dropped; workspace fields
+	// apply in every team and keep their values.
+	var dropped []dbDroppedFieldValue
+	dropQuery := `
+		DELETE FROM story_custom_field_values v
+		USING custom_fields cf
+		WHERE v.story_id = $1
+			AND cf.id = v.field_id
+			AND cf.team_id IS NOT NULL
+			AND cf.team_id <> $2
+		RETURNING v.field_id, v.value`
+	if err := tx.SelectContext(ctx, &dropped, dropQuery, move.StoryID, move.ToTeamID); err != nil {
+		r.log.Error(ctx, fmt.Sprintf("failed to drop custom field values of moved story: %s", err), "story_id", move.StoryID)
+		return stories.CoreTeamMove{}, err
+	}
+	if len(dropped) > 0 {
+		move.DroppedFieldValues = make(map[uuid.UUID]json.RawMessage, len(dropped))
+		for _, value := range dropped {
+			move.DroppedFieldValues[value.FieldID] = value.Value
+		}
+	}
+
+	move.FromTeamID = source.TeamID
+	move.OldTeamCode = source.TeamCode
+	move.OldSequenceID = source.SequenceID
+	move.OldStatusID = source.StatusID
+	move.OldSprintID = source.SprintID
+	move.OldEpicID = source.EpicID
+	move.NewTeamCode = teamCode
+	move.NewSequenceID = target.SequenceID
+	move.NewSprintID = target.SprintID
+	return move, nil
+}
+
+// storyRefAlias returns the current reference of the story that had the
+// given reference before it moved to another team.
+func (r *repo) storyRefAlias(ctx context.Context, workspaceID uuid.UUID, teamCode string, sequenceID int) (string, int, error) {
+	query := `
+		SELECT ct.code, s.sequence_id
+		FROM story_ref_aliases a
+		INNER JOIN teams t ON t.team_id = a.team_id
+		INNER JOIN stories s ON s.id = a.story_id AND s.deleted_at IS NULL
+		INNER JOIN teams ct ON ct.team_id = s.team_id
+		WHERE a.workspace_id = $1 AND t.code = $2 AND a.sequence_id = $3`
+
+	var current struct {
+		TeamCode   string `db:"code"`
+		SequenceID int    `db:"sequence_id"`
+	}
+	if err := r.db.GetContext(ctx, &current, query, workspaceID, teamCode, sequenceID); err != nil {
+		return "", 0, err
+	}
+	return current.TeamCode, current.SequenceID, nil
+}
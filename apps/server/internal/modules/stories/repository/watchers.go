@@ -0,0 +1,117 @@
+package storiesrepository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// watchersSelect aggregates a story's watcher IDs into a JSON array.
+const watchersSelect = `
+	COALESCE(
+		(
+			SELECT json_agg(sw.user_id ORDER BY sw.created_at)
+			FROM story_watchers sw
+			WHERE sw.story_id = s.id
+		), '[]'
+	) AS watchers`
+
+type dbStoryWatcher struct {
+	UserID    uuid.UUID `db:"user_id"`
+	Source    string    `db:"source"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (r *repo) ListWatchers(ctx context.Context, storyID, workspaceID uuid.UUID) ([]stories.CoreStoryWatcher, error) {
+	r.log.Info(ctx, "business.repository.stories.ListWatchers")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.ListWatchers")
+	defer span.End()
+
+	var rows []dbStoryWatcher
+	query := `
+		SELECT user_id, source, created_at
+		FROM story_watchers
+		WHERE story_id = $1 AND workspace_id = $2
+		ORDER BY created_at`
+	if err := r.db.SelectContext(ctx, &rows, query, storyID, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to list story watchers: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to list story watchers"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	watchers := make([]stories.CoreStoryWatcher, len(rows))
+	for i, row := range rows {
+		watchers[i] = stories.CoreStoryWatcher{
+			UserID:    row.UserID,
+			Source:    row.Source,
+			CreatedAt: row.CreatedAt,
+		}
+	}
+	return watchers, nil
+}
+
+// AddWatchers subscribes users to a story. Users who are not members of the
+// workspace, system users and existing watchers are skipped.
+func (r *repo) AddWatchers(ctx context.Context, storyID, workspaceID uuid.UUID, userIDs []uuid.UUID, source string) error {
+	r.log.Info(ctx, "business.repository.stories.AddWatchers")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.AddWatchers")
+	defer span.End()
+
+	query := `
+		WITH story AS (
+			SELECT id FROM stories
+			WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+		),
+		added AS (
+			INSERT INTO story_watchers (story_id, user_id, workspace_id, source)
+			SELECT story.id, u.user_id, $2, $4
+			FROM story
+			CROSS JOIN users u
+			INNER JOIN workspace_members wm ON wm.user_id = u.user_id AND wm.workspace_id = $2
+			WHERE u.user_id = ANY($3) AND u.is_system = false
+			ON CONFLICT (story_id, user_id) DO NOTHING
+		)
+		SELECT EXISTS (SELECT 1 FROM story)`
+
+	var found bool
+	if err := r.db.GetContext(ctx, &found, query, storyID, workspaceID, userIDs, source); err != nil {
+		errMsg := fmt.Sprintf("failed to add story watchers: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to add story watchers"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	if !found {
+		return stories.ErrNotFound
+	}
+
+	span.AddEvent("story watchers added", trace.WithAttributes(
+		attribute.Int("watchers.count", len(userIDs)),
+		attribute.String("watchers.source", source),
+	))
+	return nil
+}
+
+// RemoveWatcher unsubscribes a user. Removing a user who is not watching is
+// not an error.
+func (r *repo) RemoveWatcher(ctx context.Context, storyID, workspaceID, userID uuid.UUID) error {
+	r.log.Info(ctx, "business.repository.stories.RemoveWatcher")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.RemoveWatcher")
+	defer span.End()
+
+	query := `DELETE FROM story_watchers WHERE story_id = $1 AND workspace_id = $2 AND user_id = $3`
+	if _, err := r.db.ExecContext(ctx, query, storyID, workspaceID, userID); err != nil {
+		errMsg := fmt.Sprintf("failed to remove story watcher: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to remove story watcher"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package storiesrepository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type dbStatusWIPLimit struct {
+	StatusID             uuid.UUID `db:"status_id"`
+	StatusName           string    `db:"status_name"`
+	TeamID               uuid.UUID `db:"team_id"`
+	MaxStories           int       `db:"max_stories"`
+	PerAssignee          bool      `db:"per_assignee"`
+	Enforcement          string    `db:"wip_enforcement"`
+	IntegrationsOverride bool      `db:"integrations_override"`
+}
+
+type dbStatusWIPCount struct {
+	StatusID   uuid.UUID  `db:"status_id"`
+	AssigneeID *uuid.UUID `db:"assignee_id"`
+	Count      int        `db:"count"`
+}
+
+// GetWIPLimits returns the WIP limits of the statuses that have one, with
+// their current story counts. Teams without workflow settings warn.
+func (r *repo) GetWIPLimits(ctx context.Context, workspaceID uuid.UUID, statusIDs []uuid.UUID) ([]stories.CoreStatusWIP, error) {
+	r.log.Info(ctx, "business.repository.stories.GetWIPLimits")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetWIPLimits")
+	defer span.End()
+
+	result, err := getWIPLimits(ctx, r.db, workspaceID, statusIDs, false)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to get WIP limits: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get WIP limits"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	span.AddEvent("WIP limits retrieved.", trace.WithAttributes(
+		attribute.Int("limits.count", len(result)),
+	))
+	return result, nil
+}
+
+// getWIPLimits runs the GetWIPLimits queries on q. With lock the limit rows
+// are locked until q's transaction ends, so moves into the same statuses
+// count their stories one at a time.
+func getWIPLimits(ctx context.Context, q sqlx.QueryerContext, workspaceID uuid.UUID, statusIDs []uuid.UUID, lock bool) ([]stories.CoreStatusWIP, error) {
+	if len(statusIDs) == 0 {
+		return nil, nil
+	}
+
+	var limits []dbStatusWIPLimit
+	limitsQuery := `
+		SELECT
+			l.status_id,
+			st.name AS status_name,
+			l.team_id,
+			l.max_stories,
+			l.per_assignee,
+			COALESCE(ws.wip_enforcement, 'warn') AS wip_enforcement,
+			COALESCE(ws.integrations_override, false) AS integrations_override
+		FROM team_wip_limits l
+		INNER JOIN statuses st ON st.status_id = l.status_id
+		LEFT JOIN team_workflow_settings ws ON ws.team_id = l.team_id
+		WHERE l.workspace_id = $1 AND l.status_id = ANY($2)
+		ORDER BY st.order_index`
+	if lock {
+		limitsQuery += " FOR UPDATE OF l"
+	}
+	if err := sqlx.SelectContext(ctx, q, &limits, limitsQuery, workspaceID, pq.Array(statusIDs)); err != nil {
+		return nil, err
+	}
+	if len(limits) == 0 {
+		return nil, nil
+	}
+
+	limitedIDs := make([]uuid.UUID, len(limits))
+	for i, limit := range limits {
+		limitedIDs[i] = limit.StatusID
+	}
+
+	var counts []dbStatusWIPCount
+	countsQuery := `
+		SELECT s.status_id, s.assignee_id, COUNT(*) AS count
+		FROM stories s
+		WHERE s.workspace_id = $1
+			AND s.status_id = ANY($2)
+			AND s.deleted_at IS NULL
+			AND s.archived_at IS NULL
+			AND s.is_draft = false
+		GROUP BY s.status_id, s.assignee_id`
+	if err := sqlx.SelectContext(ctx, q, &counts, countsQuery, workspaceID, pq.Array(limitedIDs)); err != nil {
+		return nil, fmt.Errorf("failed to count WIP stories: %w", err)
+	}
+
+	result := make([]stories.CoreStatusWIP, len(limits))
+	index := make(map[uuid.UUID]int, len(limits))
+	for i, limit := range limits {
+		index[limit.StatusID] = i
+		result[i] = stories.CoreStatusWIP{
+			StatusID:             limit.StatusID,
+			StatusName:           limit.StatusName,
+			TeamID:               limit.TeamID,
+			Limit:                limit.MaxStories,
+			PerAssignee:          limit.PerAssignee,
+			Enforcement:          limit.Enforcement,
+			IntegrationsOverride: limit.IntegrationsOverride,
+			AssigneeCounts:       make(map[uuid.UUID]int),
+		}
+	}
+	for _, count := range counts {
+		wip := &result[index[count.StatusID]]
+		wip.Count += count.Count
+		if count.AssigneeID != nil {
+			wip.AssigneeCounts[*count.AssigneeID] = count.Count
+		}
+	}
+	return result, nil
+}
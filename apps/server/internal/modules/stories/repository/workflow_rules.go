@@ -0,0 +1,138 @@
+package storiesrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type dbWorkflowStatus struct {
+	ID       uuid.UUID `db:"status_id"`
+	Name     string    `db:"name"`
+	Category string    `db:"category"`
+}
+
+type dbWorkflowTransition struct {
+	FromStatusID uuid.UUID      `db:"from_status_id"`
+	ToStatusID   uuid.UUID      `db:"to_status_id"`
+	AllowedRoles pq.StringArray `db:"allowed_roles"`
+}
+
+type dbWorkflowRequirement struct {
+	Category       string         `db:"category"`
+	RequiredFields pq.StringArray `db:"required_fields"`
+}
+
+// GetWorkflowRules returns a team's status transition rules with its
+// statuses. Teams without workflow settings have no rules.
+func (r *repo) GetWorkflowRules(ctx context.Context, workspaceID, teamID uuid.UUID) (*stories.CoreWorkflowRules, error) {
+	r.log.Info(ctx, "business.repository.stories.GetWorkflowRules")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetWorkflowRules")
+	defer span.End()
+
+	rules := &stories.CoreWorkflowRules{
+		Statuses:     make(map[uuid.UUID]stories.CoreWorkflowStatus),
+		Requirements: make(map[string][]string),
+	}
+
+	settingsQuery := `
+		SELECT restrict_transitions, integrations_override
+		FROM team_workflow_settings
+		WHERE workspace_id = $1 AND team_id = $2`
+	row := r.db.QueryRowxContext(ctx, settingsQuery, workspaceID, teamID)
+	if err := row.Scan(&rules.RestrictTransitions, &rules.IntegrationsOverride); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		errMsg := fmt.Sprintf("failed to get workflow settings: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get workflow settings"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	var transitions []dbWorkflowTransition
+	transitionsQuery := `
+		SELECT from_status_id, to_status_id, allowed_roles
+		FROM team_status_transitions
+		WHERE workspace_id = $1 AND team_id = $2
+		ORDER BY created_at, from_status_id, to_status_id`
+	if err := r.db.SelectContext(ctx, &transitions, transitionsQuery, workspaceID, teamID); err != nil {
+		errMsg := fmt.Sprintf("failed to get status transitions: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get status transitions"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+	for _, t := range transitions {
+		rules.Transitions = append(rules.Transitions, stories.CoreWorkflowTransition{
+			FromStatusID: t.FromStatusID,
+			ToStatusID:   t.ToStatusID,
+			AllowedRoles: []string(t.AllowedRoles),
+		})
+	}
+
+	var requirements []dbWorkflowRequirement
+	requirementsQuery := `
+		SELECT category, required_fields
+		FROM team_category_requirements
+		WHERE workspace_id = $1 AND team_id = $2`
+	if err := r.db.SelectContext(ctx, &requirements, requirementsQuery, workspaceID, teamID); err != nil {
+		errMsg := fmt.Sprintf("failed to get category requirements: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get category requirements"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+	for _, requirement := range requirements {
+		rules.Requirements[requirement.Category] = []string(requirement.RequiredFields)
+	}
+
+	var statuses []dbWorkflowStatus
+	statusesQuery := `
+		SELECT status_id, name, COALESCE(category, '') AS category
+		FROM statuses
+		WHERE workspace_id = $1 AND team_id = $2`
+	if err := r.db.SelectContext(ctx, &statuses, statusesQuery, workspaceID, teamID); err != nil {
+		errMsg := fmt.Sprintf("failed to get team statuses: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get team statuses"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+	for _, status := range statuses {
+		rules.Statuses[status.ID] = stories.CoreWorkflowStatus{
+			ID:       status.ID,
+			Name:     status.Name,
+			Category: status.Category,
+		}
+	}
+
+	span.AddEvent("workflow rules retrieved.", trace.WithAttributes(
+		attribute.Int("transitions.count", len(rules.Transitions)),
+		attribute.Int("requirements.count", len(rules.Requirements)),
+	))
+	return rules, nil
+}
+
+// GetWorkspaceRole returns the user's role in the workspace. Users outside
+// the workspace, such as integration bots, count as system users.
+func (r *repo) GetWorkspaceRole(ctx context.Context, workspaceID, userID uuid.UUID) (string, error) {
+	r.log.Info(ctx, "business.repository.stories.GetWorkspaceRole")
+	ctx, span := web.AddSpan(ctx, "business.repository.stories.GetWorkspaceRole")
+	defer span.End()
+
+	var role string
+	query := `SELECT role::text FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`
+	if err := r.db.GetContext(ctx, &role, query, workspaceID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "system", nil
+		}
+		errMsg := fmt.Sprintf("failed to get workspace role: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get workspace role"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return "", err
+	}
+	return role, nil
+}
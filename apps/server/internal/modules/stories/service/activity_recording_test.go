@@ -2,11 +2,15 @@ package stories
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
 	"testing"
 
+	customfields "github.com/complexus-tech/projects-api/internal/modules/customfields/service"
 	"github.com/complexus-tech/projects-api/internal/platform/auth"
+	"github.com/complexus-tech/projects-api/pkg/events"
 	"github.com/complexus-tech/projects-api/pkg/logger"
 	"github.com/google/uuid"
 )
@@ -17,6 +21,84 @@ type activityRecordingRepo struct {
 	activities              []CoreActivity
 	previousAssociationType string
 	removed                 CoreStoryAssociation
+	story                   CoreSingleStory
+	customFields            []customfields.CoreCustomField
+	customFieldValues       map[uuid.UUID]json.RawMessage
+	updates                 map[string]any
+	write                   CoreStoryWrite
+	blockingPath            bool
+	teamStatuses            []CoreTeamStatus
+	statusCategory          string
+	teamMoves               []CoreTeamMove
+	merges                  []CoreStoryMerge
+	slaPolicy               *CoreSLAPolicy
+	slaClock                *CoreStorySLAClock
+	workflowRules           *CoreWorkflowRules
+	workspaceRole           string
+	wipLimits               []CoreStatusWIP
+	lockedWIPLimits         []CoreStatusWIP
+	checklist               []CoreChecklistItem
+	checklistOrder          []uuid.UUID
+	checklistProgress       map[uuid.UUID]CoreChecklistProgress
+	associationTypes        []CoreAssociationType
+	scheduleNodes           []CoreDependencyNode
+	scheduleEdges           []CoreDependencyEdge
+	schedules               map[uuid.UUID]CoreTeamSchedule
+	rescheduled             []CoreScheduleChange
+	rescheduleEvents        []events.Event
+	storyRanks              map[uuid.UUID]*string
+	rankUpdates             map[uuid.UUID]string
+	openStoryIDs            []uuid.UUID
+	slaClocks               map[uuid.UUID]CoreStorySLAClock
+	epicTeams               map[uuid.UUID]uuid.UUID
+	created                 []CoreSingleStory
+	refStory                CoreSingleStory
+	droppedFieldValues      map[uuid.UUID]json.RawMessage
+}
+
+func (r *activityRecordingRepo) Get(ctx context.Context, id uuid.UUID, workspaceID uuid.UUID) (CoreSingleStory, error) {
+	return r.story, nil
+}
+
+func (r *activityRecordingRepo) QueryByRef(ctx context.Context, workspaceID uuid.UUID, teamCode string, sequenceID int) (CoreSingleStory, error) {
+	return r.refStory, nil
+}
+
+func (r *activityRecordingRepo) Create(ctx context.Context, story *CoreSingleStory, write CoreStoryWrite) (CoreSingleStory, error) {
+	if write.ChecklistItemID != nil {
+		if err := r.DeleteChecklistItem(ctx, *write.ChecklistItemID, story.Workspace); err != nil {
+			return CoreSingleStory{}, err
+		}
+	}
+	r.write = write
+	r.created = append(r.created, *story)
+	return *story, nil
+}
+
+func (r *activityRecordingRepo) GetTeamEstimateScheme(ctx context.Context, teamID, workspaceID uuid.UUID) (string, error) {
+	return "", nil
+}
+
+func (r *activityRecordingRepo) Update(ctx context.Context, id uuid.UUID, workspaceID uuid.UUID, updates map[string]any, write CoreStoryWrite) error {
+	if write.CheckWIP != nil {
+		limits := r.lockedWIPLimits
+		if limits == nil {
+			limits = r.wipLimits
+		}
+		if err := write.CheckWIP(limits); err != nil {
+			return err
+		}
+	}
+	r.updates = updates
+	r.write = write
+	if len(write.CustomFieldValues) > 0 {
+		r.customFieldValues = write.CustomFieldValues
+	}
+	return nil
+}
+
+func (r *activityRecordingRepo) GetCustomFields(ctx context.Context, workspaceID, teamID uuid.UUID) ([]customfields.CoreCustomField, error) {
+	return r.customFields, nil
 }
 
 func (r *activityRecordingRepo) UpdateLabels(ctx context.Context, id uuid.UUID, workspaceID uuid.UUID, labels []uuid.UUID) error {
@@ -64,6 +146,186 @@ func (r *activityRecordingRepo) RemoveAssociation(ctx context.Context, associati
 	}, nil
 }
 
+func (r *activityRecordingRepo) GetAssociationType(ctx context.Context, workspaceID uuid.UUID, key string) (CoreAssociationType, error) {
+	for _, t := range r.associationTypes {
+		if t.Key == key {
+			return t, nil
+		}
+	}
+	return CoreAssociationType{}, ErrAssociationTypeNotFound
+}
+
+func (r *activityRecordingRepo) CreateAssociationType(ctx context.Context, assocType CoreAssociationType) (CoreAssociationType, error) {
+	r.associationTypes = append(r.associationTypes, assocType)
+	return assocType, nil
+}
+
+func (r *activityRecordingRepo) BlockingPathExists(ctx context.Context, fromID, toID, workspaceID, ignoreAssociationID uuid.UUID) (bool, error) {
+	return r.blockingPath, nil
+}
+
+func (r *activityRecordingRepo) GetTeamStatuses(ctx context.Context, workspaceID, teamID uuid.UUID) ([]CoreTeamStatus, error) {
+	return r.teamStatuses, nil
+}
+
+func (r *activityRecordingRepo) GetStatusCategory(ctx context.Context, statusID string) (string, error) {
+	return r.statusCategory, nil
+}
+
+func (r *activityRecordingRepo) MoveStoriesToTeam(ctx context.Context, moves []CoreTeamMove, moveEvents func([]CoreTeamMove) []events.Event) ([]CoreTeamMove, error) {
+	moved := make([]CoreTeamMove, 0, len(moves))
+	for _, move := range moves {
+		move.FromTeamID = r.story.Team
+		move.OldTeamCode = r.story.TeamCode
+		move.OldSequenceID = r.story.SequenceID
+		move.OldStatusID = r.story.Status
+		move.OldSprintID = r.story.Sprint
+		move.OldEpicID = r.story.Epic
+		move.DroppedFieldValues = r.droppedFieldValues
+		move.NewTeamCode = "OPS"
+		move.NewSequenceID = len(r.teamMoves) + 1
+		r.teamMoves = append(r.teamMoves, move)
+		moved = append(moved, move)
+	}
+	r.write.Events = append(r.write.Events, moveEvents(moved)...)
+	return moved, nil
+}
+
+func (r *activityRecordingRepo) MergeStory(ctx context.Context, merge CoreStoryMerge, outboxEvents ...events.Event) (CoreStoryMerge, error) {
+	merge.DuplicateRef = "ENG-9"
+	merge.CanonicalRef = "ENG-5"
+	r.merges = append(r.merges, merge)
+	r.write.Events = append(r.write.Events, outboxEvents...)
+	return merge, nil
+}
+
+func (r *activityRecordingRepo) GetSLAPolicy(ctx context.Context, workspaceID, teamID uuid.UUID, priority string) (*CoreSLAPolicy, error) {
+	return r.slaPolicy, nil
+}
+
+func (r *activityRecordingRepo) GetStorySLAClock(ctx context.Context, storyID, workspaceID uuid.UUID) (*CoreStorySLAClock, error) {
+	return r.slaClock, nil
+}
+
+func (r *activityRecordingRepo) SaveStorySLAClock(ctx context.Context, clock CoreStorySLAClock) error {
+	r.slaClock = &clock
+	if r.slaClocks == nil {
+		r.slaClocks = map[uuid.UUID]CoreStorySLAClock{}
+	}
+	r.slaClocks[clock.StoryID] = clock
+	return nil
+}
+
+func (r *activityRecordingRepo) ListOpenTeamStoryIDs(ctx context.Context, workspaceID, teamID uuid.UUID) ([]uuid.UUID, error) {
+	return r.openStoryIDs, nil
+}
+
+func (r *activityRecordingRepo) DeleteStorySLAClock(ctx context.Context, storyID, workspaceID uuid.UUID) error {
+	r.slaClock = nil
+	return nil
+}
+
+func (r *activityRecordingRepo) GetWorkflowRules(ctx context.Context, workspaceID, teamID uuid.UUID) (*CoreWorkflowRules, error) {
+	if r.workflowRules == nil {
+		return &CoreWorkflowRules{}, nil
+	}
+	return r.workflowRules, nil
+}
+
+func (r *activityRecordingRepo) GetWorkspaceRole(ctx context.Context, workspaceID, userID uuid.UUID) (string, error) {
+	return r.workspaceRole, nil
+}
+
+func (r *activityRecordingRepo) GetWIPLimits(ctx context.Context, workspaceID uuid.UUID, statusIDs []uuid.UUID) ([]CoreStatusWIP, error) {
+	return r.wipLimits, nil
+}
+
+func (r *activityRecordingRepo) ListChecklistItems(ctx context.Context, storyID, workspaceID uuid.UUID) ([]CoreChecklistItem, error) {
+	return r.checklist, nil
+}
+
+func (r *activityRecordingRepo) GetChecklistItem(ctx context.Context, itemID, workspaceID uuid.UUID) (CoreChecklistItem, error) {
+	for _, item := range r.checklist {
+		if item.ID == itemID {
+			return item, nil
+		}
+	}
+	return CoreChecklistItem{}, ErrChecklistItemNotFound
+}
+
+func (r *activityRecordingRepo) CreateChecklistItem(ctx context.Context, item CoreChecklistItem) (CoreChecklistItem, error) {
+	item.ID = uuid.New()
+	item.OrderIndex = len(r.checklist)
+	r.checklist = append(r.checklist, item)
+	return item, nil
+}
+
+func (r *activityRecordingRepo) ToggleChecklistItem(ctx context.Context, itemID, workspaceID uuid.UUID) (CoreChecklistItem, error) {
+	for i := range r.checklist {
+		if r.checklist[i].ID == itemID {
+			r.checklist[i].Done = !r.checklist[i].Done
+			return r.checklist[i], nil
+		}
+	}
+	return CoreChecklistItem{}, ErrChecklistItemNotFound
+}
+
+func (r *activityRecordingRepo) ReorderChecklistItems(ctx context.Context, storyID, workspaceID uuid.UUID, itemIDs []uuid.UUID) error {
+	r.checklistOrder = itemIDs
+	return nil
+}
+
+func (r *activityRecordingRepo) DeleteChecklistItem(ctx context.Context, itemID, workspaceID uuid.UUID) error {
+	for i, item := range r.checklist {
+		if item.ID == itemID {
+			r.checklist = append(r.checklist[:i], r.checklist[i+1:]...)
+			return nil
+		}
+	}
+	return ErrChecklistItemNotFound
+}
+
+func (r *activityRecordingRepo) GetChecklistProgress(ctx context.Context, workspaceID uuid.UUID, storyIDs []uuid.UUID) (map[uuid.UUID]CoreChecklistProgress, error) {
+	return r.checklistProgress, nil
+}
+
+func (r *activityRecordingRepo) GetScheduleDependents(ctx context.Context, storyID, workspaceID uuid.UUID) ([]CoreDependencyNode, []CoreDependencyEdge, error) {
+	return r.scheduleNodes, r.scheduleEdges, nil
+}
+
+func (r *activityRecordingRepo) GetTeamSchedules(ctx context.Context, workspaceID uuid.UUID, teamIDs []uuid.UUID) (map[uuid.UUID]CoreTeamSchedule, error) {
+	return r.schedules, nil
+}
+
+func (r *activityRecordingRepo) RescheduleStories(ctx context.Context, workspaceID uuid.UUID, changes []CoreScheduleChange, outboxEvents ...events.Event) error {
+	r.rescheduled = append(r.rescheduled, changes...)
+	r.rescheduleEvents = append(r.rescheduleEvents, outboxEvents...)
+	return nil
+}
+
+func (r *activityRecordingRepo) GetStoryRanks(ctx context.Context, workspaceID uuid.UUID, storyIDs []uuid.UUID) (map[uuid.UUID]*string, error) {
+	ranks := map[uuid.UUID]*string{}
+	for _, id := range storyIDs {
+		if rank, ok := r.storyRanks[id]; ok {
+			ranks[id] = rank
+		}
+	}
+	return ranks, nil
+}
+
+func (r *activityRecordingRepo) UpdateStoryRank(ctx context.Context, storyID, workspaceID uuid.UUID, rank string) error {
+	if r.rankUpdates == nil {
+		r.rankUpdates = map[uuid.UUID]string{}
+	}
+	r.rankUpdates[storyID] = rank
+	return nil
+}
+
+func (r *activityRecordingRepo) EpicInTeam(ctx context.Context, epicID, workspaceID, teamID uuid.UUID) (bool, error) {
+	epicTeam, ok := r.epicTeams[epicID]
+	return ok && epicTeam == teamID, nil
+}
+
 func (r *activityRecordingRepo) RecordActivities(ctx context.Context, activities []CoreActivity) ([]CoreActivity, error) {
 	r.activities = append(r.activities, activities...)
 	return activities, nil
@@ -77,7 +339,7 @@ func previousAssociationTypePtr(value string) *string {
 }
 
 func newActivityRecordingService(repo *activityRecordingRepo) *Service {
-	return New(logger.NewWithText(io.Discard, slog.LevelError, "test"), repo, nil, nil, nil)
+	return New(logger.NewWithText(io.Discard, slog.LevelError, "test"), repo, nil, nil)
 }
 
 func TestUpdateLabelsRecordsActivity(t *testing.T) {
@@ -252,3 +514,599 @@ func TestRemoveAssociationRecordsActivityForBothStories(t *testing.T) {
 		t.Fatalf("expected association_removed reason, got %v", activity.Reason)
 	}
 }
+
+func TestUpdateWritesCustomFieldsWithStoryEvent(t *testing.T) {
+	customer := customfields.CoreCustomField{ID: uuid.New(), Name: "Customer", Type: customfields.TypeText}
+	storyID := uuid.New()
+	repo := &activityRecordingRepo{
+		customFields: []customfields.CoreCustomField{customer},
+		story:        CoreSingleStory{ID: storyID, Team: uuid.New()},
+	}
+	service := newActivityRecordingService(repo)
+	actorID := uuid.New()
+
+	ctx := auth.SetUserID(context.Background(), actorID)
+	_, err := service.Update(ctx, storyID, uuid.New(), map[string]any{
+		"custom_fields": map[uuid.UUID]json.RawMessage{customer.ID: json.RawMessage(`"Acme"`)},
+	})
+	if err != nil {
+		t.Fatalf("expected custom fields to update, got error: %v", err)
+	}
+
+	if repo.write.ActorID != actorID || string(repo.write.CustomFieldValues[customer.ID]) != `"Acme"` {
+		t.Fatalf("expected the custom field value in the story write, got %+v", repo.write)
+	}
+	if len(repo.write.Events) != 1 {
+		t.Fatalf("expected the story event in the same write, got %d events", len(repo.write.Events))
+	}
+	if repo.write.DescriptionRevision != nil {
+		t.Fatalf("expected no description revision without a description change, got %+v", repo.write.DescriptionRevision)
+	}
+	payload, ok := repo.write.Events[0].Payload.(events.StoryUpdatedPayload)
+	if !ok {
+		t.Fatalf("expected a story updated payload, got %T", repo.write.Events[0].Payload)
+	}
+	if _, ok := payload.Updates["custom_fields"]; !ok {
+		t.Fatalf("expected the event to announce the custom field change, got %v", payload.Updates)
+	}
+}
+
+func TestUpdateCustomFieldsRecordsActivityPerChangedField(t *testing.T) {
+	severity := customfields.CoreCustomField{
+		ID:      uuid.New(),
+		Name:    "Severity",
+		Type:    customfields.TypeSingleSelect,
+		Options: []customfields.CoreOption{{ID: "sev1", Label: "Critical"}, {ID: "sev2", Label: "Major"}},
+	}
+	customer := customfields.CoreCustomField{ID: uuid.New(), Name: "Customer", Type: customfields.TypeText}
+	storyID := uuid.New()
+	repo := &activityRecordingRepo{
+		customFields: []customfields.CoreCustomField{severity, customer},
+		story: CoreSingleStory{
+			ID:   storyID,
+			Team: uuid.New(),
+			CustomFields: map[uuid.UUID]json.RawMessage{
+				severity.ID: json.RawMessage(`"sev2"`),
+				customer.ID: json.RawMessage(`"Acme"`),
+			},
+		},
+	}
+	service := newActivityRecordingService(repo)
+	actorID := uuid.New()
+
+	ctx := auth.SetUserID(context.Background(), actorID)
+	err := service.UpdateExternal(ctx, actorID, storyID, uuid.New(), map[string]any{
+		"custom_fields": map[uuid.UUID]json.RawMessage{
+			severity.ID: json.RawMessage(`"sev1"`),
+			customer.ID: json.RawMessage(`"Acme"`),
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected custom fields to update, got error: %v", err)
+	}
+
+	if len(repo.customFieldValues) != 1 || string(repo.customFieldValues[severity.ID]) != `"sev1"` {
+		t.Fatalf("expected only the changed severity to be stored, got %v", repo.customFieldValues)
+	}
+	if _, ok := repo.updates["custom_fields"]; ok {
+		t.Fatal("expected custom fields to be kept out of the story column updates")
+	}
+	if len(repo.activities) != 1 {
+		t.Fatalf("expected 1 activity, got %d", len(repo.activities))
+	}
+	activity := repo.activities[0]
+	if activity.Field != "custom_field:"+severity.ID.String() {
+		t.Fatalf("expected custom field activity, got %q", activity.Field)
+	}
+	if activity.CurrentValue != "Critical" {
+		t.Fatalf("expected option label as current value, got %q", activity.CurrentValue)
+	}
+}
+
+func TestUpdateCustomFieldsRejectsFieldsOutsideTheTeam(t *testing.T) {
+	repo := &activityRecordingRepo{story: CoreSingleStory{ID: uuid.New(), Team: uuid.New()}}
+	service := newActivityRecordingService(repo)
+
+	err := service.UpdateExternal(context.Background(), uuid.New(), repo.story.ID, uuid.New(), map[string]any{
+		"custom_fields": map[uuid.UUID]json.RawMessage{uuid.New(): json.RawMessage(`"x"`)},
+	})
+	if !errors.Is(err, ErrUnknownCustomField) {
+		t.Fatalf("expected ErrUnknownCustomField, got %v", err)
+	}
+}
+
+func TestUpdateExternalSavesDescriptionRevision(t *testing.T) {
+	original := "<p>Spec</p>"
+	repo := &activityRecordingRepo{story: CoreSingleStory{ID: uuid.New(), DescriptionHTML: &original}}
+	service := newActivityRecordingService(repo)
+	actorID := uuid.New()
+
+	err := service.UpdateExternalWithReason(context.Background(), actorID, repo.story.ID, uuid.New(), map[string]any{
+		"description_html": "<p>Synced</p>",
+	}, "GitHub issue details changed.")
+	if err != nil {
+		t.Fatalf("expected description to update, got error: %v", err)
+	}
+
+	if repo.write.OriginalDescription == nil {
+		t.Fatal("expected the original description in the story write")
+	}
+	if got := repo.write.OriginalDescription.DescriptionHTML; got == nil || *got != original {
+		t.Fatalf("expected original description to be kept, got %v", got)
+	}
+	revision := repo.write.DescriptionRevision
+	if revision == nil {
+		t.Fatal("expected the new description in the story write")
+	}
+	if revision.DescriptionHTML == nil || *revision.DescriptionHTML != "<p>Synced</p>" {
+		t.Fatalf("expected synced description, got %v", revision.DescriptionHTML)
+	}
+	if revision.EditedBy == nil || *revision.EditedBy != actorID {
+		t.Fatalf("expected revision editor %s, got %v", actorID, revision.EditedBy)
+	}
+	if revision.Reason == nil || *revision.Reason != "GitHub issue details changed." {
+		t.Fatalf("expected the update reason on the revision, got %v", revision.Reason)
+	}
+}
+
+func TestAddAssociationRejectsBlockingCycle(t *testing.T) {
+	repo := &activityRecordingRepo{blockingPath: true}
+	service := newActivityRecordingService(repo)
+
+	_, err := service.AddAssociation(context.Background(), uuid.New(), uuid.New(), "blocking", uuid.New())
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("expected ErrDependencyCycle, got %v", err)
+	}
+	if len(repo.activities) != 0 {
+		t.Fatalf("expected no activities for a rejected link, got %d", len(repo.activities))
+	}
+
+	if _, err := service.AddAssociation(context.Background(), uuid.New(), uuid.New(), "related", uuid.New()); err != nil {
+		t.Fatalf("expected related links to skip the cycle check, got %v", err)
+	}
+}
+
+func TestMoveToTeamMapsStatusByCategory(t *testing.T) {
+	oldStatus, backlog, started := uuid.New(), uuid.New(), uuid.New()
+	sprintID := uuid.New()
+	repo := &activityRecordingRepo{
+		story: CoreSingleStory{
+			ID:         uuid.New(),
+			Team:       uuid.New(),
+			TeamCode:   "ENG",
+			SequenceID: 4,
+			Status:     &oldStatus,
+			Sprint:     &sprintID,
+		},
+		statusCategory: "started",
+		teamStatuses: []CoreTeamStatus{
+			{ID: backlog, Category: "backlog", IsDefault: true},
+			{ID: started, Category: "started"},
+		},
+	}
+	service := newActivityRecordingService(repo)
+	targetTeam := uuid.New()
+
+	moves, err := service.MoveToTeam(context.Background(), uuid.New(), uuid.New(), []uuid.UUID{repo.story.ID}, targetTeam, nil)
+	if err != nil {
+		t.Fatalf("expected story to move, got error: %v", err)
+	}
+	if len(moves) != 1 {
+		t.Fatalf("expected 1 move, got %d", len(moves))
+	}
+	if moves[0].NewStatusID == nil || *moves[0].NewStatusID != started {
+		t.Fatalf("expected status %s in the same category, got %v", started, moves[0].NewStatusID)
+	}
+	if moves[0].OldRef() != "ENG-4" || moves[0].NewRef() != "OPS-1" {
+		t.Fatalf("expected ENG-4 to become OPS-1, got %s and %s", moves[0].OldRef(), moves[0].NewRef())
+	}
+
+	fields := map[string]bool{}
+	for _, activity := range repo.activities {
+		fields[activity.Field] = true
+		if activity.Reason == nil || *activity.Reason != "Moved from ENG-4 to OPS-1." {
+			t.Fatalf("expected move reason, got %v", activity.Reason)
+		}
+	}
+	if !fields["team_id"] || !fields["status_id"] {
+		t.Fatalf("expected team and status activities, got %v", fields)
+	}
+
+	if len(repo.write.Events) != 1 || repo.write.Events[0].Type != events.StoryUpdated {
+		t.Fatalf("expected one story.updated event, got %+v", repo.write.Events)
+	}
+	payload := repo.write.Events[0].Payload.(events.StoryUpdatedPayload)
+	if payload.StoryID != repo.story.ID || payload.Updates["team_id"] != targetTeam || payload.Updates["status_id"] != moves[0].NewStatusID {
+		t.Fatalf("expected the team and status change in the event, got %+v", payload)
+	}
+}
+
+func TestMoveToTeamClearsEpic(t *testing.T) {
+	epicID := uuid.New()
+	repo := &activityRecordingRepo{
+		story:        CoreSingleStory{ID: uuid.New(), Team: uuid.New(), Epic: &epicID},
+		teamStatuses: []CoreTeamStatus{{ID: uuid.New(), Category: "backlog", IsDefault: true}},
+	}
+	service := newActivityRecordingService(repo)
+
+	if _, err := service.MoveToTeam(context.Background(), uuid.New(), uuid.New(), []uuid.UUID{repo.story.ID}, uuid.New(), nil); err != nil {
+		t.Fatalf("expected story to move, got error: %v", err)
+	}
+
+	payload := repo.write.Events[0].Payload.(events.StoryUpdatedPayload)
+	if value, ok := payload.Updates["epic_id"]; !ok || value != nil {
+		t.Fatalf("expected the event to clear the epic, got %v", payload.Updates)
+	}
+	var cleared bool
+	for _, activity := range repo.activities {
+		if old, ok := activity.OldValue.(*uuid.UUID); activity.Field == "epic_id" && ok && *old == epicID {
+			cleared = activity.NewValue == nil
+		}
+	}
+	if !cleared {
+		t.Fatalf("expected an epic activity for %s, got %+v", epicID, repo.activities)
+	}
+}
+
+func TestMoveToTeamDropsForeignCustomFieldValues(t *testing.T) {
+	fieldID := uuid.New()
+	repo := &activityRecordingRepo{
+		story:              CoreSingleStory{ID: uuid.New(), Team: uuid.New()},
+		teamStatuses:       []CoreTeamStatus{{ID: uuid.New(), Category: "backlog", IsDefault: true}},
+		droppedFieldValues: map[uuid.UUID]json.RawMessage{fieldID: json.RawMessage(`"high"`)},
+	}
+	service := newActivityRecordingService(repo)
+
+	if _, err := service.MoveToTeam(context.Background(), uuid.New(), uuid.New(), []uuid.UUID{repo.story.ID}, uuid.New(), nil); err != nil {
+		t.Fatalf("expected story to move, got error: %v", err)
+	}
+
+	payload := repo.write.Events[0].Payload.(events.StoryUpdatedPayload)
+	cleared, ok := payload.Updates[customFieldsUpdateKey].(map[uuid.UUID]json.RawMessage)
+	if !ok || len(cleared) != 1 || cleared[fieldID] != nil {
+		t.Fatalf("expected the event to clear field %s, got %v", fieldID, payload.Updates)
+	}
+	var activity *CoreActivity
+	for i := range repo.activities {
+		if repo.activities[i].Field == customFieldActivityPrefix+fieldID.String() {
+			activity = &repo.activities[i]
+		}
+	}
+	if activity == nil || activity.NewValue != nil || string(activity.OldValue.(json.RawMessage)) != `"high"` {
+		t.Fatalf("expected an activity clearing %s, got %+v", fieldID, repo.activities)
+	}
+}
+
+func TestUpdateTeamValidatesBeforeMoving(t *testing.T) {
+	oldTeam, epicID := uuid.New(), uuid.New()
+	repo := &activityRecordingRepo{
+		story:        CoreSingleStory{ID: uuid.New(), Team: oldTeam},
+		teamStatuses: []CoreTeamStatus{{ID: uuid.New(), Category: "backlog", IsDefault: true}},
+		epicTeams:    map[uuid.UUID]uuid.UUID{epicID: oldTeam},
+	}
+	service := newActivityRecordingService(repo)
+
+	err := service.UpdateExternal(context.Background(), uuid.New(), repo.story.ID, uuid.New(), map[string]any{
+		"team_id": uuid.New(),
+		"epic_id": epicID,
+	})
+	if !errors.Is(err, ErrInvalidEpic) {
+		t.Fatalf("expected ErrInvalidEpic for an epic of the old team, got %v", err)
+	}
+	if len(repo.teamMoves) != 0 {
+		t.Fatalf("expected the story to stay in its team, got %d moves", len(repo.teamMoves))
+	}
+}
+
+func TestMoveToTeamRejectsForeignStatusMapping(t *testing.T) {
+	repo := &activityRecordingRepo{
+		teamStatuses: []CoreTeamStatus{{ID: uuid.New(), Category: "backlog", IsDefault: true}},
+	}
+	service := newActivityRecordingService(repo)
+
+	mapping := map[uuid.UUID]uuid.UUID{uuid.New(): uuid.New()}
+	_, err := service.MoveToTeam(context.Background(), uuid.New(), uuid.New(), []uuid.UUID{uuid.New()}, uuid.New(), mapping)
+	if !errors.Is(err, ErrInvalidStatusMapping) {
+		t.Fatalf("expected ErrInvalidStatusMapping, got %v", err)
+	}
+	if len(repo.teamMoves) != 0 {
+		t.Fatalf("expected no moves, got %d", len(repo.teamMoves))
+	}
+}
+
+func TestMergeStoriesLeavesTombstones(t *testing.T) {
+	cancelledID := uuid.New()
+	repo := &activityRecordingRepo{
+		teamStatuses: []CoreTeamStatus{
+			{ID: uuid.New(), Category: "unstarted", IsDefault: true},
+			{ID: cancelledID, Category: "cancelled"},
+		},
+	}
+	service := newActivityRecordingService(repo)
+	duplicateID, canonicalID := uuid.New(), uuid.New()
+
+	merge, err := service.MergeStories(context.Background(), uuid.New(), uuid.New(), duplicateID, canonicalID)
+	if err != nil {
+		t.Fatalf("expected stories to merge, got error: %v", err)
+	}
+	if merge.DuplicateID != duplicateID || merge.CanonicalID != canonicalID {
+		t.Fatalf("expected %s merged into %s, got %+v", duplicateID, canonicalID, merge)
+	}
+	if merge.CancelledStatusID != cancelledID {
+		t.Fatalf("expected duplicate to move to cancelled status %s, got %s", cancelledID, merge.CancelledStatusID)
+	}
+	if len(repo.activities) != 3 {
+		t.Fatalf("expected 2 tombstones and a status change, got %d activities", len(repo.activities))
+	}
+
+	reasons := map[string]string{}
+	for _, activity := range repo.activities {
+		if activity.Reason == nil {
+			t.Fatalf("expected a reason on %s", activity.Field)
+		}
+		reasons[activity.Field] = *activity.Reason
+	}
+	if reasons["merged_into"] != "Merged into ENG-5." {
+		t.Fatalf("unexpected duplicate tombstone %q", reasons["merged_into"])
+	}
+	if reasons["merged_from"] != "Merged ENG-9 into this story." {
+		t.Fatalf("unexpected canonical tombstone %q", reasons["merged_from"])
+	}
+	if reasons["status_id"] != "Merged into ENG-5." {
+		t.Fatalf("unexpected status change reason %q", reasons["status_id"])
+	}
+}
+
+func TestMergeStoriesPublishesUpdates(t *testing.T) {
+	cancelledID := uuid.New()
+	repo := &activityRecordingRepo{
+		teamStatuses: []CoreTeamStatus{{ID: cancelledID, Category: "cancelled"}},
+	}
+	service := newActivityRecordingService(repo)
+	duplicateID, canonicalID := uuid.New(), uuid.New()
+
+	if _, err := service.MergeStories(context.Background(), uuid.New(), uuid.New(), duplicateID, canonicalID); err != nil {
+		t.Fatalf("expected stories to merge, got error: %v", err)
+	}
+	if len(repo.write.Events) != 2 {
+		t.Fatalf("expected 2 outbox events, got %d", len(repo.write.Events))
+	}
+
+	updates := map[uuid.UUID]map[string]any{}
+	for _, event := range repo.write.Events {
+		if event.Type != events.StoryUpdated {
+			t.Fatalf("expected story.updated, got %s", event.Type)
+		}
+		payload := event.Payload.(events.StoryUpdatedPayload)
+		updates[payload.StoryID] = payload.Updates
+	}
+	if updates[duplicateID]["status_id"] != cancelledID {
+		t.Fatalf("expected duplicate event to carry the cancelled status, got %v", updates[duplicateID])
+	}
+	if updates[canonicalID]["merged_from"] != duplicateID {
+		t.Fatalf("expected canonical event to name the duplicate, got %v", updates[canonicalID])
+	}
+}
+
+func TestQueryByRefFollowsMergedDuplicate(t *testing.T) {
+	canonicalID := uuid.New()
+	repo := &activityRecordingRepo{
+		story:    CoreSingleStory{ID: canonicalID, Title: "Canonical"},
+		refStory: CoreSingleStory{ID: uuid.New(), Title: "Duplicate", MergedInto: &canonicalID},
+	}
+	service := newActivityRecordingService(repo)
+
+	story, err := service.QueryByRef(context.Background(), uuid.New(), "ENG-9")
+	if err != nil {
+		t.Fatalf("expected the reference to resolve, got error: %v", err)
+	}
+	if story.ID != canonicalID {
+		t.Fatalf("expected the duplicate's ref to resolve to %s, got %s", canonicalID, story.ID)
+	}
+}
+
+func TestMergeStoriesRequiresCancelledStatus(t *testing.T) {
+	repo := &activityRecordingRepo{
+		teamStatuses: []CoreTeamStatus{{ID: uuid.New(), Category: "completed"}},
+	}
+	service := newActivityRecordingService(repo)
+
+	_, err := service.MergeStories(context.Background(), uuid.New(), uuid.New(), uuid.New(), uuid.New())
+	if !errors.Is(err, ErrNoCancelledStatus) {
+		t.Fatalf("expected ErrNoCancelledStatus, got %v", err)
+	}
+	if len(repo.merges) != 0 {
+		t.Fatalf("expected nothing merged, got %d merges", len(repo.merges))
+	}
+}
+
+func TestMergeStoriesRejectsSelfMerge(t *testing.T) {
+	repo := &activityRecordingRepo{}
+	service := newActivityRecordingService(repo)
+	storyID := uuid.New()
+
+	_, err := service.MergeStories(context.Background(), uuid.New(), uuid.New(), storyID, storyID)
+	if !errors.Is(err, ErrMergeIntoSelf) {
+		t.Fatalf("expected ErrMergeIntoSelf, got %v", err)
+	}
+	if len(repo.merges) != 0 || len(repo.activities) != 0 {
+		t.Fatalf("expected nothing merged, got %d merges and %d activities", len(repo.merges), len(repo.activities))
+	}
+}
+
+func TestUpdateCascadesEndDateFromMaya(t *testing.T) {
+	teamID := uuid.New()
+	rootID, dependentID := uuid.New(), uuid.New()
+	repo := &activityRecordingRepo{
+		story: CoreSingleStory{ID: rootID, Team: teamID, EndDate: scheduleDay("2026-03-06")},
+		scheduleNodes: []CoreDependencyNode{
+			{ID: rootID, TeamID: teamID, TeamCode: "ENG", SequenceID: 1, StartDate: scheduleDay("2026-03-02"), EndDate: scheduleDay("2026-03-06")},
+			{ID: dependentID, TeamID: teamID, TeamCode: "ENG", SequenceID: 2, StartDate: scheduleDay("2026-03-09"), EndDate: scheduleDay("2026-03-10")},
+		},
+		scheduleEdges: []CoreDependencyEdge{{BlockerID: rootID, BlockedID: dependentID}},
+		schedules: map[uuid.UUID]CoreTeamSchedule{
+			teamID: {CascadeDependencies: true, Holidays: map[string]bool{}},
+		},
+	}
+	service := newActivityRecordingService(repo)
+
+	// Maya's schedule sync sends plain time.Time values rather than pointers.
+	updates := map[string]any{"end_date": *scheduleDay("2026-03-10")}
+	if err := service.UpdateExternal(context.Background(), uuid.New(), rootID, uuid.New(), updates); err != nil {
+		t.Fatalf("expected story to update, got error: %v", err)
+	}
+
+	if len(repo.rescheduled) != 1 || repo.rescheduled[0].StoryID != dependentID {
+		t.Fatalf("expected the dependent to be rescheduled, got %+v", repo.rescheduled)
+	}
+	if got := repo.rescheduled[0].NewStartDate; !got.Equal(*scheduleDay("2026-03-11")) {
+		t.Fatalf("expected dependent to start on 2026-03-11, got %s", got.Format("2006-01-02"))
+	}
+	if len(repo.rescheduleEvents) != 1 || repo.rescheduleEvents[0].Type != events.StoryUpdated {
+		t.Fatalf("expected one story.updated event with the reschedule, got %+v", repo.rescheduleEvents)
+	}
+
+	fields := map[string]bool{}
+	for _, activity := range repo.activities {
+		if activity.StoryID == dependentID {
+			fields[activity.Field] = true
+		}
+	}
+	if !fields["start_date"] || !fields["end_date"] {
+		t.Fatalf("expected start and end date activities on the dependent, got %v", fields)
+	}
+}
+
+func TestMoveStoryWaitsForRebalance(t *testing.T) {
+	storyID, beforeID, afterID := uuid.New(), uuid.New(), uuid.New()
+	shared := "i"
+	repo := &activityRecordingRepo{
+		storyRanks: map[uuid.UUID]*string{beforeID: &shared, afterID: &shared},
+	}
+	service := newActivityRecordingService(repo)
+
+	_, err := service.MoveStory(context.Background(), storyID, uuid.New(), &beforeID, &afterID)
+	if !errors.Is(err, ErrRanksRebalancing) {
+		t.Fatalf("expected ErrRanksRebalancing, got %v", err)
+	}
+	if len(repo.rankUpdates) != 0 {
+		t.Fatalf("expected no rank change, got %v", repo.rankUpdates)
+	}
+}
+
+func TestMoveStoryRanksBetweenNeighbours(t *testing.T) {
+	storyID, beforeID, afterID := uuid.New(), uuid.New(), uuid.New()
+	before, after := "a", "c"
+	repo := &activityRecordingRepo{
+		storyRanks: map[uuid.UUID]*string{beforeID: &before, afterID: &after},
+	}
+	service := newActivityRecordingService(repo)
+
+	rank, err := service.MoveStory(context.Background(), storyID, uuid.New(), &beforeID, &afterID)
+	if err != nil {
+		t.Fatalf("expected story to move, got error: %v", err)
+	}
+	if rank <= before || rank >= after || repo.rankUpdates[storyID] != rank {
+		t.Fatalf("expected a saved rank between %q and %q, got %q", before, after, rank)
+	}
+}
+
+func TestResyncTeamSLAAppliesNewTargets(t *testing.T) {
+	oldTarget, newTarget := 4*60, "1h"
+	first, second := uuid.New(), uuid.New()
+	repo := &activityRecordingRepo{
+		story:          CoreSingleStory{Priority: "Urgent"},
+		statusCategory: "unstarted",
+		openStoryIDs:   []uuid.UUID{first, second},
+		slaPolicy: &CoreSLAPolicy{
+			Priority:            "Urgent",
+			FirstResponseTarget: &newTarget,
+			Calendar:            CoreSLACalendar{WorkdayStart: 0, WorkdayEnd: 24 * 60, AtRiskPercent: 80},
+		},
+		slaClock: &CoreStorySLAClock{Priority: "Urgent", FirstResponseMinutes: &oldTarget},
+	}
+	service := newActivityRecordingService(repo)
+
+	synced, err := service.ResyncTeamSLA(context.Background(), uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("expected SLAs to resync, got error: %v", err)
+	}
+	if synced != 2 || len(repo.slaClocks) != 2 {
+		t.Fatalf("expected 2 clocks synced, got %d and %d saved", synced, len(repo.slaClocks))
+	}
+	for _, storyID := range []uuid.UUID{first, second} {
+		clock := repo.slaClocks[storyID]
+		if clock.FirstResponseMinutes == nil || *clock.FirstResponseMinutes != 60 {
+			t.Fatalf("expected story %s to get the 60 minute target, got %v", storyID, clock.FirstResponseMinutes)
+		}
+	}
+}
+
+func TestResyncTeamSLADropsClocksWithoutPolicy(t *testing.T) {
+	target := 60
+	repo := &activityRecordingRepo{
+		openStoryIDs: []uuid.UUID{uuid.New()},
+		slaClock:     &CoreStorySLAClock{Priority: "Urgent", FirstResponseMinutes: &target},
+	}
+	service := newActivityRecordingService(repo)
+
+	if _, err := service.ResyncTeamSLA(context.Background(), uuid.New(), uuid.New()); err != nil {
+		t.Fatalf("expected SLAs to resync, got error: %v", err)
+	}
+	if repo.slaClock != nil {
+		t.Fatalf("expected the clock to be dropped once no policy applies, got %+v", repo.slaClock)
+	}
+}
+
+func TestCreateRejectsEpicOfAnotherTeam(t *testing.T) {
+	epicID := uuid.New()
+	repo := &activityRecordingRepo{epicTeams: map[uuid.UUID]uuid.UUID{epicID: uuid.New()}}
+	service := newActivityRecordingService(repo)
+	reporterID := uuid.New()
+
+	_, err := service.Create(context.Background(), CoreNewStory{
+		Title:    "Story",
+		Team:     uuid.New(),
+		Epic:     &epicID,
+		Reporter: &reporterID,
+	}, uuid.New())
+	if !errors.Is(err, ErrInvalidEpic) {
+		t.Fatalf("expected %v, got %v", ErrInvalidEpic, err)
+	}
+}
+
+func TestUpdateChecksEpicTeam(t *testing.T) {
+	teamID := uuid.New()
+	teamEpic, otherEpic := uuid.New(), uuid.New()
+	storyID := uuid.New()
+	repo := &activityRecordingRepo{
+		story:     CoreSingleStory{ID: storyID, Team: teamID},
+		epicTeams: map[uuid.UUID]uuid.UUID{teamEpic: teamID, otherEpic: uuid.New()},
+	}
+	service := newActivityRecordingService(repo)
+	ctx := auth.SetUserID(context.Background(), uuid.New())
+
+	if _, err := service.Update(ctx, storyID, uuid.New(), map[string]any{"epic_id": &otherEpic}); !errors.Is(err, ErrInvalidEpic) {
+		t.Fatalf("expected %v for an epic of another team, got %v", ErrInvalidEpic, err)
+	}
+	if repo.updates != nil {
+		t.Fatalf("expected no write for an epic of another team, got %v", repo.updates)
+	}
+
+	missing := uuid.New()
+	if _, err := service.Update(ctx, storyID, uuid.New(), map[string]any{"epic_id": &missing}); !errors.Is(err, ErrInvalidEpic) {
+		t.Fatalf("expected %v for a missing epic, got %v", ErrInvalidEpic, err)
+	}
+
+	if _, err := service.Update(ctx, storyID, uuid.New(), map[string]any{"epic_id": &teamEpic}); err != nil {
+		t.Fatalf("expected the team's epic to be accepted, got %v", err)
+	}
+	if got, _ := repo.updates["epic_id"].(*uuid.UUID); got == nil || *got != teamEpic {
+		t.Fatalf("expected epic %s to be written, got %v", teamEpic, repo.updates["epic_id"])
+	}
+
+	var noEpic *uuid.UUID
+	if _, err := service.Update(ctx, storyID, uuid.New(), map[string]any{"epic_id": noEpic}); err != nil {
+		t.Fatalf("expected clearing the epic to be accepted, got %v", err)
+	}
+}
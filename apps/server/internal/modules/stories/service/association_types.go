@@ -0,0 +1,202 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAssociationTypeNotFound     = errors.New("association type not found")
+	ErrInvalidAssociationType      = errors.New("association type is not defined in the workspace")
+	ErrInvalidAssociationTypeKey   = errors.New("association type key must be lower-case letters, digits and underscores, starting with a letter, and at most 50 characters")
+	ErrInvalidAssociationTypeLabel = errors.New("association type labels are required and must be at most 100 characters")
+	ErrAssociationTypeExists       = errors.New("association type key is already in use")
+	ErrAssociationTypeInUse        = errors.New("association type is still used by story associations")
+	ErrSystemAssociationType       = errors.New("built-in association types cannot be changed")
+)
+
+// maxAssociationTypeLabelLength is the longest association type label in
+// characters.
+const maxAssociationTypeLabelLength = 100
+
+var associationTypeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// customAssociationActivityField is the activity field of associations of
+// workspace-defined types. Their activity values carry the type's label.
+const customAssociationActivityField = "association_id"
+
+// CoreAssociationType is a kind of link between two stories. ForwardLabel
+// reads from the source story ("causes") and BackwardLabel from the target
+// story ("caused by"). System types are built in and shared by every
+// workspace.
+type CoreAssociationType struct {
+	ID            uuid.UUID
+	WorkspaceID   uuid.UUID
+	Key           string
+	ForwardLabel  string
+	BackwardLabel string
+	System        bool
+	CreatedBy     *uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// CoreNewAssociationType is an association type to define in a workspace.
+type CoreNewAssociationType struct {
+	Key           string
+	ForwardLabel  string
+	BackwardLabel string
+}
+
+// CoreUpdateAssociationType holds the new labels of an association type.
+type CoreUpdateAssociationType struct {
+	ForwardLabel  string
+	BackwardLabel string
+}
+
+// builtinAssociationTypes are available in every workspace. Blocking links
+// also drive dependency cycles and schedules.
+var builtinAssociationTypes = []CoreAssociationType{
+	{Key: "blocking", ForwardLabel: "Blocks", BackwardLabel: "Blocked by", System: true},
+	{Key: "related", ForwardLabel: "Related to", BackwardLabel: "Related to", System: true},
+	{Key: "duplicate", ForwardLabel: "Duplicate of", BackwardLabel: "Duplicated by", System: true},
+}
+
+func builtinAssociationType(key string) (CoreAssociationType, bool) {
+	for _, t := range builtinAssociationTypes {
+		if t.Key == key {
+			return t, true
+		}
+	}
+	return CoreAssociationType{}, false
+}
+
+// ListAssociationTypes returns the built-in association types followed by
+// the workspace's own.
+func (s *Service) ListAssociationTypes(ctx context.Context, workspaceID uuid.UUID) ([]CoreAssociationType, error) {
+	s.log.Info(ctx, "business.core.stories.ListAssociationTypes")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.ListAssociationTypes")
+	defer span.End()
+
+	custom, err := s.repo.ListAssociationTypes(ctx, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	types := make([]CoreAssociationType, 0, len(builtinAssociationTypes)+len(custom))
+	types = append(types, builtinAssociationTypes...)
+	return append(types, custom...), nil
+}
+
+// CreateAssociationType defines a new association type in the workspace.
+func (s *Service) CreateAssociationType(ctx context.Context, workspaceID, actorID uuid.UUID, nt CoreNewAssociationType) (CoreAssociationType, error) {
+	s.log.Info(ctx, "business.core.stories.CreateAssociationType")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.CreateAssociationType")
+	defer span.End()
+
+	key := strings.TrimSpace(nt.Key)
+	if !associationTypeKeyPattern.MatchString(key) {
+		return CoreAssociationType{}, ErrInvalidAssociationTypeKey
+	}
+	if _, ok := builtinAssociationType(key); ok {
+		return CoreAssociationType{}, ErrAssociationTypeExists
+	}
+	forward, backward, err := normalizeAssociationTypeLabels(nt.ForwardLabel, nt.BackwardLabel)
+	if err != nil {
+		return CoreAssociationType{}, err
+	}
+
+	created, err := s.repo.CreateAssociationType(ctx, CoreAssociationType{
+		WorkspaceID:   workspaceID,
+		Key:           key,
+		ForwardLabel:  forward,
+		BackwardLabel: backward,
+		CreatedBy:     &actorID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return CoreAssociationType{}, err
+	}
+	return created, nil
+}
+
+// UpdateAssociationType relabels one of the workspace's association types.
+// The key stays the same so existing associations keep their type.
+func (s *Service) UpdateAssociationType(ctx context.Context, workspaceID uuid.UUID, key string, ut CoreUpdateAssociationType) (CoreAssociationType, error) {
+	s.log.Info(ctx, "business.core.stories.UpdateAssociationType")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.UpdateAssociationType")
+	defer span.End()
+
+	if _, ok := builtinAssociationType(key); ok {
+		return CoreAssociationType{}, ErrSystemAssociationType
+	}
+	forward, backward, err := normalizeAssociationTypeLabels(ut.ForwardLabel, ut.BackwardLabel)
+	if err != nil {
+		return CoreAssociationType{}, err
+	}
+
+	updated, err := s.repo.UpdateAssociationType(ctx, workspaceID, key, forward, backward)
+	if err != nil {
+		span.RecordError(err)
+		return CoreAssociationType{}, err
+	}
+	return updated, nil
+}
+
+// DeleteAssociationType removes one of the workspace's association types.
+// Types still used by associations are kept.
+func (s *Service) DeleteAssociationType(ctx context.Context, workspaceID uuid.UUID, key string) error {
+	s.log.Info(ctx, "business.core.stories.DeleteAssociationType")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.DeleteAssociationType")
+	defer span.End()
+
+	if _, ok := builtinAssociationType(key); ok {
+		return ErrSystemAssociationType
+	}
+	if err := s.repo.DeleteAssociationType(ctx, workspaceID, key); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// associationType returns the built-in or workspace type with key, failing
+// with ErrInvalidAssociationType when there is none.
+func (s *Service) associationType(ctx context.Context, workspaceID uuid.UUID, key string) (CoreAssociationType, error) {
+	if t, ok := builtinAssociationType(key); ok {
+		return t, nil
+	}
+	t, err := s.repo.GetAssociationType(ctx, workspaceID, key)
+	if errors.Is(err, ErrAssociationTypeNotFound) {
+		return CoreAssociationType{}, ErrInvalidAssociationType
+	}
+	return t, err
+}
+
+// associationTypeLabels returns the type with key for activities, falling
+// back to the key itself as both labels when the type cannot be loaded.
+func (s *Service) associationTypeLabels(ctx context.Context, workspaceID uuid.UUID, key string) CoreAssociationType {
+	t, err := s.associationType(ctx, workspaceID, key)
+	if err != nil {
+		return CoreAssociationType{Key: key, ForwardLabel: key, BackwardLabel: key}
+	}
+	return t
+}
+
+func normalizeAssociationTypeLabels(forward, backward string) (string, string, error) {
+	forward, backward = strings.TrimSpace(forward), strings.TrimSpace(backward)
+	for _, label := range []string{forward, backward} {
+		if label == "" || utf8.RuneCountInString(label) > maxAssociationTypeLabelLength {
+			return "", "", ErrInvalidAssociationTypeLabel
+		}
+	}
+	return forward, backward, nil
+}
@@ -0,0 +1,156 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/complexus-tech/projects-api/internal/platform/auth"
+	"github.com/google/uuid"
+)
+
+func TestCreateAssociationTypeValidates(t *testing.T) {
+	tests := []struct {
+		name string
+		nt   CoreNewAssociationType
+		want error
+	}{
+		{
+			name: "upper-case key",
+			nt:   CoreNewAssociationType{Key: "Causes", ForwardLabel: "Causes", BackwardLabel: "Caused by"},
+			want: ErrInvalidAssociationTypeKey,
+		},
+		{
+			name: "built-in key",
+			nt:   CoreNewAssociationType{Key: "blocking", ForwardLabel: "Stops", BackwardLabel: "Stopped by"},
+			want: ErrAssociationTypeExists,
+		},
+		{
+			name: "blank label",
+			nt:   CoreNewAssociationType{Key: "causes", ForwardLabel: "Causes", BackwardLabel: "  "},
+			want: ErrInvalidAssociationTypeLabel,
+		},
+		{
+			name: "long label",
+			nt:   CoreNewAssociationType{Key: "causes", ForwardLabel: strings.Repeat("a", maxAssociationTypeLabelLength+1), BackwardLabel: "Caused by"},
+			want: ErrInvalidAssociationTypeLabel,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &activityRecordingRepo{}
+			service := newActivityRecordingService(repo)
+
+			_, err := service.CreateAssociationType(context.Background(), uuid.New(), uuid.New(), tt.nt)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+			if len(repo.associationTypes) != 0 {
+				t.Errorf("expected no type to be stored, got %+v", repo.associationTypes)
+			}
+		})
+	}
+}
+
+func TestCreateAssociationTypeTrimsLabels(t *testing.T) {
+	repo := &activityRecordingRepo{}
+	service := newActivityRecordingService(repo)
+	actorID := uuid.New()
+
+	created, err := service.CreateAssociationType(context.Background(), uuid.New(), actorID, CoreNewAssociationType{
+		Key:           "tests",
+		ForwardLabel:  " Tests ",
+		BackwardLabel: "Tested by ",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ForwardLabel != "Tests" || created.BackwardLabel != "Tested by" {
+		t.Errorf("got labels %q and %q, want them trimmed", created.ForwardLabel, created.BackwardLabel)
+	}
+	if created.CreatedBy == nil || *created.CreatedBy != actorID {
+		t.Errorf("got created by %v, want %s", created.CreatedBy, actorID)
+	}
+}
+
+func TestBuiltinAssociationTypesCannotChange(t *testing.T) {
+	service := newActivityRecordingService(&activityRecordingRepo{})
+
+	_, err := service.UpdateAssociationType(context.Background(), uuid.New(), "related", CoreUpdateAssociationType{ForwardLabel: "Linked", BackwardLabel: "Linked"})
+	if !errors.Is(err, ErrSystemAssociationType) {
+		t.Errorf("update: got error %v, want ErrSystemAssociationType", err)
+	}
+	if err := service.DeleteAssociationType(context.Background(), uuid.New(), "duplicate"); !errors.Is(err, ErrSystemAssociationType) {
+		t.Errorf("delete: got error %v, want ErrSystemAssociationType", err)
+	}
+}
+
+func TestAddAssociationRejectsUnknownType(t *testing.T) {
+	repo := &activityRecordingRepo{}
+	service := newActivityRecordingService(repo)
+
+	_, err := service.AddAssociation(context.Background(), uuid.New(), uuid.New(), "causes", uuid.New())
+	if !errors.Is(err, ErrInvalidAssociationType) {
+		t.Fatalf("got error %v, want ErrInvalidAssociationType", err)
+	}
+	if len(repo.activities) != 0 {
+		t.Errorf("expected no activities, got %d", len(repo.activities))
+	}
+}
+
+func TestAddAssociationOfCustomTypeRecordsLabels(t *testing.T) {
+	repo := &activityRecordingRepo{associationTypes: []CoreAssociationType{
+		{Key: "causes", ForwardLabel: "Causes", BackwardLabel: "Caused by"},
+	}}
+	service := newActivityRecordingService(repo)
+	fromStoryID, toStoryID := uuid.New(), uuid.New()
+
+	ctx := auth.SetUserID(context.Background(), uuid.New())
+	if _, err := service.AddAssociation(ctx, fromStoryID, toStoryID, "causes", uuid.New()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.activities) != 2 {
+		t.Fatalf("got %d activities, want 2", len(repo.activities))
+	}
+	activityByStoryID := map[uuid.UUID]CoreActivity{}
+	for _, activity := range repo.activities {
+		activityByStoryID[activity.StoryID] = activity
+	}
+
+	from := activityByStoryID[fromStoryID]
+	if from.Field != customAssociationActivityField || from.CurrentValue != "Causes Related story" {
+		t.Errorf("got source activity %s = %q", from.Field, from.CurrentValue)
+	}
+	to := activityByStoryID[toStoryID]
+	if to.Field != customAssociationActivityField || to.CurrentValue != "Caused by "+fromStoryID.String() {
+		t.Errorf("got target activity %s = %q", to.Field, to.CurrentValue)
+	}
+}
+
+func TestUpdateAssociationFromCustomTypeRecordsOldLabels(t *testing.T) {
+	repo := &activityRecordingRepo{
+		associationTypes:        []CoreAssociationType{{Key: "implements", ForwardLabel: "Implements", BackwardLabel: "Implemented by"}},
+		previousAssociationType: "implements",
+	}
+	service := newActivityRecordingService(repo)
+	fromStoryID, toStoryID := uuid.New(), uuid.New()
+
+	ctx := auth.SetUserID(context.Background(), uuid.New())
+	if _, err := service.UpdateAssociation(ctx, uuid.New(), fromStoryID, toStoryID, "related", uuid.New()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	activityByStoryID := map[uuid.UUID]CoreActivity{}
+	for _, activity := range repo.activities {
+		activityByStoryID[activity.StoryID] = activity
+	}
+	if got := activityByStoryID[fromStoryID]; got.Field != "related_id" || got.OldValue != "Implements" {
+		t.Errorf("got source activity %s with old value %v", got.Field, got.OldValue)
+	}
+	if got := activityByStoryID[toStoryID]; got.OldValue != "Implemented by" {
+		t.Errorf("got target old value %v, want Implemented by", got.OldValue)
+	}
+}
@@ -0,0 +1,375 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrChecklistItemNotFound    = errors.New("checklist item not found")
+	ErrInvalidChecklistItem     = errors.New("checklist item text is required and must be at most 500 characters")
+	ErrInvalidChecklistAssignee = errors.New("checklist item assignee must be a member of the workspace")
+	ErrInvalidChecklistOrder    = errors.New("checklist order must list every item of the story once")
+)
+
+// maxChecklistItemLength is the longest checklist item text in characters.
+const maxChecklistItemLength = 500
+
+// checklistActivityType keeps checklist activities apart from field updates,
+// which are compacted, so quick changes to different items all show up.
+const checklistActivityType = "checklist"
+
+// Checklist activity fields.
+const (
+	checklistFieldAdded     = "checklist_item_added"
+	checklistFieldCompleted = "checklist_item_completed"
+	checklistFieldReopened  = "checklist_item_reopened"
+	checklistFieldRemoved   = "checklist_item_removed"
+	checklistFieldReordered = "checklist_reordered"
+	checklistFieldConverted = "checklist_item_converted"
+)
+
+// CoreChecklistItem is a step on a story's checklist.
+type CoreChecklistItem struct {
+	ID          uuid.UUID
+	StoryID     uuid.UUID
+	WorkspaceID uuid.UUID
+	Text        string
+	Done        bool
+	AssigneeID  *uuid.UUID
+	DueDate     *time.Time
+	OrderIndex  int
+	CreatedBy   *uuid.UUID
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CoreNewChecklistItem is a checklist item to add to a story.
+type CoreNewChecklistItem struct {
+	Text       string
+	AssigneeID *uuid.UUID
+	DueDate    *time.Time
+}
+
+// CoreChecklistProgress counts a story's checklist items.
+type CoreChecklistProgress struct {
+	Total int `json:"total"`
+	Done  int `json:"done"`
+}
+
+// ListChecklistItems returns a story's checklist in order.
+func (s *Service) ListChecklistItems(ctx context.Context, storyID, workspaceID uuid.UUID) ([]CoreChecklistItem, error) {
+	s.log.Info(ctx, "business.core.stories.ListChecklistItems")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.ListChecklistItems")
+	defer span.End()
+
+	if _, err := s.repo.Get(ctx, storyID, workspaceID); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	items, err := s.repo.ListChecklistItems(ctx, storyID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return items, nil
+}
+
+// AddChecklistItem adds an item to the end of a story's checklist.
+func (s *Service) AddChecklistItem(ctx context.Context, storyID, workspaceID, actorID uuid.UUID, ni CoreNewChecklistItem) (CoreChecklistItem, error) {
+	s.log.Info(ctx, "business.core.stories.AddChecklistItem")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.AddChecklistItem")
+	defer span.End()
+
+	text, err := normalizeChecklistText(ni.Text)
+	if err != nil {
+		return CoreChecklistItem{}, err
+	}
+	if _, err := s.repo.Get(ctx, storyID, workspaceID); err != nil {
+		span.RecordError(err)
+		return CoreChecklistItem{}, err
+	}
+
+	item, err := s.repo.CreateChecklistItem(ctx, CoreChecklistItem{
+		StoryID:     storyID,
+		WorkspaceID: workspaceID,
+		Text:        text,
+		AssigneeID:  ni.AssigneeID,
+		DueDate:     ni.DueDate,
+		CreatedBy:   &actorID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return CoreChecklistItem{}, err
+	}
+
+	s.recordChecklistActivity(ctx, item, actorID, checklistFieldAdded, nil, item.ID)
+	span.AddEvent("checklist item added.", trace.WithAttributes(
+		attribute.String("story.id", storyID.String()),
+		attribute.String("checklist_item.id", item.ID.String()),
+	))
+	return item, nil
+}
+
+// ToggleChecklistItem marks an item done, or not done when it already is.
+func (s *Service) ToggleChecklistItem(ctx context.Context, storyID, workspaceID, actorID, itemID uuid.UUID) (CoreChecklistItem, error) {
+	s.log.Info(ctx, "business.core.stories.ToggleChecklistItem")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.ToggleChecklistItem")
+	defer span.End()
+
+	if _, err := s.checklistItem(ctx, storyID, workspaceID, itemID); err != nil {
+		span.RecordError(err)
+		return CoreChecklistItem{}, err
+	}
+	item, err := s.repo.ToggleChecklistItem(ctx, itemID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreChecklistItem{}, err
+	}
+
+	field := checklistFieldReopened
+	if item.Done {
+		field = checklistFieldCompleted
+	}
+	s.recordChecklistActivity(ctx, item, actorID, field, !item.Done, item.Done)
+	span.AddEvent("checklist item toggled.", trace.WithAttributes(
+		attribute.String("checklist_item.id", itemID.String()),
+		attribute.Bool("checklist_item.done", item.Done),
+	))
+	return item, nil
+}
+
+// ReorderChecklistItems puts a story's checklist in the order of itemIDs,
+// which must list every item of the story once.
+func (s *Service) ReorderChecklistItems(ctx context.Context, storyID, workspaceID, actorID uuid.UUID, itemIDs []uuid.UUID) ([]CoreChecklistItem, error) {
+	s.log.Info(ctx, "business.core.stories.ReorderChecklistItems")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.ReorderChecklistItems")
+	defer span.End()
+
+	items, err := s.ListChecklistItems(ctx, storyID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if !sameChecklistItems(items, itemIDs) {
+		return nil, ErrInvalidChecklistOrder
+	}
+
+	if err := s.repo.ReorderChecklistItems(ctx, storyID, workspaceID, itemIDs); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	reordered, err := s.repo.ListChecklistItems(ctx, storyID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	oldOrder := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		oldOrder[i] = item.ID
+	}
+	activity := CoreActivity{
+		StoryID:      storyID,
+		Type:         checklistActivityType,
+		Field:        checklistFieldReordered,
+		CurrentValue: fmt.Sprintf("%d items", len(itemIDs)),
+		OldValue:     oldOrder,
+		NewValue:     itemIDs,
+		UserID:       actorID,
+		WorkspaceID:  workspaceID,
+	}
+	if _, err := s.repo.RecordActivities(ctx, []CoreActivity{activity}); err != nil {
+		span.RecordError(err)
+	}
+
+	span.AddEvent("checklist reordered.", trace.WithAttributes(
+		attribute.String("story.id", storyID.String()),
+		attribute.Int("checklist.count", len(itemIDs)),
+	))
+	return reordered, nil
+}
+
+// DeleteChecklistItem removes an item from a story's checklist.
+func (s *Service) DeleteChecklistItem(ctx context.Context, storyID, workspaceID, actorID, itemID uuid.UUID) error {
+	s.log.Info(ctx, "business.core.stories.DeleteChecklistItem")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.DeleteChecklistItem")
+	defer span.End()
+
+	item, err := s.checklistItem(ctx, storyID, workspaceID, itemID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if err := s.repo.DeleteChecklistItem(ctx, itemID, workspaceID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	s.recordChecklistActivity(ctx, item, actorID, checklistFieldRemoved, item.ID, nil)
+	span.AddEvent("checklist item deleted.", trace.WithAttributes(
+		attribute.String("checklist_item.id", itemID.String()),
+	))
+	return nil
+}
+
+// ConvertChecklistItem turns a checklist item into a sub-story of its story
+// and removes the item. The sub-story takes the item's text as its title,
+// its assignee and its due date as deadline, and starts in the team's
+// default unstarted status. The item is deleted in the transaction that
+// creates the sub-story, so converting it twice creates one sub-story.
+func (s *Service) ConvertChecklistItem(ctx context.Context, storyID, workspaceID, actorID, itemID uuid.UUID) (CoreSingleStory, error) {
+	s.log.Info(ctx, "business.core.stories.ConvertChecklistItem")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.ConvertChecklistItem")
+	defer span.End()
+
+	item, err := s.checklistItem(ctx, storyID, workspaceID, itemID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreSingleStory{}, err
+	}
+	parent, err := s.repo.Get(ctx, storyID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreSingleStory{}, err
+	}
+	statuses, err := s.repo.GetTeamStatuses(ctx, workspaceID, parent.Team)
+	if err != nil {
+		span.RecordError(err)
+		return CoreSingleStory{}, err
+	}
+
+	priority := parent.Priority
+	if priority == "" {
+		priority = "No Priority"
+	}
+	// The item has no custom field values to give, so the team's required
+	// fields are filled in on the sub-story afterwards.
+	subStory, err := s.createWithOptions(ctx, CoreNewStory{
+		Title:     item.Text,
+		Parent:    &parent.ID,
+		Team:      parent.Team,
+		Status:    matchTeamStatus("unstarted", statuses),
+		Assignee:  item.AssigneeID,
+		EndDate:   item.DueDate,
+		Priority:  priority,
+		Sprint:    parent.Sprint,
+		Objective: parent.Objective,
+		Reporter:  &actorID,
+	}, workspaceID, actorID, createOptions{
+		publishEvents:     true,
+		enqueueGitHubSync: true,
+		checklistItemID:   &item.ID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return CoreSingleStory{}, err
+	}
+	s.recordChecklistActivity(ctx, item, actorID, checklistFieldConverted, item.ID, subStory.ID)
+
+	span.AddEvent("checklist item converted.", trace.WithAttributes(
+		attribute.String("checklist_item.id", itemID.String()),
+		attribute.String("story.id", subStory.ID.String()),
+	))
+	return subStory, nil
+}
+
+// checklistItem returns an item of the story's checklist.
+func (s *Service) checklistItem(ctx context.Context, storyID, workspaceID, itemID uuid.UUID) (CoreChecklistItem, error) {
+	item, err := s.repo.GetChecklistItem(ctx, itemID, workspaceID)
+	if err != nil {
+		return CoreChecklistItem{}, err
+	}
+	if item.StoryID != storyID {
+		return CoreChecklistItem{}, ErrChecklistItemNotFound
+	}
+	return item, nil
+}
+
+// recordChecklistActivity records a change to a checklist item on its
+// story. Failures are logged so they never fail the change.
+func (s *Service) recordChecklistActivity(ctx context.Context, item CoreChecklistItem, actorID uuid.UUID, field string, oldValue, newValue any) {
+	activity := CoreActivity{
+		StoryID:      item.StoryID,
+		Type:         checklistActivityType,
+		Field:        field,
+		CurrentValue: item.Text,
+		OldValue:     oldValue,
+		NewValue:     newValue,
+		UserID:       actorID,
+		WorkspaceID:  item.WorkspaceID,
+	}
+	if _, err := s.repo.RecordActivities(ctx, []CoreActivity{activity}); err != nil {
+		s.log.Error(ctx, "failed to record checklist activity", "error", err, "storyId", item.StoryID)
+	}
+}
+
+// addChecklistProgress sets the checklist progress of stories and their
+// sub-stories. Stories without a checklist are left without progress.
+func (s *Service) addChecklistProgress(ctx context.Context, workspaceID uuid.UUID, stories []CoreStoryList) error {
+	var storyIDs []uuid.UUID
+	var collect func([]CoreStoryList)
+	collect = func(list []CoreStoryList) {
+		for _, story := range list {
+			storyIDs = append(storyIDs, story.ID)
+			collect(story.SubStories)
+		}
+	}
+	collect(stories)
+	if len(storyIDs) == 0 {
+		return nil
+	}
+
+	progress, err := s.repo.GetChecklistProgress(ctx, workspaceID, storyIDs)
+	if err != nil {
+		return err
+	}
+	var apply func([]CoreStoryList)
+	apply = func(list []CoreStoryList) {
+		for i := range list {
+			if p, ok := progress[list[i].ID]; ok {
+				list[i].Checklist = &p
+			}
+			apply(list[i].SubStories)
+		}
+	}
+	apply(stories)
+	return nil
+}
+
+// normalizeChecklistText trims item text and checks its length.
+func normalizeChecklistText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > maxChecklistItemLength {
+		return "", ErrInvalidChecklistItem
+	}
+	return text, nil
+}
+
+// sameChecklistItems reports whether itemIDs lists each item exactly once.
+func sameChecklistItems(items []CoreChecklistItem, itemIDs []uuid.UUID) bool {
+	if len(items) != len(itemIDs) {
+		return false
+	}
+	remaining := make(map[uuid.UUID]bool, len(items))
+	for _, item := range items {
+		remaining[item.ID] = true
+	}
+	for _, id := range itemIDs {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAddChecklistItemRecordsActivity(t *testing.T) {
+	storyID, workspaceID, actorID := uuid.New(), uuid.New(), uuid.New()
+	repo := &activityRecordingRepo{story: CoreSingleStory{ID: storyID}}
+	service := newActivityRecordingService(repo)
+
+	item, err := service.AddChecklistItem(context.Background(), storyID, workspaceID, actorID, CoreNewChecklistItem{Text: "  Write the migration  "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.Text != "Write the migration" {
+		t.Errorf("got text %q, want it trimmed", item.Text)
+	}
+	if item.CreatedBy == nil || *item.CreatedBy != actorID {
+		t.Errorf("got created by %v, want %s", item.CreatedBy, actorID)
+	}
+
+	if len(repo.activities) != 1 {
+		t.Fatalf("got %d activities, want 1", len(repo.activities))
+	}
+	activity := repo.activities[0]
+	if activity.Type != checklistActivityType || activity.Field != checklistFieldAdded {
+		t.Errorf("got activity %s/%s, want %s/%s", activity.Type, activity.Field, checklistActivityType, checklistFieldAdded)
+	}
+	if activity.StoryID != storyID || activity.UserID != actorID || activity.CurrentValue != item.Text {
+		t.Errorf("got activity %+v", activity)
+	}
+}
+
+func TestAddChecklistItemRejectsInvalidText(t *testing.T) {
+	service := newActivityRecordingService(&activityRecordingRepo{})
+
+	for _, text := range []string{"", "   ", strings.Repeat("a", maxChecklistItemLength+1)} {
+		_, err := service.AddChecklistItem(context.Background(), uuid.New(), uuid.New(), uuid.New(), CoreNewChecklistItem{Text: text})
+		if !errors.Is(err, ErrInvalidChecklistItem) {
+			t.Errorf("text of %d characters: got error %v, want ErrInvalidChecklistItem", len(text), err)
+		}
+	}
+}
+
+func TestToggleChecklistItemRecordsCompletion(t *testing.T) {
+	storyID := uuid.New()
+	item := CoreChecklistItem{ID: uuid.New(), StoryID: storyID, Text: "Review copy"}
+	repo := &activityRecordingRepo{checklist: []CoreChecklistItem{item}}
+	service := newActivityRecordingService(repo)
+
+	toggled, err := service.ToggleChecklistItem(context.Background(), storyID, uuid.New(), uuid.New(), item.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !toggled.Done {
+		t.Error("expected the item to be done")
+	}
+	if _, err := service.ToggleChecklistItem(context.Background(), storyID, uuid.New(), uuid.New(), item.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.activities) != 2 {
+		t.Fatalf("got %d activities, want 2", len(repo.activities))
+	}
+	if repo.activities[0].Field != checklistFieldCompleted || repo.activities[1].Field != checklistFieldReopened {
+		t.Errorf("got fields %s and %s, want %s and %s", repo.activities[0].Field, repo.activities[1].Field, checklistFieldCompleted, checklistFieldReopened)
+	}
+}
+
+func TestChecklistItemOfAnotherStory(t *testing.T) {
+	item := CoreChecklistItem{ID: uuid.New(), StoryID: uuid.New(), Text: "Elsewhere"}
+	repo := &activityRecordingRepo{checklist: []CoreChecklistItem{item}}
+	service := newActivityRecordingService(repo)
+
+	err := service.DeleteChecklistItem(context.Background(), uuid.New(), uuid.New(), uuid.New(), item.ID)
+	if !errors.Is(err, ErrChecklistItemNotFound) {
+		t.Fatalf("got error %v, want ErrChecklistItemNotFound", err)
+	}
+	if len(repo.checklist) != 1 {
+		t.Error("expected the item to be kept")
+	}
+}
+
+func TestReorderChecklistItems(t *testing.T) {
+	storyID := uuid.New()
+	first := CoreChecklistItem{ID: uuid.New(), StoryID: storyID}
+	second := CoreChecklistItem{ID: uuid.New(), StoryID: storyID}
+	repo := &activityRecordingRepo{story: CoreSingleStory{ID: storyID}, checklist: []CoreChecklistItem{first, second}}
+	service := newActivityRecordingService(repo)
+
+	invalid := [][]uuid.UUID{
+		{second.ID},
+		{second.ID, second.ID},
+		{second.ID, uuid.New()},
+	}
+	for _, order := range invalid {
+		if _, err := service.ReorderChecklistItems(context.Background(), storyID, uuid.New(), uuid.New(), order); !errors.Is(err, ErrInvalidChecklistOrder) {
+			t.Errorf("order %v: got error %v, want ErrInvalidChecklistOrder", order, err)
+		}
+	}
+
+	order := []uuid.UUID{second.ID, first.ID}
+	if _, err := service.ReorderChecklistItems(context.Background(), storyID, uuid.New(), uuid.New(), order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.checklistOrder) != 2 || repo.checklistOrder[0] != second.ID {
+		t.Errorf("got order %v, want %v", repo.checklistOrder, order)
+	}
+	if len(repo.activities) != 1 || repo.activities[0].Field != checklistFieldReordered {
+		t.Errorf("got activities %+v, want one reorder", repo.activities)
+	}
+}
+
+func TestAddChecklistProgress(t *testing.T) {
+	parent, child, other := uuid.New(), uuid.New(), uuid.New()
+	repo := &activityRecordingRepo{checklistProgress: map[uuid.UUID]CoreChecklistProgress{
+		parent: {Total: 3, Done: 1},
+		child:  {Total: 2, Done: 2},
+	}}
+	service := newActivityRecordingService(repo)
+
+	list := []CoreStoryList{
+		{ID: parent, SubStories: []CoreStoryList{{ID: child}}},
+		{ID: other},
+	}
+	if err := service.addChecklistProgress(context.Background(), uuid.New(), list); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if list[0].Checklist == nil || *list[0].Checklist != (CoreChecklistProgress{Total: 3, Done: 1}) {
+		t.Errorf("got parent progress %v", list[0].Checklist)
+	}
+	if list[0].SubStories[0].Checklist == nil || list[0].SubStories[0].Checklist.Done != 2 {
+		t.Errorf("got sub-story progress %v", list[0].SubStories[0].Checklist)
+	}
+	if list[1].Checklist != nil {
+		t.Errorf("got progress %v for a story without a checklist", list[1].Checklist)
+	}
+}
+
+func TestConvertChecklistItemDeletesItWithTheSubStory(t *testing.T) {
+	storyID, workspaceID, actorID := uuid.New(), uuid.New(), uuid.New()
+	item := CoreChecklistItem{ID: uuid.New(), StoryID: storyID, Text: "Write the migration"}
+	repo := &activityRecordingRepo{
+		story:     CoreSingleStory{ID: storyID, Team: uuid.New()},
+		checklist: []CoreChecklistItem{item},
+	}
+	service := newActivityRecordingService(repo)
+
+	subStory, err := service.ConvertChecklistItem(context.Background(), storyID, workspaceID, actorID, item.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if subStory.Title != item.Text || subStory.Parent == nil || *subStory.Parent != storyID {
+		t.Errorf("got sub-story %+v, want %q under %s", subStory, item.Text, storyID)
+	}
+	if repo.write.ChecklistItemID == nil || *repo.write.ChecklistItemID != item.ID || len(repo.checklist) != 0 {
+		t.Fatalf("expected the item to be deleted with the sub-story, got %v and %d items", repo.write.ChecklistItemID, len(repo.checklist))
+	}
+
+	// A second conversion that read the item before the first one deleted it
+	// fails when the sub-story is written.
+	_, err = service.createWithOptions(context.Background(), CoreNewStory{
+		Title:    item.Text,
+		Parent:   &storyID,
+		Team:     repo.story.Team,
+		Reporter: &actorID,
+	}, workspaceID, actorID, createOptions{checklistItemID: &item.ID})
+	if !errors.Is(err, ErrChecklistItemNotFound) {
+		t.Fatalf("got error %v, want ErrChecklistItemNotFound", err)
+	}
+	if len(repo.created) != 1 {
+		t.Errorf("got %d sub-stories, want 1", len(repo.created))
+	}
+}
@@ -0,0 +1,123 @@
+package stories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	customfields "github.com/complexus-tech/projects-api/internal/modules/customfields/service"
+	"github.com/google/uuid"
+)
+
+// customFieldsUpdateKey is the update map key carrying custom field values.
+const customFieldsUpdateKey = "custom_fields"
+
+// customFieldActivityPrefix prefixes the field ID in activity records, e.g.
+// "custom_field:<uuid>", which fits the activity field column.
+const customFieldActivityPrefix = "custom_field:"
+
+var (
+	ErrUnknownCustomField      = errors.New("custom field does not apply to this story's team")
+	ErrCustomFieldNotGroupable = errors.New("stories cannot be grouped by a multi select custom field")
+)
+
+// customFieldUpdates reads the custom field values from an update map value.
+// Handlers pass a typed map; integrations may pass decoded JSON.
+func customFieldUpdates(value any) (map[uuid.UUID]json.RawMessage, error) {
+	switch v := value.(type) {
+	case nil:
+		return map[uuid.UUID]json.RawMessage{}, nil
+	case map[uuid.UUID]json.RawMessage:
+		return v, nil
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", customfields.ErrInvalidValue, err)
+		}
+		values := map[uuid.UUID]json.RawMessage{}
+		if err := json.Unmarshal(encoded, &values); err != nil {
+			return nil, fmt.Errorf("%w: custom fields must be an object keyed by field id", customfields.ErrInvalidValue)
+		}
+		return values, nil
+	}
+}
+
+// validateCustomFieldValues checks values against the fields that apply to the
+// team and returns them in canonical form, with nil meaning "clear". When
+// requireAll is set every required field must be given a value.
+func (s *Service) validateCustomFieldValues(ctx context.Context, workspaceID, teamID uuid.UUID, values map[uuid.UUID]json.RawMessage, requireAll bool) (map[uuid.UUID]json.RawMessage, map[uuid.UUID]customfields.CoreCustomField, error) {
+	if len(values) == 0 && !requireAll {
+		return nil, nil, nil
+	}
+
+	fields, err := s.repo.GetCustomFields(ctx, workspaceID, teamID)
+	if err != nil {
+		return nil, nil, err
+	}
+	fieldsByID := make(map[uuid.UUID]customfields.CoreCustomField, len(fields))
+	for _, field := range fields {
+		fieldsByID[field.ID] = field
+	}
+
+	validated := make(map[uuid.UUID]json.RawMessage, len(values))
+	for fieldID, raw := range values {
+		field, ok := fieldsByID[fieldID]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s", ErrUnknownCustomField, fieldID)
+		}
+		value, err := customfields.ValidateValue(field, raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		validated[fieldID] = value
+	}
+
+	if requireAll {
+		for _, field := range fields {
+			if !field.Required || validated[field.ID] != nil {
+				continue
+			}
+			if _, err := customfields.ValidateValue(field, nil); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return validated, fieldsByID, nil
+}
+
+// customFieldActivities describes each changed custom field value.
+func customFieldActivities(story CoreSingleStory, changed map[uuid.UUID]json.RawMessage, fields map[uuid.UUID]customfields.CoreCustomField, base CoreActivity) []CoreActivity {
+	activities := make([]CoreActivity, 0, len(changed))
+	for fieldID, value := range changed {
+		activity := base
+		activity.Field = customFieldActivityPrefix + fieldID.String()
+		activity.CurrentValue = customfields.DisplayValue(fields[fieldID], value)
+		activity.OldValue = rawValueOrNil(story.CustomFields[fieldID])
+		activity.NewValue = rawValueOrNil(value)
+		activities = append(activities, activity)
+	}
+	return activities
+}
+
+func rawValueOrNil(value json.RawMessage) any {
+	if len(value) == 0 {
+		return nil
+	}
+	return value
+}
+
+// customFieldValueEqual compares two stored values. Postgres re-formats jsonb,
+// so values are compared decoded rather than byte for byte.
+func customFieldValueEqual(oldValue, newValue json.RawMessage) bool {
+	if len(oldValue) == 0 || len(newValue) == 0 {
+		return len(oldValue) == len(newValue)
+	}
+	var oldDecoded, newDecoded any
+	if json.Unmarshal(oldValue, &oldDecoded) != nil || json.Unmarshal(newValue, &newDecoded) != nil {
+		return string(oldValue) == string(newValue)
+	}
+	return reflect.DeepEqual(oldDecoded, newDecoded)
+}
@@ -0,0 +1,296 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Scopes a dependency graph can be built for.
+const (
+	DependencyScopeStory     = "story"
+	DependencyScopeEpic      = "epic"
+	DependencyScopeObjective = "objective"
+	DependencyScopeSprint    = "sprint"
+)
+
+// minutesPerDay is the working time counted for each scheduled day of a
+// story without an estimate.
+const minutesPerDay = 480
+
+var (
+	ErrDependencyCycle        = errors.New("blocking link would create a dependency cycle")
+	ErrInvalidDependencyScope = errors.New("dependency graph scope must be a story, epic, objective or sprint")
+)
+
+// CoreDependencyNode is a story in a dependency graph. InScope marks the
+// stories the graph was built for, the rest are reached through blocking
+// links.
+type CoreDependencyNode struct {
+	ID              uuid.UUID
+	SequenceID      int
+	Title           string
+	TeamID          uuid.UUID
+	TeamCode        string
+	StatusID        *uuid.UUID
+	StatusCategory  string
+	AssigneeID      *uuid.UUID
+	EstimateValue   *int16
+	EstimateLabel   *string
+	StartDate       *time.Time
+	EndDate         *time.Time
+	CompletedAt     *time.Time
+	InScope         bool
+	DurationMinutes int
+}
+
+// CoreDependencyEdge is a blocking link: BlockerID blocks BlockedID.
+type CoreDependencyEdge struct {
+	ID        uuid.UUID
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+}
+
+// CoreScheduleConflict is a story scheduled to start before a story that
+// blocks it is due to end.
+type CoreScheduleConflict struct {
+	BlockerID        uuid.UUID
+	BlockedID        uuid.UUID
+	BlockerEndDate   time.Time
+	BlockedStartDate time.Time
+}
+
+// CoreDependencyGraph is the blocking graph around a set of stories.
+// Blockers and Dependents hold the stories outside the scope that
+// transitively block or depend on it. The critical path is left out of any
+// cycles that predate cycle checks, which HasCycle reports.
+type CoreDependencyGraph struct {
+	Nodes               []CoreDependencyNode
+	Edges               []CoreDependencyEdge
+	Blockers            []uuid.UUID
+	Dependents          []uuid.UUID
+	CriticalPath        []uuid.UUID
+	CriticalPathMinutes int
+	Conflicts           []CoreScheduleConflict
+	HasCycle            bool
+}
+
+// ValidDependencyScope reports whether a dependency graph can be built for
+// the scope.
+func ValidDependencyScope(scope string) bool {
+	switch scope {
+	case DependencyScopeStory, DependencyScopeEpic, DependencyScopeObjective, DependencyScopeSprint:
+		return true
+	default:
+		return false
+	}
+}
+
+// DependencyGraph returns the blocking graph for a story, epic, objective or
+// sprint with its transitive blockers and dependents, critical path and
+// schedule conflicts.
+func (s *Service) DependencyGraph(ctx context.Context, workspaceID uuid.UUID, scope string, scopeID uuid.UUID) (CoreDependencyGraph, error) {
+	s.log.Info(ctx, "business.core.stories.DependencyGraph")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.DependencyGraph")
+	defer span.End()
+
+	if !ValidDependencyScope(scope) {
+		return CoreDependencyGraph{}, ErrInvalidDependencyScope
+	}
+
+	nodes, edges, err := s.repo.GetDependencyGraph(ctx, workspaceID, scope, scopeID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreDependencyGraph{}, err
+	}
+	if scope == DependencyScopeStory && len(nodes) == 0 {
+		return CoreDependencyGraph{}, ErrNotFound
+	}
+
+	schemeCache := map[uuid.UUID]string{}
+	for i := range nodes {
+		scheme, err := s.getEstimateSchemeForTeam(ctx, workspaceID, nodes[i].TeamID, schemeCache)
+		if err != nil {
+			span.RecordError(err)
+			return CoreDependencyGraph{}, err
+		}
+		nodes[i].EstimateLabel = EstimateLabelFromValue(scheme, nodes[i].EstimateValue)
+		nodes[i].DurationMinutes = dependencyDuration(nodes[i], scheme)
+	}
+
+	graph := analyseDependencies(nodes, edges)
+	span.AddEvent("dependency graph built.", trace.WithAttributes(
+		attribute.Int("graph.nodes", len(graph.Nodes)),
+		attribute.Int("graph.edges", len(graph.Edges)),
+		attribute.Int("graph.conflicts", len(graph.Conflicts)),
+	))
+	return graph, nil
+}
+
+// checkBlockingCycle rejects a blocking link from fromID to toID when toID
+// already blocks fromID, directly or through other stories. ignoreID leaves
+// out the link being changed.
+func (s *Service) checkBlockingCycle(ctx context.Context, fromID, toID, workspaceID, ignoreID uuid.UUID) error {
+	cycle, err := s.repo.BlockingPathExists(ctx, toID, fromID, workspaceID, ignoreID)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return ErrDependencyCycle
+	}
+	return nil
+}
+
+// dependencyDuration is the remaining work on a story in minutes: none once
+// it is completed, its estimate when it has one and otherwise its scheduled
+// days.
+func dependencyDuration(node CoreDependencyNode, scheme string) int {
+	if node.CompletedAt != nil {
+		return 0
+	}
+	if node.EstimateValue != nil {
+		return EstimateDurationMinutes(scheme, node.EstimateValue)
+	}
+	if node.StartDate != nil && node.EndDate != nil && !node.EndDate.Before(*node.StartDate) {
+		days := int(node.EndDate.Sub(*node.StartDate).Hours()/24) + 1
+		return days * minutesPerDay
+	}
+	return 0
+}
+
+// analyseDependencies derives the blockers, dependents, critical path and
+// schedule conflicts of a graph.
+func analyseDependencies(nodes []CoreDependencyNode, edges []CoreDependencyEdge) CoreDependencyGraph {
+	graph := CoreDependencyGraph{Nodes: nodes, Edges: edges}
+
+	index := make(map[uuid.UUID]int, len(nodes))
+	for i, node := range nodes {
+		index[node.ID] = i
+	}
+	blockedBy := make([][]int, len(nodes))
+	blocks := make([][]int, len(nodes))
+	for _, edge := range edges {
+		from, okFrom := index[edge.BlockerID]
+		to, okTo := index[edge.BlockedID]
+		if !okFrom || !okTo {
+			continue
+		}
+		blocks[from] = append(blocks[from], to)
+		blockedBy[to] = append(blockedBy[to], from)
+
+		blocker, blocked := nodes[from], nodes[to]
+		if blocker.CompletedAt == nil && blocker.EndDate != nil && blocked.StartDate != nil && !blocked.StartDate.After(*blocker.EndDate) {
+			graph.Conflicts = append(graph.Conflicts, CoreScheduleConflict{
+				BlockerID:        blocker.ID,
+				BlockedID:        blocked.ID,
+				BlockerEndDate:   *blocker.EndDate,
+				BlockedStartDate: *blocked.StartDate,
+			})
+		}
+	}
+
+	var scope []int
+	for i, node := range nodes {
+		if node.InScope {
+			scope = append(scope, i)
+		}
+	}
+	graph.Blockers = outOfScope(nodes, reachable(scope, blockedBy))
+	graph.Dependents = outOfScope(nodes, reachable(scope, blocks))
+
+	graph.CriticalPath, graph.CriticalPathMinutes, graph.HasCycle = criticalPath(nodes, blocks, blockedBy)
+	return graph
+}
+
+// reachable returns the nodes reachable from start along next, leaving out
+// the start nodes.
+func reachable(start []int, next [][]int) []int {
+	seen := make(map[int]bool, len(start))
+	for _, i := range start {
+		seen[i] = true
+	}
+	var found []int
+	queue := append([]int(nil), start...)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, n := range next[current] {
+			if seen[n] {
+				continue
+			}
+			seen[n] = true
+			found = append(found, n)
+			queue = append(queue, n)
+		}
+	}
+	return found
+}
+
+func outOfScope(nodes []CoreDependencyNode, indexes []int) []uuid.UUID {
+	ids := []uuid.UUID{}
+	for _, i := range indexes {
+		if !nodes[i].InScope {
+			ids = append(ids, nodes[i].ID)
+		}
+	}
+	return ids
+}
+
+// criticalPath returns the chain of blocking links with the most remaining
+// work, preferring longer chains when the work is equal. Stories on a cycle
+// cannot be ordered and are left out.
+func criticalPath(nodes []CoreDependencyNode, blocks, blockedBy [][]int) ([]uuid.UUID, int, bool) {
+	minutes := make([]int, len(nodes))
+	length := make([]int, len(nodes))
+	prev := make([]int, len(nodes))
+	pending := make([]int, len(nodes))
+
+	var queue []int
+	for i := range nodes {
+		minutes[i] = nodes[i].DurationMinutes
+		length[i] = 1
+		prev[i] = -1
+		pending[i] = len(blockedBy[i])
+		if pending[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+
+	end, ordered := -1, 0
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		ordered++
+		if end == -1 || minutes[current] > minutes[end] || minutes[current] == minutes[end] && length[current] > length[end] {
+			end = current
+		}
+		for _, next := range blocks[current] {
+			total := minutes[current] + nodes[next].DurationMinutes
+			if total > minutes[next] || total == minutes[next] && length[current]+1 > length[next] {
+				minutes[next] = total
+				length[next] = length[current] + 1
+				prev[next] = current
+			}
+			pending[next]--
+			if pending[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	hasCycle := ordered < len(nodes)
+	if end == -1 {
+		return []uuid.UUID{}, 0, hasCycle
+	}
+
+	path := make([]uuid.UUID, length[end])
+	for i, n := len(path)-1, end; n != -1; i, n = i-1, prev[n] {
+		path[i] = nodes[n].ID
+	}
+	return path, minutes[end], hasCycle
+}
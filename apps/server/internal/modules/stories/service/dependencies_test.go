@@ -0,0 +1,103 @@
+package stories
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAnalyseDependencies(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	done := day(1)
+
+	// upstream -> a -> b -> downstream, and a -> c, with a, b and c in scope.
+	upstream := CoreDependencyNode{ID: uuid.New(), DurationMinutes: 60, EndDate: day(10)}
+	a := CoreDependencyNode{ID: uuid.New(), InScope: true, DurationMinutes: 120, StartDate: day(9), EndDate: day(12)}
+	b := CoreDependencyNode{ID: uuid.New(), InScope: true, DurationMinutes: 480, StartDate: day(13)}
+	c := CoreDependencyNode{ID: uuid.New(), InScope: true, DurationMinutes: 240, StartDate: day(12)}
+	downstream := CoreDependencyNode{ID: uuid.New(), CompletedAt: done}
+	nodes := []CoreDependencyNode{upstream, a, b, c, downstream}
+	edges := []CoreDependencyEdge{
+		{ID: uuid.New(), BlockerID: upstream.ID, BlockedID: a.ID},
+		{ID: uuid.New(), BlockerID: a.ID, BlockedID: b.ID},
+		{ID: uuid.New(), BlockerID: a.ID, BlockedID: c.ID},
+		{ID: uuid.New(), BlockerID: b.ID, BlockedID: downstream.ID},
+	}
+
+	graph := analyseDependencies(nodes, edges)
+
+	if !reflect.DeepEqual(graph.Blockers, []uuid.UUID{upstream.ID}) {
+		t.Errorf("Blockers = %v, want %v", graph.Blockers, []uuid.UUID{upstream.ID})
+	}
+	if !reflect.DeepEqual(graph.Dependents, []uuid.UUID{downstream.ID}) {
+		t.Errorf("Dependents = %v, want %v", graph.Dependents, []uuid.UUID{downstream.ID})
+	}
+
+	wantPath := []uuid.UUID{upstream.ID, a.ID, b.ID, downstream.ID}
+	if !reflect.DeepEqual(graph.CriticalPath, wantPath) {
+		t.Errorf("CriticalPath = %v, want %v", graph.CriticalPath, wantPath)
+	}
+	if graph.CriticalPathMinutes != 660 {
+		t.Errorf("CriticalPathMinutes = %d, want 660", graph.CriticalPathMinutes)
+	}
+	if graph.HasCycle {
+		t.Error("expected no cycle")
+	}
+
+	wantConflicts := []CoreScheduleConflict{
+		{BlockerID: upstream.ID, BlockedID: a.ID, BlockerEndDate: *day(10), BlockedStartDate: *day(9)},
+		{BlockerID: a.ID, BlockedID: c.ID, BlockerEndDate: *day(12), BlockedStartDate: *day(12)},
+	}
+	if !reflect.DeepEqual(graph.Conflicts, wantConflicts) {
+		t.Errorf("Conflicts = %v, want %v", graph.Conflicts, wantConflicts)
+	}
+}
+
+func TestAnalyseDependenciesWithCycle(t *testing.T) {
+	a := CoreDependencyNode{ID: uuid.New(), InScope: true, DurationMinutes: 60}
+	b := CoreDependencyNode{ID: uuid.New(), DurationMinutes: 60}
+	c := CoreDependencyNode{ID: uuid.New(), DurationMinutes: 30}
+	edges := []CoreDependencyEdge{
+		{BlockerID: a.ID, BlockedID: b.ID},
+		{BlockerID: b.ID, BlockedID: a.ID},
+	}
+
+	graph := analyseDependencies([]CoreDependencyNode{a, b, c}, edges)
+
+	if !graph.HasCycle {
+		t.Error("expected the cycle to be reported")
+	}
+	if !reflect.DeepEqual(graph.CriticalPath, []uuid.UUID{c.ID}) {
+		t.Errorf("CriticalPath = %v, want only the story outside the cycle", graph.CriticalPath)
+	}
+}
+
+func TestDependencyDuration(t *testing.T) {
+	start := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 2)
+	estimate := int16(3)
+
+	tests := []struct {
+		name string
+		node CoreDependencyNode
+		want int
+	}{
+		{name: "estimate", node: CoreDependencyNode{EstimateValue: &estimate, StartDate: &start, EndDate: &end}, want: 120},
+		{name: "scheduled days", node: CoreDependencyNode{StartDate: &start, EndDate: &end}, want: 3 * minutesPerDay},
+		{name: "completed", node: CoreDependencyNode{EstimateValue: &estimate, CompletedAt: &end}, want: 0},
+		{name: "unplanned", node: CoreDependencyNode{}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dependencyDuration(tt.node, "hours"); got != tt.want {
+				t.Errorf("dependencyDuration() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
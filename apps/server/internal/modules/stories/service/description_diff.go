@@ -0,0 +1,89 @@
+package stories
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Diff operations for description lines.
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// maxDiffLines bounds the lines compared on each side of a diff. Lines past
+// the bound are reported as removed and added rather than aligned.
+const maxDiffLines = 2000
+
+// CoreDiffLine is one line of a description diff.
+type CoreDiffLine struct {
+	Op   string
+	Text string
+}
+
+var (
+	htmlLineBreaks = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|h[1-6]|pre|blockquote|tr)>`)
+	htmlTags       = regexp.MustCompile(`<[^>]*>`)
+)
+
+// descriptionLines splits a description into the lines a reader sees. The
+// editor saves HTML, so block elements become lines and tags are dropped;
+// the plain description is used when there is no HTML.
+func descriptionLines(description, descriptionHTML *string) []string {
+	text := ""
+	switch {
+	case descriptionHTML != nil && strings.TrimSpace(*descriptionHTML) != "":
+		text = htmlLineBreaks.ReplaceAllString(*descriptionHTML, "\n")
+		text = html.UnescapeString(htmlTags.ReplaceAllString(text, ""))
+	case description != nil:
+		text = *description
+	}
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// diffLines returns the edits that turn from into to, aligned on matching
+// runs of lines as found by difflib's sequence matcher.
+func diffLines(from, to []string) []CoreDiffLine {
+	var tail []CoreDiffLine
+	if len(from) > maxDiffLines || len(to) > maxDiffLines {
+		for _, line := range from[min(len(from), maxDiffLines):] {
+			tail = append(tail, CoreDiffLine{Op: DiffDelete, Text: line})
+		}
+		for _, line := range to[min(len(to), maxDiffLines):] {
+			tail = append(tail, CoreDiffLine{Op: DiffInsert, Text: line})
+		}
+		from, to = from[:min(len(from), maxDiffLines)], to[:min(len(to), maxDiffLines)]
+	}
+
+	lines := make([]CoreDiffLine, 0, len(from)+len(to))
+	add := func(op string, text []string) {
+		for _, line := range text {
+			lines = append(lines, CoreDiffLine{Op: op, Text: line})
+		}
+	}
+	for _, code := range difflib.NewMatcher(from, to).GetOpCodes() {
+		switch code.Tag {
+		case 'e':
+			add(DiffEqual, from[code.I1:code.I2])
+		case 'd':
+			add(DiffDelete, from[code.I1:code.I2])
+		case 'i':
+			add(DiffInsert, to[code.J1:code.J2])
+		case 'r':
+			add(DiffDelete, from[code.I1:code.I2])
+			add(DiffInsert, to[code.J1:code.J2])
+		}
+	}
+	return append(lines, tail...)
+}
@@ -0,0 +1,71 @@
+package stories
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDescriptionLines(t *testing.T) {
+	html := "<h2>Goal</h2><p>Ship &amp; measure</p><ul><li><p>One</p></li><li>Two</li></ul><p></p>"
+	plain := "first\n\n second "
+
+	tests := []struct {
+		name        string
+		description *string
+		html        *string
+		want        []string
+	}{
+		{name: "html blocks", html: &html, want: []string{"Goal", "Ship & measure", "One", "Two"}},
+		{name: "plain fallback", description: &plain, want: []string{"first", "second"}},
+		{name: "empty", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := descriptionLines(tt.description, tt.html); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("descriptionLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		from []string
+		to   []string
+		want []CoreDiffLine
+	}{
+		{
+			name: "unchanged",
+			from: []string{"a", "b"},
+			to:   []string{"a", "b"},
+			want: []CoreDiffLine{{DiffEqual, "a"}, {DiffEqual, "b"}},
+		},
+		{
+			name: "replaced line",
+			from: []string{"a", "b", "c"},
+			to:   []string{"a", "x", "c"},
+			want: []CoreDiffLine{{DiffEqual, "a"}, {DiffDelete, "b"}, {DiffInsert, "x"}, {DiffEqual, "c"}},
+		},
+		{
+			name: "added and removed",
+			from: []string{"a", "b"},
+			to:   []string{"b", "c"},
+			want: []CoreDiffLine{{DiffDelete, "a"}, {DiffEqual, "b"}, {DiffInsert, "c"}},
+		},
+		{
+			name: "from empty",
+			to:   []string{"a"},
+			want: []CoreDiffLine{{DiffInsert, "a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffLines(tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var ErrDescriptionRevisionNotFound = errors.New("description revision not found")
+
+// CoreDescriptionRevision is a saved version of a story description.
+// EditedBy is nil for the version that existed before history was kept and
+// when the editor's account was removed.
+type CoreDescriptionRevision struct {
+	ID              uuid.UUID
+	StoryID         uuid.UUID
+	WorkspaceID     uuid.UUID
+	Description     *string
+	DescriptionHTML *string
+	EditedBy        *uuid.UUID
+	Reason          *string
+	CreatedAt       time.Time
+}
+
+// CoreDescriptionDiff compares two description revisions line by line.
+type CoreDescriptionDiff struct {
+	From    CoreDescriptionRevision
+	To      CoreDescriptionRevision
+	Lines   []CoreDiffLine
+	Added   int
+	Removed int
+}
+
+// ListDescriptionRevisions returns a story's description revisions, newest
+// first.
+func (s *Service) ListDescriptionRevisions(ctx context.Context, storyID, workspaceID uuid.UUID) ([]CoreDescriptionRevision, error) {
+	s.log.Info(ctx, "business.core.stories.ListDescriptionRevisions")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.ListDescriptionRevisions")
+	defer span.End()
+
+	if _, err := s.repo.Get(ctx, storyID, workspaceID); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	revisions, err := s.repo.ListDescriptionRevisions(ctx, storyID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.AddEvent("description revisions retrieved.", trace.WithAttributes(
+		attribute.Int("revisions.count", len(revisions)),
+	))
+	return revisions, nil
+}
+
+// DiffDescriptionRevisions returns the line diff from one revision of a
+// story's description to another.
+func (s *Service) DiffDescriptionRevisions(ctx context.Context, storyID, workspaceID, fromID, toID uuid.UUID) (CoreDescriptionDiff, error) {
+	s.log.Info(ctx, "business.core.stories.DiffDescriptionRevisions")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.DiffDescriptionRevisions")
+	defer span.End()
+
+	from, err := s.descriptionRevision(ctx, storyID, workspaceID, fromID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreDescriptionDiff{}, err
+	}
+	to, err := s.descriptionRevision(ctx, storyID, workspaceID, toID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreDescriptionDiff{}, err
+	}
+
+	diff := CoreDescriptionDiff{
+		From:  from,
+		To:    to,
+		Lines: diffLines(descriptionLines(from.Description, from.DescriptionHTML), descriptionLines(to.Description, to.DescriptionHTML)),
+	}
+	for _, line := range diff.Lines {
+		switch line.Op {
+		case DiffInsert:
+			diff.Added++
+		case DiffDelete:
+			diff.Removed++
+		}
+	}
+	return diff, nil
+}
+
+// RestoreDescriptionRevision sets a story's description back to a saved
+// revision. The restore is saved as a new revision and recorded as an
+// activity, so it can itself be undone.
+func (s *Service) RestoreDescriptionRevision(ctx context.Context, storyID, workspaceID, actorID, revisionID uuid.UUID) error {
+	s.log.Info(ctx, "business.core.stories.RestoreDescriptionRevision")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.RestoreDescriptionRevision")
+	defer span.End()
+
+	revision, err := s.descriptionRevision(ctx, storyID, workspaceID, revisionID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	story, err := s.repo.Get(ctx, storyID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if s.valuesEqual(story.Description, revision.Description) && s.valuesEqual(story.DescriptionHTML, revision.DescriptionHTML) {
+		return nil
+	}
+
+	reason := fmt.Sprintf("Restored the description saved at %s.", revision.CreatedAt.UTC().Format(time.RFC3339))
+	if err := s.updateWithOptions(ctx, storyID, workspaceID, actorID, map[string]any{
+		"description":      revision.Description,
+		"description_html": revision.DescriptionHTML,
+	}, updateOptions{
+		publishEvents:     true,
+		enqueueGitHubSync: true,
+		activityReason:    reason,
+	}); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	activity := CoreActivity{
+		StoryID:      storyID,
+		Type:         "update",
+		Field:        "description",
+		CurrentValue: s.formatValue(revision.Description),
+		OldValue:     story.Description,
+		NewValue:     revision.ID,
+		Reason:       normalizeActivityReason(reason),
+		UserID:       actorID,
+		WorkspaceID:  workspaceID,
+	}
+	if _, err := s.repo.RecordActivities(ctx, []CoreActivity{activity}); err != nil {
+		span.RecordError(err)
+	}
+
+	span.AddEvent("description revision restored.", trace.WithAttributes(
+		attribute.String("story.id", storyID.String()),
+		attribute.String("revision.id", revisionID.String()),
+	))
+	return nil
+}
+
+func (s *Service) descriptionRevision(ctx context.Context, storyID, workspaceID, revisionID uuid.UUID) (CoreDescriptionRevision, error) {
+	revision, err := s.repo.GetDescriptionRevision(ctx, revisionID, workspaceID)
+	if err != nil {
+		return CoreDescriptionRevision{}, err
+	}
+	if revision.StoryID != storyID {
+		return CoreDescriptionRevision{}, ErrDescriptionRevisionNotFound
+	}
+	return revision, nil
+}
+
+// descriptionRevisionWrite adds the description a story has after updates
+// to write when the updates change it. The first saved revision of a story
+// also keeps the description it had before, so the history starts from the
+// original.
+func descriptionRevisionWrite(write *CoreStoryWrite, story CoreSingleStory, workspaceID uuid.UUID, updates map[string]any, reason *string) {
+	_, descriptionChanged := updates["description"]
+	_, htmlChanged := updates["description_html"]
+	if !descriptionChanged && !htmlChanged {
+		return
+	}
+
+	write.DescriptionRevision = &CoreDescriptionRevision{
+		StoryID:         story.ID,
+		WorkspaceID:     workspaceID,
+		Description:     descriptionValue(updates, "description", story.Description),
+		DescriptionHTML: descriptionValue(updates, "description_html", story.DescriptionHTML),
+		Reason:          reason,
+	}
+	if write.ActorID != uuid.Nil {
+		actorID := write.ActorID
+		write.DescriptionRevision.EditedBy = &actorID
+	}
+
+	if story.Description != nil || story.DescriptionHTML != nil {
+		write.OriginalDescription = &CoreDescriptionRevision{
+			StoryID:         story.ID,
+			WorkspaceID:     workspaceID,
+			Description:     story.Description,
+			DescriptionHTML: story.DescriptionHTML,
+			CreatedAt:       story.UpdatedAt,
+		}
+	}
+}
+
+// descriptionValue returns the description field a story has after updates.
+func descriptionValue(updates map[string]any, field string, current *string) *string {
+	value, ok := updates[field]
+	if !ok {
+		return current
+	}
+	switch v := value.(type) {
+	case string:
+		return &v
+	case *string:
+		return v
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,27 @@
+package stories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidEpic is returned when a story is put in an epic of another team
+// or workspace, or in one that does not exist.
+var ErrInvalidEpic = errors.New("epic must belong to the story's team")
+
+// validateEpic checks that epicID, when set, is an epic of the team.
+func (s *Service) validateEpic(ctx context.Context, workspaceID, teamID uuid.UUID, epicID *uuid.UUID) error {
+	if epicID == nil || *epicID == uuid.Nil {
+		return nil
+	}
+	ok, err := s.repo.EpicInTeam(ctx, *epicID, workspaceID, teamID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidEpic
+	}
+	return nil
+}
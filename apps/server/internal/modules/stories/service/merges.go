@@ -0,0 +1,178 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrMergeIntoSelf     = errors.New("cannot merge a story into itself")
+	ErrNoCancelledStatus = errors.New("the duplicate story's team has no cancelled status")
+)
+
+// CoreStoryMerge is a duplicate story closed into its canonical story. The
+// duplicate moves to CancelledStatusID. The repository fills in both
+// references and how much was carried over.
+type CoreStoryMerge struct {
+	DuplicateID       uuid.UUID
+	CanonicalID       uuid.UUID
+	WorkspaceID       uuid.UUID
+	CancelledStatusID uuid.UUID
+	DuplicateRef      string
+	CanonicalRef      string
+	Comments          int
+	Attachments       int
+	Links             int
+	Labels            int
+	GitHubLinks       int
+	FeedbackLinks     int
+	Watchers          int
+	TimeEntries       int
+}
+
+// MergeStories closes a duplicate story into its canonical story. Comments,
+// attachments, links, labels, GitHub and feedback links, associations,
+// sub-stories, time entries, description revisions and custom field values
+// move to the canonical story and the duplicate's watchers start watching
+// it. The duplicate moves to its team's cancelled status and stays linked to
+// the canonical story as a duplicate.
+func (s *Service) MergeStories(ctx context.Context, workspaceID, actorID, duplicateID, canonicalID uuid.UUID) (CoreStoryMerge, error) {
+	s.log.Info(ctx, "business.core.stories.MergeStories")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.MergeStories")
+	defer span.End()
+
+	if duplicateID == canonicalID {
+		return CoreStoryMerge{}, ErrMergeIntoSelf
+	}
+
+	duplicate, err := s.repo.Get(ctx, duplicateID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreStoryMerge{}, err
+	}
+	statuses, err := s.repo.GetTeamStatuses(ctx, workspaceID, duplicate.Team)
+	if err != nil {
+		span.RecordError(err)
+		return CoreStoryMerge{}, err
+	}
+	cancelledID := cancelledTeamStatus(statuses)
+	if cancelledID == nil {
+		return CoreStoryMerge{}, ErrNoCancelledStatus
+	}
+	// The merge picks the cancelled status, so teams that let integrations
+	// skip their transition rules let merges skip them too.
+	if err := s.checkTransition(ctx, workspaceID, actorID, duplicate, map[string]any{"status_id": *cancelledID}, true, make(map[uuid.UUID]*CoreWorkflowRules)); err != nil {
+		span.RecordError(err)
+		return CoreStoryMerge{}, err
+	}
+
+	merge := CoreStoryMerge{
+		DuplicateID:       duplicateID,
+		CanonicalID:       canonicalID,
+		WorkspaceID:       workspaceID,
+		CancelledStatusID: *cancelledID,
+	}
+	merge, err = s.repo.MergeStory(ctx, merge, mergeEvents(merge, duplicate.Assignee, actorID)...)
+	if err != nil {
+		span.RecordError(err)
+		return CoreStoryMerge{}, err
+	}
+	s.refreshStorySLA(ctx, duplicateID, workspaceID, false)
+
+	if _, err := s.repo.RecordActivities(ctx, s.mergeActivities(merge, duplicate.Status, actorID)); err != nil {
+		span.RecordError(err)
+	}
+
+	span.AddEvent("stories merged.", trace.WithAttributes(
+		attribute.String("story.duplicate_id", duplicateID.String()),
+		attribute.String("story.canonical_id", canonicalID.String()),
+	))
+	return merge, nil
+}
+
+// cancelledTeamStatus returns the team's default cancelled status, else its
+// first one. statuses are in order.
+func cancelledTeamStatus(statuses []CoreTeamStatus) *uuid.UUID {
+	var match *CoreTeamStatus
+	for i := range statuses {
+		status := &statuses[i]
+		if status.Category == "cancelled" && (match == nil || status.IsDefault) {
+			match = status
+		}
+	}
+	if match == nil {
+		return nil
+	}
+	return &match.ID
+}
+
+// mergeEvents are the story.updated events written with a merge: the
+// duplicate changed status and the canonical story took over its work.
+func mergeEvents(merge CoreStoryMerge, assigneeID *uuid.UUID, actorID uuid.UUID) []events.Event {
+	now := time.Now()
+	return []events.Event{
+		{
+			Type: events.StoryUpdated,
+			Payload: events.StoryUpdatedPayload{
+				StoryID:     merge.DuplicateID,
+				WorkspaceID: merge.WorkspaceID,
+				Updates:     map[string]any{"status_id": merge.CancelledStatusID},
+				AssigneeID:  assigneeID,
+			},
+			Timestamp: now,
+			ActorID:   actorID,
+		},
+		{
+			Type: events.StoryUpdated,
+			Payload: events.StoryUpdatedPayload{
+				StoryID:     merge.CanonicalID,
+				WorkspaceID: merge.WorkspaceID,
+				Updates:     map[string]any{"merged_from": merge.DuplicateID},
+			},
+			Timestamp: now,
+			ActorID:   actorID,
+		},
+	}
+}
+
+// mergeActivities leaves a tombstone on both stories of a merge, each
+// pointing at the other, and records the duplicate's move to the cancelled
+// status.
+func (s *Service) mergeActivities(merge CoreStoryMerge, oldStatusID *uuid.UUID, actorID uuid.UUID) []CoreActivity {
+	activity := func(storyID uuid.UUID, field string, value uuid.UUID, reason string) CoreActivity {
+		return CoreActivity{
+			StoryID:      storyID,
+			Type:         "update",
+			Field:        field,
+			CurrentValue: s.formatValue(value),
+			NewValue:     value,
+			Reason:       normalizeActivityReason(reason),
+			UserID:       actorID,
+			WorkspaceID:  merge.WorkspaceID,
+		}
+	}
+
+	return []CoreActivity{
+		activity(merge.DuplicateID, "merged_into", merge.CanonicalID, fmt.Sprintf("Merged into %s.", merge.CanonicalRef)),
+		activity(merge.CanonicalID, "merged_from", merge.DuplicateID, fmt.Sprintf("Merged %s into this story.", merge.DuplicateRef)),
+		{
+			StoryID:      merge.DuplicateID,
+			Type:         "update",
+			Field:        "status_id",
+			CurrentValue: s.formatValue(merge.CancelledStatusID),
+			OldValue:     oldStatusID,
+			NewValue:     merge.CancelledStatusID,
+			Reason:       normalizeActivityReason(fmt.Sprintf("Merged into %s.", merge.CanonicalRef)),
+			UserID:       actorID,
+			WorkspaceID:  merge.WorkspaceID,
+		},
+	}
+}
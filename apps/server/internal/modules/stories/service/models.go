@@ -1,8 +1,10 @@
 package stories
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/complexus-tech/projects-api/pkg/events"
 	"github.com/google/uuid"
 )
 
@@ -68,6 +70,8 @@ type CoreStoryList struct {
 	ArchivedAt       *time.Time            `json:"archived_at"`
 	Labels           []uuid.UUID           `json:"labels"`
 	SubStories       []CoreStoryList       `json:"subStories"`
+	// Checklist is set on stories with checklist items.
+	Checklist *CoreChecklistProgress `json:"checklist,omitempty"`
 }
 
 // CoreSingleStory represents a single story.
@@ -103,9 +107,14 @@ type CoreSingleStory struct {
 	DeletedAt       *time.Time
 	ArchivedAt      *time.Time
 	CompletedAt     *time.Time
-	SubStories      []CoreStoryList
-	Labels          []uuid.UUID
-	Associations    []CoreStoryAssociation
+	// MergedInto is the canonical story a merged duplicate redirects to.
+	MergedInto   *uuid.UUID
+	SubStories   []CoreStoryList
+	Labels       []uuid.UUID
+	Associations []CoreStoryAssociation
+	Watchers     []uuid.UUID
+	// CustomFields maps a custom field ID to its stored value.
+	CustomFields map[uuid.UUID]json.RawMessage
 }
 
 type CoreNewStory struct {
@@ -115,6 +124,7 @@ type CoreNewStory struct {
 	DescriptionHTML *string     `json:"descriptionHTML"`
 	Parent          *uuid.UUID  `json:"parentId"`
 	Objective       *uuid.UUID  `json:"objectiveId"`
+	Epic            *uuid.UUID  `json:"epicId"`
 	Status          *uuid.UUID  `json:"statusId"`
 	Assignee        *uuid.UUID  `json:"assigneeId"`
 	BlockedBy       *uuid.UUID  `json:"blockedById"`
@@ -128,6 +138,8 @@ type CoreNewStory struct {
 	StartDate       *time.Time  `json:"startDate"`
 	EndDate         *time.Time  `json:"endDate"`
 	Team            uuid.UUID   `json:"teamId"`
+	// CustomFields maps a custom field ID to its value.
+	CustomFields map[uuid.UUID]json.RawMessage `json:"customFields"`
 }
 
 type CoreUpdateStory struct {
@@ -161,6 +173,7 @@ func toCoreSingleStory(ns CoreNewStory, workspaceId uuid.UUID) CoreSingleStory {
 		DescriptionHTML: ns.DescriptionHTML,
 		Parent:          ns.Parent,
 		Objective:       ns.Objective,
+		Epic:            ns.Epic,
 		Status:          ns.Status,
 		Assignee:        ns.Assignee,
 		BlockedBy:       ns.BlockedBy,
@@ -178,6 +191,31 @@ func toCoreSingleStory(ns CoreNewStory, workspaceId uuid.UUID) CoreSingleStory {
 	}
 }
 
+// CoreStoryWrite holds the writes committed in the same transaction as a
+// story insert or update, so a failure never leaves half of them behind.
+type CoreStoryWrite struct {
+	// ActorID is recorded as the editor of custom field values.
+	ActorID uuid.UUID
+	// CustomFieldValues are upserted; a nil value removes the stored value.
+	CustomFieldValues map[uuid.UUID]json.RawMessage
+	// Events are written to the outbox.
+	Events []events.Event
+	// DescriptionRevision is saved as a new description revision.
+	DescriptionRevision *CoreDescriptionRevision
+	// OriginalDescription is saved before DescriptionRevision when the story
+	// has no revisions yet, so its history starts from the original.
+	OriginalDescription *CoreDescriptionRevision
+	// WIPStatusIDs are the statuses the story moves into. Their WIP limit
+	// rows are locked and read with current counts before the update, and
+	// passed to CheckWIP, whose error aborts the write.
+	WIPStatusIDs []uuid.UUID
+	CheckWIP     func(limits []CoreStatusWIP) error
+	// ChecklistItemID is the checklist item a new story replaces. It is
+	// deleted before the insert, and the write fails with
+	// ErrChecklistItemNotFound when it is already gone.
+	ChecklistItemID *uuid.UUID
+}
+
 // CoreStoryAssociation represents a relationship between two stories.
 type CoreStoryAssociation struct {
 	ID           uuid.UUID     `json:"id"`
@@ -230,6 +268,7 @@ type UserDetails struct {
 }
 
 type CoreNewComment struct {
+	ID       uuid.UUID
 	StoryID  uuid.UUID
 	Parent   *uuid.UUID
 	UserID   uuid.UUID
@@ -254,12 +293,18 @@ type CoreStoryFilters struct {
 	Epic           *uuid.UUID  `json:"epicId"`
 	KeyResult      *uuid.UUID  `json:"keyResultId"`
 	HasNoAssignee  *bool       `json:"hasNoAssignee"`
+	HasNoSprint    *bool       `json:"hasNoSprint"`
+	HasNoEpic      *bool       `json:"hasNoEpic"`
 	HasBlockedBy   *bool       `json:"hasBlockedBy"`
-	AssignedToMe   *bool       `json:"assignedToMe"`
-	CreatedByMe    *bool       `json:"createdByMe"`
-	ShowSubStories *bool       `json:"showSubStories"`
-	CurrentUserID  uuid.UUID   `json:"currentUserId"`
-	WorkspaceID    uuid.UUID   `json:"workspaceId"`
+	SLABreached    *bool       `json:"slaBreached"`
+	// AssociationTypes keeps stories linked to another story, in either
+	// direction, by an association of one of the types.
+	AssociationTypes []string  `json:"associationTypes"`
+	AssignedToMe     *bool     `json:"assignedToMe"`
+	CreatedByMe      *bool     `json:"createdByMe"`
+	ShowSubStories   *bool     `json:"showSubStories"`
+	CurrentUserID    uuid.UUID `json:"currentUserId"`
+	WorkspaceID      uuid.UUID `json:"workspaceId"`
 	// Date range filters
 	CreatedAfter    *time.Time `json:"createdAfter"`
 	CreatedBefore   *time.Time `json:"createdBefore"`
@@ -275,6 +320,39 @@ type CoreStoryFilters struct {
 	IsNotCompleted  *bool      `json:"isNotCompleted"`
 	IncludeArchived *bool      `json:"includeArchived"`
 	IncludeDeleted  *bool      `json:"includeDeleted"`
+	// Custom field filters; every entry must match
+	CustomFields []CoreCustomFieldFilter `json:"customFields"`
+	// Exclude drops stories matching any of its entries
+	Exclude *CoreStoryExclusions `json:"exclude,omitempty"`
+}
+
+// CoreStoryExclusions lists values a story must not have. Stories without an
+// assignee, sprint or epic are kept by the ID lists; use Unassigned, NoSprint
+// and NoEpic to drop those.
+type CoreStoryExclusions struct {
+	StatusIDs      []uuid.UUID `json:"statusIds,omitempty"`
+	AssigneeIDs    []uuid.UUID `json:"assigneeIds,omitempty"`
+	ReporterIDs    []uuid.UUID `json:"reporterIds,omitempty"`
+	Priorities     []string    `json:"priorities,omitempty"`
+	Categories     []string    `json:"categories,omitempty"`
+	TeamIDs        []uuid.UUID `json:"teamIds,omitempty"`
+	SprintIDs      []uuid.UUID `json:"sprintIds,omitempty"`
+	LabelIDs       []uuid.UUID `json:"labelIds,omitempty"`
+	EpicIDs        []uuid.UUID `json:"epicIds,omitempty"`
+	EstimateValues []int16     `json:"estimateValues,omitempty"`
+	Unassigned     bool        `json:"unassigned,omitempty"`
+	NoSprint       bool        `json:"noSprint,omitempty"`
+	NoEpic         bool        `json:"noEpic,omitempty"`
+	Blocked        bool        `json:"blocked,omitempty"`
+}
+
+// CoreCustomFieldFilter matches stories whose value for a custom field is one of
+// Values. For multi select fields any overlapping option matches. IsEmpty matches
+// stories without a value instead.
+type CoreCustomFieldFilter struct {
+	FieldID uuid.UUID `json:"fieldId"`
+	Values  []string  `json:"values"`
+	IsEmpty bool      `json:"isEmpty"`
 }
 
 // CoreStoryQuery represents query parameters for grouped stories
@@ -297,4 +375,6 @@ type CoreStoryGroup struct {
 	HasMore     bool            `json:"hasMore"`
 	Stories     []CoreStoryList `json:"stories"`
 	NextPage    int             `json:"nextPage"`
+	// WIP is set on status groups whose status has a WIP limit.
+	WIP *CoreStoryGroupWIP `json:"wip,omitempty"`
 }
@@ -0,0 +1,38 @@
+package stories
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// CustomFieldGroupByPrefix prefixes the field ID when grouping by a custom
+// field, e.g. "custom_field:<uuid>".
+const CustomFieldGroupByPrefix = "custom_field:"
+
+var (
+	groupByValues = []string{"status", "assignee", "priority", "team", "sprint", "epic", "none"}
+	orderByValues = []string{"created", "updated", "priority", "deadline", "rank"}
+)
+
+// IsValidGroupBy reports whether groupBy is a supported grouping for
+// CoreStoryQuery.
+func IsValidGroupBy(groupBy string) bool {
+	if fieldID, ok := strings.CutPrefix(groupBy, CustomFieldGroupByPrefix); ok {
+		_, err := uuid.Parse(fieldID)
+		return err == nil
+	}
+	return slices.Contains(groupByValues, groupBy)
+}
+
+// IsValidOrderBy reports whether orderBy is a supported ordering for
+// CoreStoryQuery.
+func IsValidOrderBy(orderBy string) bool {
+	return slices.Contains(orderByValues, orderBy)
+}
+
+// IsValidOrderDirection reports whether direction is asc or desc.
+func IsValidOrderDirection(direction string) bool {
+	return direction == "asc" || direction == "desc"
+}
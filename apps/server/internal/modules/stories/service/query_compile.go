@@ -0,0 +1,642 @@
+package stories
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Kinds of names looked up by Repository.ResolveQueryNames.
+const (
+	QueryNameUsers    = "users"
+	QueryNameLabels   = "labels"
+	QueryNameStatuses = "statuses"
+	QueryNameTeams    = "teams"
+	QueryNameSprints  = "sprints"
+	QueryNameEpics    = "epics"
+)
+
+// queryFields maps the field names accepted in expressions, including
+// aliases, to the field they filter on.
+var queryFields = map[string]string{
+	"assignee":  "assignee",
+	"reporter":  "reporter",
+	"creator":   "reporter",
+	"priority":  "priority",
+	"status":    "status",
+	"label":     "label",
+	"team":      "team",
+	"sprint":    "sprint",
+	"epic":      "epic",
+	"estimate":  "estimate",
+	"is":        "is",
+	"created":   "created",
+	"updated":   "updated",
+	"start":     "start",
+	"due":       "due",
+	"deadline":  "due",
+	"completed": "completed",
+}
+
+var queryFieldNameKinds = map[string]string{
+	"assignee": QueryNameUsers,
+	"reporter": QueryNameUsers,
+	"status":   QueryNameStatuses,
+	"label":    QueryNameLabels,
+	"team":     QueryNameTeams,
+	"sprint":   QueryNameSprints,
+	"epic":     QueryNameEpics,
+}
+
+var queryNameNouns = map[string]string{
+	QueryNameUsers:    "user",
+	QueryNameLabels:   "label",
+	QueryNameStatuses: "status",
+	QueryNameTeams:    "team",
+	QueryNameSprints:  "sprint",
+	QueryNameEpics:    "epic",
+}
+
+var queryPriorities = map[string]string{
+	"urgent": "Urgent",
+	"high":   "High",
+	"medium": "Medium",
+	"low":    "Low",
+	"none":   "No Priority",
+}
+
+var queryCategories = []string{"backlog", "unstarted", "started", "paused", "completed", "cancelled"}
+
+var queryRelativeDate = regexp.MustCompile(`^(\+?)(\d+)([hdwmy])$`)
+
+// CompileQuery compiles a query expression into story filters, looking up
+// names of users, labels, statuses, teams, sprints and epics in the
+// workspace. "me" refers to userID. The filters carry neither the viewer nor
+// the workspace; callers set CurrentUserID and WorkspaceID.
+func (s *Service) CompileQuery(ctx context.Context, workspaceID, userID uuid.UUID, expression string) (CoreStoryFilters, error) {
+	s.log.Info(ctx, "business.core.stories.CompileQuery")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.CompileQuery")
+	defer span.End()
+
+	terms, err := ParseQuery(expression)
+	if err != nil {
+		return CoreStoryFilters{}, err
+	}
+
+	lookups, err := queryNameLookups(terms)
+	if err != nil {
+		return CoreStoryFilters{}, err
+	}
+
+	resolved := make(map[string]map[string][]uuid.UUID, len(lookups))
+	for kind, names := range lookups {
+		ids, err := s.repo.ResolveQueryNames(ctx, workspaceID, kind, names)
+		if err != nil {
+			span.RecordError(err)
+			return CoreStoryFilters{}, fmt.Errorf("resolving %s in query: %w", kind, err)
+		}
+		resolved[kind] = ids
+	}
+
+	filters, err := compileQueryTerms(terms, userID, time.Now().UTC(), resolved)
+	if err != nil {
+		return CoreStoryFilters{}, err
+	}
+
+	span.AddEvent("query compiled", trace.WithAttributes(
+		attribute.Int("query.terms", len(terms)),
+	))
+	return filters, nil
+}
+
+// queryNameLookups validates the fields of terms and collects, per kind, the
+// lowercased names that must be looked up.
+func queryNameLookups(terms []QueryTerm) (map[string][]string, error) {
+	lookups := make(map[string][]string)
+	for _, term := range terms {
+		if term.Field == "" {
+			continue
+		}
+		field, ok := queryFields[term.Field]
+		if !ok {
+			return nil, queryErrorf(term.Pos, "unknown field %q", term.Field)
+		}
+		kind, ok := queryFieldNameKinds[field]
+		if !ok {
+			continue
+		}
+		if field == "status" && allQueryCategories(term.Values) {
+			continue
+		}
+		for _, value := range term.Values {
+			if key, ok := queryNameKey(kind, value); ok && !slices.Contains(lookups[kind], key) {
+				lookups[kind] = append(lookups[kind], key)
+			}
+		}
+	}
+	return lookups, nil
+}
+
+// queryNameKey returns the lookup key of a value, or false when the value is
+// a keyword or an ID that needs no lookup.
+func queryNameKey(kind string, value QueryValue) (string, bool) {
+	text := strings.ToLower(value.Text)
+	if !value.Quoted {
+		if text == "none" || (kind == QueryNameUsers && text == "me") {
+			return "", false
+		}
+	}
+	if _, err := uuid.Parse(text); err == nil {
+		return "", false
+	}
+	if kind == QueryNameUsers {
+		text = strings.TrimPrefix(text, "@")
+	}
+	return text, true
+}
+
+func allQueryCategories(values []QueryValue) bool {
+	for _, value := range values {
+		if value.Quoted || !slices.Contains(queryCategories, strings.ToLower(value.Text)) {
+			return false
+		}
+	}
+	return true
+}
+
+type queryCompiler struct {
+	filters  CoreStoryFilters
+	userID   uuid.UUID
+	now      time.Time
+	resolved map[string]map[string][]uuid.UUID
+	text     []string
+	dates    map[string]bool
+}
+
+// compileQueryTerms builds filters from parsed terms. Values of one term, and
+// repeated terms of the same field, match any of their values; different
+// fields must all match. resolved holds the IDs found for each looked up
+// name, keyed by kind.
+func compileQueryTerms(terms []QueryTerm, userID uuid.UUID, now time.Time, resolved map[string]map[string][]uuid.UUID) (CoreStoryFilters, error) {
+	c := queryCompiler{
+		userID:   userID,
+		now:      now,
+		resolved: resolved,
+		dates:    make(map[string]bool),
+	}
+	for _, term := range terms {
+		if err := c.term(term); err != nil {
+			return CoreStoryFilters{}, err
+		}
+	}
+	if len(c.text) > 0 {
+		text := strings.Join(c.text, " ")
+		c.filters.TitleContains = &text
+	}
+	return c.filters, nil
+}
+
+func (c *queryCompiler) term(term QueryTerm) error {
+	if term.Field == "" {
+		if term.Negated {
+			return queryErrorf(term.Pos, "free text cannot be negated")
+		}
+		c.text = append(c.text, term.Values[0].Text)
+		return nil
+	}
+
+	field, ok := queryFields[term.Field]
+	if !ok {
+		return queryErrorf(term.Pos, "unknown field %q", term.Field)
+	}
+
+	switch field {
+	case "assignee", "reporter":
+		return c.people(field, term)
+	case "priority":
+		return c.priority(term)
+	case "status":
+		return c.status(term)
+	case "label", "team", "sprint", "epic":
+		return c.named(field, term)
+	case "estimate":
+		return c.estimate(term)
+	case "is":
+		return c.is(term)
+	default:
+		return c.date(field, term)
+	}
+}
+
+// exclude returns the exclusions of the filters, creating them on first use.
+func (c *queryCompiler) exclude() *CoreStoryExclusions {
+	if c.filters.Exclude == nil {
+		c.filters.Exclude = &CoreStoryExclusions{}
+	}
+	return c.filters.Exclude
+}
+
+// isNone reports whether the term is field:none. A quoted "none" is a name.
+func isNone(term QueryTerm) (bool, error) {
+	for _, value := range term.Values {
+		if !value.Quoted && strings.EqualFold(value.Text, "none") {
+			if len(term.Values) > 1 {
+				return false, queryErrorf(value.Pos, "none cannot be combined with other values")
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *queryCompiler) ids(kind string, value QueryValue) ([]uuid.UUID, error) {
+	if id, err := uuid.Parse(value.Text); err == nil {
+		return []uuid.UUID{id}, nil
+	}
+	if kind == QueryNameUsers && !value.Quoted && strings.EqualFold(value.Text, "me") {
+		return []uuid.UUID{c.userID}, nil
+	}
+	key, _ := queryNameKey(kind, value)
+	ids := c.resolved[kind][key]
+	if len(ids) == 0 {
+		return nil, queryErrorf(value.Pos, "no %s named %q", queryNameNouns[kind], value.Text)
+	}
+	return ids, nil
+}
+
+func (c *queryCompiler) termIDs(kind string, term QueryTerm) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, value := range term.Values {
+		found, err := c.ids(kind, value)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, found...)
+	}
+	return ids, nil
+}
+
+func (c *queryCompiler) people(field string, term QueryTerm) error {
+	none, err := isNone(term)
+	if err != nil {
+		return err
+	}
+	if none {
+		if field == "reporter" {
+			return queryErrorf(term.Pos, "reporter cannot be none")
+		}
+		if term.Negated {
+			c.exclude().Unassigned = true
+		} else {
+			noAssignee := true
+			c.filters.HasNoAssignee = &noAssignee
+		}
+		return nil
+	}
+
+	ids, err := c.termIDs(QueryNameUsers, term)
+	if err != nil {
+		return err
+	}
+	switch {
+	case field == "assignee" && term.Negated:
+		c.exclude().AssigneeIDs = append(c.exclude().AssigneeIDs, ids...)
+	case field == "assignee":
+		c.filters.AssigneeIDs = append(c.filters.AssigneeIDs, ids...)
+	case term.Negated:
+		c.exclude().ReporterIDs = append(c.exclude().ReporterIDs, ids...)
+	default:
+		c.filters.ReporterIDs = append(c.filters.ReporterIDs, ids...)
+	}
+	return nil
+}
+
+func (c *queryCompiler) priority(term QueryTerm) error {
+	var priorities []string
+	for _, value := range term.Values {
+		priority, ok := queryPriorities[strings.ToLower(value.Text)]
+		if !ok {
+			return queryErrorf(value.Pos, "unknown priority %q; use urgent, high, medium, low or none", value.Text)
+		}
+		priorities = append(priorities, priority)
+	}
+	if term.Negated {
+		c.exclude().Priorities = append(c.exclude().Priorities, priorities...)
+	} else {
+		c.filters.Priorities = append(c.filters.Priorities, priorities...)
+	}
+	return nil
+}
+
+// status matches category names such as "started" on the category and other
+// values on the status name. When both are mixed, categories are resolved to
+// their statuses so the values still match any of them.
+func (c *queryCompiler) status(term QueryTerm) error {
+	if none, err := isNone(term); err != nil {
+		return err
+	} else if none {
+		return queryErrorf(term.Pos, "status cannot be none")
+	}
+
+	if allQueryCategories(term.Values) {
+		var categories []string
+		for _, value := range term.Values {
+			categories = append(categories, strings.ToLower(value.Text))
+		}
+		if term.Negated {
+			c.exclude().Categories = append(c.exclude().Categories, categories...)
+		} else {
+			c.filters.Categories = append(c.filters.Categories, categories...)
+		}
+		return nil
+	}
+
+	ids, err := c.termIDs(QueryNameStatuses, term)
+	if err != nil {
+		return err
+	}
+	if term.Negated {
+		c.exclude().StatusIDs = append(c.exclude().StatusIDs, ids...)
+	} else {
+		c.filters.StatusIDs = append(c.filters.StatusIDs, ids...)
+	}
+	return nil
+}
+
+func (c *queryCompiler) named(field string, term QueryTerm) error {
+	none, err := isNone(term)
+	if err != nil {
+		return err
+	}
+	if none {
+		return c.namedNone(field, term)
+	}
+
+	ids, err := c.termIDs(queryFieldNameKinds[field], term)
+	if err != nil {
+		return err
+	}
+
+	if term.Negated {
+		ex := c.exclude()
+		switch field {
+		case "label":
+			ex.LabelIDs = append(ex.LabelIDs, ids...)
+		case "team":
+			ex.TeamIDs = append(ex.TeamIDs, ids...)
+		case "sprint":
+			ex.SprintIDs = append(ex.SprintIDs, ids...)
+		case "epic":
+			ex.EpicIDs = append(ex.EpicIDs, ids...)
+		}
+		return nil
+	}
+
+	switch field {
+	case "label":
+		c.filters.LabelIDs = append(c.filters.LabelIDs, ids...)
+	case "team":
+		c.filters.TeamIDs = append(c.filters.TeamIDs, ids...)
+	case "sprint":
+		c.filters.SprintIDs = append(c.filters.SprintIDs, ids...)
+	case "epic":
+		// Stories filter on a single epic.
+		if c.filters.Epic != nil {
+			return queryErrorf(term.Pos, "epic can only be used once")
+		}
+		if len(ids) > 1 {
+			return queryErrorf(term.Pos, "epic matches more than one epic; use a single epic name or id")
+		}
+		c.filters.Epic = &ids[0]
+	}
+	return nil
+}
+
+func (c *queryCompiler) namedNone(field string, term QueryTerm) error {
+	switch {
+	case field == "sprint" && term.Negated:
+		c.exclude().NoSprint = true
+	case field == "sprint":
+		noSprint := true
+		c.filters.HasNoSprint = &noSprint
+	case field == "epic" && term.Negated:
+		c.exclude().NoEpic = true
+	case field == "epic":
+		noEpic := true
+		c.filters.HasNoEpic = &noEpic
+	default:
+		return queryErrorf(term.Pos, "%s cannot be none", field)
+	}
+	return nil
+}
+
+func (c *queryCompiler) estimate(term QueryTerm) error {
+	var estimates []int16
+	for _, value := range term.Values {
+		estimate, err := strconv.ParseInt(value.Text, 10, 16)
+		if err != nil || estimate < 0 {
+			return queryErrorf(value.Pos, "estimate %q is not a whole number", value.Text)
+		}
+		estimates = append(estimates, int16(estimate))
+	}
+	if term.Negated {
+		c.exclude().EstimateValues = append(c.exclude().EstimateValues, estimates...)
+	} else {
+		c.filters.EstimateValues = append(c.filters.EstimateValues, estimates...)
+	}
+	return nil
+}
+
+func (c *queryCompiler) is(term QueryTerm) error {
+	for _, value := range term.Values {
+		state := strings.ToLower(value.Text)
+		switch {
+		case state == "blocked" && term.Negated:
+			c.exclude().Blocked = true
+		case state == "blocked":
+			blocked := true
+			c.filters.HasBlockedBy = &blocked
+		case state == "breached":
+			breached := !term.Negated
+			c.filters.SLABreached = &breached
+		case (state == "archived" || state == "deleted") && term.Negated:
+			return queryErrorf(term.Pos, "%s stories are already left out unless is:%s is given", state, state)
+		case state == "archived":
+			archived := true
+			c.filters.IncludeArchived = &archived
+		case state == "deleted":
+			deleted := true
+			c.filters.IncludeDeleted = &deleted
+		default:
+			return queryErrorf(value.Pos, "unknown state %q; use blocked, breached, archived or deleted", value.Text)
+		}
+	}
+	return nil
+}
+
+func (c *queryCompiler) date(field string, term QueryTerm) error {
+	if term.Negated {
+		return queryErrorf(term.Pos, "%s cannot be negated; use < or > instead", field)
+	}
+	if len(term.Values) > 1 {
+		return queryErrorf(term.Values[1].Pos, "%s takes a single date or range", field)
+	}
+	if c.dates[field] {
+		return queryErrorf(term.Pos, "%s can only be used once", field)
+	}
+	c.dates[field] = true
+
+	after, before, err := parseQueryDateRange(term.Values[0], c.now)
+	if err != nil {
+		return err
+	}
+
+	switch field {
+	case "created":
+		c.filters.CreatedAfter, c.filters.CreatedBefore = after, before
+	case "updated":
+		c.filters.UpdatedAfter, c.filters.UpdatedBefore = after, before
+	case "start":
+		c.filters.StartDateAfter, c.filters.StartDateBefore = after, before
+	case "due":
+		c.filters.DeadlineAfter, c.filters.DeadlineBefore = after, before
+	case "completed":
+		c.filters.CompletedAfter, c.filters.CompletedBefore = after, before
+	}
+	return nil
+}
+
+// parseQueryDateRange turns a date value into bounds. Dates are YYYY-MM-DD,
+// today, yesterday, tomorrow, or relative: 7d is seven days ago and +7d seven
+// days from now (h, d, w, m and y are supported). Values take an operator
+// (>, >=, <, <=) or form a range a..b with either end left open. A bare day
+// matches that day and a bare relative value the time between it and now.
+func parseQueryDateRange(value QueryValue, now time.Time) (*time.Time, *time.Time, error) {
+	text := strings.ToLower(value.Text)
+
+	if from, to, ok := strings.Cut(text, ".."); ok {
+		if from == "" && to == "" {
+			return nil, nil, queryErrorf(value.Pos, "date range needs at least one end")
+		}
+		var after, before *time.Time
+		if from != "" {
+			start, _, _, err := parseQueryDate(from, value.Pos, now)
+			if err != nil {
+				return nil, nil, err
+			}
+			after = &start
+		}
+		if to != "" {
+			_, end, _, err := parseQueryDate(to, value.Pos+len([]rune(from))+2, now)
+			if err != nil {
+				return nil, nil, err
+			}
+			before = ptrTime(inclusiveBefore(end))
+		}
+		if after != nil && before != nil && after.After(*before) {
+			return nil, nil, queryErrorf(value.Pos, "date range ends before it starts")
+		}
+		return after, before, nil
+	}
+
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		rest, ok := strings.CutPrefix(text, op)
+		if !ok {
+			continue
+		}
+		start, end, _, err := parseQueryDate(rest, value.Pos+len(op), now)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch op {
+		case ">":
+			return &end, nil, nil
+		case ">=":
+			return &start, nil, nil
+		case "<":
+			return nil, ptrTime(inclusiveBefore(start)), nil
+		default:
+			return nil, ptrTime(inclusiveBefore(end)), nil
+		}
+	}
+
+	start, end, relative, err := parseQueryDate(text, value.Pos, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if relative {
+		if start.After(now) {
+			return &now, &start, nil
+		}
+		return &start, &now, nil
+	}
+	return &start, ptrTime(inclusiveBefore(end)), nil
+}
+
+// inclusiveBefore turns the exclusive end of a span into the last instant
+// inside it. The *Before filters compare with <=, so passing the end itself
+// would match a timestamp at midnight of the next day and, on date columns
+// such as start_date and end_date, the whole next day.
+func inclusiveBefore(end time.Time) time.Time {
+	return end.Add(-time.Microsecond)
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
+// parseQueryDate returns the span a date value covers: a whole day for
+// calendar dates and a single instant for relative values.
+func parseQueryDate(text string, pos int, now time.Time) (time.Time, time.Time, bool, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	day := func(t time.Time) (time.Time, time.Time, bool, error) {
+		return t, t.AddDate(0, 0, 1), false, nil
+	}
+
+	switch text {
+	case "today":
+		return day(today)
+	case "yesterday":
+		return day(today.AddDate(0, 0, -1))
+	case "tomorrow":
+		return day(today.AddDate(0, 0, 1))
+	}
+
+	if match := queryRelativeDate.FindStringSubmatch(text); match != nil {
+		n, err := strconv.Atoi(match[2])
+		if err != nil || n > 10000 {
+			return time.Time{}, time.Time{}, false, queryErrorf(pos, "relative date %q is too large", text)
+		}
+		if match[1] != "+" {
+			n = -n
+		}
+		var t time.Time
+		switch match[3] {
+		case "h":
+			t = now.Add(time.Duration(n) * time.Hour)
+		case "d":
+			t = now.AddDate(0, 0, n)
+		case "w":
+			t = now.AddDate(0, 0, 7*n)
+		case "m":
+			t = now.AddDate(0, n, 0)
+		case "y":
+			t = now.AddDate(n, 0, 0)
+		}
+		return t, t, true, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", text, now.Location()); err == nil {
+		return day(t)
+	}
+	return time.Time{}, time.Time{}, false, queryErrorf(pos, "invalid date %q; use YYYY-MM-DD, today or a relative value such as 7d", text)
+}
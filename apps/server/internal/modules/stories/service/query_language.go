@@ -0,0 +1,224 @@
+package stories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidQueryExpression is wrapped by every QueryError.
+var ErrInvalidQueryExpression = errors.New("invalid query expression")
+
+// QueryError describes a problem in a query expression. Position is the
+// 1-based character offset where the problem starts.
+type QueryError struct {
+	Position int
+	Message  string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
+
+func (e *QueryError) Unwrap() error {
+	return ErrInvalidQueryExpression
+}
+
+func queryErrorf(pos int, format string, args ...any) *QueryError {
+	return &QueryError{Position: pos + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+// QueryTerm is one whitespace separated term of a query expression. Terms
+// without a field are free text.
+type QueryTerm struct {
+	Field   string
+	Values  []QueryValue
+	Negated bool
+	Pos     int
+}
+
+// QueryValue is a single value of a term. Pos is the 0-based rune offset of
+// the value in the expression.
+type QueryValue struct {
+	Text   string
+	Quoted bool
+	Pos    int
+}
+
+// ParseQuery splits a query expression such as
+//
+//	assignee:me priority:urgent,high label:"needs review" -sprint:none login
+//
+// into terms. A term is either field:value[,value...] or free text, and a
+// leading "-" negates it. Values containing spaces or commas must be quoted;
+// inside quotes \" and \\ escape a quote and a backslash.
+func ParseQuery(expression string) ([]QueryTerm, error) {
+	p := queryParser{input: []rune(expression)}
+	var terms []QueryTerm
+	for {
+		p.skipSpace()
+		if p.done() {
+			return terms, nil
+		}
+		term, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+}
+
+type queryParser struct {
+	input []rune
+	pos   int
+}
+
+func (p *queryParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *queryParser) peek() rune {
+	return p.input[p.pos]
+}
+
+func (p *queryParser) skipSpace() {
+	for !p.done() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *queryParser) atTermEnd() bool {
+	return p.done() || unicode.IsSpace(p.peek())
+}
+
+func (p *queryParser) term() (QueryTerm, error) {
+	term := QueryTerm{Pos: p.pos}
+
+	// A lone "-" is ordinary text, e.g. "login - signup".
+	if p.peek() == '-' && p.pos+1 < len(p.input) && !unicode.IsSpace(p.input[p.pos+1]) {
+		term.Negated = true
+		p.pos++
+	}
+
+	if field, ok := p.field(); ok {
+		term.Field = strings.ToLower(field)
+		values, err := p.values(term.Field)
+		if err != nil {
+			return QueryTerm{}, err
+		}
+		term.Values = values
+		return term, nil
+	}
+
+	value, err := p.text()
+	if err != nil {
+		return QueryTerm{}, err
+	}
+	term.Values = []QueryValue{value}
+	return term, nil
+}
+
+// field reads a field name followed by ":" and leaves the parser after the
+// colon. Nothing is consumed when the next word is not a field.
+func (p *queryParser) field() (string, bool) {
+	start := p.pos
+	end := start
+	for end < len(p.input) && (unicode.IsLetter(p.input[end]) || p.input[end] == '_') {
+		end++
+	}
+	if end == start || end >= len(p.input) || p.input[end] != ':' {
+		return "", false
+	}
+	p.pos = end + 1
+	return string(p.input[start:end]), true
+}
+
+func (p *queryParser) values(field string) ([]QueryValue, error) {
+	var values []QueryValue
+	for {
+		if p.atTermEnd() {
+			if len(values) == 0 {
+				return nil, queryErrorf(p.pos, "missing value for %q", field)
+			}
+			return nil, queryErrorf(p.pos, "missing value after ','")
+		}
+
+		var value QueryValue
+		var err error
+		if p.peek() == '"' {
+			value, err = p.quoted()
+		} else {
+			value, err = p.bare(true)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if value.Text == "" && !value.Quoted {
+			return nil, queryErrorf(p.pos, "missing value for %q", field)
+		}
+		values = append(values, value)
+
+		if p.atTermEnd() {
+			return values, nil
+		}
+		if p.peek() != ',' {
+			return nil, queryErrorf(p.pos, "unexpected %q after value", p.peek())
+		}
+		p.pos++
+	}
+}
+
+// text reads a free text term, which is a quoted phrase or a single word.
+func (p *queryParser) text() (QueryValue, error) {
+	if p.peek() == '"' {
+		value, err := p.quoted()
+		if err != nil {
+			return QueryValue{}, err
+		}
+		if !p.atTermEnd() {
+			return QueryValue{}, queryErrorf(p.pos, "unexpected %q after closing quote", p.peek())
+		}
+		return value, nil
+	}
+	return p.bare(false)
+}
+
+// bare reads an unquoted value up to whitespace, or a comma when stopAtComma
+// is set.
+func (p *queryParser) bare(stopAtComma bool) (QueryValue, error) {
+	start := p.pos
+	for !p.atTermEnd() {
+		r := p.peek()
+		if stopAtComma && r == ',' {
+			break
+		}
+		if r == '"' {
+			return QueryValue{}, queryErrorf(p.pos, "unexpected quote; quote the whole value instead")
+		}
+		p.pos++
+	}
+	return QueryValue{Text: string(p.input[start:p.pos]), Pos: start}, nil
+}
+
+func (p *queryParser) quoted() (QueryValue, error) {
+	start := p.pos
+	p.pos++ // opening quote
+
+	var b strings.Builder
+	for !p.done() {
+		r := p.peek()
+		switch {
+		case r == '\\' && p.pos+1 < len(p.input) && (p.input[p.pos+1] == '"' || p.input[p.pos+1] == '\\'):
+			b.WriteRune(p.input[p.pos+1])
+			p.pos += 2
+		case r == '"':
+			p.pos++
+			return QueryValue{Text: b.String(), Quoted: true, Pos: start}, nil
+		default:
+			b.WriteRune(r)
+			p.pos++
+		}
+	}
+	return QueryValue{}, queryErrorf(start, "unterminated quote")
+}
@@ -0,0 +1,250 @@
+package stories
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseQuery(t *testing.T) {
+	terms, err := ParseQuery(`assignee:me priority:urgent,high label:"needs \"review\"" -sprint:none login - page`)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+
+	want := []QueryTerm{
+		{Field: "assignee", Values: []QueryValue{{Text: "me", Pos: 9}}, Pos: 0},
+		{Field: "priority", Values: []QueryValue{{Text: "urgent", Pos: 21}, {Text: "high", Pos: 28}}, Pos: 12},
+		{Field: "label", Values: []QueryValue{{Text: `needs "review"`, Quoted: true, Pos: 39}}, Pos: 33},
+		{Field: "sprint", Values: []QueryValue{{Text: "none", Pos: 66}}, Negated: true, Pos: 58},
+		{Values: []QueryValue{{Text: "login", Pos: 71}}, Pos: 71},
+		{Values: []QueryValue{{Text: "-", Pos: 77}}, Pos: 77},
+		{Values: []QueryValue{{Text: "page", Pos: 79}}, Pos: 79},
+	}
+	if len(terms) != len(want) {
+		t.Fatalf("got %d terms, want %d: %+v", len(terms), len(want), terms)
+	}
+	for i := range want {
+		got := terms[i]
+		if got.Field != want[i].Field || got.Negated != want[i].Negated || got.Pos != want[i].Pos || !slices.Equal(got.Values, want[i].Values) {
+			t.Errorf("term %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		query    string
+		position int
+	}{
+		{query: `status:`, position: 8},
+		{query: `priority:high,`, position: 15},
+		{query: `label:"bug`, position: 7},
+		{query: `label:"bug"x`, position: 12},
+		{query: `title:fo"o`, position: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseQuery(tt.query)
+			var queryErr *QueryError
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("ParseQuery() error = %v, want a QueryError", err)
+			}
+			if queryErr.Position != tt.position {
+				t.Errorf("position = %d, want %d (%v)", queryErr.Position, tt.position, err)
+			}
+			if !errors.Is(err, ErrInvalidQueryExpression) {
+				t.Errorf("error should wrap ErrInvalidQueryExpression")
+			}
+		})
+	}
+}
+
+func compileTestQuery(t *testing.T, query string, userID uuid.UUID, now time.Time, resolved map[string]map[string][]uuid.UUID) (CoreStoryFilters, error) {
+	t.Helper()
+	terms, err := ParseQuery(query)
+	if err != nil {
+		t.Fatalf("ParseQuery(%q) error = %v", query, err)
+	}
+	if _, err := queryNameLookups(terms); err != nil {
+		return CoreStoryFilters{}, err
+	}
+	return compileQueryTerms(terms, userID, now, resolved)
+}
+
+func TestCompileQuery(t *testing.T) {
+	userID := uuid.New()
+	bugID := uuid.New()
+	aliceID := uuid.New()
+	now := time.Date(2025, 3, 14, 15, 0, 0, 0, time.UTC)
+	resolved := map[string]map[string][]uuid.UUID{
+		QueryNameLabels: {"bug": {bugID}},
+		QueryNameUsers:  {"alice": {aliceID}},
+	}
+
+	filters, err := compileTestQuery(t, `assignee:me,@alice priority:urgent,high status:started label:"bug" updated:>7d -sprint:none flaky login`, userID, now, resolved)
+	if err != nil {
+		t.Fatalf("compile error = %v", err)
+	}
+
+	if !slices.Equal(filters.AssigneeIDs, []uuid.UUID{userID, aliceID}) {
+		t.Errorf("assignees = %v", filters.AssigneeIDs)
+	}
+	if !slices.Equal(filters.Priorities, []string{"Urgent", "High"}) {
+		t.Errorf("priorities = %v", filters.Priorities)
+	}
+	if !slices.Equal(filters.Categories, []string{"started"}) {
+		t.Errorf("categories = %v", filters.Categories)
+	}
+	if !slices.Equal(filters.LabelIDs, []uuid.UUID{bugID}) {
+		t.Errorf("labels = %v", filters.LabelIDs)
+	}
+	if filters.UpdatedAfter == nil || !filters.UpdatedAfter.Equal(now.AddDate(0, 0, -7)) || filters.UpdatedBefore != nil {
+		t.Errorf("updated range = %v..%v", filters.UpdatedAfter, filters.UpdatedBefore)
+	}
+	if filters.Exclude == nil || !filters.Exclude.NoSprint {
+		t.Errorf("exclusions = %+v, want stories without a sprint excluded", filters.Exclude)
+	}
+	if filters.TitleContains == nil || *filters.TitleContains != "flaky login" {
+		t.Errorf("text = %v", filters.TitleContains)
+	}
+}
+
+func TestCompileQueryNegation(t *testing.T) {
+	userID := uuid.New()
+	filters, err := compileTestQuery(t, `-assignee:me -priority:low -status:completed,cancelled -is:blocked -assignee:none`, userID, time.Now(), nil)
+	if err != nil {
+		t.Fatalf("compile error = %v", err)
+	}
+
+	ex := filters.Exclude
+	if ex == nil {
+		t.Fatal("expected exclusions")
+	}
+	if !slices.Equal(ex.AssigneeIDs, []uuid.UUID{userID}) || !slices.Equal(ex.Priorities, []string{"Low"}) ||
+		!slices.Equal(ex.Categories, []string{"completed", "cancelled"}) || !ex.Blocked || !ex.Unassigned {
+		t.Errorf("exclusions = %+v", ex)
+	}
+	if len(filters.AssigneeIDs) != 0 || len(filters.Categories) != 0 {
+		t.Errorf("negated terms should not add positive filters: %+v", filters)
+	}
+}
+
+func TestCompileQueryBreached(t *testing.T) {
+	for query, want := range map[string]bool{"is:breached": true, "-is:breached": false} {
+		filters, err := compileTestQuery(t, query, uuid.New(), time.Now(), nil)
+		if err != nil {
+			t.Fatalf("%s: compile error = %v", query, err)
+		}
+		if filters.SLABreached == nil || *filters.SLABreached != want {
+			t.Errorf("%s: SLA breached = %v, want %v", query, filters.SLABreached, want)
+		}
+	}
+}
+
+func TestCompileQueryDates(t *testing.T) {
+	now := time.Date(2025, 3, 14, 15, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+	// Upper bounds are compared with <=, so they stop just short of the next day.
+	endOf := func(d int) time.Time { return day(d + 1).Add(-time.Microsecond) }
+
+	tests := []struct {
+		query  string
+		after  *time.Time
+		before *time.Time
+	}{
+		{query: "created:2025-03-01", after: ptrTo(day(1)), before: ptrTo(endOf(1))},
+		{query: "created:>2025-03-01", after: ptrTo(day(2))},
+		{query: "created:>=2025-03-01", after: ptrTo(day(1))},
+		{query: "created:<2025-03-01", before: ptrTo(endOf(0))},
+		{query: "created:<=2025-03-01", before: ptrTo(endOf(1))},
+		{query: "created:2025-03-01..2025-03-05", after: ptrTo(day(1)), before: ptrTo(endOf(5))},
+		{query: "created:..today", before: ptrTo(endOf(14))},
+		{query: "created:2w", after: ptrTo(now.AddDate(0, 0, -14)), before: ptrTo(now)},
+		{query: "created:+3d", after: ptrTo(now), before: ptrTo(now.AddDate(0, 0, 3))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			filters, err := compileTestQuery(t, tt.query, uuid.New(), now, nil)
+			if err != nil {
+				t.Fatalf("compile error = %v", err)
+			}
+			if !equalTimePtr(filters.CreatedAfter, tt.after) || !equalTimePtr(filters.CreatedBefore, tt.before) {
+				t.Errorf("range = %v..%v, want %v..%v", filters.CreatedAfter, filters.CreatedBefore, tt.after, tt.before)
+			}
+		})
+	}
+}
+
+func TestCompileQueryErrors(t *testing.T) {
+	tests := []struct {
+		query    string
+		position int
+	}{
+		{query: "owner:me", position: 1},
+		{query: "priority:high,critical", position: 15},
+		{query: "assignee:me label:missing", position: 19},
+		{query: "sprint:none,current", position: 8},
+		{query: "-login", position: 1},
+		{query: "-updated:7d", position: 1},
+		{query: "updated:yesterweek", position: 9},
+		{query: "updated:7d updated:3d", position: 12},
+		{query: "created:2025-03-05..2025-03-01", position: 9},
+		{query: "is:closed", position: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := compileTestQuery(t, tt.query, uuid.New(), time.Now(), nil)
+			var queryErr *QueryError
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("compile error = %v, want a QueryError", err)
+			}
+			if queryErr.Position != tt.position {
+				t.Errorf("position = %d, want %d (%v)", queryErr.Position, tt.position, err)
+			}
+		})
+	}
+}
+
+func TestQueryNameLookups(t *testing.T) {
+	terms, err := ParseQuery(`assignee:me,@Alice status:started status:started,"In Review" label:none team:ENG,eng`)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+
+	lookups, err := queryNameLookups(terms)
+	if err != nil {
+		t.Fatalf("queryNameLookups() error = %v", err)
+	}
+
+	if !slices.Equal(lookups[QueryNameUsers], []string{"alice"}) {
+		t.Errorf("users = %v", lookups[QueryNameUsers])
+	}
+	// Mixed with a status name, the category is resolved to statuses too.
+	if !slices.Equal(lookups[QueryNameStatuses], []string{"started", "in review"}) {
+		t.Errorf("statuses = %v", lookups[QueryNameStatuses])
+	}
+	if _, ok := lookups[QueryNameLabels]; ok {
+		t.Errorf("none should not be looked up")
+	}
+	if !slices.Equal(lookups[QueryNameTeams], []string{"eng"}) {
+		t.Errorf("teams = %v", lookups[QueryNameTeams])
+	}
+}
+
+func ptrTo(t time.Time) *time.Time {
+	return &t
+}
+
+func equalTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
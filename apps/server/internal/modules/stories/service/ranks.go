@@ -0,0 +1,248 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/complexus-tech/projects-api/pkg/tasks"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// rankDigits are the digits of rank keys in byte order. A key is the
+// fractional part of a base 36 number and never ends in the lowest digit,
+// so there is always room for a key before it.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// maxRankLength is the key length past which a workspace's ranks are
+// respaced in the background.
+const maxRankLength = 24
+
+// rankPrependWidth is the key length at which stories added to the start
+// of the list are spaced.
+const rankPrependWidth = 8
+
+var (
+	ErrRankNeighboursRequired = errors.New("a story to move before or after is required")
+	ErrInvalidRankNeighbours  = errors.New("the stories to move between are not next to each other in rank order")
+	ErrRanksRebalancing       = errors.New("story ranks are being respaced, try again shortly")
+)
+
+// MoveStory ranks a story between beforeID, the story that should come
+// directly before it, and afterID, the story that should come directly
+// after it. Either may be nil to move the story to the start or end of the
+// list. Only the moved story's rank changes, and the new rank is returned.
+func (s *Service) MoveStory(ctx context.Context, storyID, workspaceID uuid.UUID, beforeID, afterID *uuid.UUID) (string, error) {
+	s.log.Info(ctx, "business.core.stories.MoveStory")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.MoveStory")
+	defer span.End()
+
+	if beforeID == nil && afterID == nil {
+		return "", ErrRankNeighboursRequired
+	}
+	if beforeID != nil && *beforeID == storyID || afterID != nil && *afterID == storyID {
+		return "", ErrInvalidRankNeighbours
+	}
+
+	rank, err := s.rankBetween(ctx, workspaceID, beforeID, afterID)
+	if errors.Is(err, errRankRebalanceNeeded) {
+		// Stories without ranks or sharing a rank cannot be moved between
+		// until the workspace is respaced, which runs in the background
+		// since it locks every story in the workspace. The move is waiting on
+		// it, so it runs right away.
+		s.enqueueRankRebalance(ctx, workspaceID, asynq.ProcessIn(0))
+		err = ErrRanksRebalancing
+	}
+	if err != nil {
+		span.RecordError(err)
+		return "", err
+	}
+
+	if err := s.repo.UpdateStoryRank(ctx, storyID, workspaceID, rank); err != nil {
+		span.RecordError(err)
+		return "", err
+	}
+
+	if len(rank) > maxRankLength {
+		s.enqueueRankRebalance(ctx, workspaceID)
+	}
+
+	span.AddEvent("story moved.", trace.WithAttributes(
+		attribute.String("story.id", storyID.String()),
+		attribute.String("story.rank", rank),
+	))
+	return rank, nil
+}
+
+// enqueueRankRebalance respaces a workspace's story ranks in the background.
+func (s *Service) enqueueRankRebalance(ctx context.Context, workspaceID uuid.UUID, opts ...asynq.Option) {
+	if s.tasksService == nil {
+		return
+	}
+	if _, err := s.tasksService.EnqueueStoryRankRebalance(tasks.StoryRankRebalancePayload{
+		WorkspaceID: workspaceID,
+	}, opts...); err != nil {
+		s.log.Error(ctx, "failed to enqueue story rank rebalance", "workspace_id", workspaceID, "error", err)
+	}
+}
+
+// errRankRebalanceNeeded is returned by rankBetween when the neighbours
+// have no ranks or no room between them.
+var errRankRebalanceNeeded = errors.New("story ranks need rebalancing")
+
+// rankBetween returns a key between the ranks of beforeID and afterID. With
+// only one of them, the key goes between it and the next ranked story in
+// the workspace, so stories outside the caller's view keep their place.
+func (s *Service) rankBetween(ctx context.Context, workspaceID uuid.UUID, beforeID, afterID *uuid.UUID) (string, error) {
+	ids := []uuid.UUID{}
+	for _, id := range []*uuid.UUID{beforeID, afterID} {
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	ranks, err := s.repo.GetStoryRanks(ctx, workspaceID, ids)
+	if err != nil {
+		return "", err
+	}
+
+	var before, after string
+	for _, id := range ids {
+		rank, ok := ranks[id]
+		if !ok {
+			return "", ErrNotFound
+		}
+		if rank == nil {
+			return "", errRankRebalanceNeeded
+		}
+	}
+
+	switch {
+	case beforeID != nil && afterID != nil:
+		before, after = *ranks[*beforeID], *ranks[*afterID]
+	case beforeID != nil:
+		before = *ranks[*beforeID]
+		next, err := s.repo.GetNeighbourRank(ctx, workspaceID, before, true)
+		if err != nil {
+			return "", err
+		}
+		if next != nil {
+			after = *next
+		}
+	default:
+		after = *ranks[*afterID]
+		previous, err := s.repo.GetNeighbourRank(ctx, workspaceID, after, false)
+		if err != nil {
+			return "", err
+		}
+		if previous != nil {
+			before = *previous
+		}
+	}
+
+	if after != "" && before >= after {
+		if beforeID != nil && afterID != nil && before > after {
+			return "", ErrInvalidRankNeighbours
+		}
+		return "", errRankRebalanceNeeded
+	}
+	return rankKeyBetween(before, after), nil
+}
+
+// rankKeyBetween returns a key that sorts after a and before b. An empty a
+// is the start of the list and an empty b is its end.
+func rankKeyBetween(a, b string) string {
+	if b != "" {
+		n := 0
+		for n < len(b) && rankDigitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + rankKeyBetween(rest, b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(rankDigits, a[0])
+	}
+	digitB := len(rankDigits)
+	if b != "" {
+		digitB = strings.IndexByte(rankDigits, b[0])
+	}
+	if digitB-digitA > 1 {
+		return string(rankDigits[(digitA+digitB+1)/2])
+	}
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if a != "" {
+		rest = a[1:]
+	}
+	return string(rankDigits[digitA]) + rankKeyBetween(rest, "")
+}
+
+// RankKeyBefore returns a key that sorts before first, for a story added to
+// the start of the list. It steps by the smallest amount at rankPrependWidth
+// digits rather than halving the space that is left, so keys stay short
+// however many stories are added. An empty first is an empty list.
+func RankKeyBefore(first string) string {
+	if first == "" {
+		return rankKeyBetween("", "")
+	}
+	digits := []byte(first)
+	for len(digits) < rankPrependWidth {
+		digits = append(digits, rankDigits[0])
+	}
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := strings.IndexByte(rankDigits, digits[i])
+		if digit > 0 {
+			digits[i] = rankDigits[digit-1]
+			if key := strings.TrimRight(string(digits), rankDigits[:1]); key != "" {
+				return key
+			}
+			break
+		}
+		digits[i] = rankDigits[len(rankDigits)-1]
+	}
+	return rankKeyBetween("", first)
+}
+
+// rankDigitAt returns the digit of key at i, reading past its end as zeros.
+func rankDigitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return rankDigits[0]
+}
+
+// SpacedRankKeys returns count keys spread evenly across the key space, all
+// of the same length before trailing zeros are trimmed.
+func SpacedRankKeys(count int) []string {
+	base := int64(len(rankDigits))
+	width, space := 1, base
+	for space <= int64(count)*2 {
+		width++
+		space *= base
+	}
+
+	keys := make([]string, count)
+	for i := range keys {
+		value := space * int64(i+1) / int64(count+1)
+		digits := make([]byte, width)
+		for d := width - 1; d >= 0; d-- {
+			digits[d] = rankDigits[value%base]
+			value /= base
+		}
+		keys[i] = strings.TrimRight(string(digits), rankDigits[:1])
+	}
+	return keys
+}
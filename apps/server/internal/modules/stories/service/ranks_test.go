@@ -0,0 +1,98 @@
+package stories
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRankKeyBetween(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"", ""},
+		{"", "1"},
+		{"", "01"},
+		{"i", ""},
+		{"z", ""},
+		{"zz", ""},
+		{"1", "2"},
+		{"1", "11"},
+		{"0001", "0002"},
+		{"a", "b"},
+		{"ay", "b"},
+		{"a", "a01"},
+	}
+	for _, tt := range tests {
+		key := rankKeyBetween(tt.a, tt.b)
+		if key <= tt.a || tt.b != "" && key >= tt.b {
+			t.Errorf("rankKeyBetween(%q, %q) = %q, not between them", tt.a, tt.b, key)
+		}
+		if strings.HasSuffix(key, "0") {
+			t.Errorf("rankKeyBetween(%q, %q) = %q, ends in 0", tt.a, tt.b, key)
+		}
+	}
+}
+
+func TestRankKeyBetweenRepeatedMoves(t *testing.T) {
+	// Moving stories to the front over and over grows keys slowly.
+	a, b := "", "i"
+	for range 200 {
+		key := rankKeyBetween(a, b)
+		if key <= a || key >= b {
+			t.Fatalf("rankKeyBetween(%q, %q) = %q, not between them", a, b, key)
+		}
+		b = key
+	}
+	if len(b) > 100 {
+		t.Errorf("key length after 200 moves = %d", len(b))
+	}
+}
+
+func TestSpacedRankKeys(t *testing.T) {
+	for _, count := range []int{1, 2, 17, 36, 1000} {
+		keys := SpacedRankKeys(count)
+		if len(keys) != count {
+			t.Fatalf("SpacedRankKeys(%d) returned %d keys", count, len(keys))
+		}
+		for i, key := range keys {
+			if key == "" || strings.HasSuffix(key, "0") {
+				t.Errorf("SpacedRankKeys(%d)[%d] = %q", count, i, key)
+			}
+			if i > 0 && keys[i-1] >= key {
+				t.Errorf("SpacedRankKeys(%d) out of order at %d: %q >= %q", count, i, keys[i-1], key)
+			}
+		}
+	}
+}
+
+func TestRankKeyBefore(t *testing.T) {
+	tests := []struct {
+		first string
+		want  string
+	}{
+		{"", "i"},
+		{"i", "hzzzzzzz"},
+		{"i0000001", "i"},
+		{"i1", "i0zzzzzz"},
+		{"10000000", "0zzzzzzz"},
+		{"00000001", "00000000i"},
+	}
+	for _, tt := range tests {
+		if got := RankKeyBefore(tt.first); got != tt.want {
+			t.Errorf("RankKeyBefore(%q) = %q, want %q", tt.first, got, tt.want)
+		}
+	}
+
+	// Adding stories one before another keeps keys short.
+	key := SpacedRankKeys(1000)[0]
+	for i := 0; i < 100000; i++ {
+		next := RankKeyBefore(key)
+		if next >= key || strings.HasSuffix(next, "0") {
+			t.Fatalf("RankKeyBefore(%q) = %q, not before it", key, next)
+		}
+		key = next
+	}
+	if len(key) > maxRankLength {
+		t.Fatalf("key grew to %d digits after 100000 additions: %q", len(key), key)
+	}
+}
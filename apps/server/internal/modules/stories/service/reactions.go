@@ -0,0 +1,132 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrCommentNotFound = errors.New("comment not found")
+	ErrInvalidReaction = errors.New("reaction must be an emoji or an emoji shortcode")
+)
+
+// maxReactionLength is the longest reaction in bytes, which fits emoji
+// sequences such as flags and skin tones.
+const maxReactionLength = 32
+
+// ToggleReaction adds the user's emoji reaction to a comment, or removes it
+// when they already reacted with that emoji, and returns the comment's
+// reactions afterwards.
+func (s *Service) ToggleReaction(ctx context.Context, workspaceID, commentID, userID uuid.UUID, emoji string) ([]comments.CoreReaction, error) {
+	s.log.Info(ctx, "business.core.stories.ToggleReaction")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.ToggleReaction")
+	defer span.End()
+
+	emoji = strings.TrimSpace(emoji)
+	if !validReaction(emoji) {
+		return nil, ErrInvalidReaction
+	}
+
+	comment, err := s.repo.GetComment(ctx, commentID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	story, err := s.repo.Get(ctx, comment.StoryID, workspaceID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		span.RecordError(err)
+		return nil, err
+	}
+
+	event := events.Event{
+		Type: events.CommentReacted,
+		Payload: events.CommentReactedPayload{
+			CommentID:       comment.ID,
+			CommentAuthorID: comment.UserID,
+			StoryID:         story.ID,
+			StoryTitle:      story.Title,
+			WorkspaceID:     workspaceID,
+			Emoji:           emoji,
+		},
+		Timestamp: time.Now(),
+		ActorID:   userID,
+	}
+
+	added, err := s.repo.ToggleReaction(ctx, commentID, userID, emoji, event)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	reactions, err := s.repo.GetCommentReactions(ctx, commentID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.AddEvent("reaction toggled.", trace.WithAttributes(
+		attribute.String("comment.id", commentID.String()),
+		attribute.Bool("reaction.added", added),
+	))
+	return reactions, nil
+}
+
+// CommentReactions returns the reactions on a comment.
+func (s *Service) CommentReactions(ctx context.Context, commentID uuid.UUID) ([]comments.CoreReaction, error) {
+	s.log.Info(ctx, "business.core.stories.CommentReactions")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.CommentReactions")
+	defer span.End()
+
+	reactions, err := s.repo.GetCommentReactions(ctx, commentID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return reactions, nil
+}
+
+// validReaction accepts an emoji such as "👍" or a shortcode such as ":+1:".
+// Plain words are rejected so reactions cannot be used as short comments.
+func validReaction(emoji string) bool {
+	if emoji == "" || len(emoji) > maxReactionLength || !utf8.ValidString(emoji) {
+		return false
+	}
+
+	if len(emoji) > 2 && strings.HasPrefix(emoji, ":") && strings.HasSuffix(emoji, ":") {
+		for _, r := range emoji[1 : len(emoji)-1] {
+			if !isShortcodeRune(r) {
+				return false
+			}
+		}
+		return true
+	}
+
+	hasSymbol := false
+	for _, r := range emoji {
+		switch {
+		case unicode.IsSpace(r), unicode.IsControl(r), r < utf8.RuneSelf && unicode.IsLetter(r):
+			return false
+		case r >= utf8.RuneSelf:
+			hasSymbol = true
+		}
+	}
+	return hasSymbol
+}
+
+func isShortcodeRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '+' || r == '-'
+}
@@ -0,0 +1,31 @@
+package stories
+
+import "testing"
+
+func TestValidReaction(t *testing.T) {
+	tests := []struct {
+		emoji string
+		valid bool
+	}{
+		{emoji: "👍", valid: true},
+		{emoji: "❤️", valid: true},
+		{emoji: "👍🏽", valid: true},
+		{emoji: "🇿🇼", valid: true},
+		{emoji: "1️⃣", valid: true},
+		{emoji: ":+1:", valid: true},
+		{emoji: ":white_check_mark:", valid: true},
+		{emoji: "", valid: false},
+		{emoji: "+1", valid: false},
+		{emoji: "lgtm", valid: false},
+		{emoji: "👍 nice", valid: false},
+		{emoji: ":Thumbs Up:", valid: false},
+		{emoji: "::", valid: false},
+		{emoji: "👍👍👍👍👍👍👍👍👍", valid: false},
+	}
+
+	for _, tt := range tests {
+		if got := validReaction(tt.emoji); got != tt.valid {
+			t.Errorf("validReaction(%q) = %v, want %v", tt.emoji, got, tt.valid)
+		}
+	}
+}
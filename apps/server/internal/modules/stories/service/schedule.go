@@ -0,0 +1,312 @@
+package stories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CoreTeamSchedule is a team's working calendar. Holidays are keyed by
+// their date in 2006-01-02 form.
+type CoreTeamSchedule struct {
+	CascadeDependencies bool
+	Holidays            map[string]bool
+}
+
+// CoreScheduleChange is a story moved forward because a story blocking it
+// now ends later.
+type CoreScheduleChange struct {
+	StoryID        uuid.UUID
+	SequenceID     int
+	Title          string
+	TeamCode       string
+	AssigneeID     *uuid.UUID
+	BlockerID      uuid.UUID
+	BlockerRef     string
+	BlockerEndDate time.Time
+	OldStartDate   *time.Time
+	OldEndDate     *time.Time
+	NewStartDate   *time.Time
+	NewEndDate     *time.Time
+}
+
+// PreviewSchedule returns the stories that would move if the story ended on
+// endDate, for teams that cascade dependencies. A nil endDate keeps the
+// story's current end date.
+func (s *Service) PreviewSchedule(ctx context.Context, storyID, workspaceID uuid.UUID, endDate *time.Time) ([]CoreScheduleChange, error) {
+	s.log.Info(ctx, "business.core.stories.PreviewSchedule")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.PreviewSchedule")
+	defer span.End()
+
+	story, err := s.repo.Get(ctx, storyID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if endDate == nil {
+		endDate = story.EndDate
+	}
+
+	changes, err := s.planSchedule(ctx, storyID, workspaceID, endDate)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.AddEvent("schedule previewed.", trace.WithAttributes(
+		attribute.String("story.id", storyID.String()),
+		attribute.Int("changes.count", len(changes)),
+	))
+	return changes, nil
+}
+
+// cascadeSchedule moves the dependents of a story whose end date changed in
+// one transaction, so either all of them move or none do, and records each
+// move as an activity with the reason for it.
+func (s *Service) cascadeSchedule(ctx context.Context, storyID, workspaceID, actorID uuid.UUID, endDate *time.Time) error {
+	changes, err := s.planSchedule(ctx, storyID, workspaceID, endDate)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+
+	now := time.Now()
+	outboxEvents := make([]events.Event, 0, len(changes))
+	activities := []CoreActivity{}
+	for _, change := range changes {
+		updates := map[string]any{"start_date": change.NewStartDate}
+		if change.NewEndDate != nil {
+			updates["end_date"] = change.NewEndDate
+		}
+		outboxEvents = append(outboxEvents, events.Event{
+			Type: events.StoryUpdated,
+			Payload: events.StoryUpdatedPayload{
+				StoryID:     change.StoryID,
+				WorkspaceID: workspaceID,
+				Updates:     updates,
+				AssigneeID:  change.AssigneeID,
+			},
+			Timestamp: now,
+			ActorID:   actorID,
+		})
+
+		reason := normalizeActivityReason(fmt.Sprintf("%s now ends on %s, so FortyOne moved this story to start after it.", change.BlockerRef, change.BlockerEndDate.Format("January 2, 2006")))
+		activity := func(field string, oldValue, newValue *time.Time) CoreActivity {
+			return CoreActivity{
+				StoryID:      change.StoryID,
+				Type:         "update",
+				Field:        field,
+				CurrentValue: s.formatValue(newValue),
+				OldValue:     oldValue,
+				NewValue:     newValue,
+				Reason:       reason,
+				UserID:       actorID,
+				WorkspaceID:  workspaceID,
+			}
+		}
+		activities = append(activities, activity("start_date", change.OldStartDate, change.NewStartDate))
+		if change.NewEndDate != nil {
+			activities = append(activities, activity("end_date", change.OldEndDate, change.NewEndDate))
+		}
+	}
+
+	if err := s.repo.RescheduleStories(ctx, workspaceID, changes, outboxEvents...); err != nil {
+		return err
+	}
+	if _, err := s.repo.RecordActivities(ctx, activities); err != nil {
+		s.log.Error(ctx, "failed to record schedule activities", "story_id", storyID, "error", err)
+	}
+	for _, change := range changes {
+		s.enqueueGitHubStorySync(ctx, change.StoryID, workspaceID)
+	}
+	return nil
+}
+
+// scheduleEndDate reads an end_date update, which the API sends as a
+// *time.Time and Maya as a time.Time.
+func scheduleEndDate(value any) *time.Time {
+	switch endDate := value.(type) {
+	case *time.Time:
+		return endDate
+	case time.Time:
+		return &endDate
+	default:
+		return nil
+	}
+}
+
+// planSchedule loads the stories that depend on a story and works out how
+// they move when it ends on endDate.
+func (s *Service) planSchedule(ctx context.Context, storyID, workspaceID uuid.UUID, endDate *time.Time) ([]CoreScheduleChange, error) {
+	nodes, edges, err := s.repo.GetScheduleDependents(ctx, storyID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(edges) == 0 {
+		return []CoreScheduleChange{}, nil
+	}
+
+	teamIDs := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, node := range nodes {
+		if !seen[node.TeamID] {
+			seen[node.TeamID] = true
+			teamIDs = append(teamIDs, node.TeamID)
+		}
+	}
+	schedules, err := s.repo.GetTeamSchedules(ctx, workspaceID, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return rescheduleDependents(storyID, endDate, nodes, edges, schedules), nil
+}
+
+// rescheduleDependents moves the stories that transitively depend on root
+// so none starts before its blockers end, with root ending on rootEnd. Only
+// scheduled, open stories of teams that cascade dependencies move. A moved
+// story starts on the first working day after its last blocker and keeps
+// its length in working days.
+func rescheduleDependents(rootID uuid.UUID, rootEnd *time.Time, nodes []CoreDependencyNode, edges []CoreDependencyEdge, schedules map[uuid.UUID]CoreTeamSchedule) []CoreScheduleChange {
+	byID := make(map[uuid.UUID]CoreDependencyNode, len(nodes))
+	ends := make(map[uuid.UUID]*time.Time, len(nodes))
+	for _, node := range nodes {
+		byID[node.ID] = node
+		ends[node.ID] = node.EndDate
+	}
+	ends[rootID] = rootEnd
+
+	blockers := map[uuid.UUID][]uuid.UUID{}
+	blocks := map[uuid.UUID][]uuid.UUID{}
+	for _, edge := range edges {
+		blockers[edge.BlockedID] = append(blockers[edge.BlockedID], edge.BlockerID)
+		blocks[edge.BlockerID] = append(blocks[edge.BlockerID], edge.BlockedID)
+	}
+
+	// Visit the dependents of root so each comes after all of its blockers
+	// that are themselves dependents of root.
+	downstream := map[uuid.UUID]bool{rootID: true}
+	queue := []uuid.UUID{rootID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range blocks[current] {
+			if !downstream[next] {
+				downstream[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	pending := map[uuid.UUID]int{}
+	for id := range downstream {
+		for _, blocker := range blockers[id] {
+			if downstream[blocker] {
+				pending[id]++
+			}
+		}
+	}
+
+	changes := []CoreScheduleChange{}
+	queue = []uuid.UUID{rootID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range blocks[current] {
+			if pending[next]--; pending[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+		if current == rootID {
+			continue
+		}
+
+		node, ok := byID[current]
+		schedule := schedules[node.TeamID]
+		if !ok || node.CompletedAt != nil || node.StartDate == nil || !schedule.CascadeDependencies {
+			continue
+		}
+
+		var latest *time.Time
+		var latestBlocker uuid.UUID
+		for _, blocker := range blockers[current] {
+			end := ends[blocker]
+			if end == nil || byID[blocker].CompletedAt != nil && blocker != rootID {
+				continue
+			}
+			if latest == nil || end.After(*latest) {
+				latest, latestBlocker = end, blocker
+			}
+		}
+		if latest == nil {
+			continue
+		}
+
+		start := schedule.nextWorkingDay(*latest)
+		if !node.StartDate.Before(start) {
+			continue
+		}
+		change := CoreScheduleChange{
+			StoryID:        node.ID,
+			SequenceID:     node.SequenceID,
+			Title:          node.Title,
+			TeamCode:       node.TeamCode,
+			AssigneeID:     node.AssigneeID,
+			BlockerID:      latestBlocker,
+			BlockerRef:     fmt.Sprintf("%s-%d", byID[latestBlocker].TeamCode, byID[latestBlocker].SequenceID),
+			BlockerEndDate: *latest,
+			OldStartDate:   node.StartDate,
+			OldEndDate:     node.EndDate,
+			NewStartDate:   &start,
+		}
+		if node.EndDate != nil {
+			end := schedule.addWorkingDays(start, schedule.workingDays(*node.StartDate, *node.EndDate))
+			change.NewEndDate = &end
+			ends[current] = &end
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func (c CoreTeamSchedule) isWorkingDay(day time.Time) bool {
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return false
+	}
+	return !c.Holidays[day.Format("2006-01-02")]
+}
+
+// nextWorkingDay returns the first working day after day.
+func (c CoreTeamSchedule) nextWorkingDay(day time.Time) time.Time {
+	next := day.AddDate(0, 0, 1)
+	for !c.isWorkingDay(next) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// workingDays counts the working days from start to end inclusive, and is
+// at least one so a story scheduled on a weekend keeps a day.
+func (c CoreTeamSchedule) workingDays(start, end time.Time) int {
+	days := 0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if c.isWorkingDay(day) {
+			days++
+		}
+	}
+	return max(days, 1)
+}
+
+// addWorkingDays returns the last day of a span of days working days that
+// starts on the working day start.
+func (c CoreTeamSchedule) addWorkingDays(start time.Time, days int) time.Time {
+	end := start
+	for days--; days > 0; days-- {
+		end = c.nextWorkingDay(end)
+	}
+	return end
+}
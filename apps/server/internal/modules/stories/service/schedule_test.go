@@ -0,0 +1,96 @@
+package stories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func scheduleDay(value string) *time.Time {
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return &day
+}
+
+func TestScheduleWorkingDays(t *testing.T) {
+	schedule := CoreTeamSchedule{Holidays: map[string]bool{"2026-03-16": true}}
+
+	// Friday March 13 is followed by a weekend and a holiday on Monday.
+	if got := schedule.nextWorkingDay(*scheduleDay("2026-03-13")); !got.Equal(*scheduleDay("2026-03-17")) {
+		t.Errorf("nextWorkingDay = %s, want 2026-03-17", got.Format("2006-01-02"))
+	}
+	if got := schedule.workingDays(*scheduleDay("2026-03-12"), *scheduleDay("2026-03-17")); got != 3 {
+		t.Errorf("workingDays = %d, want 3", got)
+	}
+	if got := schedule.workingDays(*scheduleDay("2026-03-14"), *scheduleDay("2026-03-15")); got != 1 {
+		t.Errorf("workingDays over a weekend = %d, want 1", got)
+	}
+	if got := schedule.addWorkingDays(*scheduleDay("2026-03-12"), 3); !got.Equal(*scheduleDay("2026-03-17")) {
+		t.Errorf("addWorkingDays = %s, want 2026-03-17", got.Format("2006-01-02"))
+	}
+}
+
+func TestRescheduleDependents(t *testing.T) {
+	cascading := uuid.New()
+	fixed := uuid.New()
+	root, a, b, c, done := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	nodes := []CoreDependencyNode{
+		{ID: root, TeamID: cascading, TeamCode: "ENG", SequenceID: 1, StartDate: scheduleDay("2026-03-02"), EndDate: scheduleDay("2026-03-06")},
+		// a runs Monday to Tuesday and must follow root.
+		{ID: a, TeamID: cascading, TeamCode: "ENG", SequenceID: 2, StartDate: scheduleDay("2026-03-09"), EndDate: scheduleDay("2026-03-10")},
+		// b follows a and is on a team that does not cascade.
+		{ID: b, TeamID: fixed, TeamCode: "OPS", SequenceID: 3, StartDate: scheduleDay("2026-03-11"), EndDate: scheduleDay("2026-03-12")},
+		// c follows a and already starts late enough once a moves.
+		{ID: c, TeamID: cascading, TeamCode: "ENG", SequenceID: 4, StartDate: scheduleDay("2026-03-11")},
+		{ID: done, TeamID: cascading, TeamCode: "ENG", SequenceID: 5, StartDate: scheduleDay("2026-03-09"), CompletedAt: scheduleDay("2026-03-01")},
+	}
+	edges := []CoreDependencyEdge{
+		{BlockerID: root, BlockedID: a},
+		{BlockerID: a, BlockedID: b},
+		{BlockerID: a, BlockedID: c},
+		{BlockerID: root, BlockedID: done},
+	}
+	schedules := map[uuid.UUID]CoreTeamSchedule{
+		cascading: {CascadeDependencies: true, Holidays: map[string]bool{"2026-03-16": true}},
+		fixed:     {Holidays: map[string]bool{}},
+	}
+
+	// Root now ends on Thursday March 12.
+	changes := rescheduleDependents(root, scheduleDay("2026-03-12"), nodes, edges, schedules)
+
+	if len(changes) != 2 {
+		t.Fatalf("changes = %d, want 2: %+v", len(changes), changes)
+	}
+	first, second := changes[0], changes[1]
+	if first.StoryID != a || !first.NewStartDate.Equal(*scheduleDay("2026-03-13")) || !first.NewEndDate.Equal(*scheduleDay("2026-03-17")) {
+		t.Errorf("a moved to %s - %s, want 2026-03-13 - 2026-03-17", first.NewStartDate.Format("2006-01-02"), first.NewEndDate.Format("2006-01-02"))
+	}
+	if first.BlockerRef != "ENG-1" || !first.BlockerEndDate.Equal(*scheduleDay("2026-03-12")) {
+		t.Errorf("a blocker = %s on %s, want ENG-1 on 2026-03-12", first.BlockerRef, first.BlockerEndDate.Format("2006-01-02"))
+	}
+	if second.StoryID != c || !second.NewStartDate.Equal(*scheduleDay("2026-03-18")) || second.NewEndDate != nil {
+		t.Errorf("c moved to %+v, want a start of 2026-03-18 and no end", second)
+	}
+	if second.BlockerRef != "ENG-2" {
+		t.Errorf("c blocker = %s, want ENG-2", second.BlockerRef)
+	}
+}
+
+func TestRescheduleDependentsKeepsEarlierEndDates(t *testing.T) {
+	team := uuid.New()
+	root, a := uuid.New(), uuid.New()
+	nodes := []CoreDependencyNode{
+		{ID: root, TeamID: team, EndDate: scheduleDay("2026-03-10")},
+		{ID: a, TeamID: team, StartDate: scheduleDay("2026-03-11"), EndDate: scheduleDay("2026-03-12")},
+	}
+	edges := []CoreDependencyEdge{{BlockerID: root, BlockedID: a}}
+	schedules := map[uuid.UUID]CoreTeamSchedule{team: {CascadeDependencies: true}}
+
+	if changes := rescheduleDependents(root, scheduleDay("2026-03-05"), nodes, edges, schedules); len(changes) != 0 {
+		t.Errorf("changes = %+v, want none when the blocker ends earlier", changes)
+	}
+}
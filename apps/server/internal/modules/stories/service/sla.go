@@ -0,0 +1,456 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrInvalidSLATarget = errors.New("SLA targets are a whole number of minutes, hours or days, such as 30m, 4h or 2d")
+)
+
+// SLA states of a target.
+const (
+	SLAStateOnTrack  = "on_track"
+	SLAStateAtRisk   = "at_risk"
+	SLAStateBreached = "breached"
+	SLAStatePaused   = "paused"
+	SLAStateMet      = "met"
+)
+
+// maxSLADays bounds how far ahead a due time is looked for, so a calendar
+// without working days cannot loop forever.
+const maxSLADays = 3660
+
+// CoreSLACalendar is a team's business time: working hours, in minutes
+// after midnight in Location, on weekdays that are not holidays. Holidays
+// are keyed by their date in 2006-01-02 form.
+type CoreSLACalendar struct {
+	Location      *time.Location
+	WorkdayStart  int
+	WorkdayEnd    int
+	AtRiskPercent int
+	Holidays      map[string]bool
+}
+
+// CoreSLAPolicy is a team's SLA for a priority. Targets are business time
+// such as 4h or 2d and are nil when the policy does not track them.
+type CoreSLAPolicy struct {
+	Priority            string
+	FirstResponseTarget *string
+	ResolveTarget       *string
+	Calendar            CoreSLACalendar
+}
+
+// CoreStorySLAClock is the SLA clock of a story, counted in business
+// minutes. ElapsedMinutes were counted before RunningSince, which is nil
+// while the clock is stopped.
+type CoreStorySLAClock struct {
+	StoryID                 uuid.UUID
+	WorkspaceID             uuid.UUID
+	Priority                string
+	FirstResponseMinutes    *int
+	ResolveMinutes          *int
+	ElapsedMinutes          int
+	RunningSince            *time.Time
+	FirstResponseAtRiskAt   *time.Time
+	FirstResponseDueAt      *time.Time
+	FirstRespondedAt        *time.Time
+	FirstResponseBreachedAt *time.Time
+	FirstResponseAlert      *string
+	ResolveAtRiskAt         *time.Time
+	ResolveDueAt            *time.Time
+	ResolvedAt              *time.Time
+	ResolveBreachedAt       *time.Time
+	ResolveAlert            *string
+}
+
+// CoreSLATarget is where a story stands against one SLA target.
+type CoreSLATarget struct {
+	TargetMinutes int
+	State         string
+	AtRiskAt      *time.Time
+	DueAt         *time.Time
+	MetAt         *time.Time
+	BreachedAt    *time.Time
+}
+
+// CoreStorySLA is where a story stands against its SLA. Targets the policy
+// does not track are nil.
+type CoreStorySLA struct {
+	Priority      string
+	Paused        bool
+	FirstResponse *CoreSLATarget
+	Resolve       *CoreSLATarget
+}
+
+// slaStoryState is what the SLA clock of a story follows.
+type slaStoryState struct {
+	Category  string
+	Responded bool
+}
+
+// GetStorySLA returns where a story stands against its team's SLA for its
+// priority, or nil when no policy applies.
+func (s *Service) GetStorySLA(ctx context.Context, storyID, workspaceID uuid.UUID) (*CoreStorySLA, error) {
+	s.log.Info(ctx, "business.core.stories.GetStorySLA")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.GetStorySLA")
+	defer span.End()
+
+	clock, err := s.repo.GetStorySLAClock(ctx, storyID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if clock == nil {
+		return nil, nil
+	}
+	return clock.summary(time.Now()), nil
+}
+
+// syncStorySLA brings a story's SLA clock in line with the story's team,
+// priority and status category. The clock starts when a policy first
+// applies and is dropped when none does. responded marks the first
+// response, such as a comment from someone other than the reporter.
+func (s *Service) syncStorySLA(ctx context.Context, storyID, workspaceID uuid.UUID, responded bool) error {
+	ctx, span := web.AddSpan(ctx, "business.services.stories.syncStorySLA")
+	defer span.End()
+
+	story, err := s.repo.Get(ctx, storyID, workspaceID)
+	if err != nil {
+		return err
+	}
+	clock, err := s.repo.GetStorySLAClock(ctx, storyID, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	var policy *CoreSLAPolicy
+	if story.DeletedAt == nil && story.ArchivedAt == nil {
+		policy, err = s.repo.GetSLAPolicy(ctx, workspaceID, story.Team, story.Priority)
+		if err != nil {
+			return err
+		}
+	}
+	if policy == nil {
+		if clock == nil {
+			return nil
+		}
+		return s.repo.DeleteStorySLAClock(ctx, storyID, workspaceID)
+	}
+
+	state := slaStoryState{Responded: responded}
+	if story.Status != nil {
+		if state.Category, err = s.repo.GetStatusCategory(ctx, story.Status.String()); err != nil {
+			return err
+		}
+	}
+
+	next, err := advanceSLAClock(clock, *policy, state, time.Now())
+	if err != nil {
+		return err
+	}
+	next.StoryID = storyID
+	next.WorkspaceID = workspaceID
+	if err := s.repo.SaveStorySLAClock(ctx, next); err != nil {
+		return err
+	}
+
+	span.AddEvent("story SLA synced.", trace.WithAttributes(
+		attribute.String("story.id", storyID.String()),
+		attribute.String("sla.priority", next.Priority),
+	))
+	return nil
+}
+
+// ResyncTeamSLA syncs the SLA clocks of a team's open stories, after its SLA
+// settings changed. A story that fails does not stop the others; the count
+// of synced stories is returned with an error naming how many failed.
+func (s *Service) ResyncTeamSLA(ctx context.Context, workspaceID, teamID uuid.UUID) (int, error) {
+	s.log.Info(ctx, "business.core.stories.ResyncTeamSLA")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.ResyncTeamSLA")
+	defer span.End()
+
+	storyIDs, err := s.repo.ListOpenTeamStoryIDs(ctx, workspaceID, teamID)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	synced := 0
+	for _, storyID := range storyIDs {
+		if err := s.syncStorySLA(ctx, storyID, workspaceID, false); err != nil {
+			s.log.Error(ctx, "failed to sync story SLA", "story_id", storyID, "error", err)
+			continue
+		}
+		synced++
+	}
+
+	span.AddEvent("team SLA resynced.", trace.WithAttributes(
+		attribute.String("team.id", teamID.String()),
+		attribute.Int("stories.synced", synced),
+	))
+	if failed := len(storyIDs) - synced; failed > 0 {
+		return synced, fmt.Errorf("failed to sync the SLA of %d of %d stories", failed, len(storyIDs))
+	}
+	return synced, nil
+}
+
+// refreshStorySLA syncs a story's SLA clock after a change that may affect
+// it. A failed sync is logged and does not undo the change.
+func (s *Service) refreshStorySLA(ctx context.Context, storyID, workspaceID uuid.UUID, responded bool) {
+	if err := s.syncStorySLA(ctx, storyID, workspaceID, responded); err != nil {
+		s.log.Error(ctx, "failed to sync story SLA", "story_id", storyID, "error", err)
+	}
+}
+
+// advanceSLAClock moves clock, which is nil for a story the policy has just
+// started to cover, to now. Time counts while the story is open and not
+// paused. The first response is met by a response or once the story is
+// started, and the resolve target once it is completed or cancelled.
+func advanceSLAClock(clock *CoreStorySLAClock, policy CoreSLAPolicy, state slaStoryState, now time.Time) (CoreStorySLAClock, error) {
+	calendar := policy.Calendar
+	firstResponse, err := parseSLATarget(policy.FirstResponseTarget, calendar.workdayMinutes())
+	if err != nil {
+		return CoreStorySLAClock{}, err
+	}
+	resolve, err := parseSLATarget(policy.ResolveTarget, calendar.workdayMinutes())
+	if err != nil {
+		return CoreStorySLAClock{}, err
+	}
+
+	var next CoreStorySLAClock
+	if clock != nil {
+		next = *clock
+	}
+	if next.RunningSince != nil {
+		next.ElapsedMinutes += calendar.businessMinutes(*next.RunningSince, now)
+		next.RunningSince = nil
+	}
+
+	// New targets apply to the time counted so far and may alert again.
+	if clock == nil || next.Priority != policy.Priority ||
+		!intPtrEqual(next.FirstResponseMinutes, firstResponse) || !intPtrEqual(next.ResolveMinutes, resolve) {
+		next.Priority = policy.Priority
+		next.FirstResponseMinutes = firstResponse
+		next.ResolveMinutes = resolve
+		if next.FirstRespondedAt == nil {
+			next.FirstResponseDueAt, next.FirstResponseBreachedAt, next.FirstResponseAlert = nil, nil, nil
+		}
+		if next.ResolvedAt == nil {
+			next.ResolveDueAt, next.ResolveBreachedAt, next.ResolveAlert = nil, nil, nil
+		}
+	}
+
+	if next.FirstRespondedAt == nil {
+		next.FirstResponseBreachedAt = slaBreach(next.FirstResponseMinutes, next.ElapsedMinutes, next.FirstResponseDueAt, next.FirstResponseBreachedAt, now)
+	}
+	if next.ResolvedAt == nil {
+		next.ResolveBreachedAt = slaBreach(next.ResolveMinutes, next.ElapsedMinutes, next.ResolveDueAt, next.ResolveBreachedAt, now)
+	}
+
+	closed := state.Category == "completed" || state.Category == "cancelled"
+	if next.FirstRespondedAt == nil && (state.Responded || closed || state.Category == "started") {
+		next.FirstRespondedAt = &now
+	}
+	switch {
+	case closed && next.ResolvedAt == nil:
+		next.ResolvedAt = &now
+	case !closed && next.ResolvedAt != nil:
+		// A reopened story is back on the clock.
+		next.ResolvedAt = nil
+	}
+
+	running := !closed && state.Category != "paused" &&
+		(next.FirstRespondedAt == nil && next.FirstResponseMinutes != nil || next.ResolvedAt == nil && next.ResolveMinutes != nil)
+	if running {
+		next.RunningSince = &now
+	}
+	if next.FirstRespondedAt == nil {
+		next.FirstResponseAtRiskAt, next.FirstResponseDueAt = calendar.deadlines(next.FirstResponseMinutes, next.ElapsedMinutes, running, now)
+	}
+	if next.ResolvedAt == nil {
+		next.ResolveAtRiskAt, next.ResolveDueAt = calendar.deadlines(next.ResolveMinutes, next.ElapsedMinutes, running, now)
+	}
+	return next, nil
+}
+
+// slaBreach returns when an open target was breached, given the minutes
+// counted against it, or nil when it is not breached.
+func slaBreach(target *int, elapsed int, dueAt, breachedAt *time.Time, now time.Time) *time.Time {
+	if breachedAt != nil || target == nil || elapsed < *target {
+		return breachedAt
+	}
+	if dueAt != nil && !dueAt.After(now) {
+		return dueAt
+	}
+	return &now
+}
+
+// summary reports where the clock stands at now.
+func (c CoreStorySLAClock) summary(now time.Time) *CoreStorySLA {
+	sla := &CoreStorySLA{
+		Priority: c.Priority,
+		Paused:   c.RunningSince == nil && (c.FirstRespondedAt == nil && c.FirstResponseMinutes != nil || c.ResolvedAt == nil && c.ResolveMinutes != nil),
+	}
+	if c.FirstResponseMinutes != nil {
+		sla.FirstResponse = slaTarget(*c.FirstResponseMinutes, c.FirstResponseAtRiskAt, c.FirstResponseDueAt, c.FirstRespondedAt, c.FirstResponseBreachedAt, c.RunningSince != nil, now)
+	}
+	if c.ResolveMinutes != nil {
+		sla.Resolve = slaTarget(*c.ResolveMinutes, c.ResolveAtRiskAt, c.ResolveDueAt, c.ResolvedAt, c.ResolveBreachedAt, c.RunningSince != nil, now)
+	}
+	return sla
+}
+
+func slaTarget(minutes int, atRiskAt, dueAt, metAt, breachedAt *time.Time, running bool, now time.Time) *CoreSLATarget {
+	target := &CoreSLATarget{
+		TargetMinutes: minutes,
+		AtRiskAt:      atRiskAt,
+		DueAt:         dueAt,
+		MetAt:         metAt,
+		BreachedAt:    breachedAt,
+	}
+	switch {
+	case breachedAt != nil, metAt == nil && dueAt != nil && !now.Before(*dueAt):
+		target.State = SLAStateBreached
+	case metAt != nil:
+		target.State = SLAStateMet
+	case !running:
+		target.State = SLAStatePaused
+	case atRiskAt != nil && !now.Before(*atRiskAt):
+		target.State = SLAStateAtRisk
+	default:
+		target.State = SLAStateOnTrack
+	}
+	return target
+}
+
+// parseSLATarget converts a target such as 30m, 4h or 2d to business
+// minutes, where a day is workdayMinutes long.
+func parseSLATarget(target *string, workdayMinutes int) (*int, error) {
+	if target == nil {
+		return nil, nil
+	}
+	value := *target
+	if len(value) < 2 {
+		return nil, ErrInvalidSLATarget
+	}
+	amount, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || amount <= 0 {
+		return nil, ErrInvalidSLATarget
+	}
+	var minutes int
+	switch value[len(value)-1] {
+	case 'm':
+		minutes = amount
+	case 'h':
+		minutes = amount * 60
+	case 'd':
+		minutes = amount * workdayMinutes
+	default:
+		return nil, ErrInvalidSLATarget
+	}
+	return &minutes, nil
+}
+
+func (c CoreSLACalendar) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
+
+func (c CoreSLACalendar) workdayMinutes() int {
+	return c.WorkdayEnd - c.WorkdayStart
+}
+
+// workingHours returns the working hours of the i-th day after the day of
+// t, and whether that day is a working day.
+func (c CoreSLACalendar) workingHours(t time.Time, i int) (time.Time, time.Time, bool) {
+	loc := c.location()
+	year, month, day := t.In(loc).Date()
+	date := time.Date(year, month, day+i, 0, 0, 0, 0, loc)
+	start := time.Date(year, month, day+i, 0, c.WorkdayStart, 0, 0, loc)
+	end := time.Date(year, month, day+i, 0, c.WorkdayEnd, 0, 0, loc)
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday || c.Holidays[date.Format("2006-01-02")] {
+		return start, end, false
+	}
+	return start, end, true
+}
+
+// businessMinutes counts the working minutes from from to to.
+func (c CoreSLACalendar) businessMinutes(from, to time.Time) int {
+	var total time.Duration
+	for i := 0; i < maxSLADays; i++ {
+		start, end, working := c.workingHours(from, i)
+		if !start.Before(to) {
+			break
+		}
+		if !working {
+			continue
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return int(total / time.Minute)
+}
+
+// addBusinessMinutes returns the time minutes working minutes after from.
+func (c CoreSLACalendar) addBusinessMinutes(from time.Time, minutes int) time.Time {
+	if minutes <= 0 {
+		return from
+	}
+	remaining := time.Duration(minutes) * time.Minute
+	for i := 0; i < maxSLADays; i++ {
+		start, end, working := c.workingHours(from, i)
+		if !working {
+			continue
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if !end.After(start) {
+			continue
+		}
+		if span := end.Sub(start); remaining > span {
+			remaining -= span
+			continue
+		}
+		return start.Add(remaining)
+	}
+	return from.Add(remaining)
+}
+
+// deadlines returns when an open target with elapsed minutes counted
+// becomes at risk and due, for a clock that runs from now. Stopped clocks
+// have no deadlines.
+func (c CoreSLACalendar) deadlines(target *int, elapsed int, running bool, now time.Time) (*time.Time, *time.Time) {
+	if target == nil || !running {
+		return nil, nil
+	}
+	atRisk := c.addBusinessMinutes(now, *target*c.AtRiskPercent/100-elapsed)
+	due := c.addBusinessMinutes(now, *target-elapsed)
+	return &atRisk, &due
+}
+
+func intPtrEqual(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,149 @@
+package stories
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func slaTime(day, hour, minute int) time.Time {
+	return time.Date(2024, time.January, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParseSLATarget(t *testing.T) {
+	tests := []struct {
+		target string
+		want   int
+		err    bool
+	}{
+		{"30m", 30, false},
+		{"4h", 240, false},
+		{"2d", 960, false},
+		{"0h", 0, true},
+		{"h", 0, true},
+		{"4w", 0, true},
+		{"-1d", 0, true},
+	}
+	for _, tt := range tests {
+		target := tt.target
+		got, err := parseSLATarget(&target, 480)
+		if tt.err {
+			if !errors.Is(err, ErrInvalidSLATarget) {
+				t.Errorf("%s: got error %v, want ErrInvalidSLATarget", tt.target, err)
+			}
+			continue
+		}
+		if err != nil || got == nil || *got != tt.want {
+			t.Errorf("%s: got %v, %v, want %d", tt.target, got, err, tt.want)
+		}
+	}
+
+	if got, err := parseSLATarget(nil, 480); got != nil || err != nil {
+		t.Errorf("nil target: got %v, %v, want nil", got, err)
+	}
+}
+
+func TestSLACalendarBusinessTime(t *testing.T) {
+	// 5 January 2024 is a Friday.
+	calendar := CoreSLACalendar{WorkdayStart: 9 * 60, WorkdayEnd: 17 * 60}
+
+	if got := calendar.businessMinutes(slaTime(5, 16, 0), slaTime(8, 10, 0)); got != 120 {
+		t.Errorf("over a weekend: got %d minutes, want 120", got)
+	}
+	if got := calendar.addBusinessMinutes(slaTime(5, 16, 0), 120); !got.Equal(slaTime(8, 10, 0)) {
+		t.Errorf("adding over a weekend: got %s, want Monday 10:00", got)
+	}
+	if got := calendar.businessMinutes(slaTime(6, 12, 0), slaTime(6, 15, 0)); got != 0 {
+		t.Errorf("on a Saturday: got %d minutes, want 0", got)
+	}
+
+	calendar.Holidays = map[string]bool{"2024-01-08": true}
+	if got := calendar.businessMinutes(slaTime(5, 16, 0), slaTime(9, 10, 0)); got != 120 {
+		t.Errorf("over a holiday: got %d minutes, want 120", got)
+	}
+	if got := calendar.addBusinessMinutes(slaTime(5, 16, 0), 120); !got.Equal(slaTime(9, 10, 0)) {
+		t.Errorf("adding over a holiday: got %s, want Tuesday 10:00", got)
+	}
+}
+
+func TestAdvanceSLAClockPausesAndMeetsFirstResponse(t *testing.T) {
+	firstResponse, resolve := "4h", "2d"
+	policy := CoreSLAPolicy{
+		Priority:            "Urgent",
+		FirstResponseTarget: &firstResponse,
+		ResolveTarget:       &resolve,
+		Calendar:            CoreSLACalendar{WorkdayStart: 9 * 60, WorkdayEnd: 17 * 60, AtRiskPercent: 80},
+	}
+
+	// Created on Monday at 9:00.
+	clock, err := advanceSLAClock(nil, policy, slaStoryState{Category: "unstarted"}, slaTime(8, 9, 0))
+	if err != nil {
+		t.Fatalf("start clock: %v", err)
+	}
+	if clock.RunningSince == nil || !clock.FirstResponseDueAt.Equal(slaTime(8, 13, 0)) || !clock.ResolveDueAt.Equal(slaTime(9, 17, 0)) {
+		t.Fatalf("start clock: got running %v, due %v and %v", clock.RunningSince, clock.FirstResponseDueAt, clock.ResolveDueAt)
+	}
+	if !clock.ResolveAtRiskAt.Equal(slaTime(9, 13, 48)) {
+		t.Errorf("start clock: got resolve at risk %v, want Tuesday 13:48", clock.ResolveAtRiskAt)
+	}
+
+	// Waiting on the customer from 10:00 until Wednesday.
+	clock, err = advanceSLAClock(&clock, policy, slaStoryState{Category: "paused"}, slaTime(8, 10, 0))
+	if err != nil {
+		t.Fatalf("pause clock: %v", err)
+	}
+	if clock.RunningSince != nil || clock.ElapsedMinutes != 60 || clock.FirstResponseDueAt != nil {
+		t.Fatalf("pause clock: got running %v, elapsed %d, due %v", clock.RunningSince, clock.ElapsedMinutes, clock.FirstResponseDueAt)
+	}
+	if summary := clock.summary(slaTime(9, 12, 0)); !summary.Paused || summary.FirstResponse.State != SLAStatePaused {
+		t.Errorf("paused summary: got %+v", summary)
+	}
+
+	// Started on Wednesday at 9:00.
+	clock, err = advanceSLAClock(&clock, policy, slaStoryState{Category: "started"}, slaTime(10, 9, 0))
+	if err != nil {
+		t.Fatalf("resume clock: %v", err)
+	}
+	if clock.ElapsedMinutes != 60 || clock.FirstRespondedAt == nil || clock.FirstResponseBreachedAt != nil {
+		t.Fatalf("resume clock: got elapsed %d, responded %v, breached %v", clock.ElapsedMinutes, clock.FirstRespondedAt, clock.FirstResponseBreachedAt)
+	}
+	if !clock.ResolveDueAt.Equal(slaTime(11, 16, 0)) {
+		t.Errorf("resume clock: got resolve due %v, want Thursday 16:00", clock.ResolveDueAt)
+	}
+	if summary := clock.summary(slaTime(10, 10, 0)); summary.FirstResponse.State != SLAStateMet || summary.Resolve.State != SLAStateOnTrack {
+		t.Errorf("resumed summary: got %+v and %+v", summary.FirstResponse, summary.Resolve)
+	}
+}
+
+func TestAdvanceSLAClockMarksBreaches(t *testing.T) {
+	firstResponse := "4h"
+	policy := CoreSLAPolicy{
+		Priority:            "Urgent",
+		FirstResponseTarget: &firstResponse,
+		Calendar:            CoreSLACalendar{WorkdayStart: 9 * 60, WorkdayEnd: 17 * 60, AtRiskPercent: 80},
+	}
+
+	clock, err := advanceSLAClock(nil, policy, slaStoryState{Category: "unstarted"}, slaTime(8, 9, 0))
+	if err != nil {
+		t.Fatalf("start clock: %v", err)
+	}
+	clock, err = advanceSLAClock(&clock, policy, slaStoryState{Category: "unstarted"}, slaTime(8, 14, 0))
+	if err != nil {
+		t.Fatalf("advance clock: %v", err)
+	}
+	if clock.FirstResponseBreachedAt == nil || !clock.FirstResponseBreachedAt.Equal(slaTime(8, 13, 0)) {
+		t.Errorf("got breached at %v, want Monday 13:00", clock.FirstResponseBreachedAt)
+	}
+
+	// Responding late meets the target but keeps the breach.
+	clock, err = advanceSLAClock(&clock, policy, slaStoryState{Category: "unstarted", Responded: true}, slaTime(8, 15, 0))
+	if err != nil {
+		t.Fatalf("respond: %v", err)
+	}
+	if clock.RunningSince != nil || clock.FirstResponseBreachedAt == nil {
+		t.Errorf("got running %v, breached %v", clock.RunningSince, clock.FirstResponseBreachedAt)
+	}
+	if state := clock.summary(slaTime(8, 16, 0)).FirstResponse.State; state != SLAStateBreached {
+		t.Errorf("got state %s, want %s", state, SLAStateBreached)
+	}
+}
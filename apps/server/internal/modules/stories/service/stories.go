@@ -2,6 +2,7 @@ package stories
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -10,11 +11,11 @@ import (
 	"time"
 
 	comments "github.com/complexus-tech/projects-api/internal/modules/comments/service"
+	customfields "github.com/complexus-tech/projects-api/internal/modules/customfields/service"
 	links "github.com/complexus-tech/projects-api/internal/modules/links/service"
 	"github.com/complexus-tech/projects-api/internal/platform/auth"
 	"github.com/complexus-tech/projects-api/pkg/events"
 	"github.com/complexus-tech/projects-api/pkg/logger"
-	"github.com/complexus-tech/projects-api/pkg/publisher"
 	"github.com/complexus-tech/projects-api/pkg/tasks"
 	"github.com/complexus-tech/projects-api/pkg/web"
 	"github.com/google/uuid"
@@ -36,18 +37,20 @@ type Repository interface {
 	BulkRestore(ctx context.Context, ids []uuid.UUID, workspaceId uuid.UUID) error
 	BulkArchive(ctx context.Context, ids []uuid.UUID, workspaceId uuid.UUID) error
 	BulkUnarchive(ctx context.Context, ids []uuid.UUID, workspaceId uuid.UUID) error
-	Update(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID, updates map[string]any) error
+	Update(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID, updates map[string]any, write CoreStoryWrite) error
 	UpdateLabels(ctx context.Context, id uuid.UUID, workspaceId uuid.UUID, labels []uuid.UUID) error
 	GetStoryLinks(ctx context.Context, storyID uuid.UUID) ([]links.CoreLink, error)
-	Create(ctx context.Context, story *CoreSingleStory) (CoreSingleStory, error)
+	Create(ctx context.Context, story *CoreSingleStory, write CoreStoryWrite) (CoreSingleStory, error)
 	GetNextSequenceID(ctx context.Context, teamId uuid.UUID, workspaceId uuid.UUID) (int, func() error, func() error, error)
 	MyStories(ctx context.Context, workspaceId uuid.UUID) ([]CoreStoryList, error)
 	GetSubStories(ctx context.Context, parentId uuid.UUID, workspaceId uuid.UUID) ([]CoreStoryList, error)
 	RecordActivities(ctx context.Context, activities []CoreActivity) ([]CoreActivity, error)
 	GetActivitiesWithUser(ctx context.Context, storyID uuid.UUID, page, pageSize int) ([]CoreActivityWithUser, bool, error)
-	CreateComment(ctx context.Context, comment CoreNewComment) (comments.CoreComment, error)
-	GetComments(ctx context.Context, storyID uuid.UUID, page, pageSize int) ([]comments.CoreComment, bool, error)
+	CreateComment(ctx context.Context, comment CoreNewComment, outboxEvents ...events.Event) (comments.CoreComment, error)
+	GetComments(ctx context.Context, storyID uuid.UUID, resolved *bool, page, pageSize int) ([]comments.CoreComment, bool, error)
 	GetComment(ctx context.Context, commentID uuid.UUID) (comments.CoreComment, error)
+	ToggleReaction(ctx context.Context, commentID, userID uuid.UUID, emoji string, outboxEvents ...events.Event) (bool, error)
+	GetCommentReactions(ctx context.Context, commentID uuid.UUID) ([]comments.CoreReaction, error)
 	DuplicateStory(ctx context.Context, originalStoryID uuid.UUID, workspaceId uuid.UUID, userID uuid.UUID) (CoreSingleStory, error)
 	CountStoriesInWorkspace(ctx context.Context, workspaceId uuid.UUID) (int, error)
 	List(ctx context.Context, workspaceId uuid.UUID, filters map[string]any) ([]CoreStoryList, error)
@@ -60,6 +63,45 @@ type Repository interface {
 	UpdateAssociation(ctx context.Context, associationID, fromID, toID uuid.UUID, associationType string, workspaceID uuid.UUID) (CoreStoryAssociation, error)
 	RemoveAssociation(ctx context.Context, associationID, workspaceID uuid.UUID) (CoreStoryAssociation, error)
 	GetTeamEstimateScheme(ctx context.Context, teamID, workspaceID uuid.UUID) (string, error)
+	EpicInTeam(ctx context.Context, epicID, workspaceID, teamID uuid.UUID) (bool, error)
+	GetCustomFields(ctx context.Context, workspaceID, teamID uuid.UUID) ([]customfields.CoreCustomField, error)
+	ResolveQueryNames(ctx context.Context, workspaceID uuid.UUID, kind string, names []string) (map[string][]uuid.UUID, error)
+	ListWatchers(ctx context.Context, storyID, workspaceID uuid.UUID) ([]CoreStoryWatcher, error)
+	AddWatchers(ctx context.Context, storyID, workspaceID uuid.UUID, userIDs []uuid.UUID, source string) error
+	RemoveWatcher(ctx context.Context, storyID, workspaceID, userID uuid.UUID) error
+	ListDescriptionRevisions(ctx context.Context, storyID, workspaceID uuid.UUID) ([]CoreDescriptionRevision, error)
+	GetDescriptionRevision(ctx context.Context, revisionID, workspaceID uuid.UUID) (CoreDescriptionRevision, error)
+	BlockingPathExists(ctx context.Context, fromID, toID, workspaceID, ignoreAssociationID uuid.UUID) (bool, error)
+	GetDependencyGraph(ctx context.Context, workspaceID uuid.UUID, scope string, scopeID uuid.UUID) ([]CoreDependencyNode, []CoreDependencyEdge, error)
+	GetScheduleDependents(ctx context.Context, storyID, workspaceID uuid.UUID) ([]CoreDependencyNode, []CoreDependencyEdge, error)
+	GetTeamSchedules(ctx context.Context, workspaceID uuid.UUID, teamIDs []uuid.UUID) (map[uuid.UUID]CoreTeamSchedule, error)
+	RescheduleStories(ctx context.Context, workspaceID uuid.UUID, changes []CoreScheduleChange, outboxEvents ...events.Event) error
+	GetStoryRanks(ctx context.Context, workspaceID uuid.UUID, storyIDs []uuid.UUID) (map[uuid.UUID]*string, error)
+	GetNeighbourRank(ctx context.Context, workspaceID uuid.UUID, rank string, next bool) (*string, error)
+	UpdateStoryRank(ctx context.Context, storyID, workspaceID uuid.UUID, rank string) error
+	GetTeamStatuses(ctx context.Context, workspaceID, teamID uuid.UUID) ([]CoreTeamStatus, error)
+	MoveStoriesToTeam(ctx context.Context, moves []CoreTeamMove, moveEvents func([]CoreTeamMove) []events.Event) ([]CoreTeamMove, error)
+	MergeStory(ctx context.Context, merge CoreStoryMerge, outboxEvents ...events.Event) (CoreStoryMerge, error)
+	GetSLAPolicy(ctx context.Context, workspaceID, teamID uuid.UUID, priority string) (*CoreSLAPolicy, error)
+	GetStorySLAClock(ctx context.Context, storyID, workspaceID uuid.UUID) (*CoreStorySLAClock, error)
+	SaveStorySLAClock(ctx context.Context, clock CoreStorySLAClock) error
+	DeleteStorySLAClock(ctx context.Context, storyID, workspaceID uuid.UUID) error
+	ListOpenTeamStoryIDs(ctx context.Context, workspaceID, teamID uuid.UUID) ([]uuid.UUID, error)
+	GetWorkflowRules(ctx context.Context, workspaceID, teamID uuid.UUID) (*CoreWorkflowRules, error)
+	GetWorkspaceRole(ctx context.Context, workspaceID, userID uuid.UUID) (string, error)
+	GetWIPLimits(ctx context.Context, workspaceID uuid.UUID, statusIDs []uuid.UUID) ([]CoreStatusWIP, error)
+	ListChecklistItems(ctx context.Context, storyID, workspaceID uuid.UUID) ([]CoreChecklistItem, error)
+	GetChecklistItem(ctx context.Context, itemID, workspaceID uuid.UUID) (CoreChecklistItem, error)
+	CreateChecklistItem(ctx context.Context, item CoreChecklistItem) (CoreChecklistItem, error)
+	ToggleChecklistItem(ctx context.Context, itemID, workspaceID uuid.UUID) (CoreChecklistItem, error)
+	ReorderChecklistItems(ctx context.Context, storyID, workspaceID uuid.UUID, itemIDs []uuid.UUID) error
+	DeleteChecklistItem(ctx context.Context, itemID, workspaceID uuid.UUID) error
+	GetChecklistProgress(ctx context.Context, workspaceID uuid.UUID, storyIDs []uuid.UUID) (map[uuid.UUID]CoreChecklistProgress, error)
+	ListAssociationTypes(ctx context.Context, workspaceID uuid.UUID) ([]CoreAssociationType, error)
+	GetAssociationType(ctx context.Context, workspaceID uuid.UUID, key string) (CoreAssociationType, error)
+	CreateAssociationType(ctx context.Context, assocType CoreAssociationType) (CoreAssociationType, error)
+	UpdateAssociationType(ctx context.Context, workspaceID uuid.UUID, key, forwardLabel, backwardLabel string) (CoreAssociationType, error)
+	DeleteAssociationType(ctx context.Context, workspaceID uuid.UUID, key string) error
 }
 
 // MentionsRepository provides access to comment mentions storage.
@@ -79,7 +121,6 @@ type Service struct {
 	repo           Repository
 	mentionsRepo   MentionsRepository
 	log            *logger.Logger
-	publisher      *publisher.Publisher
 	tasksService   *tasks.Service
 	mayaAssignment *mayaAssignmentAutomation
 }
@@ -87,6 +128,11 @@ type Service struct {
 type createOptions struct {
 	publishEvents     bool
 	enqueueGitHubSync bool
+	// requireCustomFields enforces required custom fields. Integrations creating
+	// stories on a user's behalf cannot know a team's fields, so it is off for them.
+	requireCustomFields bool
+	// checklistItemID is the checklist item the story is converted from.
+	checklistItemID *uuid.UUID
 }
 
 type updateOptions struct {
@@ -94,6 +140,15 @@ type updateOptions struct {
 	enqueueGitHubSync        bool
 	recordDescriptionUpdates bool
 	activityReason           string
+	// external marks moves made by integrations, which skip status
+	// transition rules and WIP limits when the team allows it.
+	external bool
+	// skipWIPLimits is set when the caller already checked WIP limits. The
+	// blocking limits are still enforced when the story is written.
+	skipWIPLimits bool
+	// wipWarnings, when set, collects the WIP limits the update goes over
+	// when the team only warns about them.
+	wipWarnings *[]CoreWIPBreach
 }
 
 type commentOptions struct {
@@ -101,12 +156,11 @@ type commentOptions struct {
 }
 
 // New constructs a new stories service instance with the provided repository.
-func New(log *logger.Logger, repo Repository, mentionsRepo MentionsRepository, publisher *publisher.Publisher, tasksService *tasks.Service) *Service {
+func New(log *logger.Logger, repo Repository, mentionsRepo MentionsRepository, tasksService *tasks.Service) *Service {
 	return &Service{
 		repo:         repo,
 		mentionsRepo: mentionsRepo,
 		log:          log,
-		publisher:    publisher,
 		tasksService: tasksService,
 	}
 }
@@ -118,8 +172,9 @@ func (s *Service) Create(ctx context.Context, ns CoreNewStory, workspaceId uuid.
 		actorID = *ns.Reporter
 	}
 	return s.createWithOptions(ctx, ns, workspaceId, actorID, createOptions{
-		publishEvents:     true,
-		enqueueGitHubSync: true,
+		publishEvents:       true,
+		enqueueGitHubSync:   true,
+		requireCustomFields: true,
 	})
 }
 
@@ -145,6 +200,11 @@ func (s *Service) createWithOptions(ctx context.Context, ns CoreNewStory, worksp
 		ns.Reporter = &actorID
 	}
 
+	if err := s.validateEpic(ctx, workspaceId, ns.Team, ns.Epic); err != nil {
+		span.RecordError(err)
+		return CoreSingleStory{}, err
+	}
+
 	story := toCoreSingleStory(ns, workspaceId)
 	estimateScheme, err := s.repo.GetTeamEstimateScheme(ctx, ns.Team, workspaceId)
 	if err != nil {
@@ -163,7 +223,42 @@ func (s *Service) createWithOptions(ctx context.Context, ns CoreNewStory, worksp
 	story.EstimateValue = ns.EstimateValue
 	story.EstimateLabel = EstimateLabelFromValue(estimateScheme, ns.EstimateValue)
 
-	cs, err := s.repo.Create(ctx, &story)
+	customFieldValues, _, err := s.validateCustomFieldValues(ctx, workspaceId, ns.Team, ns.CustomFields, options.requireCustomFields)
+	if err != nil {
+		span.RecordError(err)
+		return CoreSingleStory{}, err
+	}
+
+	// Events are written to the outbox with the story, so the ID must be known up front.
+	story.ID = uuid.New()
+	var outboxEvents []events.Event
+	if options.publishEvents {
+		outboxEvents = append(outboxEvents, events.Event{
+			Type: events.StoryCreated,
+			Payload: events.StoryCreatedPayload{
+				StoryID:     story.ID,
+				WorkspaceID: workspaceId,
+				Title:       story.Title,
+				AssigneeID:  story.Assignee,
+				ReporterID:  *ns.Reporter,
+			},
+			Timestamp: time.Now(),
+			ActorID:   actorID,
+		})
+	}
+
+	for fieldID, value := range customFieldValues {
+		if value == nil {
+			delete(customFieldValues, fieldID)
+		}
+	}
+
+	cs, err := s.repo.Create(ctx, &story, CoreStoryWrite{
+		ActorID:           actorID,
+		CustomFieldValues: customFieldValues,
+		Events:            outboxEvents,
+		ChecklistItemID:   options.checklistItemID,
+	})
 	if err != nil {
 		span.RecordError(err)
 		return CoreSingleStory{}, err
@@ -175,6 +270,9 @@ func (s *Service) createWithOptions(ctx context.Context, ns CoreNewStory, worksp
 		}
 		cs.Labels = ns.LabelIDs
 	}
+	if len(customFieldValues) > 0 {
+		cs.CustomFields = customFieldValues
+	}
 	cs.EstimateScheme = estimateScheme
 	cs.EstimateLabel = EstimateLabelFromValue(estimateScheme, cs.EstimateValue)
 
@@ -192,30 +290,10 @@ func (s *Service) createWithOptions(ctx context.Context, ns CoreNewStory, worksp
 		span.RecordError(err)
 	}
 
-	if options.publishEvents {
-		payload := events.StoryCreatedPayload{
-			StoryID:     cs.ID,
-			WorkspaceID: workspaceId,
-			Title:       cs.Title,
-			AssigneeID:  cs.Assignee,
-			ReporterID:  *ns.Reporter,
-		}
-
-		event := events.Event{
-			Type:      events.StoryCreated,
-			Payload:   payload,
-			Timestamp: time.Now(),
-			ActorID:   actorID,
-		}
-
-		if err := s.publisher.Publish(context.Background(), event); err != nil {
-			s.log.Error(ctx, "failed to publish story created event", "error", err)
-			// Don't return error as this is not critical
-		}
-	}
 	if options.enqueueGitHubSync {
 		s.enqueueGitHubStorySync(ctx, cs.ID, workspaceId)
 	}
+	s.refreshStorySLA(ctx, cs.ID, workspaceId, false)
 	if err := s.triggerMayaAssignment(ctx, cs, nil, actorID); err != nil {
 		span.RecordError(err)
 		return CoreSingleStory{}, err
@@ -286,6 +364,10 @@ func (s *Service) MyStories(ctx context.Context, workspaceId uuid.UUID) ([]CoreS
 		span.RecordError(err)
 		return nil, err
 	}
+	if err := s.addChecklistProgress(ctx, workspaceId, stories); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
 
 	span.AddEvent("stories retrieved.", trace.WithAttributes(
 		attribute.Int("story.count", len(stories)),
@@ -329,6 +411,10 @@ func (s *Service) List(ctx context.Context, workspaceId uuid.UUID, filters map[s
 		span.RecordError(err)
 		return nil, err
 	}
+	if err := s.addChecklistProgress(ctx, workspaceId, stories); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
 	span.AddEvent("stories retrieved.", trace.WithAttributes(
 		attribute.Int("story.count", len(stories)),
 	))
@@ -348,14 +434,18 @@ func (s *Service) Delete(ctx context.Context, id uuid.UUID, workspaceId uuid.UUI
 	return nil
 }
 
-// Update updates a story.
-func (s *Service) Update(ctx context.Context, storyID, workspaceID uuid.UUID, updates map[string]any) error {
+// Update updates a story. It returns the WIP limits the update goes over
+// when the team warns rather than blocks.
+func (s *Service) Update(ctx context.Context, storyID, workspaceID uuid.UUID, updates map[string]any) ([]CoreWIPBreach, error) {
 	actorID, _ := auth.GetUserID(ctx)
-	return s.updateWithOptions(ctx, storyID, workspaceID, actorID, updates, updateOptions{
+	var warnings []CoreWIPBreach
+	err := s.updateWithOptions(ctx, storyID, workspaceID, actorID, updates, updateOptions{
 		publishEvents:            true,
 		enqueueGitHubSync:        true,
 		recordDescriptionUpdates: false,
+		wipWarnings:              &warnings,
 	})
+	return warnings, err
 }
 
 func (s *Service) UpdateExternal(ctx context.Context, actorID, storyID, workspaceID uuid.UUID, updates map[string]any) error {
@@ -366,6 +456,7 @@ func (s *Service) UpdateExternalWithReason(ctx context.Context, actorID, storyID
 	return s.updateWithOptions(ctx, storyID, workspaceID, actorID, updates, updateOptions{
 		recordDescriptionUpdates: true,
 		activityReason:           reason,
+		external:                 true,
 	})
 }
 
@@ -395,20 +486,96 @@ func (s *Service) updateWithOptions(ctx context.Context, storyID, workspaceID, a
 		return err
 	}
 
+	// A team change also needs a new reference, status and sprint, so it
+	// goes through a team move. The move is planned here and the remaining
+	// updates are validated against the story as it will be in the new
+	// team; nothing is written until every check has passed.
+	var teamMove teamMovePlan
+	if value, ok := updates["team_id"]; ok {
+		delete(updates, "team_id")
+		if teamID, ok := teamUpdateValue(value); ok && teamID != story.Team {
+			if teamMove, err = s.planTeamMoves(ctx, workspaceID, actorID, []uuid.UUID{storyID}, teamID, nil); err != nil {
+				span.RecordError(err)
+				return err
+			}
+			if move, ok := teamMove.moved(storyID); ok {
+				story = movedStory(story, move)
+			}
+		}
+	}
+
+	if value, ok := updates["epic_id"]; ok {
+		if epicID, ok := teamUpdateValue(value); ok {
+			if err := s.validateEpic(ctx, workspaceID, story.Team, &epicID); err != nil {
+				span.RecordError(err)
+				return err
+			}
+		}
+	}
+
 	if err := s.applyEstimateUpdate(ctx, workspaceID, story, updates); err != nil {
 		span.RecordError(err)
 		return err
 	}
 
+	// Custom field values live in their own table and are validated against
+	// the team's field definitions, so they are split off from column updates.
+	var customFieldChanges map[uuid.UUID]json.RawMessage
+	var customFieldDefs map[uuid.UUID]customfields.CoreCustomField
+	if value, ok := updates[customFieldsUpdateKey]; ok {
+		delete(updates, customFieldsUpdateKey)
+		values, err := customFieldUpdates(value)
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+		customFieldChanges, customFieldDefs, err = s.validateCustomFieldValues(ctx, workspaceID, story.Team, values, false)
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+		for fieldID, value := range customFieldChanges {
+			if customFieldValueEqual(story.CustomFields[fieldID], value) {
+				delete(customFieldChanges, fieldID)
+			}
+		}
+	}
+
 	for field, value := range updates {
 		if s.valuesEqual(s.getOldValue(story, field), value) {
 			delete(updates, field)
 		}
 	}
-	if len(updates) == 0 {
+	if len(updates) == 0 && len(customFieldChanges) == 0 {
+		if _, err := s.moveStories(ctx, actorID, teamMove); err != nil {
+			span.RecordError(err)
+			return err
+		}
 		return nil
 	}
 
+	if err := s.checkTransition(ctx, workspaceID, actorID, story, updates, options.external, make(map[uuid.UUID]*CoreWorkflowRules)); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	move, wipMoved := storyWIPMove(story, updates)
+	if wipMoved && !options.skipWIPLimits {
+		warnings, err := s.checkWIPLimits(ctx, workspaceID, []wipMove{move}, options.external)
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+		if options.wipWarnings != nil {
+			*options.wipWarnings = warnings
+		}
+	}
+
+	if _, err := s.moveStories(ctx, actorID, teamMove); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
 	if assigneeID, ok := mayaAssignmentUpdateAssignee(updates); ok {
 		updatedStory, err := storyWithAssignee(story, assigneeID)
 		if err != nil {
@@ -429,13 +596,49 @@ func (s *Service) updateWithOptions(ctx context.Context, storyID, workspaceID, a
 		}
 	}
 
-	// Update the story
-	if err := s.repo.Update(ctx, storyID, workspaceID, updates); err != nil {
+	eventUpdates := updates
+	if len(customFieldChanges) > 0 {
+		eventUpdates = make(map[string]any, len(updates)+1)
+		for field, value := range updates {
+			eventUpdates[field] = value
+		}
+		eventUpdates[customFieldsUpdateKey] = customFieldChanges
+	}
+
+	var outboxEvents []events.Event
+	if options.publishEvents {
+		outboxEvents = append(outboxEvents, events.Event{
+			Type: events.StoryUpdated,
+			Payload: events.StoryUpdatedPayload{
+				StoryID:     storyID,
+				WorkspaceID: workspaceID,
+				Updates:     eventUpdates,
+				AssigneeID:  story.Assignee, // Current assignee before update
+			},
+			Timestamp: time.Now(),
+			ActorID:   actorID,
+		})
+	}
+
+	// Update the story, its custom field values, its description revision
+	// and its event in one transaction. With only custom field changes this
+	// still bumps updated_at and queues the event.
+	activityReason := normalizeActivityReason(options.activityReason)
+	write := CoreStoryWrite{
+		ActorID:           actorID,
+		CustomFieldValues: customFieldChanges,
+		Events:            outboxEvents,
+	}
+	descriptionRevisionWrite(&write, story, workspaceID, updates, activityReason)
+	if wipMoved {
+		wipWrite(&write, move, options.external)
+	}
+	if err := s.repo.Update(ctx, storyID, workspaceID, updates, write); err != nil {
 		span.RecordError(err)
 		return err
 	}
+
 	ca := []CoreActivity{}
-	activityReason := normalizeActivityReason(options.activityReason)
 
 	for field, value := range updates {
 		if strings.Contains(field, "description") && !options.recordDescriptionUpdates {
@@ -456,6 +659,13 @@ func (s *Service) updateWithOptions(ctx context.Context, storyID, workspaceID, a
 		}
 		ca = append(ca, na)
 	}
+	ca = append(ca, customFieldActivities(story, customFieldChanges, customFieldDefs, CoreActivity{
+		StoryID:     storyID,
+		Type:        "update",
+		Reason:      activityReason,
+		UserID:      actorID,
+		WorkspaceID: workspaceID,
+	})...)
 	if len(ca) > 0 {
 		if _, err := s.repo.RecordActivities(ctx, ca); err != nil {
 			span.RecordError(err)
@@ -466,29 +676,24 @@ func (s *Service) updateWithOptions(ctx context.Context, storyID, workspaceID, a
 		attribute.String("story.id", storyID.String()),
 	))
 
-	if options.publishEvents {
-		payload := events.StoryUpdatedPayload{
-			StoryID:     storyID,
-			WorkspaceID: workspaceID,
-			Updates:     updates,
-			AssigneeID:  story.Assignee, // Current assignee before update
-		}
+	if options.enqueueGitHubSync {
+		s.enqueueGitHubStorySync(ctx, storyID, workspaceID)
+	}
 
-		event := events.Event{
-			Type:      events.StoryUpdated,
-			Payload:   payload,
-			Timestamp: time.Now(),
-			ActorID:   actorID,
-		}
+	_, statusChanged := updates["status_id"]
+	_, priorityChanged := updates["priority"]
+	if statusChanged || priorityChanged {
+		s.refreshStorySLA(ctx, storyID, workspaceID, false)
+	}
 
-		if err := s.publisher.Publish(context.Background(), event); err != nil {
-			s.log.Error(ctx, "failed to publish story updated event", "error", err)
-			// Don't return error as this is not critical
+	// Stories that depend on this one follow its new end date. A failed
+	// cascade does not undo the update.
+	if value, ok := updates["end_date"]; ok {
+		if err := s.cascadeSchedule(ctx, storyID, workspaceID, actorID, scheduleEndDate(value)); err != nil {
+			s.log.Error(ctx, "failed to cascade schedule", "story_id", storyID, "error", err)
+			span.RecordError(err)
 		}
 	}
-	if options.enqueueGitHubSync {
-		s.enqueueGitHubStorySync(ctx, storyID, workspaceID)
-	}
 
 	return nil
 }
@@ -557,20 +762,29 @@ func (s *Service) handleCompletionStatusChange(ctx context.Context, story CoreSi
 	return nil
 }
 
-// BulkUpdate updates multiple stories with the same updates in parallel.
-func (s *Service) BulkUpdate(ctx context.Context, storyIDs []uuid.UUID, workspaceID uuid.UUID, updates map[string]any) error {
+// BulkUpdate updates multiple stories with the same updates in parallel. It
+// returns the WIP limits the updates go over when the teams only warn.
+func (s *Service) BulkUpdate(ctx context.Context, storyIDs []uuid.UUID, workspaceID uuid.UUID, updates map[string]any) ([]CoreWIPBreach, error) {
 	s.log.Info(ctx, "business.core.stories.BulkUpdate")
 	ctx, span := web.AddSpan(ctx, "business.core.stories.BulkUpdate")
 	defer span.End()
 
 	if len(storyIDs) == 0 {
-		return fmt.Errorf("no story IDs provided")
+		return nil, fmt.Errorf("no story IDs provided")
 	}
 
 	span.AddEvent("bulk update started", trace.WithAttributes(
 		attribute.Int("story.count", len(storyIDs)),
 		attribute.String("workspace.id", workspaceID.String()),
 	))
+
+	actorID, _ := auth.GetUserID(ctx)
+	warnings, err := s.checkBulkUpdate(ctx, storyIDs, workspaceID, actorID, updates)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
 	var wg sync.WaitGroup
 
 	// Channel to collect errors from goroutines
@@ -579,7 +793,12 @@ func (s *Service) BulkUpdate(ctx context.Context, storyIDs []uuid.UUID, workspac
 		wg.Add(1)
 		go func(id uuid.UUID) {
 			defer wg.Done()
-			if err := s.Update(ctx, id, workspaceID, updates); err != nil {
+			err := s.updateWithOptions(ctx, id, workspaceID, actorID, updates, updateOptions{
+				publishEvents:     true,
+				enqueueGitHubSync: true,
+				skipWIPLimits:     true,
+			})
+			if err != nil {
 				errChan <- fmt.Errorf("failed to update story %s: %w", id, err)
 			}
 		}(storyID)
@@ -602,14 +821,14 @@ func (s *Service) BulkUpdate(ctx context.Context, storyIDs []uuid.UUID, workspac
 		for _, err := range errors {
 			errorMessages = append(errorMessages, err.Error())
 		}
-		return fmt.Errorf("bulk update errors: %s", strings.Join(errorMessages, "; "))
+		return nil, fmt.Errorf("bulk update errors: %s", strings.Join(errorMessages, "; "))
 	}
 
 	span.AddEvent("bulk update completed successfully", trace.WithAttributes(
 		attribute.Int("stories.updated", len(storyIDs)),
 	))
 
-	return nil
+	return warnings, nil
 }
 
 // BulkDelete deletes the stories with the specified IDs.
@@ -758,7 +977,12 @@ func (s *Service) createCommentWithOptions(ctx context.Context, workspaceID uuid
 		return comments.CoreComment{}, err
 	}
 
-	comment, err := s.repo.CreateComment(ctx, cnc)
+	if cnc.ID == uuid.Nil {
+		cnc.ID = uuid.New()
+	}
+	outboxEvents := s.commentEvents(ctx, story, cnc, options.actorID)
+
+	comment, err := s.repo.CreateComment(ctx, cnc, outboxEvents...)
 	if err != nil {
 		span.RecordError(err)
 		return comments.CoreComment{}, err
@@ -770,101 +994,92 @@ func (s *Service) createCommentWithOptions(ctx context.Context, workspaceID uuid
 			// Note: We don't return error here to avoid failing comment creation if mentions fail
 		}
 	}
+	s.watchOnComment(ctx, story.ID, workspaceID, options.actorID, cnc.Mentions)
+	// A comment from anyone but the reporter is a response to the story.
+	if story.Reporter == nil || *story.Reporter != options.actorID {
+		s.refreshStorySLA(ctx, story.ID, workspaceID, true)
+	}
 
-	actorID := options.actorID
+	span.AddEvent("comment created.", trace.WithAttributes(
+		attribute.String("comment.comment", comment.Comment),
+		attribute.Int("mentions.count", len(cnc.Mentions)),
+	))
+
+	return comment, nil
+}
+
+// commentEvents builds the events written to the outbox alongside a new comment.
+func (s *Service) commentEvents(ctx context.Context, story CoreSingleStory, cnc CoreNewComment, actorID uuid.UUID) []events.Event {
+	now := time.Now()
+	evts := make([]events.Event, 0, len(cnc.Mentions)+1)
 
-	// Publish events based on comment type
 	if cnc.Parent != nil {
 		// This is a reply - get parent comment details
 		parentComment, err := s.repo.GetComment(ctx, *cnc.Parent)
 		if err != nil {
 			s.log.Error(ctx, "failed to get parent comment for notification", "error", err, "parent_id", *cnc.Parent)
 		} else {
-			// Publish comment reply event
-			payload := events.CommentRepliedPayload{
-				CommentID:       comment.ID,
-				ParentCommentID: *cnc.Parent,
-				ParentAuthorID:  parentComment.UserID,
-				StoryID:         cnc.StoryID,
-				StoryTitle:      story.Title,
-				WorkspaceID:     story.Workspace,
-				Content:         cnc.Comment,
-				Mentions:        cnc.Mentions,
-			}
-
-			event := events.Event{
-				Type:      events.CommentReplied,
-				Payload:   payload,
-				Timestamp: time.Now(),
+			evts = append(evts, events.Event{
+				Type: events.CommentReplied,
+				Payload: events.CommentRepliedPayload{
+					CommentID:       cnc.ID,
+					ParentCommentID: *cnc.Parent,
+					ParentAuthorID:  parentComment.UserID,
+					StoryID:         cnc.StoryID,
+					StoryTitle:      story.Title,
+					WorkspaceID:     story.Workspace,
+					Content:         cnc.Comment,
+					Mentions:        cnc.Mentions,
+				},
+				Timestamp: now,
 				ActorID:   actorID,
-			}
-
-			if err := s.publisher.Publish(context.Background(), event); err != nil {
-				s.log.Error(ctx, "failed to publish comment replied event", "error", err)
-			}
+			})
 		}
 	} else {
-		// This is a new comment on the story
-		payload := events.CommentCreatedPayload{
-			CommentID:   comment.ID,
-			StoryID:     cnc.StoryID,
-			StoryTitle:  story.Title,
-			AssigneeID:  story.Assignee,
-			WorkspaceID: story.Workspace,
-			Content:     cnc.Comment,
-			Mentions:    cnc.Mentions,
-		}
-
-		event := events.Event{
-			Type:      events.CommentCreated,
-			Payload:   payload,
-			Timestamp: time.Now(),
+		evts = append(evts, events.Event{
+			Type: events.CommentCreated,
+			Payload: events.CommentCreatedPayload{
+				CommentID:   cnc.ID,
+				StoryID:     cnc.StoryID,
+				StoryTitle:  story.Title,
+				AssigneeID:  story.Assignee,
+				WorkspaceID: story.Workspace,
+				Content:     cnc.Comment,
+				Mentions:    cnc.Mentions,
+			},
+			Timestamp: now,
 			ActorID:   actorID,
-		}
-
-		if err := s.publisher.Publish(context.Background(), event); err != nil {
-			s.log.Error(ctx, "failed to publish comment created event", "error", err)
-		}
+		})
 	}
 
-	// Publish mention events for each mentioned user
+	// One mention event per mentioned user
 	for _, mentionedUserID := range cnc.Mentions {
-		payload := events.UserMentionedPayload{
-			CommentID:     comment.ID,
-			StoryID:       cnc.StoryID,
-			StoryTitle:    story.Title,
-			WorkspaceID:   story.Workspace,
-			MentionedUser: mentionedUserID,
-			Content:       cnc.Comment,
-		}
-
-		event := events.Event{
-			Type:      events.UserMentioned,
-			Payload:   payload,
-			Timestamp: time.Now(),
+		evts = append(evts, events.Event{
+			Type: events.UserMentioned,
+			Payload: events.UserMentionedPayload{
+				CommentID:     cnc.ID,
+				StoryID:       cnc.StoryID,
+				StoryTitle:    story.Title,
+				WorkspaceID:   story.Workspace,
+				MentionedUser: mentionedUserID,
+				Content:       cnc.Comment,
+			},
+			Timestamp: now,
 			ActorID:   actorID,
-		}
-
-		if err := s.publisher.Publish(context.Background(), event); err != nil {
-			s.log.Error(ctx, "failed to publish user mentioned event", "error", err, "mentioned_user", mentionedUserID)
-		}
+		})
 	}
 
-	span.AddEvent("comment created.", trace.WithAttributes(
-		attribute.String("comment.comment", comment.Comment),
-		attribute.Int("mentions.count", len(cnc.Mentions)),
-	))
-
-	return comment, nil
+	return evts
 }
 
-// GetComments returns the comments for a story with pagination.
-func (s *Service) GetComments(ctx context.Context, storyID uuid.UUID, page, pageSize int) ([]comments.CoreComment, bool, error) {
+// GetComments returns the top-level comments for a story with pagination.
+// A non-nil resolved keeps only resolved or only open threads.
+func (s *Service) GetComments(ctx context.Context, storyID uuid.UUID, resolved *bool, page, pageSize int) ([]comments.CoreComment, bool, error) {
 	s.log.Info(ctx, "business.core.stories.GetComments")
 	ctx, span := web.AddSpan(ctx, "business.core.stories.GetComments")
 	defer span.End()
 
-	comments, hasMore, err := s.repo.GetComments(ctx, storyID, page, pageSize)
+	comments, hasMore, err := s.repo.GetComments(ctx, storyID, resolved, page, pageSize)
 	if err != nil {
 		s.log.Error(ctx, fmt.Sprintf("failed to get comments: %s", err))
 		span.RecordError(err)
@@ -931,6 +1146,14 @@ func (s *Service) ListGroupedStories(ctx context.Context, query CoreStoryQuery)
 		if err := s.enrichStoryListEstimates(ctx, query.Filters.WorkspaceID, groups[i].Stories); err != nil {
 			return nil, err
 		}
+		if err := s.addChecklistProgress(ctx, query.Filters.WorkspaceID, groups[i].Stories); err != nil {
+			return nil, err
+		}
+	}
+	if query.GroupBy == "status" {
+		if err := s.addGroupWIP(ctx, query.Filters.WorkspaceID, groups); err != nil {
+			return nil, err
+		}
 	}
 
 	return groups, nil
@@ -948,6 +1171,9 @@ func (s *Service) ListGroupStories(ctx context.Context, groupKey string, query C
 	if err := s.enrichStoryListEstimates(ctx, query.Filters.WorkspaceID, stories); err != nil {
 		return nil, false, err
 	}
+	if err := s.addChecklistProgress(ctx, query.Filters.WorkspaceID, stories); err != nil {
+		return nil, false, err
+	}
 
 	return stories, hasMore, nil
 }
@@ -964,6 +1190,9 @@ func (s *Service) ListByCategory(ctx context.Context, workspaceId, userID, teamI
 	if err := s.enrichStoryListEstimates(ctx, workspaceId, stories); err != nil {
 		return nil, false, err
 	}
+	if err := s.addChecklistProgress(ctx, workspaceId, stories); err != nil {
+		return nil, false, err
+	}
 
 	span.AddEvent("category stories retrieved.", trace.WithAttributes(
 		attribute.Int("stories.count", len(stories)),
@@ -1086,6 +1315,13 @@ func (s *Service) QueryByRef(ctx context.Context, workspaceId uuid.UUID, storyRe
 		span.RecordError(err)
 		return CoreSingleStory{}, err
 	}
+	// A merged duplicate's reference resolves to the canonical story.
+	if story.MergedInto != nil {
+		if story, err = s.repo.Get(ctx, *story.MergedInto, workspaceId); err != nil {
+			span.RecordError(err)
+			return CoreSingleStory{}, err
+		}
+	}
 	if err := s.enrichSingleStoryEstimate(ctx, workspaceId, &story); err != nil {
 		span.RecordError(err)
 		return CoreSingleStory{}, err
@@ -1197,6 +1433,16 @@ func (s *Service) AddAssociation(ctx context.Context, fromID, toID uuid.UUID, as
 	if fromID == toID {
 		return CoreStoryAssociation{}, fmt.Errorf("cannot associate story with itself")
 	}
+	if _, err := s.associationType(ctx, workspaceID, associationType); err != nil {
+		span.RecordError(err)
+		return CoreStoryAssociation{}, err
+	}
+	if associationType == "blocking" {
+		if err := s.checkBlockingCycle(ctx, fromID, toID, workspaceID, uuid.Nil); err != nil {
+			span.RecordError(err)
+			return CoreStoryAssociation{}, err
+		}
+	}
 
 	assoc, err := s.repo.AddAssociation(ctx, fromID, toID, associationType, workspaceID)
 	if err != nil {
@@ -1219,6 +1465,16 @@ func (s *Service) UpdateAssociation(ctx context.Context, associationID, fromID,
 	if fromID == toID {
 		return CoreStoryAssociation{}, fmt.Errorf("cannot associate story with itself")
 	}
+	if _, err := s.associationType(ctx, workspaceID, associationType); err != nil {
+		span.RecordError(err)
+		return CoreStoryAssociation{}, err
+	}
+	if associationType == "blocking" {
+		if err := s.checkBlockingCycle(ctx, fromID, toID, workspaceID, associationID); err != nil {
+			span.RecordError(err)
+			return CoreStoryAssociation{}, err
+		}
+	}
 
 	assoc, err := s.repo.UpdateAssociation(ctx, associationID, fromID, toID, associationType, workspaceID)
 	if err != nil {
@@ -1265,13 +1521,14 @@ const (
 func (s *Service) recordAssociationActivities(ctx context.Context, assoc CoreStoryAssociation, workspaceID uuid.UUID, reason string) error {
 	actorID, _ := auth.GetUserID(ctx)
 	activityReason := reason
-	outgoingOldValue, incomingOldValue := associationOldValues(assoc)
+	assocType := s.associationTypeLabels(ctx, workspaceID, assoc.Type)
+	outgoingOldValue, incomingOldValue := s.associationOldValues(ctx, assoc, workspaceID)
 	activities := []CoreActivity{
 		{
 			StoryID:      assoc.FromStoryID,
 			Type:         "update",
-			Field:        outgoingAssociationActivityField(assoc.Type),
-			CurrentValue: s.associationActivityValue(assoc.ToStoryID, assoc),
+			Field:        outgoingAssociationActivityField(assocType),
+			CurrentValue: s.associationActivityValue(assoc.ToStoryID, assoc, assocType, assocType.ForwardLabel),
 			OldValue:     outgoingOldValue,
 			NewValue:     assoc.ToStoryID,
 			Reason:       &activityReason,
@@ -1281,8 +1538,8 @@ func (s *Service) recordAssociationActivities(ctx context.Context, assoc CoreSto
 		{
 			StoryID:      assoc.ToStoryID,
 			Type:         "update",
-			Field:        incomingAssociationActivityField(assoc.Type),
-			CurrentValue: s.associationActivityValue(assoc.FromStoryID, assoc),
+			Field:        incomingAssociationActivityField(assocType),
+			CurrentValue: s.associationActivityValue(assoc.FromStoryID, assoc, assocType, assocType.BackwardLabel),
 			OldValue:     incomingOldValue,
 			NewValue:     assoc.FromStoryID,
 			Reason:       &activityReason,
@@ -1294,61 +1551,52 @@ func (s *Service) recordAssociationActivities(ctx context.Context, assoc CoreSto
 	return err
 }
 
-func associationOldValues(assoc CoreStoryAssociation) (any, any) {
+func (s *Service) associationOldValues(ctx context.Context, assoc CoreStoryAssociation, workspaceID uuid.UUID) (any, any) {
 	if assoc.PreviousType == nil || *assoc.PreviousType == assoc.Type {
 		return nil, nil
 	}
-	return outgoingAssociationActivityLabel(*assoc.PreviousType), incomingAssociationActivityLabel(*assoc.PreviousType)
+	previous := s.associationTypeLabels(ctx, workspaceID, *assoc.PreviousType)
+	return previous.ForwardLabel, previous.BackwardLabel
 }
 
-func (s *Service) associationActivityValue(storyID uuid.UUID, assoc CoreStoryAssociation) string {
+// associationActivityValue names the other story of an association. The
+// fields of built-in types already say how the stories are linked, so only
+// workspace-defined types put their label in front, as in "causes Login
+// fails".
+func (s *Service) associationActivityValue(storyID uuid.UUID, assoc CoreStoryAssociation, assocType CoreAssociationType, label string) string {
+	value := storyID.String()
 	if assoc.Story.ID == storyID && assoc.Story.Title != "" {
-		return assoc.Story.Title
+		value = assoc.Story.Title
 	}
-	return storyID.String()
+	if assocType.System {
+		return value
+	}
+	return label + " " + value
 }
 
-func outgoingAssociationActivityField(associationType string) string {
-	switch associationType {
-	case "blocking":
+func outgoingAssociationActivityField(assocType CoreAssociationType) string {
+	switch {
+	case assocType.Key == "blocking":
 		return "blocking_id"
-	case "duplicate":
+	case assocType.Key == "duplicate":
 		return "duplicate_id"
-	default:
+	case assocType.System:
 		return "related_id"
+	default:
+		return customAssociationActivityField
 	}
 }
 
-func incomingAssociationActivityField(associationType string) string {
-	switch associationType {
-	case "blocking":
+func incomingAssociationActivityField(assocType CoreAssociationType) string {
+	switch {
+	case assocType.Key == "blocking":
 		return "blocked_by_id"
-	case "duplicate":
+	case assocType.Key == "duplicate":
 		return "duplicated_by_id"
-	default:
+	case assocType.System:
 		return "related_id"
-	}
-}
-
-func outgoingAssociationActivityLabel(associationType string) string {
-	switch associationType {
-	case "blocking":
-		return "Blocks"
-	case "duplicate":
-		return "Duplicate of"
-	default:
-		return "Related to"
-	}
-}
-
-func incomingAssociationActivityLabel(associationType string) string {
-	switch associationType {
-	case "blocking":
-		return "Blocked by"
-	case "duplicate":
-		return "Duplicated by"
 	default:
-		return "Related to"
+		return customAssociationActivityField
 	}
 }
 
@@ -1364,6 +1612,8 @@ func (s *Service) getOldValue(story CoreSingleStory, field string) any {
 		return story.Parent
 	case "objective_id":
 		return story.Objective
+	case "epic_id":
+		return story.Epic
 	case "status_id":
 		return story.Status
 	case "assignee_id":
@@ -0,0 +1,366 @@
+package stories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrNoStoriesToMove      = errors.New("at least one story is required")
+	ErrTargetTeamNotFound   = errors.New("target team not found")
+	ErrInvalidStatusMapping = errors.New("status mapping must map to statuses of the target team")
+)
+
+// CoreTeamStatus is a workflow status of a team.
+type CoreTeamStatus struct {
+	ID         uuid.UUID
+	Category   string
+	IsDefault  bool
+	OrderIndex int
+}
+
+// CoreTeamMove is a story moving to another team. The repository fills in
+// the old and new references and the sprint, which is dropped unless it
+// belongs to the target team. The story leaves its epic, and the values of
+// custom fields owned by another team are dropped.
+type CoreTeamMove struct {
+	StoryID       uuid.UUID
+	WorkspaceID   uuid.UUID
+	FromTeamID    uuid.UUID
+	ToTeamID      uuid.UUID
+	OldTeamCode   string
+	NewTeamCode   string
+	OldSequenceID int
+	NewSequenceID int
+	OldStatusID   *uuid.UUID
+	NewStatusID   *uuid.UUID
+	OldSprintID   *uuid.UUID
+	NewSprintID   *uuid.UUID
+	OldEpicID     *uuid.UUID
+	// DroppedFieldValues holds the dropped custom field values by field.
+	DroppedFieldValues map[uuid.UUID]json.RawMessage
+}
+
+// OldRef is the reference the story had before the move.
+func (m CoreTeamMove) OldRef() string {
+	return fmt.Sprintf("%s-%d", m.OldTeamCode, m.OldSequenceID)
+}
+
+// NewRef is the reference the story has after the move.
+func (m CoreTeamMove) NewRef() string {
+	return fmt.Sprintf("%s-%d", m.NewTeamCode, m.NewSequenceID)
+}
+
+// MoveToTeam moves stories and their sub-stories to another team. Each
+// story gets the next reference of the target team, and its old reference
+// keeps resolving. Statuses follow statusMapping, keyed by the old status,
+// and otherwise move to a target status of the same category. Stories
+// already in the target team are left alone.
+func (s *Service) MoveToTeam(ctx context.Context, workspaceID, actorID uuid.UUID, storyIDs []uuid.UUID, teamID uuid.UUID, statusMapping map[uuid.UUID]uuid.UUID) ([]CoreTeamMove, error) {
+	s.log.Info(ctx, "business.core.stories.MoveToTeam")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.MoveToTeam")
+	defer span.End()
+
+	plan, err := s.planTeamMoves(ctx, workspaceID, actorID, storyIDs, teamID, statusMapping)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	moves, err := s.moveStories(ctx, actorID, plan)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.AddEvent("stories moved to team.", trace.WithAttributes(
+		attribute.String("team.id", teamID.String()),
+		attribute.Int("stories.count", len(moves)),
+	))
+	return moves, nil
+}
+
+// teamMovePlan is a team move worked out but not yet written.
+type teamMovePlan struct {
+	workspaceID uuid.UUID
+	moves       []CoreTeamMove
+	assignees   map[uuid.UUID]*uuid.UUID
+}
+
+// planTeamMoves works out the moves for stories and their sub-stories
+// without writing anything, so callers can validate the result first. Each
+// story enters its new status under the target team's transition rules; the
+// status is picked by the move rather than the user, so teams that let
+// integrations skip the rules let moves skip them too.
+func (s *Service) planTeamMoves(ctx context.Context, workspaceID, actorID uuid.UUID, storyIDs []uuid.UUID, teamID uuid.UUID, statusMapping map[uuid.UUID]uuid.UUID) (teamMovePlan, error) {
+	plan := teamMovePlan{workspaceID: workspaceID, assignees: map[uuid.UUID]*uuid.UUID{}}
+	if len(storyIDs) == 0 {
+		return plan, ErrNoStoriesToMove
+	}
+
+	statuses, err := s.repo.GetTeamStatuses(ctx, workspaceID, teamID)
+	if err != nil {
+		return plan, err
+	}
+	if len(statuses) == 0 {
+		return plan, ErrTargetTeamNotFound
+	}
+	targetStatuses := make(map[uuid.UUID]bool, len(statuses))
+	for _, status := range statuses {
+		targetStatuses[status.ID] = true
+	}
+	for _, statusID := range statusMapping {
+		if !targetStatuses[statusID] {
+			return plan, ErrInvalidStatusMapping
+		}
+	}
+
+	rulesByTeam := make(map[uuid.UUID]*CoreWorkflowRules)
+	var errs []error
+	seen := map[uuid.UUID]bool{}
+	queue := append([]uuid.UUID(nil), storyIDs...)
+	for len(queue) > 0 {
+		storyID := queue[0]
+		queue = queue[1:]
+		if seen[storyID] {
+			continue
+		}
+		seen[storyID] = true
+
+		story, err := s.repo.Get(ctx, storyID, workspaceID)
+		if err != nil {
+			return plan, fmt.Errorf("failed to move story %s: %w", storyID, err)
+		}
+		for _, sub := range story.SubStories {
+			queue = append(queue, sub.ID)
+		}
+		if story.Team == teamID {
+			continue
+		}
+
+		newStatusID, err := s.mapMovedStatus(ctx, story.Status, statuses, statusMapping)
+		if err != nil {
+			return plan, err
+		}
+
+		move := CoreTeamMove{
+			StoryID:     storyID,
+			WorkspaceID: workspaceID,
+			ToTeamID:    teamID,
+			NewStatusID: newStatusID,
+		}
+		if newStatusID != nil {
+			// The old status belongs to the team the story leaves, so the
+			// story enters the target team like a new one.
+			entering := movedStory(story, move)
+			entering.Status = nil
+			if err := s.checkTransition(ctx, workspaceID, actorID, entering, map[string]any{"status_id": *newStatusID}, true, rulesByTeam); err != nil {
+				errs = append(errs, fmt.Errorf("%s-%d: %w", story.TeamCode, story.SequenceID, err))
+			}
+		}
+
+		plan.moves = append(plan.moves, move)
+		plan.assignees[storyID] = story.Assignee
+	}
+	if len(errs) > 0 {
+		return plan, errors.Join(errs...)
+	}
+	return plan, nil
+}
+
+// moved returns the planned move of a story.
+func (p teamMovePlan) moved(storyID uuid.UUID) (CoreTeamMove, bool) {
+	for _, move := range p.moves {
+		if move.StoryID == storyID {
+			return move, true
+		}
+	}
+	return CoreTeamMove{}, false
+}
+
+// moveStories writes a planned team move and records its activities.
+func (s *Service) moveStories(ctx context.Context, actorID uuid.UUID, plan teamMovePlan) ([]CoreTeamMove, error) {
+	if len(plan.moves) == 0 {
+		return []CoreTeamMove{}, nil
+	}
+
+	// The stories move together with their events, so a failure part way
+	// leaves every story where it was.
+	moves, err := s.repo.MoveStoriesToTeam(ctx, plan.moves, func(moves []CoreTeamMove) []events.Event {
+		return teamMoveEvents(moves, plan.assignees, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, move := range moves {
+		s.refreshStorySLA(ctx, move.StoryID, plan.workspaceID, false)
+		if _, err := s.repo.RecordActivities(ctx, s.teamMoveActivities(move, actorID)); err != nil {
+			s.log.Error(ctx, "failed to record team move activities", "story_id", move.StoryID, "error", err)
+		}
+	}
+	return moves, nil
+}
+
+// mapMovedStatus picks the target team status for a story's current status:
+// the mapped one, else the target's default or first status in the same
+// category, else the target's default status.
+func (s *Service) mapMovedStatus(ctx context.Context, current *uuid.UUID, statuses []CoreTeamStatus, statusMapping map[uuid.UUID]uuid.UUID) (*uuid.UUID, error) {
+	category := ""
+	if current != nil {
+		if mapped, ok := statusMapping[*current]; ok {
+			return &mapped, nil
+		}
+		var err error
+		category, err = s.repo.GetStatusCategory(ctx, current.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return matchTeamStatus(category, statuses), nil
+}
+
+// matchTeamStatus returns the status for category from statuses, which are
+// in order. It falls back to the default status, then the first one.
+func matchTeamStatus(category string, statuses []CoreTeamStatus) *uuid.UUID {
+	var match, fallback *CoreTeamStatus
+	for i := range statuses {
+		status := &statuses[i]
+		if status.Category == category && category != "" {
+			if match == nil || status.IsDefault {
+				match = status
+			}
+		}
+		if fallback == nil || status.IsDefault && !fallback.IsDefault {
+			fallback = status
+		}
+	}
+	if match == nil {
+		match = fallback
+	}
+	if match == nil {
+		return nil
+	}
+	return &match.ID
+}
+
+// teamMoveEvents are the story.updated events written with a team move,
+// one per story with its team, status and sprint changes.
+func teamMoveEvents(moves []CoreTeamMove, assignees map[uuid.UUID]*uuid.UUID, actorID uuid.UUID) []events.Event {
+	now := time.Now()
+	outboxEvents := make([]events.Event, 0, len(moves))
+	for _, move := range moves {
+		updates := map[string]any{"team_id": move.ToTeamID}
+		if !uuidPtrEqual(move.OldStatusID, move.NewStatusID) {
+			updates["status_id"] = move.NewStatusID
+		}
+		if !uuidPtrEqual(move.OldSprintID, move.NewSprintID) {
+			updates["sprint_id"] = move.NewSprintID
+		}
+		if move.OldEpicID != nil {
+			updates["epic_id"] = nil
+		}
+		if len(move.DroppedFieldValues) > 0 {
+			cleared := make(map[uuid.UUID]json.RawMessage, len(move.DroppedFieldValues))
+			for fieldID := range move.DroppedFieldValues {
+				cleared[fieldID] = nil
+			}
+			updates[customFieldsUpdateKey] = cleared
+		}
+		outboxEvents = append(outboxEvents, events.Event{
+			Type: events.StoryUpdated,
+			Payload: events.StoryUpdatedPayload{
+				StoryID:     move.StoryID,
+				WorkspaceID: move.WorkspaceID,
+				Updates:     updates,
+				AssigneeID:  assignees[move.StoryID],
+			},
+			Timestamp: now,
+			ActorID:   actorID,
+		})
+	}
+	return outboxEvents
+}
+
+// teamMoveActivities records the team, status, sprint, epic and custom
+// field changes of a move.
+func (s *Service) teamMoveActivities(move CoreTeamMove, actorID uuid.UUID) []CoreActivity {
+	reason := normalizeActivityReason(fmt.Sprintf("Moved from %s to %s.", move.OldRef(), move.NewRef()))
+	activity := func(field string, oldValue, newValue any) CoreActivity {
+		return CoreActivity{
+			StoryID:      move.StoryID,
+			Type:         "update",
+			Field:        field,
+			CurrentValue: s.formatValue(newValue),
+			OldValue:     oldValue,
+			NewValue:     newValue,
+			Reason:       reason,
+			UserID:       actorID,
+			WorkspaceID:  move.WorkspaceID,
+		}
+	}
+
+	activities := []CoreActivity{
+		activity("team_id", &move.FromTeamID, &move.ToTeamID),
+	}
+	if !uuidPtrEqual(move.OldStatusID, move.NewStatusID) {
+		activities = append(activities, activity("status_id", move.OldStatusID, move.NewStatusID))
+	}
+	if !uuidPtrEqual(move.OldSprintID, move.NewSprintID) {
+		activities = append(activities, activity("sprint_id", move.OldSprintID, move.NewSprintID))
+	}
+	if move.OldEpicID != nil {
+		activities = append(activities, activity("epic_id", move.OldEpicID, nil))
+	}
+	if len(move.DroppedFieldValues) > 0 {
+		cleared := make(map[uuid.UUID]json.RawMessage, len(move.DroppedFieldValues))
+		for fieldID := range move.DroppedFieldValues {
+			cleared[fieldID] = nil
+		}
+		activities = append(activities, customFieldActivities(
+			CoreSingleStory{CustomFields: move.DroppedFieldValues},
+			cleared,
+			nil,
+			activity("", nil, nil),
+		)...)
+	}
+	return activities
+}
+
+// teamUpdateValue reads a team_id update value.
+func teamUpdateValue(value any) (uuid.UUID, bool) {
+	switch v := value.(type) {
+	case uuid.UUID:
+		return v, v != uuid.Nil
+	case *uuid.UUID:
+		if v != nil {
+			return *v, *v != uuid.Nil
+		}
+	}
+	return uuid.Nil, false
+}
+
+func uuidPtrEqual(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// movedStory is story as it will be once move is written. The sprint and
+// epic are dropped because they belong to the team the story leaves.
+func movedStory(story CoreSingleStory, move CoreTeamMove) CoreSingleStory {
+	story.Team = move.ToTeamID
+	story.Status = move.NewStatusID
+	story.Sprint = nil
+	story.Epic = nil
+	return story
+}
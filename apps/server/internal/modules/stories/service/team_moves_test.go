@@ -0,0 +1,39 @@
+package stories
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestMatchTeamStatus(t *testing.T) {
+	backlog, todo, started, review, done := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	statuses := []CoreTeamStatus{
+		{ID: backlog, Category: "backlog"},
+		{ID: todo, Category: "unstarted", IsDefault: true},
+		{ID: started, Category: "started"},
+		{ID: review, Category: "started"},
+		{ID: done, Category: "completed"},
+	}
+
+	tests := []struct {
+		name     string
+		category string
+		want     uuid.UUID
+	}{
+		{"first status in the category", "started", started},
+		{"only status in the category", "completed", done},
+		{"no matching category uses the default", "cancelled", todo},
+		{"no current status uses the default", "", todo},
+	}
+	for _, tt := range tests {
+		got := matchTeamStatus(tt.category, statuses)
+		if got == nil || *got != tt.want {
+			t.Errorf("%s: got %v, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if got := matchTeamStatus("started", nil); got != nil {
+		t.Errorf("no statuses: got %v, want nil", got)
+	}
+}
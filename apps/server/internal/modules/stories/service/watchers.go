@@ -0,0 +1,102 @@
+package stories
+
+import (
+	"context"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Watch sources record how a user came to watch a story.
+const (
+	WatchSourceManual  = "manual"
+	WatchSourceComment = "comment"
+	WatchSourceMention = "mention"
+)
+
+// CoreStoryWatcher is a user subscribed to a story's updates and comments.
+type CoreStoryWatcher struct {
+	UserID    uuid.UUID
+	Source    string
+	CreatedAt time.Time
+}
+
+// ListWatchers returns a story's watchers, oldest first.
+func (s *Service) ListWatchers(ctx context.Context, storyID, workspaceID uuid.UUID) ([]CoreStoryWatcher, error) {
+	s.log.Info(ctx, "business.core.stories.ListWatchers")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.ListWatchers")
+	defer span.End()
+
+	watchers, err := s.repo.ListWatchers(ctx, storyID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return watchers, nil
+}
+
+// WatcherIDs returns the IDs of a story's watchers.
+func (s *Service) WatcherIDs(ctx context.Context, storyID, workspaceID uuid.UUID) ([]uuid.UUID, error) {
+	watchers, err := s.ListWatchers(ctx, storyID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(watchers))
+	for i, watcher := range watchers {
+		ids[i] = watcher.UserID
+	}
+	return ids, nil
+}
+
+// Watch subscribes a user to a story. Watching a story twice is not an error.
+func (s *Service) Watch(ctx context.Context, storyID, workspaceID, userID uuid.UUID) error {
+	s.log.Info(ctx, "business.core.stories.Watch")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.Watch")
+	defer span.End()
+
+	if err := s.repo.AddWatchers(ctx, storyID, workspaceID, []uuid.UUID{userID}, WatchSourceManual); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	span.AddEvent("story watched.", trace.WithAttributes(
+		attribute.String("story.id", storyID.String()),
+	))
+	return nil
+}
+
+// Unwatch unsubscribes a user from a story. They are subscribed again if
+// they later comment or are mentioned.
+func (s *Service) Unwatch(ctx context.Context, storyID, workspaceID, userID uuid.UUID) error {
+	s.log.Info(ctx, "business.core.stories.Unwatch")
+	ctx, span := web.AddSpan(ctx, "business.services.stories.Unwatch")
+	defer span.End()
+
+	if err := s.repo.RemoveWatcher(ctx, storyID, workspaceID, userID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	span.AddEvent("story unwatched.", trace.WithAttributes(
+		attribute.String("story.id", storyID.String()),
+	))
+	return nil
+}
+
+// watchOnComment subscribes a comment's author and the users it mentions.
+// Failures are logged so they never fail the comment.
+func (s *Service) watchOnComment(ctx context.Context, storyID, workspaceID, authorID uuid.UUID, mentions []uuid.UUID) {
+	if authorID != uuid.Nil {
+		if err := s.repo.AddWatchers(ctx, storyID, workspaceID, []uuid.UUID{authorID}, WatchSourceComment); err != nil {
+			s.log.Error(ctx, "failed to subscribe comment author", "error", err, "storyId", storyID)
+		}
+	}
+	if len(mentions) > 0 {
+		if err := s.repo.AddWatchers(ctx, storyID, workspaceID, mentions, WatchSourceMention); err != nil {
+			s.log.Error(ctx, "failed to subscribe mentioned users", "error", err, "storyId", storyID)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrWIPLimitReached = errors.New("WIP limit reached")
+)
+
+// WIP limit enforcement modes.
+const (
+	WIPEnforcementWarn  = "warn"
+	WIPEnforcementBlock = "block"
+)
+
+// CoreStatusWIP is a status's WIP limit with its current story counts.
+// AssigneeCounts leaves out unassigned stories.
+type CoreStatusWIP struct {
+	StatusID             uuid.UUID
+	StatusName           string
+	TeamID               uuid.UUID
+	Limit                int
+	PerAssignee          bool
+	Enforcement          string
+	IntegrationsOverride bool
+	Count                int
+	AssigneeCounts       map[uuid.UUID]int
+}
+
+// overLimitAssignees returns the assignees holding more stories than a
+// per-assignee limit allows.
+func (w CoreStatusWIP) overLimitAssignees() []uuid.UUID {
+	var over []uuid.UUID
+	if !w.PerAssignee {
+		return over
+	}
+	for assigneeID, count := range w.AssigneeCounts {
+		if count > w.Limit {
+			over = append(over, assigneeID)
+		}
+	}
+	slices.SortFunc(over, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	return over
+}
+
+// CoreStoryGroupWIP is the WIP limit of a status group. Breached is set
+// when the status, or with PerAssignee one of its assignees, is over the
+// limit.
+type CoreStoryGroupWIP struct {
+	Limit              int
+	PerAssignee        bool
+	Count              int
+	Breached           bool
+	OverLimitAssignees []uuid.UUID
+}
+
+// CoreWIPBreach is a WIP limit that a move goes over. Count is the number
+// of stories after the move, for AssigneeID when the limit is per assignee.
+type CoreWIPBreach struct {
+	StatusID    uuid.UUID
+	StatusName  string
+	AssigneeID  *uuid.UUID
+	Limit       int
+	Count       int
+	Enforcement string
+}
+
+// Message describes the breach.
+func (b CoreWIPBreach) Message() string {
+	if b.AssigneeID != nil {
+		return fmt.Sprintf("%s allows %d %s per assignee and the assignee would have %d", b.StatusName, b.Limit, storiesWord(b.Limit), b.Count)
+	}
+	return fmt.Sprintf("%s allows %d %s and would have %d", b.StatusName, b.Limit, storiesWord(b.Limit), b.Count)
+}
+
+func storiesWord(n int) string {
+	if n == 1 {
+		return "story"
+	}
+	return "stories"
+}
+
+// wipMove is a story's status and assignee before and after an update.
+type wipMove struct {
+	FromStatus   *uuid.UUID
+	ToStatus     *uuid.UUID
+	FromAssignee *uuid.UUID
+	ToAssignee   *uuid.UUID
+}
+
+type wipBucket struct {
+	status   uuid.UUID
+	assignee uuid.UUID
+}
+
+// storyWIPMove returns how an update moves a story between statuses and
+// assignees, and false when it does neither.
+func storyWIPMove(story CoreSingleStory, updates map[string]any) (wipMove, bool) {
+	move := wipMove{
+		FromStatus:   story.Status,
+		ToStatus:     story.Status,
+		FromAssignee: story.Assignee,
+		ToAssignee:   story.Assignee,
+	}
+	if value, ok := updates["status_id"]; ok {
+		move.ToStatus = nil
+		if id, ok := teamUpdateValue(value); ok {
+			move.ToStatus = &id
+		}
+	}
+	if value, ok := updates["assignee_id"]; ok {
+		move.ToAssignee = nil
+		if id, ok := teamUpdateValue(value); ok {
+			move.ToAssignee = &id
+		}
+	}
+	return move, !uuidPtrEqual(move.FromStatus, move.ToStatus) || !uuidPtrEqual(move.FromAssignee, move.ToAssignee)
+}
+
+// wipBreaches applies moves to the current counts and returns the limits
+// that statuses or assignees entered by a move go over, in the order the
+// moves entered them.
+func wipBreaches(limits map[uuid.UUID]CoreStatusWIP, moves []wipMove) []CoreWIPBreach {
+	totals := make(map[uuid.UUID]int, len(limits))
+	perAssignee := make(map[wipBucket]int)
+	for statusID, limit := range limits {
+		totals[statusID] = limit.Count
+		for assigneeID, count := range limit.AssigneeCounts {
+			perAssignee[wipBucket{statusID, assigneeID}] = count
+		}
+	}
+
+	var entered []wipBucket
+	seen := make(map[wipBucket]bool)
+	enter := func(bucket wipBucket) {
+		if !seen[bucket] {
+			seen[bucket] = true
+			entered = append(entered, bucket)
+		}
+	}
+
+	for _, move := range moves {
+		if move.FromStatus != nil {
+			if _, ok := limits[*move.FromStatus]; ok {
+				totals[*move.FromStatus]--
+				if move.FromAssignee != nil {
+					perAssignee[wipBucket{*move.FromStatus, *move.FromAssignee}]--
+				}
+			}
+		}
+		if move.ToStatus == nil {
+			continue
+		}
+		limit, ok := limits[*move.ToStatus]
+		if !ok {
+			continue
+		}
+		totals[*move.ToStatus]++
+		if move.ToAssignee != nil {
+			perAssignee[wipBucket{*move.ToStatus, *move.ToAssignee}]++
+		}
+
+		statusChanged := !uuidPtrEqual(move.FromStatus, move.ToStatus)
+		if limit.PerAssignee {
+			if move.ToAssignee != nil && (statusChanged || !uuidPtrEqual(move.FromAssignee, move.ToAssignee)) {
+				enter(wipBucket{*move.ToStatus, *move.ToAssignee})
+			}
+		} else if statusChanged {
+			enter(wipBucket{status: *move.ToStatus})
+		}
+	}
+
+	var breaches []CoreWIPBreach
+	for _, bucket := range entered {
+		limit := limits[bucket.status]
+		breach := CoreWIPBreach{
+			StatusID:    bucket.status,
+			StatusName:  limit.StatusName,
+			Limit:       limit.Limit,
+			Enforcement: limit.Enforcement,
+		}
+		if limit.PerAssignee {
+			assigneeID := bucket.assignee
+			breach.AssigneeID = &assigneeID
+			breach.Count = perAssignee[bucket]
+		} else {
+			breach.Count = totals[bucket.status]
+		}
+		if breach.Count > breach.Limit {
+			breaches = append(breaches, breach)
+		}
+	}
+	return breaches
+}
+
+// checkWIPLimits returns the WIP limits that moves go over. Limits that
+// block fail the moves with ErrWIPLimitReached, unless the moves come from
+// an integration and the team lets integrations override its rules.
+func (s *Service) checkWIPLimits(ctx context.Context, workspaceID uuid.UUID, moves []wipMove, external bool) ([]CoreWIPBreach, error) {
+	statusIDs := wipStatusIDs(moves)
+	if len(statusIDs) == 0 {
+		return nil, nil
+	}
+
+	limits, err := s.repo.GetWIPLimits(ctx, workspaceID, statusIDs)
+	if err != nil {
+		return nil, err
+	}
+	return enforceWIPLimits(limits, moves, external)
+}
+
+// wipStatusIDs returns the statuses moves enter, in the order they do.
+func wipStatusIDs(moves []wipMove) []uuid.UUID {
+	var statusIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, move := range moves {
+		if move.ToStatus != nil && !seen[*move.ToStatus] {
+			seen[*move.ToStatus] = true
+			statusIDs = append(statusIDs, *move.ToStatus)
+		}
+	}
+	return statusIDs
+}
+
+// enforceWIPLimits is checkWIPLimits on limits already read.
+func enforceWIPLimits(limits []CoreStatusWIP, moves []wipMove, external bool) ([]CoreWIPBreach, error) {
+	if len(limits) == 0 {
+		return nil, nil
+	}
+	byStatus := make(map[uuid.UUID]CoreStatusWIP, len(limits))
+	for _, limit := range limits {
+		byStatus[limit.StatusID] = limit
+	}
+
+	var warnings []CoreWIPBreach
+	var blocked []error
+	for _, breach := range wipBreaches(byStatus, moves) {
+		limit := byStatus[breach.StatusID]
+		if breach.Enforcement == WIPEnforcementBlock && !(external && limit.IntegrationsOverride) {
+			blocked = append(blocked, fmt.Errorf("%w: %s", ErrWIPLimitReached, breach.Message()))
+			continue
+		}
+		warnings = append(warnings, breach)
+	}
+	if len(blocked) > 0 {
+		return nil, errors.Join(blocked...)
+	}
+	return warnings, nil
+}
+
+// wipWrite makes write check that move stays within blocking WIP limits
+// when the story is written. The limits are read again in the write's
+// transaction with their rows locked, so two moves into a full status
+// cannot both pass an earlier check.
+func wipWrite(write *CoreStoryWrite, move wipMove, external bool) {
+	write.WIPStatusIDs = wipStatusIDs([]wipMove{move})
+	if len(write.WIPStatusIDs) == 0 {
+		return
+	}
+	write.CheckWIP = func(limits []CoreStatusWIP) error {
+		_, err := enforceWIPLimits(limits, []wipMove{move}, external)
+		return err
+	}
+}
+
+// addGroupWIP sets the WIP limits of stories grouped by status.
+func (s *Service) addGroupWIP(ctx context.Context, workspaceID uuid.UUID, groups []CoreStoryGroup) error {
+	ctx, span := web.AddSpan(ctx, "business.services.stories.addGroupWIP")
+	defer span.End()
+
+	statusIDs := make([]uuid.UUID, 0, len(groups))
+	for _, group := range groups {
+		if id, err := uuid.Parse(group.Key); err == nil {
+			statusIDs = append(statusIDs, id)
+		}
+	}
+	if len(statusIDs) == 0 {
+		return nil
+	}
+
+	limits, err := s.repo.GetWIPLimits(ctx, workspaceID, statusIDs)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	byStatus := make(map[string]CoreStatusWIP, len(limits))
+	for _, limit := range limits {
+		byStatus[limit.StatusID.String()] = limit
+	}
+
+	for i := range groups {
+		limit, ok := byStatus[groups[i].Key]
+		if !ok {
+			continue
+		}
+		over := limit.overLimitAssignees()
+		groups[i].WIP = &CoreStoryGroupWIP{
+			Limit:              limit.Limit,
+			PerAssignee:        limit.PerAssignee,
+			Count:              limit.Count,
+			Breached:           len(over) > 0 || !limit.PerAssignee && limit.Count > limit.Limit,
+			OverLimitAssignees: over,
+		}
+	}
+
+	span.AddEvent("group WIP limits added", trace.WithAttributes(
+		attribute.Int("limits.count", len(limits)),
+	))
+	return nil
+}
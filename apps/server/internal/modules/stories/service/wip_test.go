@@ -0,0 +1,181 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/complexus-tech/projects-api/internal/platform/auth"
+	"github.com/google/uuid"
+)
+
+func TestWIPBreaches(t *testing.T) {
+	todo, doing := uuid.New(), uuid.New()
+	alice, bob := uuid.New(), uuid.New()
+
+	statusLimit := CoreStatusWIP{StatusID: doing, StatusName: "In Progress", Limit: 2, Count: 2, Enforcement: WIPEnforcementWarn}
+	assigneeLimit := CoreStatusWIP{
+		StatusID:       doing,
+		StatusName:     "In Progress",
+		Limit:          1,
+		PerAssignee:    true,
+		Count:          2,
+		AssigneeCounts: map[uuid.UUID]int{alice: 1, bob: 0},
+	}
+
+	tests := []struct {
+		name  string
+		limit CoreStatusWIP
+		moves []wipMove
+		want  []int
+	}{
+		{
+			name:  "move into a full status",
+			limit: statusLimit,
+			moves: []wipMove{{FromStatus: &todo, ToStatus: &doing}},
+			want:  []int{3},
+		},
+		{
+			name:  "move within the status",
+			limit: statusLimit,
+			moves: []wipMove{{FromStatus: &doing, ToStatus: &doing, FromAssignee: &alice, ToAssignee: &bob}},
+		},
+		{
+			name:  "bulk move counts every story",
+			limit: CoreStatusWIP{StatusID: doing, StatusName: "In Progress", Limit: 3, Count: 1},
+			moves: []wipMove{
+				{FromStatus: &todo, ToStatus: &doing},
+				{FromStatus: &todo, ToStatus: &doing},
+				{FromStatus: &todo, ToStatus: &doing},
+			},
+			want: []int{4},
+		},
+		{
+			name:  "assignee at the limit",
+			limit: assigneeLimit,
+			moves: []wipMove{{FromStatus: &todo, ToStatus: &doing, FromAssignee: &alice, ToAssignee: &alice}},
+			want:  []int{2},
+		},
+		{
+			name:  "assignee under the limit",
+			limit: assigneeLimit,
+			moves: []wipMove{{FromStatus: &todo, ToStatus: &doing, FromAssignee: &bob, ToAssignee: &bob}},
+		},
+		{
+			name:  "reassignment within the status",
+			limit: assigneeLimit,
+			moves: []wipMove{{FromStatus: &doing, ToStatus: &doing, FromAssignee: &bob, ToAssignee: &alice}},
+			want:  []int{2},
+		},
+		{
+			name:  "unassigned stories are not counted per assignee",
+			limit: assigneeLimit,
+			moves: []wipMove{{FromStatus: &todo, ToStatus: &doing}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaches := wipBreaches(map[uuid.UUID]CoreStatusWIP{doing: tt.limit}, tt.moves)
+			if len(breaches) != len(tt.want) {
+				t.Fatalf("got %d breaches, want %d: %+v", len(breaches), len(tt.want), breaches)
+			}
+			for i, count := range tt.want {
+				if breaches[i].Count != count {
+					t.Errorf("breach %d: got count %d, want %d", i, breaches[i].Count, count)
+				}
+				if (breaches[i].AssigneeID != nil) != tt.limit.PerAssignee {
+					t.Errorf("breach %d: got assignee %v, want per assignee %v", i, breaches[i].AssigneeID, tt.limit.PerAssignee)
+				}
+			}
+		})
+	}
+}
+
+func TestStoryWIPMove(t *testing.T) {
+	todo, doing := uuid.New(), uuid.New()
+	assignee := uuid.New()
+	story := CoreSingleStory{Status: &todo, Assignee: &assignee}
+
+	if _, ok := storyWIPMove(story, map[string]any{"title": "New title"}); ok {
+		t.Error("expected no move for an update without status or assignee")
+	}
+	if _, ok := storyWIPMove(story, map[string]any{"status_id": todo}); ok {
+		t.Error("expected no move for an unchanged status")
+	}
+
+	move, ok := storyWIPMove(story, map[string]any{"status_id": doing, "assignee_id": nil})
+	if !ok {
+		t.Fatal("expected a move")
+	}
+	if move.ToStatus == nil || *move.ToStatus != doing {
+		t.Errorf("got to status %v, want %s", move.ToStatus, doing)
+	}
+	if move.ToAssignee != nil {
+		t.Errorf("got to assignee %v, want none", move.ToAssignee)
+	}
+}
+
+func TestUpdateWIPEnforcement(t *testing.T) {
+	todo, doing := uuid.New(), uuid.New()
+	limit := CoreStatusWIP{StatusID: doing, StatusName: "In Progress", Limit: 1, Count: 1}
+
+	t.Run("warn", func(t *testing.T) {
+		limit.Enforcement = WIPEnforcementWarn
+		repo := &activityRecordingRepo{
+			story:     CoreSingleStory{ID: uuid.New(), Status: &todo},
+			wipLimits: []CoreStatusWIP{limit},
+		}
+		service := newActivityRecordingService(repo)
+
+		ctx := auth.SetUserID(context.Background(), uuid.New())
+		warnings, err := service.Update(ctx, repo.story.ID, uuid.New(), map[string]any{"status_id": doing})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(warnings) != 1 || warnings[0].Count != 2 {
+			t.Errorf("got warnings %+v, want one breach with 2 stories", warnings)
+		}
+		if repo.updates == nil {
+			t.Error("expected the story to be updated")
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+		limit.Enforcement = WIPEnforcementBlock
+		repo := &activityRecordingRepo{
+			story:     CoreSingleStory{ID: uuid.New(), Status: &todo},
+			wipLimits: []CoreStatusWIP{limit},
+		}
+		service := newActivityRecordingService(repo)
+
+		ctx := auth.SetUserID(context.Background(), uuid.New())
+		_, err := service.Update(ctx, repo.story.ID, uuid.New(), map[string]any{"status_id": doing})
+		if !errors.Is(err, ErrWIPLimitReached) {
+			t.Fatalf("got error %v, want ErrWIPLimitReached", err)
+		}
+		if repo.updates != nil {
+			t.Errorf("expected no update, got %v", repo.updates)
+		}
+	})
+	t.Run("block on the locked count", func(t *testing.T) {
+		limit.Enforcement = WIPEnforcementBlock
+		stale := limit
+		stale.Count = 0
+		repo := &activityRecordingRepo{
+			story:           CoreSingleStory{ID: uuid.New(), Status: &todo},
+			wipLimits:       []CoreStatusWIP{stale},
+			lockedWIPLimits: []CoreStatusWIP{limit},
+		}
+		service := newActivityRecordingService(repo)
+
+		ctx := auth.SetUserID(context.Background(), uuid.New())
+		_, err := service.Update(ctx, repo.story.ID, uuid.New(), map[string]any{"status_id": doing})
+		if !errors.Is(err, ErrWIPLimitReached) {
+			t.Fatalf("got error %v, want ErrWIPLimitReached", err)
+		}
+		if repo.updates != nil {
+			t.Errorf("expected no update, got %v", repo.updates)
+		}
+	})
+}
@@ -0,0 +1,261 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrTransitionNotAllowed    = errors.New("status transition is not allowed")
+	ErrTransitionForbidden     = errors.New("status transition is not allowed for your role")
+	ErrTransitionMissingFields = errors.New("story is missing fields required by the status")
+)
+
+// workflowFieldKeys maps the fields a status category can require to the
+// story update keys that hold them.
+var workflowFieldKeys = map[string]string{
+	"assignee":    "assignee_id",
+	"estimate":    "estimate_unit",
+	"sprint":      "sprint_id",
+	"start_date":  "start_date",
+	"deadline":    "end_date",
+	"epic":        "epic_id",
+	"objective":   "objective_id",
+	"description": "description",
+}
+
+// workflowFieldLabels name required fields in error messages.
+var workflowFieldLabels = map[string]string{
+	"assignee":    "an assignee",
+	"estimate":    "an estimate",
+	"sprint":      "a sprint",
+	"start_date":  "a start date",
+	"deadline":    "a deadline",
+	"epic":        "an epic",
+	"objective":   "an objective",
+	"description": "a description",
+}
+
+// CoreWorkflowStatus is a team status as seen by the transition rules.
+type CoreWorkflowStatus struct {
+	ID       uuid.UUID
+	Name     string
+	Category string
+}
+
+// CoreWorkflowTransition allows a move between two statuses. When
+// AllowedRoles is set, only users with one of those workspace roles may
+// make it.
+type CoreWorkflowTransition struct {
+	FromStatusID uuid.UUID
+	ToStatusID   uuid.UUID
+	AllowedRoles []string
+}
+
+// CoreWorkflowRules are a team's status transition rules. Requirements map
+// a status category to the fields a story needs before entering it.
+type CoreWorkflowRules struct {
+	RestrictTransitions  bool
+	IntegrationsOverride bool
+	Statuses             map[uuid.UUID]CoreWorkflowStatus
+	Transitions          []CoreWorkflowTransition
+	Requirements         map[string][]string
+}
+
+// empty reports whether the rules never reject a move.
+func (r CoreWorkflowRules) empty() bool {
+	return !r.RestrictTransitions && len(r.Transitions) == 0 && len(r.Requirements) == 0
+}
+
+func (r CoreWorkflowRules) transition(from, to uuid.UUID) *CoreWorkflowTransition {
+	for i := range r.Transitions {
+		if r.Transitions[i].FromStatusID == from && r.Transitions[i].ToStatusID == to {
+			return &r.Transitions[i]
+		}
+	}
+	return nil
+}
+
+func (r CoreWorkflowRules) statusName(id uuid.UUID) string {
+	if status, ok := r.Statuses[id]; ok {
+		return status.Name
+	}
+	return id.String()
+}
+
+// evaluateTransition checks a move from one status to another against the
+// rules. A story without a status may move anywhere. Role is the workspace
+// role of the user making the move, and values holds the story's fields by
+// update key as they will be after the move.
+func evaluateTransition(rules CoreWorkflowRules, from *uuid.UUID, to uuid.UUID, role string, values map[string]any) error {
+	if from != nil && *from != to {
+		transition := rules.transition(*from, to)
+		if transition == nil && rules.RestrictTransitions {
+			var allowed []string
+			for _, t := range rules.Transitions {
+				if t.FromStatusID == *from {
+					allowed = append(allowed, rules.statusName(t.ToStatusID))
+				}
+			}
+			if len(allowed) == 0 {
+				return fmt.Errorf("%w: stories cannot leave %s", ErrTransitionNotAllowed, rules.statusName(*from))
+			}
+			return fmt.Errorf("%w: stories in %s can only move to %s", ErrTransitionNotAllowed, rules.statusName(*from), joinWords(allowed, "or"))
+		}
+		if transition != nil && len(transition.AllowedRoles) > 0 && !slices.Contains(transition.AllowedRoles, role) {
+			return fmt.Errorf("%w: only %s can move stories from %s to %s", ErrTransitionForbidden,
+				joinWords(pluralRoles(transition.AllowedRoles), "and"), rules.statusName(*from), rules.statusName(to))
+		}
+	}
+
+	status, ok := rules.Statuses[to]
+	if !ok {
+		return nil
+	}
+	var missing []string
+	for _, field := range rules.Requirements[status.Category] {
+		if isBlankValue(values[workflowFieldKeys[field]]) {
+			missing = append(missing, workflowFieldLabels[field])
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: stories need %s before moving to %s", ErrTransitionMissingFields, joinWords(missing, "and"), status.Name)
+	}
+	return nil
+}
+
+// isBlankValue reports whether an update value leaves a field empty.
+func isBlankValue(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+	switch val := v.Interface().(type) {
+	case string:
+		return strings.TrimSpace(val) == ""
+	case uuid.UUID:
+		return val == uuid.Nil
+	}
+	return false
+}
+
+func pluralRoles(roles []string) []string {
+	plural := make([]string, len(roles))
+	for i, role := range roles {
+		plural[i] = role + "s"
+	}
+	return plural
+}
+
+// joinWords joins words as in "a, b and c".
+func joinWords(words []string, conjunction string) string {
+	if len(words) < 2 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " " + conjunction + " " + words[len(words)-1]
+}
+
+// checkTransition applies the team's transition rules to a status change
+// in updates. Moves made by integrations skip the rules when the team
+// allows it.
+func (s *Service) checkTransition(ctx context.Context, workspaceID, actorID uuid.UUID, story CoreSingleStory, updates map[string]any, external bool, rulesByTeam map[uuid.UUID]*CoreWorkflowRules) error {
+	value, ok := updates["status_id"]
+	if !ok {
+		return nil
+	}
+	to, ok := teamUpdateValue(value)
+	if !ok || (story.Status != nil && *story.Status == to) {
+		return nil
+	}
+
+	rules := rulesByTeam[story.Team]
+	if rules == nil {
+		var err error
+		rules, err = s.repo.GetWorkflowRules(ctx, workspaceID, story.Team)
+		if err != nil {
+			return err
+		}
+		rulesByTeam[story.Team] = rules
+	}
+	if rules.empty() || (external && rules.IntegrationsOverride) {
+		return nil
+	}
+
+	role := ""
+	if story.Status != nil {
+		if transition := rules.transition(*story.Status, to); transition != nil && len(transition.AllowedRoles) > 0 {
+			var err error
+			if role, err = s.repo.GetWorkspaceRole(ctx, workspaceID, actorID); err != nil {
+				return err
+			}
+		}
+	}
+
+	values := make(map[string]any, len(workflowFieldKeys))
+	for _, key := range workflowFieldKeys {
+		if value, ok := updates[key]; ok {
+			values[key] = value
+		} else {
+			values[key] = s.getOldValue(story, key)
+		}
+	}
+	return evaluateTransition(*rules, story.Status, to, role, values)
+}
+
+// checkBulkUpdate checks every story's status change and WIP limits before
+// a bulk update so that either all stories move or none do. It returns the
+// WIP limits the moves go over when the teams only warn.
+func (s *Service) checkBulkUpdate(ctx context.Context, storyIDs []uuid.UUID, workspaceID, actorID uuid.UUID, updates map[string]any) ([]CoreWIPBreach, error) {
+	ctx, span := web.AddSpan(ctx, "business.core.stories.checkBulkUpdate")
+	defer span.End()
+
+	_, statusChange := updates["status_id"]
+	_, assigneeChange := updates["assignee_id"]
+	if !statusChange && !assigneeChange {
+		return nil, nil
+	}
+
+	rulesByTeam := make(map[uuid.UUID]*CoreWorkflowRules)
+	var moves []wipMove
+	var errs []error
+	for _, storyID := range storyIDs {
+		story, err := s.repo.Get(ctx, storyID, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkTransition(ctx, workspaceID, actorID, story, updates, false, rulesByTeam); err != nil {
+			errs = append(errs, fmt.Errorf("%s-%d: %w", story.TeamCode, story.SequenceID, err))
+		}
+		if move, ok := storyWIPMove(story, updates); ok {
+			moves = append(moves, move)
+		}
+	}
+	if len(errs) > 0 {
+		span.RecordError(errors.New("bulk status transitions rejected"), trace.WithAttributes(
+			attribute.Int("stories.rejected", len(errs)),
+		))
+		return nil, errors.Join(errs...)
+	}
+
+	warnings, err := s.checkWIPLimits(ctx, workspaceID, moves, false)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return warnings, nil
+}
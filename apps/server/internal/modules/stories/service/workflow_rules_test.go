@@ -0,0 +1,203 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/complexus-tech/projects-api/internal/platform/auth"
+	"github.com/google/uuid"
+)
+
+type workflowTestStatuses struct {
+	todo, doing, review, done uuid.UUID
+}
+
+func newWorkflowTestRules() (CoreWorkflowRules, workflowTestStatuses) {
+	ids := workflowTestStatuses{todo: uuid.New(), doing: uuid.New(), review: uuid.New(), done: uuid.New()}
+	rules := CoreWorkflowRules{
+		RestrictTransitions: true,
+		Statuses: map[uuid.UUID]CoreWorkflowStatus{
+			ids.todo:   {ID: ids.todo, Name: "Todo", Category: "unstarted"},
+			ids.doing:  {ID: ids.doing, Name: "In Progress", Category: "started"},
+			ids.review: {ID: ids.review, Name: "In Review", Category: "started"},
+			ids.done:   {ID: ids.done, Name: "Done", Category: "completed"},
+		},
+		Transitions: []CoreWorkflowTransition{
+			{FromStatusID: ids.todo, ToStatusID: ids.doing},
+			{FromStatusID: ids.doing, ToStatusID: ids.review},
+			{FromStatusID: ids.review, ToStatusID: ids.done, AllowedRoles: []string{"admin"}},
+		},
+		Requirements: map[string][]string{
+			"started":   {"estimate"},
+			"completed": {"assignee"},
+		},
+	}
+	return rules, ids
+}
+
+func TestEvaluateTransition(t *testing.T) {
+	rules, ids := newWorkflowTestRules()
+	assignee := uuid.New()
+	estimate := int16(3)
+
+	tests := []struct {
+		name   string
+		from   uuid.UUID
+		to     uuid.UUID
+		role   string
+		values map[string]any
+		want   error
+		msg    string
+	}{
+		{name: "allowed", from: ids.todo, to: ids.doing, role: "member", values: map[string]any{"estimate_unit": &estimate}},
+		{name: "not listed", from: ids.todo, to: ids.done, role: "admin", want: ErrTransitionNotAllowed,
+			msg: "status transition is not allowed: stories in Todo can only move to In Progress"},
+		{name: "role", from: ids.review, to: ids.done, role: "member", values: map[string]any{"assignee_id": &assignee}, want: ErrTransitionForbidden,
+			msg: "status transition is not allowed for your role: only admins can move stories from In Review to Done"},
+		{name: "missing estimate", from: ids.todo, to: ids.doing, role: "member", values: map[string]any{"estimate_unit": (*int16)(nil)}, want: ErrTransitionMissingFields,
+			msg: "story is missing fields required by the status: stories need an estimate before moving to In Progress"},
+		{name: "admin with assignee", from: ids.review, to: ids.done, role: "admin", values: map[string]any{"assignee_id": &assignee}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := tt.from
+			err := evaluateTransition(rules, &from, tt.to, tt.role, tt.values)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+			if tt.msg != "" && err.Error() != tt.msg {
+				t.Errorf("got message %q, want %q", err, tt.msg)
+			}
+		})
+	}
+}
+
+func TestEvaluateTransitionWithoutRestriction(t *testing.T) {
+	rules, ids := newWorkflowTestRules()
+	rules.RestrictTransitions = false
+	rules.Requirements = nil
+
+	// Unlisted moves are allowed, but listed ones keep their roles.
+	if err := evaluateTransition(rules, &ids.todo, ids.done, "guest", nil); err != nil {
+		t.Errorf("unlisted move: got error %v", err)
+	}
+	if err := evaluateTransition(rules, &ids.review, ids.done, "guest", nil); !errors.Is(err, ErrTransitionForbidden) {
+		t.Errorf("listed move: got error %v, want ErrTransitionForbidden", err)
+	}
+	// A story without a status may move anywhere.
+	if err := evaluateTransition(rules, nil, ids.done, "guest", nil); err != nil {
+		t.Errorf("no status: got error %v", err)
+	}
+}
+
+func TestIsBlankValue(t *testing.T) {
+	empty, text := "  ", "spec"
+	id := uuid.New()
+	for _, value := range []any{nil, (*uuid.UUID)(nil), uuid.Nil, &empty, ""} {
+		if !isBlankValue(value) {
+			t.Errorf("%#v should be blank", value)
+		}
+	}
+	for _, value := range []any{&text, id, &id, int16(0)} {
+		if isBlankValue(value) {
+			t.Errorf("%#v should not be blank", value)
+		}
+	}
+}
+
+func TestUpdateRejectsForbiddenTransition(t *testing.T) {
+	rules, ids := newWorkflowTestRules()
+	assignee := uuid.New()
+	repo := &activityRecordingRepo{
+		story:         CoreSingleStory{ID: uuid.New(), Status: &ids.review, Assignee: &assignee},
+		workflowRules: &rules,
+		workspaceRole: "member",
+	}
+	service := newActivityRecordingService(repo)
+
+	ctx := auth.SetUserID(context.Background(), uuid.New())
+	_, err := service.Update(ctx, repo.story.ID, uuid.New(), map[string]any{"status_id": ids.done})
+	if !errors.Is(err, ErrTransitionForbidden) {
+		t.Fatalf("got error %v, want ErrTransitionForbidden", err)
+	}
+	if repo.updates != nil {
+		t.Errorf("expected no update, got %v", repo.updates)
+	}
+}
+
+func TestCheckTransitionIntegrationOverride(t *testing.T) {
+	rules, ids := newWorkflowTestRules()
+	repo := &activityRecordingRepo{workflowRules: &rules}
+	service := newActivityRecordingService(repo)
+	story := CoreSingleStory{ID: uuid.New(), Status: &ids.todo}
+	updates := map[string]any{"status_id": ids.done}
+
+	err := service.checkTransition(context.Background(), uuid.New(), uuid.New(), story, updates, true, map[uuid.UUID]*CoreWorkflowRules{})
+	if !errors.Is(err, ErrTransitionNotAllowed) {
+		t.Fatalf("without override: got error %v, want ErrTransitionNotAllowed", err)
+	}
+
+	rules.IntegrationsOverride = true
+	if err := service.checkTransition(context.Background(), uuid.New(), uuid.New(), story, updates, true, map[uuid.UUID]*CoreWorkflowRules{}); err != nil {
+		t.Errorf("with override: got error %v", err)
+	}
+	if err := service.checkTransition(context.Background(), uuid.New(), uuid.New(), story, updates, false, map[uuid.UUID]*CoreWorkflowRules{}); !errors.Is(err, ErrTransitionNotAllowed) {
+		t.Errorf("user move with override: got error %v, want ErrTransitionNotAllowed", err)
+	}
+}
+
+func TestMoveToTeamChecksTargetTeamRequirements(t *testing.T) {
+	rules, ids := newWorkflowTestRules()
+	oldStatus := uuid.New()
+	repo := &activityRecordingRepo{
+		story:          CoreSingleStory{ID: uuid.New(), Team: uuid.New(), TeamCode: "ENG", SequenceID: 4, Status: &oldStatus},
+		statusCategory: "started",
+		teamStatuses:   []CoreTeamStatus{{ID: ids.todo, Category: "unstarted", IsDefault: true}, {ID: ids.doing, Category: "started"}},
+		workflowRules:  &rules,
+	}
+	service := newActivityRecordingService(repo)
+
+	_, err := service.MoveToTeam(context.Background(), uuid.New(), uuid.New(), []uuid.UUID{repo.story.ID}, uuid.New(), nil)
+	if !errors.Is(err, ErrTransitionMissingFields) {
+		t.Fatalf("got error %v, want ErrTransitionMissingFields", err)
+	}
+	if len(repo.teamMoves) != 0 {
+		t.Fatalf("expected no moves, got %d", len(repo.teamMoves))
+	}
+
+	rules.IntegrationsOverride = true
+	if _, err := service.MoveToTeam(context.Background(), uuid.New(), uuid.New(), []uuid.UUID{repo.story.ID}, uuid.New(), nil); err != nil {
+		t.Fatalf("with override: got error %v", err)
+	}
+	if len(repo.teamMoves) != 1 {
+		t.Fatalf("with override: expected 1 move, got %d", len(repo.teamMoves))
+	}
+}
+
+func TestMergeStoriesChecksCancelTransition(t *testing.T) {
+	rules, ids := newWorkflowTestRules()
+	cancelledID := uuid.New()
+	repo := &activityRecordingRepo{
+		story:         CoreSingleStory{ID: uuid.New(), Status: &ids.todo},
+		teamStatuses:  []CoreTeamStatus{{ID: cancelledID, Category: "cancelled"}},
+		workflowRules: &rules,
+	}
+	service := newActivityRecordingService(repo)
+
+	_, err := service.MergeStories(context.Background(), uuid.New(), uuid.New(), repo.story.ID, uuid.New())
+	if !errors.Is(err, ErrTransitionNotAllowed) {
+		t.Fatalf("got error %v, want ErrTransitionNotAllowed", err)
+	}
+	if len(repo.merges) != 0 {
+		t.Fatalf("expected nothing merged, got %d merges", len(repo.merges))
+	}
+
+	rules.IntegrationsOverride = true
+	if _, err := service.MergeStories(context.Background(), uuid.New(), uuid.New(), repo.story.ID, uuid.New()); err != nil {
+		t.Fatalf("with override: got error %v", err)
+	}
+	if len(repo.merges) != 1 {
+		t.Fatalf("with override: expected 1 merge, got %d", len(repo.merges))
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	teamsettings "github.com/complexus-tech/projects-api/internal/modules/teamsettings/service"
+	"github.com/complexus-tech/projects-api/pkg/date"
+	"github.com/google/uuid"
 )
 
 type AppTeamSprintSettings struct {
@@ -34,10 +36,69 @@ type AppTeamEstimationSettings struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+type AppTeamHoliday struct {
+	Date date.Date `json:"date"`
+	Name string    `json:"name"`
+}
+
+type AppTeamSchedulingSettings struct {
+	CascadeDependencies bool             `json:"cascadeDependencies"`
+	Holidays            []AppTeamHoliday `json:"holidays"`
+	CreatedAt           time.Time        `json:"createdAt"`
+	UpdatedAt           time.Time        `json:"updatedAt"`
+}
+
+type AppTeamSLAPolicy struct {
+	Priority            string  `json:"priority"`
+	FirstResponseTarget *string `json:"firstResponseTarget"`
+	ResolveTarget       *string `json:"resolveTarget"`
+}
+
+type AppTeamSLASettings struct {
+	Timezone      string             `json:"timezone"`
+	WorkdayStart  string             `json:"workdayStart"`
+	WorkdayEnd    string             `json:"workdayEnd"`
+	AtRiskPercent int                `json:"atRiskPercent"`
+	Policies      []AppTeamSLAPolicy `json:"policies"`
+	CreatedAt     time.Time          `json:"createdAt"`
+	UpdatedAt     time.Time          `json:"updatedAt"`
+}
+
+type AppTeamStatusTransition struct {
+	FromStatusID uuid.UUID `json:"fromStatusId"`
+	ToStatusID   uuid.UUID `json:"toStatusId"`
+	AllowedRoles []string  `json:"allowedRoles"`
+}
+
+type AppTeamCategoryRequirement struct {
+	Category       string   `json:"category"`
+	RequiredFields []string `json:"requiredFields"`
+}
+
+type AppTeamWIPLimit struct {
+	StatusID    uuid.UUID `json:"statusId"`
+	MaxStories  int       `json:"maxStories"`
+	PerAssignee bool      `json:"perAssignee"`
+}
+
+type AppTeamWorkflowSettings struct {
+	RestrictTransitions  bool                         `json:"restrictTransitions"`
+	IntegrationsOverride bool                         `json:"integrationsOverride"`
+	WIPEnforcement       string                       `json:"wipEnforcement"`
+	Transitions          []AppTeamStatusTransition    `json:"transitions"`
+	Requirements         []AppTeamCategoryRequirement `json:"requirements"`
+	WIPLimits            []AppTeamWIPLimit            `json:"wipLimits"`
+	CreatedAt            time.Time                    `json:"createdAt"`
+	UpdatedAt            time.Time                    `json:"updatedAt"`
+}
+
 type AppTeamSettings struct {
 	SprintSettings          AppTeamSprintSettings          `json:"sprintSettings"`
 	StoryAutomationSettings AppTeamStoryAutomationSettings `json:"storyAutomationSettings"`
 	EstimationSettings      AppTeamEstimationSettings      `json:"estimationSettings"`
+	SchedulingSettings      AppTeamSchedulingSettings      `json:"schedulingSettings"`
+	SLASettings             AppTeamSLASettings             `json:"slaSettings"`
+	WorkflowSettings        AppTeamWorkflowSettings        `json:"workflowSettings"`
 }
 
 type AppUpdateTeamSprintSettings struct {
@@ -60,6 +121,28 @@ type AppUpdateTeamEstimationSettings struct {
 	Scheme *string `json:"scheme,omitempty"`
 }
 
+type AppUpdateTeamSchedulingSettings struct {
+	CascadeDependencies *bool             `json:"cascadeDependencies,omitempty"`
+	Holidays            *[]AppTeamHoliday `json:"holidays,omitempty"`
+}
+
+type AppUpdateTeamSLASettings struct {
+	Timezone      *string             `json:"timezone,omitempty"`
+	WorkdayStart  *string             `json:"workdayStart,omitempty"`
+	WorkdayEnd    *string             `json:"workdayEnd,omitempty"`
+	AtRiskPercent *int                `json:"atRiskPercent,omitempty"`
+	Policies      *[]AppTeamSLAPolicy `json:"policies,omitempty"`
+}
+
+type AppUpdateTeamWorkflowSettings struct {
+	RestrictTransitions  *bool                         `json:"restrictTransitions,omitempty"`
+	IntegrationsOverride *bool                         `json:"integrationsOverride,omitempty"`
+	WIPEnforcement       *string                       `json:"wipEnforcement,omitempty"`
+	Transitions          *[]AppTeamStatusTransition    `json:"transitions,omitempty"`
+	Requirements         *[]AppTeamCategoryRequirement `json:"requirements,omitempty"`
+	WIPLimits            *[]AppTeamWIPLimit            `json:"wipLimits,omitempty"`
+}
+
 // Conversion functions
 func toAppTeamSprintSettings(settings teamsettings.CoreTeamSprintSettings) AppTeamSprintSettings {
 	return AppTeamSprintSettings{
@@ -92,6 +175,9 @@ func toAppTeamSettings(settings teamsettings.CoreTeamSettings) AppTeamSettings {
 		SprintSettings:          toAppTeamSprintSettings(settings.SprintSettings),
 		StoryAutomationSettings: toAppTeamStoryAutomationSettings(settings.StoryAutomationSettings),
 		EstimationSettings:      toAppTeamEstimationSettings(settings.EstimationSettings),
+		SchedulingSettings:      toAppTeamSchedulingSettings(settings.SchedulingSettings),
+		SLASettings:             toAppTeamSLASettings(settings.SLASettings),
+		WorkflowSettings:        toAppTeamWorkflowSettings(settings.WorkflowSettings),
 	}
 }
 
@@ -128,3 +214,154 @@ func toCoreUpdateTeamEstimationSettings(app AppUpdateTeamEstimationSettings) tea
 		Scheme: app.Scheme,
 	}
 }
+
+func toAppTeamSchedulingSettings(settings teamsettings.CoreTeamSchedulingSettings) AppTeamSchedulingSettings {
+	holidays := make([]AppTeamHoliday, len(settings.Holidays))
+	for i, holiday := range settings.Holidays {
+		holidays[i] = AppTeamHoliday{
+			Date: date.Date(holiday.Date),
+			Name: holiday.Name,
+		}
+	}
+	return AppTeamSchedulingSettings{
+		CascadeDependencies: settings.CascadeDependencies,
+		Holidays:            holidays,
+		CreatedAt:           settings.CreatedAt,
+		UpdatedAt:           settings.UpdatedAt,
+	}
+}
+
+func toCoreUpdateTeamSchedulingSettings(app AppUpdateTeamSchedulingSettings) teamsettings.CoreUpdateTeamSchedulingSettings {
+	updates := teamsettings.CoreUpdateTeamSchedulingSettings{
+		CascadeDependencies: app.CascadeDependencies,
+	}
+	if app.Holidays != nil {
+		holidays := make([]teamsettings.CoreTeamHoliday, len(*app.Holidays))
+		for i, holiday := range *app.Holidays {
+			holidays[i] = teamsettings.CoreTeamHoliday{
+				Date: holiday.Date.Time(),
+				Name: holiday.Name,
+			}
+		}
+		updates.Holidays = &holidays
+	}
+	return updates
+}
+
+func toAppTeamSLASettings(settings teamsettings.CoreTeamSLASettings) AppTeamSLASettings {
+	policies := make([]AppTeamSLAPolicy, len(settings.Policies))
+	for i, policy := range settings.Policies {
+		policies[i] = AppTeamSLAPolicy{
+			Priority:            policy.Priority,
+			FirstResponseTarget: policy.FirstResponseTarget,
+			ResolveTarget:       policy.ResolveTarget,
+		}
+	}
+	return AppTeamSLASettings{
+		Timezone:      settings.Timezone,
+		WorkdayStart:  settings.WorkdayStart,
+		WorkdayEnd:    settings.WorkdayEnd,
+		AtRiskPercent: settings.AtRiskPercent,
+		Policies:      policies,
+		CreatedAt:     settings.CreatedAt,
+		UpdatedAt:     settings.UpdatedAt,
+	}
+}
+
+func toCoreUpdateTeamSLASettings(app AppUpdateTeamSLASettings) teamsettings.CoreUpdateTeamSLASettings {
+	updates := teamsettings.CoreUpdateTeamSLASettings{
+		Timezone:      app.Timezone,
+		WorkdayStart:  app.WorkdayStart,
+		WorkdayEnd:    app.WorkdayEnd,
+		AtRiskPercent: app.AtRiskPercent,
+	}
+	if app.Policies != nil {
+		policies := make([]teamsettings.CoreTeamSLAPolicy, len(*app.Policies))
+		for i, policy := range *app.Policies {
+			policies[i] = teamsettings.CoreTeamSLAPolicy{
+				Priority:            policy.Priority,
+				FirstResponseTarget: policy.FirstResponseTarget,
+				ResolveTarget:       policy.ResolveTarget,
+			}
+		}
+		updates.Policies = &policies
+	}
+	return updates
+}
+
+func toAppTeamWorkflowSettings(settings teamsettings.CoreTeamWorkflowSettings) AppTeamWorkflowSettings {
+	transitions := make([]AppTeamStatusTransition, len(settings.Transitions))
+	for i, transition := range settings.Transitions {
+		transitions[i] = AppTeamStatusTransition{
+			FromStatusID: transition.FromStatusID,
+			ToStatusID:   transition.ToStatusID,
+			AllowedRoles: transition.AllowedRoles,
+		}
+	}
+	requirements := make([]AppTeamCategoryRequirement, len(settings.Requirements))
+	for i, requirement := range settings.Requirements {
+		requirements[i] = AppTeamCategoryRequirement{
+			Category:       requirement.Category,
+			RequiredFields: requirement.RequiredFields,
+		}
+	}
+	limits := make([]AppTeamWIPLimit, len(settings.WIPLimits))
+	for i, limit := range settings.WIPLimits {
+		limits[i] = AppTeamWIPLimit{
+			StatusID:    limit.StatusID,
+			MaxStories:  limit.MaxStories,
+			PerAssignee: limit.PerAssignee,
+		}
+	}
+	return AppTeamWorkflowSettings{
+		RestrictTransitions:  settings.RestrictTransitions,
+		IntegrationsOverride: settings.IntegrationsOverride,
+		WIPEnforcement:       settings.WIPEnforcement,
+		Transitions:          transitions,
+		Requirements:         requirements,
+		WIPLimits:            limits,
+		CreatedAt:            settings.CreatedAt,
+		UpdatedAt:            settings.UpdatedAt,
+	}
+}
+
+func toCoreUpdateTeamWorkflowSettings(app AppUpdateTeamWorkflowSettings) teamsettings.CoreUpdateTeamWorkflowSettings {
+	updates := teamsettings.CoreUpdateTeamWorkflowSettings{
+		RestrictTransitions:  app.RestrictTransitions,
+		IntegrationsOverride: app.IntegrationsOverride,
+		WIPEnforcement:       app.WIPEnforcement,
+	}
+	if app.Transitions != nil {
+		transitions := make([]teamsettings.CoreTeamStatusTransition, len(*app.Transitions))
+		for i, transition := range *app.Transitions {
+			transitions[i] = teamsettings.CoreTeamStatusTransition{
+				FromStatusID: transition.FromStatusID,
+				ToStatusID:   transition.ToStatusID,
+				AllowedRoles: transition.AllowedRoles,
+			}
+		}
+		updates.Transitions = &transitions
+	}
+	if app.Requirements != nil {
+		requirements := make([]teamsettings.CoreTeamCategoryRequirement, len(*app.Requirements))
+		for i, requirement := range *app.Requirements {
+			requirements[i] = teamsettings.CoreTeamCategoryRequirement{
+				Category:       requirement.Category,
+				RequiredFields: requirement.RequiredFields,
+			}
+		}
+		updates.Requirements = &requirements
+	}
+	if app.WIPLimits != nil {
+		limits := make([]teamsettings.CoreTeamWIPLimit, len(*app.WIPLimits))
+		for i, limit := range *app.WIPLimits {
+			limits[i] = teamsettings.CoreTeamWIPLimit{
+				StatusID:    limit.StatusID,
+				MaxStories:  limit.MaxStories,
+				PerAssignee: limit.PerAssignee,
+			}
+		}
+		updates.WIPLimits = &limits
+	}
+	return updates
+}
@@ -30,4 +30,7 @@ func Routes(cfg Config, app *web.App) {
 	app.Put("/workspaces/{workspaceSlug}/teams/{teamId}/settings/sprints", h.UpdateSprintSettings, auth, workspace)
 	app.Put("/workspaces/{workspaceSlug}/teams/{teamId}/settings/story-automation", h.UpdateStoryAutomationSettings, auth, workspace)
 	app.Put("/workspaces/{workspaceSlug}/teams/{teamId}/settings/estimation", h.UpdateEstimationSettings, auth, workspace)
+	app.Put("/workspaces/{workspaceSlug}/teams/{teamId}/settings/scheduling", h.UpdateSchedulingSettings, auth, workspace)
+	app.Put("/workspaces/{workspaceSlug}/teams/{teamId}/settings/sla", h.UpdateSLASettings, auth, workspace)
+	app.Put("/workspaces/{workspaceSlug}/teams/{teamId}/settings/workflow", h.UpdateWorkflowSettings, auth, workspace)
 }
@@ -153,6 +153,93 @@ func (h *Handlers) UpdateEstimationSettings(ctx context.Context, w http.Response
 	return web.Respond(ctx, w, toAppTeamEstimationSettings(result), http.StatusOK)
 }
 
+func (h *Handlers) UpdateSchedulingSettings(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "handlers.teamsettings.UpdateSchedulingSettings")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	teamIDParam := web.Params(r, "teamId")
+	teamID, err := uuid.Parse(teamIDParam)
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidTeamID, http.StatusBadRequest)
+	}
+
+	var input AppUpdateTeamSchedulingSettings
+	if err := web.Decode(r, &input); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	updates := toCoreUpdateTeamSchedulingSettings(input)
+	result, err := h.teamsettings.UpdateSchedulingSettings(ctx, teamID, workspace.ID, updates)
+	if err != nil {
+		return web.RespondError(ctx, w, err, teamSettingsErrorStatus(err))
+	}
+
+	return web.Respond(ctx, w, toAppTeamSchedulingSettings(result), http.StatusOK)
+}
+
+func (h *Handlers) UpdateSLASettings(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "handlers.teamsettings.UpdateSLASettings")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	teamIDParam := web.Params(r, "teamId")
+	teamID, err := uuid.Parse(teamIDParam)
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidTeamID, http.StatusBadRequest)
+	}
+
+	var input AppUpdateTeamSLASettings
+	if err := web.Decode(r, &input); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	updates := toCoreUpdateTeamSLASettings(input)
+	result, err := h.teamsettings.UpdateSLASettings(ctx, teamID, workspace.ID, updates)
+	if err != nil {
+		return web.RespondError(ctx, w, err, teamSettingsErrorStatus(err))
+	}
+
+	return web.Respond(ctx, w, toAppTeamSLASettings(result), http.StatusOK)
+}
+
+func (h *Handlers) UpdateWorkflowSettings(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "handlers.teamsettings.UpdateWorkflowSettings")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	teamIDParam := web.Params(r, "teamId")
+	teamID, err := uuid.Parse(teamIDParam)
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidTeamID, http.StatusBadRequest)
+	}
+
+	var input AppUpdateTeamWorkflowSettings
+	if err := web.Decode(r, &input); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	updates := toCoreUpdateTeamWorkflowSettings(input)
+	result, err := h.teamsettings.UpdateWorkflowSettings(ctx, teamID, workspace.ID, updates)
+	if err != nil {
+		return web.RespondError(ctx, w, err, teamSettingsErrorStatus(err))
+	}
+
+	return web.Respond(ctx, w, toAppTeamWorkflowSettings(result), http.StatusOK)
+}
+
 func teamSettingsErrorStatus(err error) int {
 	switch {
 	case errors.Is(err, teamsettings.ErrInvalidSprintStartDay):
@@ -169,6 +256,18 @@ func teamSettingsErrorStatus(err error) int {
 		return http.StatusBadRequest
 	case errors.Is(err, teamsettings.ErrInvalidEstimateScheme):
 		return http.StatusBadRequest
+	case errors.Is(err, teamsettings.ErrInvalidHolidays):
+		return http.StatusBadRequest
+	case errors.Is(err, teamsettings.ErrInvalidSLATimezone),
+		errors.Is(err, teamsettings.ErrInvalidSLAWorkday),
+		errors.Is(err, teamsettings.ErrInvalidSLAAtRisk),
+		errors.Is(err, teamsettings.ErrInvalidSLAPolicies):
+		return http.StatusBadRequest
+	case errors.Is(err, teamsettings.ErrInvalidTransitions),
+		errors.Is(err, teamsettings.ErrInvalidRequirements),
+		errors.Is(err, teamsettings.ErrInvalidWIPEnforcement),
+		errors.Is(err, teamsettings.ErrInvalidWIPLimits):
+		return http.StatusBadRequest
 	default:
 		return http.StatusInternalServerError
 	}
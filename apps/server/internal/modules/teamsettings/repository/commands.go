@@ -418,3 +418,338 @@ func (r *repo) createDefaultEstimationSettings(ctx context.Context, teamID, work
 
 	return toCoreTeamEstimationSettings(settings), nil
 }
+
+func (r *repo) UpdateSchedulingSettings(ctx context.Context, teamID, workspaceID uuid.UUID, updates teamsettings.CoreUpdateTeamSchedulingSettings) (teamsettings.CoreTeamSchedulingSettings, error) {
+	ctx, span := web.AddSpan(ctx, "business.repository.teamsettings.UpdateSchedulingSettings")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return teamsettings.CoreTeamSchedulingSettings{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO team_scheduling_settings (
+			team_id,
+			workspace_id,
+			cascade_dependencies
+		) VALUES (
+			$1,
+			$2,
+			COALESCE($3, false)
+		)
+		ON CONFLICT (team_id) DO UPDATE SET
+			workspace_id = EXCLUDED.workspace_id,
+			cascade_dependencies = COALESCE($3, team_scheduling_settings.cascade_dependencies),
+			updated_at = NOW()
+	`
+	if _, err := tx.ExecContext(ctx, query, teamID, workspaceID, updates.CascadeDependencies); err != nil {
+		return teamsettings.CoreTeamSchedulingSettings{}, err
+	}
+
+	if updates.Holidays != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM team_holidays WHERE team_id = $1 AND workspace_id = $2`, teamID, workspaceID); err != nil {
+			return teamsettings.CoreTeamSchedulingSettings{}, err
+		}
+		for _, holiday := range *updates.Holidays {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO team_holidays (team_id, workspace_id, holiday_date, name)
+				VALUES ($1, $2, $3, $4)`,
+				teamID, workspaceID, holiday.Date, holiday.Name); err != nil {
+				errMsg := fmt.Sprintf("failed to save team holiday: %s", err)
+				r.log.Error(ctx, errMsg)
+				span.RecordError(errors.New("failed to save team holiday"), trace.WithAttributes(attribute.String("error", errMsg)))
+				return teamsettings.CoreTeamSchedulingSettings{}, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return teamsettings.CoreTeamSchedulingSettings{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetSchedulingSettings(ctx, teamID, workspaceID)
+}
+
+func (r *repo) createDefaultSchedulingSettings(ctx context.Context, teamID, workspaceID uuid.UUID) (dbTeamSchedulingSettings, error) {
+	ctx, span := web.AddSpan(ctx, "business.repository.teamsettings.createDefaultSchedulingSettings")
+	defer span.End()
+
+	query := `
+		INSERT INTO team_scheduling_settings (
+			team_id,
+			workspace_id
+		) VALUES (
+			$1,
+			$2
+		)
+		ON CONFLICT (team_id) DO UPDATE SET
+			workspace_id = EXCLUDED.workspace_id,
+			updated_at = NOW()
+		RETURNING
+			team_id,
+			workspace_id,
+			cascade_dependencies,
+			created_at,
+			updated_at
+	`
+
+	var settings dbTeamSchedulingSettings
+	if err := r.db.GetContext(ctx, &settings, query, teamID, workspaceID); err != nil {
+		return dbTeamSchedulingSettings{}, err
+	}
+
+	span.AddEvent("default scheduling settings created")
+	return settings, nil
+}
+
+func (r *repo) UpdateSLASettings(ctx context.Context, teamID, workspaceID uuid.UUID, updates teamsettings.CoreUpdateTeamSLASettings) (teamsettings.CoreTeamSLASettings, error) {
+	ctx, span := web.AddSpan(ctx, "business.repository.teamsettings.UpdateSLASettings")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return teamsettings.CoreTeamSLASettings{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO team_sla_settings (
+			team_id,
+			workspace_id,
+			timezone,
+			workday_start,
+			workday_end,
+			at_risk_percent
+		) VALUES (
+			$1,
+			$2,
+			COALESCE($3, 'UTC'),
+			COALESCE($4::time, '09:00'),
+			COALESCE($5::time, '17:00'),
+			COALESCE($6, 80)
+		)
+		ON CONFLICT (team_id) DO UPDATE SET
+			workspace_id = EXCLUDED.workspace_id,
+			timezone = COALESCE($3, team_sla_settings.timezone),
+			workday_start = COALESCE($4::time, team_sla_settings.workday_start),
+			workday_end = COALESCE($5::time, team_sla_settings.workday_end),
+			at_risk_percent = COALESCE($6, team_sla_settings.at_risk_percent),
+			updated_at = NOW()
+	`
+	if _, err := tx.ExecContext(ctx, query, teamID, workspaceID, updates.Timezone, updates.WorkdayStart, updates.WorkdayEnd, updates.AtRiskPercent); err != nil {
+		errMsg := fmt.Sprintf("failed to save team SLA settings: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to save team SLA settings"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return teamsettings.CoreTeamSLASettings{}, err
+	}
+
+	if updates.Policies != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM team_sla_policies WHERE team_id = $1 AND workspace_id = $2`, teamID, workspaceID); err != nil {
+			return teamsettings.CoreTeamSLASettings{}, err
+		}
+		for _, policy := range *updates.Policies {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO team_sla_policies (team_id, workspace_id, priority, first_response_target, resolve_target)
+				VALUES ($1, $2, $3, $4, $5)`,
+				teamID, workspaceID, strings.TrimSpace(policy.Priority), policy.FirstResponseTarget, policy.ResolveTarget); err != nil {
+				errMsg := fmt.Sprintf("failed to save team SLA policy: %s", err)
+				r.log.Error(ctx, errMsg)
+				span.RecordError(errors.New("failed to save team SLA policy"), trace.WithAttributes(attribute.String("error", errMsg)))
+				return teamsettings.CoreTeamSLASettings{}, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return teamsettings.CoreTeamSLASettings{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetSLASettings(ctx, teamID, workspaceID)
+}
+
+func (r *repo) createDefaultSLASettings(ctx context.Context, teamID, workspaceID uuid.UUID) (dbTeamSLASettings, error) {
+	ctx, span := web.AddSpan(ctx, "business.repository.teamsettings.createDefaultSLASettings")
+	defer span.End()
+
+	query := `
+		INSERT INTO team_sla_settings (
+			team_id,
+			workspace_id
+		) VALUES (
+			$1,
+			$2
+		)
+		ON CONFLICT (team_id) DO UPDATE SET
+			workspace_id = EXCLUDED.workspace_id,
+			updated_at = NOW()
+		RETURNING
+			team_id,
+			workspace_id,
+			timezone,
+			to_char(workday_start, 'HH24:MI') AS workday_start,
+			to_char(workday_end, 'HH24:MI') AS workday_end,
+			at_risk_percent,
+			created_at,
+			updated_at
+	`
+
+	var settings dbTeamSLASettings
+	if err := r.db.GetContext(ctx, &settings, query, teamID, workspaceID); err != nil {
+		return dbTeamSLASettings{}, err
+	}
+
+	span.AddEvent("default SLA settings created")
+	return settings, nil
+}
+
+func (r *repo) UpdateWorkflowSettings(ctx context.Context, teamID, workspaceID uuid.UUID, updates teamsettings.CoreUpdateTeamWorkflowSettings) (teamsettings.CoreTeamWorkflowSettings, error) {
+	ctx, span := web.AddSpan(ctx, "business.repository.teamsettings.UpdateWorkflowSettings")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return teamsettings.CoreTeamWorkflowSettings{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO team_workflow_settings (
+			team_id,
+			workspace_id,
+			restrict_transitions,
+			integrations_override,
+			wip_enforcement
+		) VALUES (
+			$1,
+			$2,
+			COALESCE($3, false),
+			COALESCE($4, false),
+			COALESCE($5, 'warn')
+		)
+		ON CONFLICT (team_id) DO UPDATE SET
+			workspace_id = EXCLUDED.workspace_id,
+			restrict_transitions = COALESCE($3, team_workflow_settings.restrict_transitions),
+			integrations_override = COALESCE($4, team_workflow_settings.integrations_override),
+			wip_enforcement = COALESCE($5, team_workflow_settings.wip_enforcement),
+			updated_at = NOW()
+	`
+	if _, err := tx.ExecContext(ctx, query, teamID, workspaceID, updates.RestrictTransitions, updates.IntegrationsOverride, updates.WIPEnforcement); err != nil {
+		errMsg := fmt.Sprintf("failed to save team workflow settings: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to save team workflow settings"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return teamsettings.CoreTeamWorkflowSettings{}, err
+	}
+
+	if updates.Transitions != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM team_status_transitions WHERE team_id = $1 AND workspace_id = $2`, teamID, workspaceID); err != nil {
+			return teamsettings.CoreTeamWorkflowSettings{}, err
+		}
+		for _, transition := range *updates.Transitions {
+			roles := transition.AllowedRoles
+			if roles == nil {
+				roles = []string{}
+			}
+			// Both statuses must belong to the team, otherwise nothing is inserted.
+			result, err := tx.ExecContext(ctx, `
+				INSERT INTO team_status_transitions (team_id, workspace_id, from_status_id, to_status_id, allowed_roles)
+				SELECT $1, $2, $3, $4, $5
+				WHERE (
+					SELECT COUNT(*) FROM statuses
+					WHERE team_id = $1 AND workspace_id = $2 AND status_id IN ($3, $4)
+				) = 2`,
+				teamID, workspaceID, transition.FromStatusID, transition.ToStatusID, pq.StringArray(roles))
+			if err != nil {
+				errMsg := fmt.Sprintf("failed to save team status transition: %s", err)
+				r.log.Error(ctx, errMsg)
+				span.RecordError(errors.New("failed to save team status transition"), trace.WithAttributes(attribute.String("error", errMsg)))
+				return teamsettings.CoreTeamWorkflowSettings{}, err
+			}
+			if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+				return teamsettings.CoreTeamWorkflowSettings{}, teamsettings.ErrInvalidTransitions
+			}
+		}
+	}
+
+	if updates.Requirements != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM team_category_requirements WHERE team_id = $1 AND workspace_id = $2`, teamID, workspaceID); err != nil {
+			return teamsettings.CoreTeamWorkflowSettings{}, err
+		}
+		for _, requirement := range *updates.Requirements {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO team_category_requirements (team_id, workspace_id, category, required_fields)
+				VALUES ($1, $2, $3, $4)`,
+				teamID, workspaceID, requirement.Category, pq.StringArray(requirement.RequiredFields)); err != nil {
+				errMsg := fmt.Sprintf("failed to save team category requirement: %s", err)
+				r.log.Error(ctx, errMsg)
+				span.RecordError(errors.New("failed to save team category requirement"), trace.WithAttributes(attribute.String("error", errMsg)))
+				return teamsettings.CoreTeamWorkflowSettings{}, err
+			}
+		}
+	}
+
+	if updates.WIPLimits != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM team_wip_limits WHERE team_id = $1 AND workspace_id = $2`, teamID, workspaceID); err != nil {
+			return teamsettings.CoreTeamWorkflowSettings{}, err
+		}
+		for _, limit := range *updates.WIPLimits {
+			// The status must belong to the team, otherwise nothing is inserted.
+			result, err := tx.ExecContext(ctx, `
+				INSERT INTO team_wip_limits (team_id, workspace_id, status_id, max_stories, per_assignee)
+				SELECT $1, $2, status_id, $4, $5
+				FROM statuses
+				WHERE team_id = $1 AND workspace_id = $2 AND status_id = $3`,
+				teamID, workspaceID, limit.StatusID, limit.MaxStories, limit.PerAssignee)
+			if err != nil {
+				errMsg := fmt.Sprintf("failed to save team WIP limit: %s", err)
+				r.log.Error(ctx, errMsg)
+				span.RecordError(errors.New("failed to save team WIP limit"), trace.WithAttributes(attribute.String("error", errMsg)))
+				return teamsettings.CoreTeamWorkflowSettings{}, err
+			}
+			if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+				return teamsettings.CoreTeamWorkflowSettings{}, teamsettings.ErrInvalidWIPLimits
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return teamsettings.CoreTeamWorkflowSettings{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetWorkflowSettings(ctx, teamID, workspaceID)
+}
+
+func (r *repo) createDefaultWorkflowSettings(ctx context.Context, teamID, workspaceID uuid.UUID) (dbTeamWorkflowSettings, error) {
+	ctx, span := web.AddSpan(ctx, "business.repository.teamsettings.createDefaultWorkflowSettings")
+	defer span.End()
+
+	query := `
+		INSERT INTO team_workflow_settings (
+			team_id,
+			workspace_id
+		) VALUES (
+			$1,
+			$2
+		)
+		ON CONFLICT (team_id) DO UPDATE SET
+			workspace_id = EXCLUDED.workspace_id,
+			updated_at = NOW()
+		RETURNING
+			team_id,
+			workspace_id,
+			restrict_transitions,
+			integrations_override,
+			wip_enforcement,
+			created_at,
+			updated_at
+	`
+
+	var settings dbTeamWorkflowSettings
+	if err := r.db.GetContext(ctx, &settings, query, teamID, workspaceID); err != nil {
+		return dbTeamWorkflowSettings{}, err
+	}
+
+	span.AddEvent("default workflow settings created")
+	return settings, nil
+}
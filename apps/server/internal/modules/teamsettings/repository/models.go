@@ -5,6 +5,7 @@ import (
 
 	teamsettings "github.com/complexus-tech/projects-api/internal/modules/teamsettings/service"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type dbTeamSprintSettings struct {
@@ -42,6 +43,63 @@ type dbTeamEstimationSettings struct {
 	UpdatedAt   time.Time `db:"updated_at"`
 }
 
+type dbTeamSchedulingSettings struct {
+	TeamID              uuid.UUID `db:"team_id"`
+	WorkspaceID         uuid.UUID `db:"workspace_id"`
+	CascadeDependencies bool      `db:"cascade_dependencies"`
+	CreatedAt           time.Time `db:"created_at"`
+	UpdatedAt           time.Time `db:"updated_at"`
+}
+
+type dbTeamSLASettings struct {
+	TeamID        uuid.UUID `db:"team_id"`
+	WorkspaceID   uuid.UUID `db:"workspace_id"`
+	Timezone      string    `db:"timezone"`
+	WorkdayStart  string    `db:"workday_start"`
+	WorkdayEnd    string    `db:"workday_end"`
+	AtRiskPercent int       `db:"at_risk_percent"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+type dbTeamSLAPolicy struct {
+	Priority            string  `db:"priority"`
+	FirstResponseTarget *string `db:"first_response_target"`
+	ResolveTarget       *string `db:"resolve_target"`
+}
+
+type dbTeamWorkflowSettings struct {
+	TeamID               uuid.UUID `db:"team_id"`
+	WorkspaceID          uuid.UUID `db:"workspace_id"`
+	RestrictTransitions  bool      `db:"restrict_transitions"`
+	IntegrationsOverride bool      `db:"integrations_override"`
+	WIPEnforcement       string    `db:"wip_enforcement"`
+	CreatedAt            time.Time `db:"created_at"`
+	UpdatedAt            time.Time `db:"updated_at"`
+}
+
+type dbTeamStatusTransition struct {
+	FromStatusID uuid.UUID      `db:"from_status_id"`
+	ToStatusID   uuid.UUID      `db:"to_status_id"`
+	AllowedRoles pq.StringArray `db:"allowed_roles"`
+}
+
+type dbTeamCategoryRequirement struct {
+	Category       string         `db:"category"`
+	RequiredFields pq.StringArray `db:"required_fields"`
+}
+
+type dbTeamWIPLimit struct {
+	StatusID    uuid.UUID `db:"status_id"`
+	MaxStories  int       `db:"max_stories"`
+	PerAssignee bool      `db:"per_assignee"`
+}
+
+type dbTeamHoliday struct {
+	Date time.Time `db:"holiday_date"`
+	Name string    `db:"name"`
+}
+
 func toCoreTeamSprintSettings(s dbTeamSprintSettings) teamsettings.CoreTeamSprintSettings {
 	return teamsettings.CoreTeamSprintSettings{
 		TeamID:                       s.TeamID,
@@ -90,3 +148,79 @@ func toCoreTeamEstimationSettings(s dbTeamEstimationSettings) teamsettings.CoreT
 		UpdatedAt:   s.UpdatedAt,
 	}
 }
+
+func toCoreTeamSchedulingSettings(s dbTeamSchedulingSettings, holidays []dbTeamHoliday) teamsettings.CoreTeamSchedulingSettings {
+	settings := teamsettings.CoreTeamSchedulingSettings{
+		TeamID:              s.TeamID,
+		WorkspaceID:         s.WorkspaceID,
+		CascadeDependencies: s.CascadeDependencies,
+		Holidays:            make([]teamsettings.CoreTeamHoliday, len(holidays)),
+		CreatedAt:           s.CreatedAt,
+		UpdatedAt:           s.UpdatedAt,
+	}
+	for i, holiday := range holidays {
+		settings.Holidays[i] = teamsettings.CoreTeamHoliday{
+			Date: holiday.Date,
+			Name: holiday.Name,
+		}
+	}
+	return settings
+}
+
+func toCoreTeamSLASettings(s dbTeamSLASettings, policies []dbTeamSLAPolicy) teamsettings.CoreTeamSLASettings {
+	settings := teamsettings.CoreTeamSLASettings{
+		TeamID:        s.TeamID,
+		WorkspaceID:   s.WorkspaceID,
+		Timezone:      s.Timezone,
+		WorkdayStart:  s.WorkdayStart,
+		WorkdayEnd:    s.WorkdayEnd,
+		AtRiskPercent: s.AtRiskPercent,
+		Policies:      make([]teamsettings.CoreTeamSLAPolicy, len(policies)),
+		CreatedAt:     s.CreatedAt,
+		UpdatedAt:     s.UpdatedAt,
+	}
+	for i, policy := range policies {
+		settings.Policies[i] = teamsettings.CoreTeamSLAPolicy{
+			Priority:            policy.Priority,
+			FirstResponseTarget: policy.FirstResponseTarget,
+			ResolveTarget:       policy.ResolveTarget,
+		}
+	}
+	return settings
+}
+
+func toCoreTeamWorkflowSettings(s dbTeamWorkflowSettings, transitions []dbTeamStatusTransition, requirements []dbTeamCategoryRequirement, limits []dbTeamWIPLimit) teamsettings.CoreTeamWorkflowSettings {
+	settings := teamsettings.CoreTeamWorkflowSettings{
+		TeamID:               s.TeamID,
+		WorkspaceID:          s.WorkspaceID,
+		RestrictTransitions:  s.RestrictTransitions,
+		IntegrationsOverride: s.IntegrationsOverride,
+		WIPEnforcement:       s.WIPEnforcement,
+		Transitions:          make([]teamsettings.CoreTeamStatusTransition, len(transitions)),
+		Requirements:         make([]teamsettings.CoreTeamCategoryRequirement, len(requirements)),
+		WIPLimits:            make([]teamsettings.CoreTeamWIPLimit, len(limits)),
+		CreatedAt:            s.CreatedAt,
+		UpdatedAt:            s.UpdatedAt,
+	}
+	for i, transition := range transitions {
+		settings.Transitions[i] = teamsettings.CoreTeamStatusTransition{
+			FromStatusID: transition.FromStatusID,
+			ToStatusID:   transition.ToStatusID,
+			AllowedRoles: []string(transition.AllowedRoles),
+		}
+	}
+	for i, requirement := range requirements {
+		settings.Requirements[i] = teamsettings.CoreTeamCategoryRequirement{
+			Category:       requirement.Category,
+			RequiredFields: []string(requirement.RequiredFields),
+		}
+	}
+	for i, limit := range limits {
+		settings.WIPLimits[i] = teamsettings.CoreTeamWIPLimit{
+			StatusID:    limit.StatusID,
+			MaxStories:  limit.MaxStories,
+			PerAssignee: limit.PerAssignee,
+		}
+	}
+	return settings
+}
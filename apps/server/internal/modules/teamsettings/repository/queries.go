@@ -172,6 +172,175 @@ func (r *repo) GetEstimationSettings(ctx context.Context, teamID, workspaceID uu
 	return toCoreTeamEstimationSettings(settings), nil
 }
 
+func (r *repo) GetSchedulingSettings(ctx context.Context, teamID, workspaceID uuid.UUID) (teamsettings.CoreTeamSchedulingSettings, error) {
+	ctx, span := web.AddSpan(ctx, "business.repository.teamsettings.GetSchedulingSettings")
+	defer span.End()
+
+	var settings dbTeamSchedulingSettings
+	query := `
+		SELECT
+			team_id,
+			workspace_id,
+			cascade_dependencies,
+			created_at,
+			updated_at
+		FROM
+			team_scheduling_settings
+		WHERE
+			team_id = $1
+			AND workspace_id = $2
+	`
+	if err := r.db.GetContext(ctx, &settings, query, teamID, workspaceID); err != nil {
+		if err != sql.ErrNoRows {
+			return teamsettings.CoreTeamSchedulingSettings{}, err
+		}
+		settings, err = r.createDefaultSchedulingSettings(ctx, teamID, workspaceID)
+		if err != nil {
+			return teamsettings.CoreTeamSchedulingSettings{}, err
+		}
+	}
+
+	var holidays []dbTeamHoliday
+	holidaysQuery := `
+		SELECT holiday_date, name
+		FROM team_holidays
+		WHERE team_id = $1 AND workspace_id = $2
+		ORDER BY holiday_date
+	`
+	if err := r.db.SelectContext(ctx, &holidays, holidaysQuery, teamID, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to get team holidays: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get team holidays"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return teamsettings.CoreTeamSchedulingSettings{}, err
+	}
+
+	return toCoreTeamSchedulingSettings(settings, holidays), nil
+}
+
+func (r *repo) GetSLASettings(ctx context.Context, teamID, workspaceID uuid.UUID) (teamsettings.CoreTeamSLASettings, error) {
+	ctx, span := web.AddSpan(ctx, "business.repository.teamsettings.GetSLASettings")
+	defer span.End()
+
+	var settings dbTeamSLASettings
+	query := `
+		SELECT
+			team_id,
+			workspace_id,
+			timezone,
+			to_char(workday_start, 'HH24:MI') AS workday_start,
+			to_char(workday_end, 'HH24:MI') AS workday_end,
+			at_risk_percent,
+			created_at,
+			updated_at
+		FROM
+			team_sla_settings
+		WHERE
+			team_id = $1
+			AND workspace_id = $2
+	`
+	if err := r.db.GetContext(ctx, &settings, query, teamID, workspaceID); err != nil {
+		if err != sql.ErrNoRows {
+			return teamsettings.CoreTeamSLASettings{}, err
+		}
+		settings, err = r.createDefaultSLASettings(ctx, teamID, workspaceID)
+		if err != nil {
+			return teamsettings.CoreTeamSLASettings{}, err
+		}
+	}
+
+	var policies []dbTeamSLAPolicy
+	policiesQuery := `
+		SELECT priority, first_response_target, resolve_target
+		FROM team_sla_policies
+		WHERE team_id = $1 AND workspace_id = $2
+		ORDER BY created_at, priority
+	`
+	if err := r.db.SelectContext(ctx, &policies, policiesQuery, teamID, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to get team SLA policies: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get team SLA policies"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return teamsettings.CoreTeamSLASettings{}, err
+	}
+
+	return toCoreTeamSLASettings(settings, policies), nil
+}
+
+func (r *repo) GetWorkflowSettings(ctx context.Context, teamID, workspaceID uuid.UUID) (teamsettings.CoreTeamWorkflowSettings, error) {
+	ctx, span := web.AddSpan(ctx, "business.repository.teamsettings.GetWorkflowSettings")
+	defer span.End()
+
+	var settings dbTeamWorkflowSettings
+	query := `
+		SELECT
+			team_id,
+			workspace_id,
+			restrict_transitions,
+			integrations_override,
+			wip_enforcement,
+			created_at,
+			updated_at
+		FROM
+			team_workflow_settings
+		WHERE
+			team_id = $1
+			AND workspace_id = $2
+	`
+	if err := r.db.GetContext(ctx, &settings, query, teamID, workspaceID); err != nil {
+		if err != sql.ErrNoRows {
+			return teamsettings.CoreTeamWorkflowSettings{}, err
+		}
+		settings, err = r.createDefaultWorkflowSettings(ctx, teamID, workspaceID)
+		if err != nil {
+			return teamsettings.CoreTeamWorkflowSettings{}, err
+		}
+	}
+
+	var transitions []dbTeamStatusTransition
+	transitionsQuery := `
+		SELECT from_status_id, to_status_id, allowed_roles
+		FROM team_status_transitions
+		WHERE team_id = $1 AND workspace_id = $2
+		ORDER BY created_at, from_status_id, to_status_id
+	`
+	if err := r.db.SelectContext(ctx, &transitions, transitionsQuery, teamID, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to get team status transitions: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get team status transitions"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return teamsettings.CoreTeamWorkflowSettings{}, err
+	}
+
+	var requirements []dbTeamCategoryRequirement
+	requirementsQuery := `
+		SELECT category, required_fields
+		FROM team_category_requirements
+		WHERE team_id = $1 AND workspace_id = $2
+		ORDER BY created_at, category
+	`
+	if err := r.db.SelectContext(ctx, &requirements, requirementsQuery, teamID, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to get team category requirements: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get team category requirements"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return teamsettings.CoreTeamWorkflowSettings{}, err
+	}
+
+	var limits []dbTeamWIPLimit
+	limitsQuery := `
+		SELECT l.status_id, l.max_stories, l.per_assignee
+		FROM team_wip_limits l
+		INNER JOIN statuses st ON st.status_id = l.status_id
+		WHERE l.team_id = $1 AND l.workspace_id = $2
+		ORDER BY st.order_index, l.created_at
+	`
+	if err := r.db.SelectContext(ctx, &limits, limitsQuery, teamID, workspaceID); err != nil {
+		errMsg := fmt.Sprintf("failed to get team WIP limits: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get team WIP limits"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return teamsettings.CoreTeamWorkflowSettings{}, err
+	}
+
+	return toCoreTeamWorkflowSettings(settings, transitions, requirements, limits), nil
+}
+
 func (r *repo) GetTeamsWithAutoSprintCreation(ctx context.Context) ([]teamsettings.CoreTeamSprintSettings, error) {
 	ctx, span := web.AddSpan(ctx, "business.repository.teamsettings.GetTeamsWithAutoSprintCreation")
 	defer span.End()
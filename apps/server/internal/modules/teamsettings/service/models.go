@@ -41,10 +41,93 @@ type CoreTeamEstimationSettings struct {
 	UpdatedAt   time.Time
 }
 
+// CoreTeamSchedulingSettings controls how a team's stories follow the
+// stories that block them.
+type CoreTeamSchedulingSettings struct {
+	TeamID              uuid.UUID
+	WorkspaceID         uuid.UUID
+	CascadeDependencies bool
+	Holidays            []CoreTeamHoliday
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// CoreTeamHoliday is a day the team does not work.
+type CoreTeamHoliday struct {
+	Date time.Time
+	Name string
+}
+
+// CoreTeamSLASettings are a team's SLA policies and the business hours
+// their targets count in. Workday times are HH:MM in Timezone.
+type CoreTeamSLASettings struct {
+	TeamID        uuid.UUID
+	WorkspaceID   uuid.UUID
+	Timezone      string
+	WorkdayStart  string
+	WorkdayEnd    string
+	AtRiskPercent int
+	Policies      []CoreTeamSLAPolicy
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// CoreTeamSLAPolicy sets the SLA targets of a priority, such as 4h or 2d
+// of business time. A nil target is not tracked.
+type CoreTeamSLAPolicy struct {
+	Priority            string
+	FirstResponseTarget *string
+	ResolveTarget       *string
+}
+
+// CoreTeamWorkflowSettings are a team's status transition rules and WIP
+// limits. With RestrictTransitions on, stories only move along Transitions.
+// WIPEnforcement is warn or block. IntegrationsOverride lets integrations
+// such as GitHub move stories regardless of the rules and limits.
+type CoreTeamWorkflowSettings struct {
+	TeamID               uuid.UUID
+	WorkspaceID          uuid.UUID
+	RestrictTransitions  bool
+	IntegrationsOverride bool
+	WIPEnforcement       string
+	Transitions          []CoreTeamStatusTransition
+	Requirements         []CoreTeamCategoryRequirement
+	WIPLimits            []CoreTeamWIPLimit
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// CoreTeamStatusTransition allows a move between two statuses. When
+// AllowedRoles is set, only users with one of those workspace roles may
+// make it.
+type CoreTeamStatusTransition struct {
+	FromStatusID uuid.UUID
+	ToStatusID   uuid.UUID
+	AllowedRoles []string
+}
+
+// CoreTeamCategoryRequirement lists the fields a story needs before it
+// enters a status of the category.
+type CoreTeamCategoryRequirement struct {
+	Category       string
+	RequiredFields []string
+}
+
+// CoreTeamWIPLimit is the most stories a status may hold, counted per
+// assignee when PerAssignee is set.
+type CoreTeamWIPLimit struct {
+	StatusID    uuid.UUID
+	MaxStories  int
+	PerAssignee bool
+}
+
 type CoreTeamSettings struct {
 	SprintSettings          CoreTeamSprintSettings
 	StoryAutomationSettings CoreTeamStoryAutomationSettings
 	EstimationSettings      CoreTeamEstimationSettings
+	SchedulingSettings      CoreTeamSchedulingSettings
+	SLASettings             CoreTeamSLASettings
+	WorkflowSettings        CoreTeamWorkflowSettings
 }
 
 type CoreUpdateTeamSprintSettings struct {
@@ -66,3 +149,31 @@ type CoreUpdateTeamStoryAutomationSettings struct {
 type CoreUpdateTeamEstimationSettings struct {
 	Scheme *string
 }
+
+// CoreUpdateTeamSchedulingSettings updates scheduling settings. Holidays,
+// when set, replace the team's holidays.
+type CoreUpdateTeamSchedulingSettings struct {
+	CascadeDependencies *bool
+	Holidays            *[]CoreTeamHoliday
+}
+
+// CoreUpdateTeamSLASettings updates SLA settings. Policies, when set,
+// replace the team's policies.
+type CoreUpdateTeamSLASettings struct {
+	Timezone      *string
+	WorkdayStart  *string
+	WorkdayEnd    *string
+	AtRiskPercent *int
+	Policies      *[]CoreTeamSLAPolicy
+}
+
+// CoreUpdateTeamWorkflowSettings updates workflow settings. Transitions,
+// Requirements and WIPLimits, when set, replace the team's.
+type CoreUpdateTeamWorkflowSettings struct {
+	RestrictTransitions  *bool
+	IntegrationsOverride *bool
+	WIPEnforcement       *string
+	Transitions          *[]CoreTeamStatusTransition
+	Requirements         *[]CoreTeamCategoryRequirement
+	WIPLimits            *[]CoreTeamWIPLimit
+}
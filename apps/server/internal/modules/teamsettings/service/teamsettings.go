@@ -3,6 +3,10 @@ package teamsettings
 import (
 	"context"
 	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/complexus-tech/projects-api/pkg/logger"
 	"github.com/complexus-tech/projects-api/pkg/tasks"
@@ -22,6 +26,12 @@ type Repository interface {
 	UpdateEstimationSettings(ctx context.Context, teamID, workspaceID uuid.UUID, updates CoreUpdateTeamEstimationSettings) (CoreTeamEstimationSettings, error)
 	GetTeamsWithAutoSprintCreation(ctx context.Context) ([]CoreTeamSprintSettings, error)
 	IncrementAutoSprintNumber(ctx context.Context, teamID, workspaceID uuid.UUID) error
+	GetSchedulingSettings(ctx context.Context, teamID, workspaceID uuid.UUID) (CoreTeamSchedulingSettings, error)
+	UpdateSchedulingSettings(ctx context.Context, teamID, workspaceID uuid.UUID, updates CoreUpdateTeamSchedulingSettings) (CoreTeamSchedulingSettings, error)
+	GetSLASettings(ctx context.Context, teamID, workspaceID uuid.UUID) (CoreTeamSLASettings, error)
+	UpdateSLASettings(ctx context.Context, teamID, workspaceID uuid.UUID, updates CoreUpdateTeamSLASettings) (CoreTeamSLASettings, error)
+	GetWorkflowSettings(ctx context.Context, teamID, workspaceID uuid.UUID) (CoreTeamWorkflowSettings, error)
+	UpdateWorkflowSettings(ctx context.Context, teamID, workspaceID uuid.UUID, updates CoreUpdateTeamWorkflowSettings) (CoreTeamWorkflowSettings, error)
 }
 
 // Validation errors
@@ -33,8 +43,37 @@ var (
 	ErrInvalidCloseMonths    = errors.New("auto-close inactive months must be between 1 and 24")
 	ErrInvalidArchiveMonths  = errors.New("auto-archive months must be between 1 and 24")
 	ErrInvalidEstimateScheme = errors.New("estimate scheme must be one of: points, hours, tshirt, ideal_days")
+	ErrInvalidHolidays       = errors.New("holidays must have unique dates and names of at most 100 characters")
+	ErrInvalidSLATimezone    = errors.New("SLA timezone must be an IANA time zone such as Africa/Harare")
+	ErrInvalidSLAWorkday     = errors.New("SLA workday must start before it ends, with times in HH:MM form")
+	ErrInvalidSLAAtRisk      = errors.New("SLA at-risk percent must be between 1 and 99")
+	ErrInvalidSLAPolicies    = errors.New("SLA policies need a unique priority and at least one target, such as 30m, 4h or 2d")
+	ErrInvalidTransitions    = errors.New("transitions must be unique, move between two different statuses of the team, and allow only the admin, member, guest or system roles")
+	ErrInvalidRequirements   = errors.New("requirements need a unique status category and fields from: assignee, estimate, sprint, start_date, deadline, epic, objective, description")
+	ErrInvalidWIPEnforcement = errors.New("WIP enforcement must be warn or block")
+	ErrInvalidWIPLimits      = errors.New("WIP limits need a unique status of the team and at most 1000 stories, with at least 1")
 )
 
+// maxHolidayNameLength is the longest holiday name in characters.
+const maxHolidayNameLength = 100
+
+// slaTargetPattern matches SLA targets: whole minutes, hours or days.
+var slaTargetPattern = regexp.MustCompile(`^[1-9][0-9]{0,4}[mhd]$`)
+
+// workflowRoles are the workspace roles a transition can be limited to.
+var workflowRoles = map[string]bool{"admin": true, "member": true, "guest": true, "system": true}
+
+// workflowCategories are the status categories requirements can be set on.
+var workflowCategories = map[string]bool{
+	"backlog": true, "unstarted": true, "started": true, "paused": true, "completed": true, "cancelled": true,
+}
+
+// workflowFields are the story fields a category can require.
+var workflowFields = map[string]bool{
+	"assignee": true, "estimate": true, "sprint": true, "start_date": true,
+	"deadline": true, "epic": true, "objective": true, "description": true,
+}
+
 // Service provides team settings-related operations.
 type Service struct {
 	repo         Repository
@@ -75,10 +114,31 @@ func (s *Service) GetSettings(ctx context.Context, teamID, workspaceID uuid.UUID
 		return CoreTeamSettings{}, err
 	}
 
+	schedulingSettings, err := s.repo.GetSchedulingSettings(ctx, teamID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreTeamSettings{}, err
+	}
+
+	slaSettings, err := s.repo.GetSLASettings(ctx, teamID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreTeamSettings{}, err
+	}
+
+	workflowSettings, err := s.repo.GetWorkflowSettings(ctx, teamID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreTeamSettings{}, err
+	}
+
 	result := CoreTeamSettings{
 		SprintSettings:          sprintSettings,
 		StoryAutomationSettings: storySettings,
 		EstimationSettings:      estimationSettings,
+		SchedulingSettings:      schedulingSettings,
+		SLASettings:             slaSettings,
+		WorkflowSettings:        workflowSettings,
 	}
 
 	span.AddEvent("team settings retrieved.", trace.WithAttributes(
@@ -232,6 +292,92 @@ func (s *Service) UpdateEstimationSettings(ctx context.Context, teamID, workspac
 	return result, nil
 }
 
+// UpdateSchedulingSettings updates the scheduling settings for a team.
+func (s *Service) UpdateSchedulingSettings(ctx context.Context, teamID, workspaceID uuid.UUID, updates CoreUpdateTeamSchedulingSettings) (CoreTeamSchedulingSettings, error) {
+	s.log.Info(ctx, "business.core.teamsettings.updateSchedulingSettings")
+	ctx, span := web.AddSpan(ctx, "business.core.teamsettings.UpdateSchedulingSettings")
+	defer span.End()
+
+	if err := s.validateSchedulingSettingsUpdate(updates); err != nil {
+		span.RecordError(err)
+		return CoreTeamSchedulingSettings{}, err
+	}
+
+	result, err := s.repo.UpdateSchedulingSettings(ctx, teamID, workspaceID, updates)
+	if err != nil {
+		span.RecordError(err)
+		return CoreTeamSchedulingSettings{}, err
+	}
+
+	span.AddEvent("scheduling settings updated.", trace.WithAttributes(
+		attribute.String("team.id", teamID.String()),
+		attribute.String("workspace.id", workspaceID.String()),
+	))
+	return result, nil
+}
+
+// UpdateSLASettings updates the SLA settings for a team.
+func (s *Service) UpdateSLASettings(ctx context.Context, teamID, workspaceID uuid.UUID, updates CoreUpdateTeamSLASettings) (CoreTeamSLASettings, error) {
+	s.log.Info(ctx, "business.core.teamsettings.updateSLASettings")
+	ctx, span := web.AddSpan(ctx, "business.core.teamsettings.UpdateSLASettings")
+	defer span.End()
+
+	current, err := s.repo.GetSLASettings(ctx, teamID, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreTeamSLASettings{}, err
+	}
+	if err := s.validateSLASettingsUpdate(current, updates); err != nil {
+		span.RecordError(err)
+		return CoreTeamSLASettings{}, err
+	}
+
+	result, err := s.repo.UpdateSLASettings(ctx, teamID, workspaceID, updates)
+	if err != nil {
+		span.RecordError(err)
+		return CoreTeamSLASettings{}, err
+	}
+
+	// Open stories keep the clocks of the old settings until resynced.
+	if _, err := s.tasksService.EnqueueStorySLAResync(tasks.StorySLAResyncPayload{
+		WorkspaceID: workspaceID,
+		TeamID:      teamID,
+	}); err != nil {
+		span.RecordError(err)
+		s.log.Error(ctx, "business.core.teamsettings.updateSLASettings", "error enqueuing story SLA resync task", "error", err)
+	}
+
+	span.AddEvent("SLA settings updated.", trace.WithAttributes(
+		attribute.String("team.id", teamID.String()),
+		attribute.String("workspace.id", workspaceID.String()),
+	))
+	return result, nil
+}
+
+// UpdateWorkflowSettings updates the status transition rules for a team.
+func (s *Service) UpdateWorkflowSettings(ctx context.Context, teamID, workspaceID uuid.UUID, updates CoreUpdateTeamWorkflowSettings) (CoreTeamWorkflowSettings, error) {
+	s.log.Info(ctx, "business.core.teamsettings.updateWorkflowSettings")
+	ctx, span := web.AddSpan(ctx, "business.core.teamsettings.UpdateWorkflowSettings")
+	defer span.End()
+
+	if err := s.validateWorkflowSettingsUpdate(updates); err != nil {
+		span.RecordError(err)
+		return CoreTeamWorkflowSettings{}, err
+	}
+
+	result, err := s.repo.UpdateWorkflowSettings(ctx, teamID, workspaceID, updates)
+	if err != nil {
+		span.RecordError(err)
+		return CoreTeamWorkflowSettings{}, err
+	}
+
+	span.AddEvent("workflow settings updated.", trace.WithAttributes(
+		attribute.String("team.id", teamID.String()),
+		attribute.String("workspace.id", workspaceID.String()),
+	))
+	return result, nil
+}
+
 // validateSprintSettingsUpdate validates sprint settings updates
 func (s *Service) validateSprintSettingsUpdate(updates CoreUpdateTeamSprintSettings) error {
 	validDays := map[string]bool{
@@ -289,6 +435,118 @@ func (s *Service) validateEstimationSettingsUpdate(updates CoreUpdateTeamEstimat
 	}
 }
 
+func (s *Service) validateSchedulingSettingsUpdate(updates CoreUpdateTeamSchedulingSettings) error {
+	if updates.Holidays == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(*updates.Holidays))
+	for _, holiday := range *updates.Holidays {
+		day := holiday.Date.Format("2006-01-02")
+		if holiday.Date.IsZero() || seen[day] || utf8.RuneCountInString(holiday.Name) > maxHolidayNameLength {
+			return ErrInvalidHolidays
+		}
+		seen[day] = true
+	}
+	return nil
+}
+
+// validateSLASettingsUpdate checks updates against the current settings,
+// since a new workday start must still come before the current end.
+func (s *Service) validateSLASettingsUpdate(current CoreTeamSLASettings, updates CoreUpdateTeamSLASettings) error {
+	if updates.Timezone != nil {
+		if _, err := time.LoadLocation(*updates.Timezone); err != nil || *updates.Timezone == "" {
+			return ErrInvalidSLATimezone
+		}
+	}
+
+	start, end := current.WorkdayStart, current.WorkdayEnd
+	if updates.WorkdayStart != nil {
+		start = *updates.WorkdayStart
+	}
+	if updates.WorkdayEnd != nil {
+		end = *updates.WorkdayEnd
+	}
+	startTime, startErr := time.Parse("15:04", start)
+	endTime, endErr := time.Parse("15:04", end)
+	if startErr != nil || endErr != nil || !startTime.Before(endTime) {
+		return ErrInvalidSLAWorkday
+	}
+
+	if updates.AtRiskPercent != nil && (*updates.AtRiskPercent < 1 || *updates.AtRiskPercent > 99) {
+		return ErrInvalidSLAAtRisk
+	}
+
+	if updates.Policies == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(*updates.Policies))
+	for _, policy := range *updates.Policies {
+		priority := strings.ToLower(strings.TrimSpace(policy.Priority))
+		if priority == "" || seen[priority] || policy.FirstResponseTarget == nil && policy.ResolveTarget == nil {
+			return ErrInvalidSLAPolicies
+		}
+		for _, target := range []*string{policy.FirstResponseTarget, policy.ResolveTarget} {
+			if target != nil && !slaTargetPattern.MatchString(*target) {
+				return ErrInvalidSLAPolicies
+			}
+		}
+		seen[priority] = true
+	}
+	return nil
+}
+
+// validateWorkflowSettingsUpdate checks transitions, requirements and WIP
+// limits. The repository checks that their statuses belong to the team.
+func (s *Service) validateWorkflowSettingsUpdate(updates CoreUpdateTeamWorkflowSettings) error {
+	if updates.Transitions != nil {
+		seen := make(map[[2]uuid.UUID]bool, len(*updates.Transitions))
+		for _, transition := range *updates.Transitions {
+			key := [2]uuid.UUID{transition.FromStatusID, transition.ToStatusID}
+			if transition.FromStatusID == uuid.Nil || transition.ToStatusID == uuid.Nil ||
+				transition.FromStatusID == transition.ToStatusID || seen[key] {
+				return ErrInvalidTransitions
+			}
+			for _, role := range transition.AllowedRoles {
+				if !workflowRoles[role] {
+					return ErrInvalidTransitions
+				}
+			}
+			seen[key] = true
+		}
+	}
+
+	if updates.Requirements != nil {
+		seen := make(map[string]bool, len(*updates.Requirements))
+		for _, requirement := range *updates.Requirements {
+			if !workflowCategories[requirement.Category] || seen[requirement.Category] || len(requirement.RequiredFields) == 0 {
+				return ErrInvalidRequirements
+			}
+			for _, field := range requirement.RequiredFields {
+				if !workflowFields[field] {
+					return ErrInvalidRequirements
+				}
+			}
+			seen[requirement.Category] = true
+		}
+	}
+
+	if updates.WIPEnforcement != nil && *updates.WIPEnforcement != "warn" && *updates.WIPEnforcement != "block" {
+		return ErrInvalidWIPEnforcement
+	}
+
+	if updates.WIPLimits != nil {
+		seen := make(map[uuid.UUID]bool, len(*updates.WIPLimits))
+		for _, limit := range *updates.WIPLimits {
+			if limit.StatusID == uuid.Nil || seen[limit.StatusID] || limit.MaxStories < 1 || limit.MaxStories > 1000 {
+				return ErrInvalidWIPLimits
+			}
+			seen[limit.StatusID] = true
+		}
+	}
+	return nil
+}
+
 // GetTeamsWithAutoSprintCreation returns teams that have auto sprint creation enabled.
 func (s *Service) GetTeamsWithAutoSprintCreation(ctx context.Context) ([]CoreTeamSprintSettings, error) {
 	s.log.Info(ctx, "business.core.teamsettings.getTeamsWithAutoSprintCreation")
@@ -0,0 +1,134 @@
+package timeentrieshttp
+
+import (
+	"time"
+
+	timeentries "github.com/complexus-tech/projects-api/internal/modules/timeentries/service"
+	"github.com/google/uuid"
+)
+
+type AppTimeEntry struct {
+	ID              uuid.UUID  `json:"id"`
+	Workspace       uuid.UUID  `json:"workspaceId"`
+	Story           uuid.UUID  `json:"storyId"`
+	User            uuid.UUID  `json:"userId"`
+	StartedAt       time.Time  `json:"startedAt"`
+	EndedAt         *time.Time `json:"endedAt"`
+	DurationMinutes int        `json:"durationMinutes"`
+	IsRunning       bool       `json:"isRunning"`
+	Note            string     `json:"note"`
+	Billable        bool       `json:"billable"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+type AppNewTimeEntry struct {
+	Story           uuid.UUID  `json:"storyId" validate:"required"`
+	StartedAt       *time.Time `json:"startedAt"`
+	EndedAt         *time.Time `json:"endedAt"`
+	DurationMinutes *int       `json:"durationMinutes"`
+	Note            string     `json:"note" validate:"max=500"`
+	Billable        bool       `json:"billable"`
+}
+
+type AppUpdateTimeEntry struct {
+	Story           *uuid.UUID `json:"storyId"`
+	StartedAt       *time.Time `json:"startedAt"`
+	EndedAt         *time.Time `json:"endedAt"`
+	DurationMinutes *int       `json:"durationMinutes"`
+	Note            *string    `json:"note" validate:"omitempty,max=500"`
+	Billable        *bool      `json:"billable"`
+}
+
+type AppStartTimer struct {
+	Story    uuid.UUID `json:"storyId" validate:"required"`
+	Note     string    `json:"note" validate:"max=500"`
+	Billable bool      `json:"billable"`
+}
+
+type AppTimesheet struct {
+	WeekStart       string             `json:"weekStart"`
+	WeekEnd         string             `json:"weekEnd"`
+	Timezone        string             `json:"timezone"`
+	Days            [7]int             `json:"days"`
+	TotalMinutes    int                `json:"totalMinutes"`
+	BillableMinutes int                `json:"billableMinutes"`
+	Users           []AppTimesheetUser `json:"users"`
+}
+
+type AppTimesheetUser struct {
+	User            uuid.UUID           `json:"userId"`
+	Days            [7]int              `json:"days"`
+	TotalMinutes    int                 `json:"totalMinutes"`
+	BillableMinutes int                 `json:"billableMinutes"`
+	Stories         []AppTimesheetStory `json:"stories"`
+}
+
+type AppTimesheetStory struct {
+	Story           uuid.UUID `json:"storyId"`
+	Team            uuid.UUID `json:"teamId"`
+	SequenceID      int       `json:"sequenceId"`
+	Title           string    `json:"title"`
+	Days            [7]int    `json:"days"`
+	TotalMinutes    int       `json:"totalMinutes"`
+	BillableMinutes int       `json:"billableMinutes"`
+}
+
+func toAppTimeEntry(e timeentries.CoreTimeEntry) AppTimeEntry {
+	return AppTimeEntry{
+		ID:              e.ID,
+		Workspace:       e.WorkspaceID,
+		Story:           e.StoryID,
+		User:            e.UserID,
+		StartedAt:       e.StartedAt,
+		EndedAt:         e.EndedAt,
+		DurationMinutes: int(e.Duration(time.Now()) / time.Minute),
+		IsRunning:       e.IsRunning(),
+		Note:            e.Note,
+		Billable:        e.Billable,
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+	}
+}
+
+func toAppTimeEntries(entries []timeentries.CoreTimeEntry) []AppTimeEntry {
+	result := make([]AppTimeEntry, len(entries))
+	for i, e := range entries {
+		result[i] = toAppTimeEntry(e)
+	}
+	return result
+}
+
+func toAppTimesheet(sheet timeentries.CoreTimesheet) AppTimesheet {
+	result := AppTimesheet{
+		WeekStart:       sheet.WeekStart.Format(time.DateOnly),
+		WeekEnd:         sheet.WeekStart.AddDate(0, 0, 6).Format(time.DateOnly),
+		Timezone:        sheet.Location.String(),
+		Days:            sheet.Days,
+		TotalMinutes:    sheet.TotalMinutes,
+		BillableMinutes: sheet.BillableMinutes,
+		Users:           make([]AppTimesheetUser, len(sheet.Users)),
+	}
+	for i, u := range sheet.Users {
+		user := AppTimesheetUser{
+			User:            u.UserID,
+			Days:            u.Days,
+			TotalMinutes:    u.TotalMinutes,
+			BillableMinutes: u.BillableMinutes,
+			Stories:         make([]AppTimesheetStory, len(u.Stories)),
+		}
+		for j, s := range u.Stories {
+			user.Stories[j] = AppTimesheetStory{
+				Story:           s.StoryID,
+				Team:            s.TeamID,
+				SequenceID:      s.SequenceID,
+				Title:           s.Title,
+				Days:            s.Days,
+				TotalMinutes:    s.TotalMinutes,
+				BillableMinutes: s.BillableMinutes,
+			}
+		}
+		result.Users[i] = user
+	}
+	return result
+}
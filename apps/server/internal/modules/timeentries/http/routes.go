@@ -0,0 +1,37 @@
+package timeentrieshttp
+
+import (
+	timeentries "github.com/complexus-tech/projects-api/internal/modules/timeentries/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/cache"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	DB        *sqlx.DB
+	Log       *logger.Logger
+	SecretKey string
+	Cache     *cache.Service
+	Service   *timeentries.Service
+}
+
+func Routes(cfg Config, app *web.App) {
+	timeEntriesService := cfg.Service
+	auth := mid.Auth(cfg.Log, cfg.SecretKey)
+	workspace := mid.Workspace(cfg.Log, cfg.DB, cfg.Cache)
+	memberAndAdmin := mid.RequireMinimumRole(cfg.Log, mid.RoleMember)
+
+	h := New(timeEntriesService)
+
+	app.Get("/workspaces/{workspaceSlug}/time-entries", h.List, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/time-entries", h.Create, auth, workspace, memberAndAdmin)
+	app.Get("/workspaces/{workspaceSlug}/time-entries/timer", h.RunningTimer, auth, workspace)
+	app.Post("/workspaces/{workspaceSlug}/time-entries/timer/start", h.StartTimer, auth, workspace, memberAndAdmin)
+	app.Post("/workspaces/{workspaceSlug}/time-entries/timer/stop", h.StopTimer, auth, workspace, memberAndAdmin)
+	app.Put("/workspaces/{workspaceSlug}/time-entries/{id}", h.Update, auth, workspace, memberAndAdmin)
+	app.Delete("/workspaces/{workspaceSlug}/time-entries/{id}", h.Delete, auth, workspace, memberAndAdmin)
+	app.Get("/workspaces/{workspaceSlug}/timesheets/users/{userId}", h.UserTimesheet, auth, workspace)
+	app.Get("/workspaces/{workspaceSlug}/timesheets/teams/{teamId}", h.TeamTimesheet, auth, workspace)
+}
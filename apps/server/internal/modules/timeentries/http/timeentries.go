@@ -0,0 +1,398 @@
+package timeentrieshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	timeentries "github.com/complexus-tech/projects-api/internal/modules/timeentries/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidEntryID  = errors.New("time entry id is not in its proper form")
+	ErrInvalidUserID   = errors.New("user id is not in its proper form")
+	ErrInvalidTeamID   = errors.New("team id is not in its proper form")
+	ErrInvalidStoryID  = errors.New("story id is not in its proper form")
+	ErrInvalidDate     = errors.New("dates must be YYYY-MM-DD or RFC 3339 timestamps")
+	ErrInvalidWeek     = errors.New("week must be a date in the form YYYY-MM-DD")
+	ErrInvalidTimezone = errors.New("timezone must be an IANA time zone name")
+)
+
+type Handlers struct {
+	timeEntries *timeentries.Service
+}
+
+func New(timeEntries *timeentries.Service) *Handlers {
+	return &Handlers{
+		timeEntries: timeEntries,
+	}
+}
+
+// List returns entries in the workspace, filtered by storyId, userId, teamId
+// and a from/to range.
+func (h *Handlers) List(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "timeentrieshttp.handlers.List")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	filters, err := parseFilters(r.URL.Query())
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	entries, err := h.timeEntries.List(ctx, workspace.ID, filters)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppTimeEntries(entries), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "timeentrieshttp.handlers.Create")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	var ne AppNewTimeEntry
+	if err := web.Decode(r, &ne); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	entry, err := h.timeEntries.Create(ctx, workspace.ID, userID, timeentries.CoreNewTimeEntry{
+		StoryID:         ne.Story,
+		StartedAt:       ne.StartedAt,
+		EndedAt:         ne.EndedAt,
+		DurationMinutes: ne.DurationMinutes,
+		Note:            ne.Note,
+		Billable:        ne.Billable,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppTimeEntry(entry), http.StatusCreated)
+	return nil
+}
+
+func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "timeentrieshttp.handlers.Update")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	entryID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidEntryID, http.StatusBadRequest)
+	}
+
+	var ue AppUpdateTimeEntry
+	if err := web.Decode(r, &ue); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	entry, err := h.timeEntries.Update(ctx, entryID, workspace.ID, userID, isAdmin(workspace), timeentries.CoreUpdateTimeEntry{
+		StoryID:         ue.Story,
+		StartedAt:       ue.StartedAt,
+		EndedAt:         ue.EndedAt,
+		DurationMinutes: ue.DurationMinutes,
+		Note:            ue.Note,
+		Billable:        ue.Billable,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppTimeEntry(entry), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "timeentrieshttp.handlers.Delete")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	entryID, err := uuid.Parse(web.Params(r, "id"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidEntryID, http.StatusBadRequest)
+	}
+
+	if err := h.timeEntries.Delete(ctx, entryID, workspace.ID, userID, isAdmin(workspace)); err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, nil, http.StatusNoContent)
+	return nil
+}
+
+// RunningTimer returns the user's running timer, or null when none is running.
+func (h *Handlers) RunningTimer(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "timeentrieshttp.handlers.RunningTimer")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	entry, err := h.timeEntries.RunningTimer(ctx, workspace.ID, userID)
+	if errors.Is(err, timeentries.ErrNoRunningTimer) {
+		web.Respond(ctx, w, nil, http.StatusOK)
+		return nil
+	}
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppTimeEntry(entry), http.StatusOK)
+	return nil
+}
+
+func (h *Handlers) StartTimer(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "timeentrieshttp.handlers.StartTimer")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	var st AppStartTimer
+	if err := web.Decode(r, &st); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	entry, err := h.timeEntries.StartTimer(ctx, workspace.ID, userID, timeentries.CoreStartTimer{
+		StoryID:  st.Story,
+		Note:     st.Note,
+		Billable: st.Billable,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppTimeEntry(entry), http.StatusCreated)
+	return nil
+}
+
+func (h *Handlers) StopTimer(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "timeentrieshttp.handlers.StopTimer")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	entry, err := h.timeEntries.StopTimer(ctx, workspace.ID, userID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppTimeEntry(entry), http.StatusOK)
+	return nil
+}
+
+// UserTimesheet returns a user's week. The week parameter is any date in the
+// week and timezone sets where days start; they default to today and UTC.
+func (h *Handlers) UserTimesheet(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "timeentrieshttp.handlers.UserTimesheet")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	viewerID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	userID, err := uuid.Parse(web.Params(r, "userId"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidUserID, http.StatusBadRequest)
+	}
+
+	day, loc, err := parseWeek(r.URL.Query())
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	sheet, err := h.timeEntries.UserTimesheet(ctx, workspace.ID, viewerID, isAdmin(workspace), userID, day, loc)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppTimesheet(sheet), http.StatusOK)
+	return nil
+}
+
+// TeamTimesheet returns the week for everyone who logged time on a team's
+// stories. It takes the same parameters as UserTimesheet.
+func (h *Handlers) TeamTimesheet(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "timeentrieshttp.handlers.TeamTimesheet")
+	defer span.End()
+
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	viewerID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+
+	teamID, err := uuid.Parse(web.Params(r, "teamId"))
+	if err != nil {
+		return web.RespondError(ctx, w, ErrInvalidTeamID, http.StatusBadRequest)
+	}
+
+	day, loc, err := parseWeek(r.URL.Query())
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+
+	sheet, err := h.timeEntries.TeamTimesheet(ctx, workspace.ID, viewerID, isAdmin(workspace), teamID, day, loc)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	web.Respond(ctx, w, toAppTimesheet(sheet), http.StatusOK)
+	return nil
+}
+
+func parseFilters(query url.Values) (timeentries.CoreTimeEntryFilters, error) {
+	var filters timeentries.CoreTimeEntryFilters
+	ids := []struct {
+		name   string
+		target **uuid.UUID
+		err    error
+	}{
+		{"storyId", &filters.StoryID, ErrInvalidStoryID},
+		{"userId", &filters.UserID, ErrInvalidUserID},
+		{"teamId", &filters.TeamID, ErrInvalidTeamID},
+	}
+	for _, param := range ids {
+		if value := query.Get(param.name); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				return filters, param.err
+			}
+			*param.target = &id
+		}
+	}
+
+	if value := query.Get("from"); value != "" {
+		from, err := parseTime(value)
+		if err != nil {
+			return filters, ErrInvalidDate
+		}
+		filters.From = &from
+	}
+	if value := query.Get("to"); value != "" {
+		to, err := parseTime(value)
+		if err != nil {
+			return filters, ErrInvalidDate
+		}
+		filters.To = &to
+	}
+	return filters, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+func parseWeek(query url.Values) (time.Time, *time.Location, error) {
+	loc := time.UTC
+	if name := query.Get("timezone"); name != "" {
+		var err error
+		if loc, err = time.LoadLocation(name); err != nil {
+			return time.Time{}, nil, ErrInvalidTimezone
+		}
+	}
+
+	day := time.Now().In(loc)
+	if value := query.Get("week"); value != "" {
+		var err error
+		if day, err = time.ParseInLocation(time.DateOnly, value, loc); err != nil {
+			return time.Time{}, nil, ErrInvalidWeek
+		}
+	}
+	return day, loc, nil
+}
+
+func isAdmin(workspace mid.WorkspaceInfo) bool {
+	return mid.Role(workspace.UserRole) == mid.RoleAdmin
+}
+
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, timeentries.ErrNotFound),
+		errors.Is(err, timeentries.ErrStoryNotFound),
+		errors.Is(err, timeentries.ErrNoRunningTimer):
+		return http.StatusNotFound
+	case errors.Is(err, timeentries.ErrForbidden),
+		errors.Is(err, timeentries.ErrTimesheetDenied):
+		return http.StatusForbidden
+	case errors.Is(err, timeentries.ErrTimerRunning):
+		return http.StatusConflict
+	case errors.Is(err, timeentries.ErrEntryRunning),
+		errors.Is(err, timeentries.ErrDurationRequired),
+		errors.Is(err, timeentries.ErrAmbiguousDuration),
+		errors.Is(err, timeentries.ErrStartRequired),
+		errors.Is(err, timeentries.ErrInvalidRange),
+		errors.Is(err, timeentries.ErrInvalidDuration),
+		errors.Is(err, timeentries.ErrStartInFuture),
+		errors.Is(err, timeentries.ErrNoteTooLong):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
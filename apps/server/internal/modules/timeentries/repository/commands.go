@@ -0,0 +1,142 @@
+package timeentriesrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	timeentries "github.com/complexus-tech/projects-api/internal/modules/timeentries/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// runningTimerIndex allows one running timer per user.
+const runningTimerIndex = "idx_time_entries_running_timer"
+
+// Create inserts an entry. It is only inserted when the story is in the
+// workspace and not deleted.
+func (r *repo) Create(ctx context.Context, entry timeentries.CoreTimeEntry) (timeentries.CoreTimeEntry, error) {
+	r.log.Info(ctx, "business.repository.timeentries.Create")
+	ctx, span := web.AddSpan(ctx, "business.repository.timeentries.Create")
+	defer span.End()
+
+	var row dbTimeEntry
+	query := `
+		INSERT INTO time_entries (workspace_id, story_id, user_id, started_at, ended_at, note, billable)
+		SELECT $1, s.id, $3, $4, $5, $6, $7
+		FROM stories s
+		WHERE s.id = $2 AND s.workspace_id = $1 AND s.deleted_at IS NULL
+		RETURNING id, workspace_id, story_id, user_id, started_at, ended_at, note, billable, created_at, updated_at`
+	err := r.db.GetContext(ctx, &row, query,
+		entry.WorkspaceID, entry.StoryID, entry.UserID, entry.StartedAt, entry.EndedAt, entry.Note, entry.Billable,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return timeentries.CoreTimeEntry{}, timeentries.ErrStoryNotFound
+		}
+		if strings.Contains(err.Error(), runningTimerIndex) {
+			return timeentries.CoreTimeEntry{}, timeentries.ErrTimerRunning
+		}
+		errMsg := fmt.Sprintf("failed to create time entry: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to create time entry"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return timeentries.CoreTimeEntry{}, err
+	}
+
+	span.AddEvent("time entry created", trace.WithAttributes(
+		attribute.String("time_entry.id", row.ID.String()),
+	))
+	return toCoreTimeEntry(row), nil
+}
+
+// Update writes an entry's fields. A new story must be in the workspace and
+// not deleted.
+func (r *repo) Update(ctx context.Context, entry timeentries.CoreTimeEntry) (timeentries.CoreTimeEntry, error) {
+	r.log.Info(ctx, "business.repository.timeentries.Update")
+	ctx, span := web.AddSpan(ctx, "business.repository.timeentries.Update")
+	defer span.End()
+
+	var row dbTimeEntry
+	query := `
+		UPDATE time_entries e
+		SET story_id = s.id,
+			started_at = $4,
+			ended_at = $5,
+			note = $6,
+			billable = $7,
+			updated_at = NOW()
+		FROM stories s
+		WHERE e.id = $1 AND e.workspace_id = $2
+			AND s.id = $3 AND s.workspace_id = $2 AND s.deleted_at IS NULL
+		RETURNING ` + entryColumns
+	err := r.db.GetContext(ctx, &row, query,
+		entry.ID, entry.WorkspaceID, entry.StoryID, entry.StartedAt, entry.EndedAt, entry.Note, entry.Billable,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// The entry was read just before, so a miss means the story check failed.
+			return timeentries.CoreTimeEntry{}, timeentries.ErrStoryNotFound
+		}
+		errMsg := fmt.Sprintf("failed to update time entry: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to update time entry"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return timeentries.CoreTimeEntry{}, err
+	}
+
+	return toCoreTimeEntry(row), nil
+}
+
+func (r *repo) Delete(ctx context.Context, id, workspaceID uuid.UUID) error {
+	r.log.Info(ctx, "business.repository.timeentries.Delete")
+	ctx, span := web.AddSpan(ctx, "business.repository.timeentries.Delete")
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM time_entries WHERE id = $1 AND workspace_id = $2`, id, workspaceID)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to delete time entry: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to delete time entry"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete time entry rows affected: %w", err)
+	}
+	if rows == 0 {
+		return timeentries.ErrNotFound
+	}
+	return nil
+}
+
+// StopTimer ends the user's running timer in the workspace at endedAt. A
+// timer stopped within a second of starting still ends after it starts.
+func (r *repo) StopTimer(ctx context.Context, workspaceID, userID uuid.UUID, endedAt time.Time) (timeentries.CoreTimeEntry, error) {
+	r.log.Info(ctx, "business.repository.timeentries.StopTimer")
+	ctx, span := web.AddSpan(ctx, "business.repository.timeentries.StopTimer")
+	defer span.End()
+
+	var row dbTimeEntry
+	query := `
+		UPDATE time_entries e
+		SET ended_at = GREATEST($2, e.started_at + INTERVAL '1 second'),
+			updated_at = NOW()
+		WHERE e.user_id = $1 AND e.workspace_id = $3 AND e.ended_at IS NULL
+		RETURNING ` + entryColumns
+	if err := r.db.GetContext(ctx, &row, query, userID, endedAt, workspaceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return timeentries.CoreTimeEntry{}, timeentries.ErrNoRunningTimer
+		}
+		errMsg := fmt.Sprintf("failed to stop timer: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to stop timer"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return timeentries.CoreTimeEntry{}, err
+	}
+
+	return toCoreTimeEntry(row), nil
+}
@@ -0,0 +1,64 @@
+package timeentriesrepository
+
+import (
+	"time"
+
+	timeentries "github.com/complexus-tech/projects-api/internal/modules/timeentries/service"
+	"github.com/google/uuid"
+)
+
+type dbTimeEntry struct {
+	ID          uuid.UUID  `db:"id"`
+	WorkspaceID uuid.UUID  `db:"workspace_id"`
+	StoryID     uuid.UUID  `db:"story_id"`
+	UserID      uuid.UUID  `db:"user_id"`
+	StartedAt   time.Time  `db:"started_at"`
+	EndedAt     *time.Time `db:"ended_at"`
+	Note        string     `db:"note"`
+	Billable    bool       `db:"billable"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+type dbTimesheetEntry struct {
+	dbTimeEntry
+	TeamID     uuid.UUID `db:"team_id"`
+	SequenceID int       `db:"sequence_id"`
+	StoryTitle string    `db:"story_title"`
+}
+
+func toCoreTimeEntry(e dbTimeEntry) timeentries.CoreTimeEntry {
+	return timeentries.CoreTimeEntry{
+		ID:          e.ID,
+		WorkspaceID: e.WorkspaceID,
+		StoryID:     e.StoryID,
+		UserID:      e.UserID,
+		StartedAt:   e.StartedAt,
+		EndedAt:     e.EndedAt,
+		Note:        e.Note,
+		Billable:    e.Billable,
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
+	}
+}
+
+func toCoreTimeEntries(entries []dbTimeEntry) []timeentries.CoreTimeEntry {
+	result := make([]timeentries.CoreTimeEntry, len(entries))
+	for i, e := range entries {
+		result[i] = toCoreTimeEntry(e)
+	}
+	return result
+}
+
+func toCoreTimesheetEntries(entries []dbTimesheetEntry) []timeentries.CoreTimesheetEntry {
+	result := make([]timeentries.CoreTimesheetEntry, len(entries))
+	for i, e := range entries {
+		result[i] = timeentries.CoreTimesheetEntry{
+			CoreTimeEntry: toCoreTimeEntry(e.dbTimeEntry),
+			TeamID:        e.TeamID,
+			SequenceID:    e.SequenceID,
+			StoryTitle:    e.StoryTitle,
+		}
+	}
+	return result
+}
@@ -0,0 +1,159 @@
+package timeentriesrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	timeentries "github.com/complexus-tech/projects-api/internal/modules/timeentries/service"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxListEntries caps entry lists; timesheets are not capped.
+const maxListEntries = 500
+
+const entryColumns = `
+		e.id,
+		e.workspace_id,
+		e.story_id,
+		e.user_id,
+		e.started_at,
+		e.ended_at,
+		e.note,
+		e.billable,
+		e.created_at,
+		e.updated_at`
+
+// entryFilters matches entries in workspace $1 against the filters in $2-$6.
+// A running entry overlaps every range that starts before now.
+const entryFilters = `
+	e.workspace_id = $1
+	AND ($2::uuid IS NULL OR e.story_id = $2)
+	AND ($3::uuid IS NULL OR e.user_id = $3)
+	AND ($4::uuid IS NULL OR s.team_id = $4)
+	AND ($5::timestamptz IS NULL OR COALESCE(e.ended_at, NOW()) > $5)
+	AND ($6::timestamptz IS NULL OR e.started_at < $6)
+	AND s.deleted_at IS NULL`
+
+func filterArgs(workspaceID uuid.UUID, filters timeentries.CoreTimeEntryFilters) []any {
+	return []any{workspaceID, filters.StoryID, filters.UserID, filters.TeamID, filters.From, filters.To}
+}
+
+func (r *repo) List(ctx context.Context, workspaceID uuid.UUID, filters timeentries.CoreTimeEntryFilters) ([]timeentries.CoreTimeEntry, error) {
+	r.log.Info(ctx, "business.repository.timeentries.List")
+	ctx, span := web.AddSpan(ctx, "business.repository.timeentries.List")
+	defer span.End()
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM time_entries e
+		INNER JOIN stories s ON s.id = e.story_id
+		WHERE %s
+		ORDER BY e.started_at DESC
+		LIMIT %d`, entryColumns, entryFilters, maxListEntries)
+
+	var rows []dbTimeEntry
+	if err := r.db.SelectContext(ctx, &rows, query, filterArgs(workspaceID, filters)...); err != nil {
+		errMsg := fmt.Sprintf("failed to list time entries: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to list time entries"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	return toCoreTimeEntries(rows), nil
+}
+
+func (r *repo) Get(ctx context.Context, id, workspaceID uuid.UUID) (timeentries.CoreTimeEntry, error) {
+	r.log.Info(ctx, "business.repository.timeentries.Get")
+	ctx, span := web.AddSpan(ctx, "business.repository.timeentries.Get")
+	defer span.End()
+
+	var row dbTimeEntry
+	query := `SELECT ` + entryColumns + ` FROM time_entries e WHERE e.id = $1 AND e.workspace_id = $2`
+	if err := r.db.GetContext(ctx, &row, query, id, workspaceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return timeentries.CoreTimeEntry{}, timeentries.ErrNotFound
+		}
+		errMsg := fmt.Sprintf("failed to get time entry: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get time entry"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return timeentries.CoreTimeEntry{}, err
+	}
+
+	return toCoreTimeEntry(row), nil
+}
+
+// RunningTimer returns the user's running timer in the workspace.
+func (r *repo) RunningTimer(ctx context.Context, workspaceID, userID uuid.UUID) (timeentries.CoreTimeEntry, error) {
+	r.log.Info(ctx, "business.repository.timeentries.RunningTimer")
+	ctx, span := web.AddSpan(ctx, "business.repository.timeentries.RunningTimer")
+	defer span.End()
+
+	var row dbTimeEntry
+	query := `SELECT ` + entryColumns + ` FROM time_entries e WHERE e.user_id = $1 AND e.workspace_id = $2 AND e.ended_at IS NULL`
+	if err := r.db.GetContext(ctx, &row, query, userID, workspaceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return timeentries.CoreTimeEntry{}, timeentries.ErrNoRunningTimer
+		}
+		errMsg := fmt.Sprintf("failed to get running timer: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get running timer"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return timeentries.CoreTimeEntry{}, err
+	}
+
+	return toCoreTimeEntry(row), nil
+}
+
+// TimesheetEntries returns every matching entry with its story's details.
+func (r *repo) TimesheetEntries(ctx context.Context, workspaceID uuid.UUID, filters timeentries.CoreTimeEntryFilters) ([]timeentries.CoreTimesheetEntry, error) {
+	r.log.Info(ctx, "business.repository.timeentries.TimesheetEntries")
+	ctx, span := web.AddSpan(ctx, "business.repository.timeentries.TimesheetEntries")
+	defer span.End()
+
+	query := fmt.Sprintf(`
+		SELECT %s,
+			s.team_id,
+			s.sequence_id,
+			s.title AS story_title
+		FROM time_entries e
+		INNER JOIN stories s ON s.id = e.story_id
+		WHERE %s
+		ORDER BY e.started_at ASC`, entryColumns, entryFilters)
+
+	var rows []dbTimesheetEntry
+	if err := r.db.SelectContext(ctx, &rows, query, filterArgs(workspaceID, filters)...); err != nil {
+		errMsg := fmt.Sprintf("failed to get timesheet entries: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to get timesheet entries"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return nil, err
+	}
+
+	return toCoreTimesheetEntries(rows), nil
+}
+
+func (r *repo) IsTeamMember(ctx context.Context, workspaceID, teamID, userID uuid.UUID) (bool, error) {
+	r.log.Info(ctx, "business.repository.timeentries.IsTeamMember")
+	ctx, span := web.AddSpan(ctx, "business.repository.timeentries.IsTeamMember")
+	defer span.End()
+
+	var member bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM team_members tm
+			INNER JOIN teams t ON t.team_id = tm.team_id
+			WHERE tm.team_id = $2 AND tm.user_id = $3 AND t.workspace_id = $1
+		)`
+	if err := r.db.GetContext(ctx, &member, query, workspaceID, teamID, userID); err != nil {
+		errMsg := fmt.Sprintf("failed to check team membership: %s", err)
+		r.log.Error(ctx, errMsg)
+		span.RecordError(errors.New("failed to check team membership"), trace.WithAttributes(attribute.String("error", errMsg)))
+		return false, err
+	}
+
+	return member, nil
+}
@@ -0,0 +1,119 @@
+package timeentries
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CoreTimeEntry is time a user spent on a story. A running timer has no
+// EndedAt.
+type CoreTimeEntry struct {
+	ID          uuid.UUID
+	WorkspaceID uuid.UUID
+	StoryID     uuid.UUID
+	UserID      uuid.UUID
+	StartedAt   time.Time
+	EndedAt     *time.Time
+	Note        string
+	Billable    bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// IsRunning reports whether the entry is a running timer.
+func (e CoreTimeEntry) IsRunning() bool {
+	return e.EndedAt == nil
+}
+
+// Duration returns the length of the entry, counting a running timer up to
+// now.
+func (e CoreTimeEntry) Duration(now time.Time) time.Duration {
+	end := now
+	if e.EndedAt != nil {
+		end = *e.EndedAt
+	}
+	if end.Before(e.StartedAt) {
+		return 0
+	}
+	return end.Sub(e.StartedAt)
+}
+
+// CoreNewTimeEntry holds the fields for logging time. The entry is given
+// either by StartedAt and EndedAt or by DurationMinutes; a duration without
+// StartedAt ends now.
+type CoreNewTimeEntry struct {
+	StoryID         uuid.UUID
+	StartedAt       *time.Time
+	EndedAt         *time.Time
+	DurationMinutes *int
+	Note            string
+	Billable        bool
+}
+
+// CoreUpdateTimeEntry holds the fields that can be changed on an entry. Nil
+// fields are left as they are. Moving StartedAt alone keeps the duration.
+type CoreUpdateTimeEntry struct {
+	StoryID         *uuid.UUID
+	StartedAt       *time.Time
+	EndedAt         *time.Time
+	DurationMinutes *int
+	Note            *string
+	Billable        *bool
+}
+
+// CoreStartTimer holds the fields for starting a timer.
+type CoreStartTimer struct {
+	StoryID  uuid.UUID
+	Note     string
+	Billable bool
+}
+
+// CoreTimeEntryFilters narrows entry lists. From and To select entries that
+// overlap the range.
+type CoreTimeEntryFilters struct {
+	StoryID *uuid.UUID
+	UserID  *uuid.UUID
+	TeamID  *uuid.UUID
+	From    *time.Time
+	To      *time.Time
+}
+
+// CoreTimesheetEntry is an entry with the story details timesheets show.
+type CoreTimesheetEntry struct {
+	CoreTimeEntry
+	TeamID     uuid.UUID
+	SequenceID int
+	StoryTitle string
+}
+
+// CoreTimesheet is the time logged in one week, Monday to Sunday in
+// Location. Day arrays are indexed from Monday.
+type CoreTimesheet struct {
+	WeekStart       time.Time
+	Location        *time.Location
+	Users           []CoreTimesheetUser
+	Days            [7]int
+	TotalMinutes    int
+	BillableMinutes int
+}
+
+// CoreTimesheetUser is one user's row in a timesheet.
+type CoreTimesheetUser struct {
+	UserID          uuid.UUID
+	Stories         []CoreTimesheetStory
+	Days            [7]int
+	TotalMinutes    int
+	BillableMinutes int
+}
+
+// CoreTimesheetStory is the time a user logged on one story in the week.
+type CoreTimesheetStory struct {
+	StoryID         uuid.UUID
+	TeamID          uuid.UUID
+	SequenceID      int
+	Title           string
+	Days            [7]int
+	TotalMinutes    int
+	BillableMinutes int
+}
@@ -0,0 +1,391 @@
+package timeentries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	maxNoteLength = 500
+	// maxEntryDuration caps logged entries; timers may run longer.
+	maxEntryDuration = 24 * time.Hour
+)
+
+// Service errors
+var (
+	ErrNotFound          = errors.New("time entry not found")
+	ErrStoryNotFound     = errors.New("story not found")
+	ErrForbidden         = errors.New("only the entry's owner or a workspace admin can change this entry")
+	ErrTimesheetDenied   = errors.New("you cannot view this timesheet")
+	ErrTimerRunning      = errors.New("a timer is already running; stop it first")
+	ErrNoRunningTimer    = errors.New("no timer is running")
+	ErrEntryRunning      = errors.New("stop the timer before changing when it ends")
+	ErrDurationRequired  = errors.New("either endedAt or durationMinutes is required")
+	ErrAmbiguousDuration = errors.New("endedAt and durationMinutes cannot both be given")
+	ErrStartRequired     = errors.New("startedAt is required with endedAt")
+	ErrInvalidRange      = errors.New("time entry must end after it starts")
+	ErrInvalidDuration   = fmt.Errorf("time entry duration must be between 1 minute and %d hours", int(maxEntryDuration.Hours()))
+	ErrStartInFuture     = errors.New("a running timer cannot start in the future")
+	ErrNoteTooLong       = fmt.Errorf("time entry note cannot be longer than %d characters", maxNoteLength)
+)
+
+// Repository provides access to the time entries storage.
+type Repository interface {
+	List(ctx context.Context, workspaceID uuid.UUID, filters CoreTimeEntryFilters) ([]CoreTimeEntry, error)
+	Get(ctx context.Context, id, workspaceID uuid.UUID) (CoreTimeEntry, error)
+	Create(ctx context.Context, entry CoreTimeEntry) (CoreTimeEntry, error)
+	Update(ctx context.Context, entry CoreTimeEntry) (CoreTimeEntry, error)
+	Delete(ctx context.Context, id, workspaceID uuid.UUID) error
+	RunningTimer(ctx context.Context, workspaceID, userID uuid.UUID) (CoreTimeEntry, error)
+	StopTimer(ctx context.Context, workspaceID, userID uuid.UUID, endedAt time.Time) (CoreTimeEntry, error)
+	TimesheetEntries(ctx context.Context, workspaceID uuid.UUID, filters CoreTimeEntryFilters) ([]CoreTimesheetEntry, error)
+	IsTeamMember(ctx context.Context, workspaceID, teamID, userID uuid.UUID) (bool, error)
+}
+
+// Service provides time tracking operations.
+type Service struct {
+	repo Repository
+	log  *logger.Logger
+}
+
+// New constructs a new time entries service instance with the provided repository.
+func New(log *logger.Logger, repo Repository) *Service {
+	return &Service{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// List returns entries in the workspace, newest first.
+func (s *Service) List(ctx context.Context, workspaceID uuid.UUID, filters CoreTimeEntryFilters) ([]CoreTimeEntry, error) {
+	s.log.Info(ctx, "business.core.timeentries.list")
+	ctx, span := web.AddSpan(ctx, "business.core.timeentries.List")
+	defer span.End()
+
+	entries, err := s.repo.List(ctx, workspaceID, filters)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.AddEvent("time entries retrieved.", trace.WithAttributes(
+		attribute.Int("time_entry.count", len(entries)),
+	))
+	return entries, nil
+}
+
+// Create logs time on a story for the user.
+func (s *Service) Create(ctx context.Context, workspaceID, userID uuid.UUID, ne CoreNewTimeEntry) (CoreTimeEntry, error) {
+	s.log.Info(ctx, "business.core.timeentries.create")
+	ctx, span := web.AddSpan(ctx, "business.core.timeentries.Create")
+	defer span.End()
+
+	note, err := normalizeNote(ne.Note)
+	if err != nil {
+		return CoreTimeEntry{}, err
+	}
+	startedAt, endedAt, err := entryRange(time.Now().UTC(), ne.StartedAt, ne.EndedAt, ne.DurationMinutes)
+	if err != nil {
+		return CoreTimeEntry{}, err
+	}
+
+	entry, err := s.repo.Create(ctx, CoreTimeEntry{
+		WorkspaceID: workspaceID,
+		StoryID:     ne.StoryID,
+		UserID:      userID,
+		StartedAt:   startedAt,
+		EndedAt:     &endedAt,
+		Note:        note,
+		Billable:    ne.Billable,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return CoreTimeEntry{}, err
+	}
+
+	span.AddEvent("time entry created.", trace.WithAttributes(
+		attribute.String("time_entry.id", entry.ID.String()),
+	))
+	return entry, nil
+}
+
+// Update changes an entry. Only its owner or a workspace admin may change it,
+// and a running timer keeps running.
+func (s *Service) Update(ctx context.Context, id, workspaceID, userID uuid.UUID, isAdmin bool, ue CoreUpdateTimeEntry) (CoreTimeEntry, error) {
+	s.log.Info(ctx, "business.core.timeentries.update")
+	ctx, span := web.AddSpan(ctx, "business.core.timeentries.Update")
+	defer span.End()
+
+	entry, err := s.repo.Get(ctx, id, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreTimeEntry{}, err
+	}
+	if entry.UserID != userID && !isAdmin {
+		return CoreTimeEntry{}, ErrForbidden
+	}
+
+	if ue.Note != nil {
+		if entry.Note, err = normalizeNote(*ue.Note); err != nil {
+			return CoreTimeEntry{}, err
+		}
+	}
+	if ue.Billable != nil {
+		entry.Billable = *ue.Billable
+	}
+	if ue.StoryID != nil {
+		entry.StoryID = *ue.StoryID
+	}
+	if entry, err = updateRange(entry, time.Now().UTC(), ue); err != nil {
+		return CoreTimeEntry{}, err
+	}
+
+	entry, err = s.repo.Update(ctx, entry)
+	if err != nil {
+		span.RecordError(err)
+		return CoreTimeEntry{}, err
+	}
+
+	span.AddEvent("time entry updated.", trace.WithAttributes(
+		attribute.String("time_entry.id", entry.ID.String()),
+	))
+	return entry, nil
+}
+
+// Delete removes an entry. Only its owner or a workspace admin may delete it.
+func (s *Service) Delete(ctx context.Context, id, workspaceID, userID uuid.UUID, isAdmin bool) error {
+	s.log.Info(ctx, "business.core.timeentries.delete")
+	ctx, span := web.AddSpan(ctx, "business.core.timeentries.Delete")
+	defer span.End()
+
+	entry, err := s.repo.Get(ctx, id, workspaceID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if entry.UserID != userID && !isAdmin {
+		return ErrForbidden
+	}
+
+	if err := s.repo.Delete(ctx, id, workspaceID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	span.AddEvent("time entry deleted.", trace.WithAttributes(
+		attribute.String("time_entry.id", id.String()),
+	))
+	return nil
+}
+
+// RunningTimer returns the user's running timer in the workspace.
+func (s *Service) RunningTimer(ctx context.Context, workspaceID, userID uuid.UUID) (CoreTimeEntry, error) {
+	s.log.Info(ctx, "business.core.timeentries.runningTimer")
+	ctx, span := web.AddSpan(ctx, "business.core.timeentries.RunningTimer")
+	defer span.End()
+
+	entry, err := s.repo.RunningTimer(ctx, workspaceID, userID)
+	if err != nil {
+		span.RecordError(err)
+		return CoreTimeEntry{}, err
+	}
+	return entry, nil
+}
+
+// StartTimer starts a timer on a story. A user has at most one running timer,
+// so starting another fails with ErrTimerRunning.
+func (s *Service) StartTimer(ctx context.Context, workspaceID, userID uuid.UUID, st CoreStartTimer) (CoreTimeEntry, error) {
+	s.log.Info(ctx, "business.core.timeentries.startTimer")
+	ctx, span := web.AddSpan(ctx, "business.core.timeentries.StartTimer")
+	defer span.End()
+
+	note, err := normalizeNote(st.Note)
+	if err != nil {
+		return CoreTimeEntry{}, err
+	}
+
+	entry, err := s.repo.Create(ctx, CoreTimeEntry{
+		WorkspaceID: workspaceID,
+		StoryID:     st.StoryID,
+		UserID:      userID,
+		StartedAt:   time.Now().UTC(),
+		Note:        note,
+		Billable:    st.Billable,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return CoreTimeEntry{}, err
+	}
+
+	span.AddEvent("timer started.", trace.WithAttributes(
+		attribute.String("time_entry.id", entry.ID.String()),
+		attribute.String("story.id", st.StoryID.String()),
+	))
+	return entry, nil
+}
+
+// StopTimer stops the user's running timer in the workspace and returns the
+// finished entry.
+func (s *Service) StopTimer(ctx context.Context, workspaceID, userID uuid.UUID) (CoreTimeEntry, error) {
+	s.log.Info(ctx, "business.core.timeentries.stopTimer")
+	ctx, span := web.AddSpan(ctx, "business.core.timeentries.StopTimer")
+	defer span.End()
+
+	entry, err := s.repo.StopTimer(ctx, workspaceID, userID, time.Now().UTC())
+	if err != nil {
+		span.RecordError(err)
+		return CoreTimeEntry{}, err
+	}
+
+	span.AddEvent("timer stopped.", trace.WithAttributes(
+		attribute.String("time_entry.id", entry.ID.String()),
+	))
+	return entry, nil
+}
+
+// UserTimesheet returns the week containing day for one user. Users can see
+// their own timesheet; admins can see anyone's.
+func (s *Service) UserTimesheet(ctx context.Context, workspaceID, viewerID uuid.UUID, isAdmin bool, userID uuid.UUID, day time.Time, loc *time.Location) (CoreTimesheet, error) {
+	s.log.Info(ctx, "business.core.timeentries.userTimesheet")
+	ctx, span := web.AddSpan(ctx, "business.core.timeentries.UserTimesheet")
+	defer span.End()
+
+	if viewerID != userID && !isAdmin {
+		return CoreTimesheet{}, ErrTimesheetDenied
+	}
+	return s.timesheet(ctx, workspaceID, CoreTimeEntryFilters{UserID: &userID}, day, loc)
+}
+
+// TeamTimesheet returns the week containing day for everyone who logged time
+// on the team's stories. Team members and admins can see it.
+func (s *Service) TeamTimesheet(ctx context.Context, workspaceID, viewerID uuid.UUID, isAdmin bool, teamID uuid.UUID, day time.Time, loc *time.Location) (CoreTimesheet, error) {
+	s.log.Info(ctx, "business.core.timeentries.teamTimesheet")
+	ctx, span := web.AddSpan(ctx, "business.core.timeentries.TeamTimesheet")
+	defer span.End()
+
+	if !isAdmin {
+		member, err := s.repo.IsTeamMember(ctx, workspaceID, teamID, viewerID)
+		if err != nil {
+			span.RecordError(err)
+			return CoreTimesheet{}, err
+		}
+		if !member {
+			return CoreTimesheet{}, ErrTimesheetDenied
+		}
+	}
+	return s.timesheet(ctx, workspaceID, CoreTimeEntryFilters{TeamID: &teamID}, day, loc)
+}
+
+func (s *Service) timesheet(ctx context.Context, workspaceID uuid.UUID, filters CoreTimeEntryFilters, day time.Time, loc *time.Location) (CoreTimesheet, error) {
+	weekStart := WeekStart(day, loc)
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	filters.From = &weekStart
+	filters.To = &weekEnd
+
+	entries, err := s.repo.TimesheetEntries(ctx, workspaceID, filters)
+	if err != nil {
+		return CoreTimesheet{}, fmt.Errorf("getting timesheet entries: %w", err)
+	}
+	return buildTimesheet(entries, weekStart, time.Now().UTC()), nil
+}
+
+// normalizeNote trims a note and checks its length.
+func normalizeNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		return "", ErrNoteTooLong
+	}
+	return note, nil
+}
+
+// entryRange works out when a logged entry starts and ends.
+func entryRange(now time.Time, startedAt, endedAt *time.Time, durationMinutes *int) (time.Time, time.Time, error) {
+	switch {
+	case endedAt != nil && durationMinutes != nil:
+		return time.Time{}, time.Time{}, ErrAmbiguousDuration
+	case durationMinutes != nil:
+		duration := time.Duration(*durationMinutes) * time.Minute
+		if duration < time.Minute || duration > maxEntryDuration {
+			return time.Time{}, time.Time{}, ErrInvalidDuration
+		}
+		if startedAt == nil {
+			return now.Add(-duration), now, nil
+		}
+		return *startedAt, startedAt.Add(duration), nil
+	case endedAt != nil:
+		if startedAt == nil {
+			return time.Time{}, time.Time{}, ErrStartRequired
+		}
+		if err := checkRange(*startedAt, *endedAt); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		return *startedAt, *endedAt, nil
+	default:
+		return time.Time{}, time.Time{}, ErrDurationRequired
+	}
+}
+
+// updateRange applies changed times to an entry.
+func updateRange(entry CoreTimeEntry, now time.Time, ue CoreUpdateTimeEntry) (CoreTimeEntry, error) {
+	if ue.EndedAt != nil && ue.DurationMinutes != nil {
+		return entry, ErrAmbiguousDuration
+	}
+
+	if entry.IsRunning() {
+		if ue.EndedAt != nil || ue.DurationMinutes != nil {
+			return entry, ErrEntryRunning
+		}
+		if ue.StartedAt != nil {
+			if ue.StartedAt.After(now) {
+				return entry, ErrStartInFuture
+			}
+			entry.StartedAt = *ue.StartedAt
+		}
+		return entry, nil
+	}
+
+	// Stopped timers may have run longer than a logged entry may last, so
+	// the range is only checked when it changes.
+	if ue.StartedAt == nil && ue.EndedAt == nil && ue.DurationMinutes == nil {
+		return entry, nil
+	}
+
+	duration := entry.EndedAt.Sub(entry.StartedAt)
+	if ue.StartedAt != nil {
+		entry.StartedAt = *ue.StartedAt
+	}
+	var endedAt time.Time
+	switch {
+	case ue.DurationMinutes != nil:
+		endedAt = entry.StartedAt.Add(time.Duration(*ue.DurationMinutes) * time.Minute)
+	case ue.EndedAt != nil:
+		endedAt = *ue.EndedAt
+	default:
+		endedAt = entry.StartedAt.Add(duration)
+	}
+	if err := checkRange(entry.StartedAt, endedAt); err != nil {
+		return entry, err
+	}
+	entry.EndedAt = &endedAt
+	return entry, nil
+}
+
+func checkRange(startedAt, endedAt time.Time) error {
+	if !endedAt.After(startedAt) {
+		return ErrInvalidRange
+	}
+	duration := endedAt.Sub(startedAt)
+	if duration < time.Minute || duration > maxEntryDuration {
+		return ErrInvalidDuration
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package timeentries
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEntryRange(t *testing.T) {
+	now := time.Date(2025, 3, 14, 15, 0, 0, 0, time.UTC)
+	start := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 3, 14, 10, 30, 0, 0, time.UTC)
+	minutes := func(m int) *int { return &m }
+
+	tests := []struct {
+		name      string
+		startedAt *time.Time
+		endedAt   *time.Time
+		duration  *int
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   error
+	}{
+		{name: "start and end", startedAt: &start, endedAt: &end, wantStart: start, wantEnd: end},
+		{name: "start and duration", startedAt: &start, duration: minutes(45), wantStart: start, wantEnd: start.Add(45 * time.Minute)},
+		{name: "duration ends now", duration: minutes(30), wantStart: now.Add(-30 * time.Minute), wantEnd: now},
+		{name: "both end and duration", startedAt: &start, endedAt: &end, duration: minutes(30), wantErr: ErrAmbiguousDuration},
+		{name: "end without start", endedAt: &end, wantErr: ErrStartRequired},
+		{name: "nothing", startedAt: &start, wantErr: ErrDurationRequired},
+		{name: "end before start", startedAt: &end, endedAt: &start, wantErr: ErrInvalidRange},
+		{name: "zero duration", duration: minutes(0), wantErr: ErrInvalidDuration},
+		{name: "over a day", duration: minutes(25 * 60), wantErr: ErrInvalidDuration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStart, gotEnd, err := entryRange(now, tt.startedAt, tt.endedAt, tt.duration)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("entryRange() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (!gotStart.Equal(tt.wantStart) || !gotEnd.Equal(tt.wantEnd)) {
+				t.Errorf("entryRange() = %v..%v, want %v..%v", gotStart, gotEnd, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestUpdateRange(t *testing.T) {
+	now := time.Date(2025, 3, 14, 15, 0, 0, 0, time.UTC)
+	start := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	moved := start.Add(2 * time.Hour)
+
+	entry, err := updateRange(CoreTimeEntry{StartedAt: start, EndedAt: &end}, now, CoreUpdateTimeEntry{StartedAt: &moved})
+	if err != nil {
+		t.Fatalf("updateRange() error = %v", err)
+	}
+	if !entry.StartedAt.Equal(moved) || !entry.EndedAt.Equal(moved.Add(time.Hour)) {
+		t.Errorf("moving the start should keep the duration, got %v..%v", entry.StartedAt, entry.EndedAt)
+	}
+
+	longEnd := start.Add(30 * time.Hour)
+	if _, err := updateRange(CoreTimeEntry{StartedAt: start, EndedAt: &longEnd}, now, CoreUpdateTimeEntry{}); err != nil {
+		t.Errorf("keeping the range of a long timer error = %v, want nil", err)
+	}
+	if _, err := updateRange(CoreTimeEntry{StartedAt: start, EndedAt: &longEnd}, now, CoreUpdateTimeEntry{StartedAt: &moved}); !errors.Is(err, ErrInvalidDuration) {
+		t.Errorf("moving a long timer error = %v, want %v", err, ErrInvalidDuration)
+	}
+
+	running := CoreTimeEntry{StartedAt: start}
+	if _, err := updateRange(running, now, CoreUpdateTimeEntry{EndedAt: &end}); !errors.Is(err, ErrEntryRunning) {
+		t.Errorf("ending a running timer error = %v, want %v", err, ErrEntryRunning)
+	}
+	future := now.Add(time.Minute)
+	if _, err := updateRange(running, now, CoreUpdateTimeEntry{StartedAt: &future}); !errors.Is(err, ErrStartInFuture) {
+		t.Errorf("starting a timer in the future error = %v, want %v", err, ErrStartInFuture)
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	harare, err := time.LoadLocation("Africa/Harare")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+
+	// Sunday 23:30 UTC is already Monday in Harare.
+	day := time.Date(2025, 3, 16, 23, 30, 0, 0, time.UTC)
+	if got, want := WeekStart(day, time.UTC), time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("WeekStart(UTC) = %v, want %v", got, want)
+	}
+	if got, want := WeekStart(day, harare), time.Date(2025, 3, 17, 0, 0, 0, 0, harare); !got.Equal(want) {
+		t.Errorf("WeekStart(Harare) = %v, want %v", got, want)
+	}
+}
+
+func TestBuildTimesheet(t *testing.T) {
+	weekStart := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 3, 12, 10, 0, 0, 0, time.UTC)
+	alice, bob := uuid.New(), uuid.New()
+	storyA, storyB := uuid.New(), uuid.New()
+
+	entry := func(user, story uuid.UUID, start time.Time, duration time.Duration, billable bool) CoreTimesheetEntry {
+		e := CoreTimesheetEntry{
+			CoreTimeEntry: CoreTimeEntry{UserID: user, StoryID: story, StartedAt: start, Billable: billable},
+			SequenceID:    1,
+		}
+		if duration > 0 {
+			end := start.Add(duration)
+			e.EndedAt = &end
+		}
+		return e
+	}
+
+	sheet := buildTimesheet([]CoreTimesheetEntry{
+		// Crosses midnight into Tuesday.
+		entry(alice, storyA, time.Date(2025, 3, 10, 23, 0, 0, 0, time.UTC), 2*time.Hour, true),
+		entry(alice, storyB, time.Date(2025, 3, 11, 9, 0, 0, 0, time.UTC), 30*time.Minute, false),
+		// Started the Sunday before the week.
+		entry(alice, storyA, time.Date(2025, 3, 9, 23, 30, 0, 0, time.UTC), time.Hour, false),
+		// Running timer counts up to now.
+		entry(bob, storyA, time.Date(2025, 3, 12, 8, 0, 0, 0, time.UTC), 0, true),
+	}, weekStart, now)
+
+	if sheet.TotalMinutes != 300 || sheet.BillableMinutes != 240 {
+		t.Fatalf("totals = %d (%d billable), want 300 (240 billable)", sheet.TotalMinutes, sheet.BillableMinutes)
+	}
+	if want := [7]int{90, 90, 120, 0, 0, 0, 0}; sheet.Days != want {
+		t.Errorf("days = %v, want %v", sheet.Days, want)
+	}
+	if len(sheet.Users) != 2 || sheet.Users[0].UserID != alice {
+		t.Fatalf("users = %+v, want alice first", sheet.Users)
+	}
+
+	aliceRow := sheet.Users[0]
+	if len(aliceRow.Stories) != 2 || aliceRow.Stories[0].StoryID != storyA {
+		t.Fatalf("alice stories = %+v", aliceRow.Stories)
+	}
+	if want := [7]int{90, 60, 0, 0, 0, 0, 0}; aliceRow.Stories[0].Days != want {
+		t.Errorf("story A days = %v, want %v", aliceRow.Stories[0].Days, want)
+	}
+	if aliceRow.BillableMinutes != 120 {
+		t.Errorf("alice billable = %d, want 120", aliceRow.BillableMinutes)
+	}
+}
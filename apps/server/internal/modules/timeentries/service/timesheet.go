@@ -0,0 +1,131 @@
+package timeentries
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WeekStart returns midnight on the Monday of the week containing day in loc.
+func WeekStart(day time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	day = day.In(loc)
+	offset := (int(day.Weekday()) + 6) % 7
+	return time.Date(day.Year(), day.Month(), day.Day()-offset, 0, 0, 0, 0, loc)
+}
+
+type timesheetTotals struct {
+	days     [7]time.Duration
+	billable time.Duration
+}
+
+// buildTimesheet sums entries into the week starting at weekStart. Entries are
+// split at local midnights so time past midnight counts on the next day, and
+// running timers count up to now. Durations are rounded to minutes per day.
+func buildTimesheet(entries []CoreTimesheetEntry, weekStart, now time.Time) CoreTimesheet {
+	loc := weekStart.Location()
+	sheet := CoreTimesheet{WeekStart: weekStart, Location: loc}
+
+	var dayStarts [8]time.Time
+	for i := range dayStarts {
+		dayStarts[i] = weekStart.AddDate(0, 0, i)
+	}
+
+	type storyKey struct{ user, story uuid.UUID }
+	totals := make(map[storyKey]*timesheetTotals)
+	stories := make(map[storyKey]CoreTimesheetEntry)
+	for _, entry := range entries {
+		start := entry.StartedAt
+		end := now
+		if entry.EndedAt != nil {
+			end = *entry.EndedAt
+		}
+
+		key := storyKey{entry.UserID, entry.StoryID}
+		t, ok := totals[key]
+		if !ok {
+			t = &timesheetTotals{}
+			totals[key] = t
+			stories[key] = entry
+		}
+		for d := 0; d < 7; d++ {
+			from := later(start, dayStarts[d])
+			to := earlier(end, dayStarts[d+1])
+			if !to.After(from) {
+				continue
+			}
+			t.days[d] += to.Sub(from)
+			if entry.Billable {
+				t.billable += to.Sub(from)
+			}
+		}
+	}
+
+	users := make(map[uuid.UUID]*CoreTimesheetUser)
+	for key, t := range totals {
+		entry := stories[key]
+		story := CoreTimesheetStory{
+			StoryID:         entry.StoryID,
+			TeamID:          entry.TeamID,
+			SequenceID:      entry.SequenceID,
+			Title:           entry.StoryTitle,
+			BillableMinutes: toMinutes(t.billable),
+		}
+		for d, duration := range t.days {
+			story.Days[d] = toMinutes(duration)
+			story.TotalMinutes += story.Days[d]
+		}
+		if story.TotalMinutes == 0 {
+			continue
+		}
+
+		user, ok := users[key.user]
+		if !ok {
+			user = &CoreTimesheetUser{UserID: key.user}
+			users[key.user] = user
+		}
+		user.Stories = append(user.Stories, story)
+		for d := range story.Days {
+			user.Days[d] += story.Days[d]
+			sheet.Days[d] += story.Days[d]
+		}
+		user.TotalMinutes += story.TotalMinutes
+		user.BillableMinutes += story.BillableMinutes
+		sheet.TotalMinutes += story.TotalMinutes
+		sheet.BillableMinutes += story.BillableMinutes
+	}
+
+	sheet.Users = make([]CoreTimesheetUser, 0, len(users))
+	for _, user := range users {
+		slices.SortFunc(user.Stories, func(a, b CoreTimesheetStory) int {
+			return cmp.Or(cmp.Compare(b.TotalMinutes, a.TotalMinutes), cmp.Compare(a.SequenceID, b.SequenceID))
+		})
+		sheet.Users = append(sheet.Users, *user)
+	}
+	slices.SortFunc(sheet.Users, func(a, b CoreTimesheetUser) int {
+		return cmp.Or(cmp.Compare(b.TotalMinutes, a.TotalMinutes), cmp.Compare(a.UserID.String(), b.UserID.String()))
+	})
+	return sheet
+}
+
+func toMinutes(d time.Duration) int {
+	return int(d.Round(time.Minute) / time.Minute)
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,100 @@
+package webhookshttp
+
+import (
+	"encoding/json"
+	"time"
+
+	webhooks "github.com/complexus-tech/projects-api/internal/modules/webhooks/service"
+	"github.com/google/uuid"
+)
+
+type AppEndpoint struct {
+	ID                  uuid.UUID  `json:"id"`
+	WorkspaceID         uuid.UUID  `json:"workspaceId"`
+	URL                 string     `json:"url"`
+	Description         string     `json:"description"`
+	EventTypes          []string   `json:"eventTypes"`
+	IsActive            bool       `json:"isActive"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	DisabledAt          *time.Time `json:"disabledAt"`
+	CreatedBy           *uuid.UUID `json:"createdBy"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	UpdatedAt           time.Time  `json:"updatedAt"`
+}
+
+// AppEndpointWithSecret is only returned when an endpoint is created or its secret rotated.
+type AppEndpointWithSecret struct {
+	AppEndpoint
+	Secret string `json:"secret"`
+}
+
+type AppNewEndpoint struct {
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"eventTypes"`
+}
+
+type AppUpdateEndpoint struct {
+	URL         *string  `json:"url"`
+	Description *string  `json:"description"`
+	EventTypes  []string `json:"eventTypes"`
+	IsActive    *bool    `json:"isActive"`
+}
+
+type AppDelivery struct {
+	ID            uuid.UUID       `json:"id"`
+	EndpointID    uuid.UUID       `json:"endpointId"`
+	EventType     string          `json:"eventType"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	ResponseCode  *int            `json:"responseCode"`
+	ResponseBody  *string         `json:"responseBody,omitempty"`
+	Error         *string         `json:"error"`
+	RedeliveryOf  *uuid.UUID      `json:"redeliveryOf"`
+	LastAttemptAt *time.Time      `json:"lastAttemptAt"`
+	DeliveredAt   *time.Time      `json:"deliveredAt"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+func toAppEndpoint(endpoint webhooks.CoreEndpoint) AppEndpoint {
+	return AppEndpoint{
+		ID:                  endpoint.ID,
+		WorkspaceID:         endpoint.WorkspaceID,
+		URL:                 endpoint.URL,
+		Description:         endpoint.Description,
+		EventTypes:          endpoint.EventTypes,
+		IsActive:            endpoint.IsActive,
+		ConsecutiveFailures: endpoint.ConsecutiveFailures,
+		DisabledAt:          endpoint.DisabledAt,
+		CreatedBy:           endpoint.CreatedBy,
+		CreatedAt:           endpoint.CreatedAt,
+		UpdatedAt:           endpoint.UpdatedAt,
+	}
+}
+
+func toAppEndpointWithSecret(endpoint webhooks.CoreEndpoint) AppEndpointWithSecret {
+	return AppEndpointWithSecret{AppEndpoint: toAppEndpoint(endpoint), Secret: endpoint.Secret}
+}
+
+// toAppDelivery omits the payload and response body unless detailed is set, keeping the log listing small.
+func toAppDelivery(delivery webhooks.CoreDelivery, detailed bool) AppDelivery {
+	result := AppDelivery{
+		ID:            delivery.ID,
+		EndpointID:    delivery.EndpointID,
+		EventType:     delivery.EventType,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		ResponseCode:  delivery.ResponseCode,
+		Error:         delivery.Error,
+		RedeliveryOf:  delivery.RedeliveryOf,
+		LastAttemptAt: delivery.LastAttemptAt,
+		DeliveredAt:   delivery.DeliveredAt,
+		CreatedAt:     delivery.CreatedAt,
+	}
+	if detailed {
+		result.Payload = delivery.Payload
+		result.ResponseBody = delivery.ResponseBody
+	}
+	return result
+}
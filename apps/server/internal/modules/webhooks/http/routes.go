@@ -0,0 +1,36 @@
+package webhookshttp
+
+import (
+	webhooks "github.com/complexus-tech/projects-api/internal/modules/webhooks/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/cache"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	DB        *sqlx.DB
+	Log       *logger.Logger
+	SecretKey string
+	Cache     *cache.Service
+	Service   *webhooks.Service
+}
+
+func Routes(cfg Config, app *web.App) {
+	h := New(cfg.Service, cfg.Log)
+	auth := mid.Auth(cfg.Log, cfg.SecretKey)
+	workspace := mid.Workspace(cfg.Log, cfg.DB, cfg.Cache)
+	adminOnly := mid.RequireMinimumRole(cfg.Log, mid.RoleAdmin)
+
+	app.Get("/workspaces/{workspaceSlug}/webhooks/event-types", h.ListEventTypes, auth, workspace, adminOnly)
+	app.Get("/workspaces/{workspaceSlug}/webhooks", h.List, auth, workspace, adminOnly)
+	app.Post("/workspaces/{workspaceSlug}/webhooks", h.Create, auth, workspace, adminOnly)
+	app.Get("/workspaces/{workspaceSlug}/webhooks/{webhookId}", h.Get, auth, workspace, adminOnly)
+	app.Put("/workspaces/{workspaceSlug}/webhooks/{webhookId}", h.Update, auth, workspace, adminOnly)
+	app.Delete("/workspaces/{workspaceSlug}/webhooks/{webhookId}", h.Delete, auth, workspace, adminOnly)
+	app.Post("/workspaces/{workspaceSlug}/webhooks/{webhookId}/rotate-secret", h.RotateSecret, auth, workspace, adminOnly)
+	app.Get("/workspaces/{workspaceSlug}/webhooks/{webhookId}/deliveries", h.ListDeliveries, auth, workspace, adminOnly)
+	app.Get("/workspaces/{workspaceSlug}/webhooks/{webhookId}/deliveries/{deliveryId}", h.GetDelivery, auth, workspace, adminOnly)
+	app.Post("/workspaces/{workspaceSlug}/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver", h.Redeliver, auth, workspace, adminOnly)
+}
@@ -0,0 +1,217 @@
+package webhookshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	webhooks "github.com/complexus-tech/projects-api/internal/modules/webhooks/service"
+	mid "github.com/complexus-tech/projects-api/internal/platform/http/middleware"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+type Handlers struct {
+	webhooks *webhooks.Service
+	log      *logger.Logger
+}
+
+func New(service *webhooks.Service, log *logger.Logger) *Handlers {
+	return &Handlers{webhooks: service, log: log}
+}
+
+func (h *Handlers) ListEventTypes(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	eventTypes := make([]string, 0, len(webhooks.SupportedEventTypes))
+	for _, eventType := range webhooks.SupportedEventTypes {
+		eventTypes = append(eventTypes, string(eventType))
+	}
+	return web.Respond(ctx, w, eventTypes, http.StatusOK)
+}
+
+func (h *Handlers) List(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	endpoints, err := h.webhooks.ListEndpoints(ctx, workspace.ID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusInternalServerError)
+	}
+	response := make([]AppEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		response = append(response, toAppEndpoint(endpoint))
+	}
+	return web.Respond(ctx, w, response, http.StatusOK)
+}
+
+func (h *Handlers) Get(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	endpointID, err := uuid.Parse(web.Params(r, "webhookId"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	endpoint, err := h.webhooks.GetEndpoint(ctx, workspace.ID, endpointID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, toAppEndpoint(endpoint), http.StatusOK)
+}
+
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	var input AppNewEndpoint
+	if err := web.Decode(r, &input); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	endpoint, err := h.webhooks.CreateEndpoint(ctx, webhooks.CoreNewEndpoint{
+		WorkspaceID: workspace.ID,
+		URL:         input.URL,
+		Description: input.Description,
+		EventTypes:  input.EventTypes,
+		CreatedBy:   userID,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, toAppEndpointWithSecret(endpoint), http.StatusCreated)
+}
+
+func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	endpointID, err := uuid.Parse(web.Params(r, "webhookId"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	var input AppUpdateEndpoint
+	if err := web.Decode(r, &input); err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	endpoint, err := h.webhooks.UpdateEndpoint(ctx, workspace.ID, endpointID, webhooks.CoreUpdateEndpoint{
+		URL:         input.URL,
+		Description: input.Description,
+		EventTypes:  input.EventTypes,
+		IsActive:    input.IsActive,
+	})
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, toAppEndpoint(endpoint), http.StatusOK)
+}
+
+func (h *Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	endpointID, err := uuid.Parse(web.Params(r, "webhookId"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	if err := h.webhooks.DeleteEndpoint(ctx, workspace.ID, endpointID); err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+func (h *Handlers) RotateSecret(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	endpointID, err := uuid.Parse(web.Params(r, "webhookId"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	endpoint, err := h.webhooks.RotateSecret(ctx, workspace.ID, endpointID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, toAppEndpointWithSecret(endpoint), http.StatusOK)
+}
+
+func (h *Handlers) ListDeliveries(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	endpointID, err := uuid.Parse(web.Params(r, "webhookId"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	deliveries, err := h.webhooks.ListDeliveries(ctx, workspace.ID, endpointID, limit)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	response := make([]AppDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, toAppDelivery(delivery, false))
+	}
+	return web.Respond(ctx, w, response, http.StatusOK)
+}
+
+func (h *Handlers) GetDelivery(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	endpointID, err := uuid.Parse(web.Params(r, "webhookId"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	deliveryID, err := uuid.Parse(web.Params(r, "deliveryId"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	delivery, err := h.webhooks.GetDelivery(ctx, workspace.ID, endpointID, deliveryID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, toAppDelivery(delivery, true), http.StatusOK)
+}
+
+func (h *Handlers) Redeliver(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	workspace, err := mid.GetWorkspace(ctx)
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusUnauthorized)
+	}
+	endpointID, err := uuid.Parse(web.Params(r, "webhookId"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	deliveryID, err := uuid.Parse(web.Params(r, "deliveryId"))
+	if err != nil {
+		return web.RespondError(ctx, w, err, http.StatusBadRequest)
+	}
+	delivery, err := h.webhooks.Redeliver(ctx, workspace.ID, endpointID, deliveryID)
+	if err != nil {
+		return web.RespondError(ctx, w, err, httpStatus(err))
+	}
+	return web.Respond(ctx, w, toAppDelivery(delivery, false), http.StatusAccepted)
+}
+
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, webhooks.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, webhooks.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,286 @@
+package webhooksrepository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	webhooks "github.com/complexus-tech/projects-api/internal/modules/webhooks/service"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repo struct {
+	log *logger.Logger
+	db  *sqlx.DB
+}
+
+func New(log *logger.Logger, db *sqlx.DB) *Repo {
+	return &Repo{log: log, db: db}
+}
+
+const (
+	endpointColumns = `endpoint_id, workspace_id, url, description, secret, event_types, is_active, consecutive_failures, disabled_at, created_by, created_at, updated_at`
+	deliveryColumns = `delivery_id, endpoint_id, workspace_id, event_type, payload, status, attempts, response_code, response_body, error, redelivery_of, last_attempt_at, delivered_at, created_at, updated_at`
+)
+
+type endpointRow struct {
+	ID                  uuid.UUID      `db:"endpoint_id"`
+	WorkspaceID         uuid.UUID      `db:"workspace_id"`
+	URL                 string         `db:"url"`
+	Description         string         `db:"description"`
+	Secret              string         `db:"secret"`
+	EventTypes          pq.StringArray `db:"event_types"`
+	IsActive            bool           `db:"is_active"`
+	ConsecutiveFailures int            `db:"consecutive_failures"`
+	DisabledAt          *time.Time     `db:"disabled_at"`
+	CreatedBy           *uuid.UUID     `db:"created_by"`
+	CreatedAt           time.Time      `db:"created_at"`
+	UpdatedAt           time.Time      `db:"updated_at"`
+}
+
+type deliveryRow struct {
+	ID            uuid.UUID  `db:"delivery_id"`
+	EndpointID    uuid.UUID  `db:"endpoint_id"`
+	WorkspaceID   uuid.UUID  `db:"workspace_id"`
+	EventType     string     `db:"event_type"`
+	Payload       []byte     `db:"payload"`
+	Status        string     `db:"status"`
+	Attempts      int        `db:"attempts"`
+	ResponseCode  *int       `db:"response_code"`
+	ResponseBody  *string    `db:"response_body"`
+	Error         *string    `db:"error"`
+	RedeliveryOf  *uuid.UUID `db:"redelivery_of"`
+	LastAttemptAt *time.Time `db:"last_attempt_at"`
+	DeliveredAt   *time.Time `db:"delivered_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
+
+func (r *Repo) ListEndpoints(ctx context.Context, workspaceID uuid.UUID) ([]webhooks.CoreEndpoint, error) {
+	var rows []endpointRow
+	if err := r.db.SelectContext(ctx, &rows, `
+		SELECT `+endpointColumns+`
+		FROM webhook_endpoints
+		WHERE workspace_id = $1
+		ORDER BY created_at ASC
+	`, workspaceID); err != nil {
+		return nil, err
+	}
+	return toCoreEndpoints(rows), nil
+}
+
+func (r *Repo) GetEndpoint(ctx context.Context, workspaceID, endpointID uuid.UUID) (webhooks.CoreEndpoint, error) {
+	var row endpointRow
+	if err := r.db.GetContext(ctx, &row, `
+		SELECT `+endpointColumns+`
+		FROM webhook_endpoints
+		WHERE workspace_id = $1 AND endpoint_id = $2
+	`, workspaceID, endpointID); err != nil {
+		return webhooks.CoreEndpoint{}, err
+	}
+	return toCoreEndpoint(row), nil
+}
+
+func (r *Repo) ListActiveEndpointsForEvent(ctx context.Context, workspaceID uuid.UUID, eventType string) ([]webhooks.CoreEndpoint, error) {
+	var rows []endpointRow
+	if err := r.db.SelectContext(ctx, &rows, `
+		SELECT `+endpointColumns+`
+		FROM webhook_endpoints
+		WHERE workspace_id = $1 AND is_active = true AND event_types @> ARRAY[$2]::text[]
+	`, workspaceID, eventType); err != nil {
+		return nil, err
+	}
+	return toCoreEndpoints(rows), nil
+}
+
+func (r *Repo) CreateEndpoint(ctx context.Context, input webhooks.CoreNewEndpoint, secret string) (webhooks.CoreEndpoint, error) {
+	var row endpointRow
+	if err := r.db.GetContext(ctx, &row, `
+		INSERT INTO webhook_endpoints (workspace_id, url, description, secret, event_types, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+endpointColumns,
+		input.WorkspaceID, input.URL, input.Description, secret, pq.StringArray(input.EventTypes), input.CreatedBy); err != nil {
+		return webhooks.CoreEndpoint{}, err
+	}
+	return toCoreEndpoint(row), nil
+}
+
+func (r *Repo) UpdateEndpoint(ctx context.Context, workspaceID, endpointID uuid.UUID, input webhooks.CoreUpdateEndpoint) (webhooks.CoreEndpoint, error) {
+	var eventTypes any
+	if input.EventTypes != nil {
+		eventTypes = pq.StringArray(input.EventTypes)
+	}
+	var row endpointRow
+	if err := r.db.GetContext(ctx, &row, `
+		UPDATE webhook_endpoints
+		SET url = COALESCE($3, url),
+			description = COALESCE($4, description),
+			event_types = COALESCE($5, event_types),
+			is_active = COALESCE($6, is_active),
+			consecutive_failures = CASE WHEN $6 = true THEN 0 ELSE consecutive_failures END,
+			disabled_at = CASE WHEN $6 IS NULL THEN disabled_at WHEN $6 THEN NULL ELSE COALESCE(disabled_at, NOW()) END,
+			updated_at = NOW()
+		WHERE workspace_id = $1 AND endpoint_id = $2
+		RETURNING `+endpointColumns,
+		workspaceID, endpointID, input.URL, input.Description, eventTypes, input.IsActive); err != nil {
+		return webhooks.CoreEndpoint{}, err
+	}
+	return toCoreEndpoint(row), nil
+}
+
+func (r *Repo) UpdateEndpointSecret(ctx context.Context, workspaceID, endpointID uuid.UUID, secret string) (webhooks.CoreEndpoint, error) {
+	var row endpointRow
+	if err := r.db.GetContext(ctx, &row, `
+		UPDATE webhook_endpoints
+		SET secret = $3, updated_at = NOW()
+		WHERE workspace_id = $1 AND endpoint_id = $2
+		RETURNING `+endpointColumns,
+		workspaceID, endpointID, secret); err != nil {
+		return webhooks.CoreEndpoint{}, err
+	}
+	return toCoreEndpoint(row), nil
+}
+
+func (r *Repo) DeleteEndpoint(ctx context.Context, workspaceID, endpointID uuid.UUID) error {
+	var id uuid.UUID
+	return r.db.GetContext(ctx, &id, `
+		DELETE FROM webhook_endpoints
+		WHERE workspace_id = $1 AND endpoint_id = $2
+		RETURNING endpoint_id
+	`, workspaceID, endpointID)
+}
+
+func (r *Repo) RecordEndpointSuccess(ctx context.Context, endpointID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_endpoints
+		SET consecutive_failures = 0, updated_at = NOW()
+		WHERE endpoint_id = $1
+	`, endpointID)
+	return err
+}
+
+// RecordEndpointFailure bumps the failure count and disables the endpoint once it reaches disableAfter.
+func (r *Repo) RecordEndpointFailure(ctx context.Context, endpointID uuid.UUID, disableAfter int) (webhooks.CoreEndpoint, error) {
+	var row endpointRow
+	if err := r.db.GetContext(ctx, &row, `
+		UPDATE webhook_endpoints
+		SET consecutive_failures = consecutive_failures + 1,
+			is_active = is_active AND consecutive_failures + 1 < $2,
+			disabled_at = CASE
+				WHEN is_active AND consecutive_failures + 1 >= $2 THEN NOW()
+				ELSE disabled_at
+			END,
+			updated_at = NOW()
+		WHERE endpoint_id = $1
+		RETURNING `+endpointColumns,
+		endpointID, disableAfter); err != nil {
+		return webhooks.CoreEndpoint{}, err
+	}
+	return toCoreEndpoint(row), nil
+}
+
+func (r *Repo) ListDeliveries(ctx context.Context, workspaceID, endpointID uuid.UUID, limit int) ([]webhooks.CoreDelivery, error) {
+	var rows []deliveryRow
+	if err := r.db.SelectContext(ctx, &rows, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE workspace_id = $1 AND endpoint_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, workspaceID, endpointID, limit); err != nil {
+		return nil, err
+	}
+	result := make([]webhooks.CoreDelivery, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toCoreDelivery(row))
+	}
+	return result, nil
+}
+
+func (r *Repo) GetDelivery(ctx context.Context, workspaceID, deliveryID uuid.UUID) (webhooks.CoreDelivery, error) {
+	var row deliveryRow
+	if err := r.db.GetContext(ctx, &row, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE workspace_id = $1 AND delivery_id = $2
+	`, workspaceID, deliveryID); err != nil {
+		return webhooks.CoreDelivery{}, err
+	}
+	return toCoreDelivery(row), nil
+}
+
+func (r *Repo) CreateDelivery(ctx context.Context, input webhooks.CoreNewDelivery) (webhooks.CoreDelivery, error) {
+	var row deliveryRow
+	if err := r.db.GetContext(ctx, &row, `
+		INSERT INTO webhook_deliveries (endpoint_id, workspace_id, event_type, payload, redelivery_of)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+deliveryColumns,
+		input.EndpointID, input.WorkspaceID, input.EventType, []byte(input.Payload), input.RedeliveryOf); err != nil {
+		return webhooks.CoreDelivery{}, err
+	}
+	return toCoreDelivery(row), nil
+}
+
+func (r *Repo) RecordDeliveryAttempt(ctx context.Context, deliveryID uuid.UUID, attempt webhooks.CoreDeliveryAttempt) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2,
+			attempts = attempts + 1,
+			response_code = $3,
+			response_body = $4,
+			error = $5,
+			last_attempt_at = NOW(),
+			delivered_at = CASE WHEN $2 = 'succeeded' THEN NOW() ELSE delivered_at END,
+			updated_at = NOW()
+		WHERE delivery_id = $1
+	`, deliveryID, attempt.Status, attempt.ResponseCode, attempt.ResponseBody, attempt.Error)
+	return err
+}
+
+func toCoreEndpoints(rows []endpointRow) []webhooks.CoreEndpoint {
+	result := make([]webhooks.CoreEndpoint, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toCoreEndpoint(row))
+	}
+	return result
+}
+
+func toCoreEndpoint(row endpointRow) webhooks.CoreEndpoint {
+	return webhooks.CoreEndpoint{
+		ID:                  row.ID,
+		WorkspaceID:         row.WorkspaceID,
+		URL:                 row.URL,
+		Description:         row.Description,
+		Secret:              row.Secret,
+		EventTypes:          []string(row.EventTypes),
+		IsActive:            row.IsActive,
+		ConsecutiveFailures: row.ConsecutiveFailures,
+		DisabledAt:          row.DisabledAt,
+		CreatedBy:           row.CreatedBy,
+		CreatedAt:           row.CreatedAt,
+		UpdatedAt:           row.UpdatedAt,
+	}
+}
+
+func toCoreDelivery(row deliveryRow) webhooks.CoreDelivery {
+	return webhooks.CoreDelivery{
+		ID:            row.ID,
+		EndpointID:    row.EndpointID,
+		WorkspaceID:   row.WorkspaceID,
+		EventType:     row.EventType,
+		Payload:       json.RawMessage(row.Payload),
+		Status:        row.Status,
+		Attempts:      row.Attempts,
+		ResponseCode:  row.ResponseCode,
+		ResponseBody:  row.ResponseBody,
+		Error:         row.Error,
+		RedeliveryOf:  row.RedeliveryOf,
+		LastAttemptAt: row.LastAttemptAt,
+		DeliveredAt:   row.DeliveredAt,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}
+}
@@ -0,0 +1,125 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/tasks"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// SupportedEventTypes lists the events that can be delivered to webhook endpoints.
+// Account events such as email verification carry secrets and are never exposed.
+var SupportedEventTypes = []events.EventType{
+	events.StoryCreated,
+	events.StoryUpdated,
+	events.StoryDuplicated,
+	events.CommentCreated,
+	events.CommentReplied,
+	events.UserMentioned,
+	events.ObjectiveUpdated,
+	events.KeyResultUpdated,
+}
+
+type CoreEndpoint struct {
+	ID                  uuid.UUID
+	WorkspaceID         uuid.UUID
+	URL                 string
+	Description         string
+	Secret              string
+	EventTypes          []string
+	IsActive            bool
+	ConsecutiveFailures int
+	DisabledAt          *time.Time
+	CreatedBy           *uuid.UUID
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+type CoreNewEndpoint struct {
+	WorkspaceID uuid.UUID
+	URL         string
+	Description string
+	EventTypes  []string
+	CreatedBy   uuid.UUID
+}
+
+type CoreUpdateEndpoint struct {
+	URL         *string
+	Description *string
+	EventTypes  []string
+	IsActive    *bool
+}
+
+type CoreDelivery struct {
+	ID            uuid.UUID
+	EndpointID    uuid.UUID
+	WorkspaceID   uuid.UUID
+	EventType     string
+	Payload       json.RawMessage
+	Status        string
+	Attempts      int
+	ResponseCode  *int
+	ResponseBody  *string
+	Error         *string
+	RedeliveryOf  *uuid.UUID
+	LastAttemptAt *time.Time
+	DeliveredAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type CoreNewDelivery struct {
+	EndpointID   uuid.UUID
+	WorkspaceID  uuid.UUID
+	EventType    string
+	Payload      json.RawMessage
+	RedeliveryOf *uuid.UUID
+}
+
+// CoreDeliveryAttempt is the outcome of a single HTTP request to an endpoint.
+type CoreDeliveryAttempt struct {
+	Status       string
+	ResponseCode *int
+	ResponseBody *string
+	Error        *string
+}
+
+// CoreEventPayload is the JSON body sent to webhook endpoints.
+type CoreEventPayload struct {
+	Type        string          `json:"type"`
+	WorkspaceID uuid.UUID       `json:"workspaceId"`
+	ActorID     uuid.UUID       `json:"actorId"`
+	Timestamp   time.Time       `json:"timestamp"`
+	Data        json.RawMessage `json:"data"`
+}
+
+type Repository interface {
+	ListEndpoints(ctx context.Context, workspaceID uuid.UUID) ([]CoreEndpoint, error)
+	GetEndpoint(ctx context.Context, workspaceID, endpointID uuid.UUID) (CoreEndpoint, error)
+	ListActiveEndpointsForEvent(ctx context.Context, workspaceID uuid.UUID, eventType string) ([]CoreEndpoint, error)
+	CreateEndpoint(ctx context.Context, input CoreNewEndpoint, secret string) (CoreEndpoint, error)
+	UpdateEndpoint(ctx context.Context, workspaceID, endpointID uuid.UUID, input CoreUpdateEndpoint) (CoreEndpoint, error)
+	UpdateEndpointSecret(ctx context.Context, workspaceID, endpointID uuid.UUID, secret string) (CoreEndpoint, error)
+	DeleteEndpoint(ctx context.Context, workspaceID, endpointID uuid.UUID) error
+	RecordEndpointSuccess(ctx context.Context, endpointID uuid.UUID) error
+	RecordEndpointFailure(ctx context.Context, endpointID uuid.UUID, disableAfter int) (CoreEndpoint, error)
+	ListDeliveries(ctx context.Context, workspaceID, endpointID uuid.UUID, limit int) ([]CoreDelivery, error)
+	GetDelivery(ctx context.Context, workspaceID, deliveryID uuid.UUID) (CoreDelivery, error)
+	CreateDelivery(ctx context.Context, input CoreNewDelivery) (CoreDelivery, error)
+	RecordDeliveryAttempt(ctx context.Context, deliveryID uuid.UUID, attempt CoreDeliveryAttempt) error
+}
+
+// TasksService provides access to the task queue for background delivery.
+type TasksService interface {
+	EnqueueWebhookDelivery(payload tasks.WebhookDeliveryPayload, opts ...asynq.Option) (*asynq.TaskInfo, error)
+}
@@ -0,0 +1,428 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/tasks"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidInput = errors.New("invalid webhook input")
+	ErrNotFound     = sql.ErrNoRows
+
+	errDisallowedAddress = errors.New("webhook endpoint resolves to a non-public address")
+)
+
+const (
+	SignatureHeader = "X-FortyOne-Signature"
+	TimestampHeader = "X-FortyOne-Timestamp"
+	EventHeader     = "X-FortyOne-Event"
+	DeliveryHeader  = "X-FortyOne-Delivery"
+
+	// disableAfterFailures is the number of consecutive deliveries that may exhaust
+	// their retries before an endpoint is switched off.
+	disableAfterFailures = 10
+	maxResponseBodyBytes = 2048
+	maxDeliveriesListed  = 100
+	requestTimeout       = 10 * time.Second
+)
+
+type Service struct {
+	repo   Repository
+	log    *logger.Logger
+	tasks  TasksService
+	client *http.Client
+	now    func() time.Time
+}
+
+func New(log *logger.Logger, repo Repository, tasksService TasksService) *Service {
+	return &Service{
+		repo:   repo,
+		log:    log,
+		tasks:  tasksService,
+		client: newDeliveryClient(),
+		now:    time.Now,
+	}
+}
+
+func (s *Service) ListEndpoints(ctx context.Context, workspaceID uuid.UUID) ([]CoreEndpoint, error) {
+	return s.repo.ListEndpoints(ctx, workspaceID)
+}
+
+func (s *Service) GetEndpoint(ctx context.Context, workspaceID, endpointID uuid.UUID) (CoreEndpoint, error) {
+	return s.repo.GetEndpoint(ctx, workspaceID, endpointID)
+}
+
+func (s *Service) CreateEndpoint(ctx context.Context, input CoreNewEndpoint) (CoreEndpoint, error) {
+	ctx, span := web.AddSpan(ctx, "business.core.webhooks.CreateEndpoint")
+	defer span.End()
+
+	endpointURL, err := validateURL(input.URL)
+	if err != nil {
+		return CoreEndpoint{}, err
+	}
+	eventTypes, err := normalizeEventTypes(input.EventTypes)
+	if err != nil {
+		return CoreEndpoint{}, err
+	}
+	input.URL = endpointURL
+	input.EventTypes = eventTypes
+	input.Description = strings.TrimSpace(input.Description)
+
+	secret, err := generateSecret()
+	if err != nil {
+		return CoreEndpoint{}, err
+	}
+	return s.repo.CreateEndpoint(ctx, input, secret)
+}
+
+// UpdateEndpoint changes an endpoint's settings. Re-enabling an endpoint clears its failure count.
+func (s *Service) UpdateEndpoint(ctx context.Context, workspaceID, endpointID uuid.UUID, input CoreUpdateEndpoint) (CoreEndpoint, error) {
+	ctx, span := web.AddSpan(ctx, "business.core.webhooks.UpdateEndpoint")
+	defer span.End()
+
+	if input.URL != nil {
+		endpointURL, err := validateURL(*input.URL)
+		if err != nil {
+			return CoreEndpoint{}, err
+		}
+		input.URL = &endpointURL
+	}
+	if input.EventTypes != nil {
+		eventTypes, err := normalizeEventTypes(input.EventTypes)
+		if err != nil {
+			return CoreEndpoint{}, err
+		}
+		input.EventTypes = eventTypes
+	}
+	if input.Description != nil {
+		description := strings.TrimSpace(*input.Description)
+		input.Description = &description
+	}
+	return s.repo.UpdateEndpoint(ctx, workspaceID, endpointID, input)
+}
+
+func (s *Service) RotateSecret(ctx context.Context, workspaceID, endpointID uuid.UUID) (CoreEndpoint, error) {
+	secret, err := generateSecret()
+	if err != nil {
+		return CoreEndpoint{}, err
+	}
+	return s.repo.UpdateEndpointSecret(ctx, workspaceID, endpointID, secret)
+}
+
+func (s *Service) DeleteEndpoint(ctx context.Context, workspaceID, endpointID uuid.UUID) error {
+	return s.repo.DeleteEndpoint(ctx, workspaceID, endpointID)
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, workspaceID, endpointID uuid.UUID, limit int) ([]CoreDelivery, error) {
+	if limit <= 0 || limit > maxDeliveriesListed {
+		limit = maxDeliveriesListed
+	}
+	return s.repo.ListDeliveries(ctx, workspaceID, endpointID, limit)
+}
+
+func (s *Service) GetDelivery(ctx context.Context, workspaceID, endpointID, deliveryID uuid.UUID) (CoreDelivery, error) {
+	delivery, err := s.repo.GetDelivery(ctx, workspaceID, deliveryID)
+	if err != nil {
+		return CoreDelivery{}, err
+	}
+	if delivery.EndpointID != endpointID {
+		return CoreDelivery{}, ErrNotFound
+	}
+	return delivery, nil
+}
+
+// Redeliver queues a fresh delivery carrying the original payload of an earlier one.
+func (s *Service) Redeliver(ctx context.Context, workspaceID, endpointID, deliveryID uuid.UUID) (CoreDelivery, error) {
+	ctx, span := web.AddSpan(ctx, "business.core.webhooks.Redeliver")
+	defer span.End()
+
+	original, err := s.GetDelivery(ctx, workspaceID, endpointID, deliveryID)
+	if err != nil {
+		return CoreDelivery{}, err
+	}
+	endpoint, err := s.repo.GetEndpoint(ctx, workspaceID, endpointID)
+	if err != nil {
+		return CoreDelivery{}, err
+	}
+	if !endpoint.IsActive {
+		return CoreDelivery{}, fmt.Errorf("%w: endpoint is disabled", ErrInvalidInput)
+	}
+
+	delivery, err := s.repo.CreateDelivery(ctx, CoreNewDelivery{
+		EndpointID:   original.EndpointID,
+		WorkspaceID:  original.WorkspaceID,
+		EventType:    original.EventType,
+		Payload:      original.Payload,
+		RedeliveryOf: &original.ID,
+	})
+	if err != nil {
+		return CoreDelivery{}, err
+	}
+	if err := s.enqueue(delivery); err != nil {
+		return CoreDelivery{}, err
+	}
+	return delivery, nil
+}
+
+// Dispatch records a delivery for every active endpoint subscribed to the event
+// and queues it for sending. Events that are not exposed to webhooks are ignored.
+func (s *Service) Dispatch(ctx context.Context, event events.Event) error {
+	ctx, span := web.AddSpan(ctx, "business.core.webhooks.Dispatch")
+	defer span.End()
+
+	if !slices.Contains(SupportedEventTypes, event.Type) {
+		return nil
+	}
+
+	data, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("marshal event payload: %w", err)
+	}
+	var scope struct {
+		WorkspaceID uuid.UUID `json:"workspace_id"`
+	}
+	if err := json.Unmarshal(data, &scope); err != nil || scope.WorkspaceID == uuid.Nil {
+		return fmt.Errorf("event %s has no workspace id", event.Type)
+	}
+
+	endpoints, err := s.repo.ListActiveEndpointsForEvent(ctx, scope.WorkspaceID, string(event.Type))
+	if err != nil {
+		return err
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(CoreEventPayload{
+		Type:        string(event.Type),
+		WorkspaceID: scope.WorkspaceID,
+		ActorID:     event.ActorID,
+		Timestamp:   event.Timestamp,
+		Data:        data,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal webhook body: %w", err)
+	}
+
+	var errs []error
+	for _, endpoint := range endpoints {
+		delivery, err := s.repo.CreateDelivery(ctx, CoreNewDelivery{
+			EndpointID:  endpoint.ID,
+			WorkspaceID: endpoint.WorkspaceID,
+			EventType:   string(event.Type),
+			Payload:     body,
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := s.enqueue(delivery); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Deliver sends a queued delivery and records the outcome. It returns an error when
+// the attempt failed so the task queue retries it; finalAttempt marks the last retry,
+// after which the delivery is failed and counted against the endpoint.
+func (s *Service) Deliver(ctx context.Context, workspaceID, deliveryID uuid.UUID, finalAttempt bool) error {
+	ctx, span := web.AddSpan(ctx, "business.core.webhooks.Deliver")
+	defer span.End()
+
+	delivery, err := s.repo.GetDelivery(ctx, workspaceID, deliveryID)
+	if err != nil {
+		return err
+	}
+	if delivery.Status != DeliveryPending {
+		return nil
+	}
+	endpoint, err := s.repo.GetEndpoint(ctx, workspaceID, delivery.EndpointID)
+	if err != nil {
+		return err
+	}
+	if !endpoint.IsActive {
+		message := "endpoint is disabled"
+		return s.repo.RecordDeliveryAttempt(ctx, delivery.ID, CoreDeliveryAttempt{Status: DeliveryFailed, Error: &message})
+	}
+
+	attempt := s.send(ctx, endpoint, delivery)
+	if attempt.Status == DeliverySucceeded {
+		if err := s.repo.RecordDeliveryAttempt(ctx, delivery.ID, attempt); err != nil {
+			return err
+		}
+		if endpoint.ConsecutiveFailures > 0 {
+			return s.repo.RecordEndpointSuccess(ctx, endpoint.ID)
+		}
+		return nil
+	}
+
+	if finalAttempt {
+		attempt.Status = DeliveryFailed
+	}
+	if err := s.repo.RecordDeliveryAttempt(ctx, delivery.ID, attempt); err != nil {
+		return err
+	}
+	if finalAttempt {
+		updated, err := s.repo.RecordEndpointFailure(ctx, endpoint.ID, disableAfterFailures)
+		if err != nil {
+			return err
+		}
+		if !updated.IsActive && s.log != nil {
+			s.log.Warn(ctx, "webhook endpoint disabled after repeated failures", "endpoint_id", endpoint.ID, "workspace_id", endpoint.WorkspaceID)
+		}
+	}
+	return fmt.Errorf("webhook delivery %s failed: %s", delivery.ID, *attempt.Error)
+}
+
+func (s *Service) send(ctx context.Context, endpoint CoreEndpoint, delivery CoreDelivery) CoreDeliveryAttempt {
+	failed := func(message string) CoreDeliveryAttempt {
+		return CoreDeliveryAttempt{Status: DeliveryPending, Error: &message}
+	}
+
+	timestamp := s.now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return failed(err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "FortyOne-Webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return failed(err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodyBytes))
+	responseBody := string(body)
+	attempt := CoreDeliveryAttempt{ResponseCode: &resp.StatusCode, ResponseBody: &responseBody}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		attempt.Status = DeliverySucceeded
+		return attempt
+	}
+	message := fmt.Sprintf("endpoint responded with status %d", resp.StatusCode)
+	attempt.Status = DeliveryPending
+	attempt.Error = &message
+	return attempt
+}
+
+func (s *Service) enqueue(delivery CoreDelivery) error {
+	if s.tasks == nil {
+		return errors.New("webhook delivery queue is not configured")
+	}
+	_, err := s.tasks.EnqueueWebhookDelivery(tasks.WebhookDeliveryPayload{
+		DeliveryID:  delivery.ID,
+		WorkspaceID: delivery.WorkspaceID,
+	})
+	return err
+}
+
+// Sign returns the signature header value for a payload: an HMAC-SHA256 over
+// "<timestamp>.<body>" keyed with the endpoint secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func validateURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return "", fmt.Errorf("%w: url must be an absolute https url", ErrInvalidInput)
+	}
+	host := parsed.Hostname()
+	if strings.EqualFold(host, "localhost") {
+		return "", fmt.Errorf("%w: url must not point to a local address", ErrInvalidInput)
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return "", fmt.Errorf("%w: url must not point to a private address", ErrInvalidInput)
+	}
+	return raw, nil
+}
+
+// newDeliveryClient returns a client that refuses to connect to non-public
+// addresses. The check runs on the resolved address of every connection, so
+// hostnames resolving or rebinding to internal addresses are caught as well
+// as redirects to them.
+func newDeliveryClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: requestTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return fmt.Errorf("%w: %s", errDisallowedAddress, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialled instead of the endpoint, bypassing the check.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: requestTimeout, Transport: transport}
+}
+
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified()
+}
+
+func normalizeEventTypes(eventTypes []string) ([]string, error) {
+	if len(eventTypes) == 0 {
+		return nil, fmt.Errorf("%w: at least one event type is required", ErrInvalidInput)
+	}
+	result := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if !slices.Contains(SupportedEventTypes, events.EventType(eventType)) {
+			return nil, fmt.Errorf("%w: unsupported event type %q", ErrInvalidInput, eventType)
+		}
+		if !slices.Contains(result, eventType) {
+			result = append(result, eventType)
+		}
+	}
+	slices.Sort(result)
+	return result, nil
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
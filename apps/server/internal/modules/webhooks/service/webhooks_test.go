@@ -0,0 +1,285 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/tasks"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+type repoStub struct {
+	endpoints  []CoreEndpoint
+	deliveries []CoreDelivery
+	attempts   []CoreDeliveryAttempt
+	failures   int
+}
+
+func (r *repoStub) ListEndpoints(ctx context.Context, workspaceID uuid.UUID) ([]CoreEndpoint, error) {
+	return r.endpoints, nil
+}
+
+func (r *repoStub) GetEndpoint(ctx context.Context, workspaceID, endpointID uuid.UUID) (CoreEndpoint, error) {
+	for _, endpoint := range r.endpoints {
+		if endpoint.ID == endpointID && endpoint.WorkspaceID == workspaceID {
+			return endpoint, nil
+		}
+	}
+	return CoreEndpoint{}, sql.ErrNoRows
+}
+
+func (r *repoStub) ListActiveEndpointsForEvent(ctx context.Context, workspaceID uuid.UUID, eventType string) ([]CoreEndpoint, error) {
+	var result []CoreEndpoint
+	for _, endpoint := range r.endpoints {
+		if endpoint.WorkspaceID == workspaceID && endpoint.IsActive && slices.Contains(endpoint.EventTypes, eventType) {
+			result = append(result, endpoint)
+		}
+	}
+	return result, nil
+}
+
+func (r *repoStub) CreateEndpoint(ctx context.Context, input CoreNewEndpoint, secret string) (CoreEndpoint, error) {
+	endpoint := CoreEndpoint{ID: uuid.New(), WorkspaceID: input.WorkspaceID, URL: input.URL, Secret: secret, EventTypes: input.EventTypes, IsActive: true}
+	r.endpoints = append(r.endpoints, endpoint)
+	return endpoint, nil
+}
+
+func (r *repoStub) UpdateEndpoint(ctx context.Context, workspaceID, endpointID uuid.UUID, input CoreUpdateEndpoint) (CoreEndpoint, error) {
+	return CoreEndpoint{}, sql.ErrNoRows
+}
+
+func (r *repoStub) UpdateEndpointSecret(ctx context.Context, workspaceID, endpointID uuid.UUID, secret string) (CoreEndpoint, error) {
+	return CoreEndpoint{}, sql.ErrNoRows
+}
+
+func (r *repoStub) DeleteEndpoint(ctx context.Context, workspaceID, endpointID uuid.UUID) error {
+	return nil
+}
+
+func (r *repoStub) RecordEndpointSuccess(ctx context.Context, endpointID uuid.UUID) error {
+	r.failures = 0
+	return nil
+}
+
+func (r *repoStub) RecordEndpointFailure(ctx context.Context, endpointID uuid.UUID, disableAfter int) (CoreEndpoint, error) {
+	r.failures++
+	for i := range r.endpoints {
+		if r.endpoints[i].ID == endpointID {
+			r.endpoints[i].ConsecutiveFailures = r.failures
+			r.endpoints[i].IsActive = r.failures < disableAfter
+			return r.endpoints[i], nil
+		}
+	}
+	return CoreEndpoint{}, sql.ErrNoRows
+}
+
+func (r *repoStub) ListDeliveries(ctx context.Context, workspaceID, endpointID uuid.UUID, limit int) ([]CoreDelivery, error) {
+	return r.deliveries, nil
+}
+
+func (r *repoStub) GetDelivery(ctx context.Context, workspaceID, deliveryID uuid.UUID) (CoreDelivery, error) {
+	for _, delivery := range r.deliveries {
+		if delivery.ID == deliveryID && delivery.WorkspaceID == workspaceID {
+			return delivery, nil
+		}
+	}
+	return CoreDelivery{}, sql.ErrNoRows
+}
+
+func (r *repoStub) CreateDelivery(ctx context.Context, input CoreNewDelivery) (CoreDelivery, error) {
+	delivery := CoreDelivery{
+		ID:           uuid.New(),
+		EndpointID:   input.EndpointID,
+		WorkspaceID:  input.WorkspaceID,
+		EventType:    input.EventType,
+		Payload:      input.Payload,
+		Status:       DeliveryPending,
+		RedeliveryOf: input.RedeliveryOf,
+	}
+	r.deliveries = append(r.deliveries, delivery)
+	return delivery, nil
+}
+
+func (r *repoStub) RecordDeliveryAttempt(ctx context.Context, deliveryID uuid.UUID, attempt CoreDeliveryAttempt) error {
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+type tasksStub struct {
+	enqueued []tasks.WebhookDeliveryPayload
+}
+
+func (t *tasksStub) EnqueueWebhookDelivery(payload tasks.WebhookDeliveryPayload, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	t.enqueued = append(t.enqueued, payload)
+	return &asynq.TaskInfo{}, nil
+}
+
+func TestCreateEndpointValidatesInput(t *testing.T) {
+	service := New(nil, &repoStub{}, &tasksStub{})
+	workspaceID := uuid.New()
+
+	invalid := []CoreNewEndpoint{
+		{WorkspaceID: workspaceID, URL: "http://example.com/hook", EventTypes: []string{"story.created"}},
+		{WorkspaceID: workspaceID, URL: "https://localhost/hook", EventTypes: []string{"story.created"}},
+		{WorkspaceID: workspaceID, URL: "https://10.0.0.4/hook", EventTypes: []string{"story.created"}},
+		{WorkspaceID: workspaceID, URL: "https://example.com/hook", EventTypes: nil},
+		{WorkspaceID: workspaceID, URL: "https://example.com/hook", EventTypes: []string{"email.verification"}},
+	}
+	for _, input := range invalid {
+		_, err := service.CreateEndpoint(context.Background(), input)
+		require.ErrorIs(t, err, ErrInvalidInput, input.URL)
+	}
+
+	endpoint, err := service.CreateEndpoint(context.Background(), CoreNewEndpoint{
+		WorkspaceID: workspaceID,
+		URL:         " https://example.com/hook ",
+		EventTypes:  []string{"story.updated", "story.created", "story.updated"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/hook", endpoint.URL)
+	require.Equal(t, []string{"story.created", "story.updated"}, endpoint.EventTypes)
+	require.NotEmpty(t, endpoint.Secret)
+}
+
+func TestDispatchQueuesDeliveriesForSubscribedEndpoints(t *testing.T) {
+	workspaceID := uuid.New()
+	subscribed := CoreEndpoint{ID: uuid.New(), WorkspaceID: workspaceID, EventTypes: []string{"story.created"}, IsActive: true}
+	other := CoreEndpoint{ID: uuid.New(), WorkspaceID: workspaceID, EventTypes: []string{"comment.created"}, IsActive: true}
+	disabled := CoreEndpoint{ID: uuid.New(), WorkspaceID: workspaceID, EventTypes: []string{"story.created"}, IsActive: false}
+	repo := &repoStub{endpoints: []CoreEndpoint{subscribed, other, disabled}}
+	queue := &tasksStub{}
+	service := New(nil, repo, queue)
+
+	err := service.Dispatch(context.Background(), events.Event{
+		Type:    events.StoryCreated,
+		Payload: events.StoryCreatedPayload{StoryID: uuid.New(), WorkspaceID: workspaceID, Title: "Fix login"},
+		ActorID: uuid.New(),
+	})
+	require.NoError(t, err)
+	require.Len(t, repo.deliveries, 1)
+	require.Equal(t, subscribed.ID, repo.deliveries[0].EndpointID)
+	require.Equal(t, []tasks.WebhookDeliveryPayload{{DeliveryID: repo.deliveries[0].ID, WorkspaceID: workspaceID}}, queue.enqueued)
+
+	err = service.Dispatch(context.Background(), events.Event{
+		Type:    events.EmailVerification,
+		Payload: events.EmailVerificationPayload{Email: "a@example.com", Token: "secret"},
+	})
+	require.NoError(t, err)
+	require.Len(t, repo.deliveries, 1)
+}
+
+func TestDeliverSignsPayloadAndRecordsSuccess(t *testing.T) {
+	workspaceID := uuid.New()
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	endpoint := CoreEndpoint{ID: uuid.New(), WorkspaceID: workspaceID, URL: server.URL, Secret: "whsec_test", IsActive: true, ConsecutiveFailures: 2}
+	delivery := CoreDelivery{ID: uuid.New(), EndpointID: endpoint.ID, WorkspaceID: workspaceID, EventType: "story.created", Payload: []byte(`{"type":"story.created"}`), Status: DeliveryPending}
+	repo := &repoStub{endpoints: []CoreEndpoint{endpoint}, deliveries: []CoreDelivery{delivery}, failures: 2}
+	service := New(nil, repo, &tasksStub{})
+	service.client = server.Client()
+	now := time.Unix(1700000000, 0)
+	service.now = func() time.Time { return now }
+
+	require.NoError(t, service.Deliver(context.Background(), workspaceID, delivery.ID, false))
+	require.Equal(t, string(delivery.Payload), string(body))
+	require.Equal(t, "story.created", received.Header.Get(EventHeader))
+	require.Equal(t, delivery.ID.String(), received.Header.Get(DeliveryHeader))
+	require.Equal(t, strconv.FormatInt(now.Unix(), 10), received.Header.Get(TimestampHeader))
+	require.Equal(t, Sign("whsec_test", now.Unix(), delivery.Payload), received.Header.Get(SignatureHeader))
+	require.Len(t, repo.attempts, 1)
+	require.Equal(t, DeliverySucceeded, repo.attempts[0].Status)
+	require.Equal(t, http.StatusNoContent, *repo.attempts[0].ResponseCode)
+	require.Zero(t, repo.failures)
+}
+
+func TestDeliverRefusesHostnamesResolvingToPrivateAddresses(t *testing.T) {
+	workspaceID := uuid.New()
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+
+	// The endpoint is stored by hostname, as validateURL only sees the name.
+	endpoint := CoreEndpoint{ID: uuid.New(), WorkspaceID: workspaceID, URL: "http://localhost:" + port + "/hook", Secret: "whsec_test", IsActive: true}
+	delivery := CoreDelivery{ID: uuid.New(), EndpointID: endpoint.ID, WorkspaceID: workspaceID, EventType: "story.created", Payload: []byte(`{}`), Status: DeliveryPending}
+	repo := &repoStub{endpoints: []CoreEndpoint{endpoint}, deliveries: []CoreDelivery{delivery}}
+	service := New(nil, repo, &tasksStub{})
+
+	require.Error(t, service.Deliver(context.Background(), workspaceID, delivery.ID, false))
+	require.False(t, called)
+	require.Len(t, repo.attempts, 1)
+	require.Contains(t, *repo.attempts[0].Error, errDisallowedAddress.Error())
+}
+
+func TestPublicIP(t *testing.T) {
+	for _, address := range []string{"127.0.0.1", "::1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "fe80::1", "fd00::1", "0.0.0.0", "::ffff:127.0.0.1"} {
+		require.False(t, publicIP(net.ParseIP(address)), address)
+	}
+	for _, address := range []string{"93.184.216.34", "2606:2800:220:1::"} {
+		require.True(t, publicIP(net.ParseIP(address)), address)
+	}
+}
+
+func TestDeliverFailuresRetryThenDisableEndpoint(t *testing.T) {
+	workspaceID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	endpoint := CoreEndpoint{ID: uuid.New(), WorkspaceID: workspaceID, URL: server.URL, Secret: "whsec_test", IsActive: true}
+	delivery := CoreDelivery{ID: uuid.New(), EndpointID: endpoint.ID, WorkspaceID: workspaceID, EventType: "story.updated", Payload: []byte(`{}`), Status: DeliveryPending}
+	repo := &repoStub{endpoints: []CoreEndpoint{endpoint}, deliveries: []CoreDelivery{delivery}, failures: disableAfterFailures - 1}
+	service := New(nil, repo, &tasksStub{})
+	service.client = server.Client()
+
+	require.Error(t, service.Deliver(context.Background(), workspaceID, delivery.ID, false))
+	require.Equal(t, DeliveryPending, repo.attempts[0].Status)
+	require.Equal(t, http.StatusBadGateway, *repo.attempts[0].ResponseCode)
+	require.True(t, repo.endpoints[0].IsActive)
+
+	require.Error(t, service.Deliver(context.Background(), workspaceID, delivery.ID, true))
+	require.Equal(t, DeliveryFailed, repo.attempts[1].Status)
+	require.False(t, repo.endpoints[0].IsActive)
+}
+
+func TestRedeliverCopiesOriginalPayload(t *testing.T) {
+	workspaceID := uuid.New()
+	endpoint := CoreEndpoint{ID: uuid.New(), WorkspaceID: workspaceID, IsActive: true}
+	original := CoreDelivery{ID: uuid.New(), EndpointID: endpoint.ID, WorkspaceID: workspaceID, EventType: "comment.created", Payload: []byte(`{"a":1}`), Status: DeliveryFailed}
+	repo := &repoStub{endpoints: []CoreEndpoint{endpoint}, deliveries: []CoreDelivery{original}}
+	queue := &tasksStub{}
+	service := New(nil, repo, queue)
+
+	delivery, err := service.Redeliver(context.Background(), workspaceID, endpoint.ID, original.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, original.ID, delivery.ID)
+	require.Equal(t, original.ID, *delivery.RedeliveryOf)
+	require.Equal(t, string(original.Payload), string(delivery.Payload))
+	require.Len(t, queue.enqueued, 1)
+
+	_, err = service.Redeliver(context.Background(), workspaceID, uuid.New(), original.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+}
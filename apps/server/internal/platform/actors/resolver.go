@@ -18,6 +18,7 @@ type Key string
 const (
 	KeySystem Key = "system"
 	KeyGitHub Key = "github"
+	KeyEmail  Key = "email"
 )
 
 const cacheTTL = time.Hour
@@ -25,6 +26,7 @@ const cacheTTL = time.Hour
 var actorEmails = map[Key]string{
 	KeySystem: "maya@fortyone.app",
 	KeyGitHub: "github@fortyone.app",
+	KeyEmail:  "email@fortyone.app",
 }
 
 // EmailForKey returns the configured system actor email for a key.
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const apiTokenKey contextKey = "apiToken"
+
+// APITokenPrefix marks bearer tokens that must be resolved as personal access tokens.
+const APITokenPrefix = "fo_pat_"
+
+// Access levels granted by a token scope.
+const (
+	AccessRead  = "read"
+	AccessWrite = "write"
+)
+
+// Resources that personal access tokens can be scoped to.
+const (
+	ResourceStories    = "stories"
+	ResourceSprints    = "sprints"
+	ResourceObjectives = "objectives"
+)
+
+// Resources lists every resource a token scope can reference.
+var Resources = []string{ResourceStories, ResourceSprints, ResourceObjectives}
+
+// routeResources maps the first path segment after the workspace slug to a scoped resource.
+var routeResources = map[string]string{
+	"stories":      ResourceStories,
+	"my-stories":   ResourceStories,
+	"story-by-ref": ResourceStories,
+	"sprints":      ResourceSprints,
+	"objectives":   ResourceObjectives,
+	"key-results":  ResourceObjectives,
+}
+
+// APIToken describes the personal access token that authenticated a request.
+type APIToken struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	WorkspaceID   uuid.UUID
+	WorkspaceSlug string
+	Scopes        []string
+}
+
+// Scope builds a scope string such as "stories:read".
+func Scope(resource, access string) string {
+	return resource + ":" + access
+}
+
+// IsValidScope reports whether scope references a known resource and access level.
+func IsValidScope(scope string) bool {
+	resource, access, ok := strings.Cut(scope, ":")
+	if !ok || !slices.Contains(Resources, resource) {
+		return false
+	}
+	return access == AccessRead || access == AccessWrite
+}
+
+// Allows reports whether the token grants access to resource. Write access implies read.
+func (t APIToken) Allows(resource, access string) bool {
+	if slices.Contains(t.Scopes, Scope(resource, AccessWrite)) {
+		return true
+	}
+	return access == AccessRead && slices.Contains(t.Scopes, Scope(resource, AccessRead))
+}
+
+// AuthorizeRequest checks that the request targets the token's workspace and a resource it is scoped to.
+func (t APIToken) AuthorizeRequest(r *http.Request) bool {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 3 || segments[0] != "workspaces" {
+		return false
+	}
+	if !strings.EqualFold(segments[1], t.WorkspaceSlug) {
+		return false
+	}
+
+	resource, ok := routeResources[segments[2]]
+	if !ok {
+		return false
+	}
+
+	access := AccessWrite
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		access = AccessRead
+	}
+	return t.Allows(resource, access)
+}
+
+func SetAPIToken(ctx context.Context, token APIToken) context.Context {
+	return context.WithValue(ctx, apiTokenKey, token)
+}
+
+// GetAPIToken returns the token that authenticated the request, if any.
+func GetAPIToken(ctx context.Context) (APIToken, bool) {
+	token, ok := ctx.Value(apiTokenKey).(APIToken)
+	return token, ok
+}
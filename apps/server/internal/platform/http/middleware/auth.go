@@ -23,10 +23,21 @@ const (
 
 var authCache *cache.Service
 
+// APITokenResolver resolves a raw personal access token into its owner and scopes.
+type APITokenResolver interface {
+	ResolveAPIToken(ctx context.Context, token string) (auth.APIToken, error)
+}
+
+var apiTokenResolver APITokenResolver
+
 func SetAuthCache(service *cache.Service) {
 	authCache = service
 }
 
+func SetAPITokenResolver(resolver APITokenResolver) {
+	apiTokenResolver = resolver
+}
+
 func GetUserID(ctx context.Context) (uuid.UUID, error) {
 	return auth.GetUserID(ctx)
 }
@@ -46,6 +57,10 @@ func Auth(log *logger.Logger, secretKey string) web.Middleware {
 				return web.RespondError(ctx, w, errors.New("unauthorized: token not found"), http.StatusUnauthorized)
 			}
 
+			if strings.HasPrefix(tokenString, auth.APITokenPrefix) {
+				return authenticateAPIToken(ctx, w, r, next, tokenString)
+			}
+
 			claims := &jwt.RegisteredClaims{}
 			token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
 				return []byte(secretKey), nil
@@ -71,6 +86,25 @@ func Auth(log *logger.Logger, secretKey string) web.Middleware {
 	return m
 }
 
+func authenticateAPIToken(ctx context.Context, w http.ResponseWriter, r *http.Request, next web.Handler, tokenString string) error {
+	if apiTokenResolver == nil {
+		return web.RespondError(ctx, w, errors.New("token is invalid"), http.StatusUnauthorized)
+	}
+
+	token, err := apiTokenResolver.ResolveAPIToken(ctx, tokenString)
+	if err != nil {
+		return web.RespondError(ctx, w, errors.New("token is invalid"), http.StatusUnauthorized)
+	}
+	if !token.AuthorizeRequest(r) {
+		return web.RespondError(ctx, w, errors.New("token does not grant access to this resource"), http.StatusForbidden)
+	}
+
+	ctx = auth.SetUserID(ctx, token.UserID)
+	ctx = auth.SetAPIToken(ctx, token)
+
+	return next(ctx, w, r)
+}
+
 func resolveUserIDFromSessionCookie(
 	ctx context.Context,
 	r *http.Request,
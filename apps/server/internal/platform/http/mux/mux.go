@@ -62,6 +62,11 @@ type Config struct {
 	SlackClientSecret  string
 	SlackRedirectURL   string
 	BotToken           string
+	InboundDomain      string
+	InboundSecret      string
+	InboundAuthServID  string
+	InboundMaxBytes    int64
+	InboundMaxPerHour  int
 	AIAPIKey           string
 	SSEHub             *sse.Hub
 	CorsOrigin         string
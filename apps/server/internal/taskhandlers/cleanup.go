@@ -121,6 +121,19 @@ func (c *CleanupHandlers) HandleStoryAutoArchive(ctx context.Context, t *asynq.T
 	return nil
 }
 
+// HandleStorySLAAlerts processes the story SLA alerts task
+func (c *CleanupHandlers) HandleStorySLAAlerts(ctx context.Context, t *asynq.Task) error {
+	c.log.Info(ctx, "HANDLER: Processing StorySLAAlerts task", "task_id", t.ResultWriter().TaskID())
+
+	if err := jobs.ProcessStorySLAAlerts(ctx, c.db, c.log, c.systemUserID); err != nil {
+		c.log.Error(ctx, "Failed to process story SLA alerts", "error", err, "task_id", t.ResultWriter().TaskID())
+		return fmt.Errorf("story SLA alerts failed: %w", err)
+	}
+
+	c.log.Info(ctx, "HANDLER: Successfully processed StorySLAAlerts task", "task_id", t.ResultWriter().TaskID())
+	return nil
+}
+
 // HandleStoryAutoClose processes the story auto-close task
 func (c *CleanupHandlers) HandleStoryAutoClose(ctx context.Context, t *asynq.Task) error {
 	c.log.Info(ctx, "HANDLER: Processing StoryAutoClose task", "task_id", t.ResultWriter().TaskID())
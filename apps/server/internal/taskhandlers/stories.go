@@ -0,0 +1,46 @@
+package taskhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	storiesrepository "github.com/complexus-tech/projects-api/internal/modules/stories/repository"
+	stories "github.com/complexus-tech/projects-api/internal/modules/stories/service"
+	"github.com/complexus-tech/projects-api/pkg/tasks"
+	"github.com/hibiken/asynq"
+)
+
+func (h *handlers) HandleStoryRankRebalance(ctx context.Context, t *asynq.Task) error {
+	var payload tasks.StoryRankRebalancePayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		h.log.Error(ctx, "Failed to unmarshal StoryRankRebalancePayload", "error", err)
+		return fmt.Errorf("unmarshal payload failed: %w: %w", err, asynq.SkipRetry)
+	}
+
+	repo := storiesrepository.New(h.log, h.db)
+	if err := repo.RebalanceStoryRanks(ctx, payload.WorkspaceID); err != nil {
+		h.log.Error(ctx, "Failed to rebalance story ranks", "error", err, "workspace_id", payload.WorkspaceID)
+		return err
+	}
+
+	return nil
+}
+
+func (h *handlers) HandleStorySLAResync(ctx context.Context, t *asynq.Task) error {
+	var payload tasks.StorySLAResyncPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		h.log.Error(ctx, "Failed to unmarshal StorySLAResyncPayload", "error", err)
+		return fmt.Errorf("unmarshal payload failed: %w: %w", err, asynq.SkipRetry)
+	}
+
+	storiesService := stories.New(h.log, storiesrepository.New(h.log, h.db), nil, nil)
+	synced, err := storiesService.ResyncTeamSLA(ctx, payload.WorkspaceID, payload.TeamID)
+	if err != nil {
+		h.log.Error(ctx, "Failed to resync story SLAs", "error", err, "team_id", payload.TeamID, "synced", synced)
+		return err
+	}
+
+	h.log.Info(ctx, "Resynced story SLAs", "team_id", payload.TeamID, "synced", synced)
+	return nil
+}
@@ -0,0 +1,37 @@
+package taskhandlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	webhooksrepository "github.com/complexus-tech/projects-api/internal/modules/webhooks/repository"
+	webhooks "github.com/complexus-tech/projects-api/internal/modules/webhooks/service"
+	"github.com/complexus-tech/projects-api/pkg/tasks"
+	"github.com/hibiken/asynq"
+)
+
+func (h *handlers) HandleWebhookDelivery(ctx context.Context, t *asynq.Task) error {
+	var payload tasks.WebhookDeliveryPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		h.log.Error(ctx, "Failed to unmarshal WebhookDeliveryPayload", "error", err)
+		return fmt.Errorf("unmarshal payload failed: %w: %w", err, asynq.SkipRetry)
+	}
+
+	retryCount, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	finalAttempt := retryCount >= maxRetry
+
+	service := webhooks.New(h.log, webhooksrepository.New(h.log, h.db), nil)
+	if err := service.Deliver(ctx, payload.WorkspaceID, payload.DeliveryID, finalAttempt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("webhook delivery %s not found: %w", payload.DeliveryID, asynq.SkipRetry)
+		}
+		h.log.Warn(ctx, "Webhook delivery attempt failed", "error", err, "delivery_id", payload.DeliveryID, "retry", retryCount, "max_retry", maxRetry)
+		return err
+	}
+
+	return nil
+}
@@ -3,16 +3,19 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
 	"time"
 
+	"github.com/complexus-tech/projects-api/internal/modules/comments/service"
 	"github.com/complexus-tech/projects-api/internal/modules/notifications/service"
 	"github.com/complexus-tech/projects-api/internal/modules/objectives/service"
 	"github.com/complexus-tech/projects-api/internal/modules/states/service"
 	"github.com/complexus-tech/projects-api/internal/modules/stories/service"
 	"github.com/complexus-tech/projects-api/internal/modules/users/service"
+	"github.com/complexus-tech/projects-api/pkg/deadletter"
 	"github.com/complexus-tech/projects-api/pkg/events"
 	"github.com/complexus-tech/projects-api/pkg/logger"
 	"github.com/complexus-tech/projects-api/pkg/mailer"
@@ -26,6 +29,10 @@ const (
 	eventConsumerGroup  = "events-processors"
 	streamReadCount     = 10
 	pendingClaimTimeout = time.Minute * 5
+	processedKeyPrefix  = "events-processed:"
+	processedKeyTTL     = time.Hour * 24 * 7
+	// maxDeliveryAttempts bounds how often a failing message is retried before it is dead-lettered
+	maxDeliveryAttempts = 5
 )
 
 // GitHubCommentSyncer syncs FortyOne comments to linked GitHub issues.
@@ -33,6 +40,11 @@ type GitHubCommentSyncer interface {
 	SyncCommentToGitHub(ctx context.Context, workspaceID, storyID, teamID, localCommentID uuid.UUID, authorName, content string) error
 }
 
+// WebhookDispatcher fans events out to workspace webhook endpoints.
+type WebhookDispatcher interface {
+	Dispatch(ctx context.Context, event events.Event) error
+}
+
 type Consumer struct {
 	redis             *redis.Client
 	log               *logger.Logger
@@ -44,11 +56,13 @@ type Consumer struct {
 	users             *users.Service
 	statuses          *states.Service
 	githubSyncer      GitHubCommentSyncer
+	webhooks          WebhookDispatcher
+	deadLetters       *deadletter.Store
 	websiteURL        string
 }
 
-func New(redis *redis.Client, db *sqlx.DB, log *logger.Logger, websiteURL string, notificationsService *notifications.Service, mailerService mailer.Service, stories *stories.Service, objectives *objectives.Service, users *users.Service, statuses *states.Service, githubSyncer GitHubCommentSyncer) *Consumer {
-	notificationRules := notifications.NewRules(log, stories, users, statuses)
+func New(redis *redis.Client, db *sqlx.DB, log *logger.Logger, websiteURL string, notificationsService *notifications.Service, mailerService mailer.Service, stories *stories.Service, objectives *objectives.Service, users *users.Service, statuses *states.Service, githubSyncer GitHubCommentSyncer, webhooks WebhookDispatcher) *Consumer {
+	notificationRules := notifications.NewRules(log, stories, users, statuses, notificationsService)
 
 	return &Consumer{
 		redis:             redis,
@@ -61,6 +75,8 @@ func New(redis *redis.Client, db *sqlx.DB, log *logger.Logger, websiteURL string
 		users:             users,
 		statuses:          statuses,
 		githubSyncer:      githubSyncer,
+		webhooks:          webhooks,
+		deadLetters:       deadletter.New(redis),
 		websiteURL:        websiteURL,
 	}
 }
@@ -119,7 +135,7 @@ func (c *Consumer) processNewMessages(ctx context.Context, instanceID string) er
 	for _, stream := range streams {
 		for _, message := range stream.Messages {
 			if err := c.processStreamMessage(ctx, message, instanceID); err != nil {
-				c.log.Error(ctx, "failed to process message", "message_id", message.ID, "error", err)
+				c.handleFailedMessage(ctx, message, err)
 				// Continue with other messages
 			}
 		}
@@ -150,11 +166,37 @@ func (c *Consumer) processStreamMessage(ctx context.Context, message redis.XMess
 		return fmt.Errorf("failed to unmarshal event: %w", err)
 	}
 
+	// The outbox relay delivers at least once, so skip events that were already handled
+	dedupeKey, _ := message.Values["dedupe_key"].(string)
+	if dedupeKey != "" {
+		processed, err := c.redis.Exists(ctx, processedKeyPrefix+dedupeKey).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check dedupe key: %w", err)
+		}
+		if processed > 0 {
+			c.log.Info(ctx, "skipping duplicate event", "message_id", message.ID, "dedupe_key", dedupeKey, "event_type", event.Type)
+			return c.redis.XAck(ctx, eventStreamKey, eventConsumerGroup, message.ID).Err()
+		}
+	}
+
 	// Handle the event
 	if err := c.handleEvent(ctx, event); err != nil {
 		return fmt.Errorf("failed to handle event: %w", err)
 	}
 
+	// Webhook fan-out must not hold up internal processing, so failures are only logged
+	if c.webhooks != nil {
+		if err := c.webhooks.Dispatch(ctx, event); err != nil {
+			c.log.Error(ctx, "failed to dispatch webhooks", "message_id", message.ID, "event_type", event.Type, "error", err)
+		}
+	}
+
+	if dedupeKey != "" {
+		if err := c.redis.Set(ctx, processedKeyPrefix+dedupeKey, message.ID, processedKeyTTL).Err(); err != nil {
+			c.log.Error(ctx, "failed to record processed event", "message_id", message.ID, "dedupe_key", dedupeKey, "error", err)
+		}
+	}
+
 	// Acknowledge the message
 	if err := c.redis.XAck(ctx, eventStreamKey, eventConsumerGroup, message.ID).Err(); err != nil {
 		return fmt.Errorf("failed to acknowledge message: %w", err)
@@ -189,6 +231,11 @@ func (c *Consumer) claimPendingMessages(ctx context.Context, instanceID string)
 
 			// Claim messages that are pending for too long
 			for _, p := range pending {
+				if p.Idle > pendingClaimTimeout && p.RetryCount >= maxDeliveryAttempts {
+					// Exhausted before it could be dead-lettered, e.g. the consumer crashed mid-handling
+					c.deadLetterPending(ctx, p, instanceID)
+					continue
+				}
 				if p.Idle > pendingClaimTimeout {
 					claimed, err := c.redis.XClaim(ctx, &redis.XClaimArgs{
 						Stream:   eventStreamKey,
@@ -206,8 +253,7 @@ func (c *Consumer) claimPendingMessages(ctx context.Context, instanceID string)
 					// Process claimed messages
 					for _, msg := range claimed {
 						if err := c.processStreamMessage(ctx, msg, instanceID); err != nil {
-							c.log.Error(ctx, "failed to process claimed message",
-								"message_id", msg.ID, "error", err)
+							c.handleFailedMessage(ctx, msg, err)
 						}
 					}
 				}
@@ -216,6 +262,73 @@ func (c *Consumer) claimPendingMessages(ctx context.Context, instanceID string)
 	}
 }
 
+// handleFailedMessage leaves a failed message pending so claimPendingMessages retries it,
+// until it has been delivered maxDeliveryAttempts times and is moved to the dead-letter stream.
+func (c *Consumer) handleFailedMessage(ctx context.Context, message redis.XMessage, cause error) {
+	attempts, err := c.deliveryCount(ctx, message.ID)
+	if err != nil {
+		c.log.Error(ctx, "failed to process message", "message_id", message.ID, "error", cause)
+		c.log.Error(ctx, "failed to read message delivery count", "message_id", message.ID, "error", err)
+		return
+	}
+
+	if attempts < maxDeliveryAttempts {
+		c.log.Error(ctx, "failed to process message", "message_id", message.ID, "attempt", attempts, "error", cause)
+		return
+	}
+
+	c.deadLetter(ctx, message, attempts, cause)
+}
+
+// deadLetterPending dead-letters a pending message without processing it again.
+func (c *Consumer) deadLetterPending(ctx context.Context, pending redis.XPendingExt, instanceID string) {
+	claimed, err := c.redis.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   eventStreamKey,
+		Group:    eventConsumerGroup,
+		Consumer: instanceID,
+		MinIdle:  pendingClaimTimeout,
+		Messages: []string{pending.ID},
+	}).Result()
+	if err != nil {
+		c.log.Error(ctx, "failed to claim exhausted message", "message_id", pending.ID, "error", err)
+		return
+	}
+	for _, msg := range claimed {
+		c.deadLetter(ctx, msg, int(pending.RetryCount), fmt.Errorf("delivery attempts exhausted without a recorded error"))
+	}
+}
+
+func (c *Consumer) deadLetter(ctx context.Context, message redis.XMessage, attempts int, cause error) {
+	id, err := c.deadLetters.Add(ctx, message, attempts, cause)
+	if err != nil {
+		// Leave the message pending so the next claim cycle tries again
+		c.log.Error(ctx, "failed to dead-letter message", "message_id", message.ID, "error", err)
+		return
+	}
+	if err := c.redis.XAck(ctx, eventStreamKey, eventConsumerGroup, message.ID).Err(); err != nil {
+		c.log.Error(ctx, "failed to acknowledge dead-lettered message", "message_id", message.ID, "error", err)
+	}
+	c.log.Error(ctx, "message dead-lettered", "message_id", message.ID, "dead_letter_id", id, "attempts", attempts, "error", cause)
+}
+
+// deliveryCount returns how many times the group has delivered the message, including the current attempt.
+func (c *Consumer) deliveryCount(ctx context.Context, messageID string) (int, error) {
+	pending, err := c.redis.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: eventStreamKey,
+		Group:  eventConsumerGroup,
+		Start:  messageID,
+		End:    messageID,
+		Count:  1,
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, fmt.Errorf("message %s is not pending", messageID)
+	}
+	return int(pending[0].RetryCount), nil
+}
+
 // handleEvent routes events to the appropriate handler based on the event type
 func (c *Consumer) handleEvent(ctx context.Context, event events.Event) error {
 	switch event.Type {
@@ -223,10 +336,14 @@ func (c *Consumer) handleEvent(ctx context.Context, event events.Event) error {
 		return c.handleStoryCreated(ctx, event)
 	case events.StoryUpdated:
 		return c.handleStoryUpdated(ctx, event)
+	case events.StorySLAAlert:
+		return c.handleStorySLAAlert(ctx, event)
 	case events.CommentCreated:
 		return c.handleCommentCreated(ctx, event)
 	case events.CommentReplied:
 		return c.handleCommentReplied(ctx, event)
+	case events.CommentReacted:
+		return c.handleCommentReacted(ctx, event)
 	case events.UserMentioned:
 		return c.handleUserMentioned(ctx, event)
 	case events.ObjectiveUpdated:
@@ -610,6 +727,36 @@ func (c *Consumer) handleInvitationAccepted(ctx context.Context, event events.Ev
 	return nil
 }
 
+// handleStorySLAAlert notifies the assignee and watchers of a story whose
+// SLA target is at risk or breached.
+func (c *Consumer) handleStorySLAAlert(ctx context.Context, event events.Event) error {
+	c.log.Info(ctx, "consumer.handleStorySLAAlert", "event_type", event.Type)
+
+	var payload events.StorySLAAlertPayload
+	payloadBytes, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	notifications, err := c.notificationRules.ProcessStorySLAAlert(ctx, payload, event.ActorID)
+	if err != nil {
+		c.log.Error(ctx, "failed to process story SLA alert rules", "error", err)
+		return err
+	}
+
+	for _, notification := range notifications {
+		if _, err := c.notifications.Create(ctx, notification); err != nil {
+			c.log.Error(ctx, "failed to create notification", "error", err)
+		}
+	}
+
+	return nil
+}
+
 func (c *Consumer) handleCommentCreated(ctx context.Context, event events.Event) error {
 	c.log.Info(ctx, "consumer.handleCommentCreated", "event_type", event.Type)
 
@@ -672,6 +819,78 @@ func (c *Consumer) handleCommentReplied(ctx context.Context, event events.Event)
 	return nil
 }
 
+// handleCommentReacted broadcasts a comment's reactions to the workspace and
+// notifies the comment author.
+func (c *Consumer) handleCommentReacted(ctx context.Context, event events.Event) error {
+	c.log.Info(ctx, "consumer.handleCommentReacted", "event_type", event.Type)
+
+	var payload events.CommentReactedPayload
+	payloadBytes, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	reactions, err := c.stories.CommentReactions(ctx, payload.CommentID)
+	if err != nil {
+		if errors.Is(err, stories.ErrCommentNotFound) {
+			// The comment was deleted before the event was handled
+			return nil
+		}
+		return fmt.Errorf("failed to get comment reactions: %w", err)
+	}
+
+	c.broadcastReactions(ctx, payload, reactions, event.ActorID)
+
+	notifications, err := c.notificationRules.ProcessCommentReacted(ctx, payload, event.ActorID, reactions)
+	if err != nil {
+		c.log.Error(ctx, "failed to process comment reacted rules", "error", err)
+		return err
+	}
+
+	for _, notification := range notifications {
+		if _, err := c.notifications.Create(ctx, notification); err != nil {
+			c.log.Error(ctx, "failed to create notification", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// broadcastReactions publishes a comment's current reactions to the workspace channel.
+func (c *Consumer) broadcastReactions(ctx context.Context, payload events.CommentReactedPayload, reactions []comments.CoreReaction, actorID uuid.UUID) {
+	frontendReactions := make([]map[string]any, len(reactions))
+	for i, reaction := range reactions {
+		frontendReactions[i] = map[string]any{
+			"emoji":   reaction.Emoji,
+			"count":   reaction.Count,
+			"userIds": reaction.UserIDs,
+		}
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"type":        "comment.reactions_updated",
+		"storyId":     payload.StoryID,
+		"commentId":   payload.CommentID,
+		"workspaceId": payload.WorkspaceID,
+		"reactions":   frontendReactions,
+		"actorId":     actorID,
+		"timestamp":   time.Now().Unix(),
+	})
+	if err != nil {
+		c.log.Error(ctx, "failed to marshal reactions update", "error", err)
+		return
+	}
+
+	channelName := fmt.Sprintf("workspace-updates:%s", payload.WorkspaceID.String())
+	if err := c.redis.Publish(ctx, channelName, data).Err(); err != nil {
+		c.log.Error(ctx, "failed to publish reactions update", "error", err)
+	}
+}
+
 func (c *Consumer) handleUserMentioned(ctx context.Context, event events.Event) error {
 	c.log.Info(ctx, "consumer.handleUserMentioned", "event_type", event.Type)
 
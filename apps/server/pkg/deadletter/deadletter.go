@@ -0,0 +1,197 @@
+// Package deadletter keeps events-stream messages that exhausted their
+// delivery attempts, along with the failure that stopped them, so they can be
+// inspected and replayed or discarded later.
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// StreamKey is the Redis stream holding dead-lettered events.
+	StreamKey = "events-dead-letter"
+
+	eventStreamKey = "events-stream"
+	originalPrefix = "original_"
+	maxStreamLen   = 10000
+)
+
+var ErrNotFound = errors.New("dead-lettered event not found")
+
+// Entry is a dead-lettered message and the context it failed in.
+type Entry struct {
+	ID         string            `json:"id"`
+	MessageID  string            `json:"messageId"`
+	EventType  string            `json:"eventType"`
+	Error      string            `json:"error"`
+	Attempts   int               `json:"attempts"`
+	FailedAt   time.Time         `json:"failedAt"`
+	Payload    string            `json:"payload"`
+	DedupeKey  string            `json:"dedupeKey,omitempty"`
+	Original   map[string]string `json:"original"`
+	ReplayedAs string            `json:"replayedAs,omitempty"`
+}
+
+// Page is a newest-first slice of the dead-letter stream.
+type Page struct {
+	Items      []Entry `json:"items"`
+	Total      int64   `json:"total"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
+
+type Store struct {
+	redis *redis.Client
+}
+
+func New(redis *redis.Client) *Store {
+	return &Store{redis: redis}
+}
+
+// Add copies message into the dead-letter stream. The original fields are kept
+// verbatim under an "original_" prefix so a replay republishes exactly what failed.
+func (s *Store) Add(ctx context.Context, message redis.XMessage, attempts int, cause error) (string, error) {
+	values := map[string]any{
+		"message_id": message.ID,
+		"attempts":   attempts,
+		"failed_at":  time.Now().UTC().Format(time.RFC3339),
+	}
+	if cause != nil {
+		values["error"] = cause.Error()
+	}
+	for key, value := range message.Values {
+		values[originalPrefix+key] = value
+	}
+
+	id, err := s.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: StreamKey,
+		MaxLen: maxStreamLen,
+		Approx: true,
+		Values: values,
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to add dead-lettered event: %w", err)
+	}
+	return id, nil
+}
+
+// List returns up to limit entries older than cursor, newest first. An empty
+// cursor starts from the most recent entry.
+func (s *Store) List(ctx context.Context, cursor string, limit int) (Page, error) {
+	end := "+"
+	if cursor != "" {
+		end = "(" + cursor
+	}
+
+	messages, err := s.redis.XRevRangeN(ctx, StreamKey, end, "-", int64(limit)+1).Result()
+	if err != nil {
+		return Page{}, fmt.Errorf("failed to list dead-lettered events: %w", err)
+	}
+	total, err := s.redis.XLen(ctx, StreamKey).Result()
+	if err != nil {
+		return Page{}, fmt.Errorf("failed to count dead-lettered events: %w", err)
+	}
+
+	page := Page{Items: make([]Entry, 0, len(messages)), Total: total}
+	if len(messages) > limit {
+		messages = messages[:limit]
+		page.NextCursor = messages[len(messages)-1].ID
+	}
+	for _, message := range messages {
+		page.Items = append(page.Items, toEntry(message))
+	}
+	return page, nil
+}
+
+// Get returns a single dead-lettered entry.
+func (s *Store) Get(ctx context.Context, id string) (Entry, error) {
+	messages, err := s.redis.XRangeN(ctx, StreamKey, id, id, 1).Result()
+	if err != nil {
+		if strings.Contains(err.Error(), "Invalid stream ID") {
+			return Entry{}, ErrNotFound
+		}
+		return Entry{}, fmt.Errorf("failed to get dead-lettered event: %w", err)
+	}
+	if len(messages) == 0 {
+		return Entry{}, ErrNotFound
+	}
+	return toEntry(messages[0]), nil
+}
+
+// Replay republishes the original message to the events stream and removes it
+// from the dead-letter stream. The returned entry carries the new stream ID.
+func (s *Store) Replay(ctx context.Context, id string) (Entry, error) {
+	entry, err := s.Get(ctx, id)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	values := make(map[string]any, len(entry.Original))
+	for key, value := range entry.Original {
+		values[key] = value
+	}
+	newID, err := s.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: eventStreamKey,
+		Values: values,
+	}).Result()
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to replay dead-lettered event: %w", err)
+	}
+
+	if err := s.redis.XDel(ctx, StreamKey, id).Err(); err != nil {
+		return Entry{}, fmt.Errorf("failed to remove replayed event: %w", err)
+	}
+
+	entry.ReplayedAs = newID
+	return entry, nil
+}
+
+// Discard deletes a dead-lettered entry without replaying it.
+func (s *Store) Discard(ctx context.Context, id string) (Entry, error) {
+	entry, err := s.Get(ctx, id)
+	if err != nil {
+		return Entry{}, err
+	}
+	if err := s.redis.XDel(ctx, StreamKey, id).Err(); err != nil {
+		return Entry{}, fmt.Errorf("failed to discard dead-lettered event: %w", err)
+	}
+	return entry, nil
+}
+
+func toEntry(message redis.XMessage) Entry {
+	entry := Entry{
+		ID:        message.ID,
+		MessageID: stringValue(message.Values["message_id"]),
+		Error:     stringValue(message.Values["error"]),
+		Original:  map[string]string{},
+	}
+	entry.Attempts, _ = strconv.Atoi(stringValue(message.Values["attempts"]))
+	entry.FailedAt, _ = time.Parse(time.RFC3339, stringValue(message.Values["failed_at"]))
+
+	for key, value := range message.Values {
+		if field, ok := strings.CutPrefix(key, originalPrefix); ok {
+			entry.Original[field] = stringValue(value)
+		}
+	}
+	entry.EventType = entry.Original["type"]
+	entry.Payload = entry.Original["payload"]
+	entry.DedupeKey = entry.Original["dedupe_key"]
+	return entry
+}
+
+func stringValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -12,8 +12,10 @@ const (
 	StoryCreated                           EventType = "story.created"
 	StoryUpdated                           EventType = "story.updated"
 	StoryDuplicated                        EventType = "story.duplicated"
+	StorySLAAlert                          EventType = "story.sla.alert"
 	CommentCreated                         EventType = "comment.created"
 	CommentReplied                         EventType = "comment.replied"
+	CommentReacted                         EventType = "comment.reacted"
 	UserMentioned                          EventType = "user.mentioned"
 	ObjectiveUpdated                       EventType = "objective.updated"
 	KeyResultUpdated                       EventType = "keyresult.updated"
@@ -32,6 +34,8 @@ type Event struct {
 	Payload   any       `json:"payload"`
 	Timestamp time.Time `json:"timestamp"`
 	ActorID   uuid.UUID `json:"actor_id"`
+	// DedupeKey identifies an event across redeliveries so consumers can skip duplicates.
+	DedupeKey string `json:"dedupe_key,omitempty"`
 }
 
 // StoryCreatedPayload contains data for story creation events
@@ -51,6 +55,19 @@ type StoryUpdatedPayload struct {
 	AssigneeID  *uuid.UUID     `json:"assignee_id,omitempty"`
 }
 
+// StorySLAAlertPayload contains data for a story SLA target that became at
+// risk or was breached. Target is first_response or resolve and Level is
+// at_risk or breached.
+type StorySLAAlertPayload struct {
+	StoryID     uuid.UUID  `json:"story_id"`
+	WorkspaceID uuid.UUID  `json:"workspace_id"`
+	StoryTitle  string     `json:"story_title"`
+	AssigneeID  *uuid.UUID `json:"assignee_id,omitempty"`
+	Target      string     `json:"target"`
+	Level       string     `json:"level"`
+	DueAt       time.Time  `json:"due_at"`
+}
+
 // ObjectiveUpdatedPayload contains data for objective update events
 type ObjectiveUpdatedPayload struct {
 	ObjectiveID uuid.UUID      `json:"objective_id"`
@@ -128,6 +145,18 @@ type CommentRepliedPayload struct {
 	Mentions        []uuid.UUID `json:"mentions"`
 }
 
+// CommentReactedPayload contains data for comment reaction events. The event
+// is written when a reaction is added or removed; consumers read the current
+// reactions.
+type CommentReactedPayload struct {
+	CommentID       uuid.UUID `json:"comment_id"`
+	CommentAuthorID uuid.UUID `json:"comment_author_id"`
+	StoryID         uuid.UUID `json:"story_id"`
+	StoryTitle      string    `json:"story_title"`
+	WorkspaceID     uuid.UUID `json:"workspace_id"`
+	Emoji           string    `json:"emoji"`
+}
+
 // UserMentionedPayload contains data for user mention events
 type UserMentionedPayload struct {
 	CommentID     uuid.UUID `json:"comment_id"`
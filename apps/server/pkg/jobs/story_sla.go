@@ -0,0 +1,170 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/complexus-tech/projects-api/pkg/outbox"
+	"github.com/complexus-tech/projects-api/pkg/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// storySLAAlertBatchSize caps the clocks alerted on in one run; the rest
+// wait for the next run.
+const storySLAAlertBatchSize = 500
+
+// slaAlertClock is a running SLA clock with a target that is at risk or
+// past due.
+type slaAlertClock struct {
+	StoryID                 uuid.UUID  `db:"story_id"`
+	WorkspaceID             uuid.UUID  `db:"workspace_id"`
+	Title                   string     `db:"title"`
+	AssigneeID              *uuid.UUID `db:"assignee_id"`
+	FirstResponseAtRiskAt   *time.Time `db:"first_response_at_risk_at"`
+	FirstResponseDueAt      *time.Time `db:"first_response_due_at"`
+	FirstRespondedAt        *time.Time `db:"first_responded_at"`
+	FirstResponseBreachedAt *time.Time `db:"first_response_breached_at"`
+	FirstResponseAlert      *string    `db:"first_response_alert"`
+	ResolveAtRiskAt         *time.Time `db:"resolve_at_risk_at"`
+	ResolveDueAt            *time.Time `db:"resolve_due_at"`
+	ResolvedAt              *time.Time `db:"resolved_at"`
+	ResolveBreachedAt       *time.Time `db:"resolve_breached_at"`
+	ResolveAlert            *string    `db:"resolve_alert"`
+}
+
+// slaAlert is the alert an SLA target is due, if any: at_risk once, then
+// breached once.
+func slaAlert(atRiskAt, dueAt, metAt, breachedAt *time.Time, alert *string, now time.Time) (string, time.Time) {
+	if metAt != nil {
+		return "", time.Time{}
+	}
+	sent := ""
+	if alert != nil {
+		sent = *alert
+	}
+	switch {
+	case breachedAt != nil && sent != "breached":
+		return "breached", *breachedAt
+	case dueAt != nil && !dueAt.After(now) && sent != "breached":
+		return "breached", *dueAt
+	case atRiskAt != nil && !atRiskAt.After(now) && sent == "":
+		return "at_risk", *dueAt
+	}
+	return "", time.Time{}
+}
+
+// ProcessStorySLAAlerts marks SLA targets that became at risk or breached
+// since the last run and raises an alert event for each, which notifies the
+// story's assignee and watchers.
+func ProcessStorySLAAlerts(ctx context.Context, db *sqlx.DB, log *logger.Logger, systemUserID uuid.UUID) error {
+	ctx, span := web.AddSpan(ctx, "jobs.ProcessStorySLAAlerts")
+	defer span.End()
+
+	log.Info(ctx, "Processing story SLA alerts")
+	startTime := time.Now()
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT
+			c.story_id, c.workspace_id, s.title, s.assignee_id,
+			c.first_response_at_risk_at, c.first_response_due_at, c.first_responded_at,
+			c.first_response_breached_at, c.first_response_alert,
+			c.resolve_at_risk_at, c.resolve_due_at, c.resolved_at,
+			c.resolve_breached_at, c.resolve_alert
+		FROM story_sla_clocks c
+		INNER JOIN stories s ON s.id = c.story_id
+		WHERE s.deleted_at IS NULL
+			AND s.archived_at IS NULL
+			AND (
+				(c.first_responded_at IS NULL AND c.first_response_alert IS DISTINCT FROM 'breached' AND (
+					c.first_response_breached_at IS NOT NULL
+					OR c.first_response_due_at <= NOW()
+					OR (c.first_response_alert IS NULL AND c.first_response_at_risk_at <= NOW())
+				))
+				OR (c.resolved_at IS NULL AND c.resolve_alert IS DISTINCT FROM 'breached' AND (
+					c.resolve_breached_at IS NOT NULL
+					OR c.resolve_due_at <= NOW()
+					OR (c.resolve_alert IS NULL AND c.resolve_at_risk_at <= NOW())
+				))
+			)
+		ORDER BY c.story_id
+		LIMIT $1
+		FOR UPDATE OF c SKIP LOCKED`
+
+	var clocks []slaAlertClock
+	if err := tx.SelectContext(ctx, &clocks, query, storySLAAlertBatchSize); err != nil {
+		span.RecordError(err)
+		log.Error(ctx, "Failed to find story SLA clocks to alert on", "error", err)
+		return fmt.Errorf("failed to find story SLA clocks to alert on: %w", err)
+	}
+
+	now := time.Now()
+	var alerts []events.Event
+	for _, clock := range clocks {
+		firstResponseLevel, firstResponseAt := slaAlert(clock.FirstResponseAtRiskAt, clock.FirstResponseDueAt, clock.FirstRespondedAt, clock.FirstResponseBreachedAt, clock.FirstResponseAlert, now)
+		resolveLevel, resolveAt := slaAlert(clock.ResolveAtRiskAt, clock.ResolveDueAt, clock.ResolvedAt, clock.ResolveBreachedAt, clock.ResolveAlert, now)
+		targets := []struct {
+			name  string
+			level string
+			at    time.Time
+		}{
+			{"first_response", firstResponseLevel, firstResponseAt},
+			{"resolve", resolveLevel, resolveAt},
+		}
+
+		for _, target := range targets {
+			if target.level == "" {
+				continue
+			}
+			update := fmt.Sprintf(`
+				UPDATE story_sla_clocks
+				SET %[1]s_alert = $2,
+					%[1]s_breached_at = CASE WHEN $2 = 'breached' THEN COALESCE(%[1]s_breached_at, $3) ELSE %[1]s_breached_at END,
+					updated_at = NOW()
+				WHERE story_id = $1`, target.name)
+			if _, err := tx.ExecContext(ctx, update, clock.StoryID, target.level, target.at); err != nil {
+				span.RecordError(err)
+				log.Error(ctx, "Failed to mark story SLA alert", "error", err, "story_id", clock.StoryID)
+				return fmt.Errorf("failed to mark story SLA alert: %w", err)
+			}
+
+			alerts = append(alerts, events.Event{
+				Type: events.StorySLAAlert,
+				Payload: events.StorySLAAlertPayload{
+					StoryID:     clock.StoryID,
+					WorkspaceID: clock.WorkspaceID,
+					StoryTitle:  clock.Title,
+					AssigneeID:  clock.AssigneeID,
+					Target:      target.name,
+					Level:       target.level,
+					DueAt:       target.at,
+				},
+				Timestamp: now,
+				ActorID:   systemUserID,
+				DedupeKey: fmt.Sprintf("story.sla.alert:%s:%s:%s:%d", clock.StoryID, target.name, target.level, target.at.Unix()),
+			})
+		}
+	}
+
+	if err := outbox.Write(ctx, tx, alerts...); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Info(ctx, fmt.Sprintf("Story SLA alerts completed: %d alerts raised in %v", len(alerts), time.Since(startTime)))
+	span.SetAttributes(attribute.Int("alerts.raised", len(alerts)))
+	return nil
+}
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSLAAlert(t *testing.T) {
+	now := time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	atRisk, breached := "at_risk", "breached"
+
+	tests := []struct {
+		name       string
+		atRiskAt   *time.Time
+		dueAt      *time.Time
+		metAt      *time.Time
+		breachedAt *time.Time
+		alert      *string
+		want       string
+		wantAt     time.Time
+	}{
+		{"on track", &future, &future, nil, nil, nil, "", time.Time{}},
+		{"at risk", &past, &future, nil, nil, nil, "at_risk", future},
+		{"at risk alerted once", &past, &future, nil, nil, &atRisk, "", time.Time{}},
+		{"past due", &past, &past, nil, nil, &atRisk, "breached", past},
+		{"breached while paused", nil, nil, nil, &past, nil, "breached", past},
+		{"breach alerted once", &past, &past, nil, &past, &breached, "", time.Time{}},
+		{"met", &past, &past, &now, nil, nil, "", time.Time{}},
+	}
+	for _, tt := range tests {
+		got, gotAt := slaAlert(tt.atRiskAt, tt.dueAt, tt.metAt, tt.breachedAt, tt.alert, now)
+		if got != tt.want || !gotAt.Equal(tt.wantAt) {
+			t.Errorf("%s: got %q at %v, want %q at %v", tt.name, got, gotAt, tt.want, tt.wantAt)
+		}
+	}
+}
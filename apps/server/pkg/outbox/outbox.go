@@ -0,0 +1,42 @@
+// Package outbox stores domain events in Postgres inside the transaction that
+// produced them and relays them to the Redis events stream afterwards, so an
+// unavailable Redis delays events instead of dropping them.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Write records events in the outbox using tx, which should be the transaction
+// that persists the change the events describe. Events without a dedupe key get one.
+func Write(ctx context.Context, tx sqlx.ExecerContext, evts ...events.Event) error {
+	for _, event := range evts {
+		if event.DedupeKey == "" {
+			event.DedupeKey = uuid.NewString()
+		}
+		if event.Timestamp.IsZero() {
+			event.Timestamp = time.Now()
+		}
+
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("outbox: failed to marshal %s event: %w", event.Type, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO event_outbox (dedupe_key, event_type, payload)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (dedupe_key) DO NOTHING
+		`, event.DedupeKey, string(event.Type), payload); err != nil {
+			return fmt.Errorf("outbox: failed to write %s event: %w", event.Type, err)
+		}
+	}
+	return nil
+}
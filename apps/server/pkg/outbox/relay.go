@@ -0,0 +1,184 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	relayBatchSize     = 100
+	relayPollInterval  = time.Second
+	purgeInterval      = time.Hour
+	publishedRetention = 7 * 24 * time.Hour
+)
+
+// Publisher adds events to the events stream.
+type Publisher interface {
+	Publish(ctx context.Context, event events.Event) error
+}
+
+// Relay drains unpublished outbox rows into the events stream in insertion order.
+// A row is only marked published after the stream accepted it, so delivery is
+// at-least-once; consumers use the event dedupe key to drop repeats.
+type Relay struct {
+	db        *sqlx.DB
+	publisher Publisher
+	log       *logger.Logger
+}
+
+func NewRelay(db *sqlx.DB, publisher Publisher, log *logger.Logger) *Relay {
+	return &Relay{db: db, publisher: publisher, log: log}
+}
+
+type outboxRow struct {
+	ID      int64  `db:"outbox_id"`
+	Payload []byte `db:"payload"`
+}
+
+// Start polls the outbox until ctx is cancelled.
+func (r *Relay) Start(ctx context.Context) error {
+	r.log.Info(ctx, "starting event outbox relay")
+
+	poll := time.NewTicker(relayPollInterval)
+	defer poll.Stop()
+	purge := time.NewTicker(purgeInterval)
+	defer purge.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-purge.C:
+			if err := r.purgePublished(ctx); err != nil {
+				r.log.Error(ctx, "failed to purge published outbox events", "error", err)
+			}
+		case <-poll.C:
+			// Keep draining while full batches come back so a backlog clears quickly
+			for {
+				relayed, err := r.RelayBatch(ctx)
+				if err != nil {
+					r.log.Error(ctx, "failed to relay outbox events", "error", err)
+					break
+				}
+				if relayed < relayBatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// RelayBatch publishes the oldest unpublished events and returns how many were relayed.
+// Rows are locked with SKIP LOCKED so several API instances can relay concurrently.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var rows []outboxRow
+	if err := tx.SelectContext(ctx, &rows, `
+		SELECT outbox_id, payload
+		FROM event_outbox
+		WHERE published_at IS NULL AND dead_at IS NULL
+		ORDER BY outbox_id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`, relayBatchSize); err != nil {
+		return 0, fmt.Errorf("failed to load outbox events: %w", err)
+	}
+
+	relayed, err := r.relayRows(ctx, rows, txBatch{tx: tx, log: r.log})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit outbox batch: %w", err)
+	}
+	return relayed, nil
+}
+
+// batch records the outcome of each row of a locked batch.
+type batch interface {
+	markPublished(ctx context.Context, id int64) error
+	markDead(ctx context.Context, id int64, cause error) error
+	recordFailure(ctx context.Context, id int64, cause error)
+}
+
+func (r *Relay) relayRows(ctx context.Context, rows []outboxRow, b batch) (int, error) {
+	relayed := 0
+	for _, row := range rows {
+		var event events.Event
+		if err := json.Unmarshal(row.Payload, &event); err != nil {
+			// A payload that cannot be decoded will never succeed; mark it dead so
+			// it is no longer selected, keeping the error for inspection
+			r.log.Error(ctx, "dropping undecodable outbox event", "outbox_id", row.ID, "error", err)
+			if err := b.markDead(ctx, row.ID, err); err != nil {
+				return 0, fmt.Errorf("failed to mark outbox event dead: %w", err)
+			}
+			continue
+		}
+
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			// Stop at the first failure to preserve ordering; the rest are retried next poll
+			r.log.Error(ctx, "failed to relay outbox event", "outbox_id", row.ID, "error", err)
+			b.recordFailure(ctx, row.ID, err)
+			break
+		}
+
+		if err := b.markPublished(ctx, row.ID); err != nil {
+			return 0, fmt.Errorf("failed to mark outbox event published: %w", err)
+		}
+		relayed++
+	}
+	return relayed, nil
+}
+
+type txBatch struct {
+	tx  *sqlx.Tx
+	log *logger.Logger
+}
+
+func (b txBatch) markPublished(ctx context.Context, id int64) error {
+	_, err := b.tx.ExecContext(ctx, `
+		UPDATE event_outbox
+		SET published_at = NOW(), attempts = attempts + 1, last_error = NULL
+		WHERE outbox_id = $1
+	`, id)
+	return err
+}
+
+func (b txBatch) markDead(ctx context.Context, id int64, cause error) error {
+	_, err := b.tx.ExecContext(ctx, `
+		UPDATE event_outbox
+		SET dead_at = NOW(), attempts = attempts + 1, last_error = $2
+		WHERE outbox_id = $1
+	`, id, cause.Error())
+	return err
+}
+
+func (b txBatch) recordFailure(ctx context.Context, id int64, cause error) {
+	if _, err := b.tx.ExecContext(ctx, `
+		UPDATE event_outbox
+		SET attempts = attempts + 1, last_error = $2
+		WHERE outbox_id = $1
+	`, id, cause.Error()); err != nil {
+		b.log.Error(ctx, "failed to record outbox relay failure", "outbox_id", id, "error", err)
+	}
+}
+
+func (r *Relay) purgePublished(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM event_outbox
+		WHERE published_at IS NOT NULL AND published_at < $1
+	`, time.Now().Add(-publishedRetention))
+	return err
+}
@@ -0,0 +1,109 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/complexus-tech/projects-api/pkg/events"
+	"github.com/complexus-tech/projects-api/pkg/logger"
+)
+
+type publisherStub struct {
+	published []events.Event
+	err       error
+}
+
+func (p *publisherStub) Publish(ctx context.Context, event events.Event) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, event)
+	return nil
+}
+
+type batchStub struct {
+	published []int64
+	dead      []int64
+	failed    []int64
+}
+
+func (b *batchStub) markPublished(ctx context.Context, id int64) error {
+	b.published = append(b.published, id)
+	return nil
+}
+
+func (b *batchStub) markDead(ctx context.Context, id int64, cause error) error {
+	b.dead = append(b.dead, id)
+	return nil
+}
+
+func (b *batchStub) recordFailure(ctx context.Context, id int64, cause error) {
+	b.failed = append(b.failed, id)
+}
+
+func newTestRelay(publisher Publisher) *Relay {
+	return NewRelay(nil, publisher, logger.NewWithText(io.Discard, slog.LevelError, "outbox-test"))
+}
+
+func payload(t *testing.T, eventType events.EventType) []byte {
+	t.Helper()
+	data, err := json.Marshal(events.Event{Type: eventType})
+	if err != nil {
+		t.Fatalf("marshal event: %v", err)
+	}
+	return data
+}
+
+func TestRelayRowsMarksUndecodablePayloadDead(t *testing.T) {
+	publisher := &publisherStub{}
+	b := &batchStub{}
+	rows := []outboxRow{
+		{ID: 1, Payload: payload(t, events.StoryCreated)},
+		{ID: 2, Payload: []byte(`{"type":`)},
+		{ID: 3, Payload: payload(t, events.StoryUpdated)},
+	}
+
+	relayed, err := newTestRelay(publisher).relayRows(context.Background(), rows, b)
+	if err != nil {
+		t.Fatalf("relayRows returned error: %v", err)
+	}
+	if relayed != 2 {
+		t.Fatalf("expected 2 relayed events, got %d", relayed)
+	}
+	if len(b.dead) != 1 || b.dead[0] != 2 {
+		t.Fatalf("expected row 2 to be marked dead, got %v", b.dead)
+	}
+	if len(b.published) != 2 || b.published[0] != 1 || b.published[1] != 3 {
+		t.Fatalf("expected rows 1 and 3 to be published, got %v", b.published)
+	}
+	if len(b.failed) != 0 {
+		t.Fatalf("expected no retryable failures, got %v", b.failed)
+	}
+}
+
+func TestRelayRowsStopsAtFirstPublishFailure(t *testing.T) {
+	publisher := &publisherStub{err: errors.New("stream unavailable")}
+	b := &batchStub{}
+	rows := []outboxRow{
+		{ID: 1, Payload: payload(t, events.StoryCreated)},
+		{ID: 2, Payload: payload(t, events.StoryUpdated)},
+	}
+
+	relayed, err := newTestRelay(publisher).relayRows(context.Background(), rows, b)
+	if err != nil {
+		t.Fatalf("relayRows returned error: %v", err)
+	}
+	if relayed != 0 {
+		t.Fatalf("expected nothing relayed, got %d", relayed)
+	}
+	if len(b.failed) != 1 || b.failed[0] != 1 {
+		t.Fatalf("expected only row 1 to record a failure, got %v", b.failed)
+	}
+	if len(b.dead) != 0 {
+		t.Fatalf("expected publish failures to stay retryable, got dead rows %v", b.dead)
+	}
+}
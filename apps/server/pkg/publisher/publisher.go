@@ -42,6 +42,9 @@ func (p *Publisher) Publish(ctx context.Context, event events.Event) error {
 		"timestamp": event.Timestamp.Format(time.RFC3339),
 		"actor_id":  event.ActorID.String(),
 	}
+	if event.DedupeKey != "" {
+		fields["dedupe_key"] = event.DedupeKey
+	}
 
 	_, err = p.redis.XAdd(ctx, &redis.XAddArgs{
 		Stream: eventStreamKey,
@@ -0,0 +1,328 @@
+// Package smtpd is a minimal SMTP server for receiving mail locally. It
+// speaks enough of RFC 5321 for mail clients and net/smtp to deliver a
+// message and hands each message to a Handler. It does not relay mail or
+// support TLS or authentication, so it is meant for development and tests.
+package smtpd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxMessageBytes = 25 << 20
+	defaultMaxRecipients   = 100
+	commandTimeout         = 5 * time.Minute
+)
+
+// ErrServerClosed is returned by Serve after Shutdown.
+var ErrServerClosed = errors.New("smtpd: server closed")
+
+// Envelope is a message as delivered over SMTP.
+type Envelope struct {
+	RemoteAddr string
+	From       string
+	To         []string
+	Data       []byte
+}
+
+// Handler receives delivered messages. A returned error rejects the message
+// with a temporary failure so the client may retry.
+type Handler func(ctx context.Context, envelope Envelope) error
+
+// Server is an SMTP server that passes every message it receives to Handler.
+type Server struct {
+	// Hostname is announced in the greeting.
+	Hostname        string
+	Handler         Handler
+	MaxMessageBytes int64
+	MaxRecipients   int
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// ListenAndServe listens on the TCP address addr and serves connections.
+func (s *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve accepts connections on listener until Shutdown is called.
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+	s.listener = listener
+	s.conns = make(map[net.Conn]struct{})
+	s.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(conn)
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// Shutdown stops accepting connections and closes open ones.
+func (s *Server) Shutdown() error {
+	s.mu.Lock()
+	s.closed = true
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// session is the state of one SMTP conversation.
+type session struct {
+	server   *Server
+	conn     net.Conn
+	text     *textproto.Conn
+	greeted  bool
+	envelope Envelope
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	sess := &session{
+		server: s,
+		conn:   conn,
+		text:   textproto.NewConn(conn),
+	}
+	sess.reset()
+	sess.reply(220, fmt.Sprintf("%s ESMTP ready", s.hostname()))
+
+	for {
+		conn.SetDeadline(time.Now().Add(commandTimeout))
+		line, err := sess.text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		if quit := sess.handle(strings.ToUpper(verb), strings.TrimSpace(arg)); quit {
+			return
+		}
+	}
+}
+
+func (sess *session) handle(verb, arg string) bool {
+	switch verb {
+	case "HELO":
+		sess.greeted = true
+		sess.reset()
+		sess.reply(250, sess.server.hostname())
+	case "EHLO":
+		sess.greeted = true
+		sess.reset()
+		sess.reply(250,
+			sess.server.hostname(),
+			"SIZE "+strconv.FormatInt(sess.server.maxMessageBytes(), 10),
+			"8BITMIME",
+			"PIPELINING",
+		)
+	case "MAIL":
+		sess.mail(arg)
+	case "RCPT":
+		sess.rcpt(arg)
+	case "DATA":
+		sess.data()
+	case "RSET":
+		sess.reset()
+		sess.reply(250, "OK")
+	case "NOOP":
+		sess.reply(250, "OK")
+	case "VRFY":
+		sess.reply(252, "Cannot verify user")
+	case "QUIT":
+		sess.reply(221, "Bye")
+		return true
+	default:
+		sess.reply(502, "Command not implemented")
+	}
+	return false
+}
+
+func (sess *session) mail(arg string) {
+	if !sess.greeted {
+		sess.reply(503, "Send HELO or EHLO first")
+		return
+	}
+	if sess.envelope.From != "" {
+		sess.reply(503, "Sender already specified")
+		return
+	}
+	from, params, ok := parsePath(arg, "FROM:")
+	if !ok {
+		sess.reply(501, "Syntax: MAIL FROM:<address>")
+		return
+	}
+	for _, param := range params {
+		key, value, _ := strings.Cut(param, "=")
+		if strings.EqualFold(key, "SIZE") {
+			if size, err := strconv.ParseInt(value, 10, 64); err == nil && size > sess.server.maxMessageBytes() {
+				sess.reply(552, "Message exceeds maximum size")
+				return
+			}
+		}
+	}
+	// The null reverse-path of bounces is kept as "<>" so it is non-empty.
+	if from == "" {
+		from = "<>"
+	}
+	sess.envelope.From = from
+	sess.reply(250, "OK")
+}
+
+func (sess *session) rcpt(arg string) {
+	if sess.envelope.From == "" {
+		sess.reply(503, "Send MAIL first")
+		return
+	}
+	to, _, ok := parsePath(arg, "TO:")
+	if !ok || to == "" {
+		sess.reply(501, "Syntax: RCPT TO:<address>")
+		return
+	}
+	if len(sess.envelope.To) >= sess.server.maxRecipients() {
+		sess.reply(452, "Too many recipients")
+		return
+	}
+	sess.envelope.To = append(sess.envelope.To, to)
+	sess.reply(250, "OK")
+}
+
+func (sess *session) data() {
+	if len(sess.envelope.To) == 0 {
+		sess.reply(503, "Send RCPT first")
+		return
+	}
+	sess.reply(354, "End data with <CR><LF>.<CR><LF>")
+
+	limit := sess.server.maxMessageBytes()
+	body := sess.text.DotReader()
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		sess.reply(451, "Error reading message")
+		sess.reset()
+		return
+	}
+	if int64(len(data)) > limit {
+		// Drain the rest of the message so the conversation stays in sync.
+		io.Copy(io.Discard, body)
+		sess.reply(552, "Message exceeds maximum size")
+		sess.reset()
+		return
+	}
+
+	envelope := sess.envelope
+	envelope.Data = data
+	sess.reset()
+	if sess.server.Handler != nil {
+		if err := sess.server.Handler(context.Background(), envelope); err != nil {
+			sess.reply(451, "Message not accepted: "+firstLine(err.Error()))
+			return
+		}
+	}
+	sess.reply(250, "OK: message accepted")
+}
+
+func (sess *session) reset() {
+	sess.envelope = Envelope{RemoteAddr: sess.conn.RemoteAddr().String()}
+}
+
+func (sess *session) reply(code int, lines ...string) {
+	for i, line := range lines {
+		separator := " "
+		if i < len(lines)-1 {
+			separator = "-"
+		}
+		if err := sess.text.PrintfLine("%d%s%s", code, separator, line); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) hostname() string {
+	if s.Hostname != "" {
+		return s.Hostname
+	}
+	return "localhost"
+}
+
+func (s *Server) maxMessageBytes() int64 {
+	if s.MaxMessageBytes > 0 {
+		return s.MaxMessageBytes
+	}
+	return defaultMaxMessageBytes
+}
+
+func (s *Server) maxRecipients() int {
+	if s.MaxRecipients > 0 {
+		return s.MaxRecipients
+	}
+	return defaultMaxRecipients
+}
+
+// parsePath parses "FROM:<address> PARAM=VALUE" style arguments.
+func parsePath(arg, prefix string) (string, []string, bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", nil, false
+	}
+	fields := strings.Fields(strings.TrimSpace(arg[len(prefix):]))
+	if len(fields) == 0 {
+		return "", nil, false
+	}
+	path := fields[0]
+	if !strings.HasPrefix(path, "<") || !strings.HasSuffix(path, ">") {
+		return "", nil, false
+	}
+	return strings.ToLower(strings.Trim(path, "<>")), fields[1:], true
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return strings.TrimSpace(line)
+}
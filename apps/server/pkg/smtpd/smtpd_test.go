@@ -0,0 +1,82 @@
+package smtpd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/smtp"
+	"strings"
+	"testing"
+)
+
+func startServer(t *testing.T, server *Server) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Shutdown() })
+	return listener.Addr().String()
+}
+
+func TestServerDeliversMessage(t *testing.T) {
+	received := make(chan Envelope, 1)
+	addr := startServer(t, &Server{
+		Handler: func(_ context.Context, envelope Envelope) error {
+			received <- envelope
+			return nil
+		},
+	})
+
+	message := "Subject: Printer is down\r\n\r\nThe printer on floor 2 is down.\r\n.leading dot\r\n"
+	err := smtp.SendMail(addr, nil, "Ops@Example.com", []string{"ops-abc@inbound.test", "cc@inbound.test"}, []byte(message))
+	if err != nil {
+		t.Fatalf("SendMail returned error: %v", err)
+	}
+
+	envelope := <-received
+	if envelope.From != "ops@example.com" {
+		t.Fatalf("expected sender ops@example.com, got %q", envelope.From)
+	}
+	if strings.Join(envelope.To, ",") != "ops-abc@inbound.test,cc@inbound.test" {
+		t.Fatalf("unexpected recipients %v", envelope.To)
+	}
+	if !strings.Contains(string(envelope.Data), "\n.leading dot\n") {
+		t.Fatalf("expected dot-stuffing to be undone, got %q", envelope.Data)
+	}
+}
+
+func TestServerRejectsOversizedMessage(t *testing.T) {
+	called := false
+	addr := startServer(t, &Server{
+		MaxMessageBytes: 64,
+		Handler: func(context.Context, Envelope) error {
+			called = true
+			return nil
+		},
+	})
+
+	message := "Subject: Large\r\n\r\n" + strings.Repeat("x", 256) + "\r\n"
+	err := smtp.SendMail(addr, nil, "ops@example.com", []string{"ops@inbound.test"}, []byte(message))
+	if err == nil || !strings.Contains(err.Error(), "552") {
+		t.Fatalf("expected 552 error, got %v", err)
+	}
+	if called {
+		t.Fatal("expected handler not to be called")
+	}
+}
+
+func TestServerReportsHandlerError(t *testing.T) {
+	addr := startServer(t, &Server{
+		Handler: func(context.Context, Envelope) error {
+			return errors.New("endpoint unavailable")
+		},
+	})
+
+	err := smtp.SendMail(addr, nil, "ops@example.com", []string{"ops@inbound.test"}, []byte("Subject: Hi\r\n\r\nHello\r\n"))
+	if err == nil || !strings.Contains(err.Error(), "endpoint unavailable") {
+		t.Fatalf("expected handler error to be returned, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const TypeStoryRankRebalance = "stories:rank:rebalance"
+
+type StoryRankRebalancePayload struct {
+	WorkspaceID uuid.UUID `json:"workspaceId"`
+}
+
+// EnqueueStoryRankRebalance enqueues a task to respace the story ranks of a
+// workspace once moves have made them long.
+func (s *Service) EnqueueStoryRankRebalance(payload StoryRankRebalancePayload, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	ctx := context.Background()
+	s.log.Info(ctx, "Attempting to enqueue StoryRankRebalance task", "workspace_id", payload.WorkspaceID)
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		s.log.Error(ctx, "Failed to marshal StoryRankRebalancePayload", "error", err, "workspace_id", payload.WorkspaceID)
+		return nil, fmt.Errorf("tasks: failed to marshal %s payload: %w", TypeStoryRankRebalance, err)
+	}
+
+	defaultOpts := []asynq.Option{
+		asynq.Queue("low"),
+		asynq.MaxRetry(3),
+		asynq.ProcessIn(time.Minute),
+		asynq.Unique(10 * time.Minute),
+	}
+
+	finalOpts := append(defaultOpts, opts...)
+	task := asynq.NewTask(TypeStoryRankRebalance, payloadBytes, finalOpts...)
+
+	info, err := s.asynqClient.Enqueue(task)
+	if errors.Is(err, asynq.ErrDuplicateTask) {
+		s.log.Info(ctx, "StoryRankRebalance task already queued", "workspace_id", payload.WorkspaceID)
+		return nil, nil
+	}
+	if err != nil {
+		s.log.Error(ctx, "Failed to enqueue StoryRankRebalance task", "error", err, "workspace_id", payload.WorkspaceID)
+		return nil, fmt.Errorf("tasks: failed to enqueue %s task: %w", TypeStoryRankRebalance, err)
+	}
+
+	s.log.Info(ctx, "Successfully enqueued StoryRankRebalance task", "task_id", info.ID, "queue", info.Queue, "workspace_id", payload.WorkspaceID)
+	return info, nil
+}
+
+const TypeStorySLAResync = "stories:sla:resync"
+
+type StorySLAResyncPayload struct {
+	WorkspaceID uuid.UUID `json:"workspaceId"`
+	TeamID      uuid.UUID `json:"teamId"`
+}
+
+// EnqueueStorySLAResync enqueues a task to bring the SLA clocks of a team's
+// open stories in line with its SLA settings after they change.
+func (s *Service) EnqueueStorySLAResync(payload StorySLAResyncPayload, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	ctx := context.Background()
+	s.log.Info(ctx, "Attempting to enqueue StorySLAResync task", "workspace_id", payload.WorkspaceID, "team_id", payload.TeamID)
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		s.log.Error(ctx, "Failed to marshal StorySLAResyncPayload", "error", err, "team_id", payload.TeamID)
+		return nil, fmt.Errorf("tasks: failed to marshal %s payload: %w", TypeStorySLAResync, err)
+	}
+
+	defaultOpts := []asynq.Option{
+		asynq.Queue("low"),
+		asynq.MaxRetry(3),
+	}
+
+	finalOpts := append(defaultOpts, opts...)
+	task := asynq.NewTask(TypeStorySLAResync, payloadBytes, finalOpts...)
+
+	info, err := s.asynqClient.Enqueue(task)
+	if err != nil {
+		s.log.Error(ctx, "Failed to enqueue StorySLAResync task", "error", err, "team_id", payload.TeamID)
+		return nil, fmt.Errorf("tasks: failed to enqueue %s task: %w", TypeStorySLAResync, err)
+	}
+
+	s.log.Info(ctx, "Successfully enqueued StorySLAResync task", "task_id", info.ID, "queue", info.Queue, "team_id", payload.TeamID)
+	return info, nil
+}
@@ -14,6 +14,7 @@ const (
 	TypeStoryAutoClose            = "automation:stories:close"
 	TypeSprintStoryMigration      = "automation:sprints:migrate_stories"
 	TypeDisableInactiveAutomation = "automation:disable:inactive"
+	TypeStorySLAAlerts            = "automation:stories:sla_alerts"
 )
 
 // EnqueueSprintAutoCreation enqueues a task to auto-create sprints for teams.
@@ -0,0 +1,47 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const TypeWebhookDelivery = "webhook:delivery"
+
+type WebhookDeliveryPayload struct {
+	DeliveryID  uuid.UUID `json:"deliveryId"`
+	WorkspaceID uuid.UUID `json:"workspaceId"`
+}
+
+// EnqueueWebhookDelivery enqueues a task that sends a single webhook delivery.
+// Failed attempts are retried by asynq with its exponential backoff.
+func (s *Service) EnqueueWebhookDelivery(payload WebhookDeliveryPayload, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	ctx := context.Background()
+	s.log.Info(ctx, "Attempting to enqueue WebhookDelivery task", "delivery_id", payload.DeliveryID, "workspace_id", payload.WorkspaceID)
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		s.log.Error(ctx, "Failed to marshal WebhookDeliveryPayload", "error", err, "delivery_id", payload.DeliveryID)
+		return nil, fmt.Errorf("tasks: failed to marshal %s payload: %w", TypeWebhookDelivery, err)
+	}
+
+	defaultOpts := []asynq.Option{
+		asynq.Queue("integrations"),
+		asynq.MaxRetry(8),
+	}
+
+	finalOpts := append(defaultOpts, opts...)
+	task := asynq.NewTask(TypeWebhookDelivery, payloadBytes, finalOpts...)
+
+	info, err := s.asynqClient.Enqueue(task)
+	if err != nil {
+		s.log.Error(ctx, "Failed to enqueue WebhookDelivery task", "error", err, "delivery_id", payload.DeliveryID)
+		return nil, fmt.Errorf("tasks: failed to enqueue %s task: %w", TypeWebhookDelivery, err)
+	}
+
+	s.log.Info(ctx, "Successfully enqueued WebhookDelivery task", "task_id", info.ID, "queue", info.Queue, "delivery_id", payload.DeliveryID)
+	return info, nil
+}